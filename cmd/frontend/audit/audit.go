@@ -0,0 +1,161 @@
+// Package audit records security-relevant actions (such as changes to the site configuration, the
+// creation of access tokens, and sign-ins) in the audit log, so that site admins can find out who
+// did what, and when.
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
+	"github.com/sourcegraph/sourcegraph/pkg/rcache"
+	"github.com/sourcegraph/sourcegraph/pkg/requestclient"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// The actions that are recorded in the audit log.
+const (
+	AccessTokenCreate = "access_token.create"
+	AccessTokenDelete = "access_token.delete"
+	AccessTokenSudo   = "access_token.sudo" // a site admin's token was used to act as another user
+
+	SiteConfigUpdate     = "site_config.update"
+	CriticalConfigUpdate = "critical_config.update"
+
+	ExternalServiceCreate = "external_service.create"
+	ExternalServiceUpdate = "external_service.update"
+	ExternalServiceDelete = "external_service.delete"
+
+	UserCreate            = "user.create"
+	UserDelete            = "user.delete"
+	UserSetSiteAdmin      = "user.set_site_admin"
+	UserRandomizePassword = "user.randomize_password"
+	UserSignIn            = "user.sign_in"
+	UserSignOut           = "user.sign_out"
+	UserUpdate            = "user.update"
+	UserSuspend           = "user.suspend"
+	UserUnsuspend         = "user.unsuspend"
+
+	OrgCreate       = "org.create"
+	OrgUpdate       = "org.update"
+	OrgDelete       = "org.delete"
+	OrgMemberAdd    = "org.member_add"
+	OrgMemberRemove = "org.member_remove"
+
+	RepoPermissionsSet    = "repo_permissions.set"
+	RepoPermissionsDelete = "repo_permissions.delete"
+
+	WebhookCreate = "webhook.create"
+	WebhookUpdate = "webhook.update"
+	WebhookDelete = "webhook.delete"
+)
+
+// Event describes an action to record in the audit log.
+type Event struct {
+	Action     string // the action (one of the constants in this package)
+	TargetType string // the GraphQL type name of the object acted on (e.g., "User"), if any
+	TargetID   string // the ID of the object acted on, if any
+
+	// Details summarizes the action (e.g., the values before and after a change). It is marshaled
+	// to a JSON object, so it must be a struct or map. It must not contain secrets.
+	Details interface{}
+}
+
+// UserID returns the target ID of the user with the given ID (which is the user's GraphQL ID, like
+// all target IDs).
+func UserID(id int32) string {
+	return string(relay.MarshalID("User", id))
+}
+
+// OrgID returns the target ID of the organization with the given ID.
+func OrgID(id int32) string {
+	return string(relay.MarshalID("Org", id))
+}
+
+// Change is the before and after value of something that an action changed.
+type Change struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Record records the event in the audit log, attributing it to the actor and to the client that
+// made the request (both of which are taken from ctx).
+//
+// Failing to record the event does not fail the action (because the action has usually already
+// been performed), so errors are logged instead of returned.
+func Record(ctx context.Context, ev Event) {
+	e := &db.AuditLogEntry{
+		ActorUserID: actor.FromContext(ctx).UID,
+		Action:      ev.Action,
+		TargetType:  ev.TargetType,
+		TargetID:    ev.TargetID,
+	}
+	if c := requestclient.FromContext(ctx); c != nil {
+		e.IP = c.IP
+		e.ForwardedFor = c.ForwardedFor
+	}
+	if ev.Details != nil {
+		details, err := json.Marshal(ev.Details)
+		if err != nil {
+			log15.Error("Failed to marshal audit log event details.", "action", ev.Action, "error", err)
+		} else {
+			e.Details = details
+		}
+	}
+	if err := db.AuditLog.Create(ctx, e); err != nil {
+		log15.Error("Failed to record audit log event.", "action", ev.Action, "targetType", ev.TargetType, "targetID", ev.TargetID, "error", err)
+	}
+}
+
+// recordOnceWindow is the period in which RecordOnce records at most one event per key.
+const recordOnceWindow = time.Hour
+
+// recentlyRecorded holds the (hashed) keys of the events recorded by RecordOnce in the last
+// recordOnceWindow.
+var recentlyRecorded = rcache.NewWithTTL("audit_log_recorded", int(recordOnceWindow/time.Second))
+
+// RecordOnce records the event like Record, unless an event with the same key was recorded by
+// RecordOnce in the last hour. It is used for actions that happen on every request (such as using a
+// sudo access token), which would otherwise flood the audit log.
+//
+// The key is hashed before it is stored, so it may contain secrets (such as the access token).
+func RecordOnce(ctx context.Context, key string, ev Event) {
+	sum := sha256.Sum256([]byte(ev.Action + "\x00" + key))
+	hashedKey := hex.EncodeToString(sum[:])
+	if _, ok := recentlyRecorded.Get(hashedKey); ok {
+		return
+	}
+	recentlyRecorded.Set(hashedKey, []byte{1})
+	Record(ctx, ev)
+}
+
+// ChangedKeys returns the sorted top-level keys whose values differ between the before and after
+// JSONC objects (such as two versions of the site configuration). The values themselves are not
+// returned, because they may contain secrets.
+func ChangedKeys(before, after string) []string {
+	var b, a map[string]interface{}
+	_ = jsonc.Unmarshal(before, &b) // an invalid object is treated as empty
+	_ = jsonc.Unmarshal(after, &a)
+
+	keys := []string{}
+	for k, v := range b {
+		if av, ok := a[k]; !ok || !reflect.DeepEqual(v, av) {
+			keys = append(keys, k)
+		}
+	}
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
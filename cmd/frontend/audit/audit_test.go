@@ -0,0 +1,83 @@
+package audit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/rcache"
+	"github.com/sourcegraph/sourcegraph/pkg/requestclient"
+)
+
+func TestRecord(t *testing.T) {
+	var got *db.AuditLogEntry
+	db.Mocks.AuditLog.Create = func(e *db.AuditLogEntry) error {
+		got = e
+		return nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	ctx = requestclient.WithClient(ctx, &requestclient.Client{IP: "10.0.0.1", ForwardedFor: "203.0.113.7"})
+	Record(ctx, Event{
+		Action:     UserSetSiteAdmin,
+		TargetType: "User",
+		TargetID:   UserID(2),
+		Details:    map[string]bool{"siteAdmin": true},
+	})
+
+	want := &db.AuditLogEntry{
+		ActorUserID:  1,
+		IP:           "10.0.0.1",
+		ForwardedFor: "203.0.113.7",
+		Action:       UserSetSiteAdmin,
+		TargetType:   "User",
+		TargetID:     "VXNlcjoy",
+		Details:      []byte(`{"siteAdmin":true}`),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestRecordOnce(t *testing.T) {
+	rcache.SetupForTest(t)
+
+	var recorded []string
+	db.Mocks.AuditLog.Create = func(e *db.AuditLogEntry) error {
+		recorded = append(recorded, e.TargetID)
+		return nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	RecordOnce(ctx, "token:2", Event{Action: AccessTokenSudo, TargetType: "User", TargetID: UserID(2)})
+	RecordOnce(ctx, "token:2", Event{Action: AccessTokenSudo, TargetType: "User", TargetID: UserID(2)})
+	RecordOnce(ctx, "token:3", Event{Action: AccessTokenSudo, TargetType: "User", TargetID: UserID(3)})
+
+	if want := []string{UserID(2), UserID(3)}; !reflect.DeepEqual(recorded, want) {
+		t.Errorf("got %q, want %q", recorded, want)
+	}
+}
+
+func TestChangedKeys(t *testing.T) {
+	tests := map[string]struct {
+		before, after string
+		want          []string
+	}{
+		"unchanged":  {`{"a": 1}`, `{"a": 1, /* comment */}`, []string{}},
+		"changed":    {`{"a": 1, "b": {"c": 2}}`, `{"a": 1, "b": {"c": 3}}`, []string{"b"}},
+		"added":      {`{"a": 1}`, `{"a": 1, "c": 1, "b": 1}`, []string{"b", "c"}},
+		"removed":    {`{"a": 1, "b": 1}`, `{}`, []string{"a", "b"}},
+		"from empty": {``, `{"a": 1}`, []string{"a"}},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := ChangedKeys(test.before, test.after); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
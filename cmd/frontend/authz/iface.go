@@ -47,7 +47,8 @@ type Provider interface {
 	// implementations should use only the userAccount parameter to compute permissions. They should
 	// NOT use any information about the currently authenticated user, including what might be
 	// inferred from the ctx parameter.) The userAccount parameter may be nil, in which case the set
-	// of permissions for an code-host-unauthenticated user is returned.
+	// of permissions for an code-host-unauthenticated user is returned. The caller sets the priority
+	// of the requests to the code host in ctx (see ratelimit.WithPriority).
 	//
 	// Design note: this is a better interface than ListAllRepos, because the list of all repos may
 	// be very long (especially if the returned list includes public repos). RepoPerms is a
@@ -82,6 +83,17 @@ type Provider interface {
 	Validate() (problems []string)
 }
 
+// TransientAccountsProvider is implemented by authz providers whose external accounts are computed
+// from the Sourcegraph user alone (without any requests to an external service). Such accounts are
+// recomputed with FetchAccount each time they are needed, instead of being saved as external
+// accounts of the user.
+type TransientAccountsProvider interface {
+	Provider
+
+	// TransientAccounts is a marker method that has no effect.
+	TransientAccounts()
+}
+
 type Repo struct {
 	// RepoName is the unique name of the repo on Sourcegraph.
 	RepoName api.RepoName
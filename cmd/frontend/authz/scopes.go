@@ -1,13 +1,121 @@
 package authz
 
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+)
+
 const (
 	// Access token scopes.
 	ScopeUserAll       = "user:all"        // Full control of all resources accessible to the user account.
+	ScopeUserRead      = "user:read"       // Read-only access to all resources accessible to the user account.
+	ScopeSearch        = "search"          // Ability to perform searches (and nothing else).
 	ScopeSiteAdminSudo = "site-admin:sudo" // Ability to perform any action as any other user.
+
+	// ScopeRepoPrefix is the prefix of the scopes that restrict an access token to the repositories
+	// whose names match the regular expression following the prefix (e.g., "repo:^github\.com/foo/").
+	ScopeRepoPrefix = "repo:"
 )
 
 // AllScopes is a list of all known access token scopes.
 var AllScopes = []string{
 	ScopeUserAll,
+	ScopeUserRead,
+	ScopeSearch,
 	ScopeSiteAdminSudo,
+	ScopeRepoPrefix + "<pattern>",
+}
+
+// UserScopes are the scopes that grant access to the resources of the token's subject user. Every
+// access token has exactly one of them.
+var UserScopes = []string{
+	ScopeUserAll,
+	ScopeUserRead,
+	ScopeSearch,
+}
+
+// HasScope reports whether scopes contains scope.
+func HasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseRepoScope returns the repository name pattern of a scope with the ScopeRepoPrefix.
+func ParseRepoScope(scope string) (*regexp.Regexp, error) {
+	pattern := strings.TrimPrefix(scope, ScopeRepoPrefix)
+	if pattern == scope || pattern == "" {
+		return nil, fmt.Errorf("invalid access token scope %q (want %q followed by a repository name pattern)", scope, ScopeRepoPrefix)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository name pattern in access token scope %q: %s", scope, err)
+	}
+	return re, nil
+}
+
+// ScopeError is returned when an actor authenticated by an access token attempts an operation that
+// the token's scopes don't permit.
+type ScopeError struct {
+	Required string // the scope the operation requires
+}
+
+func (e *ScopeError) Error() string {
+	return fmt.Sprintf("access token does not have the required scope %q", e.Required)
+}
+
+func (e *ScopeError) HTTPStatusCode() int { return http.StatusForbidden }
+
+// CheckWriteScope returns an error if the actor in ctx is authenticated by an access token that
+// does not permit changing data (i.e., one without the "user:all" scope). Actors which were not
+// authenticated by an access token are not restricted.
+func CheckWriteScope(ctx context.Context) error {
+	scopes := actor.FromContext(ctx).Scopes
+	if scopes == nil || HasScope(scopes, ScopeUserAll) {
+		return nil
+	}
+	return &ScopeError{Required: ScopeUserAll}
+}
+
+// FilterReposByScopes returns the repositories permitted by the repository name patterns of
+// scopes. If scopes has no such patterns, all repositories are permitted. If it has several, a
+// repository needs to match only one of them.
+//
+// 🚨 SECURITY: An invalid pattern permits no repositories, so that a token can never grant more
+// access than was intended when it was created.
+func FilterReposByScopes(scopes []string, repos []*types.Repo) []*types.Repo {
+	var patterns []*regexp.Regexp
+	for _, scope := range scopes {
+		if !strings.HasPrefix(scope, ScopeRepoPrefix) {
+			continue
+		}
+		re, err := ParseRepoScope(scope)
+		if err != nil {
+			return nil
+		}
+		patterns = append(patterns, re)
+	}
+	if len(patterns) == 0 {
+		return repos
+	}
+
+	filtered := make([]*types.Repo, 0, len(repos))
+	for _, repo := range repos {
+		for _, re := range patterns {
+			if re.MatchString(string(repo.Name)) {
+				filtered = append(filtered, repo)
+				break
+			}
+		}
+	}
+	return filtered
 }
@@ -0,0 +1,86 @@
+package authz
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+func TestFilterReposByScopes(t *testing.T) {
+	repos := []*types.Repo{
+		{Name: "github.com/foo/a"},
+		{Name: "github.com/foo/b"},
+		{Name: "github.com/bar/c"},
+	}
+	names := func(repos []*types.Repo) (names []api.RepoName) {
+		for _, r := range repos {
+			names = append(names, r.Name)
+		}
+		return names
+	}
+
+	tests := []struct {
+		scopes []string
+		want   []api.RepoName
+	}{
+		{
+			scopes: []string{ScopeUserRead},
+			want:   []api.RepoName{"github.com/foo/a", "github.com/foo/b", "github.com/bar/c"},
+		},
+		{
+			scopes: []string{ScopeUserRead, `repo:^github\.com/foo/`},
+			want:   []api.RepoName{"github.com/foo/a", "github.com/foo/b"},
+		},
+		{
+			scopes: []string{ScopeSearch, "repo:/a$", "repo:/c$"},
+			want:   []api.RepoName{"github.com/foo/a", "github.com/bar/c"},
+		},
+		{
+			scopes: []string{ScopeUserAll, "repo:^nomatch$"},
+			want:   nil,
+		},
+		{
+			scopes: []string{ScopeUserAll, "repo:(", "repo:."},
+			want:   nil,
+		},
+	}
+	for _, test := range tests {
+		if got := names(FilterReposByScopes(test.scopes, repos)); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("scopes %q: got %q, want %q", test.scopes, got, test.want)
+		}
+	}
+}
+
+func TestParseRepoScope(t *testing.T) {
+	for _, scope := range []string{"repo:", "repo:(", "user:all"} {
+		if _, err := ParseRepoScope(scope); err == nil {
+			t.Errorf("%q: want error", scope)
+		}
+	}
+	if _, err := ParseRepoScope(`repo:^github\.com/`); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCheckWriteScope(t *testing.T) {
+	tests := []struct {
+		scopes  []string
+		wantErr bool
+	}{
+		{scopes: nil},
+		{scopes: []string{ScopeUserAll}},
+		{scopes: []string{ScopeUserAll, `repo:^github\.com/`}},
+		{scopes: []string{ScopeUserRead}, wantErr: true},
+		{scopes: []string{ScopeSearch}, wantErr: true},
+	}
+	for _, test := range tests {
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1, Scopes: test.scopes})
+		if err := CheckWriteScope(ctx); (err != nil) != test.wantErr {
+			t.Errorf("scopes %q: got error %v, want error %v", test.scopes, err, test.wantErr)
+		}
+	}
+}
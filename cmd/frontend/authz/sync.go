@@ -0,0 +1,36 @@
+package authz
+
+import "sync"
+
+// PermissionsSyncer syncs users' repository permissions from the authz providers to the database
+// in the background (when the "permissions.backgroundSync" site configuration property is
+// enabled).
+type PermissionsSyncer interface {
+	// RequestSync requests that the user's repository permissions be synced soon. It must not
+	// block.
+	RequestSync(userID int32)
+}
+
+var (
+	permissionsSyncerMu sync.RWMutex
+	permissionsSyncer   PermissionsSyncer
+)
+
+// SetPermissionsSyncer sets the permissions syncer that RequestPermissionsSync uses. It is
+// concurrency-safe.
+func SetPermissionsSyncer(s PermissionsSyncer) {
+	permissionsSyncerMu.Lock()
+	defer permissionsSyncerMu.Unlock()
+	permissionsSyncer = s
+}
+
+// RequestPermissionsSync requests that the user's repository permissions be synced in the
+// background (for example, after the user signs in). It does nothing if no permissions syncer has
+// been set.
+func RequestPermissionsSync(userID int32) {
+	permissionsSyncerMu.RLock()
+	defer permissionsSyncerMu.RUnlock()
+	if permissionsSyncer != nil {
+		permissionsSyncer.RequestSync(userID)
+	}
+}
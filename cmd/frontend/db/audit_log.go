@@ -0,0 +1,152 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// AuditLogEntry is an entry in the audit log, which records security-relevant actions (such as
+// changes to the site configuration, the creation of access tokens, and sign-ins).
+type AuditLogEntry struct {
+	ID           int64
+	CreatedAt    time.Time
+	ActorUserID  int32           // the user who performed the action (zero if unauthenticated or internal)
+	IP           string          // the IP address of the client that performed the action (if known)
+	ForwardedFor string          // the X-Forwarded-For header of the request (if any)
+	Action       string          // the action (e.g., "site_config.update")
+	TargetType   string          // the type of the object acted on (e.g., "User"), if any
+	TargetID     string          // the ID of the object acted on, if any
+	Details      json.RawMessage // a JSON object that summarizes the action (e.g., before and after values)
+}
+
+// auditLog provides access to the `audit_log` table. The table is append-only: the database
+// rejects updates and deletions of its rows.
+type auditLog struct{}
+
+// Create appends the entry to the audit log. The entry's ID and CreatedAt fields are ignored; they
+// are set on return.
+func (*auditLog) Create(ctx context.Context, e *AuditLogEntry) error {
+	if Mocks.AuditLog.Create != nil {
+		return Mocks.AuditLog.Create(e)
+	}
+
+	if dbconn.Global == nil { // e.g., in tests that don't mock the audit log
+		return errors.New("db connection is nil")
+	}
+
+	details := e.Details
+	if len(details) == 0 {
+		details = json.RawMessage("{}")
+	}
+	return dbconn.Global.QueryRowContext(ctx, `
+INSERT INTO audit_log(actor_user_id, ip, forwarded_for, action, target_type, target_id, details)
+VALUES(NULLIF($1, 0), NULLIF($2, ''), NULLIF($3, ''), $4, NULLIF($5, ''), NULLIF($6, ''), $7)
+RETURNING id, created_at`,
+		e.ActorUserID, e.IP, e.ForwardedFor, e.Action, e.TargetType, e.TargetID, []byte(details),
+	).Scan(&e.ID, &e.CreatedAt)
+}
+
+// AuditLogListOptions contains options for listing audit log entries.
+type AuditLogListOptions struct {
+	ActorUserID int32  // only list entries with this actor
+	Action      string // only list entries with this action
+	TargetType  string // only list entries with this target type
+	TargetID    string // only list entries with this target ID (and TargetType)
+	Since       *time.Time
+	Until       *time.Time
+	BeforeID    int64 // only list entries with an ID less than this (to page through entries stably)
+	*LimitOffset
+}
+
+func (o AuditLogListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if o.ActorUserID != 0 {
+		conds = append(conds, sqlf.Sprintf("actor_user_id=%d", o.ActorUserID))
+	}
+	if o.Action != "" {
+		conds = append(conds, sqlf.Sprintf("action=%s", o.Action))
+	}
+	if o.TargetType != "" {
+		conds = append(conds, sqlf.Sprintf("target_type=%s", o.TargetType))
+	}
+	if o.TargetID != "" {
+		conds = append(conds, sqlf.Sprintf("target_id=%s", o.TargetID))
+	}
+	if o.Since != nil {
+		conds = append(conds, sqlf.Sprintf("created_at>=%s", *o.Since))
+	}
+	if o.Until != nil {
+		conds = append(conds, sqlf.Sprintf("created_at<%s", *o.Until))
+	}
+	if o.BeforeID != 0 {
+		conds = append(conds, sqlf.Sprintf("id<%d", o.BeforeID))
+	}
+	return conds
+}
+
+// List lists the audit log entries that satisfy the options, most recent first.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*auditLog) List(ctx context.Context, opt AuditLogListOptions) ([]*AuditLogEntry, error) {
+	if Mocks.AuditLog.List != nil {
+		return Mocks.AuditLog.List(opt)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT id, created_at, actor_user_id, ip, forwarded_for, action, target_type, target_id, details
+FROM audit_log
+WHERE (%s)
+ORDER BY id DESC
+%s`,
+		sqlf.Join(opt.sqlConditions(), ") AND ("),
+		opt.LimitOffset.SQL(),
+	)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*AuditLogEntry
+	for rows.Next() {
+		var (
+			e                                      AuditLogEntry
+			actorUserID                            sql.NullInt64
+			ip, forwardedFor, targetType, targetID sql.NullString
+			details                                []byte
+		)
+		if err := rows.Scan(&e.ID, &e.CreatedAt, &actorUserID, &ip, &forwardedFor, &e.Action, &targetType, &targetID, &details); err != nil {
+			return nil, err
+		}
+		e.ActorUserID = int32(actorUserID.Int64)
+		e.IP = ip.String
+		e.ForwardedFor = forwardedFor.String
+		e.TargetType = targetType.String
+		e.TargetID = targetID.String
+		e.Details = details
+		results = append(results, &e)
+	}
+	return results, rows.Err()
+}
+
+// Count counts the audit log entries that satisfy the options (ignoring limit and offset).
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*auditLog) Count(ctx context.Context, opt AuditLogListOptions) (int, error) {
+	if Mocks.AuditLog.Count != nil {
+		return Mocks.AuditLog.Count(opt)
+	}
+
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM audit_log WHERE (%s)", sqlf.Join(opt.sqlConditions(), ") AND ("))
+	var count int
+	if err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+)
+
+func TestAuditLog(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	entries := []*AuditLogEntry{
+		{ActorUserID: 1, IP: "10.0.0.1", Action: "user.sign_in", TargetType: "User", TargetID: "1"},
+		{ActorUserID: 1, Action: "site_config.update", Details: json.RawMessage(`{"changedKeys":["a"]}`)},
+		{Action: "user.sign_in", TargetType: "User", TargetID: "2"},
+	}
+	for _, e := range entries {
+		if err := AuditLog.Create(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+		if e.ID == 0 || e.CreatedAt.IsZero() {
+			t.Fatalf("got %+v, want ID and CreatedAt to be set", e)
+		}
+	}
+
+	for _, test := range []struct {
+		opt     AuditLogListOptions
+		wantIDs []int64
+	}{
+		{AuditLogListOptions{}, []int64{entries[2].ID, entries[1].ID, entries[0].ID}},
+		{AuditLogListOptions{ActorUserID: 1}, []int64{entries[1].ID, entries[0].ID}},
+		{AuditLogListOptions{Action: "user.sign_in"}, []int64{entries[2].ID, entries[0].ID}},
+		{AuditLogListOptions{TargetType: "User", TargetID: "2"}, []int64{entries[2].ID}},
+		{AuditLogListOptions{BeforeID: entries[2].ID}, []int64{entries[1].ID, entries[0].ID}},
+		{AuditLogListOptions{LimitOffset: &LimitOffset{Limit: 1, Offset: 1}}, []int64{entries[1].ID}},
+	} {
+		got, err := AuditLog.List(ctx, test.opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var gotIDs []int64
+		for _, e := range got {
+			gotIDs = append(gotIDs, e.ID)
+		}
+		if !reflect.DeepEqual(gotIDs, test.wantIDs) {
+			t.Errorf("%+v: got IDs %v, want %v", test.opt, gotIDs, test.wantIDs)
+		}
+
+		count, err := AuditLog.Count(ctx, test.opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if test.opt.LimitOffset == nil && count != len(test.wantIDs) {
+			t.Errorf("%+v: got count %d, want %d", test.opt, count, len(test.wantIDs))
+		}
+	}
+
+	if got, err := AuditLog.List(ctx, AuditLogListOptions{Action: "site_config.update"}); err != nil {
+		t.Fatal(err)
+	} else if len(got) != 1 || string(got[0].Details) != `{"changedKeys": ["a"]}` || got[0].IP != "" {
+		t.Errorf("got %s, want the site config entry with details and no IP", asJSON(t, got))
+	}
+
+	// The audit log is append-only.
+	if _, err := dbconn.Global.ExecContext(ctx, "UPDATE audit_log SET action='x'"); err == nil {
+		t.Error("got no error updating audit log entries")
+	}
+	if _, err := dbconn.Global.ExecContext(ctx, "DELETE FROM audit_log"); err == nil {
+		t.Error("got no error deleting audit log entries")
+	}
+}
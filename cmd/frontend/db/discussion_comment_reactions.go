@@ -0,0 +1,64 @@
+package db
+
+import (
+	"context"
+
+	"github.com/lib/pq"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// discussionCommentReactions provides access to the `discussion_comment_reactions` table.
+//
+// For a detailed overview of the schema, see schema.md.
+type discussionCommentReactions struct{}
+
+// Add adds the user's reaction to the comment. Adding a reaction that the user
+// already added to the comment is a no-op.
+//
+// The caller is responsible for validating the reaction.
+func (*discussionCommentReactions) Add(ctx context.Context, commentID int64, userID int32, reaction string) error {
+	if Mocks.DiscussionCommentReactions.Add != nil {
+		return Mocks.DiscussionCommentReactions.Add(ctx, commentID, userID, reaction)
+	}
+	_, err := dbconn.Global.ExecContext(ctx, `
+INSERT INTO discussion_comment_reactions(comment_id, user_id, reaction)
+SELECT $1, $2, $3 WHERE EXISTS (SELECT 1 FROM discussion_comments WHERE id=$1 AND deleted_at IS NULL)
+ON CONFLICT (comment_id, user_id, reaction) DO NOTHING`,
+		commentID, userID, reaction,
+	)
+	return err
+}
+
+// Remove removes the user's reaction from the comment, if any.
+func (*discussionCommentReactions) Remove(ctx context.Context, commentID int64, userID int32, reaction string) error {
+	if Mocks.DiscussionCommentReactions.Remove != nil {
+		return Mocks.DiscussionCommentReactions.Remove(ctx, commentID, userID, reaction)
+	}
+	_, err := dbconn.Global.ExecContext(ctx, "DELETE FROM discussion_comment_reactions WHERE comment_id=$1 AND user_id=$2 AND reaction=$3", commentID, userID, reaction)
+	return err
+}
+
+// List returns the reactions to the given comments, oldest first.
+func (*discussionCommentReactions) List(ctx context.Context, commentIDs ...int64) ([]*types.DiscussionCommentReaction, error) {
+	if Mocks.DiscussionCommentReactions.List != nil {
+		return Mocks.DiscussionCommentReactions.List(ctx, commentIDs...)
+	}
+	rows, err := dbconn.Global.QueryContext(ctx, `
+SELECT comment_id, user_id, reaction, created_at FROM discussion_comment_reactions
+WHERE comment_id = ANY($1) AND user_id IN (SELECT id FROM users WHERE deleted_at IS NULL)
+ORDER BY created_at ASC, user_id ASC`, pq.Array(commentIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var reactions []*types.DiscussionCommentReaction
+	for rows.Next() {
+		var r types.DiscussionCommentReaction
+		if err := rows.Scan(&r.CommentID, &r.UserID, &r.Reaction, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		reactions = append(reactions, &r)
+	}
+	return reactions, rows.Err()
+}
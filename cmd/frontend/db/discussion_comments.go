@@ -36,6 +36,11 @@ func (c *discussionComments) Create(ctx context.Context, newComment *types.Discu
 	if Mocks.DiscussionComments.Create != nil {
 		return Mocks.DiscussionComments.Create(ctx, newComment)
 	}
+	return c.create(ctx, dbconn.Global, newComment)
+}
+
+// create creates the comment using db, which may be a transaction.
+func (c *discussionComments) create(ctx context.Context, db queryable, newComment *types.DiscussionComment) (*types.DiscussionComment, error) {
 
 	// Validate the input comment.
 	if newComment == nil {
@@ -61,7 +66,7 @@ func (c *discussionComments) Create(ctx context.Context, newComment *types.Discu
 	newComment.CreatedAt = time.Now()
 	newComment.UpdatedAt = newComment.CreatedAt
 
-	err := dbconn.Global.QueryRowContext(ctx, `INSERT INTO discussion_comments(
+	err := db.QueryRowContext(ctx, `INSERT INTO discussion_comments(
 		thread_id,
 		author_user_id,
 		contents,
@@ -164,6 +169,12 @@ func (c *discussionComments) Update(ctx context.Context, commentID int64, opts *
 	if !deletingFirstComment && opts.hardDelete {
 		// Intentionally not setting anyUpdate=true here, it would cause us to
 		// try to update updated_at below which would fail.
+		if _, err := dbconn.Global.ExecContext(ctx, "DELETE FROM discussion_comment_reactions WHERE comment_id=$1", commentID); err != nil {
+			return nil, err
+		}
+		if _, err := dbconn.Global.ExecContext(ctx, "DELETE FROM discussion_imported_comments WHERE comment_id=$1", commentID); err != nil {
+			return nil, err
+		}
 		if _, err := dbconn.Global.ExecContext(ctx, "DELETE FROM discussion_comments WHERE id=$1", commentID); err != nil {
 			return nil, err
 		}
@@ -0,0 +1,131 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbutil"
+)
+
+// discussionImportedComments provides access to the `discussion_imported_comments` table.
+//
+// It records which discussion comments were imported from a code host (e.g.
+// GitHub pull request review comments), so that importing the same comments
+// again does not create duplicates.
+//
+// For a detailed overview of the schema, see schema.md.
+type discussionImportedComments struct{}
+
+// Create records that the comment was imported.
+func (*discussionImportedComments) Create(ctx context.Context, c *types.DiscussionImportedComment) error {
+	if Mocks.DiscussionImportedComments.Create != nil {
+		return Mocks.DiscussionImportedComments.Create(ctx, c)
+	}
+	if c.CommentID == 0 || c.ThreadID == 0 {
+		return errors.New("imported comment must have a comment and thread ID")
+	}
+	if c.ExternalThreadID == "" || c.ExternalID == "" {
+		return errors.New("imported comment must have an external thread ID and external ID")
+	}
+	return dbconn.Global.QueryRowContext(ctx, `
+INSERT INTO discussion_imported_comments(comment_id, thread_id, external_thread_id, external_id, url)
+VALUES($1, $2, $3, $4, $5) RETURNING created_at`,
+		c.CommentID, c.ThreadID, c.ExternalThreadID, c.ExternalID, c.URL,
+	).Scan(&c.CreatedAt)
+}
+
+// errAlreadyImported is returned in Import's transaction to roll it back when
+// the comment was already imported.
+var errAlreadyImported = errors.New("comment was already imported")
+
+// Import creates newComment (and newThread, if it is non-nil, in which case
+// newComment is added to it) and records that it was imported as c, in a single
+// transaction. The comment and thread IDs of c are set by Import.
+//
+// If a comment with c's external ID was already imported (e.g. by a concurrent
+// import), nothing is created and ok is false.
+func (*discussionImportedComments) Import(ctx context.Context, newThread *types.DiscussionThread, newComment *types.DiscussionComment, c *types.DiscussionImportedComment) (ok bool, err error) {
+	if Mocks.DiscussionImportedComments.Import != nil {
+		return Mocks.DiscussionImportedComments.Import(ctx, newThread, newComment, c)
+	}
+	if c.ExternalThreadID == "" || c.ExternalID == "" {
+		return false, errors.New("imported comment must have an external thread ID and external ID")
+	}
+	err = dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		if newThread != nil {
+			if _, err := DiscussionThreads.create(ctx, tx, newThread); err != nil {
+				return errors.Wrap(err, "create thread")
+			}
+			newComment.ThreadID = newThread.ID
+		}
+		if _, err := DiscussionComments.create(ctx, tx, newComment); err != nil {
+			return errors.Wrap(err, "create comment")
+		}
+		c.CommentID, c.ThreadID = newComment.ID, newComment.ThreadID
+
+		// The unique external ID guards against importing the comment twice.
+		err := tx.QueryRowContext(ctx, `
+INSERT INTO discussion_imported_comments(comment_id, thread_id, external_thread_id, external_id, url)
+VALUES($1, $2, $3, $4, $5) ON CONFLICT (external_id) DO NOTHING RETURNING created_at`,
+			c.CommentID, c.ThreadID, c.ExternalThreadID, c.ExternalID, c.URL,
+		).Scan(&c.CreatedAt)
+		if err == sql.ErrNoRows {
+			return errAlreadyImported
+		}
+		return err
+	})
+	if err == errAlreadyImported {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Exists tells if a comment with the given external ID was imported.
+func (*discussionImportedComments) Exists(ctx context.Context, externalID string) (bool, error) {
+	if Mocks.DiscussionImportedComments.Exists != nil {
+		return Mocks.DiscussionImportedComments.Exists(ctx, externalID)
+	}
+	var exists bool
+	err := dbconn.Global.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM discussion_imported_comments WHERE external_id=$1)", externalID).Scan(&exists)
+	return exists, err
+}
+
+// GetThreadID returns the ID of the thread that the external thread was
+// imported as, or ok=false if no comments of the external thread were
+// imported.
+func (*discussionImportedComments) GetThreadID(ctx context.Context, externalThreadID string) (threadID int64, ok bool, err error) {
+	if Mocks.DiscussionImportedComments.GetThreadID != nil {
+		return Mocks.DiscussionImportedComments.GetThreadID(ctx, externalThreadID)
+	}
+	err = dbconn.Global.QueryRowContext(ctx, "SELECT thread_id FROM discussion_imported_comments WHERE external_thread_id=$1 LIMIT 1", externalThreadID).Scan(&threadID)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return threadID, true, nil
+}
+
+// GetByCommentID returns the import record of the comment, or nil if the
+// comment was not imported.
+func (*discussionImportedComments) GetByCommentID(ctx context.Context, commentID int64) (*types.DiscussionImportedComment, error) {
+	if Mocks.DiscussionImportedComments.GetByCommentID != nil {
+		return Mocks.DiscussionImportedComments.GetByCommentID(ctx, commentID)
+	}
+	var c types.DiscussionImportedComment
+	err := dbconn.Global.QueryRowContext(ctx, `
+SELECT comment_id, thread_id, external_thread_id, external_id, url, created_at
+FROM discussion_imported_comments WHERE comment_id=$1`, commentID,
+	).Scan(&c.CommentID, &c.ThreadID, &c.ExternalThreadID, &c.ExternalID, &c.URL, &c.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,15 @@
+package db
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+type MockDiscussionImportedComments struct {
+	Create         func(ctx context.Context, c *types.DiscussionImportedComment) error
+	Import         func(ctx context.Context, newThread *types.DiscussionThread, newComment *types.DiscussionComment, c *types.DiscussionImportedComment) (ok bool, err error)
+	Exists         func(ctx context.Context, externalID string) (bool, error)
+	GetThreadID    func(ctx context.Context, externalThreadID string) (threadID int64, ok bool, err error)
+	GetByCommentID func(ctx context.Context, commentID int64) (*types.DiscussionImportedComment, error)
+}
@@ -0,0 +1,169 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+)
+
+func TestDiscussionImportedComments(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	user, err := Users.Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a repository to comply with the postgres repo constraint.
+	if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: "myrepo", Description: "", Fork: false, Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := Repos.GetByName(ctx, "myrepo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	thread, err := DiscussionThreads.Create(ctx, &types.DiscussionThread{
+		AuthorUserID: user.ID,
+		Title:        "Hello world!",
+		TargetRepo:   &types.DiscussionThreadTargetRepo{RepoID: repo.ID},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	comment, err := DiscussionComments.Create(ctx, &types.DiscussionComment{
+		ThreadID:     thread.ID,
+		AuthorUserID: user.ID,
+		Contents:     "Hello world!",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exists, err := DiscussionImportedComments.Exists(ctx, "https://github.com/#2"); err != nil {
+		t.Fatal(err)
+	} else if exists {
+		t.Error("got exists == true before import")
+	}
+	if _, ok, err := DiscussionImportedComments.GetThreadID(ctx, "https://github.com/#1"); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Error("got ok == true before import")
+	}
+	if imported, err := DiscussionImportedComments.GetByCommentID(ctx, comment.ID); err != nil {
+		t.Fatal(err)
+	} else if imported != nil {
+		t.Errorf("got imported comment %+v before import, want nil", imported)
+	}
+
+	if err := DiscussionImportedComments.Create(ctx, &types.DiscussionImportedComment{
+		CommentID:        comment.ID,
+		ThreadID:         thread.ID,
+		ExternalThreadID: "https://github.com/#1",
+		ExternalID:       "https://github.com/#2",
+		URL:              "https://github.com/o/r/pull/3#discussion_r2",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if exists, err := DiscussionImportedComments.Exists(ctx, "https://github.com/#2"); err != nil {
+		t.Fatal(err)
+	} else if !exists {
+		t.Error("got exists == false after import")
+	}
+	if threadID, ok, err := DiscussionImportedComments.GetThreadID(ctx, "https://github.com/#1"); err != nil {
+		t.Fatal(err)
+	} else if !ok || threadID != thread.ID {
+		t.Errorf("got thread ID %d (ok=%v), want %d", threadID, ok, thread.ID)
+	}
+	imported, err := DiscussionImportedComments.GetByCommentID(ctx, comment.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported == nil || imported.URL != "https://github.com/o/r/pull/3#discussion_r2" {
+		t.Errorf("got imported comment %+v, want URL of the review comment", imported)
+	}
+
+	// Importing the same comment again must fail.
+	if err := DiscussionImportedComments.Create(ctx, &types.DiscussionImportedComment{
+		CommentID:        comment.ID,
+		ThreadID:         thread.ID,
+		ExternalThreadID: "https://github.com/#1",
+		ExternalID:       "https://github.com/#2",
+	}); err == nil {
+		t.Error("got err == nil when importing a comment twice")
+	}
+
+	// Hard deleting the thread deletes the import records.
+	if _, err := DiscussionThreads.Update(ctx, thread.ID, &DiscussionThreadsUpdateOptions{hardDelete: true}); err != nil {
+		t.Fatal(err)
+	}
+	if exists, err := DiscussionImportedComments.Exists(ctx, "https://github.com/#2"); err != nil {
+		t.Fatal(err)
+	} else if exists {
+		t.Error("got exists == true after hard delete")
+	}
+}
+
+func TestDiscussionImportedComments_Import(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	user, err := Users.Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: "myrepo", Description: "", Fork: false, Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := Repos.GetByName(ctx, "myrepo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	imported := &types.DiscussionImportedComment{
+		ExternalThreadID: "https://github.com/#1",
+		ExternalID:       "https://github.com/#2",
+		URL:              "https://github.com/o/r/pull/3#discussion_r2",
+	}
+	ok, err := DiscussionImportedComments.Import(ctx,
+		&types.DiscussionThread{AuthorUserID: user.ID, Title: "t", TargetRepo: &types.DiscussionThreadTargetRepo{RepoID: repo.ID}},
+		&types.DiscussionComment{AuthorUserID: user.ID, Contents: "c"},
+		imported,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || imported.CommentID == 0 || imported.ThreadID == 0 {
+		t.Fatalf("got ok=%v and imported comment %+v, want it to be imported", ok, imported)
+	}
+	if threadID, ok, err := DiscussionImportedComments.GetThreadID(ctx, "https://github.com/#1"); err != nil {
+		t.Fatal(err)
+	} else if !ok || threadID != imported.ThreadID {
+		t.Errorf("got thread ID %d (ok=%v), want %d", threadID, ok, imported.ThreadID)
+	}
+
+	// Importing the same comment again creates nothing.
+	ok, err = DiscussionImportedComments.Import(ctx, nil,
+		&types.DiscussionComment{ThreadID: imported.ThreadID, AuthorUserID: user.ID, Contents: "c"},
+		&types.DiscussionImportedComment{ExternalThreadID: "https://github.com/#1", ExternalID: "https://github.com/#2"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Error("got ok == true when importing a comment twice")
+	}
+	if count, err := DiscussionComments.Count(ctx, &DiscussionCommentsListOptions{ThreadID: &imported.ThreadID}); err != nil {
+		t.Fatal(err)
+	} else if count != 1 {
+		t.Errorf("got %d comments, want 1", count)
+	}
+}
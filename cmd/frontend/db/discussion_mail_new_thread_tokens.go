@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// discussionMailNewThreadTokens provides access to the `discussion_mail_new_thread_tokens` table.
+//
+// For a detailed overview of the schema, see schema.md.
+type discussionMailNewThreadTokens struct{}
+
+// DiscussionMailNewThreadTokenPrefix is the prefix of all tokens generated by
+// DiscussionMailNewThreadTokens.Generate. It distinguishes them from the other
+// kinds of mail tokens.
+const DiscussionMailNewThreadTokenPrefix = "t-"
+
+// Generate gets the existing token, or generates a new one, for giving the
+// specified user access to create discussion threads in the specified
+// repository through only the token.
+//
+// 🚨 SECURITY: The caller must ensure the token is ONLY given to the user that
+// is passed to this method. Anyone with the token can create threads in the
+// specified repository as the specified user, at ANY point in the future.
+func (*discussionMailNewThreadTokens) Generate(ctx context.Context, userID int32, repoID api.RepoID) (string, error) {
+	if Mocks.DiscussionMailNewThreadTokens.Generate != nil {
+		return Mocks.DiscussionMailNewThreadTokens.Generate(ctx, userID, repoID)
+	}
+
+	// Check if there already exists a token for this userID + repoID pair.
+	// If there is, we do not need to store a new one.
+	token, err := DiscussionMailNewThreadTokens.GetByUserAndRepo(ctx, userID, repoID)
+	if err != nil {
+		return "", err
+	}
+	if token != "" {
+		return token, nil // use the existing token
+	}
+
+	token, err = newMailToken(DiscussionMailNewThreadTokenPrefix)
+	if err != nil {
+		return "", err
+	}
+	_, err = dbconn.Global.ExecContext(ctx, "INSERT INTO discussion_mail_new_thread_tokens(token, user_id, repo_id) VALUES($1, $2, $3)", token, userID, repoID)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// GetByUserAndRepo returns the user's existing token for the repository, or
+// an empty string if the user has none (see Generate).
+//
+// 🚨 SECURITY: The caller must ensure the token is ONLY given to the user that
+// is passed to this method (see Generate).
+func (*discussionMailNewThreadTokens) GetByUserAndRepo(ctx context.Context, userID int32, repoID api.RepoID) (string, error) {
+	if Mocks.DiscussionMailNewThreadTokens.GetByUserAndRepo != nil {
+		return Mocks.DiscussionMailNewThreadTokens.GetByUserAndRepo(ctx, userID, repoID)
+	}
+	var token string
+	err := dbconn.Global.QueryRowContext(ctx, "SELECT token FROM discussion_mail_new_thread_tokens WHERE user_id=$1 AND repo_id=$2 AND deleted_at IS NULL", userID, repoID).Scan(&token)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return token, err
+}
+
+// Get returns the user and repository ID found for the given token. If there is
+// none, the token is invalid and ErrInvalidToken is returned.
+func (*discussionMailNewThreadTokens) Get(ctx context.Context, token string) (userID int32, repoID api.RepoID, err error) {
+	if Mocks.DiscussionMailNewThreadTokens.Get != nil {
+		return Mocks.DiscussionMailNewThreadTokens.Get(ctx, token)
+	}
+	err = dbconn.Global.QueryRowContext(ctx, "SELECT user_id, repo_id FROM discussion_mail_new_thread_tokens WHERE token=$1 AND deleted_at IS NULL", token).Scan(
+		&userID,
+		&repoID,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, 0, ErrInvalidToken
+		}
+		return 0, 0, err
+	}
+	return userID, repoID, nil
+}
+
+// Revoke revokes the user's token for the repository (if any), so that emails
+// sent to its address are ignored. The next call to Generate generates a new
+// token.
+func (*discussionMailNewThreadTokens) Revoke(ctx context.Context, userID int32, repoID api.RepoID) error {
+	_, err := dbconn.Global.ExecContext(ctx, "UPDATE discussion_mail_new_thread_tokens SET deleted_at=now() WHERE user_id=$1 AND repo_id=$2 AND deleted_at IS NULL", userID, repoID)
+	return err
+}
@@ -0,0 +1,13 @@
+package db
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+type MockDiscussionMailNewThreadTokens struct {
+	Generate         func(ctx context.Context, userID int32, repoID api.RepoID) (string, error)
+	GetByUserAndRepo func(ctx context.Context, userID int32, repoID api.RepoID) (string, error)
+	Get              func(ctx context.Context, token string) (userID int32, repoID api.RepoID, err error)
+}
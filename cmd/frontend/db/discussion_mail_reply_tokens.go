@@ -39,15 +39,11 @@ func (*discussionMailReplyTokens) Generate(ctx context.Context, userID int32, th
 		return token, nil // use the existing token
 	}
 
-	// Generate a new secure token and store it. We use SHA256 because it is
-	// short and its characters are valid to place in an email address field
-	// like "foo+TOKEN@gmail.com", while still providing good security.
-	h := sha256.New()
-	_, err = io.Copy(h, io.LimitReader(cryptorand.Reader, 128)) // Using 128 bytes just to be on the safe side, but 32 bytes should be enough.
+	// Generate a new secure token and store it.
+	token, err = newMailToken("")
 	if err != nil {
 		return "", err
 	}
-	token = fmt.Sprintf("%x", h.Sum(nil))
 
 	_, err = dbconn.Global.ExecContext(ctx, "INSERT INTO discussion_mail_reply_tokens(token, user_id, thread_id) VALUES($1, $2, $3)", token, userID, threadID)
 	if err != nil {
@@ -56,8 +52,23 @@ func (*discussionMailReplyTokens) Generate(ctx context.Context, userID int32, th
 	return token, nil
 }
 
-// ErrInvalidToken is returned by DiscussionMailReplyTokens.Get when the token is
-// invalid.
+// newMailToken generates a new secure token for use in a sub-address of an email
+// address (e.g. "notifications+TOKEN@example.com"). The prefix (if any)
+// distinguishes the kinds of tokens.
+func newMailToken(prefix string) (string, error) {
+	// We use SHA256 because it is short and its characters are valid to place
+	// in an email address field like "foo+TOKEN@gmail.com", while still
+	// providing good security.
+	h := sha256.New()
+	_, err := io.Copy(h, io.LimitReader(cryptorand.Reader, 128)) // Using 128 bytes just to be on the safe side, but 32 bytes should be enough.
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%x", prefix, h.Sum(nil)), nil
+}
+
+// ErrInvalidToken is returned by DiscussionMailReplyTokens.Get (and the other
+// mail token stores) when the token is invalid.
 var ErrInvalidToken = errors.New("invalid token")
 
 // Get returns the user and thread ID found for the given token. If there
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// discussionThreadSubscriptions provides access to the `discussion_thread_subscriptions` table,
+// which is the single source of truth for who is subscribed to a thread.
+//
+// A user is subscribed to the threads they participate in (see AddParticipants)
+// and to the threads they explicitly subscribed to (see Set). A row with
+// subscribed=false means the user unsubscribed from the thread, which takes
+// precedence over later participation.
+//
+// For a detailed overview of the schema, see schema.md.
+type discussionThreadSubscriptions struct{}
+
+// Set subscribes the user to (or unsubscribes the user from) the thread.
+func (*discussionThreadSubscriptions) Set(ctx context.Context, threadID int64, userID int32, subscribed bool) error {
+	if Mocks.DiscussionThreadSubscriptions.Set != nil {
+		return Mocks.DiscussionThreadSubscriptions.Set(ctx, threadID, userID, subscribed)
+	}
+	_, err := dbconn.Global.ExecContext(ctx, `
+INSERT INTO discussion_thread_subscriptions(thread_id, user_id, subscribed) VALUES($1, $2, $3)
+ON CONFLICT (thread_id, user_id) DO UPDATE SET subscribed=$3, updated_at=now()`,
+		threadID, userID, subscribed,
+	)
+	return err
+}
+
+// Get returns whether the user explicitly subscribed to (true) or
+// unsubscribed from (false) the thread, or nil if the user did neither.
+func (*discussionThreadSubscriptions) Get(ctx context.Context, threadID int64, userID int32) (*bool, error) {
+	if Mocks.DiscussionThreadSubscriptions.Get != nil {
+		return Mocks.DiscussionThreadSubscriptions.Get(ctx, threadID, userID)
+	}
+	var subscribed bool
+	err := dbconn.Global.QueryRowContext(ctx, "SELECT subscribed FROM discussion_thread_subscriptions WHERE thread_id=$1 AND user_id=$2", threadID, userID).Scan(&subscribed)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &subscribed, nil
+}
+
+// AddParticipants subscribes the author and the mentioned users (by username)
+// to the thread, unless they already subscribed to or unsubscribed from it.
+func (*discussionThreadSubscriptions) AddParticipants(ctx context.Context, threadID int64, authorUserID int32, mentionedUsernames []string) error {
+	if Mocks.DiscussionThreadSubscriptions.AddParticipants != nil {
+		return Mocks.DiscussionThreadSubscriptions.AddParticipants(ctx, threadID, authorUserID, mentionedUsernames)
+	}
+	_, err := dbconn.Global.ExecContext(ctx, `
+INSERT INTO discussion_thread_subscriptions(thread_id, user_id, subscribed)
+SELECT $1, id, true FROM users WHERE (id=$2 OR username = ANY($3::citext[])) AND deleted_at IS NULL
+ON CONFLICT (thread_id, user_id) DO NOTHING`,
+		threadID, authorUserID, pq.Array(mentionedUsernames),
+	)
+	return err
+}
+
+// ListSubscribers returns the usernames of the users who are subscribed to
+// the thread, in alphabetical order.
+func (*discussionThreadSubscriptions) ListSubscribers(ctx context.Context, threadID int64) ([]string, error) {
+	if Mocks.DiscussionThreadSubscriptions.ListSubscribers != nil {
+		return Mocks.DiscussionThreadSubscriptions.ListSubscribers(ctx, threadID)
+	}
+	rows, err := dbconn.Global.QueryContext(ctx, `
+SELECT users.username FROM discussion_thread_subscriptions s
+INNER JOIN users ON users.id=s.user_id
+WHERE s.thread_id=$1 AND s.subscribed AND users.deleted_at IS NULL
+ORDER BY users.username`, threadID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var usernames []string
+	for rows.Next() {
+		var username string
+		if err := rows.Scan(&username); err != nil {
+			return nil, err
+		}
+		usernames = append(usernames, username)
+	}
+	return usernames, rows.Err()
+}
+
+// ListThreadIDs returns the IDs of the threads that the user is subscribed to.
+func (*discussionThreadSubscriptions) ListThreadIDs(ctx context.Context, userID int32) ([]int64, error) {
+	if Mocks.DiscussionThreadSubscriptions.ListThreadIDs != nil {
+		return Mocks.DiscussionThreadSubscriptions.ListThreadIDs(ctx, userID)
+	}
+	rows, err := dbconn.Global.QueryContext(ctx, "SELECT thread_id FROM discussion_thread_subscriptions WHERE user_id=$1 AND subscribed ORDER BY thread_id", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var threadIDs []int64
+	for rows.Next() {
+		var threadID int64
+		if err := rows.Scan(&threadID); err != nil {
+			return nil, err
+		}
+		threadIDs = append(threadIDs, threadID)
+	}
+	return threadIDs, rows.Err()
+}
@@ -0,0 +1,11 @@
+package db
+
+import "context"
+
+type MockDiscussionThreadSubscriptions struct {
+	Set             func(ctx context.Context, threadID int64, userID int32, subscribed bool) error
+	Get             func(ctx context.Context, threadID int64, userID int32) (*bool, error)
+	AddParticipants func(ctx context.Context, threadID int64, authorUserID int32, mentionedUsernames []string) error
+	ListSubscribers func(ctx context.Context, threadID int64) ([]string, error)
+	ListThreadIDs   func(ctx context.Context, userID int32) ([]int64, error)
+}
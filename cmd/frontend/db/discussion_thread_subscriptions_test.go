@@ -0,0 +1,106 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+)
+
+func TestDiscussionThreadSubscriptions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	alice, err := Users.Create(ctx, NewUser{Username: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := Users.Create(ctx, NewUser{Username: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a repository to comply with the postgres repo constraint.
+	if err := Repos.Upsert(ctx, api.InsertRepoOp{Name: "myrepo", Description: "", Fork: false, Enabled: true}); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := Repos.GetByName(ctx, "myrepo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var threadIDs []int64
+	for i := 0; i < 2; i++ {
+		thread, err := DiscussionThreads.Create(ctx, &types.DiscussionThread{
+			AuthorUserID: alice.ID,
+			Title:        "Hello world!",
+			TargetRepo:   &types.DiscussionThreadTargetRepo{RepoID: repo.ID},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := DiscussionComments.Create(ctx, &types.DiscussionComment{
+			ThreadID:     thread.ID,
+			AuthorUserID: alice.ID,
+			Contents:     "Hello world!",
+		}); err != nil {
+			t.Fatal(err)
+		}
+		threadIDs = append(threadIDs, thread.ID)
+	}
+
+	// Alice authored both threads, and she mentioned Bob (and a nonexistent
+	// user) in the 1st thread.
+	if err := DiscussionThreadSubscriptions.AddParticipants(ctx, threadIDs[0], alice.ID, []string{"bob", "nobody"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := DiscussionThreadSubscriptions.AddParticipants(ctx, threadIDs[1], alice.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+	if subscribed, err := DiscussionThreadSubscriptions.Get(ctx, threadIDs[1], bob.ID); err != nil {
+		t.Fatal(err)
+	} else if subscribed != nil {
+		t.Fatalf("got subscribed %v, want nil", *subscribed)
+	}
+
+	// Bob subscribes to the 2nd thread, and Alice unsubscribes from the 1st
+	// thread (which stays in effect when she participates in it again).
+	if err := DiscussionThreadSubscriptions.Set(ctx, threadIDs[1], bob.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := DiscussionThreadSubscriptions.Set(ctx, threadIDs[0], alice.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := DiscussionThreadSubscriptions.AddParticipants(ctx, threadIDs[0], alice.ID, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range [][]string{{"bob"}, {"alice", "bob"}} {
+		subscribers, err := DiscussionThreadSubscriptions.ListSubscribers(ctx, threadIDs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(subscribers, want) {
+			t.Errorf("thread %d: got subscribers %v, want %v", threadIDs[i], subscribers, want)
+		}
+	}
+	for _, test := range []struct {
+		userID int32
+		want   []int64
+	}{
+		{userID: alice.ID, want: []int64{threadIDs[1]}},
+		{userID: bob.ID, want: threadIDs},
+	} {
+		ids, err := DiscussionThreadSubscriptions.ListThreadIDs(ctx, test.userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(ids, test.want) {
+			t.Errorf("user %d: got thread IDs %v, want %v", test.userID, ids, test.want)
+		}
+	}
+}
@@ -44,6 +44,11 @@ func (t *discussionThreads) Create(ctx context.Context, newThread *types.Discuss
 	if Mocks.DiscussionThreads.Create != nil {
 		return Mocks.DiscussionThreads.Create(ctx, newThread)
 	}
+	return t.create(ctx, dbconn.Global, newThread)
+}
+
+// create creates the thread using db, which may be a transaction.
+func (t *discussionThreads) create(ctx context.Context, db queryable, newThread *types.DiscussionThread) (*types.DiscussionThread, error) {
 
 	// Validate the input thread.
 	if newThread == nil {
@@ -70,6 +75,9 @@ func (t *discussionThreads) Create(ctx context.Context, newThread *types.Discuss
 	if newThread.DeletedAt != nil {
 		return nil, errors.New("newThread.DeletedAt must not be specified")
 	}
+	if newThread.ResolvedAt != nil || newThread.ResolvedByUserID != nil {
+		return nil, errors.New("newThread.ResolvedAt and newThread.ResolvedByUserID must not be specified")
+	}
 	if newThread.TargetRepo != nil {
 		if rev := newThread.TargetRepo.Revision; rev != nil {
 			if !git.IsAbsoluteRevision(*rev) {
@@ -85,7 +93,7 @@ func (t *discussionThreads) Create(ctx context.Context, newThread *types.Discuss
 	// First, create the thread itself. Initially it will have no target.
 	newThread.CreatedAt = time.Now()
 	newThread.UpdatedAt = newThread.CreatedAt
-	err := dbconn.Global.QueryRowContext(ctx, `INSERT INTO discussion_threads(
+	err := db.QueryRowContext(ctx, `INSERT INTO discussion_threads(
 		author_user_id,
 		title,
 		created_at,
@@ -108,7 +116,7 @@ func (t *discussionThreads) Create(ctx context.Context, newThread *types.Discuss
 	switch {
 	case newThread.TargetRepo != nil:
 		var err error
-		newThread.TargetRepo, err = t.createTargetRepo(ctx, newThread.TargetRepo, newThread.ID)
+		newThread.TargetRepo, err = t.createTargetRepo(ctx, db, newThread.TargetRepo, newThread.ID)
 		if err != nil {
 			return nil, errors.Wrap(err, "createTargetRepo")
 		}
@@ -119,7 +127,7 @@ func (t *discussionThreads) Create(ctx context.Context, newThread *types.Discuss
 	}
 
 	// Update the thread to reference the target we just created.
-	_, err = dbconn.Global.ExecContext(ctx, `UPDATE discussion_threads SET `+targetName+`=$1 WHERE id=$2`, targetID, newThread.ID)
+	_, err = db.ExecContext(ctx, `UPDATE discussion_threads SET `+targetName+`=$1 WHERE id=$2`, targetID, newThread.ID)
 	if err != nil {
 		return nil, errors.Wrap(err, "update thread target")
 	}
@@ -143,6 +151,13 @@ type DiscussionThreadsUpdateOptions struct {
 	// Archive, when non-nil, specifies whether the thread is archived or not.
 	Archive *bool
 
+	// Resolve, when non-nil, specifies whether the thread is resolved or not.
+	Resolve *bool
+
+	// ResolvedByUserID is the user who resolved the thread. It must be set
+	// when Resolve is true.
+	ResolvedByUserID int32
+
 	// Delete, when true, specifies that the thread should be deleted. This
 	// operation cannot be undone.
 	Delete bool
@@ -167,6 +182,10 @@ func (t *discussionThreads) Update(ctx context.Context, threadID int64, opts *Di
 
 	// TODO(slimsag:discussions): should be in a transaction
 
+	if opts.Resolve != nil && *opts.Resolve && opts.ResolvedByUserID == 0 {
+		return nil, errors.New("ResolvedByUserID must be set when resolving a thread")
+	}
+
 	anyUpdate := false
 	if opts.Archive != nil {
 		anyUpdate = true
@@ -178,6 +197,20 @@ func (t *discussionThreads) Update(ctx context.Context, threadID int64, opts *Di
 			return nil, err
 		}
 	}
+	if opts.Resolve != nil {
+		anyUpdate = true
+		var (
+			resolvedAt       *time.Time
+			resolvedByUserID *int32
+		)
+		if *opts.Resolve {
+			resolvedAt = &now
+			resolvedByUserID = &opts.ResolvedByUserID
+		}
+		if _, err := dbconn.Global.ExecContext(ctx, "UPDATE discussion_threads SET resolved_at=$1, resolved_by_user_id=$2 WHERE id=$3 AND deleted_at IS NULL", resolvedAt, resolvedByUserID, threadID); err != nil {
+			return nil, err
+		}
+	}
 	if opts.Delete {
 		anyUpdate = true
 		if _, err := dbconn.Global.ExecContext(ctx, "UPDATE discussion_threads SET deleted_at=$1 WHERE id=$2 AND deleted_at IS NULL", now, threadID); err != nil {
@@ -202,10 +235,13 @@ func (t *discussionThreads) Update(ctx context.Context, threadID int64, opts *Di
 		// Intentionally not setting anyUpdate=true here, it would cause us to
 		// try to update updated_at below which would fail.
 
-		// Hard delete the mail reply tokens.
+		// Hard delete the mail reply tokens and subscriptions.
 		if _, err := dbconn.Global.ExecContext(ctx, "DELETE FROM discussion_mail_reply_tokens WHERE thread_id=$1", threadID); err != nil {
 			return nil, err
 		}
+		if _, err := dbconn.Global.ExecContext(ctx, "DELETE FROM discussion_thread_subscriptions WHERE thread_id=$1", threadID); err != nil {
+			return nil, err
+		}
 
 		// Unlink and hard delete discussion thread targets.
 		if _, err := dbconn.Global.ExecContext(ctx, "UPDATE discussion_threads SET target_repo_id=null WHERE id=$1", threadID); err != nil {
@@ -286,6 +322,10 @@ type DiscussionThreadsListOptions struct {
 	// Reported, when true, specifies that only threads with at least one
 	// reported comment should be returned.
 	Reported bool
+
+	// Resolved, when non-nil, specifies that only threads that are (or are
+	// not) resolved should be returned.
+	Resolved *bool
 }
 
 // SetFromQuery sets the options based on the search query string.
@@ -327,6 +367,17 @@ func (opts *DiscussionThreadsListOptions) SetFromQuery(ctx context.Context, quer
 		return
 	}
 
+	findSubscribedThreadIDs := func(value string) (threadIDs []int64) {
+		for _, user := range userList(value) {
+			ids, err := DiscussionThreadSubscriptions.ListThreadIDs(ctx, user.ID)
+			if err != nil {
+				continue
+			}
+			threadIDs = append(threadIDs, ids...)
+		}
+		return
+	}
+
 	parseTimeOrDuration := func(value string) *time.Time {
 		// Try parsing as RFC3339 / ISO 8601 first.
 		t, err := time.Parse(time.RFC3339, value)
@@ -421,6 +472,24 @@ func (opts *DiscussionThreadsListOptions) SetFromQuery(ctx context.Context, quer
 		"reported": func(value string) {
 			reported, _ = strconv.ParseBool(value)
 		},
+
+		// syntax: "resolved:true" or "resolved:false"
+		"resolved": func(value string) {
+			if resolved, err := strconv.ParseBool(value); err == nil {
+				opts.Resolved = &resolved
+			}
+		},
+
+		// syntax: "subscriber:slimsag" or "subscriber:@slimsag" or `subscriber:"slimsag @jack"`
+		"subscriber": func(value string) {
+			opts.ThreadIDs = append(opts.ThreadIDs, findSubscribedThreadIDs(value)...)
+			if len(opts.ThreadIDs) == 0 {
+				opts.ThreadIDs = []int64{-1}
+			}
+		},
+		"-subscriber": func(value string) {
+			opts.NotThreadIDs = append(opts.NotThreadIDs, findSubscribedThreadIDs(value)...)
+		},
 	}
 	remaining, operations := searchquery.Parse(query)
 	for _, operation := range operations {
@@ -580,6 +649,13 @@ func (*discussionThreads) getListSQL(opts *DiscussionThreadsListOptions) (conds
 	if opts.CreatedAfter != nil {
 		conds = append(conds, sqlf.Sprintf("created_at > %v", *opts.CreatedAfter))
 	}
+	if opts.Resolved != nil {
+		if *opts.Resolved {
+			conds = append(conds, sqlf.Sprintf("resolved_at IS NOT NULL"))
+		} else {
+			conds = append(conds, sqlf.Sprintf("resolved_at IS NULL"))
+		}
+	}
 
 	if opts.TargetRepoID != nil || opts.TargetRepoPath != nil || opts.NotTargetRepoID != nil || opts.NotTargetRepoPath != nil {
 		targetRepoConds := []*sqlf.Query{}
@@ -621,7 +697,7 @@ func (*discussionThreads) getCountBySQL(ctx context.Context, query string, args
 }
 
 // createTargetRepo handles the creation of a repo-based discussion thread target.
-func (t *discussionThreads) createTargetRepo(ctx context.Context, tr *types.DiscussionThreadTargetRepo, threadID int64) (*types.DiscussionThreadTargetRepo, error) {
+func (t *discussionThreads) createTargetRepo(ctx context.Context, db queryable, tr *types.DiscussionThreadTargetRepo, threadID int64) (*types.DiscussionThreadTargetRepo, error) {
 	var fields []*sqlf.Query
 	var values []*sqlf.Query
 	field := func(name string, arg interface{}) {
@@ -654,7 +730,7 @@ func (t *discussionThreads) createTargetRepo(ctx context.Context, tr *types.Disc
 	//fmt.Println(q.Query(sqlf.PostgresBindVar))
 	//fmt.Println(q.Args())
 
-	err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&tr.ID)
+	err := db.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&tr.ID)
 	if err != nil {
 		return nil, err
 	}
@@ -671,7 +747,9 @@ func (t *discussionThreads) getBySQL(ctx context.Context, query string, args ...
 			t.target_repo_id,
 			t.created_at,
 			t.archived_at,
-			t.updated_at
+			t.updated_at,
+			t.resolved_at,
+			t.resolved_by_user_id
 		FROM discussion_threads t `+query, args...)
 	if err != nil {
 		return nil, err
@@ -692,6 +770,8 @@ func (t *discussionThreads) getBySQL(ctx context.Context, query string, args ...
 			&thread.CreatedAt,
 			&thread.ArchivedAt,
 			&thread.UpdatedAt,
+			&thread.ResolvedAt,
+			&thread.ResolvedByUserID,
 		)
 		if err != nil {
 			return nil, err
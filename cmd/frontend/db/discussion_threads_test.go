@@ -122,6 +122,34 @@ func TestDiscussionThreads_Update(t *testing.T) {
 	if gotThread.ArchivedAt == nil {
 		t.Fatal("expected thread to be archived")
 	}
+
+	// Resolve the thread.
+	gotThread, err = DiscussionThreads.Update(ctx, thread.ID, &DiscussionThreadsUpdateOptions{
+		Resolve:          boolPtr(true),
+		ResolvedByUserID: user.ID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotThread.ResolvedAt == nil || gotThread.ResolvedByUserID == nil || *gotThread.ResolvedByUserID != user.ID {
+		t.Fatalf("expected thread to be resolved by user %d, got %+v", user.ID, gotThread)
+	}
+	if n, err := DiscussionThreads.Count(ctx, &DiscussionThreadsListOptions{Resolved: boolPtr(false)}); err != nil {
+		t.Fatal(err)
+	} else if n != 0 {
+		t.Fatalf("got %d unresolved threads, want 0", n)
+	}
+
+	// Unresolve the thread.
+	gotThread, err = DiscussionThreads.Update(ctx, thread.ID, &DiscussionThreadsUpdateOptions{
+		Resolve: boolPtr(false),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotThread.ResolvedAt != nil || gotThread.ResolvedByUserID != nil {
+		t.Fatalf("expected thread to be unresolved, got %+v", gotThread)
+	}
 }
 
 func TestDiscussionThreads_Count(t *testing.T) {
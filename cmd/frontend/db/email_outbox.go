@@ -0,0 +1,252 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// The states of an email in the outbox.
+const (
+	OutboxEmailStatePending = "pending" // the email has not yet been sent and will be (re)attempted
+	OutboxEmailStateSent    = "sent"    // the mail server (or email API) accepted the email
+	OutboxEmailStateFailed  = "failed"  // all attempts failed, and the email was abandoned
+	OutboxEmailStateBounced = "bounced" // the recipient's mail server permanently rejected the email
+)
+
+// OutboxEmail is a rendered email to a single recipient in the email outbox.
+type OutboxEmail struct {
+	ID            int64
+	FromName      string
+	FromAddress   string
+	ToAddress     string
+	ReplyTo       *string
+	MessageID     *string
+	References    []string // the message IDs of the "References" header
+	Subject       string
+	TextBody      string // cleared once the email is sent
+	HTMLBody      string // cleared once the email is sent
+	State         string // the email's state (e.g., OutboxEmailStatePending)
+	Attempts      int32  // the number of delivery attempts so far
+	NextAttemptAt time.Time
+	LastAttemptAt *time.Time
+	Error         *string // the error of the last attempt, if any
+	CreatedAt     time.Time
+}
+
+// ErrOutboxEmailNotFound occurs when a database operation expects a specific email in the outbox
+// to exist but it does not exist.
+var ErrOutboxEmailNotFound = errors.New("outbox email not found")
+
+// emailOutboxLease is how long a dequeued email is reserved for the worker that dequeued it. If the
+// worker doesn't record the attempt within this time (e.g., because it was terminated), another
+// worker may dequeue the email again.
+const emailOutboxLease = 2 * time.Minute
+
+// emailOutbox provides access to the `email_outbox` table, which is both the queue and the history
+// of outgoing emails.
+type emailOutbox struct{}
+
+// Create enqueues the email. It is sent as soon as possible. The email's ID, state, and other
+// fields that are set by the database are updated.
+func (s *emailOutbox) Create(ctx context.Context, e *OutboxEmail) error {
+	if Mocks.EmailOutbox.Create != nil {
+		return Mocks.EmailOutbox.Create(e)
+	}
+
+	if e.References == nil {
+		e.References = []string{}
+	}
+	return dbconn.Global.QueryRowContext(ctx, `
+INSERT INTO email_outbox(from_name, from_address, to_address, reply_to, message_id, message_references, subject, text_body, html_body)
+VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, state, attempts, next_attempt_at, created_at`,
+		e.FromName, e.FromAddress, e.ToAddress, e.ReplyTo, e.MessageID, pq.Array(e.References), e.Subject, e.TextBody, e.HTMLBody,
+	).Scan(&e.ID, &e.State, &e.Attempts, &e.NextAttemptAt, &e.CreatedAt)
+}
+
+// GetByID returns the email in the outbox with the given ID.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (s *emailOutbox) GetByID(ctx context.Context, id int64) (*OutboxEmail, error) {
+	if Mocks.EmailOutbox.GetByID != nil {
+		return Mocks.EmailOutbox.GetByID(id)
+	}
+
+	results, err := s.list(ctx, sqlf.Sprintf("id=%d", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrOutboxEmailNotFound
+	}
+	return results[0], nil
+}
+
+// Dequeue returns up to limit pending emails that are due to be sent, oldest first. The returned
+// emails are leased to the caller, which must record the attempt with MarkAttempt. Concurrent
+// callers never receive the same emails.
+func (s *emailOutbox) Dequeue(ctx context.Context, limit int) ([]*OutboxEmail, error) {
+	if Mocks.EmailOutbox.Dequeue != nil {
+		return Mocks.EmailOutbox.Dequeue(limit)
+	}
+
+	rows, err := dbconn.Global.QueryContext(ctx, `
+UPDATE email_outbox SET next_attempt_at=now() + $1 * interval '1 second'
+WHERE id IN (
+	SELECT id FROM email_outbox
+	WHERE state='pending' AND next_attempt_at<=now()
+	ORDER BY next_attempt_at ASC
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING id`,
+		emailOutboxLease.Seconds(), limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	idQueries := make([]*sqlf.Query, len(ids))
+	for i, id := range ids {
+		idQueries[i] = sqlf.Sprintf("%d", id)
+	}
+	return s.list(ctx, sqlf.Sprintf("id IN (%s)", sqlf.Join(idQueries, ",")), nil)
+}
+
+// OutboxEmailAttempt is the result of an attempt to send an email in the outbox.
+type OutboxEmailAttempt struct {
+	State         string     // the email's new state
+	NextAttemptAt *time.Time // when to attempt to send the email again (if State is pending)
+	Error         string     // the error that occurred (empty if none)
+}
+
+// MarkAttempt records an attempt to send the email in the outbox with the given ID. The bodies of
+// emails that were sent are cleared, because they may contain private content (such as code and
+// comments) that the outbox doesn't need to keep once the email is delivered.
+func (*emailOutbox) MarkAttempt(ctx context.Context, id int64, attempt OutboxEmailAttempt) error {
+	if Mocks.EmailOutbox.MarkAttempt != nil {
+		return Mocks.EmailOutbox.MarkAttempt(id, attempt)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx, `
+UPDATE email_outbox SET
+	state=$2,
+	attempts=attempts+1,
+	last_attempt_at=now(),
+	next_attempt_at=COALESCE($3, next_attempt_at),
+	error=NULLIF($4, ''),
+	text_body=CASE WHEN $2=$5 THEN '' ELSE text_body END,
+	html_body=CASE WHEN $2=$5 THEN '' ELSE html_body END
+WHERE id=$1`,
+		id, attempt.State, attempt.NextAttemptAt, attempt.Error, OutboxEmailStateSent,
+	)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return ErrOutboxEmailNotFound
+	}
+	return nil
+}
+
+// EmailOutboxListOptions contains options for listing the emails in the outbox.
+type EmailOutboxListOptions struct {
+	State string // only list the emails in this state
+	*LimitOffset
+}
+
+func (o EmailOutboxListOptions) sqlConditions() *sqlf.Query {
+	if o.State != "" {
+		return sqlf.Sprintf("state=%s", o.State)
+	}
+	return sqlf.Sprintf("TRUE")
+}
+
+// List lists the emails in the outbox that satisfy the options, most recent first.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (s *emailOutbox) List(ctx context.Context, opt EmailOutboxListOptions) ([]*OutboxEmail, error) {
+	if Mocks.EmailOutbox.List != nil {
+		return Mocks.EmailOutbox.List(opt)
+	}
+	return s.list(ctx, opt.sqlConditions(), opt.LimitOffset)
+}
+
+// Count counts the emails in the outbox that satisfy the options (ignoring limit and offset).
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*emailOutbox) Count(ctx context.Context, opt EmailOutboxListOptions) (int, error) {
+	if Mocks.EmailOutbox.Count != nil {
+		return Mocks.EmailOutbox.Count(opt)
+	}
+
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM email_outbox WHERE (%s)", opt.sqlConditions())
+	var count int
+	if err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (*emailOutbox) list(ctx context.Context, cond *sqlf.Query, limitOffset *LimitOffset) ([]*OutboxEmail, error) {
+	q := sqlf.Sprintf(`
+SELECT id, from_name, from_address, to_address, reply_to, message_id, message_references, subject, text_body, html_body, state, attempts, next_attempt_at, last_attempt_at, error, created_at
+FROM email_outbox
+WHERE (%s)
+ORDER BY id DESC
+%s`,
+		cond,
+		limitOffset.SQL(),
+	)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*OutboxEmail
+	for rows.Next() {
+		var e OutboxEmail
+		if err := rows.Scan(&e.ID, &e.FromName, &e.FromAddress, &e.ToAddress, &e.ReplyTo, &e.MessageID, pq.Array(&e.References), &e.Subject, &e.TextBody, &e.HTMLBody, &e.State, &e.Attempts, &e.NextAttemptAt, &e.LastAttemptAt, &e.Error, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, &e)
+	}
+	return results, rows.Err()
+}
+
+// DeleteOlderThan deletes the emails in the outbox that are no longer pending and were created
+// before the given time, so that the history doesn't grow without bound.
+func (*emailOutbox) DeleteOlderThan(ctx context.Context, t time.Time) error {
+	if Mocks.EmailOutbox.DeleteOlderThan != nil {
+		return Mocks.EmailOutbox.DeleteOlderThan(t)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx, "DELETE FROM email_outbox WHERE state<>'pending' AND created_at<$1", t)
+	return err
+}
@@ -0,0 +1,13 @@
+package db
+
+import "time"
+
+type MockEmailOutbox struct {
+	Create          func(e *OutboxEmail) error
+	GetByID         func(id int64) (*OutboxEmail, error)
+	Dequeue         func(limit int) ([]*OutboxEmail, error)
+	MarkAttempt     func(id int64, attempt OutboxEmailAttempt) error
+	List            func(opt EmailOutboxListOptions) ([]*OutboxEmail, error)
+	Count           func(opt EmailOutboxListOptions) (int, error)
+	DeleteOlderThan func(t time.Time) error
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+)
+
+func TestEmailOutbox(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	e1 := &OutboxEmail{FromName: "Sourcegraph", FromAddress: "noreply@example.com", ToAddress: "a@example.com", Subject: "s1", TextBody: "t", HTMLBody: "h", References: []string{"r1", "r2"}}
+	e2 := &OutboxEmail{FromName: "Sourcegraph", FromAddress: "noreply@example.com", ToAddress: "b@example.com", Subject: "s2", TextBody: "t", HTMLBody: "h"}
+	for _, e := range []*OutboxEmail{e1, e2} {
+		if err := EmailOutbox.Create(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+		if e.State != OutboxEmailStatePending {
+			t.Errorf("got state %q, want %q", e.State, OutboxEmailStatePending)
+		}
+	}
+
+	// Dequeued emails are leased, so they aren't dequeued again.
+	dequeued, err := EmailOutbox.Dequeue(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dequeued) != 2 {
+		t.Fatalf("got %d dequeued emails, want 2", len(dequeued))
+	}
+	if again, err := EmailOutbox.Dequeue(ctx, 10); err != nil {
+		t.Fatal(err)
+	} else if len(again) != 0 {
+		t.Errorf("got %d emails dequeued again, want 0", len(again))
+	}
+
+	// Record a failed attempt that is retried, and a successful attempt.
+	next := time.Now().Add(-time.Second)
+	if err := EmailOutbox.MarkAttempt(ctx, e1.ID, OutboxEmailAttempt{State: OutboxEmailStatePending, NextAttemptAt: &next, Error: "connection refused"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := EmailOutbox.MarkAttempt(ctx, e2.ID, OutboxEmailAttempt{State: OutboxEmailStateSent}); err != nil {
+		t.Fatal(err)
+	}
+	if err := EmailOutbox.MarkAttempt(ctx, -1, OutboxEmailAttempt{State: OutboxEmailStateSent}); err != ErrOutboxEmailNotFound {
+		t.Errorf("got error %v, want ErrOutboxEmailNotFound", err)
+	}
+
+	// The email to retry is due again.
+	if dequeued, err := EmailOutbox.Dequeue(ctx, 10); err != nil {
+		t.Fatal(err)
+	} else if len(dequeued) != 1 || dequeued[0].ID != e1.ID {
+		t.Fatalf("got %s, want only email %d", asJSON(t, dequeued), e1.ID)
+	} else if got := dequeued[0]; got.Attempts != 1 || got.Error == nil || *got.Error != "connection refused" || len(got.References) != 2 {
+		t.Errorf("got %s, want 1 failed attempt", asJSON(t, got))
+	}
+
+	if n, err := EmailOutbox.Count(ctx, EmailOutboxListOptions{State: OutboxEmailStateSent}); err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Errorf("got %d sent emails, want 1", n)
+	}
+	if es, err := EmailOutbox.List(ctx, EmailOutboxListOptions{}); err != nil {
+		t.Fatal(err)
+	} else if len(es) != 2 || es[0].ID != e2.ID {
+		t.Errorf("got %s, want most recent first", asJSON(t, es))
+	}
+
+	// Only emails that are no longer pending are deleted.
+	if err := EmailOutbox.DeleteOlderThan(ctx, time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if es, err := EmailOutbox.List(ctx, EmailOutboxListOptions{}); err != nil {
+		t.Fatal(err)
+	} else if len(es) != 1 || es[0].ID != e1.ID {
+		t.Errorf("got %s, want only pending email %d", asJSON(t, es), e1.ID)
+	}
+}
@@ -0,0 +1,208 @@
+package db
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// ExplicitRepoPermission is a list of the users and organizations that can read a repository (or
+// the repositories whose names match a pattern), as set by a site admin. It is used for
+// repositories whose code host has no authz provider (such as Gitolite).
+type ExplicitRepoPermission struct {
+	ID          int32
+	RepoID      api.RepoID   // the repository (zero if RepoPattern is set)
+	RepoName    api.RepoName // the name of the repository (empty if RepoPattern is set)
+	RepoPattern string       // a regular expression matched against entire repository names (empty if RepoID is set)
+	UserIDs     []int32      // the users who can read the repositories
+	OrgIDs      []int32      // the organizations whose members can read the repositories
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// CompileExplicitRepoPattern compiles the RepoPattern of explicit repository permissions. The
+// pattern must match the entire repository name (it is anchored at both ends), so that a pattern
+// such as "example.com/foo" doesn't also apply to "example.com/foo-secret".
+func CompileExplicitRepoPattern(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + pattern + ")$")
+}
+
+// ErrExplicitRepoPermissionsNotFound occurs when a database operation expects specific explicit
+// repository permissions to exist but they do not exist.
+var ErrExplicitRepoPermissionsNotFound = errors.New("explicit repository permissions not found")
+
+// explicitRepoPermissions provides access to the `explicit_repo_permissions` table.
+type explicitRepoPermissions struct{}
+
+// SetForRepo sets the users and organizations that can read the repository, replacing the
+// previously set ones.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (s *explicitRepoPermissions) SetForRepo(ctx context.Context, repoID api.RepoID, userIDs, orgIDs []int32) (*ExplicitRepoPermission, error) {
+	if Mocks.ExplicitRepoPermissions.SetForRepo != nil {
+		return Mocks.ExplicitRepoPermissions.SetForRepo(repoID, userIDs, orgIDs)
+	}
+
+	var id int32
+	if err := dbconn.Global.QueryRowContext(ctx, `
+INSERT INTO explicit_repo_permissions(repo_id, user_ids, org_ids)
+VALUES($1, $2, $3)
+ON CONFLICT (repo_id) DO UPDATE SET user_ids=EXCLUDED.user_ids, org_ids=EXCLUDED.org_ids, updated_at=now()
+RETURNING id`,
+		repoID, pq.Array(toInt64s(userIDs)), pq.Array(toInt64s(orgIDs)),
+	).Scan(&id); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, id)
+}
+
+// SetForPattern sets the users and organizations that can read the repositories whose names match
+// the regular expression pattern (see CompileExplicitRepoPattern), replacing the previously set
+// ones for the same pattern.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (s *explicitRepoPermissions) SetForPattern(ctx context.Context, pattern string, userIDs, orgIDs []int32) (*ExplicitRepoPermission, error) {
+	if Mocks.ExplicitRepoPermissions.SetForPattern != nil {
+		return Mocks.ExplicitRepoPermissions.SetForPattern(pattern, userIDs, orgIDs)
+	}
+
+	if pattern == "" {
+		return nil, errors.New("empty repository name pattern")
+	}
+	if _, err := CompileExplicitRepoPattern(pattern); err != nil {
+		return nil, errors.Wrap(err, "invalid repository name pattern")
+	}
+
+	var id int32
+	if err := dbconn.Global.QueryRowContext(ctx, `
+INSERT INTO explicit_repo_permissions(repo_pattern, user_ids, org_ids)
+VALUES($1, $2, $3)
+ON CONFLICT (repo_pattern) DO UPDATE SET user_ids=EXCLUDED.user_ids, org_ids=EXCLUDED.org_ids, updated_at=now()
+RETURNING id`,
+		pattern, pq.Array(toInt64s(userIDs)), pq.Array(toInt64s(orgIDs)),
+	).Scan(&id); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, id)
+}
+
+// GetByID returns the explicit repository permissions with the given ID.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (s *explicitRepoPermissions) GetByID(ctx context.Context, id int32) (*ExplicitRepoPermission, error) {
+	if Mocks.ExplicitRepoPermissions.GetByID != nil {
+		return Mocks.ExplicitRepoPermissions.GetByID(id)
+	}
+
+	results, err := s.list(ctx, sqlf.Sprintf("p.id=%d", id))
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrExplicitRepoPermissionsNotFound
+	}
+	return results[0], nil
+}
+
+// List lists all explicit repository permissions.
+func (s *explicitRepoPermissions) List(ctx context.Context) ([]*ExplicitRepoPermission, error) {
+	if Mocks.ExplicitRepoPermissions.List != nil {
+		return Mocks.ExplicitRepoPermissions.List()
+	}
+	return s.list(ctx, sqlf.Sprintf("TRUE"))
+}
+
+// ListGranted lists the explicit repository permissions that grant access to the user, either
+// directly or through the user's membership in an organization.
+func (s *explicitRepoPermissions) ListGranted(ctx context.Context, userID int32) ([]*ExplicitRepoPermission, error) {
+	if Mocks.ExplicitRepoPermissions.ListGranted != nil {
+		return Mocks.ExplicitRepoPermissions.ListGranted(userID)
+	}
+	return s.list(ctx, sqlf.Sprintf("%d = ANY(p.user_ids) OR p.org_ids && ARRAY(SELECT org_id FROM org_members WHERE user_id=%d)", userID, userID))
+}
+
+func (*explicitRepoPermissions) list(ctx context.Context, cond *sqlf.Query) ([]*ExplicitRepoPermission, error) {
+	q := sqlf.Sprintf(`
+SELECT p.id, p.repo_id, repo.name, p.repo_pattern, p.user_ids, p.org_ids, p.created_at, p.updated_at
+FROM explicit_repo_permissions p
+LEFT JOIN repo ON repo.id=p.repo_id
+WHERE (%s)
+ORDER BY p.id ASC`, cond)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*ExplicitRepoPermission
+	for rows.Next() {
+		var (
+			p               ExplicitRepoPermission
+			repoID          *int32
+			repoName        *string
+			repoPattern     *string
+			userIDs, orgIDs []int64
+		)
+		if err := rows.Scan(&p.ID, &repoID, &repoName, &repoPattern, pq.Array(&userIDs), pq.Array(&orgIDs), &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if repoID != nil {
+			p.RepoID = api.RepoID(*repoID)
+		}
+		if repoName != nil {
+			p.RepoName = api.RepoName(*repoName)
+		}
+		if repoPattern != nil {
+			p.RepoPattern = *repoPattern
+		}
+		p.UserIDs = toInt32s(userIDs)
+		p.OrgIDs = toInt32s(orgIDs)
+		results = append(results, &p)
+	}
+	return results, rows.Err()
+}
+
+// Delete deletes the explicit repository permissions with the given ID. The repositories are then
+// no longer restricted to the users and organizations that they listed.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*explicitRepoPermissions) Delete(ctx context.Context, id int32) error {
+	if Mocks.ExplicitRepoPermissions.Delete != nil {
+		return Mocks.ExplicitRepoPermissions.Delete(id)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx, "DELETE FROM explicit_repo_permissions WHERE id=$1", id)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return ErrExplicitRepoPermissionsNotFound
+	}
+	return nil
+}
+
+func toInt64s(ids []int32) []int64 {
+	v := make([]int64, len(ids))
+	for i, id := range ids {
+		v[i] = int64(id)
+	}
+	return v
+}
+
+func toInt32s(ids []int64) []int32 {
+	v := make([]int32, len(ids))
+	for i, id := range ids {
+		v[i] = int32(id)
+	}
+	return v
+}
@@ -0,0 +1,12 @@
+package db
+
+import "github.com/sourcegraph/sourcegraph/pkg/api"
+
+type MockExplicitRepoPermissions struct {
+	SetForRepo    func(repoID api.RepoID, userIDs, orgIDs []int32) (*ExplicitRepoPermission, error)
+	SetForPattern func(pattern string, userIDs, orgIDs []int32) (*ExplicitRepoPermission, error)
+	GetByID       func(id int32) (*ExplicitRepoPermission, error)
+	List          func() ([]*ExplicitRepoPermission, error)
+	ListGranted   func(userID int32) ([]*ExplicitRepoPermission, error)
+	Delete        func(id int32) error
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+)
+
+func TestExplicitRepoPermissions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	user1, err := Users.Create(ctx, NewUser{Username: "u1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user2, err := Users.Create(ctx, NewUser{Username: "u2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	org, err := Orgs.Create(ctx, "o", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OrgMembers.Create(ctx, org.ID, user2.ID); err != nil {
+		t.Fatal(err)
+	}
+	repos := mustCreate(ctx, t, &types.Repo{Name: "a"}, &types.Repo{Name: "b"})
+
+	// The second SetForRepo replaces the first.
+	if _, err := ExplicitRepoPermissions.SetForRepo(ctx, repos[0].ID, []int32{user2.ID}, nil); err != nil {
+		t.Fatal(err)
+	}
+	p1, err := ExplicitRepoPermissions.SetForRepo(ctx, repos[0].ID, []int32{user1.ID}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p1.RepoName != "a" || !reflect.DeepEqual(p1.UserIDs, []int32{user1.ID}) || len(p1.OrgIDs) != 0 {
+		t.Errorf("got %+v, want repo a with user %d", p1, user1.ID)
+	}
+	if _, err := ExplicitRepoPermissions.SetForPattern(ctx, "(", nil, nil); err == nil {
+		t.Error("got no error for invalid pattern")
+	}
+	p2, err := ExplicitRepoPermissions.SetForPattern(ctx, "^b", nil, []int32{org.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if all, err := ExplicitRepoPermissions.List(ctx); err != nil {
+		t.Fatal(err)
+	} else if len(all) != 2 || all[0].ID != p1.ID || all[1].ID != p2.ID {
+		t.Errorf("got %s, want IDs %d and %d", asJSON(t, all), p1.ID, p2.ID)
+	}
+
+	// Each user is granted access directly or through their organization membership.
+	for _, test := range []struct {
+		userID int32
+		want   int32
+	}{{user1.ID, p1.ID}, {user2.ID, p2.ID}} {
+		if granted, err := ExplicitRepoPermissions.ListGranted(ctx, test.userID); err != nil {
+			t.Fatal(err)
+		} else if len(granted) != 1 || granted[0].ID != test.want {
+			t.Errorf("user %d: got %s, want ID %d", test.userID, asJSON(t, granted), test.want)
+		}
+	}
+
+	if err := ExplicitRepoPermissions.Delete(ctx, p1.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExplicitRepoPermissions.GetByID(ctx, p1.ID); err != ErrExplicitRepoPermissionsNotFound {
+		t.Errorf("got error %v, want %v", err, ErrExplicitRepoPermissionsNotFound)
+	}
+	if err := ExplicitRepoPermissions.Delete(ctx, p1.ID); err != ErrExplicitRepoPermissionsNotFound {
+		t.Errorf("got error %v, want %v", err, ErrExplicitRepoPermissionsNotFound)
+	}
+}
@@ -27,8 +27,9 @@ import (
 // The enterprise code registers additional validators at run-time and sets the
 // global instance in stores.go
 type ExternalServicesStore struct {
-	GitHubValidators []func(*schema.GitHubConnection) error
-	GitLabValidators []func(*schema.GitLabConnection, []schema.AuthProviders) error
+	GitHubValidators          []func(*schema.GitHubConnection) error
+	GitLabValidators          []func(*schema.GitLabConnection, []schema.AuthProviders) error
+	BitbucketServerValidators []func(*schema.BitbucketServerConnection, []schema.AuthProviders) error
 }
 
 // ExternalServiceKinds contains a map of all supported kinds of
@@ -40,6 +41,7 @@ var ExternalServiceKinds = map[string]ExternalServiceKind{
 	"GITLAB":          {CodeHost: true, JSONSchema: schema.GitLabSchemaJSON},
 	"GITOLITE":        {CodeHost: true, JSONSchema: schema.GitoliteSchemaJSON},
 	"PHABRICATOR":     {CodeHost: true, JSONSchema: schema.PhabricatorSchemaJSON},
+	"PLUGIN":          {CodeHost: true, JSONSchema: schema.PluginSchemaJSON},
 	"OTHER":           {CodeHost: true, JSONSchema: schema.OtherExternalServiceSchemaJSON},
 }
 
@@ -86,6 +88,11 @@ func (e *ExternalServicesStore) ValidateConfig(kind, config string, ps []schema.
 		return errors.Wrapf(err, "failed to compile schema for external service of kind %q", kind)
 	}
 
+	// Secret references (like "$env:SRC_SECRET_GITHUB_TOKEN") are validated, not the values they
+	// refer to, because they may only be resolvable by the services that use them.
+	if err := conf.ValidateSecretReferences(config); err != nil {
+		return err
+	}
 	normalized, err := jsonc.Parse(config)
 	if err != nil {
 		return errors.Wrapf(err, "failed to normalize JSON")
@@ -126,6 +133,13 @@ func (e *ExternalServicesStore) ValidateConfig(kind, config string, ps []schema.
 		}
 		err = e.validateGitlabConnection(&c, ps)
 
+	case "BITBUCKETSERVER":
+		var c schema.BitbucketServerConnection
+		if err = json.Unmarshal(normalized, &c); err != nil {
+			return err
+		}
+		err = e.validateBitbucketServerConnection(&c, ps)
+
 	case "OTHER":
 		var c schema.OtherExternalServiceConnection
 		if err = json.Unmarshal(normalized, &c); err != nil {
@@ -182,6 +196,14 @@ func (e *ExternalServicesStore) validateGitlabConnection(c *schema.GitLabConnect
 	return err.ErrorOrNil()
 }
 
+func (e *ExternalServicesStore) validateBitbucketServerConnection(c *schema.BitbucketServerConnection, ps []schema.AuthProviders) error {
+	err := new(multierror.Error)
+	for _, validate := range e.BitbucketServerValidators {
+		err = multierror.Append(err, validate(c, ps))
+	}
+	return err.ErrorOrNil()
+}
+
 // Create creates a external service.
 //
 // 🚨 SECURITY: The caller must ensure that the actor is a site admin.
@@ -322,11 +344,16 @@ func (c *ExternalServicesStore) listConfigs(ctx context.Context, kind string, re
 		return err
 	}
 
-	// Decode the jsonc configs into Go objects.
+	// Decode the jsonc configs into Go objects, resolving secret references (like
+	// "$env:SRC_SECRET_GITHUB_TOKEN").
 	var cfgs []interface{}
 	for _, service := range services {
+		config, err := conf.ResolveSecrets(service.Config)
+		if err != nil {
+			return err
+		}
 		var cfg interface{}
-		if err := jsonc.Unmarshal(service.Config, &cfg); err != nil {
+		if err := jsonc.Unmarshal(config, &cfg); err != nil {
 			return err
 		}
 		cfgs = append(cfgs, cfg)
@@ -5,10 +5,15 @@ var Mocks MockStores
 // MockStores has a field for each store interface with the concrete mock type (to obviate the need for tedious type assertions in test code).
 type MockStores struct {
 	AccessTokens MockAccessTokens
+	AuditLog     MockAuditLog
 
-	DiscussionThreads         MockDiscussionThreads
-	DiscussionComments        MockDiscussionComments
-	DiscussionMailReplyTokens MockDiscussionMailReplyTokens
+	DiscussionThreads             MockDiscussionThreads
+	DiscussionComments            MockDiscussionComments
+	DiscussionMailReplyTokens     MockDiscussionMailReplyTokens
+	DiscussionMailNewThreadTokens MockDiscussionMailNewThreadTokens
+	DiscussionThreadSubscriptions MockDiscussionThreadSubscriptions
+	DiscussionCommentReactions    MockDiscussionCommentReactions
+	DiscussionImportedComments    MockDiscussionImportedComments
 
 	Repos      MockRepos
 	Orgs       MockOrgs
@@ -17,6 +22,9 @@ type MockStores struct {
 	Users      MockUsers
 	UserEmails MockUserEmails
 
+	UserPermissions         MockUserPermissions
+	ExplicitRepoPermissions MockExplicitRepoPermissions
+
 	Phabricator MockPhabricator
 
 	ExternalAccounts MockExternalAccounts
@@ -24,4 +32,13 @@ type MockStores struct {
 	OrgInvitations MockOrgInvitations
 
 	ExternalServices MockExternalServices
+
+	Webhooks          MockWebhooks
+	WebhookDeliveries MockWebhookDeliveries
+
+	SavedSearchMailReplyTokens MockSavedSearchMailReplyTokens
+	SavedSearchEmailMutes      MockSavedSearchEmailMutes
+	SavedSearchDigests         MockSavedSearchDigests
+
+	EmailOutbox MockEmailOutbox
 }
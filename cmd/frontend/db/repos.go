@@ -65,6 +65,9 @@ func (s *repos) Get(ctx context.Context, id api.RepoID) (*types.Repo, error) {
 // error. If the repo doesn't exist in the DB, then errcode.IsNotFound will
 // return true on the error returned. It does not attempt to look up or update
 // the repository on any external service (such as its code host).
+//
+// If no repository has the given name but a repository was renamed from it, the
+// renamed repository is returned. Callers can detect this by comparing names.
 func (s *repos) GetByName(ctx context.Context, name api.RepoName) (*types.Repo, error) {
 	if Mocks.Repos.GetByName != nil {
 		return Mocks.Repos.GetByName(ctx, name)
@@ -75,6 +78,13 @@ func (s *repos) GetByName(ctx context.Context, name api.RepoName) (*types.Repo,
 		return nil, err
 	}
 
+	if len(repos) == 0 {
+		repos, err = s.getBySQL(ctx, sqlf.Sprintf("id=(SELECT repo_id FROM repo_redirects WHERE name=%s) LIMIT 1", name))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if len(repos) == 0 {
 		return nil, &repoNotFoundErr{Name: name}
 	}
@@ -669,6 +679,8 @@ func (s *repos) Upsert(ctx context.Context, op api.InsertRepoOp) error {
 			return err
 		}
 		insert = true // missing
+	} else if !strings.EqualFold(string(r.Name), string(op.Name)) {
+		insert = true // op.Name is the previous name of a renamed repo
 	} else {
 		enabled = r.Enabled
 		language = r.Language
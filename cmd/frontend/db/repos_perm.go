@@ -7,7 +7,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/actor"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+	"github.com/sourcegraph/sourcegraph/pkg/ratelimit"
 	log15 "gopkg.in/inconshreveable/log15.v2"
 )
 
@@ -28,6 +30,14 @@ func authzFilter(ctx context.Context, repos []*types.Repo, p authz.Perm) ([]*typ
 		return repos, nil
 	}
 
+	// 🚨 SECURITY: Access tokens with repository name pattern scopes only grant access to the
+	// matching repositories, even if their subject is a site admin.
+	if scopes := actor.FromContext(ctx).Scopes; scopes != nil {
+		if repos = authz.FilterReposByScopes(scopes, repos); len(repos) == 0 {
+			return repos, nil
+		}
+	}
+
 	var currentUser *types.User
 	if actor.FromContext(ctx).IsAuthenticated() {
 		var err error
@@ -64,14 +74,7 @@ func isInternalActor(ctx context.Context) bool {
 }
 
 func getFilteredRepoNames(ctx context.Context, currentUser *types.User, repos map[authz.Repo]struct{}, p authz.Perm) (accepted map[api.RepoName]struct{}, err error) {
-	var accts []*extsvc.ExternalAccount
 	authzAllowByDefault, authzProviders := authz.GetProviders()
-	if len(authzProviders) > 0 && currentUser != nil {
-		accts, err = ExternalAccounts.List(ctx, ExternalAccountsListOptions{UserID: currentUser.ID})
-		if err != nil {
-			return nil, err
-		}
-	}
 
 	accepted = make(map[api.RepoName]struct{})  // repositories that have been claimed and have read permissions
 	unverified := make(map[authz.Repo]struct{}) // repositories that have not been claimed by any authz provider
@@ -84,6 +87,33 @@ func getFilteredRepoNames(ctx context.Context, currentUser *types.User, repos ma
 		unverified[repo] = struct{}{}
 	}
 
+	// If permissions are synced in the background and the user's permissions have been synced from
+	// all authz providers, use the stored permissions. Only the repos that were added since the
+	// last sync need to be checked with the authz providers.
+	if len(authzProviders) > 0 && len(unverified) > 0 && currentUser != nil && conf.PermissionsBackgroundSyncEnabled() {
+		stored, unsynced, ok, err := UserPermissions.filter(ctx, currentUser.ID, p, authzAllowByDefault, authzProviders, unverified)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			accepted, unverified = stored, unsynced
+		} else {
+			authz.RequestPermissionsSync(currentUser.ID)
+		}
+	}
+
+	var accts []*extsvc.ExternalAccount
+	if len(authzProviders) > 0 && len(unverified) > 0 && currentUser != nil {
+		accts, err = ExternalAccounts.List(ctx, ExternalAccountsListOptions{UserID: currentUser.ID})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Permissions are checked while a user waits on a request, so the authz providers' requests to
+	// the code hosts take priority over background requests (such as permissions syncing).
+	ctx = ratelimit.WithPriority(ctx, ratelimit.PriorityInteractive)
+
 	// Walk through all authz providers, checking repo permissions against each. If any own a given
 	// repo, we use its permissions for that repo.
 	for _, authzProvider := range authzProviders {
@@ -91,31 +121,14 @@ func getFilteredRepoNames(ctx context.Context, currentUser *types.User, repos ma
 			break
 		}
 
-		// determine external account to use
-		var providerAcct *extsvc.ExternalAccount
-		for _, acct := range accts {
-			if acct.ServiceID == authzProvider.ServiceID() && acct.ServiceType == authzProvider.ServiceType() {
-				providerAcct = acct
-				break
-			}
-		}
-		if providerAcct == nil && currentUser != nil { // no existing external account for authz provider
-			if pr, err := authzProvider.FetchAccount(ctx, currentUser, accts); err == nil {
-				providerAcct = pr
-				if providerAcct != nil {
-					err := ExternalAccounts.AssociateUserAndSave(ctx, currentUser.ID, providerAcct.ExternalAccountSpec, providerAcct.ExternalAccountData)
-					if err != nil {
-						return nil, err
-					}
-				}
-			} else {
-				log15.Warn("Could not fetch authz provider account for user", "username", currentUser.Username, "authzProvider", authzProvider.ServiceID(), "error", err)
-			}
-		}
-
 		// determine which repos "belong" to this authz provider
 		myUnverified, nextUnverified := authzProvider.Repos(ctx, unverified)
 
+		providerAcct, err := authzProviderAccount(ctx, currentUser, accts, authzProvider)
+		if err != nil {
+			return nil, err
+		}
+
 		// check the perms on those repos
 		perms, err := authzProvider.RepoPerms(ctx, providerAcct, myUnverified)
 		if err != nil {
@@ -138,3 +151,30 @@ func getFilteredRepoNames(ctx context.Context, currentUser *types.User, repos ma
 
 	return accepted, nil
 }
+
+// authzProviderAccount returns the user's external account that identifies the user to the authz
+// provider, or nil if there is none. If the user has no such external account yet, it asks the authz
+// provider to compute one and saves it (unless the authz provider's accounts are transient).
+func authzProviderAccount(ctx context.Context, user *types.User, accts []*extsvc.ExternalAccount, authzProvider authz.Provider) (*extsvc.ExternalAccount, error) {
+	for _, acct := range accts {
+		if acct.ServiceID == authzProvider.ServiceID() && acct.ServiceType == authzProvider.ServiceType() {
+			return acct, nil
+		}
+	}
+	if user == nil {
+		return nil, nil
+	}
+
+	// no existing external account for authz provider
+	providerAcct, err := authzProvider.FetchAccount(ctx, user, accts)
+	if err != nil {
+		log15.Warn("Could not fetch authz provider account for user", "username", user.Username, "authzProvider", authzProvider.ServiceID(), "error", err)
+		return nil, nil
+	}
+	if _, transient := authzProvider.(authz.TransientAccountsProvider); providerAcct != nil && !transient {
+		if err := ExternalAccounts.AssociateUserAndSave(ctx, user.ID, providerAcct.ExternalAccountSpec, providerAcct.ExternalAccountData); err != nil {
+			return nil, err
+		}
+	}
+	return providerAcct, nil
+}
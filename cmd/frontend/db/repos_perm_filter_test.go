@@ -647,6 +647,46 @@ func Test_authzFilter_createsNewUsers(t *testing.T) {
 	}
 }
 
+type mockTransientAuthzProvider struct{ *MockAuthzProvider }
+
+func (mockTransientAuthzProvider) TransientAccounts() {}
+
+func Test_authzFilter_transientAccounts(t *testing.T) {
+	Mocks.ExternalAccounts.AssociateUserAndSave = func(userID int32, spec extsvc.ExternalAccountSpec, data extsvc.ExternalAccountData) error {
+		t.Errorf("got AssociateUserAndSave(%d, %+v), want transient accounts not to be saved", userID, spec)
+		return nil
+	}
+	Mocks.ExternalAccounts.List = func(op ExternalAccountsListOptions) ([]*extsvc.ExternalAccount, error) {
+		return []*extsvc.ExternalAccount{acct(23, "okta", "https://okta.mine/", "101")}, nil
+	}
+	Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: actor.FromContext(ctx).UID}, nil
+	}
+	authz.SetProviders(false, []authz.Provider{
+		mockTransientAuthzProvider{&MockAuthzProvider{
+			serviceID:    "https://explicit.mine/",
+			serviceType:  "explicit",
+			okServiceIDs: map[string]struct{}{"https://okta.mine/": {}},
+			repos:        map[api.RepoName]struct{}{"r": {}},
+			perms: map[extsvc.ExternalAccount]map[api.RepoName]map[authz.Perm]bool{
+				*acct(23, "explicit", "https://explicit.mine/", "101"): {"r": {authz.Read: true}},
+			},
+		}},
+	})
+	defer authz.SetProviders(true, nil)
+
+	// The account is computed (and not saved) each time, and it is used to check permissions.
+	for i := 0; i < 2; i++ {
+		filtered, err := authzFilter(actor.WithActor(context.Background(), &actor.Actor{UID: 23}), []*types.Repo{makeRepo("r", 1)}, authz.Read)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := sortedRepoNames(filtered), []api.RepoName{"r"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got repos %v, want %v", got, want)
+		}
+	}
+}
+
 func acct(userID int32, serviceType, serviceID, accountID string) *extsvc.ExternalAccount {
 	return &extsvc.ExternalAccount{
 		UserID: userID,
@@ -186,12 +186,13 @@ func TestRepos_Upsert(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if _, err := Repos.GetByName(ctx, "myrepo"); !errcode.IsNotFound(err) {
-		if err == nil {
-			t.Fatal("myrepo should be renamed, but still present as myrepo")
-		} else {
-			t.Fatal(err)
-		}
+	// The previous name redirects to the renamed repo.
+	rp, err = Repos.GetByName(ctx, "myrepo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rp.Name != "myrepo/renamed" {
+		t.Fatalf("rp.Name: %s != %s", rp.Name, "myrepo/renamed")
 	}
 
 	rp, err = Repos.GetByName(ctx, "myrepo/renamed")
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// savedSearchDigests provides access to the `saved_search_digests` table.
+//
+// query-runner saves the pending digest email of each user who receives
+// saved search digests, so that new results that haven't been sent yet aren't
+// lost when it restarts.
+//
+// For a detailed overview of the schema, see schema.md.
+type savedSearchDigests struct{}
+
+// SavedSearchDigest is a user's pending saved search digest email.
+type SavedSearchDigest struct {
+	UserID   int32
+	DueAt    time.Time
+	Contents string // JSON, opaque to the frontend
+}
+
+// Save creates or replaces the user's pending digest.
+func (*savedSearchDigests) Save(ctx context.Context, d *SavedSearchDigest) error {
+	if Mocks.SavedSearchDigests.Save != nil {
+		return Mocks.SavedSearchDigests.Save(d)
+	}
+	_, err := dbconn.Global.ExecContext(ctx, `
+INSERT INTO saved_search_digests(user_id, due_at, contents) VALUES($1, $2, $3)
+ON CONFLICT (user_id) DO UPDATE SET due_at=excluded.due_at, contents=excluded.contents, updated_at=now()`,
+		d.UserID, d.DueAt, d.Contents,
+	)
+	return err
+}
+
+// Delete deletes the user's pending digest if it is due at dueAt. A digest with
+// a different due time is a newer digest, which is kept.
+func (*savedSearchDigests) Delete(ctx context.Context, userID int32, dueAt time.Time) error {
+	if Mocks.SavedSearchDigests.Delete != nil {
+		return Mocks.SavedSearchDigests.Delete(userID, dueAt)
+	}
+	_, err := dbconn.Global.ExecContext(ctx, "DELETE FROM saved_search_digests WHERE user_id=$1 AND due_at=$2", userID, dueAt)
+	return err
+}
+
+// List lists all pending digests, ordered by user ID.
+func (*savedSearchDigests) List(ctx context.Context) ([]*SavedSearchDigest, error) {
+	if Mocks.SavedSearchDigests.List != nil {
+		return Mocks.SavedSearchDigests.List()
+	}
+	rows, err := dbconn.Global.QueryContext(ctx, "SELECT user_id, due_at, contents FROM saved_search_digests ORDER BY user_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var digests []*SavedSearchDigest
+	for rows.Next() {
+		var d SavedSearchDigest
+		if err := rows.Scan(&d.UserID, &d.DueAt, &d.Contents); err != nil {
+			return nil, err
+		}
+		digests = append(digests, &d)
+	}
+	return digests, rows.Err()
+}
@@ -0,0 +1,9 @@
+package db
+
+import "time"
+
+type MockSavedSearchDigests struct {
+	Save   func(d *SavedSearchDigest) error
+	Delete func(userID int32, dueAt time.Time) error
+	List   func() ([]*SavedSearchDigest, error)
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+)
+
+func TestSavedSearchDigests(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	var userIDs []int32
+	for _, username := range []string{"u1", "u2"} {
+		user, err := Users.Create(ctx, NewUser{Username: username})
+		if err != nil {
+			t.Fatal(err)
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+
+	due := time.Date(2019, 1, 31, 14, 0, 0, 0, time.UTC)
+	later := due.Add(time.Hour)
+	if err := SavedSearchDigests.Save(ctx, &SavedSearchDigest{UserID: userIDs[1], DueAt: due, Contents: `{"entries": []}`}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SavedSearchDigests.Save(ctx, &SavedSearchDigest{UserID: userIDs[0], DueAt: due, Contents: `{"entries": []}`}); err != nil {
+		t.Fatal(err)
+	}
+	// Saving again replaces the user's digest.
+	if err := SavedSearchDigests.Save(ctx, &SavedSearchDigest{UserID: userIDs[0], DueAt: later, Contents: `{"entries": [{}]}`}); err != nil {
+		t.Fatal(err)
+	}
+
+	digests, err := SavedSearchDigests.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(digests) != 2 {
+		t.Fatalf("got %d digests, want 2", len(digests))
+	}
+	if d := digests[0]; d.UserID != userIDs[0] || !d.DueAt.Equal(later) || d.Contents != `{"entries": [{}]}` {
+		t.Errorf("got digest %+v, want user %d's replaced digest", d, userIDs[0])
+	}
+	if d := digests[1]; d.UserID != userIDs[1] || !d.DueAt.Equal(due) {
+		t.Errorf("got digest %+v, want user %d's digest", d, userIDs[1])
+	}
+
+	// Deleting a digest with a different due time (i.e., an older digest) keeps the newer digest.
+	if err := SavedSearchDigests.Delete(ctx, userIDs[0], due); err != nil {
+		t.Fatal(err)
+	}
+	if err := SavedSearchDigests.Delete(ctx, userIDs[1], due); err != nil {
+		t.Fatal(err)
+	}
+	digests, err = SavedSearchDigests.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(digests) != 1 || digests[0].UserID != userIDs[0] {
+		t.Errorf("got digests %+v, want only user %d's digest", digests, userIDs[0])
+	}
+}
@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// savedSearchEmailMutes provides access to the `saved_search_email_mutes` table.
+//
+// A user who mutes a saved search doesn't receive email notifications for it
+// until the mute expires. A user who unsubscribes from a saved search is muted
+// indefinitely.
+//
+// For a detailed overview of the schema, see schema.md.
+type savedSearchEmailMutes struct{}
+
+// Mute mutes email notifications of the saved search (identified by its
+// settings subject and key) for the user until the given time. If until is
+// nil, the user is unsubscribed (muted indefinitely).
+func (*savedSearchEmailMutes) Mute(ctx context.Context, userID int32, subject, savedQueryKey string, until *time.Time) error {
+	if Mocks.SavedSearchEmailMutes.Mute != nil {
+		return Mocks.SavedSearchEmailMutes.Mute(userID, subject, savedQueryKey, until)
+	}
+	_, err := dbconn.Global.ExecContext(ctx, `
+INSERT INTO saved_search_email_mutes(user_id, subject, saved_query_key, muted_until) VALUES($1, $2, $3, $4)
+ON CONFLICT (user_id, subject, saved_query_key) DO UPDATE SET muted_until=excluded.muted_until, created_at=now()`,
+		userID, subject, savedQueryKey, until,
+	)
+	return err
+}
+
+// Unmute resumes email notifications of the saved search for the user.
+func (*savedSearchEmailMutes) Unmute(ctx context.Context, userID int32, subject, savedQueryKey string) error {
+	if Mocks.SavedSearchEmailMutes.Unmute != nil {
+		return Mocks.SavedSearchEmailMutes.Unmute(userID, subject, savedQueryKey)
+	}
+	_, err := dbconn.Global.ExecContext(ctx, "DELETE FROM saved_search_email_mutes WHERE user_id=$1 AND subject=$2 AND saved_query_key=$3", userID, subject, savedQueryKey)
+	return err
+}
+
+// ListMutedUsers lists the users who have currently muted (or unsubscribed from)
+// email notifications of the saved search.
+func (*savedSearchEmailMutes) ListMutedUsers(ctx context.Context, subject, savedQueryKey string) ([]int32, error) {
+	if Mocks.SavedSearchEmailMutes.ListMutedUsers != nil {
+		return Mocks.SavedSearchEmailMutes.ListMutedUsers(subject, savedQueryKey)
+	}
+	rows, err := dbconn.Global.QueryContext(ctx, "SELECT user_id FROM saved_search_email_mutes WHERE subject=$1 AND saved_query_key=$2 AND (muted_until IS NULL OR muted_until > now()) ORDER BY user_id", subject, savedQueryKey)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var userIDs []int32
+	for rows.Next() {
+		var userID int32
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+	return userIDs, rows.Err()
+}
@@ -0,0 +1,9 @@
+package db
+
+import "time"
+
+type MockSavedSearchEmailMutes struct {
+	Mute           func(userID int32, subject, savedQueryKey string, until *time.Time) error
+	Unmute         func(userID int32, subject, savedQueryKey string) error
+	ListMutedUsers func(subject, savedQueryKey string) ([]int32, error)
+}
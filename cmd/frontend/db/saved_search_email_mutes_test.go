@@ -0,0 +1,92 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+)
+
+func TestSavedSearchEmailMutes(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	var userIDs []int32
+	for _, username := range []string{"u1", "u2", "u3"} {
+		user, err := Users.Create(ctx, NewUser{Username: username})
+		if err != nil {
+			t.Fatal(err)
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+
+	const subject, key = "org 1", "k"
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	if err := SavedSearchEmailMutes.Mute(ctx, userIDs[0], subject, key, &future); err != nil {
+		t.Fatal(err)
+	}
+	if err := SavedSearchEmailMutes.Mute(ctx, userIDs[1], subject, key, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := SavedSearchEmailMutes.Mute(ctx, userIDs[2], subject, key, &past); err != nil {
+		t.Fatal(err)
+	}
+	if err := SavedSearchEmailMutes.Mute(ctx, userIDs[2], subject, "other", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Expired mutes and mutes of other saved searches are not listed.
+	if muted, err := SavedSearchEmailMutes.ListMutedUsers(ctx, subject, key); err != nil {
+		t.Fatal(err)
+	} else if want := userIDs[:2]; !reflect.DeepEqual(muted, want) {
+		t.Errorf("got muted users %v, want %v", muted, want)
+	}
+
+	// Muting again replaces the previous mute.
+	if err := SavedSearchEmailMutes.Mute(ctx, userIDs[2], subject, key, &future); err != nil {
+		t.Fatal(err)
+	}
+	if err := SavedSearchEmailMutes.Unmute(ctx, userIDs[0], subject, key); err != nil {
+		t.Fatal(err)
+	}
+	if muted, err := SavedSearchEmailMutes.ListMutedUsers(ctx, subject, key); err != nil {
+		t.Fatal(err)
+	} else if want := userIDs[1:]; !reflect.DeepEqual(muted, want) {
+		t.Errorf("got muted users %v, want %v", muted, want)
+	}
+}
+
+func TestSavedSearchMailReplyTokens(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	user, err := Users.Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := SavedSearchMailReplyToken{UserID: user.ID, Subject: "user 1", SavedQueryKey: "k"}
+	token, err := SavedSearchMailReplyTokens.Generate(ctx, want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token2, err := SavedSearchMailReplyTokens.Generate(ctx, want); err != nil {
+		t.Fatal(err)
+	} else if token2 != token {
+		t.Errorf("got new token %q, want existing token %q", token2, token)
+	}
+
+	if got, err := SavedSearchMailReplyTokens.Get(ctx, token); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(*got, want) {
+		t.Errorf("got %+v, want %+v", *got, want)
+	}
+	if _, err := SavedSearchMailReplyTokens.Get(ctx, SavedSearchMailReplyTokenPrefix+"invalid"); err != ErrInvalidToken {
+		t.Errorf("got error %v, want ErrInvalidToken", err)
+	}
+}
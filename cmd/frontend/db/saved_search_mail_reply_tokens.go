@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// savedSearchMailReplyTokens provides access to the `saved_search_mail_reply_tokens` table.
+//
+// For a detailed overview of the schema, see schema.md.
+type savedSearchMailReplyTokens struct{}
+
+// SavedSearchMailReplyTokenPrefix is the prefix of all tokens generated by
+// SavedSearchMailReplyTokens.Generate. It distinguishes them from the other
+// kinds of mail tokens.
+const SavedSearchMailReplyTokenPrefix = "s-"
+
+// SavedSearchMailReplyToken describes the user and saved search that a
+// SavedSearchMailReplyTokens token grants access to.
+type SavedSearchMailReplyToken struct {
+	UserID        int32
+	Subject       string // the saved search's settings subject (api.SettingsSubject.String())
+	SavedQueryKey string // the saved search's key
+}
+
+// Generate gets the existing token, or generates a new one, for giving the
+// specified user access to change their notifications for the specified saved
+// search through only the token.
+//
+// 🚨 SECURITY: The caller must ensure the token is ONLY given to the user that
+// is passed to this method. Anyone with the token can change the user's
+// notifications for the saved search, at ANY point in the future.
+func (*savedSearchMailReplyTokens) Generate(ctx context.Context, t SavedSearchMailReplyToken) (string, error) {
+	if Mocks.SavedSearchMailReplyTokens.Generate != nil {
+		return Mocks.SavedSearchMailReplyTokens.Generate(ctx, t)
+	}
+
+	// Check if there already exists a token for this user and saved search. If
+	// there is, we do not need to store a new one.
+	var token string
+	err := dbconn.Global.QueryRowContext(ctx, "SELECT token FROM saved_search_mail_reply_tokens WHERE user_id=$1 AND subject=$2 AND saved_query_key=$3 AND deleted_at IS NULL", t.UserID, t.Subject, t.SavedQueryKey).Scan(&token)
+	if err != nil && err != sql.ErrNoRows {
+		return "", err
+	}
+	if err == nil {
+		return token, nil // use the existing token
+	}
+
+	token, err = newMailToken(SavedSearchMailReplyTokenPrefix)
+	if err != nil {
+		return "", err
+	}
+	_, err = dbconn.Global.ExecContext(ctx, "INSERT INTO saved_search_mail_reply_tokens(token, user_id, subject, saved_query_key) VALUES($1, $2, $3, $4)", token, t.UserID, t.Subject, t.SavedQueryKey)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Get returns the user and saved search found for the given token. If there is
+// none, the token is invalid and ErrInvalidToken is returned.
+func (*savedSearchMailReplyTokens) Get(ctx context.Context, token string) (*SavedSearchMailReplyToken, error) {
+	if Mocks.SavedSearchMailReplyTokens.Get != nil {
+		return Mocks.SavedSearchMailReplyTokens.Get(ctx, token)
+	}
+	var t SavedSearchMailReplyToken
+	err := dbconn.Global.QueryRowContext(ctx, "SELECT user_id, subject, saved_query_key FROM saved_search_mail_reply_tokens WHERE token=$1 AND deleted_at IS NULL", token).Scan(
+		&t.UserID,
+		&t.Subject,
+		&t.SavedQueryKey,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+	return &t, nil
+}
@@ -0,0 +1,8 @@
+package db
+
+import "context"
+
+type MockSavedSearchMailReplyTokens struct {
+	Generate func(ctx context.Context, t SavedSearchMailReplyToken) (string, error)
+	Get      func(ctx context.Context, token string) (*SavedSearchMailReplyToken, error)
+}
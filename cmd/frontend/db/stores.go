@@ -1,20 +1,33 @@
 package db
 
 var (
-	AccessTokens              = &accessTokens{}
-	ExternalServices          = &ExternalServicesStore{}
-	DiscussionThreads         = &discussionThreads{}
-	DiscussionComments        = &discussionComments{}
-	DiscussionMailReplyTokens = &discussionMailReplyTokens{}
-	Repos                     = &repos{}
-	Phabricator               = &phabricator{}
-	SavedQueries              = &savedQueries{}
-	Orgs                      = &orgs{}
-	OrgMembers                = &orgMembers{}
-	RecentSearches            = &recentSearches{}
-	Settings                  = &settings{}
-	Users                     = &users{}
-	UserEmails                = &userEmails{}
+	AccessTokens                  = &accessTokens{}
+	AuditLog                      = &auditLog{}
+	EmailOutbox                   = &emailOutbox{}
+	ExternalServices              = &ExternalServicesStore{}
+	ExplicitRepoPermissions       = &explicitRepoPermissions{}
+	DiscussionThreads             = &discussionThreads{}
+	DiscussionComments            = &discussionComments{}
+	DiscussionMailReplyTokens     = &discussionMailReplyTokens{}
+	DiscussionMailNewThreadTokens = &discussionMailNewThreadTokens{}
+	DiscussionThreadSubscriptions = &discussionThreadSubscriptions{}
+	DiscussionCommentReactions    = &discussionCommentReactions{}
+	DiscussionImportedComments    = &discussionImportedComments{}
+	Repos                         = &repos{}
+	Phabricator                   = &phabricator{}
+	SavedQueries                  = &savedQueries{}
+	SavedSearchMailReplyTokens    = &savedSearchMailReplyTokens{}
+	SavedSearchEmailMutes         = &savedSearchEmailMutes{}
+	SavedSearchDigests            = &savedSearchDigests{}
+	Orgs                          = &orgs{}
+	OrgMembers                    = &orgMembers{}
+	RecentSearches                = &recentSearches{}
+	Settings                      = &settings{}
+	Users                         = &users{}
+	UserEmails                    = &userEmails{}
+	UserPermissions               = &userPermissions{}
+	Webhooks                      = &webhooks{}
+	WebhookDeliveries             = &webhookDeliveries{}
 
 	SurveyResponses = &surveyResponses{}
 
@@ -19,6 +19,8 @@ type UserEmail struct {
 	CreatedAt        time.Time
 	VerificationCode *string
 	VerifiedAt       *time.Time
+	BouncedAt        *time.Time // the last time an email to this address bounced
+	BounceReason     *string    // why the last email to this address bounced
 }
 
 // userEmailNotFoundError is the error that is returned when a user email is not found.
@@ -149,11 +151,22 @@ func (*userEmails) SetVerified(ctx context.Context, userID int32, email string,
 	return nil
 }
 
+// RecordBounce records that an email to the address bounced (i.e., was permanently rejected by the
+// recipient's mail server) for the given reason. It is recorded for every user with the address.
+func (*userEmails) RecordBounce(ctx context.Context, email, reason string) error {
+	if Mocks.UserEmails.RecordBounce != nil {
+		return Mocks.UserEmails.RecordBounce(email, reason)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx, "UPDATE user_emails SET bounced_at=now(), bounce_reason=$2 WHERE email=$1", email, reason)
+	return err
+}
+
 // getBySQL returns user emails matching the SQL query, if any exist.
 func (*userEmails) getBySQL(ctx context.Context, query string, args ...interface{}) ([]*UserEmail, error) {
 	rows, err := dbconn.Global.QueryContext(ctx,
 		`SELECT user_emails.user_id, user_emails.email, user_emails.created_at, user_emails.verification_code,
-				user_emails.verified_at FROM user_emails `+query, args...)
+				user_emails.verified_at, user_emails.bounced_at, user_emails.bounce_reason FROM user_emails `+query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -162,7 +175,7 @@ func (*userEmails) getBySQL(ctx context.Context, query string, args ...interface
 	defer rows.Close()
 	for rows.Next() {
 		var v UserEmail
-		err := rows.Scan(&v.UserID, &v.Email, &v.CreatedAt, &v.VerificationCode, &v.VerifiedAt)
+		err := rows.Scan(&v.UserID, &v.Email, &v.CreatedAt, &v.VerificationCode, &v.VerifiedAt, &v.BouncedAt, &v.BounceReason)
 		if err != nil {
 			return nil, err
 		}
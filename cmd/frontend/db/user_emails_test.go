@@ -265,6 +265,35 @@ func TestUserEmails_SetVerified(t *testing.T) {
 	}
 }
 
+func TestUserEmails_RecordBounce(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	user, err := Users.Create(ctx, NewUser{
+		Email:                 "a@example.com",
+		Username:              "u2",
+		Password:              "pw",
+		EmailVerificationCode: "c",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Addresses are compared case-insensitively.
+	if err := UserEmails.RecordBounce(ctx, "A@example.com", "550 mailbox unavailable"); err != nil {
+		t.Fatal(err)
+	}
+	userEmails, err := UserEmails.ListByUser(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(userEmails) != 1 || userEmails[0].BouncedAt == nil || userEmails[0].BounceReason == nil || *userEmails[0].BounceReason != "550 mailbox unavailable" {
+		t.Errorf("got %s, want bounce recorded", toJSON(userEmails))
+	}
+}
+
 func isUserEmailVerified(ctx context.Context, userID int32, email string) (bool, error) {
 	userEmails, err := UserEmails.ListByUser(ctx, userID)
 	if err != nil {
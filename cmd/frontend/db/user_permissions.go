@@ -0,0 +1,306 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/pkg/ratelimit"
+)
+
+// UserPermission is the set of repositories on which a user has a permission, as determined by an
+// authz provider. It is synced from the authz provider in the background.
+//
+// The repositories that no authz provider claimed when the permissions were synced are stored as a
+// UserPermission with an empty ProviderServiceType and ProviderServiceID. They are accessible only
+// if the authz providers allow access by default.
+type UserPermission struct {
+	UserID              int32
+	Perm                authz.Perm
+	ProviderServiceType string // the authz provider's ServiceType()
+	ProviderServiceID   string // the authz provider's ServiceID()
+	RepoIDs             []api.RepoID
+	UpdatedAt           time.Time // the time as of which the permissions are current
+}
+
+// permissionsSyncRetryInterval is how long after a failed sync a user's permissions are synced
+// again. It doubles after each consecutive failure (up to the sync interval).
+const permissionsSyncRetryInterval = 5 * time.Minute
+
+// userPermissions provides access to the `user_permissions` and `user_permissions_sync` tables.
+type userPermissions struct{}
+
+// SetAll stores the user's permissions from all authz providers, replacing all of the user's
+// previously stored permissions (including those from authz providers that are no longer
+// configured).
+func (*userPermissions) SetAll(ctx context.Context, userID int32, perm authz.Perm, ps []*UserPermission) (err error) {
+	if Mocks.UserPermissions.SetAll != nil {
+		return Mocks.UserPermissions.SetAll(userID, perm, ps)
+	}
+
+	tx, err := dbconn.Global.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			rollErr := tx.Rollback()
+			if rollErr != nil {
+				err = multierror.Append(err, rollErr)
+			}
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_permissions WHERE user_id=$1 AND permission=$2", userID, perm); err != nil {
+		return err
+	}
+	for _, p := range ps {
+		repoIDs := make([]int64, len(p.RepoIDs))
+		for i, id := range p.RepoIDs {
+			repoIDs[i] = int64(id)
+		}
+		if _, err := tx.ExecContext(ctx, `
+INSERT INTO user_permissions(user_id, permission, provider_service_type, provider_service_id, repo_ids, updated_at)
+VALUES($1, $2, $3, $4, $5, $6)`,
+			userID, perm, p.ProviderServiceType, p.ProviderServiceID, pq.Array(repoIDs), p.UpdatedAt,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get returns the user's stored permissions from the authz provider, or nil if they haven't been
+// synced.
+func (*userPermissions) Get(ctx context.Context, userID int32, perm authz.Perm, providerServiceType, providerServiceID string) (*UserPermission, error) {
+	p := UserPermission{UserID: userID, Perm: perm, ProviderServiceType: providerServiceType, ProviderServiceID: providerServiceID}
+	var repoIDs []int64
+	err := dbconn.Global.QueryRowContext(ctx, `
+SELECT repo_ids, updated_at FROM user_permissions
+WHERE user_id=$1 AND permission=$2 AND provider_service_type=$3 AND provider_service_id=$4`,
+		userID, perm, providerServiceType, providerServiceID,
+	).Scan(pq.Array(&repoIDs), &p.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.RepoIDs = make([]api.RepoID, len(repoIDs))
+	for i, id := range repoIDs {
+		p.RepoIDs[i] = api.RepoID(id)
+	}
+	return &p, nil
+}
+
+// RecordSyncAttempt records an attempt to sync the user's permissions, which determines when they
+// are next synced (see ListUsersToSync).
+func (*userPermissions) RecordSyncAttempt(ctx context.Context, userID int32, failed bool) error {
+	if Mocks.UserPermissions.RecordSyncAttempt != nil {
+		return Mocks.UserPermissions.RecordSyncAttempt(userID, failed)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx, `
+INSERT INTO user_permissions_sync(user_id, last_attempt_at, failures)
+VALUES($1, now(), CASE WHEN $2 THEN 1 ELSE 0 END)
+ON CONFLICT (user_id) DO UPDATE SET
+	last_attempt_at=EXCLUDED.last_attempt_at,
+	failures=CASE WHEN $2 THEN user_permissions_sync.failures+1 ELSE 0 END`,
+		userID, failed)
+	return err
+}
+
+// ListUsersToSync returns the IDs of up to limit users whose permissions are due to be synced,
+// least recently attempted first. A user's permissions are due to be synced if they have never been
+// synced, if the last sync attempt was longer than interval ago, or if the last sync attempt failed
+// and its backoff (see permissionsSyncRetryInterval) has passed. Site admins are omitted because
+// their permissions are never checked.
+func (*userPermissions) ListUsersToSync(ctx context.Context, interval time.Duration, limit int) ([]int32, error) {
+	if Mocks.UserPermissions.ListUsersToSync != nil {
+		return Mocks.UserPermissions.ListUsersToSync(interval, limit)
+	}
+
+	rows, err := dbconn.Global.QueryContext(ctx, `
+SELECT users.id FROM users
+LEFT JOIN user_permissions_sync s ON s.user_id=users.id
+WHERE users.deleted_at IS NULL AND NOT users.site_admin AND (
+	s.user_id IS NULL OR
+	s.last_attempt_at + CASE
+		WHEN s.failures=0 THEN $1::float8
+		ELSE LEAST($2::float8 * power(2, LEAST(s.failures, 16) - 1), $1::float8)
+	END * interval '1 second' <= now()
+)
+ORDER BY s.last_attempt_at ASC NULLS FIRST, users.id ASC
+LIMIT $3`, interval.Seconds(), permissionsSyncRetryInterval.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var userIDs []int32
+	for rows.Next() {
+		var id int32
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, id)
+	}
+	return userIDs, rows.Err()
+}
+
+// Sync computes the user's permissions on the given repositories from each authz provider and
+// stores them. Each repository's permissions are determined by the first authz provider that
+// claims it, in the same way as when the permissions are checked with the authz providers directly.
+// The repositories must have been listed at reposListedAt.
+func (s *userPermissions) Sync(ctx context.Context, userID int32, repos []*types.Repo, reposListedAt time.Time) error {
+	_, authzProviders := authz.GetProviders()
+	if len(authzProviders) == 0 {
+		return nil
+	}
+	user, err := Users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	accts, err := ExternalAccounts.List(ctx, ExternalAccountsListOptions{UserID: userID})
+	if err != nil {
+		return err
+	}
+
+	// Syncs run in the background (for all users), so the authz providers' requests to the code
+	// hosts must not use the budget reserved for requests that users are waiting on.
+	ctx = ratelimit.WithPriority(ctx, ratelimit.PriorityBackground)
+
+	repoIDs := make(map[api.RepoName]api.RepoID, len(repos))
+	unclaimed := make(map[authz.Repo]struct{}, len(repos))
+	for _, repo := range repos {
+		// 🚨 SECURITY: Repos with no external repo spec are never accessible through an authz
+		// provider (see getFilteredRepoNames).
+		if spec := repo.ExternalRepo; spec == nil || spec.ID == "" || spec.ServiceID == "" || spec.ServiceType == "" {
+			continue
+		}
+		repoIDs[repo.Name] = repo.ID
+		unclaimed[authz.Repo{RepoName: repo.Name, ExternalRepoSpec: *repo.ExternalRepo}] = struct{}{}
+	}
+
+	ps := make([]*UserPermission, 0, len(authzProviders)+1)
+	for _, authzProvider := range authzProviders {
+		var mine map[authz.Repo]struct{}
+		mine, unclaimed = authzProvider.Repos(ctx, unclaimed)
+
+		providerAcct, err := authzProviderAccount(ctx, user, accts, authzProvider)
+		if err != nil {
+			return err
+		}
+		perms, err := authzProvider.RepoPerms(ctx, providerAcct, mine)
+		if err != nil {
+			return err
+		}
+
+		p := &UserPermission{
+			UserID:              userID,
+			Perm:                authz.Read,
+			ProviderServiceType: authzProvider.ServiceType(),
+			ProviderServiceID:   authzProvider.ServiceID(),
+			RepoIDs:             []api.RepoID{},
+			UpdatedAt:           reposListedAt,
+		}
+		for repo := range mine {
+			if perms[repo.RepoName][authz.Read] {
+				p.RepoIDs = append(p.RepoIDs, repoIDs[repo.RepoName])
+			}
+		}
+		ps = append(ps, p)
+	}
+
+	// Store the unclaimed repos even if access is not allowed by default, because that setting may
+	// change before the permissions are next synced.
+	p := &UserPermission{UserID: userID, Perm: authz.Read, RepoIDs: []api.RepoID{}, UpdatedAt: reposListedAt}
+	for repo := range unclaimed {
+		p.RepoIDs = append(p.RepoIDs, repoIDs[repo.RepoName])
+	}
+	ps = append(ps, p)
+
+	return s.SetAll(ctx, userID, authz.Read, ps)
+}
+
+// filter returns the names of the repositories among repos on which the user has the permission,
+// according to the user's stored permissions, with a single query. The repositories that were
+// added after the user's permissions were synced are returned as unsynced, and their permissions
+// must be checked with the authz providers.
+//
+// If the user's permissions haven't been synced from all of the authz providers (or none of the
+// repos exist), ok is false.
+func (*userPermissions) filter(ctx context.Context, userID int32, perm authz.Perm, authzAllowByDefault bool, authzProviders []authz.Provider, repos map[authz.Repo]struct{}) (accepted map[api.RepoName]struct{}, unsynced map[authz.Repo]struct{}, ok bool, err error) {
+	if Mocks.UserPermissions.Filter != nil {
+		return Mocks.UserPermissions.Filter(userID, perm, repos)
+	}
+
+	byName := make(map[api.RepoName]authz.Repo, len(repos))
+	names := make([]string, 0, len(repos))
+	for repo := range repos {
+		byName[repo.RepoName] = repo
+		names = append(names, string(repo.RepoName))
+	}
+	// The stored permissions for the repos that no authz provider claimed have an empty provider.
+	providerKeys := []*sqlf.Query{sqlf.Sprintf("('', '')")}
+	for _, p := range authzProviders {
+		providerKeys = append(providerKeys, sqlf.Sprintf("(%s, %s)", p.ServiceType(), p.ServiceID()))
+	}
+
+	q := sqlf.Sprintf(`
+WITH p AS (
+	SELECT provider_service_type, repo_ids, updated_at FROM user_permissions
+	WHERE user_id=%s AND permission=%s AND (provider_service_type, provider_service_id) IN (%s)
+)
+SELECT
+	repo.name,
+	(SELECT count(*) FROM p),
+	repo.created_at > (SELECT min(updated_at) FROM p),
+	EXISTS (SELECT 1 FROM p WHERE repo.id = ANY(p.repo_ids) AND (p.provider_service_type<>'' OR %s))
+FROM repo
+WHERE repo.name = ANY(%s::citext[])`,
+		userID, perm, sqlf.Join(providerKeys, ","), authzAllowByDefault, pq.Array(names))
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	defer rows.Close()
+
+	accepted = map[api.RepoName]struct{}{}
+	unsynced = map[authz.Repo]struct{}{}
+	for rows.Next() {
+		var (
+			name                   string
+			syncedProviders        int
+			isUnsynced, isAccepted sql.NullBool
+		)
+		if err := rows.Scan(&name, &syncedProviders, &isUnsynced, &isAccepted); err != nil {
+			return nil, nil, false, err
+		}
+		if syncedProviders != len(providerKeys) {
+			return nil, nil, false, nil
+		}
+		ok = true
+		repo, found := byName[api.RepoName(name)]
+		if !found {
+			continue
+		}
+		if isUnsynced.Bool {
+			unsynced[repo] = struct{}{}
+		} else if isAccepted.Bool {
+			accepted[repo.RepoName] = struct{}{}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, false, err
+	}
+	return accepted, unsynced, ok, nil
+}
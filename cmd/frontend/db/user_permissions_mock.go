@@ -0,0 +1,15 @@
+package db
+
+import (
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+type MockUserPermissions struct {
+	SetAll            func(userID int32, perm authz.Perm, ps []*UserPermission) error
+	RecordSyncAttempt func(userID int32, failed bool) error
+	ListUsersToSync   func(interval time.Duration, limit int) ([]int32, error)
+	Filter            func(userID int32, perm authz.Perm, repos map[authz.Repo]struct{}) (accepted map[api.RepoName]struct{}, unsynced map[authz.Repo]struct{}, ok bool, err error)
+}
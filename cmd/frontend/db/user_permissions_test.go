@@ -0,0 +1,274 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type mockPermissionsSyncer struct{ requested []int32 }
+
+func (s *mockPermissionsSyncer) RequestSync(userID int32) { s.requested = append(s.requested, userID) }
+
+func Test_authzFilter_storedPermissions(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{PermissionsBackgroundSync: &schema.PermissionsBackgroundSync{Enabled: true}}})
+	defer conf.Mock(nil)
+	defer func() { Mocks = MockStores{} }()
+
+	// The user has live permissions on all repos except "d", but the stored permissions (which take
+	// precedence) only include some of them.
+	authz.SetProviders(false, []authz.Provider{
+		&MockAuthzProvider{
+			serviceID:   "https://mock.mine/",
+			serviceType: "mock",
+			repos:       map[api.RepoName]struct{}{"a": {}, "b": {}, "c": {}, "d": {}},
+			perms: map[extsvc.ExternalAccount]map[api.RepoName]map[authz.Perm]bool{
+				{}: {"a": {authz.Read: true}, "b": {authz.Read: true}, "c": {authz.Read: true}},
+			},
+		},
+	})
+	Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) { return &types.User{ID: 1}, nil }
+	Mocks.ExternalAccounts.List = func(ExternalAccountsListOptions) ([]*extsvc.ExternalAccount, error) { return nil, nil }
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+
+	tests := []struct {
+		name          string
+		synced        bool
+		wantRepos     []api.RepoName
+		wantRequested []int32
+	}{
+		// Repo "c" was added after the user's permissions were synced, so it is checked with the
+		// authz provider.
+		{name: "synced", synced: true, wantRepos: []api.RepoName{"a", "c"}},
+		{name: "not synced", synced: false, wantRepos: []api.RepoName{"a", "b", "c"}, wantRequested: []int32{1}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			syncer := &mockPermissionsSyncer{}
+			authz.SetPermissionsSyncer(syncer)
+			defer authz.SetPermissionsSyncer(nil)
+
+			Mocks.UserPermissions.Filter = func(userID int32, perm authz.Perm, repos map[authz.Repo]struct{}) (map[api.RepoName]struct{}, map[authz.Repo]struct{}, bool, error) {
+				if userID != 1 || perm != authz.Read || len(repos) != 4 {
+					t.Fatalf("unexpected arguments %d, %q, %v", userID, perm, repos)
+				}
+				if !test.synced {
+					return nil, nil, false, nil
+				}
+				unsynced := map[authz.Repo]struct{}{}
+				for repo := range repos {
+					if repo.RepoName == "c" {
+						unsynced[repo] = struct{}{}
+					}
+				}
+				return map[api.RepoName]struct{}{"a": {}}, unsynced, true, nil
+			}
+
+			repos, err := authzFilter(ctx, makeRepos("a", "b", "c", "d"), authz.Read)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sortedRepoNames(repos); !reflect.DeepEqual(got, test.wantRepos) {
+				t.Errorf("got repos %v, want %v", got, test.wantRepos)
+			}
+			if !reflect.DeepEqual(syncer.requested, test.wantRequested) {
+				t.Errorf("got sync requests for users %v, want %v", syncer.requested, test.wantRequested)
+			}
+		})
+	}
+}
+
+func TestUserPermissions_Sync(t *testing.T) {
+	defer func() { Mocks = MockStores{} }()
+
+	authz.SetProviders(false, []authz.Provider{
+		&MockAuthzProvider{
+			serviceID:    "https://gitlab.mine/",
+			serviceType:  "gitlab",
+			okServiceIDs: map[string]struct{}{"https://okta.mine/": {}},
+			repos:        map[api.RepoName]struct{}{"a": {}, "b": {}},
+			perms: map[extsvc.ExternalAccount]map[api.RepoName]map[authz.Perm]bool{
+				*acct(1, "gitlab", "https://gitlab.mine/", "alice"): {"a": {authz.Read: true}},
+				{}: {},
+			},
+		},
+		&MockAuthzProvider{
+			serviceID:   "https://other.mine/",
+			serviceType: "other",
+			repos:       map[api.RepoName]struct{}{"a": {}, "c": {}},
+			perms: map[extsvc.ExternalAccount]map[api.RepoName]map[authz.Perm]bool{
+				{}: {"a": {authz.Read: true}, "c": {authz.Read: true}},
+			},
+		},
+	})
+	Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) { return &types.User{ID: id}, nil }
+	Mocks.ExternalAccounts.List = func(ExternalAccountsListOptions) ([]*extsvc.ExternalAccount, error) {
+		return []*extsvc.ExternalAccount{acct(1, "okta", "https://okta.mine/", "alice")}, nil
+	}
+	Mocks.ExternalAccounts.AssociateUserAndSave = func(int32, extsvc.ExternalAccountSpec, extsvc.ExternalAccountData) error { return nil }
+	var got []*UserPermission
+	Mocks.UserPermissions.SetAll = func(userID int32, perm authz.Perm, ps []*UserPermission) error {
+		if userID != 1 || perm != authz.Read {
+			t.Fatalf("unexpected arguments %d, %q", userID, perm)
+		}
+		for _, p := range ps {
+			sort.Slice(p.RepoIDs, func(i, j int) bool { return p.RepoIDs[i] < p.RepoIDs[j] })
+		}
+		got = ps
+		return nil
+	}
+
+	listedAt := time.Now()
+	repos := []*types.Repo{makeRepo("a", 1), makeRepo("b", 2), makeRepo("c", 3), {ID: 4, Name: "d"}, makeRepo("e", 5)}
+	if err := UserPermissions.Sync(context.Background(), 1, repos, listedAt); err != nil {
+		t.Fatal(err)
+	}
+	// Repo "a" is claimed by the first provider, so the second provider's permissions on it are
+	// ignored. Repo "d" has no external repo spec, so it is never accessible. Repo "e" is claimed
+	// by no provider.
+	want := []*UserPermission{
+		{UserID: 1, Perm: authz.Read, ProviderServiceType: "gitlab", ProviderServiceID: "https://gitlab.mine/", RepoIDs: []api.RepoID{1}, UpdatedAt: listedAt},
+		{UserID: 1, Perm: authz.Read, ProviderServiceType: "other", ProviderServiceID: "https://other.mine/", RepoIDs: []api.RepoID{3}, UpdatedAt: listedAt},
+		{UserID: 1, Perm: authz.Read, RepoIDs: []api.RepoID{5}, UpdatedAt: listedAt},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %s, want %s", asJSON(t, got), asJSON(t, want))
+	}
+}
+
+func TestUserPermissions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	if _, err := Users.Create(ctx, NewUser{Username: "admin"}); err != nil {
+		t.Fatal(err)
+	}
+	user1, err := Users.Create(ctx, NewUser{Username: "u1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user2, err := Users.Create(ctx, NewUser{Username: "u2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	repos := mustCreate(ctx, t, &types.Repo{Name: "a"}, &types.Repo{Name: "b"}, &types.Repo{Name: "c"})
+
+	listUsersToSync := func(want ...int32) {
+		t.Helper()
+		if got, err := UserPermissions.ListUsersToSync(ctx, time.Hour, 10); err != nil {
+			t.Fatal(err)
+		} else if !reflect.DeepEqual(got, want) {
+			t.Errorf("got users to sync %v, want %v", got, want)
+		}
+	}
+
+	// Neither user's permissions have been synced (and the site admin is never synced).
+	listUsersToSync(user1.ID, user2.ID)
+
+	// The users are synced again after the interval, or after the backoff if the sync failed.
+	if err := UserPermissions.RecordSyncAttempt(ctx, user1.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := UserPermissions.RecordSyncAttempt(ctx, user2.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	listUsersToSync()
+	if _, err := dbconn.Global.ExecContext(ctx, "UPDATE user_permissions_sync SET last_attempt_at=last_attempt_at - interval '10 minutes'"); err != nil {
+		t.Fatal(err)
+	}
+	listUsersToSync(user2.ID)
+	if err := UserPermissions.RecordSyncAttempt(ctx, user2.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dbconn.Global.ExecContext(ctx, "UPDATE user_permissions_sync SET last_attempt_at=last_attempt_at - interval '7 minutes'"); err != nil {
+		t.Fatal(err)
+	}
+	listUsersToSync() // the second failure doubled the backoff to 10 minutes
+
+	gitlab := &MockAuthzProvider{serviceType: "gitlab", serviceID: "https://gitlab.mine/"}
+	github := &MockAuthzProvider{serviceType: "github", serviceID: "https://github.mine/"}
+	filter := func(allowByDefault bool, authzProviders ...authz.Provider) (accepted []api.RepoName, unsynced []api.RepoName, ok bool) {
+		t.Helper()
+		acceptedSet, unsyncedSet, ok, err := UserPermissions.filter(ctx, user1.ID, authz.Read, allowByDefault, authzProviders, authz.ToRepos(repos))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name := range acceptedSet {
+			accepted = append(accepted, name)
+		}
+		for repo := range unsyncedSet {
+			unsynced = append(unsynced, repo.RepoName)
+		}
+		sort.Slice(accepted, func(i, j int) bool { return accepted[i] < accepted[j] })
+		return accepted, unsynced, ok
+	}
+
+	if _, _, ok := filter(false, gitlab, github); ok {
+		t.Error("got ok before the permissions were synced, want !ok")
+	}
+
+	syncedAt := time.Now()
+	for _, ps := range [][]*UserPermission{
+		{
+			{ProviderServiceType: "gitlab", ProviderServiceID: "https://gitlab.mine/", RepoIDs: []api.RepoID{repos[0].ID, repos[1].ID}, UpdatedAt: syncedAt},
+			{ProviderServiceType: "removed", ProviderServiceID: "https://removed.mine/", RepoIDs: []api.RepoID{repos[1].ID}, UpdatedAt: syncedAt},
+			{RepoIDs: []api.RepoID{}, UpdatedAt: syncedAt},
+		},
+		{
+			{ProviderServiceType: "gitlab", ProviderServiceID: "https://gitlab.mine/", RepoIDs: []api.RepoID{repos[0].ID}, UpdatedAt: syncedAt},
+			{ProviderServiceType: "github", ProviderServiceID: "https://github.mine/", RepoIDs: []api.RepoID{}, UpdatedAt: syncedAt},
+			{RepoIDs: []api.RepoID{repos[1].ID}, UpdatedAt: syncedAt},
+		},
+	} {
+		if err := UserPermissions.SetAll(ctx, user1.ID, authz.Read, ps); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The second SetAll replaced the first (including the permissions from the removed provider).
+	if p, err := UserPermissions.Get(ctx, user1.ID, authz.Read, "gitlab", "https://gitlab.mine/"); err != nil {
+		t.Fatal(err)
+	} else if want := []api.RepoID{repos[0].ID}; p == nil || !reflect.DeepEqual(p.RepoIDs, want) {
+		t.Errorf("got permissions %+v, want repo IDs %v", p, want)
+	}
+	if p, err := UserPermissions.Get(ctx, user1.ID, authz.Read, "removed", "https://removed.mine/"); err != nil {
+		t.Fatal(err)
+	} else if p != nil {
+		t.Errorf("got permissions %+v, want nil", p)
+	}
+
+	// Repo "b" is claimed by no provider.
+	if accepted, unsynced, ok := filter(false, gitlab, github); !ok || !reflect.DeepEqual(accepted, []api.RepoName{"a"}) || len(unsynced) != 0 {
+		t.Errorf("got accepted %v, unsynced %v, ok %v, want [a], [], true", accepted, unsynced, ok)
+	}
+	if accepted, unsynced, ok := filter(true, gitlab, github); !ok || !reflect.DeepEqual(accepted, []api.RepoName{"a", "b"}) || len(unsynced) != 0 {
+		t.Errorf("got accepted %v, unsynced %v, ok %v, want [a b], [], true", accepted, unsynced, ok)
+	}
+
+	// The permissions haven't been synced from a newly added provider.
+	if _, _, ok := filter(false, gitlab, github, &MockAuthzProvider{serviceType: "new", serviceID: "https://new.mine/"}); ok {
+		t.Error("got ok with an unsynced provider, want !ok")
+	}
+
+	// Repo "c" was added after the permissions were synced.
+	if _, err := dbconn.Global.ExecContext(ctx, "UPDATE repo SET created_at=$1 WHERE id=$2", syncedAt.Add(time.Minute), repos[2].ID); err != nil {
+		t.Fatal(err)
+	}
+	if accepted, unsynced, ok := filter(false, gitlab, github); !ok || !reflect.DeepEqual(accepted, []api.RepoName{"a"}) || !reflect.DeepEqual(unsynced, []api.RepoName{"c"}) {
+		t.Errorf("got accepted %v, unsynced %v, ok %v, want [a], [c], true", accepted, unsynced, ok)
+	}
+}
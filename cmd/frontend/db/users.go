@@ -12,6 +12,7 @@ import (
 	"github.com/lib/pq"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/pkg/db/globalstatedb"
@@ -475,6 +476,18 @@ func (u *users) HardDelete(ctx context.Context, id int32) error {
 	if _, err := tx.ExecContext(ctx, "DELETE FROM discussion_mail_reply_tokens WHERE user_id=$1", id); err != nil {
 		return err
 	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM discussion_comment_reactions WHERE user_id=$1 OR comment_id IN (SELECT id FROM discussion_comments WHERE author_user_id=$1)", id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM discussion_thread_subscriptions WHERE user_id=$1 OR thread_id IN (SELECT id FROM discussion_threads WHERE author_user_id=$1)", id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM discussion_imported_comments WHERE comment_id IN (SELECT id FROM discussion_comments WHERE author_user_id=$1) OR thread_id IN (SELECT id FROM discussion_threads WHERE author_user_id=$1)", id); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE discussion_threads SET resolved_by_user_id=null WHERE resolved_by_user_id=$1", id); err != nil {
+		return err
+	}
 	if _, err := tx.ExecContext(ctx, "UPDATE discussion_threads SET target_repo_id=null WHERE author_user_id=$1", id); err != nil {
 		return err
 	}
@@ -510,6 +523,72 @@ func (u *users) SetIsSiteAdmin(ctx context.Context, id int32, isSiteAdmin bool)
 	return err
 }
 
+// SetSuspended suspends or unsuspends the user. A suspended user can't sign in, and their existing
+// sessions and access tokens can't be used, but their account and data are retained so that they
+// can be unsuspended.
+func (u *users) SetSuspended(ctx context.Context, id int32, suspended bool) error {
+	if Mocks.Users.SetSuspended != nil {
+		return Mocks.Users.SetSuspended(id, suspended)
+	}
+	var q string
+	if suspended {
+		q = "UPDATE users SET suspended_at=COALESCE(suspended_at, now()), updated_at=now() WHERE id=$1 AND deleted_at IS NULL"
+	} else {
+		q = "UPDATE users SET suspended_at=NULL, updated_at=now() WHERE id=$1 AND deleted_at IS NULL"
+	}
+	res, err := dbconn.Global.ExecContext(ctx, q, id)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return userNotFoundErr{args: []interface{}{id}}
+	}
+	return nil
+}
+
+// GetSavedSearchEmailFrequency returns how often the user receives saved search email notifications
+// (one of the api.SavedSearchEmailFrequency* values).
+func (u *users) GetSavedSearchEmailFrequency(ctx context.Context, id int32) (string, error) {
+	if Mocks.Users.GetSavedSearchEmailFrequency != nil {
+		return Mocks.Users.GetSavedSearchEmailFrequency(id)
+	}
+	var frequency string
+	err := dbconn.Global.QueryRowContext(ctx, "SELECT saved_search_email_frequency FROM users WHERE id=$1 AND deleted_at IS NULL", id).Scan(&frequency)
+	if err == sql.ErrNoRows {
+		return "", userNotFoundErr{args: []interface{}{id}}
+	}
+	return frequency, err
+}
+
+// SetSavedSearchEmailFrequency sets how often the user receives saved search email notifications. The
+// frequency must be one of the api.SavedSearchEmailFrequency* values.
+func (u *users) SetSavedSearchEmailFrequency(ctx context.Context, id int32, frequency string) error {
+	if Mocks.Users.SetSavedSearchEmailFrequency != nil {
+		return Mocks.Users.SetSavedSearchEmailFrequency(id, frequency)
+	}
+	switch frequency {
+	case api.SavedSearchEmailFrequencyImmediate, api.SavedSearchEmailFrequencyHourly, api.SavedSearchEmailFrequencyDaily:
+	default:
+		return fmt.Errorf("invalid saved search email frequency %q", frequency)
+	}
+	res, err := dbconn.Global.ExecContext(ctx, "UPDATE users SET saved_search_email_frequency=$1, updated_at=now() WHERE id=$2 AND deleted_at IS NULL", frequency, id)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return userNotFoundErr{args: []interface{}{id}}
+	}
+	return nil
+}
+
 // CheckAndDecrementInviteQuota should be called before the user (identified
 // by userID) is allowed to invite any other user. If ok is false, then the
 // user is not allowed to invite any other user (either because they've
@@ -660,7 +739,7 @@ func (u *users) getOneBySQL(ctx context.Context, query string, args ...interface
 
 // getBySQL returns users matching the SQL query, if any exist.
 func (*users) getBySQL(ctx context.Context, query string, args ...interface{}) ([]*types.User, error) {
-	rows, err := dbconn.Global.QueryContext(ctx, "SELECT u.id, u.username, u.display_name, u.avatar_url, u.created_at, u.updated_at, u.site_admin, u.tags FROM users u "+query, args...)
+	rows, err := dbconn.Global.QueryContext(ctx, "SELECT u.id, u.username, u.display_name, u.avatar_url, u.created_at, u.updated_at, u.site_admin, u.tags, u.suspended_at IS NOT NULL FROM users u "+query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -670,7 +749,7 @@ func (*users) getBySQL(ctx context.Context, query string, args ...interface{}) (
 	for rows.Next() {
 		var u types.User
 		var displayName, avatarURL sql.NullString
-		err := rows.Scan(&u.ID, &u.Username, &displayName, &avatarURL, &u.CreatedAt, &u.UpdatedAt, &u.SiteAdmin, pq.Array(&u.Tags))
+		err := rows.Scan(&u.ID, &u.Username, &displayName, &avatarURL, &u.CreatedAt, &u.UpdatedAt, &u.SiteAdmin, pq.Array(&u.Tags), &u.Suspended)
 		if err != nil {
 			return nil, err
 		}
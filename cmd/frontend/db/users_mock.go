@@ -11,12 +11,16 @@ type MockUsers struct {
 	Create               func(ctx context.Context, info NewUser) (newUser *types.User, err error)
 	Update               func(userID int32, update UserUpdate) error
 	SetIsSiteAdmin       func(id int32, isSiteAdmin bool) error
+	SetSuspended         func(id int32, suspended bool) error
 	GetByID              func(ctx context.Context, id int32) (*types.User, error)
 	GetByUsername        func(ctx context.Context, username string) (*types.User, error)
 	GetByCurrentAuthUser func(ctx context.Context) (*types.User, error)
 	GetByVerifiedEmail   func(ctx context.Context, email string) (*types.User, error)
 	Count                func(ctx context.Context, opt *UsersListOptions) (int, error)
 	List                 func(ctx context.Context, opt *UsersListOptions) ([]*types.User, error)
+
+	GetSavedSearchEmailFrequency func(id int32) (string, error)
+	SetSavedSearchEmailFrequency func(id int32, frequency string) error
 }
 
 func (s *MockUsers) MockGetByID_Return(t *testing.T, returns *types.User, returnsErr error) (called *bool) {
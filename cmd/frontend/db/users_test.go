@@ -377,6 +377,90 @@ func TestUsers_GetByVerifiedEmail(t *testing.T) {
 	}
 }
 
+func TestUsers_SetSuspended(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	user, err := Users.Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, tv, err := AccessTokens.Create(ctx, user.ID, []string{"a"}, "n", user.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Users.SetSuspended(ctx, user.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	// Suspended users are still returned, but their access tokens can't be used.
+	if gotUser, err := Users.GetByID(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	} else if !gotUser.Suspended {
+		t.Error("want user to be suspended")
+	}
+	if _, _, err := AccessTokens.Lookup(ctx, tv, "a"); err == nil {
+		t.Error("Lookup: want error looking up token for suspended user")
+	}
+
+	if err := Users.SetSuspended(ctx, user.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	if gotUser, err := Users.GetByID(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	} else if gotUser.Suspended {
+		t.Error("want user to not be suspended")
+	}
+	if _, _, err := AccessTokens.Lookup(ctx, tv, "a"); err != nil {
+		t.Errorf("Lookup: got error %v, want no error for unsuspended user", err)
+	}
+
+	// Can't suspend nonexistent user.
+	if err := Users.SetSuspended(ctx, 12345, true); !errcode.IsNotFound(err) {
+		t.Errorf("got error %v, want IsNotFound", err)
+	}
+}
+
+func TestUsers_SavedSearchEmailFrequency(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	user, err := Users.Create(ctx, NewUser{Username: "u"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Defaults to immediate.
+	if frequency, err := Users.GetSavedSearchEmailFrequency(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	} else if want := api.SavedSearchEmailFrequencyImmediate; frequency != want {
+		t.Errorf("got frequency %q, want %q", frequency, want)
+	}
+
+	if err := Users.SetSavedSearchEmailFrequency(ctx, user.ID, api.SavedSearchEmailFrequencyDaily); err != nil {
+		t.Fatal(err)
+	}
+	if frequency, err := Users.GetSavedSearchEmailFrequency(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	} else if want := api.SavedSearchEmailFrequencyDaily; frequency != want {
+		t.Errorf("got frequency %q, want %q", frequency, want)
+	}
+
+	if err := Users.SetSavedSearchEmailFrequency(ctx, user.ID, "weekly"); err == nil {
+		t.Error("want error for invalid frequency")
+	}
+	if err := Users.SetSavedSearchEmailFrequency(ctx, 12345, api.SavedSearchEmailFrequencyDaily); !errcode.IsNotFound(err) {
+		t.Errorf("got error %v, want IsNotFound", err)
+	}
+	if _, err := Users.GetSavedSearchEmailFrequency(ctx, 12345); !errcode.IsNotFound(err) {
+		t.Errorf("got error %v, want IsNotFound", err)
+	}
+}
+
 func TestUsers_Delete(t *testing.T) {
 	for name, hard := range map[string]bool{"": false, "_Hard": true} {
 		t.Run("TestUsers_Delete"+name, func(t *testing.T) {
@@ -0,0 +1,262 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// The states of a webhook delivery.
+const (
+	WebhookDeliveryStatePending   = "pending"   // the delivery has not yet succeeded and will be (re)attempted
+	WebhookDeliveryStateSucceeded = "succeeded" // the webhook's endpoint accepted the delivery
+	WebhookDeliveryStateFailed    = "failed"    // all attempts failed, and the delivery was abandoned
+)
+
+// WebhookDelivery is the delivery of an event to a webhook.
+type WebhookDelivery struct {
+	ID                 int64
+	WebhookID          int32
+	EventType          string
+	Payload            json.RawMessage
+	State              string // the delivery's state (e.g., WebhookDeliveryStatePending)
+	Attempts           int32  // the number of delivery attempts so far
+	NextAttemptAt      time.Time
+	LastAttemptAt      *time.Time
+	ResponseStatusCode *int32  // the HTTP status code of the last attempt's response, if any
+	ResponseBody       *string // the (truncated) body of the last attempt's response, if any
+	Error              *string // the error of the last attempt, if any
+	CreatedAt          time.Time
+}
+
+// ErrWebhookDeliveryNotFound occurs when a database operation expects a specific webhook delivery
+// to exist but it does not exist.
+var ErrWebhookDeliveryNotFound = errors.New("webhook delivery not found")
+
+// webhookDeliveryLease is how long a dequeued delivery is reserved for the worker that dequeued
+// it. If the worker doesn't record the attempt within this time (e.g., because it was
+// terminated), another worker may dequeue the delivery again.
+const webhookDeliveryLease = time.Minute
+
+// webhookDeliveries provides access to the `webhook_deliveries` table, which is both the queue and
+// the history of webhook deliveries.
+type webhookDeliveries struct{}
+
+// Create enqueues the delivery of an event to a webhook. It is attempted as soon as possible.
+func (s *webhookDeliveries) Create(ctx context.Context, webhookID int32, eventType string, payload json.RawMessage) (*WebhookDelivery, error) {
+	if Mocks.WebhookDeliveries.Create != nil {
+		return Mocks.WebhookDeliveries.Create(webhookID, eventType, payload)
+	}
+
+	var id int64
+	if err := dbconn.Global.QueryRowContext(ctx,
+		"INSERT INTO webhook_deliveries(webhook_id, event_type, payload) VALUES($1, $2, $3) RETURNING id",
+		webhookID, eventType, []byte(payload),
+	).Scan(&id); err != nil {
+		return nil, err
+	}
+	return s.GetByID(ctx, id)
+}
+
+// GetByID returns the webhook delivery with the given ID.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin or the webhook's user.
+func (s *webhookDeliveries) GetByID(ctx context.Context, id int64) (*WebhookDelivery, error) {
+	if Mocks.WebhookDeliveries.GetByID != nil {
+		return Mocks.WebhookDeliveries.GetByID(id)
+	}
+
+	results, err := s.list(ctx, sqlf.Sprintf("id=%d", id), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrWebhookDeliveryNotFound
+	}
+	return results[0], nil
+}
+
+// Dequeue returns up to limit pending deliveries that are due to be attempted, oldest first. The
+// returned deliveries are leased to the caller, which must record the attempt with MarkAttempt.
+// Concurrent callers never receive the same deliveries.
+func (s *webhookDeliveries) Dequeue(ctx context.Context, limit int) ([]*WebhookDelivery, error) {
+	if Mocks.WebhookDeliveries.Dequeue != nil {
+		return Mocks.WebhookDeliveries.Dequeue(limit)
+	}
+
+	rows, err := dbconn.Global.QueryContext(ctx, `
+UPDATE webhook_deliveries SET next_attempt_at=now() + $1 * interval '1 second'
+WHERE id IN (
+	SELECT id FROM webhook_deliveries
+	WHERE state='pending' AND next_attempt_at<=now()
+	ORDER BY next_attempt_at ASC
+	LIMIT $2
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING id`,
+		webhookDeliveryLease.Seconds(), limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	idQueries := make([]*sqlf.Query, len(ids))
+	for i, id := range ids {
+		idQueries[i] = sqlf.Sprintf("%d", id)
+	}
+	return s.list(ctx, sqlf.Sprintf("id IN (%s)", sqlf.Join(idQueries, ",")), nil)
+}
+
+// WebhookDeliveryAttempt is the result of an attempt to deliver an event to a webhook.
+type WebhookDeliveryAttempt struct {
+	State              string     // the delivery's new state
+	NextAttemptAt      *time.Time // when to attempt the delivery again (if State is pending)
+	ResponseStatusCode int32      // the HTTP status code of the response (zero if there was none)
+	ResponseBody       string     // the (truncated) body of the response
+	Error              string     // the error that occurred (empty if none)
+}
+
+// MarkAttempt records an attempt to deliver the webhook delivery with the given ID.
+func (*webhookDeliveries) MarkAttempt(ctx context.Context, id int64, attempt WebhookDeliveryAttempt) error {
+	if Mocks.WebhookDeliveries.MarkAttempt != nil {
+		return Mocks.WebhookDeliveries.MarkAttempt(id, attempt)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx, `
+UPDATE webhook_deliveries SET
+	state=$2,
+	attempts=attempts+1,
+	last_attempt_at=now(),
+	next_attempt_at=COALESCE($3, next_attempt_at),
+	response_status_code=NULLIF($4, 0),
+	response_body=NULLIF($5, ''),
+	error=NULLIF($6, '')
+WHERE id=$1`,
+		id, attempt.State, attempt.NextAttemptAt, attempt.ResponseStatusCode, attempt.ResponseBody, attempt.Error,
+	)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return ErrWebhookDeliveryNotFound
+	}
+	return nil
+}
+
+// WebhookDeliveriesListOptions contains options for listing webhook deliveries.
+type WebhookDeliveriesListOptions struct {
+	WebhookID int32 // only list the deliveries to this webhook
+	*LimitOffset
+}
+
+func (o WebhookDeliveriesListOptions) sqlConditions() *sqlf.Query {
+	if o.WebhookID != 0 {
+		return sqlf.Sprintf("webhook_id=%d", o.WebhookID)
+	}
+	return sqlf.Sprintf("TRUE")
+}
+
+// List lists the webhook deliveries that satisfy the options, most recent first.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin or the webhook's user.
+func (s *webhookDeliveries) List(ctx context.Context, opt WebhookDeliveriesListOptions) ([]*WebhookDelivery, error) {
+	if Mocks.WebhookDeliveries.List != nil {
+		return Mocks.WebhookDeliveries.List(opt)
+	}
+	return s.list(ctx, opt.sqlConditions(), opt.LimitOffset)
+}
+
+// Count counts the webhook deliveries that satisfy the options (ignoring limit and offset).
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin or the webhook's user.
+func (*webhookDeliveries) Count(ctx context.Context, opt WebhookDeliveriesListOptions) (int, error) {
+	if Mocks.WebhookDeliveries.Count != nil {
+		return Mocks.WebhookDeliveries.Count(opt)
+	}
+
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM webhook_deliveries WHERE (%s)", opt.sqlConditions())
+	var count int
+	if err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (*webhookDeliveries) list(ctx context.Context, cond *sqlf.Query, limitOffset *LimitOffset) ([]*WebhookDelivery, error) {
+	q := sqlf.Sprintf(`
+SELECT id, webhook_id, event_type, payload, state, attempts, next_attempt_at, last_attempt_at, response_status_code, response_body, error, created_at
+FROM webhook_deliveries
+WHERE (%s)
+ORDER BY id DESC
+%s`,
+		cond,
+		limitOffset.SQL(),
+	)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*WebhookDelivery
+	for rows.Next() {
+		var (
+			d                    WebhookDelivery
+			payload              []byte
+			statusCode           sql.NullInt64
+			responseBody, errMsg sql.NullString
+		)
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.EventType, &payload, &d.State, &d.Attempts, &d.NextAttemptAt, &d.LastAttemptAt, &statusCode, &responseBody, &errMsg, &d.CreatedAt); err != nil {
+			return nil, err
+		}
+		d.Payload = payload
+		if statusCode.Valid {
+			v := int32(statusCode.Int64)
+			d.ResponseStatusCode = &v
+		}
+		if responseBody.Valid {
+			d.ResponseBody = &responseBody.String
+		}
+		if errMsg.Valid {
+			d.Error = &errMsg.String
+		}
+		results = append(results, &d)
+	}
+	return results, rows.Err()
+}
+
+// DeleteOlderThan deletes the succeeded and failed webhook deliveries that were created before the
+// given time, so that the delivery history doesn't grow without bound.
+func (*webhookDeliveries) DeleteOlderThan(ctx context.Context, t time.Time) error {
+	if Mocks.WebhookDeliveries.DeleteOlderThan != nil {
+		return Mocks.WebhookDeliveries.DeleteOlderThan(t)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx, "DELETE FROM webhook_deliveries WHERE state<>'pending' AND created_at<$1", t)
+	return err
+}
@@ -0,0 +1,16 @@
+package db
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type MockWebhookDeliveries struct {
+	Create          func(webhookID int32, eventType string, payload json.RawMessage) (*WebhookDelivery, error)
+	GetByID         func(id int64) (*WebhookDelivery, error)
+	Dequeue         func(limit int) ([]*WebhookDelivery, error)
+	MarkAttempt     func(id int64, attempt WebhookDeliveryAttempt) error
+	List            func(opt WebhookDeliveriesListOptions) ([]*WebhookDelivery, error)
+	Count           func(opt WebhookDeliveriesListOptions) (int, error)
+	DeleteOlderThan func(t time.Time) error
+}
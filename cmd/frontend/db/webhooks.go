@@ -0,0 +1,227 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// Webhook is an outbound webhook: an HTTP endpoint that is notified of the platform events of the
+// types it subscribes to. A webhook registered by a site admin (with a zero UserID) receives all
+// events of its types; a user's webhook only receives the events that concern the user.
+type Webhook struct {
+	ID         int32
+	UserID     int32    // the user who registered the webhook (zero for a site webhook)
+	URL        string   // the URL that events are POSTed to
+	Secret     string   // the secret used to sign the deliveries' payloads
+	EventTypes []string // the event types the webhook subscribes to (e.g., "repo.cloned")
+	Active     bool     // whether events are delivered to the webhook
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// ErrWebhookNotFound occurs when a database operation expects a specific webhook to exist but it
+// does not exist.
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// webhooks provides access to the `webhooks` table.
+type webhooks struct{}
+
+// Create creates a webhook. The webhook's ID, CreatedAt, and UpdatedAt fields are ignored; they
+// are set on return.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin (for a site webhook) or the
+// user who the webhook is for.
+func (*webhooks) Create(ctx context.Context, w *Webhook) error {
+	if Mocks.Webhooks.Create != nil {
+		return Mocks.Webhooks.Create(w)
+	}
+
+	return dbconn.Global.QueryRowContext(ctx, `
+INSERT INTO webhooks(user_id, url, secret, event_types, active)
+VALUES(NULLIF($1, 0), $2, $3, $4, $5)
+RETURNING id, created_at, updated_at`,
+		w.UserID, w.URL, w.Secret, pq.Array(w.EventTypes), w.Active,
+	).Scan(&w.ID, &w.CreatedAt, &w.UpdatedAt)
+}
+
+// GetByID returns the webhook with the given ID.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin or the webhook's user.
+func (s *webhooks) GetByID(ctx context.Context, id int32) (*Webhook, error) {
+	if Mocks.Webhooks.GetByID != nil {
+		return Mocks.Webhooks.GetByID(id)
+	}
+
+	results, err := s.list(ctx, []*sqlf.Query{sqlf.Sprintf("id=%d", id)}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrWebhookNotFound
+	}
+	return results[0], nil
+}
+
+// WebhooksListOptions contains options for listing webhooks.
+type WebhooksListOptions struct {
+	UserID int32 // only list the webhooks of this user
+	Site   bool  // only list site webhooks (those not registered by a user)
+	*LimitOffset
+}
+
+func (o WebhooksListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if o.UserID != 0 {
+		conds = append(conds, sqlf.Sprintf("user_id=%d", o.UserID))
+	}
+	if o.Site {
+		conds = append(conds, sqlf.Sprintf("user_id IS NULL"))
+	}
+	return conds
+}
+
+// List lists the webhooks that satisfy the options.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin or the user whose webhooks
+// are listed.
+func (s *webhooks) List(ctx context.Context, opt WebhooksListOptions) ([]*Webhook, error) {
+	if Mocks.Webhooks.List != nil {
+		return Mocks.Webhooks.List(opt)
+	}
+	return s.list(ctx, opt.sqlConditions(), opt.LimitOffset)
+}
+
+// Count counts the webhooks that satisfy the options (ignoring limit and offset).
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin or the user whose webhooks
+// are counted.
+func (*webhooks) Count(ctx context.Context, opt WebhooksListOptions) (int, error) {
+	if Mocks.Webhooks.Count != nil {
+		return Mocks.Webhooks.Count(opt)
+	}
+
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM webhooks WHERE (%s)", sqlf.Join(opt.sqlConditions(), ") AND ("))
+	var count int
+	if err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListForEvent lists the active webhooks that an event of the given type is delivered to: the site
+// webhooks and the webhooks of the given users (who the event concerns) that subscribe to the
+// event type. The webhooks of deleted users are omitted.
+func (s *webhooks) ListForEvent(ctx context.Context, eventType string, userIDs []int32) ([]*Webhook, error) {
+	if Mocks.Webhooks.ListForEvent != nil {
+		return Mocks.Webhooks.ListForEvent(eventType, userIDs)
+	}
+
+	return s.list(ctx, []*sqlf.Query{
+		sqlf.Sprintf("active"),
+		sqlf.Sprintf("%s = ANY(event_types)", eventType),
+		sqlf.Sprintf("user_id IS NULL OR (user_id = ANY(%s) AND user_id IN (SELECT id FROM users WHERE deleted_at IS NULL))", pq.Array(toInt64s(userIDs))),
+	}, nil)
+}
+
+func (*webhooks) list(ctx context.Context, conds []*sqlf.Query, limitOffset *LimitOffset) ([]*Webhook, error) {
+	q := sqlf.Sprintf(`
+SELECT id, user_id, url, secret, event_types, active, created_at, updated_at
+FROM webhooks
+WHERE (%s)
+ORDER BY id ASC
+%s`,
+		sqlf.Join(conds, ") AND ("),
+		limitOffset.SQL(),
+	)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*Webhook
+	for rows.Next() {
+		var (
+			w      Webhook
+			userID sql.NullInt64
+		)
+		if err := rows.Scan(&w.ID, &userID, &w.URL, &w.Secret, pq.Array(&w.EventTypes), &w.Active, &w.CreatedAt, &w.UpdatedAt); err != nil {
+			return nil, err
+		}
+		w.UserID = int32(userID.Int64)
+		results = append(results, &w)
+	}
+	return results, rows.Err()
+}
+
+// WebhookUpdate describes updates to a webhook. Nil fields are not updated.
+type WebhookUpdate struct {
+	URL        *string
+	Secret     *string
+	EventTypes []string
+	Active     *bool
+}
+
+// Update updates the webhook with the given ID.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin or the webhook's user.
+func (s *webhooks) Update(ctx context.Context, id int32, update WebhookUpdate) (*Webhook, error) {
+	if Mocks.Webhooks.Update != nil {
+		return Mocks.Webhooks.Update(id, update)
+	}
+
+	sets := []*sqlf.Query{sqlf.Sprintf("updated_at=now()")}
+	if update.URL != nil {
+		sets = append(sets, sqlf.Sprintf("url=%s", *update.URL))
+	}
+	if update.Secret != nil {
+		sets = append(sets, sqlf.Sprintf("secret=%s", *update.Secret))
+	}
+	if update.EventTypes != nil {
+		sets = append(sets, sqlf.Sprintf("event_types=%s", pq.Array(update.EventTypes)))
+	}
+	if update.Active != nil {
+		sets = append(sets, sqlf.Sprintf("active=%s", *update.Active))
+	}
+	q := sqlf.Sprintf("UPDATE webhooks SET %s WHERE id=%d", sqlf.Join(sets, ", "), id)
+	res, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if nrows == 0 {
+		return nil, ErrWebhookNotFound
+	}
+	return s.GetByID(ctx, id)
+}
+
+// Delete deletes the webhook with the given ID, along with its deliveries.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin or the webhook's user.
+func (*webhooks) Delete(ctx context.Context, id int32) error {
+	if Mocks.Webhooks.Delete != nil {
+		return Mocks.Webhooks.Delete(id)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx, "DELETE FROM webhooks WHERE id=$1", id)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package db
+
+type MockWebhooks struct {
+	Create       func(w *Webhook) error
+	GetByID      func(id int32) (*Webhook, error)
+	List         func(opt WebhooksListOptions) ([]*Webhook, error)
+	Count        func(opt WebhooksListOptions) (int, error)
+	ListForEvent func(eventType string, userIDs []int32) ([]*Webhook, error)
+	Update       func(id int32, update WebhookUpdate) (*Webhook, error)
+	Delete       func(id int32) error
+}
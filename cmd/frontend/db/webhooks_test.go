@@ -0,0 +1,130 @@
+package db
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+)
+
+func TestWebhooks(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	user1, err := Users.Create(ctx, NewUser{Username: "u1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user2, err := Users.Create(ctx, NewUser{Username: "u2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	site := &Webhook{URL: "https://example.com/site", Secret: "s", EventTypes: []string{"repo.cloned", "saved_search.results"}, Active: true}
+	user1Hook := &Webhook{UserID: user1.ID, URL: "https://example.com/u1", Secret: "s", EventTypes: []string{"saved_search.results"}, Active: true}
+	user2Hook := &Webhook{UserID: user2.ID, URL: "https://example.com/u2", Secret: "s", EventTypes: []string{"saved_search.results"}, Active: true}
+	for _, w := range []*Webhook{site, user1Hook, user2Hook} {
+		if err := Webhooks.Create(ctx, w); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n, err := Webhooks.Count(ctx, WebhooksListOptions{UserID: user1.ID}); err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Errorf("got %d webhooks for user 1, want 1", n)
+	}
+	if ws, err := Webhooks.List(ctx, WebhooksListOptions{Site: true}); err != nil {
+		t.Fatal(err)
+	} else if len(ws) != 1 || ws[0].ID != site.ID {
+		t.Errorf("got %s, want only site webhook %d", asJSON(t, ws), site.ID)
+	}
+
+	// An event is delivered to the site webhooks and to the webhooks of the users it concerns.
+	if ws, err := Webhooks.ListForEvent(ctx, "saved_search.results", []int32{user1.ID}); err != nil {
+		t.Fatal(err)
+	} else if len(ws) != 2 || ws[0].ID != site.ID || ws[1].ID != user1Hook.ID {
+		t.Errorf("got %s, want webhooks %d and %d", asJSON(t, ws), site.ID, user1Hook.ID)
+	}
+
+	// Inactive webhooks and the webhooks of deleted users receive no events.
+	inactive := false
+	if _, err := Webhooks.Update(ctx, site.ID, WebhookUpdate{Active: &inactive}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Users.Delete(ctx, user1.ID); err != nil {
+		t.Fatal(err)
+	}
+	if ws, err := Webhooks.ListForEvent(ctx, "saved_search.results", []int32{user1.ID, user2.ID}); err != nil {
+		t.Fatal(err)
+	} else if len(ws) != 1 || ws[0].ID != user2Hook.ID {
+		t.Errorf("got %s, want only webhook %d", asJSON(t, ws), user2Hook.ID)
+	}
+
+	if err := Webhooks.Delete(ctx, user2Hook.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Webhooks.GetByID(ctx, user2Hook.ID); err != ErrWebhookNotFound {
+		t.Errorf("got error %v, want %v", err, ErrWebhookNotFound)
+	}
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	w := &Webhook{URL: "https://example.com", Secret: "s", EventTypes: []string{"repo.cloned"}, Active: true}
+	if err := Webhooks.Create(ctx, w); err != nil {
+		t.Fatal(err)
+	}
+	d, err := WebhookDeliveries.Create(ctx, w.ID, "repo.cloned", json.RawMessage(`{"repo":"r"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.State != WebhookDeliveryStatePending || d.Attempts != 0 {
+		t.Errorf("got %+v, want a pending delivery with no attempts", d)
+	}
+
+	// A dequeued delivery is leased, so it isn't dequeued again.
+	if ds, err := WebhookDeliveries.Dequeue(ctx, 10); err != nil {
+		t.Fatal(err)
+	} else if len(ds) != 1 || ds[0].ID != d.ID {
+		t.Fatalf("got %s, want delivery %d", asJSON(t, ds), d.ID)
+	}
+	if ds, err := WebhookDeliveries.Dequeue(ctx, 10); err != nil {
+		t.Fatal(err)
+	} else if len(ds) != 0 {
+		t.Errorf("got %s, want no deliveries", asJSON(t, ds))
+	}
+
+	// A failed attempt is retried once it is due.
+	retryAt := time.Now().Add(-time.Second)
+	if err := WebhookDeliveries.MarkAttempt(ctx, d.ID, WebhookDeliveryAttempt{State: WebhookDeliveryStatePending, NextAttemptAt: &retryAt, ResponseStatusCode: 500, Error: "HTTP 500"}); err != nil {
+		t.Fatal(err)
+	}
+	if ds, err := WebhookDeliveries.Dequeue(ctx, 10); err != nil {
+		t.Fatal(err)
+	} else if len(ds) != 1 || ds[0].Attempts != 1 || ds[0].ResponseStatusCode == nil || *ds[0].ResponseStatusCode != 500 {
+		t.Fatalf("got %s, want delivery %d after 1 failed attempt", asJSON(t, ds), d.ID)
+	}
+
+	if err := WebhookDeliveries.MarkAttempt(ctx, d.ID, WebhookDeliveryAttempt{State: WebhookDeliveryStateSucceeded, ResponseStatusCode: 200}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := WebhookDeliveries.Count(ctx, WebhookDeliveriesListOptions{WebhookID: w.ID}); err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Errorf("got %d deliveries, want 1", n)
+	}
+	if err := WebhookDeliveries.DeleteOlderThan(ctx, time.Now().Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WebhookDeliveries.GetByID(ctx, d.ID); err != ErrWebhookDeliveryNotFound {
+		t.Errorf("got error %v, want %v", err, ErrWebhookDeliveryNotFound)
+	}
+}
@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
@@ -17,9 +20,10 @@ import (
 )
 
 type createAccessTokenInput struct {
-	User   graphql.ID
-	Scopes []string
-	Note   string
+	User      graphql.ID
+	Scopes    []string
+	Note      string
+	ExpiresAt *string
 }
 
 func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAccessTokenInput) (*createAccessTokenResult, error) {
@@ -31,6 +35,11 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 	if err := backend.CheckSiteAdminOrSameUser(ctx, userID); err != nil {
 		return nil, err
 	}
+	// 🚨 SECURITY: Access tokens that can't make changes must not be able to create (possibly more
+	// powerful) access tokens.
+	if err := authz.CheckWriteScope(ctx); err != nil {
+		return nil, err
+	}
 
 	switch conf.AccessTokensAllow() {
 	case conf.AccessTokensAll:
@@ -46,18 +55,22 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 	}
 
 	// Validate scopes.
-	var hasUserAllScope bool
+	var userScopes int
 	seenScope := map[string]struct{}{}
 	sort.Strings(args.Scopes)
 	for _, scope := range args.Scopes {
-		switch scope {
-		case authz.ScopeUserAll:
-			hasUserAllScope = true
-		case authz.ScopeSiteAdminSudo:
+		switch {
+		case scope == authz.ScopeUserAll, scope == authz.ScopeUserRead, scope == authz.ScopeSearch:
+			userScopes++
+		case scope == authz.ScopeSiteAdminSudo:
 			// 🚨 SECURITY: Only site admins may create a token with the "site-admin:sudo" scope.
 			if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
 				return nil, err
 			}
+		case strings.HasPrefix(scope, authz.ScopeRepoPrefix):
+			if _, err := authz.ParseRepoScope(scope); err != nil {
+				return nil, err
+			}
 		default:
 			return nil, fmt.Errorf("unknown access token scope %q (valid scopes: %q)", scope, authz.AllScopes)
 		}
@@ -67,12 +80,38 @@ func (r *schemaResolver) CreateAccessToken(ctx context.Context, args *createAcce
 		}
 		seenScope[scope] = struct{}{}
 	}
-	if !hasUserAllScope {
-		return nil, fmt.Errorf("all access tokens must have scope %q", authz.ScopeUserAll)
+	if userScopes != 1 {
+		return nil, fmt.Errorf("all access tokens must have exactly one of the scopes %q", authz.UserScopes)
+	}
+	if _, ok := seenScope[authz.ScopeSiteAdminSudo]; ok {
+		if _, ok := seenScope[authz.ScopeUserAll]; !ok {
+			return nil, fmt.Errorf("access tokens with scope %q must also have scope %q", authz.ScopeSiteAdminSudo, authz.ScopeUserAll)
+		}
+	}
+
+	var expiresAt *time.Time
+	if args.ExpiresAt != nil {
+		t, err := time.Parse(time.RFC3339, *args.ExpiresAt)
+		if err != nil {
+			return nil, err
+		}
+		if !t.After(time.Now()) {
+			return nil, errors.New("access token expiry date must be in the future")
+		}
+		expiresAt = &t
 	}
 
-	id, token, err := db.AccessTokens.Create(ctx, userID, args.Scopes, args.Note, actor.FromContext(ctx).UID)
-	return &createAccessTokenResult{id: marshalAccessTokenID(id), token: token}, err
+	id, token, err := db.AccessTokens.Create(ctx, userID, args.Scopes, args.Note, actor.FromContext(ctx).UID, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	audit.Record(ctx, audit.Event{
+		Action:     audit.AccessTokenCreate,
+		TargetType: "AccessToken",
+		TargetID:   string(marshalAccessTokenID(id)),
+		Details:    map[string]interface{}{"subject": args.User, "scopes": args.Scopes, "note": args.Note},
+	})
+	return &createAccessTokenResult{id: marshalAccessTokenID(id), token: token}, nil
 }
 
 type createAccessTokenResult struct {
@@ -116,6 +155,12 @@ func (r *schemaResolver) DeleteAccessToken(ctx context.Context, args *deleteAcce
 		if err := db.AccessTokens.DeleteByID(ctx, token.ID, token.SubjectUserID); err != nil {
 			return nil, err
 		}
+		audit.Record(ctx, audit.Event{
+			Action:     audit.AccessTokenDelete,
+			TargetType: "AccessToken",
+			TargetID:   string(*args.ByID),
+			Details:    map[string]interface{}{"subject": marshalUserID(token.SubjectUserID)},
+		})
 
 	case args.ByToken != nil:
 		// 🚨 SECURITY: This is easier than the ByID case because anyone holding the access token's
@@ -123,6 +168,7 @@ func (r *schemaResolver) DeleteAccessToken(ctx context.Context, args *deleteAcce
 		if err := db.AccessTokens.DeleteByToken(ctx, *args.ByToken); err != nil {
 			return nil, err
 		}
+		audit.Record(ctx, audit.Event{Action: audit.AccessTokenDelete, TargetType: "AccessToken", Details: map[string]bool{"byToken": true}})
 	}
 	if err != nil {
 		return nil, err
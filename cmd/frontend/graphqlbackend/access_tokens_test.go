@@ -4,6 +4,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/gqltesting"
@@ -17,7 +18,7 @@ import (
 // 🚨 SECURITY: This tests that users can't create tokens for users they aren't allowed to do so for.
 func TestMutation_CreateAccessToken(t *testing.T) {
 	mockAccessTokensCreate := func(t *testing.T, wantCreatorUserID int32, wantScopes []string) {
-		db.Mocks.AccessTokens.Create = func(subjectUserID int32, scopes []string, note string, creatorUserID int32) (int64, string, error) {
+		db.Mocks.AccessTokens.Create = func(subjectUserID int32, scopes []string, note string, creatorUserID int32, expiresAt *time.Time) (int64, string, error) {
 			if want := int32(1); subjectUserID != want {
 				t.Errorf("got %v, want %v", subjectUserID, want)
 			}
@@ -76,6 +77,86 @@ func TestMutation_CreateAccessToken(t *testing.T) {
 		}
 	})
 
+	t.Run("authenticated as user, using invalid scope combinations", func(t *testing.T) {
+		resetMocks()
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		for _, scopes := range [][]string{
+			{authz.ScopeUserAll, authz.ScopeUserRead},
+			{authz.ScopeSearch, authz.ScopeUserRead},
+			{authz.ScopeUserRead, "repo:("},
+			{authz.ScopeUserRead, "repo:"},
+			{"repo:^github\\.com/"},
+		} {
+			result, err := (&schemaResolver{}).CreateAccessToken(ctx, &createAccessTokenInput{User: uid1GQLID, Scopes: scopes, Note: "n"})
+			if err == nil {
+				t.Errorf("scopes %q: err == nil", scopes)
+			}
+			if result != nil {
+				t.Errorf("scopes %q: got result %v, want nil", scopes, result)
+			}
+		}
+	})
+
+	t.Run("authenticated as user, using limited scopes with expiry", func(t *testing.T) {
+		resetMocks()
+		wantScopes := []string{`repo:^github\.com/foo/`, authz.ScopeUserRead}
+		expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+		var calledCreate bool
+		db.Mocks.AccessTokens.Create = func(subjectUserID int32, scopes []string, note string, creatorUserID int32, gotExpiresAt *time.Time) (int64, string, error) {
+			calledCreate = true
+			if !reflect.DeepEqual(scopes, wantScopes) {
+				t.Errorf("got %q, want %q", scopes, wantScopes)
+			}
+			if gotExpiresAt == nil || !gotExpiresAt.Equal(expiresAt) {
+				t.Errorf("got expiry %v, want %v", gotExpiresAt, expiresAt)
+			}
+			return 1, "t", nil
+		}
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		expiresAtStr := expiresAt.Format(time.RFC3339)
+		if _, err := (&schemaResolver{}).CreateAccessToken(ctx, &createAccessTokenInput{
+			User:      uid1GQLID,
+			Scopes:    []string{authz.ScopeUserRead, `repo:^github\.com/foo/`},
+			Note:      "n",
+			ExpiresAt: &expiresAtStr,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if !calledCreate {
+			t.Error("!calledCreate")
+		}
+
+		pastStr := time.Now().Add(-time.Hour).Format(time.RFC3339)
+		if _, err := (&schemaResolver{}).CreateAccessToken(ctx, &createAccessTokenInput{
+			User:      uid1GQLID,
+			Scopes:    []string{authz.ScopeUserRead},
+			Note:      "n",
+			ExpiresAt: &pastStr,
+		}); err == nil {
+			t.Error("want error creating access token that has already expired")
+		}
+	})
+
+	// 🚨 SECURITY: Tokens that can't make changes must not be able to create new tokens.
+	t.Run("authenticated with read-only access token", func(t *testing.T) {
+		resetMocks()
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1, Scopes: []string{authz.ScopeUserRead}})
+		result, err := (&schemaResolver{}).CreateAccessToken(ctx, &createAccessTokenInput{
+			User:   uid1GQLID,
+			Scopes: []string{authz.ScopeUserAll},
+			Note:   "n",
+		})
+		if _, ok := err.(*authz.ScopeError); !ok {
+			t.Errorf("got err %v, want *authz.ScopeError", err)
+		}
+		if result != nil {
+			t.Errorf("got result %v, want nil", result)
+		}
+	})
+
 	t.Run("authenticated as user, using site-admin-only scopes", func(t *testing.T) {
 		resetMocks()
 		db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
@@ -0,0 +1,159 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+func (r *siteResolver) AuditLog(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+	Actor      *graphql.ID
+	Action     *string
+	TargetType *string
+	TargetID   *string
+	Since      *string
+	Until      *string
+}) (*auditLogEntryConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins can view the audit log.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	var opt db.AuditLogListOptions
+	if args.Actor != nil {
+		var err error
+		opt.ActorUserID, err = UnmarshalUserID(*args.Actor)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if args.Action != nil {
+		opt.Action = *args.Action
+	}
+	if args.TargetType != nil {
+		opt.TargetType = *args.TargetType
+	}
+	if args.TargetID != nil {
+		opt.TargetID = *args.TargetID
+	}
+	for _, t := range []struct {
+		arg *string
+		opt **time.Time
+	}{{args.Since, &opt.Since}, {args.Until, &opt.Until}} {
+		if t.arg == nil {
+			continue
+		}
+		v, err := time.Parse(time.RFC3339, *t.arg)
+		if err != nil {
+			return nil, err
+		}
+		*t.opt = &v
+	}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &auditLogEntryConnectionResolver{opt: opt}, nil
+}
+
+// auditLogEntryConnectionResolver resolves a list of audit log entries.
+//
+// 🚨 SECURITY: When instantiating an auditLogEntryConnectionResolver value, the caller MUST check
+// that the actor is a site admin.
+type auditLogEntryConnectionResolver struct {
+	opt db.AuditLogListOptions
+
+	// cache results because they are used by multiple fields
+	once    sync.Once
+	entries []*db.AuditLogEntry
+	err     error
+}
+
+func (r *auditLogEntryConnectionResolver) compute(ctx context.Context) ([]*db.AuditLogEntry, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.LimitOffset != nil {
+			tmp := *opt2.LimitOffset
+			opt2.LimitOffset = &tmp
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+
+		r.entries, r.err = db.AuditLog.List(ctx, opt2)
+	})
+	return r.entries, r.err
+}
+
+func (r *auditLogEntryConnectionResolver) Nodes(ctx context.Context) ([]*auditLogEntryResolver, error) {
+	entries, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(entries) > r.opt.Limit {
+		entries = entries[:r.opt.Limit]
+	}
+
+	l := make([]*auditLogEntryResolver, len(entries))
+	for i, e := range entries {
+		l[i] = &auditLogEntryResolver{entry: e}
+	}
+	return l, nil
+}
+
+func (r *auditLogEntryConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := db.AuditLog.Count(ctx, r.opt)
+	return int32(count), err
+}
+
+func (r *auditLogEntryConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	entries, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(entries) > r.opt.Limit), nil
+}
+
+type auditLogEntryResolver struct {
+	entry *db.AuditLogEntry
+}
+
+func (r *auditLogEntryResolver) CreatedAt() string { return r.entry.CreatedAt.Format(time.RFC3339) }
+
+func (r *auditLogEntryResolver) Actor(ctx context.Context) (*UserResolver, error) {
+	if r.entry.ActorUserID == 0 {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, r.entry.ActorUserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil // the user was deleted
+	}
+	return user, err
+}
+
+func (r *auditLogEntryResolver) IP() *string { return nonEmpty(r.entry.IP) }
+
+func (r *auditLogEntryResolver) ForwardedFor() *string { return nonEmpty(r.entry.ForwardedFor) }
+
+func (r *auditLogEntryResolver) Action() string { return r.entry.Action }
+
+func (r *auditLogEntryResolver) TargetType() *string { return nonEmpty(r.entry.TargetType) }
+
+func (r *auditLogEntryResolver) TargetID() *string { return nonEmpty(r.entry.TargetID) }
+
+func (r *auditLogEntryResolver) Details() jsonValue {
+	if len(r.entry.Details) == 0 {
+		return jsonValue{value: map[string]interface{}{}}
+	}
+	return jsonValue{value: r.entry.Details}
+}
+
+// nonEmpty returns a pointer to s, or nil if s is empty.
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,123 @@
+package graphqlbackend
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+)
+
+func TestSite_AuditLog(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, Username: "alice"}, nil
+	}
+	var gotOpt db.AuditLogListOptions
+	db.Mocks.AuditLog.List = func(opt db.AuditLogListOptions) ([]*db.AuditLogEntry, error) {
+		gotOpt = opt
+		return []*db.AuditLogEntry{
+			{
+				ID:          2,
+				CreatedAt:   time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC),
+				ActorUserID: 1,
+				IP:          "10.0.0.1",
+				Action:      "user.set_site_admin",
+				TargetType:  "User",
+				TargetID:    "VXNlcjoy",
+				Details:     json.RawMessage(`{"siteAdmin":true}`),
+			},
+			{ID: 1, Action: "site_config.update"},
+		}, nil
+	}
+	db.Mocks.AuditLog.Count = func(opt db.AuditLogListOptions) (int, error) { return 2, nil }
+
+	t.Run("non-site-admin", func(t *testing.T) {
+		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{ID: 1}, nil
+		}
+		_, err := (&siteResolver{}).AuditLog(actor.WithActor(context.Background(), &actor.Actor{UID: 1}), &struct {
+			graphqlutil.ConnectionArgs
+			Actor      *graphql.ID
+			Action     *string
+			TargetType *string
+			TargetID   *string
+			Since      *string
+			Until      *string
+		}{})
+		if want := backend.ErrMustBeSiteAdmin; err != want {
+			t.Errorf("got error %v, want %v", err, want)
+		}
+	})
+
+	t.Run("site admin", func(t *testing.T) {
+		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{ID: 1, SiteAdmin: true}, nil
+		}
+		gqltesting.RunTests(t, []*gqltesting.Test{
+			{
+				Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+				Schema:  GraphQLSchema,
+				Query: `
+				{
+					site {
+						auditLog(first: 10, actor: "VXNlcjox", action: "user.set_site_admin") {
+							nodes {
+								createdAt
+								actor {
+									username
+								}
+								ip
+								action
+								targetType
+								targetID
+								details
+							}
+							totalCount
+						}
+					}
+				}
+			`,
+				ExpectedResult: `
+				{
+					"site": {
+						"auditLog": {
+							"nodes": [
+								{
+									"createdAt": "2019-01-02T03:04:05Z",
+									"actor": {"username": "alice"},
+									"ip": "10.0.0.1",
+									"action": "user.set_site_admin",
+									"targetType": "User",
+									"targetID": "VXNlcjoy",
+									"details": {"siteAdmin": true}
+								},
+								{
+									"createdAt": "0001-01-01T00:00:00Z",
+									"actor": null,
+									"ip": null,
+									"action": "site_config.update",
+									"targetType": null,
+									"targetID": null,
+									"details": {}
+								}
+							],
+							"totalCount": 2
+						}
+					}
+				}
+			`,
+			},
+		})
+		if gotOpt.ActorUserID != 1 || gotOpt.Action != "user.set_site_admin" || gotOpt.LimitOffset == nil || gotOpt.Limit != 11 {
+			t.Errorf("got options %+v, want actor 1, action, and limit 11", gotOpt)
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/discussions"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+func (r *discussionCommentResolver) Reactions(ctx context.Context) ([]*discussionCommentReactionGroupResolver, error) {
+	reactions, err := db.DiscussionCommentReactions.List(ctx, r.c.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "DiscussionCommentReactions.List")
+	}
+	var (
+		groups  []*discussionCommentReactionGroupResolver
+		byEmoji = map[string]*discussionCommentReactionGroupResolver{}
+	)
+	for _, reaction := range reactions {
+		group, ok := byEmoji[reaction.Reaction]
+		if !ok {
+			group = &discussionCommentReactionGroupResolver{reaction: reaction.Reaction}
+			byEmoji[reaction.Reaction] = group
+			groups = append(groups, group)
+		}
+		group.userIDs = append(group.userIDs, reaction.UserID)
+	}
+	return groups, nil
+}
+
+type discussionCommentReactionGroupResolver struct {
+	reaction string
+	userIDs  []int32
+}
+
+func (r *discussionCommentReactionGroupResolver) Reaction() string { return r.reaction }
+
+func (r *discussionCommentReactionGroupResolver) Count() int32 { return int32(len(r.userIDs)) }
+
+func (r *discussionCommentReactionGroupResolver) Users(ctx context.Context) ([]*UserResolver, error) {
+	users := make([]*UserResolver, 0, len(r.userIDs))
+	for _, userID := range r.userIDs {
+		user, err := UserByIDInt32(ctx, userID)
+		if errcode.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+func (r *discussionCommentReactionGroupResolver) ViewerHasReacted(ctx context.Context) (bool, error) {
+	currentUser, err := CurrentUser(ctx)
+	if err != nil || currentUser == nil {
+		return false, err
+	}
+	for _, userID := range r.userIDs {
+		if userID == currentUser.user.ID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *discussionsMutationResolver) AddReactionToComment(ctx context.Context, args *struct {
+	CommentID graphql.ID
+	Reaction  string
+}) (*discussionCommentResolver, error) {
+	return updateDiscussionCommentReaction(ctx, args.CommentID, args.Reaction, db.DiscussionCommentReactions.Add)
+}
+
+func (r *discussionsMutationResolver) RemoveReactionFromComment(ctx context.Context, args *struct {
+	CommentID graphql.ID
+	Reaction  string
+}) (*discussionCommentResolver, error) {
+	return updateDiscussionCommentReaction(ctx, args.CommentID, args.Reaction, db.DiscussionCommentReactions.Remove)
+}
+
+func updateDiscussionCommentReaction(ctx context.Context, id graphql.ID, reaction string, update func(ctx context.Context, commentID int64, userID int32, reaction string) error) (*discussionCommentResolver, error) {
+	// 🚨 SECURITY: Only signed in users may react to a discussion comment, and
+	// only on their own behalf.
+	currentUser, err := CurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if currentUser == nil {
+		return nil, errors.New("no current user")
+	}
+	if !discussions.IsValidReaction(reaction) {
+		return nil, errors.Errorf("invalid reaction %q", reaction)
+	}
+
+	commentID, err := unmarshalDiscussionID(id)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := db.DiscussionComments.Get(ctx, commentID)
+	if err != nil {
+		return nil, errors.Wrap(err, "DiscussionComments.Get")
+	}
+	if err := update(ctx, comment.ID, currentUser.user.ID, reaction); err != nil {
+		return nil, err
+	}
+	return &discussionCommentResolver{c: comment}, nil
+}
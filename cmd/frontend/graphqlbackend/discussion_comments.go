@@ -66,6 +66,16 @@ func (r *discussionCommentResolver) InlineURL(ctx context.Context) (*string, err
 	}
 	return strptr(url.String()), nil
 }
+func (r *discussionCommentResolver) ImportedFromURL(ctx context.Context) (*string, error) {
+	imported, err := db.DiscussionImportedComments.GetByCommentID(ctx, r.c.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "DiscussionImportedComments.GetByCommentID")
+	}
+	if imported == nil {
+		return nil, nil
+	}
+	return &imported.URL, nil
+}
 func (r *discussionCommentResolver) CreatedAt(ctx context.Context) string {
 	return r.c.CreatedAt.Format(time.RFC3339)
 }
@@ -2,7 +2,6 @@ package graphqlbackend
 
 import (
 	"context"
-	"fmt"
 	"strconv"
 	"strings"
 	"sync"
@@ -14,10 +13,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/discussions"
-	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/discussions/ratelimit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
-	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
 	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
@@ -228,13 +226,6 @@ func (r *discussionsMutationResolver) CreateThread(ctx context.Context, args *st
 	if currentUser == nil {
 		return nil, errors.New("no current user")
 	}
-	if dc := conf.Get().Discussions; dc != nil && dc.AbuseProtection {
-		if mustWait := ratelimit.TimeUntilUserCanCreateThread(ctx, currentUser.user.ID, *args.Input.Title, args.Input.Contents); mustWait != 0 {
-			return nil, fmt.Errorf("You are creating threads too quickly. You may create a new one after %v", mustWait.Round(time.Second))
-		}
-	}
-
-	// Create the thread.
 	newThread := &types.DiscussionThread{
 		AuthorUserID: currentUser.user.ID,
 		Title:        *args.Input.Title,
@@ -248,22 +239,10 @@ func (r *discussionsMutationResolver) CreateThread(ctx context.Context, args *st
 			return nil, err
 		}
 	}
-	thread, err := db.DiscussionThreads.Create(ctx, newThread)
-	if err != nil {
-		return nil, errors.Wrap(err, "DiscussionThreads.Create")
-	}
-
-	// Create the first comment in the thread.
-	newComment := &types.DiscussionComment{
-		ThreadID:     newThread.ID,
-		AuthorUserID: currentUser.user.ID,
-		Contents:     args.Input.Contents,
-	}
-	_, err = db.DiscussionComments.Create(ctx, newComment)
+	thread, err := discussions.InsecureCreateThread(ctx, newThread, args.Input.Contents)
 	if err != nil {
-		return nil, errors.Wrap(err, "DiscussionComments.Create")
+		return nil, err
 	}
-	discussions.NotifyNewThread(newThread, newComment)
 	return &discussionThreadResolver{t: thread}, nil
 }
 
@@ -271,6 +250,7 @@ func (r *discussionsMutationResolver) UpdateThread(ctx context.Context, args *st
 	Input *struct {
 		ThreadID graphql.ID
 		Archive  *bool
+		Resolve  *bool
 		Delete   *bool
 	}
 }) (*discussionThreadResolver, error) {
@@ -297,8 +277,10 @@ func (r *discussionsMutationResolver) UpdateThread(ctx context.Context, args *st
 		return nil, err
 	}
 	thread, err := db.DiscussionThreads.Update(ctx, threadID, &db.DiscussionThreadsUpdateOptions{
-		Archive: args.Input.Archive,
-		Delete:  delete,
+		Archive:          args.Input.Archive,
+		Resolve:          args.Input.Resolve,
+		ResolvedByUserID: currentUser.user.ID,
+		Delete:           delete,
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "DiscussionThreads.Update")
@@ -310,6 +292,34 @@ func (r *discussionsMutationResolver) UpdateThread(ctx context.Context, args *st
 	return &discussionThreadResolver{t: thread}, nil
 }
 
+func (r *discussionsMutationResolver) UpdateThreadSubscription(ctx context.Context, args *struct {
+	ThreadID   graphql.ID
+	Subscribed bool
+}) (*discussionThreadResolver, error) {
+	// 🚨 SECURITY: Only signed in users may subscribe to a discussion thread,
+	// and only on their own behalf.
+	currentUser, err := CurrentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if currentUser == nil {
+		return nil, errors.New("no current user")
+	}
+
+	threadID, err := unmarshalDiscussionID(args.ThreadID)
+	if err != nil {
+		return nil, err
+	}
+	thread, err := db.DiscussionThreads.Get(ctx, threadID)
+	if err != nil {
+		return nil, errors.Wrap(err, "DiscussionThreads.Get")
+	}
+	if err := db.DiscussionThreadSubscriptions.Set(ctx, thread.ID, currentUser.user.ID, args.Subscribed); err != nil {
+		return nil, errors.Wrap(err, "DiscussionThreadSubscriptions.Set")
+	}
+	return &discussionThreadResolver{t: thread}, nil
+}
+
 func (*schemaResolver) Discussions(ctx context.Context) (*discussionsMutationResolver, error) {
 	if err := viewerCanUseDiscussions(ctx); err != nil {
 		return nil, err
@@ -326,6 +336,7 @@ func (*schemaResolver) DiscussionThreads(ctx context.Context, args *struct {
 	TargetRepositoryName        *string
 	TargetRepositoryGitCloneURL *string
 	TargetRepositoryPath        *string
+	TargetRepositoryRevision    *string
 }) (*discussionThreadsConnectionResolver, error) {
 	if err := viewerCanUseDiscussions(ctx); err != nil {
 		return nil, err
@@ -377,7 +388,18 @@ func (*schemaResolver) DiscussionThreads(ctx context.Context, args *struct {
 	} else if count > 1 {
 		return nil, errors.New("only one of targetRepositoryID, targetRepositoryName, or targetRepositoryGitCloneURL can be specified")
 	}
-	return &discussionThreadsConnectionResolver{opt: opt}, nil
+
+	var relativePath *discussionThreadsRelativePathFilter
+	if args.TargetRepositoryRevision != nil {
+		if opt.TargetRepoID == nil || args.TargetRepositoryPath == nil {
+			return nil, errors.New("targetRepositoryRevision requires a target repository and targetRepositoryPath")
+		}
+		// The threads' paths are matched relative to the revision (after
+		// listing all of the repository's threads), not as stored.
+		relativePath = &discussionThreadsRelativePathFilter{rev: *args.TargetRepositoryRevision, path: *args.TargetRepositoryPath}
+		opt.TargetRepoPath = nil
+	}
+	return &discussionThreadsConnectionResolver{opt: opt, relativePath: relativePath}, nil
 }
 
 type discussionThreadTargetRepoSelectionResolver struct {
@@ -433,192 +455,21 @@ func (r *discussionThreadTargetRepoResolver) Selection() *discussionThreadTarget
 func (r *discussionThreadTargetRepoResolver) RelativePath(ctx context.Context, args *struct {
 	Rev string
 }) (*string, error) {
-	if r.t.Path == nil {
-		return nil, nil
-	}
-	repo, err := repositoryByIDInt32(ctx, r.t.RepoID)
-	if err != nil {
-		return nil, err
-	}
-	if r.t.Revision == nil && r.t.Branch == nil {
-		// The thread wasn't created on a specific revision or branch, so we
-		// cannot walk the history. Instead, we must assume its location and
-		// check in the relative revision.
-		commit, err := repo.Commit(ctx, &repositoryCommitArgs{Rev: args.Rev})
-		if err != nil {
-			return nil, err
-		}
-		_, err = commit.File(ctx, &struct{ Path string }{Path: *r.t.Path})
-		if err != nil {
-			// File does not exist in this revision.
-			return nil, nil
-		}
-		return r.t.Path, nil // File exists at that path.
-	}
-
-	var rev string
-	if r.t.Revision != nil {
-		rev = *r.t.Revision
-	} else if r.t.Branch != nil {
-		rev = *r.t.Branch
-	}
-	comparison, err := repo.Comparison(ctx, &repositoryComparisonInput{
-		Base: &rev,
-		Head: &args.Rev,
-	})
+	rel, err := r.RelativeTo(ctx, args)
 	if err != nil {
 		return nil, err
 	}
-	currentPath := *r.t.Path
-	fileDiffs, err := comparison.FileDiffs(&struct{ First *int32 }{}).Nodes(ctx)
-	if err != nil {
-		return nil, err
-	}
-	for _, fileDiff := range fileDiffs {
-		oldPath := fileDiff.OldPath()
-		newPath := fileDiff.NewPath()
-
-		if oldPath == nil && newPath != nil {
-			// newPath was added. We don't need to do anything because this
-			// could only indicate the file we're tracking was added.
-		} else if oldPath != nil && newPath == nil {
-			// oldPath was removed
-			if currentPath == *oldPath {
-				// The file we are tracking was removed!
-				return nil, nil
-			}
-		} else if oldPath != nil && newPath != nil {
-			// oldPath was renamed to newPath
-			if currentPath == *oldPath {
-				// The file we are tracking was renamed.
-				currentPath = *newPath
-			}
-		}
-	}
-	return &currentPath, nil
-}
-
-type discussionSelectionRangeResolver struct {
-	startLine, startCharacter, endLine, endCharacter int32
-}
-
-func (r *discussionSelectionRangeResolver) StartLine() int32      { return r.startLine }
-func (r *discussionSelectionRangeResolver) StartCharacter() int32 { return r.startCharacter }
-func (r *discussionSelectionRangeResolver) EndLine() int32        { return r.endLine }
-func (r *discussionSelectionRangeResolver) EndCharacter() int32   { return r.endCharacter }
-
-func discussionSelectionRelativeTo(oldSel *types.DiscussionThreadTargetRepo, newContent string) *discussionSelectionRangeResolver {
-	mustFindLines := 4
-
-	search := func(searchForLines string) *discussionSelectionRangeResolver {
-		if len(strings.Split(searchForLines, "\n")) < mustFindLines {
-			// We do not have enough search lines to find a good match.
-			return nil
-		}
-		matches := strings.Count(newContent, searchForLines)
-		switch {
-		case matches > 1:
-			// The lines we are searching for produced too many matches.
-			return nil
-		case matches == 1:
-			// We found a perfect match.
-			idx := strings.Index(newContent, searchForLines)
-			startLine := int32(len(strings.Split(newContent[:idx], "\n")))
-			return &discussionSelectionRangeResolver{
-				startCharacter: *oldSel.StartCharacter,
-				endCharacter:   *oldSel.EndCharacter,
-				startLine:      startLine,
-				endLine:        startLine + int32(len(*oldSel.Lines)),
-			}
-		default:
-			return nil
-		}
-	}
-
-	// Start removing lines until we find a result (or fail to find one).
-	allLines := *oldSel.LinesBefore
-	allLines = append(allLines, *oldSel.Lines...)
-	allLines = append(allLines, *oldSel.LinesAfter...)
-	removeLines := 0
-	for {
-		if removeLines > len(allLines) {
-			return nil
-		}
-		// Try removing N lines from the top.
-		if r := search(strings.Join(allLines[removeLines:], "\n")); r != nil {
-			offset := int32(len(*oldSel.LinesBefore) - 1 - removeLines)
-			r.startLine += offset
-			r.endLine += offset
-			return r
-		}
-
-		// Try removing N lines from the bottom.
-		if r := search(strings.Join(allLines[:len(allLines)-removeLines], "\n")); r != nil {
-			offset := int32(len(*oldSel.LinesAfter) - 1 - removeLines)
-			r.startLine += offset
-			r.endLine += offset
-			return r
-		}
-		removeLines++
-	}
+	return rel.path, nil
 }
 
 func (r *discussionThreadTargetRepoResolver) RelativeSelection(ctx context.Context, args *struct {
 	Rev string
 }) (*discussionSelectionRangeResolver, error) {
-	if !r.t.HasSelection() {
-		return nil, nil
-	}
-	path, err := r.RelativePath(ctx, args)
-	if err != nil {
-		return nil, err
-	}
-	if path == nil {
-		return nil, nil
-	}
-	repo, err := repositoryByIDInt32(ctx, r.t.RepoID)
-	if err != nil {
-		return nil, err
-	}
-	commit, err := repo.Commit(ctx, &repositoryCommitArgs{Rev: args.Rev})
+	rel, err := r.RelativeTo(ctx, args)
 	if err != nil {
 		return nil, err
 	}
-	oldSel := &discussionSelectionRangeResolver{
-		startLine:      *r.t.StartLine,
-		startCharacter: *r.t.StartCharacter,
-		endLine:        *r.t.EndLine,
-		endCharacter:   *r.t.EndCharacter,
-	}
-	if oid, _ := commit.OID(); r.t.Revision != nil && *r.t.Revision == string(oid) {
-		return oldSel, nil // nothing to do (requested relative revision is identical to the stored revision)
-	}
-	if r.t.Branch != nil {
-		branchCommit, err := repo.Commit(ctx, &repositoryCommitArgs{Rev: *r.t.Branch})
-		if err != nil {
-			return nil, err
-		}
-		bOid, err := branchCommit.OID()
-		if err != nil {
-			return nil, err
-		}
-		oid, err := commit.OID()
-		if err != nil {
-			return nil, err
-		}
-		if bOid == oid {
-			return oldSel, nil // nothing to do (requested relative revision is identical to the stored branch revision)
-		}
-	}
-	file, err := commit.File(ctx, &struct{ Path string }{Path: *path})
-	if err != nil {
-		return nil, err
-	}
-	newContent, err := file.Content(ctx)
-	if err != nil {
-		return nil, err
-	}
-	return discussionSelectionRelativeTo(r.t, newContent), nil
+	return rel.selection, nil
 }
 
 type discussionThreadTargetResolver struct {
@@ -675,6 +526,32 @@ func (d *discussionThreadResolver) ArchivedAt(ctx context.Context) *string {
 	return strptr(d.t.ArchivedAt.Format(time.RFC3339))
 }
 
+func (d *discussionThreadResolver) ResolvedAt(ctx context.Context) *string {
+	if d.t.ResolvedAt == nil {
+		return nil
+	}
+	return strptr(d.t.ResolvedAt.Format(time.RFC3339))
+}
+
+func (d *discussionThreadResolver) ResolvedBy(ctx context.Context) (*UserResolver, error) {
+	if d.t.ResolvedByUserID == nil {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, *d.t.ResolvedByUserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil // deleted user
+	}
+	return user, err
+}
+
+func (d *discussionThreadResolver) ViewerIsSubscribed(ctx context.Context) (bool, error) {
+	currentUser, err := CurrentUser(ctx)
+	if err != nil || currentUser == nil {
+		return false, err
+	}
+	return discussions.IsSubscribed(ctx, d.t, currentUser.user)
+}
+
 func (d *discussionThreadResolver) Comments(ctx context.Context, args *struct {
 	graphqlutil.ConnectionArgs
 }) *discussionCommentsConnectionResolver {
@@ -695,15 +572,45 @@ func (d *discussionThreadResolver) Comments(ctx context.Context, args *struct {
 type discussionThreadsConnectionResolver struct {
 	opt *db.DiscussionThreadsListOptions
 
+	// relativePath, if set, filters the threads by their path relative to a
+	// revision. The filtering happens after listing all threads that match
+	// opt, so opt.LimitOffset is applied to the filtered list.
+	relativePath *discussionThreadsRelativePathFilter
+
 	// cache results because they are used by multiple fields
 	once     sync.Once
 	comments []*types.DiscussionThread
+	total    int // only set if relativePath != nil
 	err      error
 }
 
 func (r *discussionThreadsConnectionResolver) compute(ctx context.Context) ([]*types.DiscussionThread, error) {
 	r.once.Do(func() {
 		opt2 := *r.opt
+		if r.relativePath != nil {
+			opt2.LimitOffset = nil
+			threads, err := db.DiscussionThreads.List(ctx, &opt2)
+			if err != nil {
+				r.err = err
+				return
+			}
+			if threads, r.err = r.relativePath.filter(ctx, threads); r.err != nil {
+				return
+			}
+			r.total = len(threads)
+			if lo := r.opt.LimitOffset; lo != nil {
+				if lo.Offset < len(threads) {
+					threads = threads[lo.Offset:]
+				} else {
+					threads = nil
+				}
+				if len(threads) > lo.Limit+1 {
+					threads = threads[:lo.Limit+1] // +1 so we can detect if there is a next page
+				}
+			}
+			r.comments = threads
+			return
+		}
 		if opt2.LimitOffset != nil {
 			tmp := *opt2.LimitOffset
 			opt2.LimitOffset = &tmp
@@ -728,6 +635,10 @@ func (r *discussionThreadsConnectionResolver) Nodes(ctx context.Context) ([]*dis
 }
 
 func (r *discussionThreadsConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	if r.relativePath != nil {
+		_, err := r.compute(ctx)
+		return int32(r.total), err
+	}
 	withoutLimit := *r.opt
 	withoutLimit.LimitOffset = nil
 	count, err := db.DiscussionThreads.Count(ctx, &withoutLimit)
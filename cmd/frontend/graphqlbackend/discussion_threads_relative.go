@@ -0,0 +1,244 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sourcegraph/go-diff/diff"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/discussions"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/vcs/git"
+)
+
+// discussionThreadTargetRepoRelativeLocationResolver resolves the location of a
+// discussion thread's repository target relative to a Git revision.
+type discussionThreadTargetRepoRelativeLocationResolver struct {
+	commit    *gitCommitResolver
+	path      *string
+	selection *discussionSelectionRangeResolver
+	outdated  bool
+}
+
+func (r *discussionThreadTargetRepoRelativeLocationResolver) Commit() *gitCommitResolver {
+	return r.commit
+}
+func (r *discussionThreadTargetRepoRelativeLocationResolver) Path() *string { return r.path }
+func (r *discussionThreadTargetRepoRelativeLocationResolver) Selection() *discussionSelectionRangeResolver {
+	return r.selection
+}
+func (r *discussionThreadTargetRepoRelativeLocationResolver) IsOutdated() bool { return r.outdated }
+
+func (r *discussionThreadTargetRepoResolver) RelativeTo(ctx context.Context, args *struct {
+	Rev string
+}) (*discussionThreadTargetRepoRelativeLocationResolver, error) {
+	return discussionThreadTargetRepoRelativeTo(ctx, r.t, args.Rev, discussionFileDiffsCache{})
+}
+
+// discussionFileDiffsCache caches the file diffs between the revision a thread
+// was created on and the revision it is resolved relative to, because threads
+// on the same file were often created on the same revision. The key is the
+// base and head revision.
+type discussionFileDiffsCache map[[2]string][]*diff.FileDiff
+
+func (c discussionFileDiffsCache) get(ctx context.Context, repo *repositoryResolver, base, head string) ([]*diff.FileDiff, error) {
+	key := [2]string{base, head}
+	if fileDiffs, ok := c[key]; ok {
+		return fileDiffs, nil
+	}
+	comparison, err := repo.Comparison(ctx, &repositoryComparisonInput{Base: &base, Head: &head})
+	if err != nil {
+		return nil, err
+	}
+	fileDiffs, err := comparison.FileDiffs(&struct{ First *int32 }{}).compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c[key] = fileDiffs
+	return fileDiffs, nil
+}
+
+// discussionThreadTargetRepoRelativeTo computes where the thread's target is
+// in the given revision.
+//
+// If the thread was created on a known revision (or branch), its file is
+// followed through renames and its selection is moved through the diff hunks
+// between that revision and the given revision (see discussions.Reanchor).
+// Otherwise, the thread's file is assumed to be at the same path and its
+// selection is searched for in the file. If the revision the thread was created
+// on no longer exists (e.g., because its branch was deleted or force-pushed),
+// the thread is outdated.
+func discussionThreadTargetRepoRelativeTo(ctx context.Context, t *types.DiscussionThreadTargetRepo, rev string, cache discussionFileDiffsCache) (*discussionThreadTargetRepoRelativeLocationResolver, error) {
+	repo, err := repositoryByIDInt32(ctx, t.RepoID)
+	if err != nil {
+		return nil, err
+	}
+	commit, err := repo.Commit(ctx, &repositoryCommitArgs{Rev: rev})
+	if err != nil {
+		return nil, err
+	}
+	if commit == nil {
+		return nil, fmt.Errorf("revision not found: %q", rev)
+	}
+	result := &discussionThreadTargetRepoRelativeLocationResolver{commit: commit}
+	if t.Path == nil {
+		return result, nil // the thread is about the repository generally
+	}
+
+	var baseRev string
+	if t.Revision != nil {
+		baseRev = *t.Revision
+	} else if t.Branch != nil {
+		baseRev = *t.Branch
+	}
+
+	path := *t.Path
+	var hunks []*diff.Hunk
+	if baseRev == "" {
+		// The thread wasn't created on a specific revision or branch, so we
+		// cannot walk the history. Instead, we must assume its location and
+		// check in the relative revision.
+		if _, err := commit.File(ctx, &struct{ Path string }{Path: path}); err != nil {
+			result.outdated = true // file does not exist in this revision
+			return result, nil
+		}
+	} else {
+		fileDiffs, err := cache.get(ctx, repo, baseRev, rev)
+		if git.IsRevisionNotFound(err) {
+			result.path = &path
+			result.outdated = true
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		fileDiff, deleted := discussionFindFileDiff(fileDiffs, path)
+		if deleted {
+			result.outdated = true
+			return result, nil
+		}
+		hunks = []*diff.Hunk{} // the file is unchanged
+		if fileDiff != nil {
+			path = fileDiff.NewName
+			hunks = fileDiff.Hunks
+		}
+	}
+	result.path = &path
+
+	if !t.HasSelection() {
+		return result, nil
+	}
+	if len(hunks) == 0 && hunks != nil {
+		result.selection = &discussionSelectionRangeResolver{
+			startLine:      *t.StartLine,
+			startCharacter: *t.StartCharacter,
+			endLine:        *t.EndLine,
+			endCharacter:   *t.EndCharacter,
+		}
+		return result, nil
+	}
+	file, err := commit.File(ctx, &struct{ Path string }{Path: path})
+	if err != nil {
+		return nil, err
+	}
+	newContent, err := file.Content(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.selection = discussionSelectionRelativeTo(t, hunks, newContent)
+	result.outdated = result.selection == nil
+	return result, nil
+}
+
+// discussionFindFileDiff returns the diff of the file at the given path (in
+// the diff's original revision), or nil if the file is unchanged. It reports
+// whether the file was deleted. Copies of the file are ignored (the thread
+// stays on the original file).
+func discussionFindFileDiff(fileDiffs []*diff.FileDiff, path string) (fileDiff *diff.FileDiff, deleted bool) {
+	for _, fileDiff := range fileDiffs {
+		oldPath := diffPathOrNull(fileDiff.OrigName)
+		if oldPath == nil || *oldPath != path || isCopyFileDiff(fileDiff) {
+			continue
+		}
+		if diffPathOrNull(fileDiff.NewName) == nil {
+			return nil, true
+		}
+		return fileDiff, false
+	}
+	return nil, false
+}
+
+func isCopyFileDiff(fileDiff *diff.FileDiff) bool {
+	for _, line := range fileDiff.Extended {
+		if strings.HasPrefix(line, "copy from ") {
+			return true
+		}
+	}
+	return false
+}
+
+// discussionThreadsRelativePathFilter filters a list of discussion threads to
+// those whose target is at a path in a revision, following renames of the
+// threads' files since the threads were created.
+type discussionThreadsRelativePathFilter struct {
+	rev  string
+	path string // if it ends with "/**", any path below it is matched
+}
+
+func (f *discussionThreadsRelativePathFilter) filter(ctx context.Context, threads []*types.DiscussionThread) ([]*types.DiscussionThread, error) {
+	cache := discussionFileDiffsCache{}
+	var keep []*types.DiscussionThread
+	for _, thread := range threads {
+		if thread.TargetRepo == nil || thread.TargetRepo.Path == nil {
+			continue
+		}
+		rel, err := discussionThreadTargetRepoRelativeTo(ctx, thread.TargetRepo, f.rev, cache)
+		if err != nil {
+			return nil, err
+		}
+		if rel.path != nil && f.match(*rel.path) {
+			keep = append(keep, thread)
+		}
+	}
+	return keep, nil
+}
+
+func (f *discussionThreadsRelativePathFilter) match(path string) bool {
+	if strings.HasSuffix(f.path, "/**") {
+		return strings.HasPrefix(path, strings.TrimSuffix(f.path, "**"))
+	}
+	return path == f.path
+}
+
+type discussionSelectionRangeResolver struct {
+	startLine, startCharacter, endLine, endCharacter int32
+}
+
+func (r *discussionSelectionRangeResolver) StartLine() int32      { return r.startLine }
+func (r *discussionSelectionRangeResolver) StartCharacter() int32 { return r.startCharacter }
+func (r *discussionSelectionRangeResolver) EndLine() int32        { return r.endLine }
+func (r *discussionSelectionRangeResolver) EndCharacter() int32   { return r.endCharacter }
+
+// discussionSelectionRelativeTo returns the thread's selection in newContent,
+// or nil if it can't be found. The hunks are the diff hunks of the file since
+// the revision the thread was created on (or nil if the revision is unknown).
+func discussionSelectionRelativeTo(oldSel *types.DiscussionThreadTargetRepo, hunks []*diff.Hunk, newContent string) *discussionSelectionRangeResolver {
+	r := discussions.Reanchor(discussions.Anchor{
+		LineRange: discussions.LineRange{
+			StartLine: int(*oldSel.StartLine),
+			EndLine:   int(*oldSel.EndLine),
+		},
+		LinesBefore: *oldSel.LinesBefore,
+		Lines:       *oldSel.Lines,
+		LinesAfter:  *oldSel.LinesAfter,
+	}, hunks, newContent)
+	if r == nil {
+		return nil
+	}
+	return &discussionSelectionRangeResolver{
+		startLine:      int32(r.StartLine),
+		startCharacter: *oldSel.StartCharacter,
+		endLine:        int32(r.EndLine),
+		endCharacter:   *oldSel.EndCharacter,
+	}
+}
@@ -0,0 +1,85 @@
+package graphqlbackend
+
+import (
+	"context"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/discussions"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+)
+
+func (r *repositoryResolver) ViewerDiscussionsEmailAddress(ctx context.Context) (*string, error) {
+	if !conf.CanReadEmail() {
+		return nil, nil
+	}
+	currentUser, err := CurrentUser(ctx)
+	if err != nil || currentUser == nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Anyone with the address can create threads in this
+	// repository as the viewer, so it is only ever given to the viewer. Only
+	// signed in users with a verified email may create threads.
+	if _, err := checkSignedInAndEmailVerified(ctx); err != nil {
+		return nil, err
+	}
+	token, err := db.DiscussionMailNewThreadTokens.GetByUserAndRepo(ctx, currentUser.user.ID, r.repo.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "DiscussionMailNewThreadTokens.GetByUserAndRepo")
+	}
+	if token == "" {
+		return nil, nil // not yet created (see CreateRepositoryEmailAddress)
+	}
+	address := discussions.MailSubAddress(token)
+	return &address, nil
+}
+
+func (r *discussionsMutationResolver) CreateRepositoryEmailAddress(ctx context.Context, args *struct {
+	Repository graphql.ID
+}) (string, error) {
+	if !conf.CanReadEmail() {
+		return "", errors.New("email.imap must be configured to create discussion threads by email")
+	}
+
+	// 🚨 SECURITY: Anyone with the address can create threads in the
+	// repository as the current user, so it is only ever given to them. Only
+	// signed in users with a verified email may create threads, and the
+	// repository must be visible to them (repositoryByID checks this).
+	currentUser, err := checkSignedInAndEmailVerified(ctx)
+	if err != nil {
+		return "", err
+	}
+	repo, err := repositoryByID(ctx, args.Repository)
+	if err != nil {
+		return "", err
+	}
+	token, err := db.DiscussionMailNewThreadTokens.Generate(ctx, currentUser.user.ID, repo.repo.ID)
+	if err != nil {
+		return "", errors.Wrap(err, "DiscussionMailNewThreadTokens.Generate")
+	}
+	return discussions.MailSubAddress(token), nil
+}
+
+func (r *discussionsMutationResolver) ResetRepositoryEmailAddress(ctx context.Context, args *struct {
+	Repository graphql.ID
+}) (string, error) {
+	// 🚨 SECURITY: Only signed in users may reset their own address. The
+	// repository must be visible to them (repositoryByID checks this).
+	currentUser, err := CurrentUser(ctx)
+	if err != nil {
+		return "", err
+	}
+	if currentUser == nil {
+		return "", errors.New("no current user")
+	}
+	repo, err := repositoryByID(ctx, args.Repository)
+	if err != nil {
+		return "", err
+	}
+	if err := db.DiscussionMailNewThreadTokens.Revoke(ctx, currentUser.user.ID, repo.repo.ID); err != nil {
+		return "", errors.Wrap(err, "DiscussionMailNewThreadTokens.Revoke")
+	}
+	return r.CreateRepositoryEmailAddress(ctx, args)
+}
@@ -0,0 +1,123 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+)
+
+func (r *siteResolver) EmailDeliveries(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+	State *string
+}) (*emailDeliveryConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins can list the emails sent by the site (which contain users'
+	// email addresses and notification contents).
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	var opt db.EmailOutboxListOptions
+	if args.State != nil {
+		opt.State = strings.ToLower(*args.State)
+	}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &emailDeliveryConnectionResolver{opt: opt}, nil
+}
+
+// emailDeliveryConnectionResolver resolves a list of emails in the email outbox.
+//
+// 🚨 SECURITY: When instantiating an emailDeliveryConnectionResolver value, the caller MUST check
+// that the actor is a site admin.
+type emailDeliveryConnectionResolver struct {
+	opt db.EmailOutboxListOptions
+
+	// cache results because they are used by multiple fields
+	once   sync.Once
+	emails []*db.OutboxEmail
+	err    error
+}
+
+func (r *emailDeliveryConnectionResolver) compute(ctx context.Context) ([]*db.OutboxEmail, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.LimitOffset != nil {
+			tmp := *opt2.LimitOffset
+			opt2.LimitOffset = &tmp
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+
+		r.emails, r.err = db.EmailOutbox.List(ctx, opt2)
+	})
+	return r.emails, r.err
+}
+
+func (r *emailDeliveryConnectionResolver) Nodes(ctx context.Context) ([]*emailDeliveryResolver, error) {
+	emails, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(emails) > r.opt.Limit {
+		emails = emails[:r.opt.Limit]
+	}
+
+	l := make([]*emailDeliveryResolver, len(emails))
+	for i, e := range emails {
+		l[i] = &emailDeliveryResolver{email: e}
+	}
+	return l, nil
+}
+
+func (r *emailDeliveryConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := db.EmailOutbox.Count(ctx, r.opt)
+	return int32(count), err
+}
+
+func (r *emailDeliveryConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	emails, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(emails) > r.opt.Limit), nil
+}
+
+type emailDeliveryResolver struct {
+	email *db.OutboxEmail
+}
+
+func (r *emailDeliveryResolver) ID() string { return strconv.FormatInt(r.email.ID, 10) }
+
+func (r *emailDeliveryResolver) To() string { return r.email.ToAddress }
+
+func (r *emailDeliveryResolver) Subject() string { return r.email.Subject }
+
+func (r *emailDeliveryResolver) State() string { return strings.ToUpper(r.email.State) }
+
+func (r *emailDeliveryResolver) Attempts() int32 { return r.email.Attempts }
+
+func (r *emailDeliveryResolver) LastAttemptAt() *string {
+	if r.email.LastAttemptAt == nil {
+		return nil
+	}
+	s := r.email.LastAttemptAt.Format(time.RFC3339)
+	return &s
+}
+
+func (r *emailDeliveryResolver) NextAttemptAt() *string {
+	if r.email.State != db.OutboxEmailStatePending {
+		return nil
+	}
+	s := r.email.NextAttemptAt.Format(time.RFC3339)
+	return &s
+}
+
+func (r *emailDeliveryResolver) Error() *string { return r.email.Error }
+
+func (r *emailDeliveryResolver) CreatedAt() string {
+	return r.email.CreatedAt.Format(time.RFC3339)
+}
@@ -0,0 +1,232 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+type explicitRepositoryPermissionsResolver struct {
+	perm *db.ExplicitRepoPermission
+}
+
+const explicitRepositoryPermissionsIDKind = "ExplicitRepositoryPermissions"
+
+func explicitRepositoryPermissionsByID(ctx context.Context, id graphql.ID) (*explicitRepositoryPermissionsResolver, error) {
+	// 🚨 SECURITY: Only site admins are allowed to read explicit repository permissions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	permID, err := unmarshalExplicitRepositoryPermissionsID(id)
+	if err != nil {
+		return nil, err
+	}
+	perm, err := db.ExplicitRepoPermissions.GetByID(ctx, permID)
+	if err != nil {
+		return nil, err
+	}
+	return &explicitRepositoryPermissionsResolver{perm: perm}, nil
+}
+
+func marshalExplicitRepositoryPermissionsID(id int32) graphql.ID {
+	return relay.MarshalID(explicitRepositoryPermissionsIDKind, id)
+}
+
+func unmarshalExplicitRepositoryPermissionsID(id graphql.ID) (permID int32, err error) {
+	if kind := relay.UnmarshalKind(id); kind != explicitRepositoryPermissionsIDKind {
+		err = fmt.Errorf("expected graphql ID to have kind %q; got %q", explicitRepositoryPermissionsIDKind, kind)
+		return
+	}
+	err = relay.UnmarshalSpec(id, &permID)
+	return
+}
+
+func (r *explicitRepositoryPermissionsResolver) ID() graphql.ID {
+	return marshalExplicitRepositoryPermissionsID(r.perm.ID)
+}
+
+func (r *explicitRepositoryPermissionsResolver) Repository(ctx context.Context) (*repositoryResolver, error) {
+	if r.perm.RepoID == 0 {
+		return nil, nil
+	}
+	return repositoryByIDInt32(ctx, r.perm.RepoID)
+}
+
+func (r *explicitRepositoryPermissionsResolver) Pattern() *string {
+	if r.perm.RepoPattern == "" {
+		return nil
+	}
+	return &r.perm.RepoPattern
+}
+
+func (r *explicitRepositoryPermissionsResolver) Users(ctx context.Context) ([]*UserResolver, error) {
+	users := make([]*UserResolver, 0, len(r.perm.UserIDs))
+	for _, id := range r.perm.UserIDs {
+		user, err := UserByIDInt32(ctx, id)
+		if errcode.IsNotFound(err) {
+			continue // the user was deleted
+		}
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+func (r *explicitRepositoryPermissionsResolver) Organizations(ctx context.Context) ([]*OrgResolver, error) {
+	orgs := make([]*OrgResolver, 0, len(r.perm.OrgIDs))
+	for _, id := range r.perm.OrgIDs {
+		org, err := OrgByIDInt32(ctx, id)
+		if errcode.IsNotFound(err) {
+			continue // the organization was deleted
+		}
+		if err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, org)
+	}
+	return orgs, nil
+}
+
+func (r *explicitRepositoryPermissionsResolver) CreatedAt() string {
+	return r.perm.CreatedAt.Format(time.RFC3339)
+}
+
+func (r *explicitRepositoryPermissionsResolver) UpdatedAt() string {
+	return r.perm.UpdatedAt.Format(time.RFC3339)
+}
+
+func (*schemaResolver) ExplicitRepositoryPermissions(ctx context.Context) ([]*explicitRepositoryPermissionsResolver, error) {
+	// 🚨 SECURITY: Only site admins can list explicit repository permissions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	perms, err := db.ExplicitRepoPermissions.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*explicitRepositoryPermissionsResolver, len(perms))
+	for i, perm := range perms {
+		resolvers[i] = &explicitRepositoryPermissionsResolver{perm: perm}
+	}
+	return resolvers, nil
+}
+
+// unmarshalUserAndOrgIDs unmarshals the IDs of the users and organizations, and checks that they
+// exist.
+func unmarshalUserAndOrgIDs(ctx context.Context, users, orgs []graphql.ID) (userIDs, orgIDs []int32, err error) {
+	userIDs = make([]int32, len(users))
+	for i, id := range users {
+		if userIDs[i], err = UnmarshalUserID(id); err != nil {
+			return nil, nil, err
+		}
+		if _, err := db.Users.GetByID(ctx, userIDs[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	orgIDs = make([]int32, len(orgs))
+	for i, id := range orgs {
+		if orgIDs[i], err = UnmarshalOrgID(id); err != nil {
+			return nil, nil, err
+		}
+		if _, err := db.Orgs.GetByID(ctx, orgIDs[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return userIDs, orgIDs, nil
+}
+
+func (*schemaResolver) SetRepositoryPermissions(ctx context.Context, args *struct {
+	Repository    graphql.ID
+	Users         []graphql.ID
+	Organizations []graphql.ID
+}) (*explicitRepositoryPermissionsResolver, error) {
+	// 🚨 SECURITY: Only site admins can set explicit repository permissions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	repo, err := repositoryByID(ctx, args.Repository)
+	if err != nil {
+		return nil, err
+	}
+	userIDs, orgIDs, err := unmarshalUserAndOrgIDs(ctx, args.Users, args.Organizations)
+	if err != nil {
+		return nil, err
+	}
+	perm, err := db.ExplicitRepoPermissions.SetForRepo(ctx, repo.repo.ID, userIDs, orgIDs)
+	if err != nil {
+		return nil, err
+	}
+	recordExplicitRepositoryPermissionsSet(ctx, perm, args.Users, args.Organizations)
+	return &explicitRepositoryPermissionsResolver{perm: perm}, nil
+}
+
+func (*schemaResolver) SetRepositoryPatternPermissions(ctx context.Context, args *struct {
+	Pattern       string
+	Users         []graphql.ID
+	Organizations []graphql.ID
+}) (*explicitRepositoryPermissionsResolver, error) {
+	// 🚨 SECURITY: Only site admins can set explicit repository permissions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	userIDs, orgIDs, err := unmarshalUserAndOrgIDs(ctx, args.Users, args.Organizations)
+	if err != nil {
+		return nil, err
+	}
+	perm, err := db.ExplicitRepoPermissions.SetForPattern(ctx, args.Pattern, userIDs, orgIDs)
+	if err != nil {
+		return nil, err
+	}
+	recordExplicitRepositoryPermissionsSet(ctx, perm, args.Users, args.Organizations)
+	return &explicitRepositoryPermissionsResolver{perm: perm}, nil
+}
+
+func recordExplicitRepositoryPermissionsSet(ctx context.Context, perm *db.ExplicitRepoPermission, users, orgs []graphql.ID) {
+	audit.Record(ctx, audit.Event{
+		Action:     audit.RepoPermissionsSet,
+		TargetType: explicitRepositoryPermissionsIDKind,
+		TargetID:   string(marshalExplicitRepositoryPermissionsID(perm.ID)),
+		Details: map[string]interface{}{
+			"repository":    perm.RepoName,
+			"pattern":       perm.RepoPattern,
+			"users":         users,
+			"organizations": orgs,
+		},
+	})
+}
+
+func (*schemaResolver) DeleteExplicitRepositoryPermissions(ctx context.Context, args *struct {
+	ExplicitRepositoryPermissions graphql.ID
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can delete explicit repository permissions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	permID, err := unmarshalExplicitRepositoryPermissionsID(args.ExplicitRepositoryPermissions)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.ExplicitRepoPermissions.Delete(ctx, permID); err != nil {
+		return nil, err
+	}
+	audit.Record(ctx, audit.Event{
+		Action:     audit.RepoPermissionsDelete,
+		TargetType: explicitRepositoryPermissionsIDKind,
+		TargetID:   string(args.ExplicitRepositoryPermissions),
+	})
+	return &EmptyResponse{}, nil
+}
@@ -0,0 +1,99 @@
+package graphqlbackend
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+)
+
+func TestMutation_SetRepositoryPatternPermissions(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, Username: "alice"}, nil
+	}
+	db.Mocks.Orgs.GetByID = func(ctx context.Context, id int32) (*types.Org, error) {
+		return &types.Org{ID: id, Name: "acme"}, nil
+	}
+	var called bool
+	db.Mocks.ExplicitRepoPermissions.SetForPattern = func(pattern string, userIDs, orgIDs []int32) (*db.ExplicitRepoPermission, error) {
+		called = true
+		if want := "^gitolite/"; pattern != want {
+			t.Errorf("got pattern %q, want %q", pattern, want)
+		}
+		if want := []int32{1}; !reflect.DeepEqual(userIDs, want) {
+			t.Errorf("got user IDs %v, want %v", userIDs, want)
+		}
+		if want := []int32{2}; !reflect.DeepEqual(orgIDs, want) {
+			t.Errorf("got org IDs %v, want %v", orgIDs, want)
+		}
+		return &db.ExplicitRepoPermission{ID: 3, RepoPattern: pattern, UserIDs: userIDs, OrgIDs: orgIDs}, nil
+	}
+
+	t.Run("non-site-admin", func(t *testing.T) {
+		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{ID: 1}, nil
+		}
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := (&schemaResolver{}).SetRepositoryPatternPermissions(ctx, &struct {
+			Pattern       string
+			Users         []graphql.ID
+			Organizations []graphql.ID
+		}{Pattern: "^gitolite/"})
+		if want := backend.ErrMustBeSiteAdmin; err != want {
+			t.Errorf("got error %v, want %v", err, want)
+		}
+		if result != nil || called {
+			t.Error("want explicit permissions not to be set")
+		}
+	})
+
+	t.Run("site admin", func(t *testing.T) {
+		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{ID: 1, SiteAdmin: true}, nil
+		}
+		gqltesting.RunTests(t, []*gqltesting.Test{
+			{
+				Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+				Schema:  GraphQLSchema,
+				Query: `
+				mutation {
+					setRepositoryPatternPermissions(pattern: "^gitolite/", users: ["VXNlcjox"], organizations: ["T3JnOjI="]) {
+						id
+						repository {
+							name
+						}
+						pattern
+						users {
+							username
+						}
+						organizations {
+							name
+						}
+					}
+				}
+			`,
+				ExpectedResult: `
+				{
+					"setRepositoryPatternPermissions": {
+						"id": "RXhwbGljaXRSZXBvc2l0b3J5UGVybWlzc2lvbnM6Mw==",
+						"repository": null,
+						"pattern": "^gitolite/",
+						"users": [{"username": "alice"}],
+						"organizations": [{"name": "acme"}]
+					}
+				}
+			`,
+			},
+		})
+		if !called {
+			t.Error("want explicit permissions to be set")
+		}
+	})
+}
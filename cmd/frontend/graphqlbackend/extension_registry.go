@@ -41,6 +41,7 @@ type ExtensionRegistryResolver interface {
 	CreateExtension(context.Context, *ExtensionRegistryCreateExtensionArgs) (ExtensionRegistryMutationResult, error)
 	UpdateExtension(context.Context, *ExtensionRegistryUpdateExtensionArgs) (ExtensionRegistryMutationResult, error)
 	PublishExtension(context.Context, *ExtensionRegistryPublishExtensionArgs) (ExtensionRegistryMutationResult, error)
+	YankExtensionRelease(context.Context, *ExtensionRegistryYankExtensionReleaseArgs) (*EmptyResponse, error)
 	DeleteExtension(context.Context, *ExtensionRegistryDeleteExtensionArgs) (*EmptyResponse, error)
 	LocalExtensionIDPrefix() *string
 
@@ -77,12 +78,19 @@ type ExtensionRegistryUpdateExtensionArgs struct {
 
 type ExtensionRegistryPublishExtensionArgs struct {
 	ExtensionID string
+	Version     *string
 	Manifest    string
 	Bundle      *string
 	SourceMap   *string
 	Force       bool
 }
 
+type ExtensionRegistryYankExtensionReleaseArgs struct {
+	Extension graphql.ID
+	Version   string
+	Yanked    bool
+}
+
 type ExtensionRegistryDeleteExtensionArgs struct {
 	Extension graphql.ID
 }
@@ -112,6 +120,7 @@ type RegistryExtension interface {
 	CreatedAt() *string
 	UpdatedAt() *string
 	PublishedAt(context.Context) (*string, error)
+	Releases(context.Context, *graphqlutil.ConnectionArgs) (RegistryExtensionReleaseConnection, error)
 	URL() string
 	RemoteURL() *string
 	RegistryName() (string, error)
@@ -127,6 +136,15 @@ type ExtensionManifest interface {
 	BundleURL() (*string, error)
 }
 
+// RegistryExtensionRelease is the interface for the GraphQL type RegistryExtensionRelease.
+type RegistryExtensionRelease interface {
+	Version() *string
+	Creator(context.Context) (*UserResolver, error)
+	CreatedAt() string
+	Yanked() bool
+	YankedAt() *string
+}
+
 // RegistryPublisher is the interface for the GraphQL type RegistryPublisher.
 type RegistryPublisher interface {
 	ToUser() (*UserResolver, bool)
@@ -145,6 +163,14 @@ type RegistryExtensionConnection interface {
 	Error(context.Context) *string
 }
 
+// RegistryExtensionReleaseConnection is the interface for the GraphQL type
+// RegistryExtensionReleaseConnection.
+type RegistryExtensionReleaseConnection interface {
+	Nodes(context.Context) ([]RegistryExtensionRelease, error)
+	TotalCount(context.Context) (int32, error)
+	PageInfo(context.Context) (*graphqlutil.PageInfo, error)
+}
+
 // RegistryPublisherConnection is the interface for the GraphQL type RegistryPublisherConnection.
 type RegistryPublisherConnection interface {
 	Nodes(context.Context) ([]RegistryPublisher, error)
@@ -8,9 +8,11 @@ import (
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/configfiles"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/repoupdater"
@@ -27,6 +29,9 @@ func (r *schemaResolver) AddExternalService(ctx context.Context, args *struct {
 	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
 		return nil, err
 	}
+	if err := configfiles.CheckWritable(); err != nil {
+		return nil, err
+	}
 
 	externalService := &types.ExternalService{
 		Kind:        args.Input.Kind,
@@ -37,6 +42,12 @@ func (r *schemaResolver) AddExternalService(ctx context.Context, args *struct {
 	if err := db.ExternalServices.Create(ctx, externalService); err != nil {
 		return nil, err
 	}
+	audit.Record(ctx, audit.Event{
+		Action:     audit.ExternalServiceCreate,
+		TargetType: "ExternalService",
+		TargetID:   string(marshalExternalServiceID(externalService.ID)),
+		Details:    map[string]string{"kind": externalService.Kind, "displayName": externalService.DisplayName},
+	})
 
 	if err := syncExternalService(ctx, externalService); err != nil {
 		return nil, errors.Wrap(err, "warning: external service created, but sync request failed")
@@ -61,11 +72,19 @@ func (*schemaResolver) UpdateExternalService(ctx context.Context, args *struct {
 	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
 		return nil, err
 	}
+	if err := configfiles.CheckWritable(); err != nil {
+		return nil, err
+	}
 
 	if args.Input.Config != nil && strings.TrimSpace(*args.Input.Config) == "" {
 		return nil, fmt.Errorf("blank external service configuration is invalid (must be valid JSONC)")
 	}
 
+	before, err := db.ExternalServices.GetByID(ctx, externalServiceID)
+	if err != nil {
+		return nil, err
+	}
+
 	update := &db.ExternalServiceUpdate{
 		DisplayName: args.Input.DisplayName,
 		Config:      args.Input.Config,
@@ -78,6 +97,19 @@ func (*schemaResolver) UpdateExternalService(ctx context.Context, args *struct {
 	if err != nil {
 		return nil, err
 	}
+	details := map[string]interface{}{
+		"kind":              externalService.Kind,
+		"changedConfigKeys": audit.ChangedKeys(before.Config, externalService.Config),
+	}
+	if before.DisplayName != externalService.DisplayName {
+		details["displayName"] = audit.Change{Before: before.DisplayName, After: externalService.DisplayName}
+	}
+	audit.Record(ctx, audit.Event{
+		Action:     audit.ExternalServiceUpdate,
+		TargetType: "ExternalService",
+		TargetID:   string(args.Input.ID),
+		Details:    details,
+	})
 
 	if err = syncExternalService(ctx, externalService); err != nil {
 		return nil, errors.Wrap(err, "warning: external service updated, but sync request failed")
@@ -112,6 +144,9 @@ func (*schemaResolver) DeleteExternalService(ctx context.Context, args *struct {
 	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
 		return nil, err
 	}
+	if err := configfiles.CheckWritable(); err != nil {
+		return nil, err
+	}
 
 	id, err := unmarshalExternalServiceID(args.ExternalService)
 	if err != nil {
@@ -126,6 +161,12 @@ func (*schemaResolver) DeleteExternalService(ctx context.Context, args *struct {
 	if err := db.ExternalServices.Delete(ctx, id); err != nil {
 		return nil, err
 	}
+	audit.Record(ctx, audit.Event{
+		Action:     audit.ExternalServiceDelete,
+		TargetType: "ExternalService",
+		TargetID:   string(args.ExternalService),
+		Details:    map[string]string{"kind": externalService.Kind, "displayName": externalService.DisplayName},
+	})
 
 	if err = syncExternalService(ctx, externalService); err != nil {
 		return nil, errors.Wrap(err, "warning: external service deleted, but sync request failed")
@@ -54,6 +54,16 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+
+	readOnlySchema, searchSchema := restrictedSchemas(Schema)
+	readOnlyGraphQLSchema, err = graphql.ParseSchema(readOnlySchema, &schemaResolver{}, graphql.Tracer(prometheusTracer{}))
+	if err != nil {
+		panic(err)
+	}
+	searchGraphQLSchema, err = graphql.ParseSchema(searchSchema, &searchSchemaResolver{}, graphql.Tracer(prometheusTracer{}))
+	if err != nil {
+		panic(err)
+	}
 }
 
 // EmptyResponse is a type that can be used in the return signature for graphql queries
@@ -99,6 +109,16 @@ func (r *nodeResolver) ToExternalService() (*externalServiceResolver, bool) {
 	return n, ok
 }
 
+func (r *nodeResolver) ToExplicitRepositoryPermissions() (*explicitRepositoryPermissionsResolver, bool) {
+	n, ok := r.node.(*explicitRepositoryPermissionsResolver)
+	return n, ok
+}
+
+func (r *nodeResolver) ToWebhook() (*webhookResolver, bool) {
+	n, ok := r.node.(*webhookResolver)
+	return n, ok
+}
+
 func (r *nodeResolver) ToGitRef() (*gitRefResolver, bool) {
 	n, ok := r.node.(*gitRefResolver)
 	return n, ok
@@ -171,6 +191,10 @@ func nodeByID(ctx context.Context, id graphql.ID) (node, error) {
 		return externalAccountByID(ctx, id)
 	case externalServiceIDKind:
 		return externalServiceByID(ctx, id)
+	case explicitRepositoryPermissionsIDKind:
+		return explicitRepositoryPermissionsByID(ctx, id)
+	case webhookIDKind:
+		return webhookByID(ctx, id)
 	case "GitRef":
 		return gitRefByID(ctx, id)
 	case "Repository":
@@ -7,6 +7,7 @@ import (
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/suspiciousnames"
@@ -190,11 +191,19 @@ func (*schemaResolver) CreateOrganization(ctx context.Context, args *struct {
 		return nil, err
 	}
 
+	audit.Record(ctx, audit.Event{
+		Action:     audit.OrgCreate,
+		TargetType: "Org",
+		TargetID:   string(marshalOrgID(newOrg.ID)),
+		Details:    map[string]string{"name": newOrg.Name},
+	})
+
 	// Add the current user as the first member of the new org.
 	_, err = db.OrgMembers.Create(ctx, newOrg.ID, currentUser.user.ID)
 	if err != nil {
 		return nil, err
 	}
+	recordOrgMemberAdd(ctx, newOrg.ID, currentUser.user.ID)
 
 	return &OrgResolver{org: newOrg}, nil
 }
@@ -218,6 +227,12 @@ func (*schemaResolver) UpdateOrganization(ctx context.Context, args *struct {
 	if err != nil {
 		return nil, err
 	}
+	audit.Record(ctx, audit.Event{
+		Action:     audit.OrgUpdate,
+		TargetType: "Org",
+		TargetID:   string(marshalOrgID(orgID)),
+		Details:    map[string]*string{"displayName": args.DisplayName},
+	})
 
 	return &OrgResolver{org: updatedOrg}, nil
 }
@@ -242,7 +257,16 @@ func (*schemaResolver) RemoveUserFromOrganization(ctx context.Context, args *str
 	}
 
 	log15.Info("removing user from org", "user", userID, "org", orgID)
-	return nil, db.OrgMembers.Remove(ctx, orgID, userID)
+	if err := db.OrgMembers.Remove(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+	audit.Record(ctx, audit.Event{
+		Action:     audit.OrgMemberRemove,
+		TargetType: "Org",
+		TargetID:   string(args.Organization),
+		Details:    map[string]string{"user": string(args.User)},
+	})
+	return nil, nil
 }
 
 func (*schemaResolver) AddUserToOrganization(ctx context.Context, args *struct {
@@ -266,5 +290,16 @@ func (*schemaResolver) AddUserToOrganization(ctx context.Context, args *struct {
 	if _, err := db.OrgMembers.Create(ctx, orgID, userToInvite.ID); err != nil {
 		return nil, err
 	}
+	recordOrgMemberAdd(ctx, orgID, userToInvite.ID)
 	return &EmptyResponse{}, nil
 }
+
+// recordOrgMemberAdd records the addition of the user to the org in the audit log.
+func recordOrgMemberAdd(ctx context.Context, orgID, userID int32) {
+	audit.Record(ctx, audit.Event{
+		Action:     audit.OrgMemberAdd,
+		TargetType: "Org",
+		TargetID:   string(marshalOrgID(orgID)),
+		Details:    map[string]string{"user": audit.UserID(userID)},
+	})
+}
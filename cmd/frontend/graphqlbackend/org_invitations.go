@@ -139,6 +139,7 @@ func (*schemaResolver) RespondToOrganizationInvitation(ctx context.Context, args
 		if _, err := db.OrgMembers.Create(ctx, orgID, currentUser.user.ID); err != nil {
 			return nil, err
 		}
+		recordOrgMemberAdd(ctx, orgID, currentUser.user.ID)
 	}
 	return &EmptyResponse{}, nil
 }
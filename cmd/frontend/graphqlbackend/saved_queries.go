@@ -22,6 +22,7 @@ type savedQueryResolver struct {
 	description                         string
 	query                               string
 	showOnHomepage, notify, notifySlack bool
+	notifyMSTeams, notifyWebhook        bool
 }
 
 func savedQueryByID(ctx context.Context, id graphql.ID) (*savedQueryResolver, error) {
@@ -88,6 +89,14 @@ func (r savedQueryResolver) NotifySlack() bool {
 	return r.notifySlack
 }
 
+func (r savedQueryResolver) NotifyMSTeams() bool {
+	return r.notifyMSTeams
+}
+
+func (r savedQueryResolver) NotifyWebhook() bool {
+	return r.notifyWebhook
+}
+
 func (r savedQueryResolver) Subject() *settingsSubject { return r.subject }
 
 func (r savedQueryResolver) Key() *string {
@@ -105,13 +114,15 @@ func (r savedQueryResolver) Query() string { return r.query }
 
 func toSavedQueryResolver(index int, subject *settingsSubject, entry api.ConfigSavedQuery) *savedQueryResolver {
 	return &savedQueryResolver{
-		subject:     subject,
-		key:         entry.Key,
-		index:       index,
-		description: entry.Description,
-		query:       entry.Query,
-		notify:      entry.Notify,
-		notifySlack: entry.NotifySlack,
+		subject:       subject,
+		key:           entry.Key,
+		index:         index,
+		description:   entry.Description,
+		query:         entry.Query,
+		notify:        entry.Notify,
+		notifySlack:   entry.NotifySlack,
+		notifyMSTeams: entry.NotifyMSTeams,
+		notifyWebhook: entry.NotifyWebhook,
 	}
 }
 
@@ -143,6 +154,7 @@ func (r *settingsMutation) CreateSavedQuery(ctx context.Context, args *struct {
 	Description                         string
 	Query                               string
 	ShowOnHomepage, Notify, NotifySlack bool
+	NotifyMSTeams, NotifyWebhook        bool
 	DisableSubscriptionNotifications    bool
 }) (*savedQueryResolver, error) {
 	var index int
@@ -157,11 +169,13 @@ func (r *settingsMutation) CreateSavedQuery(ctx context.Context, args *struct {
 		key = generateUniqueSavedQueryKey(config.SavedQueries)
 
 		value := api.ConfigSavedQuery{
-			Key:         key,
-			Description: args.Description,
-			Query:       args.Query,
-			Notify:      args.Notify,
-			NotifySlack: args.NotifySlack,
+			Key:           key,
+			Description:   args.Description,
+			Query:         args.Query,
+			Notify:        args.Notify,
+			NotifySlack:   args.NotifySlack,
+			NotifyMSTeams: args.NotifyMSTeams,
+			NotifyWebhook: args.NotifyWebhook,
 		}
 		edits, _, err = jsonx.ComputePropertyEdit(oldConfig, jsonx.MakePath("search.savedQueries", -1), value, nil, conf.FormatOptions)
 		return edits, err
@@ -178,13 +192,15 @@ func (r *settingsMutation) CreateSavedQuery(ctx context.Context, args *struct {
 	go queryrunnerapi.Client.SavedQueryWasCreatedOrUpdated(context.Background(), r.subject.toSubject(), config, args.DisableSubscriptionNotifications)
 
 	return &savedQueryResolver{
-		subject:     r.subject,
-		key:         key,
-		index:       index,
-		description: args.Description,
-		query:       args.Query,
-		notify:      args.Notify,
-		notifySlack: args.NotifySlack,
+		subject:       r.subject,
+		key:           key,
+		index:         index,
+		description:   args.Description,
+		query:         args.Query,
+		notify:        args.Notify,
+		notifySlack:   args.NotifySlack,
+		notifyMSTeams: args.NotifyMSTeams,
+		notifyWebhook: args.NotifyWebhook,
 	}, nil
 }
 
@@ -208,6 +224,7 @@ func (r *settingsMutation) UpdateSavedQuery(ctx context.Context, args *struct {
 	Description                         *string
 	Query                               *string
 	ShowOnHomepage, Notify, NotifySlack bool
+	NotifyMSTeams, NotifyWebhook        *bool
 }) (*savedQueryResolver, error) {
 	spec, err := unmarshalSavedQueryID(args.ID)
 	if err != nil {
@@ -238,6 +255,12 @@ func (r *settingsMutation) UpdateSavedQuery(ctx context.Context, args *struct {
 
 	fieldUpdates["notify"] = args.Notify
 	fieldUpdates["notifySlack"] = args.NotifySlack
+	if args.NotifyMSTeams != nil {
+		fieldUpdates["notifyMSTeams"] = *args.NotifyMSTeams
+	}
+	if args.NotifyWebhook != nil {
+		fieldUpdates["notifyWebhook"] = *args.NotifyWebhook
+	}
 
 	for propertyName, value := range fieldUpdates {
 		id, err := r.doUpdateSettings(ctx, func(oldConfig string) (edits []jsonx.Edit, err error) {
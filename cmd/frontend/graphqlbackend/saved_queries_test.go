@@ -87,6 +87,8 @@ func TestCreateSavedQuery(t *testing.T) {
 		ShowOnHomepage                   bool
 		Notify                           bool
 		NotifySlack                      bool
+		NotifyMSTeams                    bool
+		NotifyWebhook                    bool
 		DisableSubscriptionNotifications bool
 	}{
 		Description: "d2",
@@ -157,6 +159,8 @@ func TestUpdateSavedQuery(t *testing.T) {
 		ShowOnHomepage bool
 		Notify         bool
 		NotifySlack    bool
+		NotifyMSTeams  *bool
+		NotifyWebhook  *bool
 	}{
 		ID:          marshalSavedQueryID(api.SavedQueryIDSpec{Subject: subject.toSubject(), Key: "a"}),
 		Description: &newDescription,
@@ -0,0 +1,44 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+)
+
+func (r *UserResolver) SavedSearchEmailFrequency(ctx context.Context) (string, error) {
+	// 🚨 SECURITY: Only the user and site admins are allowed to access the user's notification
+	// preferences.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
+		return "", err
+	}
+	frequency, err := db.Users.GetSavedSearchEmailFrequency(ctx, r.user.ID)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(frequency), nil
+}
+
+func (*schemaResolver) SetSavedSearchEmailFrequency(ctx context.Context, args *struct {
+	User      graphql.ID
+	Frequency string
+}) (*EmptyResponse, error) {
+	userID, err := UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only the user and site admins are allowed to change the user's notification
+	// preferences.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := db.Users.SetSavedSearchEmailFrequency(ctx, userID, strings.ToLower(args.Frequency)); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
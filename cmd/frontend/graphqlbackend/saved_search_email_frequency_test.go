@@ -0,0 +1,51 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+func TestMutation_SetSavedSearchEmailFrequency(t *testing.T) {
+	resetMocks()
+	calledSet := false
+	db.Mocks.Users.SetSavedSearchEmailFrequency = func(id int32, frequency string) error {
+		calledSet = true
+		if want := int32(1); id != want {
+			t.Errorf("got user ID %d, want %d", id, want)
+		}
+		if want := api.SavedSearchEmailFrequencyHourly; frequency != want {
+			t.Errorf("got frequency %q, want %q", frequency, want)
+		}
+		return nil
+	}
+	defer resetMocks()
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Context: actor.WithActor(context.Background(), &actor.Actor{UID: 1}),
+			Schema:  GraphQLSchema,
+			Query: `
+				mutation {
+					setSavedSearchEmailFrequency(user: "VXNlcjox", frequency: HOURLY) {
+						alwaysNil
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"setSavedSearchEmailFrequency": {
+						"alwaysNil": null
+					}
+				}
+			`,
+		},
+	})
+	if !calledSet {
+		t.Error("!calledSet")
+	}
+}
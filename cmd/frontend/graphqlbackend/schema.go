@@ -31,6 +31,10 @@ type Mutation {
     #
     # Only the user and site admins may perform this mutation.
     updateUser(user: ID!, username: String, displayName: String, avatarURL: String): EmptyResponse!
+    # Sets how often the user receives email notifications for new saved search results.
+    #
+    # Only the user and site admins may perform this mutation.
+    setSavedSearchEmailFrequency(user: ID!, frequency: SavedSearchEmailFrequency!): EmptyResponse!
     # Creates an organization. The caller is added as a member of the newly created organization.
     #
     # Only authenticated users may perform this mutation.
@@ -56,6 +60,76 @@ type Mutation {
     #
     # Only site admins may perform this mutation.
     setAllRepositoriesEnabled(enabled: Boolean!): EmptyResponse
+    # Sets the users and organizations that can read the repository, replacing any explicit
+    # permissions previously set for the repository. Explicit permissions restrict access to
+    # repositories whose code host has no repository permissions on Sourcegraph (such as Gitolite).
+    # They are only enforced if the "permissions.explicit.enabled" site configuration property is
+    # true.
+    #
+    # Only site admins may perform this mutation.
+    setRepositoryPermissions(
+        # The repository.
+        repository: ID!
+        # The users who can read the repository.
+        users: [ID!]!
+        # The organizations whose members can read the repository.
+        organizations: [ID!]!
+    ): ExplicitRepositoryPermissions!
+    # Sets the users and organizations that can read the repositories whose names match the
+    # pattern, replacing any explicit permissions previously set for the same pattern. See
+    # setRepositoryPermissions.
+    #
+    # Only site admins may perform this mutation.
+    setRepositoryPatternPermissions(
+        # A regular expression that must match the entire repository name, such as
+        # "gitolite\.example\.com/secret/.*".
+        pattern: String!
+        # The users who can read the repositories.
+        users: [ID!]!
+        # The organizations whose members can read the repositories.
+        organizations: [ID!]!
+    ): ExplicitRepositoryPermissions!
+    # Deletes explicit repository permissions. The repositories that they applied to are no longer
+    # restricted by them.
+    #
+    # Only site admins may perform this mutation.
+    deleteExplicitRepositoryPermissions(explicitRepositoryPermissions: ID!): EmptyResponse
+    # Creates an outbound webhook, which is notified of the events of the given types by HTTP POST
+    # requests to its URL. Each request's body is signed with the secret (see the
+    # X-Sourcegraph-Signature header).
+    #
+    # A site webhook (created without a user) receives all events of its types, and only site admins
+    # may create one. A user's webhook only receives the events that concern the user (such as new
+    # results of the user's saved searches), and only the user and site admins may create one.
+    createWebhook(
+        # The user to create the webhook for, or null to create a site webhook.
+        user: ID
+        # The URL that events are POSTed to.
+        url: String!
+        # The secret used to sign the requests.
+        secret: String!
+        # The event types to subscribe to (such as "repo.cloned").
+        eventTypes: [String!]!
+    ): Webhook!
+    # Updates an outbound webhook. Fields that are null are not updated.
+    #
+    # Only site admins and the webhook's user may perform this mutation.
+    updateWebhook(
+        # The webhook to update.
+        webhook: ID!
+        # The URL that events are POSTed to.
+        url: String
+        # The secret used to sign the requests.
+        secret: String
+        # The event types to subscribe to.
+        eventTypes: [String!]
+        # Whether events are delivered to the webhook.
+        active: Boolean
+    ): Webhook!
+    # Deletes an outbound webhook, along with its delivery history.
+    #
+    # Only site admins and the webhook's user may perform this mutation.
+    deleteWebhook(webhook: ID!): EmptyResponse
     # Tests the connection to a mirror repository's original source repository. This is an
     # expensive and slow operation, so it should only be used for interactive diagnostics.
     #
@@ -141,11 +215,21 @@ type Mutation {
     # The supported scopes are:
     #
     # - "user:all": Full control of all resources accessible to the user account.
+    # - "user:read": Read-only access to all resources accessible to the user account. Tokens with this scope may
+    #   not be used for GraphQL mutations or other API requests that change data.
+    # - "search": Ability to perform searches (with the GraphQL Query.search field) and nothing else.
     # - "site-admin:sudo": Ability to perform any action as any other user. (Only site admins may create tokens
-    #   with this scope.)
+    #   with this scope, and they must also have the "user:all" scope.)
+    # - "repo:<pattern>": Restricts the token to the repositories whose names match the regular expression
+    #   <pattern> (e.g., "repo:^github\.com/myorg/"). A token with several such scopes may access the
+    #   repositories matching any of them.
+    #
+    # Every access token must have exactly one of the "user:all", "user:read" and "search" scopes.
+    #
+    # If expiresAt (an RFC 3339 date) is given, the access token can't be used after that date.
     #
     # Only the user or site admins may perform this mutation.
-    createAccessToken(user: ID!, scopes: [String!]!, note: String!): CreateAccessTokenResult!
+    createAccessToken(user: ID!, scopes: [String!]!, note: String!, expiresAt: String): CreateAccessTokenResult!
     # Deletes and immediately revokes the specified access token, specified by either its ID or by the token
     # itself.
     #
@@ -278,6 +362,17 @@ type Mutation {
         # with this new value.
         input: String!
     ): Boolean!
+    # Restores the site or critical configuration to the contents of a previously saved revision, by saving them
+    # as a new revision. The rollback is rejected if the resulting configuration is invalid. Returns whether or not
+    # a restart is required for the rollback to be applied.
+    #
+    # Only site admins may perform this mutation.
+    rollBackConfiguration(
+        # The type of the configuration to roll back.
+        type: ConfigurationType!
+        # The ID of the revision to restore.
+        revision: Int!
+    ): Boolean!
     # Manages discussions.
     discussions: DiscussionsMutation
     # Sets whether the user with the specified user ID is a site admin.
@@ -431,6 +526,10 @@ input DiscussionThreadUpdateInput {
     # When non-null, indicates that the thread should be archived.
     Archive: Boolean
 
+    # When non-null, indicates whether the thread should be marked as resolved
+    # (by the current user) or unresolved.
+    Resolve: Boolean
+
     # When non-null, indicates that the thread should be deleted. Only admins
     # can perform this action.
     Delete: Boolean
@@ -472,6 +571,34 @@ type DiscussionsMutation {
 
     # Updates an existing comment. Returns the updated thread.
     updateComment(input: DiscussionCommentUpdateInput!): DiscussionThread!
+
+    # Subscribes the current user to (or unsubscribes the current user from)
+    # notifications from a thread. Returns the updated thread.
+    #
+    # Users who were mentioned in a thread or commented on it are subscribed
+    # to it unless they unsubscribe.
+    updateThreadSubscription(threadID: ID!, subscribed: Boolean!): DiscussionThread!
+
+    # Adds the current user's reaction to a comment. Returns the updated comment.
+    #
+    # The reaction must be one of the emoji 👍, 👎, 😄, 🎉, 😕, ❤️, 🚀, or 👀.
+    addReactionToComment(commentID: ID!, reaction: String!): DiscussionComment!
+
+    # Removes the current user's reaction from a comment. Returns the updated
+    # comment.
+    removeReactionFromComment(commentID: ID!, reaction: String!): DiscussionComment!
+
+    # Creates the current user's email address for creating discussion threads
+    # in the repository (see Repository.viewerDiscussionsEmailAddress), or
+    # returns the existing one. Requires email.imap to be configured and the
+    # current user to have a verified email.
+    createRepositoryEmailAddress(repository: ID!): String!
+
+    # Replaces the current user's email address for creating discussion threads
+    # in the repository (see Repository.viewerDiscussionsEmailAddress) with a
+    # new one, so that emails sent to the old address are ignored. Returns the
+    # new address.
+    resetRepositoryEmailAddress(repository: ID!): String!
 }
 
 # Describes options for rendering Markdown.
@@ -523,6 +650,10 @@ type SettingsMutation {
         showOnHomepage: Boolean = false
         notify: Boolean = false
         notifySlack: Boolean = false
+        # Whether to notify the Microsoft Teams channel configured in the "notifications.msteams" setting.
+        notifyMSTeams: Boolean = false
+        # Whether to notify the webhook configured in the "notifications.webhook" setting.
+        notifyWebhook: Boolean = false
         disableSubscriptionNotifications: Boolean = false
     ): SavedQuery!
     # Update the saved query with the given ID in settings.
@@ -533,6 +664,10 @@ type SettingsMutation {
         showOnHomepage: Boolean = false
         notify: Boolean = false
         notifySlack: Boolean = false
+        # Whether to notify on Microsoft Teams. If null, the current value is kept.
+        notifyMSTeams: Boolean
+        # Whether to notify the generic webhook. If null, the current value is kept.
+        notifyWebhook: Boolean
     ): SavedQuery!
     # Delete the saved query with the given ID in the settings.
     deleteSavedQuery(id: ID!, disableSubscriptionNotifications: Boolean = false): EmptyResponse
@@ -651,6 +786,9 @@ type Query {
         # Returns the first n external services from the list.
         first: Int
     ): ExternalServiceConnection!
+    # Lists all explicit repository permissions (see Mutation.setRepositoryPermissions). Only site
+    # admins may perform this query.
+    explicitRepositoryPermissions: [ExplicitRepositoryPermissions!]!
     # List all repositories.
     repositories(
         # Returns the first n repositories from the list.
@@ -740,6 +878,11 @@ type Query {
         #
         # If the path ends with "/**", any path below that is matched.
         targetRepositoryPath: String
+        # When present (along with a target repository and 'targetRepositoryPath'),
+        # the 'targetRepositoryPath' is matched against the path of each thread's
+        # file relative to this Git revision specifier, following renames of the
+        # file since the thread was created. See DiscussionThreadTargetRepo.relativeTo.
+        targetRepositoryRevision: String
     ): DiscussionThreadConnection!
     # Lists discussion comments.
     discussionComments(
@@ -972,6 +1115,20 @@ type SavedQuery {
     notify: Boolean!
     # Whether or not to notify on Slack.
     notifySlack: Boolean!
+    # Whether or not to notify on Microsoft Teams.
+    notifyMSTeams: Boolean!
+    # Whether or not to notify the generic webhook configured in the "notifications.webhook" setting.
+    notifyWebhook: Boolean!
+}
+
+# How often a user receives email notifications for new saved search results.
+enum SavedSearchEmailFrequency {
+    # An email is sent each time a saved search has new results.
+    IMMEDIATE
+    # New results of all saved searches are summarized in an hourly digest email.
+    HOURLY
+    # New results of all saved searches are summarized in a daily digest email.
+    DAILY
 }
 
 # A search query description.
@@ -1068,6 +1225,7 @@ enum ExternalServiceKind {
     GITLAB
     GITOLITE
     PHABRICATOR
+    PLUGIN
     OTHER
 }
 
@@ -1087,6 +1245,27 @@ type ExternalService implements Node {
     updatedAt: String!
 }
 
+# The users and organizations that can read a repository (or the repositories whose names match a
+# pattern), as set explicitly by a site admin.
+type ExplicitRepositoryPermissions implements Node {
+    # The unique ID of the explicit repository permissions.
+    id: ID!
+    # The repository, or null if the permissions apply to the repositories whose names match a
+    # pattern.
+    repository: Repository
+    # The regular expression that must match the entire repository name, or null if the permissions
+    # apply to a single repository.
+    pattern: String
+    # The users who can read the repositories.
+    users: [User!]!
+    # The organizations whose members can read the repositories.
+    organizations: [Org!]!
+    # When the explicit repository permissions were created.
+    createdAt: String!
+    # When the explicit repository permissions were last updated.
+    updatedAt: String!
+}
+
 # A list of repositories.
 type RepositoryConnection {
     # A list of repositories.
@@ -1215,6 +1394,15 @@ type Repository implements Node & GenericSearchResultInterface {
     redirectURL: String
     # Whether the viewer has admin privileges on this repository.
     viewerCanAdminister: Boolean!
+    # The email address that the viewer can send email to in order to create a new discussion thread in this
+    # repository. The first line of the email is the file path and line that the thread is about (e.g.
+    # "path/to/file.go:12" or "path/to/file.go:12-15"), and the rest of the email is the thread's first comment.
+    # The email's subject is the thread's title.
+    #
+    # Only the viewer may see their address, because anyone with it can create threads as the viewer. Null if
+    # the viewer is not signed in, if email.imap is not configured, or if the viewer has not created an address
+    # for this repository yet (with the createRepositoryEmailAddress mutation).
+    viewerDiscussionsEmailAddress: String
     # Base64 data uri to an icon.
     icon: String!
     # A markdown string that is rendered prominently.
@@ -1269,6 +1457,8 @@ type UpdateSchedule {
     index: Int!
     # The total number of repos in the schedule.
     total: Int!
+    # The error of the last update of the repo, if it failed.
+    lastError: String
 }
 
 # The state of a repository in the update queue.
@@ -2121,6 +2311,10 @@ type User implements Node & SettingsSubject {
     #
     # Only the user and site admins can access this field.
     emails: [UserEmail!]!
+    # How often the user receives email notifications for new saved search results.
+    #
+    # Only the user and site admins can access this field.
+    savedSearchEmailFrequency: SavedSearchEmailFrequency!
     # The user's access tokens (which grant to the holder the privileges of the user). This consists
     # of all access tokens whose subject is this user.
     #
@@ -2129,6 +2323,13 @@ type User implements Node & SettingsSubject {
         # Returns the first n access tokens from the list.
         first: Int
     ): AccessTokenConnection!
+    # The outbound webhooks registered by the user (see Mutation.createWebhook).
+    #
+    # Only the user and site admins can access this field.
+    webhooks(
+        # Returns the first n webhooks from the list.
+        first: Int
+    ): WebhookConnection!
     # A list of external accounts that are associated with the user.
     externalAccounts(
         # Returns the first n external accounts from the list.
@@ -2180,6 +2381,8 @@ type AccessToken implements Node {
     createdAt: String!
     # The date when the access token was last used to authenticate a request.
     lastUsedAt: String
+    # The date after which the access token can no longer be used, or null if it never expires.
+    expiresAt: String
 }
 
 # A list of access tokens.
@@ -2221,6 +2424,168 @@ type AuthProvider {
     authenticationURL: String
 }
 
+# A list of audit log entries.
+type AuditLogEntryConnection {
+    # A list of audit log entries.
+    nodes: [AuditLogEntry!]!
+    # The total count of audit log entries in the connection. This total count may be larger than the number of
+    # nodes in this object when the result is paginated.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# An outbound webhook, which is notified of platform events by HTTP POST requests.
+type Webhook implements Node {
+    # The unique ID of the webhook.
+    id: ID!
+    # The user who registered the webhook, or null for a site webhook.
+    user: User
+    # The URL that events are POSTed to.
+    url: String!
+    # The event types the webhook subscribes to.
+    eventTypes: [String!]!
+    # Whether events are delivered to the webhook.
+    active: Boolean!
+    # When the webhook was created.
+    createdAt: String!
+    # When the webhook was last updated.
+    updatedAt: String!
+    # The deliveries of events to the webhook, most recent first.
+    deliveries(
+        # Returns the first n deliveries from the list.
+        first: Int
+    ): WebhookDeliveryConnection!
+}
+
+# A list of webhooks.
+type WebhookConnection {
+    # A list of webhooks.
+    nodes: [Webhook!]!
+    # The total count of webhooks in the connection. This total count may be larger than the number
+    # of nodes in this object when the result is paginated.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# The state of a webhook delivery.
+enum WebhookDeliveryState {
+    # The delivery has not yet succeeded and will be attempted (again).
+    PENDING
+    # The webhook's endpoint accepted the delivery.
+    SUCCEEDED
+    # All attempts failed, and the delivery was abandoned.
+    FAILED
+}
+
+# The delivery of an event to a webhook.
+type WebhookDelivery {
+    # The delivery's ID, which is sent in the X-Sourcegraph-Delivery header.
+    id: String!
+    # The event type.
+    eventType: String!
+    # The event's data.
+    payload: JSONValue!
+    # The delivery's state.
+    state: WebhookDeliveryState!
+    # The number of delivery attempts so far.
+    attempts: Int!
+    # When the delivery was last attempted, if ever.
+    lastAttemptAt: String
+    # When the delivery will next be attempted (if it is pending).
+    nextAttemptAt: String
+    # The HTTP status code of the last attempt's response, if any.
+    responseStatusCode: Int
+    # The (truncated) body of the last attempt's response, if any.
+    responseBody: String
+    # The error of the last attempt, if any.
+    error: String
+    # When the event was enqueued.
+    createdAt: String!
+}
+
+# A list of webhook deliveries.
+type WebhookDeliveryConnection {
+    # A list of webhook deliveries.
+    nodes: [WebhookDelivery!]!
+    # The total count of webhook deliveries in the connection. This total count may be larger than
+    # the number of nodes in this object when the result is paginated.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# The state of an email sent by the site.
+enum EmailDeliveryState {
+    # The email has not yet been sent and will be attempted (again).
+    PENDING
+    # The mail server (or HTTP email API) accepted the email.
+    SENT
+    # All attempts failed, and the email was abandoned.
+    FAILED
+    # The recipient's mail server permanently rejected the email.
+    BOUNCED
+}
+
+# An email sent (or being sent) by the site. Emails are sent asynchronously, and failed attempts are
+# retried with exponential backoff.
+type EmailDelivery {
+    # The email's ID.
+    id: String!
+    # The recipient's email address.
+    to: String!
+    # The email's subject.
+    subject: String!
+    # The email's state.
+    state: EmailDeliveryState!
+    # The number of delivery attempts so far.
+    attempts: Int!
+    # When the email was last attempted, if ever.
+    lastAttemptAt: String
+    # When the email will next be attempted (if it is pending).
+    nextAttemptAt: String
+    # The error of the last attempt, if any.
+    error: String
+    # When the email was enqueued.
+    createdAt: String!
+}
+
+# A list of emails sent by the site.
+type EmailDeliveryConnection {
+    # A list of emails.
+    nodes: [EmailDelivery!]!
+    # The total count of emails in the connection. This total count may be larger than the number
+    # of nodes in this object when the result is paginated.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# An entry in the audit log, which records a security-relevant action.
+type AuditLogEntry {
+    # The date when the action was performed.
+    createdAt: String!
+    # The user who performed the action, or null if the action was performed by an unauthenticated
+    # client or by Sourcegraph itself (or if the user was deleted).
+    actor: User
+    # The IP address of the client that performed the action (which is the address of a proxy if
+    # Sourcegraph is deployed behind one), if known.
+    ip: String
+    # The X-Forwarded-For header of the request that performed the action, if any. It can be forged
+    # by the client.
+    forwardedFor: String
+    # The action (such as "site_config.update" or "user.sign_in").
+    action: String!
+    # The type of the object that the action was performed on (such as "User"), if any.
+    targetType: String
+    # The ID of the object that the action was performed on, if any.
+    targetID: String
+    # A summary of the action (such as the changed keys of the site configuration). It never contains
+    # secrets.
+    details: JSONValue!
+}
+
 # A list of external accounts.
 type ExternalAccountConnection {
     # A list of external accounts.
@@ -2302,6 +2667,11 @@ type UserEmail {
     # Whether the viewer has privileges to manually mark this email address as verified (without the user going
     # through the normal verification process). Only site admins have this privilege.
     viewerCanManuallyVerify: Boolean!
+    # When an email sent to this address last bounced (i.e., the recipient's mail server permanently
+    # rejected it), if ever.
+    bouncedAt: String
+    # The mail server's reason for the last bounce, if any.
+    bounceReason: String
 }
 
 # A list of organizations.
@@ -2479,15 +2849,40 @@ type DiscussionThreadTargetRepo {
     # Where the selection would be relative to the given Git revision specifier
     # (branch/commit/etc).
     #
-    # The implementation relies on a hueristic which is generally good enough,
-    # but under certain circumstances may not be as accurate as e.g. determining
-    # this placement by walking through the Git history.
+    # If determining the relative placement is not possible (file was removed,
+    # or the selection no longer exists in the file) null is returned and it
+    # should be assumed the selection does not exist in this revision.
     #
-    # If determining the relative placement is not possible (file was renamed
-    # or removed, the selection no longer exists in the file, or the hueristic
-    # failed) null is returned and it should be assumed the selection does not
-    # exist in this revision.
+    # See relativeTo for details.
     relativeSelection(rev: String!): DiscussionSelectionRange
+
+    # Where the thread's path and selection are relative to the given Git
+    # revision specifier (branch/commit/etc).
+    #
+    # If the thread was created on a known revision or branch, the file is
+    # followed through renames, and the selection is moved through the changes
+    # made to the file since then. If the selected lines themselves were changed
+    # (or the thread's revision is unknown), the selected lines (and the lines
+    # around them) are searched for in the file instead.
+    relativeTo(rev: String!): DiscussionThreadTargetRepoRelativeLocation!
+}
+
+# The location of a discussion thread's repository target relative to a Git
+# revision.
+type DiscussionThreadTargetRepoRelativeLocation {
+    # The commit that the location is relative to.
+    commit: GitCommit!
+
+    # The path of the thread's file or directory in the commit, or null if the
+    # thread has no path or the file was deleted.
+    path: String
+
+    # The thread's selection in the commit's version of the file, or null if
+    # the thread has no selection or the selection could not be found.
+    selection: DiscussionSelectionRange
+
+    # Whether the thread's file or selection no longer exists in the commit.
+    isOutdated: Boolean!
 }
 
 # The target of a discussion thread. Today, the only possible target is a
@@ -2528,6 +2923,19 @@ type DiscussionThread {
     # The date when the discussion thread was archived (or null if it has not).
     archivedAt: String
 
+    # The date when the discussion thread was resolved (or null if it has not).
+    resolvedAt: String
+
+    # The user who resolved the discussion thread (or null if it has not been
+    # resolved).
+    resolvedBy: User
+
+    # Whether the current user is subscribed to notifications from the thread.
+    #
+    # Users who were mentioned in a thread or commented on it are subscribed
+    # to it unless they unsubscribe.
+    viewerIsSubscribed: Boolean!
+
     # The comments in the discussion thread.
     comments(
         # Returns the first n comments from the list.
@@ -2564,6 +2972,11 @@ type DiscussionComment {
     # This will be null if the thread was created without a path string.
     inlineURL: String
 
+    # The URL of the code review comment (e.g. a GitHub pull request review
+    # comment) that this comment was imported from, or null if it was not
+    # imported.
+    importedFromURL: String
+
     # The date when the discussion thread was created.
     createdAt: String!
 
@@ -2589,6 +3002,25 @@ type DiscussionComment {
     #
     # This is always false when discussions.abuseProtection in the site config is set to false.
     canClearReports: Boolean!
+
+    # The reactions to the comment, grouped by reaction (in the order that
+    # each reaction was first added).
+    reactions: [DiscussionCommentReactionGroup!]!
+}
+
+# The users who reacted to a discussion comment with a specific reaction.
+type DiscussionCommentReactionGroup {
+    # The reaction (an emoji).
+    reaction: String!
+
+    # The number of users who reacted with this reaction.
+    count: Int!
+
+    # The users who reacted with this reaction.
+    users: [User!]!
+
+    # Whether the current user reacted with this reaction.
+    viewerHasReacted: Boolean!
 }
 
 # A list of discussion threads.
@@ -2680,6 +3112,9 @@ type Site implements SettingsSubject {
     settingsURL: String
     # Whether the viewer can reload the site (with the reloadSite mutation).
     canReloadSite: Boolean!
+    # Whether the site configuration, critical configuration, external services and global settings are loaded
+    # from configuration files. If true, they can't be edited in the UI or with the API.
+    configurationReadOnly: Boolean!
     # Whether the viewer can modify the subject's settings.
     viewerCanAdminister: Boolean!
     # A list of all access tokens on this site.
@@ -2690,6 +3125,44 @@ type Site implements SettingsSubject {
     # A list of all authentication providers. This information is visible to all viewers and does not contain any
     # secret information.
     authProviders: AuthProviderConnection!
+    # The audit log of security-relevant actions on this site (such as changes to the site
+    # configuration, the creation of access tokens, and sign-ins), most recent first.
+    #
+    # Only site admins can access this field.
+    auditLog(
+        # Returns the first n entries from the list.
+        first: Int
+        # Include only entries whose actor is this user.
+        actor: ID
+        # Include only entries with this action (such as "site_config.update").
+        action: String
+        # Include only entries whose target has this type (such as "User").
+        targetType: String
+        # Include only entries whose target has this ID.
+        targetID: String
+        # Include only entries created at or after this time (in RFC 3339 format).
+        since: String
+        # Include only entries created before this time (in RFC 3339 format).
+        until: String
+    ): AuditLogEntryConnection!
+    # The site's outbound webhooks, which receive all events of their types (see
+    # Mutation.createWebhook). Webhooks registered by users are listed in User.webhooks.
+    #
+    # Only site admins can access this field.
+    webhooks(
+        # Returns the first n webhooks from the list.
+        first: Int
+    ): WebhookConnection!
+    # The emails recently sent (or being sent) by the site, most recent first, including failed and
+    # bounced emails.
+    #
+    # Only site admins can access this field.
+    emailDeliveries(
+        # Returns the first n emails from the list.
+        first: Int
+        # Include only emails in this state.
+        state: EmailDeliveryState
+    ): EmailDeliveryConnection!
     # A list of all user external accounts on this site.
     externalAccounts(
         # Returns the first n external accounts from the list.
@@ -2741,6 +3214,36 @@ type Site implements SettingsSubject {
     #
     # Only site admins may retrieve this information.
     managementConsoleState: ManagementConsoleState!
+    # The repositories whose last update failed, ordered by their number of consecutive failed updates.
+    #
+    # Only site admins may retrieve this information.
+    failingRepositories: [FailingRepository!]!
+}
+
+# A repository whose last update failed. Failing repositories are updated with an exponential backoff.
+type FailingRepository {
+    # The repository.
+    repository: Repository!
+    # The number of consecutive failed updates of the repository.
+    failures: Int!
+    # The kind of error of the last update.
+    errorKind: RepositoryUpdateErrorKind!
+    # The error of the last update.
+    lastError: String!
+    # The next time that the repository will be updated.
+    due: String!
+}
+
+# The kinds of errors of repository updates.
+enum RepositoryUpdateErrorKind {
+    # The repository can't be cloned or fetched, e.g. because it was deleted on the code host.
+    NOT_CLONEABLE
+    # The credentials for the repository are missing or were rejected by the code host.
+    AUTH
+    # The update timed out.
+    TIMEOUT
+    # Any other error.
+    OTHER
 }
 
 # Information about this site's management console.
@@ -2763,6 +3266,74 @@ type SiteConfiguration {
     # This includes both JSON Schema validation problems and other messages that perform more advanced checks
     # on the configuration (that can't be expressed in the JSON Schema).
     validationMessages: [String!]!
+    # The saved revisions of the site or critical configuration, most recent first.
+    revisions(
+        # The type of the configuration whose revisions to list.
+        type: ConfigurationType = SITE
+        # Returns the first n revisions from the list.
+        first: Int
+        # Returns the revisions that come after the revision with this ID in the list (i.e., that were saved
+        # before it). To get the next page of revisions, use the ID of the last revision of the previous page.
+        after: Int
+    ): ConfigurationRevisionConnection!
+    # Looks up a saved revision of the site or critical configuration by its ID. Returns null if there is no such
+    # revision.
+    revision(
+        # The type of the configuration.
+        type: ConfigurationType = SITE
+        # The ID of the revision.
+        id: Int!
+    ): ConfigurationRevision
+}
+
+# A type of configuration that is stored by the site.
+enum ConfigurationType {
+    # The site configuration.
+    SITE
+    # The critical configuration, which is edited in the management console.
+    CRITICAL
+}
+
+# A list of configuration revisions.
+type ConfigurationRevisionConnection {
+    # A list of configuration revisions.
+    nodes: [ConfigurationRevision!]!
+    # The total count of configuration revisions in the connection. This total count may be larger than the number
+    # of nodes in this object when the result is paginated.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# A saved revision of the site or critical configuration.
+type ConfigurationRevision {
+    # The unique identifier of this configuration revision.
+    id: Int!
+    # The type of the configuration.
+    type: ConfigurationType!
+    # The configuration JSON of this revision.
+    contents: String!
+    # The user who saved this revision, or null if it was saved by Sourcegraph itself (or the user was deleted).
+    author: User
+    # The date when this revision was saved.
+    createdAt: String!
+    # The changes made by this revision relative to the base revision, or null if there is no base revision.
+    diff(
+        # The ID of the base revision. Defaults to the revision that was saved just before this one.
+        base: Int
+    ): ConfigurationRevisionDiff
+}
+
+# The changes between two revisions of the same configuration.
+type ConfigurationRevisionDiff {
+    # The revision that the changes are relative to.
+    base: ConfigurationRevision!
+    # The names of the configuration properties whose values differ between the revisions (e.g.,
+    # "auth.providers"). Properties of the critical configuration are prefixed with "critical::".
+    changedProperties: [String!]!
+    # A line-based diff of the revisions' configuration JSON, in which removed lines are prefixed with "-", added
+    # lines with "+" and unchanged lines with " ".
+    contentsDiff: String!
 }
 
 # Information about software updates for Sourcegraph.
@@ -3160,6 +3731,10 @@ type ExtensionRegistryMutation {
         #
         # Examples: "alice/myextension", "acmecorp/myextension"
         extensionID: String!
+        # The semantic version of the release (such as "1.2.3"). It must be unique among the extension's
+        # releases. Settings may pin an extension to a range of versions (in the "extensions.versions"
+        # property). Releases without a version are never in a range.
+        version: String
         # The extension manifest (as JSON).
         manifest: String!
         # The bundled JavaScript source of the extension.
@@ -3172,6 +3747,19 @@ type ExtensionRegistryMutation {
         # Force publish even if there are warnings (such as invalid JSON warnings).
         force: Boolean = false
     ): ExtensionRegistryCreateExtensionResult!
+    # Mark a release of an extension as yanked (or not yanked). A yanked release remains in the
+    # extension's release history, but it is never used as the extension's latest release (or as the
+    # latest release in a version range), so users get the previous release that is not yanked.
+    #
+    # Only authorized extension publishers may perform this mutation.
+    yankExtensionRelease(
+        # The extension whose release to yank.
+        extension: ID!
+        # The version of the release to yank.
+        version: String!
+        # Whether the release is yanked. Use false to undo a previous yank.
+        yanked: Boolean = true
+    ): EmptyResponse!
 }
 
 # The result of Mutation.extensionRegistry.createExtension.
@@ -3218,6 +3806,12 @@ type RegistryExtension implements Node {
     updatedAt: String
     # The date when a release of this extension was most recently published, or null if there are no releases.
     publishedAt: String
+    # The releases of this extension, most recent first (including yanked releases). It is null for extensions
+    # that are not published on this Sourcegraph site.
+    releases(
+        # Returns the first n releases from the list.
+        first: Int
+    ): RegistryExtensionReleaseConnection
     # The URL to the extension on this Sourcegraph site.
     url: String!
     # The URL to the extension on the extension registry where it lives (if this is a remote
@@ -3233,6 +3827,31 @@ type RegistryExtension implements Node {
     viewerCanAdminister: Boolean!
 }
 
+# A list of releases of a registry extension.
+type RegistryExtensionReleaseConnection {
+    # A list of releases.
+    nodes: [RegistryExtensionRelease!]!
+    # The total count of releases in the connection. This total count may be larger than the number of
+    # nodes in this object when the result is paginated.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# A release of a registry extension.
+type RegistryExtensionRelease {
+    # The semantic version of the release, or null if it was published without a version.
+    version: String
+    # The user who published the release, or null if the user was deleted.
+    creator: User
+    # The date when the release was published.
+    createdAt: String!
+    # Whether the release is yanked. Yanked releases are never used as the extension's latest release.
+    yanked: Boolean!
+    # The date when the release was yanked, or null if it is not yanked.
+    yankedAt: String
+}
+
 # A description of the extension, how to run or access it, and when to activate it.
 type ExtensionManifest {
     # The raw JSON contents of the manifest.
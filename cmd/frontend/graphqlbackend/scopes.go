@@ -0,0 +1,66 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strings"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+)
+
+// Requests by actors that were authenticated by an access token without the "user:all" scope are
+// executed against restricted variants of the schema, so that the GraphQL executor itself rejects
+// the operations that the token's scopes don't permit.
+var (
+	// readOnlyGraphQLSchema is GraphQLSchema without the mutation root.
+	readOnlyGraphQLSchema *graphql.Schema
+
+	// searchGraphQLSchema is readOnlyGraphQLSchema with searchQueryType as its query root.
+	searchGraphQLSchema *graphql.Schema
+)
+
+// searchQueryType is the query root of searchGraphQLSchema. Its fields are the only top-level
+// fields that access tokens with the "search" scope may query.
+const searchQueryType = `
+type Query {
+    # Runs a search.
+    search(
+        # The search query (such as "foo" or "repo:myrepo foo").
+        query: String = ""
+    ): Search
+}
+`
+
+// searchSchemaResolver is the root resolver of searchGraphQLSchema. Only the methods for the fields
+// of searchQueryType are reachable through it.
+type searchSchemaResolver struct {
+	schemaResolver
+}
+
+// restrictedSchemas returns the read-only and search variants of the schema.
+func restrictedSchemas(schema string) (readOnly, search string) {
+	const mutationEntryPoint = "\n    mutation: Mutation\n"
+	const queryType = "\ntype Query {"
+	if !strings.Contains(schema, mutationEntryPoint) || !strings.Contains(schema, queryType) {
+		// 🚨 SECURITY: Fail loudly instead of serving restricted schemas that aren't restricted.
+		panic("unable to derive the restricted GraphQL schemas (the schema and mutation root definitions have an unexpected format)")
+	}
+	readOnly = strings.Replace(schema, mutationEntryPoint, "\n", 1)
+	search = strings.Replace(readOnly, queryType, "\ntype UnrestrictedQuery {", 1) + searchQueryType
+	return readOnly, search
+}
+
+// SchemaForActor returns the schema to execute GraphQL requests by the actor in ctx against.
+//
+// 🚨 SECURITY: Access tokens without the "user:all" scope may not perform mutations, and access
+// tokens with the "search" scope may only query the fields of searchQueryType.
+func SchemaForActor(ctx context.Context) *graphql.Schema {
+	if err := authz.CheckWriteScope(ctx); err == nil {
+		return GraphQLSchema
+	}
+	if authz.HasScope(actor.FromContext(ctx).Scopes, authz.ScopeSearch) {
+		return searchGraphQLSchema
+	}
+	return readOnlyGraphQLSchema
+}
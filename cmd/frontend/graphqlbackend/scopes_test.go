@@ -0,0 +1,55 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+)
+
+func TestSchemaForActor(t *testing.T) {
+	tests := []struct {
+		name  string
+		actor *actor.Actor
+		want  *graphql.Schema
+	}{
+		{name: "session", actor: &actor.Actor{UID: 1}, want: GraphQLSchema},
+		{name: "user:all token", actor: &actor.Actor{UID: 1, Scopes: []string{authz.ScopeUserAll}}, want: GraphQLSchema},
+		{name: "user:read token", actor: &actor.Actor{UID: 1, Scopes: []string{authz.ScopeUserRead}}, want: readOnlyGraphQLSchema},
+		{name: "search token", actor: &actor.Actor{UID: 1, Scopes: []string{authz.ScopeSearch}}, want: searchGraphQLSchema},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := SchemaForActor(actor.WithActor(context.Background(), test.actor)); got != test.want {
+				t.Error("got unexpected schema")
+			}
+		})
+	}
+}
+
+func TestRestrictedSchemas(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  *graphql.Schema
+		query   string
+		wantErr bool
+	}{
+		{name: "read-only query", schema: readOnlyGraphQLSchema, query: `{ __typename }`},
+		{name: "read-only mutation", schema: readOnlyGraphQLSchema, query: `mutation { deleteUser(user: "VXNlcjox") { alwaysNil } }`, wantErr: true},
+		{name: "search query", schema: searchGraphQLSchema, query: `{ __typename }`},
+		{name: "search other field", schema: searchGraphQLSchema, query: `{ site { id } }`, wantErr: true},
+		{name: "search aliased field", schema: searchGraphQLSchema, query: `{ search: currentUser { id } }`, wantErr: true},
+		{name: "search fragment", schema: searchGraphQLSchema, query: `fragment F on Query { site { id } } { ...F }`, wantErr: true},
+		{name: "search mutation", schema: searchGraphQLSchema, query: `mutation { deleteUser(user: "VXNlcjox") { alwaysNil } }`, wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resp := test.schema.Exec(context.Background(), test.query, "", nil)
+			if gotErr := len(resp.Errors) > 0; gotErr != test.wantErr {
+				t.Errorf("got errors %v, want error %v", resp.Errors, test.wantErr)
+			}
+		})
+	}
+}
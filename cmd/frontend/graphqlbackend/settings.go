@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/configfiles"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/cmd/query-runner/queryrunnerapi"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
@@ -52,6 +53,13 @@ func (o *settingsResolver) Author(ctx context.Context) (*UserResolver, error) {
 // like db.Settings.CreateIfUpToDate, except it handles notifying the
 // query-runner if any saved queries have changed.
 func settingsCreateIfUpToDate(ctx context.Context, subject *settingsSubject, lastID *int32, authorUserID int32, contents string) (latestSetting *api.Settings, err error) {
+	// The global settings may not be edited if they are loaded from configuration files.
+	if subject.site != nil {
+		if err := configfiles.CheckWritable(); err != nil {
+			return nil, err
+		}
+	}
+
 	// Read current saved queries.
 	var oldSavedQueries api.PartialConfigSavedQueries
 	if err := subject.readSettings(ctx, &oldSavedQueries); err != nil {
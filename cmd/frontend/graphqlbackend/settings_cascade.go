@@ -8,6 +8,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 // settingsCascade implements the GraphQL type SettingsCascade (and the deprecated type ConfigurationCascade).
@@ -76,6 +77,19 @@ func viewerFinalSettings(ctx context.Context) (*configurationResolver, error) {
 	return cascade.Merged(ctx)
 }
 
+// ViewerFinalSettings returns the viewer's final (merged) settings, decoded.
+func ViewerFinalSettings(ctx context.Context) (*schema.Settings, error) {
+	merged, err := viewerFinalSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var settings schema.Settings
+	if err := jsonc.Unmarshal(merged.Contents(), &settings); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
+
 func (r *settingsCascade) Final(ctx context.Context) (string, error) {
 	var allSettings []string
 	subjects, err := r.Subjects(ctx)
@@ -118,6 +132,7 @@ var deeplyMergedSettingsFields = map[string]int{
 	"search.repositoryGroups": 1,
 	"motd":                    1,
 	"extensions":              1,
+	"extensions.versions":     1,
 }
 
 // mergeSettings merges the specified JSON settings documents together to produce a single JSON
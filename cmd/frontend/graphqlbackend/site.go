@@ -7,6 +7,7 @@ import (
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/siteid"
@@ -16,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/version"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/configfiles"
 )
 
 const singletonSiteGQLID = "site"
@@ -102,6 +104,8 @@ func (r *siteResolver) CanReloadSite(ctx context.Context) bool {
 	return canReloadSite && err == nil
 }
 
+func (r *siteResolver) ConfigurationReadOnly() bool { return configfiles.Enabled() }
+
 func (r *siteResolver) BuildVersion() string { return version.Version() }
 
 func (r *siteResolver) ProductVersion() string { return version.Version() }
@@ -189,10 +193,15 @@ func (r *schemaResolver) UpdateSiteConfiguration(ctx context.Context, args *stru
 		return false, fmt.Errorf("blank site configuration is invalid (you can clear the site configuration by entering an empty JSON object: {})")
 	}
 	prev := globals.ConfigurationServerFrontendOnly.Raw()
+	beforeSite := prev.Site
 	prev.Site = args.Input
 	// TODO(slimsag): future: actually pass lastID through to prevent race conditions
 	if err := globals.ConfigurationServerFrontendOnly.Write(ctx, prev); err != nil {
 		return false, err
 	}
+	audit.Record(ctx, audit.Event{
+		Action:  audit.SiteConfigUpdate,
+		Details: map[string][]string{"changedKeys": audit.ChangedKeys(beforeSite, args.Input)},
+	})
 	return globals.ConfigurationServerFrontendOnly.NeedServerRestart(), nil
 }
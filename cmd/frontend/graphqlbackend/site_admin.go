@@ -5,6 +5,7 @@ import (
 	"errors"
 
 	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 )
@@ -31,7 +32,8 @@ func (*schemaResolver) DeleteUser(ctx context.Context, args *struct {
 		return nil, errors.New("unable to delete current user")
 	}
 
-	if args.Hard != nil && *args.Hard {
+	hard := args.Hard != nil && *args.Hard
+	if hard {
 		if err := db.Users.HardDelete(ctx, userID); err != nil {
 			return nil, err
 		}
@@ -40,6 +42,12 @@ func (*schemaResolver) DeleteUser(ctx context.Context, args *struct {
 			return nil, err
 		}
 	}
+	audit.Record(ctx, audit.Event{
+		Action:     audit.UserDelete,
+		TargetType: "User",
+		TargetID:   string(args.User),
+		Details:    map[string]bool{"hard": hard},
+	})
 	return &EmptyResponse{}, nil
 }
 
@@ -59,6 +67,7 @@ func (*schemaResolver) DeleteOrganization(ctx context.Context, args *struct {
 	if err := db.Orgs.Delete(ctx, orgID); err != nil {
 		return nil, err
 	}
+	audit.Record(ctx, audit.Event{Action: audit.OrgDelete, TargetType: "Org", TargetID: string(args.Organization)})
 	return &EmptyResponse{}, nil
 }
 
@@ -88,5 +97,11 @@ func (*schemaResolver) SetUserIsSiteAdmin(ctx context.Context, args *struct {
 	if err := db.Users.SetIsSiteAdmin(ctx, userID, args.SiteAdmin); err != nil {
 		return nil, err
 	}
+	audit.Record(ctx, audit.Event{
+		Action:     audit.UserSetSiteAdmin,
+		TargetType: "User",
+		TargetID:   string(args.UserID),
+		Details:    map[string]bool{"siteAdmin": args.SiteAdmin},
+	})
 	return &EmptyResponse{}, nil
 }
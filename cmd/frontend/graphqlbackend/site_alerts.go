@@ -2,8 +2,10 @@ package graphqlbackend
 
 import (
 	"context"
+	"strings"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/configfiles"
 	"github.com/sourcegraph/sourcegraph/pkg/actor"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 
@@ -81,4 +83,24 @@ func init() {
 		}
 		return nil
 	})
+
+	// Warn about problems with the configuration files.
+	AlertFuncs = append(AlertFuncs, func(args AlertFuncArgs) []*Alert {
+		// 🚨 SECURITY: Only the site admin cares about this, and the messages may contain details
+		// of the site configuration.
+		if !args.IsSiteAdmin {
+			return nil
+		}
+
+		problems := configfiles.Problems()
+		if len(problems) == 0 {
+			return nil
+		}
+		return []*Alert{
+			{
+				TypeValue:    AlertTypeError,
+				MessageValue: "Unable to load the configuration files (`CONFIG_FILES_DIR`):\n\n- " + strings.Join(problems, "\n- "),
+			},
+		}
+	})
 }
@@ -0,0 +1,323 @@
+package graphqlbackend
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/pkg/db/confdb"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+// configurationTypes maps the GraphQL ConfigurationType enum values to config types.
+var configurationTypes = map[string]confdb.Type{
+	"SITE":     confdb.TypeSite,
+	"CRITICAL": confdb.TypeCritical,
+}
+
+func toConfigurationType(typ string) (confdb.Type, error) {
+	t, ok := configurationTypes[typ]
+	if !ok {
+		return "", fmt.Errorf("invalid configuration type %q", typ)
+	}
+	return t, nil
+}
+
+func (r *siteConfigurationResolver) Revisions(ctx context.Context, args *struct {
+	Type string
+	graphqlutil.ConnectionArgs
+	After *int32
+}) (*configurationRevisionConnectionResolver, error) {
+	// 🚨 SECURITY: The site configuration contains secret tokens and credentials,
+	// so only admins may view it.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	typ, err := toConfigurationType(args.Type)
+	if err != nil {
+		return nil, err
+	}
+	var opt confdb.ListOptions
+	if args.First != nil {
+		opt.Limit = int(*args.First)
+	}
+	if args.After != nil {
+		opt.AfterID = *args.After
+	}
+	return &configurationRevisionConnectionResolver{typ: typ, opt: opt}, nil
+}
+
+func (r *siteConfigurationResolver) Revision(ctx context.Context, args *struct {
+	Type string
+	ID   int32
+}) (*configurationRevisionResolver, error) {
+	// 🚨 SECURITY: The site configuration contains secret tokens and credentials,
+	// so only admins may view it.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	typ, err := toConfigurationType(args.Type)
+	if err != nil {
+		return nil, err
+	}
+	config, err := confdb.GetRevision(ctx, typ, args.ID)
+	if err == confdb.ErrRevisionNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &configurationRevisionResolver{config: config}, nil
+}
+
+// configurationRevisionConnectionResolver resolves a list of configuration revisions.
+//
+// 🚨 SECURITY: When instantiating a configurationRevisionConnectionResolver value, the caller MUST
+// check that the current user is a site admin.
+type configurationRevisionConnectionResolver struct {
+	typ confdb.Type
+	opt confdb.ListOptions
+
+	// cache results because they are used by multiple fields
+	once      sync.Once
+	revisions []*confdb.Config
+	err       error
+}
+
+func (r *configurationRevisionConnectionResolver) compute(ctx context.Context) ([]*confdb.Config, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.Limit > 0 {
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+		r.revisions, r.err = confdb.ListRevisions(ctx, r.typ, opt2)
+	})
+	return r.revisions, r.err
+}
+
+func (r *configurationRevisionConnectionResolver) Nodes(ctx context.Context) ([]*configurationRevisionResolver, error) {
+	revisions, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.Limit > 0 && len(revisions) > r.opt.Limit {
+		revisions = revisions[:r.opt.Limit]
+	}
+
+	l := make([]*configurationRevisionResolver, len(revisions))
+	for i, config := range revisions {
+		l[i] = &configurationRevisionResolver{config: config}
+	}
+	return l, nil
+}
+
+func (r *configurationRevisionConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := confdb.CountRevisions(ctx, r.typ)
+	return int32(count), err
+}
+
+func (r *configurationRevisionConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	revisions, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.Limit > 0 && len(revisions) > r.opt.Limit), nil
+}
+
+// configurationRevisionResolver resolves a saved revision of the site or critical configuration.
+//
+// 🚨 SECURITY: When instantiating a configurationRevisionResolver value, the caller MUST check that
+// the current user is a site admin.
+type configurationRevisionResolver struct {
+	config *confdb.Config
+}
+
+func (r *configurationRevisionResolver) ID() int32 { return r.config.ID }
+
+func (r *configurationRevisionResolver) Type() string {
+	for name, typ := range configurationTypes {
+		if string(typ) == r.config.Type {
+			return name
+		}
+	}
+	return strings.ToUpper(r.config.Type)
+}
+
+func (r *configurationRevisionResolver) Contents() string {
+	return redactedContents(r.config)
+}
+
+func (r *configurationRevisionResolver) Author(ctx context.Context) (*UserResolver, error) {
+	if r.config.AuthorUserID == nil {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, *r.config.AuthorUserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *configurationRevisionResolver) CreatedAt() string {
+	return r.config.CreatedAt.Format(time.RFC3339)
+}
+
+func (r *configurationRevisionResolver) Diff(ctx context.Context, args *struct {
+	Base *int32
+}) (*configurationRevisionDiffResolver, error) {
+	typ := confdb.Type(r.config.Type)
+	var (
+		base *confdb.Config
+		err  error
+	)
+	if args.Base != nil {
+		base, err = confdb.GetRevision(ctx, typ, *args.Base)
+	} else {
+		base, err = confdb.GetPreviousRevision(ctx, typ, r.config.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if base == nil {
+		return nil, nil
+	}
+	return &configurationRevisionDiffResolver{base: base, head: r.config}, nil
+}
+
+// configurationRevisionDiffResolver resolves the changes between two revisions of the same
+// configuration.
+type configurationRevisionDiffResolver struct {
+	base, head *confdb.Config
+}
+
+func (r *configurationRevisionDiffResolver) Base() *configurationRevisionResolver {
+	return &configurationRevisionResolver{config: r.base}
+}
+
+func (r *configurationRevisionDiffResolver) ChangedProperties() ([]string, error) {
+	// The revisions only contain one part of the unified configuration, so use the current
+	// contents of the other parts to compare them.
+	before := globals.ConfigurationServerFrontendOnly.Raw()
+	after := before
+	setRawContents(&before, confdb.Type(r.base.Type), r.base.Contents)
+	setRawContents(&after, confdb.Type(r.head.Type), r.head.Contents)
+	return conf.DiffRaw(before, after)
+}
+
+func (r *configurationRevisionDiffResolver) ContentsDiff() string {
+	return lineDiff(redactedContents(r.base), redactedContents(r.head))
+}
+
+// redactedContents returns the contents of the configuration revision, with secrets that are
+// secret references in the current configuration redacted (see conf.RedactSecrets).
+func redactedContents(c *confdb.Config) string {
+	current := rawContents(globals.ConfigurationServerFrontendOnly.Raw(), confdb.Type(c.Type))
+	return conf.RedactSecrets(c.Contents, current)
+}
+
+func rawContents(raw conftypes.RawUnified, typ confdb.Type) string {
+	switch typ {
+	case confdb.TypeSite:
+		return raw.Site
+	case confdb.TypeCritical:
+		return raw.Critical
+	}
+	return ""
+}
+
+func setRawContents(raw *conftypes.RawUnified, typ confdb.Type, contents string) {
+	switch typ {
+	case confdb.TypeSite:
+		raw.Site = contents
+	case confdb.TypeCritical:
+		raw.Critical = contents
+	}
+}
+
+// lineDiff returns a line-based diff of two texts, in which removed lines are prefixed with "-",
+// added lines with "+" and unchanged lines with " ".
+func lineDiff(before, after string) string {
+	dmp := diffmatchpatch.New()
+	a, b, lines := dmp.DiffLinesToChars(before, after)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(a, b, false), lines)
+
+	var buf bytes.Buffer
+	for _, d := range diffs {
+		prefix := " "
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			prefix = "-"
+		case diffmatchpatch.DiffInsert:
+			prefix = "+"
+		}
+		for _, line := range strings.SplitAfter(d.Text, "\n") {
+			if line == "" {
+				continue
+			}
+			buf.WriteString(prefix)
+			buf.WriteString(line)
+			if !strings.HasSuffix(line, "\n") {
+				buf.WriteString("\n")
+			}
+		}
+	}
+	return buf.String()
+}
+
+func (r *schemaResolver) RollBackConfiguration(ctx context.Context, args *struct {
+	Type     string
+	Revision int32
+}) (bool, error) {
+	// 🚨 SECURITY: The site configuration contains secret tokens and credentials,
+	// so only admins may view or change it.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return false, err
+	}
+
+	typ, err := toConfigurationType(args.Type)
+	if err != nil {
+		return false, err
+	}
+	config, err := confdb.GetRevision(ctx, typ, args.Revision)
+	if err != nil {
+		return false, err
+	}
+
+	raw := globals.ConfigurationServerFrontendOnly.Raw()
+	before := rawContents(raw, typ)
+	setRawContents(&raw, typ, config.Contents)
+	problems, err := conf.Validate(raw)
+	if err != nil {
+		return false, err
+	}
+	if len(problems) > 0 {
+		return false, fmt.Errorf("unable to roll back to %s configuration revision %d because the resulting configuration is invalid:\n%s", typ, args.Revision, strings.Join(problems, "\n"))
+	}
+	if err := globals.ConfigurationServerFrontendOnly.Write(ctx, raw); err != nil {
+		return false, err
+	}
+	action := audit.SiteConfigUpdate
+	if typ == confdb.TypeCritical {
+		action = audit.CriticalConfigUpdate
+	}
+	audit.Record(ctx, audit.Event{
+		Action: action,
+		Details: map[string]interface{}{
+			"changedKeys":          audit.ChangedKeys(before, config.Contents),
+			"rolledBackToRevision": args.Revision,
+		},
+	})
+	return globals.ConfigurationServerFrontendOnly.NeedServerRestart(), nil
+}
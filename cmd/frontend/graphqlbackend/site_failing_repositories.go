@@ -0,0 +1,59 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+	"github.com/sourcegraph/sourcegraph/pkg/repoupdater"
+	"github.com/sourcegraph/sourcegraph/pkg/repoupdater/protocol"
+)
+
+func (r *siteResolver) FailingRepositories(ctx context.Context) ([]*failingRepositoryResolver, error) {
+	// 🚨 SECURITY: Only site admins may view this information.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := repoupdater.DefaultClient.FailingRepos(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]*failingRepositoryResolver, 0, len(result.Repos))
+	for _, failing := range result.Repos {
+		repo, err := repositoryByIDInt32(ctx, api.RepoID(failing.ID))
+		if errcode.IsNotFound(err) {
+			// The repo was deleted since its last update.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		resolvers = append(resolvers, &failingRepositoryResolver{repo: repo, failing: failing})
+	}
+	return resolvers, nil
+}
+
+type failingRepositoryResolver struct {
+	repo    *repositoryResolver
+	failing *protocol.FailingRepo
+}
+
+func (r *failingRepositoryResolver) Repository() *repositoryResolver { return r.repo }
+
+func (r *failingRepositoryResolver) Failures() int32 { return int32(r.failing.Failures) }
+
+func (r *failingRepositoryResolver) ErrorKind() string {
+	if r.failing.ErrorKind == "" {
+		return "OTHER"
+	}
+	return strings.ToUpper(strings.Replace(r.failing.ErrorKind, "-", "_", -1))
+}
+
+func (r *failingRepositoryResolver) LastError() string { return r.failing.LastError }
+
+func (r *failingRepositoryResolver) Due() string { return r.failing.Due.Format(time.RFC3339) }
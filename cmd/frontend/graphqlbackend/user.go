@@ -8,6 +8,7 @@ import (
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/envvar"
@@ -204,6 +205,12 @@ func (*schemaResolver) UpdateUser(ctx context.Context, args *struct {
 	if err := db.Users.Update(ctx, userID, update); err != nil {
 		return nil, err
 	}
+	audit.Record(ctx, audit.Event{
+		Action:     audit.UserUpdate,
+		TargetType: "User",
+		TargetID:   string(args.User),
+		Details:    map[string]*string{"username": args.Username, "displayName": args.DisplayName, "avatarURL": args.AvatarURL},
+	})
 	return &EmptyResponse{}, nil
 }
 
@@ -2,6 +2,7 @@ package graphqlbackend
 
 import (
 	"context"
+	"time"
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
@@ -51,6 +52,16 @@ func (r *userEmailResolver) VerificationPending() bool {
 }
 func (r *userEmailResolver) User() *UserResolver { return r.user }
 
+func (r *userEmailResolver) BouncedAt() *string {
+	if r.userEmail.BouncedAt == nil {
+		return nil
+	}
+	s := r.userEmail.BouncedAt.Format(time.RFC3339)
+	return &s
+}
+
+func (r *userEmailResolver) BounceReason() *string { return r.userEmail.BounceReason }
+
 func (r *userEmailResolver) ViewerCanManuallyVerify(ctx context.Context) (bool, error) {
 	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err == backend.ErrNotAuthenticated || err == backend.ErrMustBeSiteAdmin {
 		return false, nil
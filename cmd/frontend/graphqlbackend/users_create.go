@@ -3,6 +3,7 @@ package graphqlbackend
 import (
 	"context"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
@@ -34,6 +35,12 @@ func (*schemaResolver) CreateUser(ctx context.Context, args *struct {
 	if err != nil {
 		return nil, err
 	}
+	audit.Record(ctx, audit.Event{
+		Action:     audit.UserCreate,
+		TargetType: "User",
+		TargetID:   string(marshalUserID(user.ID)),
+		Details:    map[string]string{"username": user.Username},
+	})
 	return &createUserResult{user: user}, nil
 }
 
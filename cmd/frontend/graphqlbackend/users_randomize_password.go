@@ -4,6 +4,7 @@ import (
 	"context"
 
 	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
@@ -46,6 +47,7 @@ func (*schemaResolver) RandomizeUserPassword(ctx context.Context, args *struct {
 	if err := db.Users.RandomizePasswordAndClearPasswordResetRateLimit(ctx, userID); err != nil {
 		return nil, err
 	}
+	audit.Record(ctx, audit.Event{Action: audit.UserRandomizePassword, TargetType: "User", TargetID: string(args.User)})
 
 	return &randomizeUserPasswordResult{userID: userID}, nil
 }
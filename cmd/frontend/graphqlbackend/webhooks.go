@@ -0,0 +1,385 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/webhooks"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+const webhookIDKind = "Webhook"
+
+func marshalWebhookID(id int32) graphql.ID {
+	return relay.MarshalID(webhookIDKind, id)
+}
+
+func unmarshalWebhookID(id graphql.ID) (webhookID int32, err error) {
+	if kind := relay.UnmarshalKind(id); kind != webhookIDKind {
+		err = fmt.Errorf("expected graphql ID to have kind %q; got %q", webhookIDKind, kind)
+		return
+	}
+	err = relay.UnmarshalSpec(id, &webhookID)
+	return
+}
+
+// checkCanAdministerWebhook returns an error if the current user can't view or modify the webhook.
+// Site webhooks can only be administered by site admins, and a user's webhooks by the user and
+// site admins.
+func checkCanAdministerWebhook(ctx context.Context, w *db.Webhook) error {
+	if w.UserID == 0 {
+		return backend.CheckCurrentUserIsSiteAdmin(ctx)
+	}
+	return backend.CheckSiteAdminOrSameUser(ctx, w.UserID)
+}
+
+func webhookByID(ctx context.Context, id graphql.ID) (*webhookResolver, error) {
+	webhookID, err := unmarshalWebhookID(id)
+	if err != nil {
+		return nil, err
+	}
+	w, err := db.Webhooks.GetByID(ctx, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Only site admins and the webhook's user can view a webhook.
+	if err := checkCanAdministerWebhook(ctx, w); err != nil {
+		return nil, err
+	}
+	return &webhookResolver{webhook: w}, nil
+}
+
+func (r *siteResolver) Webhooks(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+}) (*webhookConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins can list the site's webhooks.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	opt := db.WebhooksListOptions{Site: true}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &webhookConnectionResolver{opt: opt}, nil
+}
+
+func (r *UserResolver) Webhooks(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+}) (*webhookConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins and the user can list a user's webhooks.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
+		return nil, err
+	}
+
+	opt := db.WebhooksListOptions{UserID: r.user.ID}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &webhookConnectionResolver{opt: opt}, nil
+}
+
+// webhookConnectionResolver resolves a list of webhooks.
+//
+// 🚨 SECURITY: When instantiating a webhookConnectionResolver value, the caller MUST check that the
+// actor can administer the listed webhooks.
+type webhookConnectionResolver struct {
+	opt db.WebhooksListOptions
+
+	// cache results because they are used by multiple fields
+	once     sync.Once
+	webhooks []*db.Webhook
+	err      error
+}
+
+func (r *webhookConnectionResolver) compute(ctx context.Context) ([]*db.Webhook, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.LimitOffset != nil {
+			tmp := *opt2.LimitOffset
+			opt2.LimitOffset = &tmp
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+
+		r.webhooks, r.err = db.Webhooks.List(ctx, opt2)
+	})
+	return r.webhooks, r.err
+}
+
+func (r *webhookConnectionResolver) Nodes(ctx context.Context) ([]*webhookResolver, error) {
+	webhooks, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(webhooks) > r.opt.Limit {
+		webhooks = webhooks[:r.opt.Limit]
+	}
+
+	l := make([]*webhookResolver, len(webhooks))
+	for i, w := range webhooks {
+		l[i] = &webhookResolver{webhook: w}
+	}
+	return l, nil
+}
+
+func (r *webhookConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := db.Webhooks.Count(ctx, r.opt)
+	return int32(count), err
+}
+
+func (r *webhookConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	webhooks, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(webhooks) > r.opt.Limit), nil
+}
+
+// webhookResolver resolves a webhook. The webhook's secret is never exposed.
+//
+// 🚨 SECURITY: When instantiating a webhookResolver value, the caller MUST check that the actor can
+// administer the webhook.
+type webhookResolver struct {
+	webhook *db.Webhook
+}
+
+func (r *webhookResolver) ID() graphql.ID { return marshalWebhookID(r.webhook.ID) }
+
+func (r *webhookResolver) User(ctx context.Context) (*UserResolver, error) {
+	if r.webhook.UserID == 0 {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, r.webhook.UserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil // the user was deleted
+	}
+	return user, err
+}
+
+func (r *webhookResolver) URL() string { return r.webhook.URL }
+
+func (r *webhookResolver) EventTypes() []string { return r.webhook.EventTypes }
+
+func (r *webhookResolver) Active() bool { return r.webhook.Active }
+
+func (r *webhookResolver) CreatedAt() string { return r.webhook.CreatedAt.Format(time.RFC3339) }
+
+func (r *webhookResolver) UpdatedAt() string { return r.webhook.UpdatedAt.Format(time.RFC3339) }
+
+func (r *webhookResolver) Deliveries(args *struct {
+	graphqlutil.ConnectionArgs
+}) *webhookDeliveryConnectionResolver {
+	opt := db.WebhookDeliveriesListOptions{WebhookID: r.webhook.ID}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &webhookDeliveryConnectionResolver{opt: opt}
+}
+
+// webhookDeliveryConnectionResolver resolves a list of webhook deliveries.
+//
+// 🚨 SECURITY: When instantiating a webhookDeliveryConnectionResolver value, the caller MUST check
+// that the actor can administer the webhook.
+type webhookDeliveryConnectionResolver struct {
+	opt db.WebhookDeliveriesListOptions
+
+	// cache results because they are used by multiple fields
+	once       sync.Once
+	deliveries []*db.WebhookDelivery
+	err        error
+}
+
+func (r *webhookDeliveryConnectionResolver) compute(ctx context.Context) ([]*db.WebhookDelivery, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.LimitOffset != nil {
+			tmp := *opt2.LimitOffset
+			opt2.LimitOffset = &tmp
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+
+		r.deliveries, r.err = db.WebhookDeliveries.List(ctx, opt2)
+	})
+	return r.deliveries, r.err
+}
+
+func (r *webhookDeliveryConnectionResolver) Nodes(ctx context.Context) ([]*webhookDeliveryResolver, error) {
+	deliveries, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(deliveries) > r.opt.Limit {
+		deliveries = deliveries[:r.opt.Limit]
+	}
+
+	l := make([]*webhookDeliveryResolver, len(deliveries))
+	for i, d := range deliveries {
+		l[i] = &webhookDeliveryResolver{delivery: d}
+	}
+	return l, nil
+}
+
+func (r *webhookDeliveryConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := db.WebhookDeliveries.Count(ctx, r.opt)
+	return int32(count), err
+}
+
+func (r *webhookDeliveryConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	deliveries, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(deliveries) > r.opt.Limit), nil
+}
+
+type webhookDeliveryResolver struct {
+	delivery *db.WebhookDelivery
+}
+
+func (r *webhookDeliveryResolver) ID() string { return strconv.FormatInt(r.delivery.ID, 10) }
+
+func (r *webhookDeliveryResolver) EventType() string { return r.delivery.EventType }
+
+func (r *webhookDeliveryResolver) Payload() jsonValue { return jsonValue{value: r.delivery.Payload} }
+
+func (r *webhookDeliveryResolver) State() string { return strings.ToUpper(r.delivery.State) }
+
+func (r *webhookDeliveryResolver) Attempts() int32 { return r.delivery.Attempts }
+
+func (r *webhookDeliveryResolver) LastAttemptAt() *string {
+	if r.delivery.LastAttemptAt == nil {
+		return nil
+	}
+	s := r.delivery.LastAttemptAt.Format(time.RFC3339)
+	return &s
+}
+
+func (r *webhookDeliveryResolver) NextAttemptAt() *string {
+	if r.delivery.State != db.WebhookDeliveryStatePending {
+		return nil
+	}
+	s := r.delivery.NextAttemptAt.Format(time.RFC3339)
+	return &s
+}
+
+func (r *webhookDeliveryResolver) ResponseStatusCode() *int32 { return r.delivery.ResponseStatusCode }
+
+func (r *webhookDeliveryResolver) ResponseBody() *string { return r.delivery.ResponseBody }
+
+func (r *webhookDeliveryResolver) Error() *string { return r.delivery.Error }
+
+func (r *webhookDeliveryResolver) CreatedAt() string {
+	return r.delivery.CreatedAt.Format(time.RFC3339)
+}
+
+func (*schemaResolver) CreateWebhook(ctx context.Context, args *struct {
+	User       *graphql.ID
+	URL        string
+	Secret     string
+	EventTypes []string
+}) (*webhookResolver, error) {
+	w := &db.Webhook{URL: args.URL, Secret: args.Secret, EventTypes: args.EventTypes, Active: true}
+	if args.User != nil {
+		var err error
+		if w.UserID, err = UnmarshalUserID(*args.User); err != nil {
+			return nil, err
+		}
+	}
+
+	// 🚨 SECURITY: Only site admins can create site webhooks, and only site admins and the user can
+	// create a user's webhooks.
+	if err := checkCanAdministerWebhook(ctx, w); err != nil {
+		return nil, err
+	}
+
+	if err := webhooks.ValidateURL(w.URL); err != nil {
+		return nil, err
+	}
+	if err := webhooks.ValidateEventTypes(w.EventTypes, w.UserID == 0); err != nil {
+		return nil, err
+	}
+	if w.Secret == "" {
+		return nil, errors.New("webhook secret must not be empty")
+	}
+	if err := db.Webhooks.Create(ctx, w); err != nil {
+		return nil, err
+	}
+	recordWebhookEvent(ctx, audit.WebhookCreate, w)
+	return &webhookResolver{webhook: w}, nil
+}
+
+func (*schemaResolver) UpdateWebhook(ctx context.Context, args *struct {
+	Webhook    graphql.ID
+	URL        *string
+	Secret     *string
+	EventTypes *[]string
+	Active     *bool
+}) (*webhookResolver, error) {
+	// 🚨 SECURITY: webhookByID checks that the current user can administer the webhook.
+	r, err := webhookByID(ctx, args.Webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	update := db.WebhookUpdate{URL: args.URL, Secret: args.Secret, Active: args.Active}
+	if args.URL != nil {
+		if err := webhooks.ValidateURL(*args.URL); err != nil {
+			return nil, err
+		}
+	}
+	if args.Secret != nil && *args.Secret == "" {
+		return nil, errors.New("webhook secret must not be empty")
+	}
+	if args.EventTypes != nil {
+		if err := webhooks.ValidateEventTypes(*args.EventTypes, r.webhook.UserID == 0); err != nil {
+			return nil, err
+		}
+		update.EventTypes = *args.EventTypes
+	}
+	w, err := db.Webhooks.Update(ctx, r.webhook.ID, update)
+	if err != nil {
+		return nil, err
+	}
+	recordWebhookEvent(ctx, audit.WebhookUpdate, w)
+	return &webhookResolver{webhook: w}, nil
+}
+
+func (*schemaResolver) DeleteWebhook(ctx context.Context, args *struct {
+	Webhook graphql.ID
+}) (*EmptyResponse, error) {
+	// 🚨 SECURITY: webhookByID checks that the current user can administer the webhook.
+	r, err := webhookByID(ctx, args.Webhook)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Webhooks.Delete(ctx, r.webhook.ID); err != nil {
+		return nil, err
+	}
+	recordWebhookEvent(ctx, audit.WebhookDelete, r.webhook)
+	return &EmptyResponse{}, nil
+}
+
+// recordWebhookEvent records the creation, update, or deletion of a webhook in the audit log. The
+// webhook's secret is not recorded.
+func recordWebhookEvent(ctx context.Context, action string, w *db.Webhook) {
+	details := map[string]interface{}{
+		"url":        w.URL,
+		"eventTypes": w.EventTypes,
+		"active":     w.Active,
+	}
+	if w.UserID != 0 {
+		details["user"] = audit.UserID(w.UserID)
+	}
+	audit.Record(ctx, audit.Event{
+		Action:     action,
+		TargetType: webhookIDKind,
+		TargetID:   string(marshalWebhookID(w.ID)),
+		Details:    details,
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/errcode"
 	"github.com/sourcegraph/sourcegraph/pkg/hubspot/hubspotutil"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/tracking"
@@ -181,6 +182,12 @@ func HandleSignIn(w http.ResponseWriter, r *http.Request) {
 		httpLogAndError(w, "Authentication failed", http.StatusUnauthorized)
 		return
 	}
+	// 🚨 SECURITY: suspended users may not sign in. This is checked only after the password is
+	// verified so that the response doesn't reveal whether an account is suspended.
+	if usr.Suspended {
+		httpLogAndError(w, "User account is suspended", http.StatusForbidden, "userID", usr.ID)
+		return
+	}
 	actor := &actor.Actor{UID: usr.ID}
 
 	// Write the session cookie
@@ -188,6 +195,8 @@ func HandleSignIn(w http.ResponseWriter, r *http.Request) {
 		httpLogAndError(w, "Could not create new user session", http.StatusInternalServerError)
 		return
 	}
+
+	authz.RequestPermissionsSync(usr.ID)
 }
 
 func httpLogAndError(w http.ResponseWriter, msg string, code int, errArgs ...interface{}) {
@@ -141,13 +141,19 @@ func (c configurationSource) Write(ctx context.Context, input conftypes.RawUnifi
 		return errors.Wrap(err, "confdb.SiteGetLatest")
 	}
 
-	_, err = confdb.CriticalCreateIfUpToDate(ctx, &critical.ID, input.Critical)
-	if err != nil {
-		return errors.Wrap(err, "confdb.CriticalCreateIfUpToDate")
+	// Only save the configs that changed, so that the history of each config only contains
+	// actual edits.
+	if input.Critical != critical.Contents {
+		_, err = confdb.CriticalCreateIfUpToDate(ctx, &critical.ID, input.Critical)
+		if err != nil {
+			return errors.Wrap(err, "confdb.CriticalCreateIfUpToDate")
+		}
 	}
-	_, err = confdb.SiteCreateIfUpToDate(ctx, &site.ID, input.Site)
-	if err != nil {
-		return errors.Wrap(err, "confdb.SiteCreateIfUpToDate")
+	if input.Site != site.Contents {
+		_, err = confdb.SiteCreateIfUpToDate(ctx, &site.ID, input.Site)
+		if err != nil {
+			return errors.Wrap(err, "confdb.SiteCreateIfUpToDate")
+		}
 	}
 	return nil
 }
@@ -22,6 +22,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/session"
 	"github.com/sourcegraph/sourcegraph/pkg/actor"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/requestclient"
 	tracepkg "github.com/sourcegraph/sourcegraph/pkg/trace"
 	"github.com/sourcegraph/sourcegraph/pkg/version"
 )
@@ -73,6 +74,7 @@ func newExternalHTTPHandler(ctx context.Context) (http.Handler, error) {
 	h = healthCheckMiddleware(h)
 	h = gcontext.ClearHandler(h)
 	h = middleware.Trace(h)
+	h = requestclient.HTTPMiddleware(h)
 	return h, nil
 }
 
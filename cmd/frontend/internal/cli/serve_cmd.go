@@ -20,9 +20,13 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/pkg/updatecheck"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/bg"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/cli/loghandlers"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/configfiles"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/goroutine"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/discussions/mailreply"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/discussions/reviewimport"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/emailoutbox"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/siteid"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/webhooks"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/pkg/debugserver"
@@ -30,6 +34,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/processrestart"
 	"github.com/sourcegraph/sourcegraph/pkg/sysreq"
 	"github.com/sourcegraph/sourcegraph/pkg/tracer"
+	"github.com/sourcegraph/sourcegraph/pkg/txemail"
 	"github.com/sourcegraph/sourcegraph/pkg/version"
 	"github.com/sourcegraph/sourcegraph/pkg/vfsutil"
 	log15 "gopkg.in/inconshreveable/log15.v2"
@@ -97,7 +102,11 @@ func Main() error {
 	if err := dbconn.ConnectToDB(""); err != nil {
 		log.Fatal(err)
 	}
-	globals.ConfigurationServerFrontendOnly = conf.InitConfigurationServerFrontendOnly(&configurationSource{})
+	var configSource conf.ConfigurationSource = &configurationSource{}
+	if configfiles.Enabled() {
+		configSource = &configfiles.Source{DB: configSource}
+	}
+	globals.ConfigurationServerFrontendOnly = conf.InitConfigurationServerFrontendOnly(configSource)
 	conf.MustValidateDefaults()
 	handleConfigOverrides()
 
@@ -170,6 +179,14 @@ func Main() error {
 
 	goroutine.Go(func() { bg.MigrateAllSettingsMOTDToNotices(context.Background()) })
 	goroutine.Go(mailreply.StartWorker)
+	goroutine.Go(reviewimport.StartWorker)
+	goroutine.Go(webhooks.StartWorker)
+	// Send emails through the outbox, so that failed deliveries are retried.
+	txemail.Outbox = emailoutbox.Enqueue
+	goroutine.Go(emailoutbox.StartWorker)
+	if configfiles.Enabled() {
+		goroutine.Go(func() { configfiles.Watch(context.Background()) })
+	}
 	go updatecheck.Start()
 	if hooks.AfterDBInit != nil {
 		hooks.AfterDBInit()
@@ -0,0 +1,101 @@
+// Package configfiles loads the site configuration, critical configuration, external services and
+// global settings from a directory of JSONC files (instead of letting site admins edit them in the
+// UI) and reconciles them into the database whenever the files change.
+//
+// The directory may contain the following files:
+//
+//	site.json                      the site configuration
+//	critical.json                  the critical configuration
+//	global-settings.json           the global settings
+//	external-services/*.json       one file per external service (see externalServiceFile)
+//
+// A missing site.json, critical.json or global-settings.json file (or external-services directory)
+// leaves the corresponding configuration in the database unchanged (but it still can't be edited in
+// the UI). Otherwise, the external services in the database are made to match the files in the
+// external-services directory, which means that external services without a file are deleted.
+package configfiles
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/pkg/env"
+	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
+)
+
+// Dir is the directory that the configuration files are loaded from. If empty, the configuration
+// is stored in (and edited in) the database only.
+var Dir = env.Get("CONFIG_FILES_DIR", "", "directory of JSONC files (site.json, critical.json, global-settings.json and external-services/*.json) to load the site configuration, external services and global settings from; if set, they can't be edited in the UI")
+
+const (
+	siteFile            = "site.json"
+	criticalFile        = "critical.json"
+	globalSettingsFile  = "global-settings.json"
+	externalServicesDir = "external-services"
+)
+
+// ErrReadOnly is returned when a site admin attempts to edit configuration that is loaded from the
+// configuration files.
+var ErrReadOnly = errors.New("the site configuration, external services and global settings are loaded from configuration files (CONFIG_FILES_DIR) and can't be edited in the UI: edit the files instead")
+
+// Enabled reports whether the configuration is loaded from the configuration files in Dir.
+func Enabled() bool { return Dir != "" }
+
+// CheckWritable returns ErrReadOnly if the configuration is loaded from the configuration files
+// (and therefore may not be edited through the API).
+func CheckWritable() error {
+	if Enabled() {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+var (
+	problemsMu sync.Mutex
+	problems   = map[string][]string{} // problems by the part of the configuration they affect
+)
+
+// Problems returns messages describing the problems (such as invalid JSON) that were found the last
+// time the configuration files were loaded and reconciled into the database.
+func Problems() []string {
+	problemsMu.Lock()
+	defer problemsMu.Unlock()
+	var all []string
+	for _, p := range problems {
+		all = append(all, p...)
+	}
+	sort.Strings(all)
+	return all
+}
+
+func setProblems(part string, p []string) {
+	problemsMu.Lock()
+	defer problemsMu.Unlock()
+	problems[part] = p
+}
+
+// readJSONCFile returns the contents of the JSONC file at path, or nil if the file doesn't exist.
+// An error is returned if the contents aren't valid JSONC.
+func readJSONCFile(path string) (*string, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	contents := string(data)
+	if strings.TrimSpace(contents) == "" {
+		return nil, fmt.Errorf("%s: file is empty (use {} for an empty JSON object)", filepath.Base(path))
+	}
+	if _, err := jsonc.Parse(contents); err != nil {
+		return nil, fmt.Errorf("%s: %s", filepath.Base(path), err)
+	}
+	return &contents, nil
+}
@@ -0,0 +1,136 @@
+package configfiles
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/conf/conftypes"
+)
+
+func writeFiles(t *testing.T, files map[string]string) (dir string) {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "configfiles")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestReadExternalServiceFiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.json": `{"kind": "github", "config": {"url": "https://github.com", /* comment */ "token": "t",}}`,
+		"b.json": `{"kind": "GITLAB", "displayName": "My GitLab", "config": {"url": "https://gitlab.com"}}`,
+		"c.json": `{"kind": "GITHUB", "displayName": "a", "config": {}}`,
+		"d.json": `{"kind": "GITHUB"}`,
+		"e.json": `{`,
+		"f.txt":  `ignored`,
+	})
+	defer os.RemoveAll(dir)
+
+	svcs, problems := readExternalServiceFiles(dir)
+	want := []*types.ExternalService{
+		{Kind: "GITHUB", DisplayName: "a", Config: `{"url": "https://github.com", /* comment */ "token": "t",}`},
+		{Kind: "GITLAB", DisplayName: "My GitLab", Config: `{"url": "https://gitlab.com"}`},
+	}
+	if !reflect.DeepEqual(svcs, want) {
+		t.Errorf("got external services %+v, want %+v", svcs, want)
+	}
+	if len(problems) != 3 {
+		t.Errorf("got problems %q, want 3 problems (for c.json, d.json and e.json)", problems)
+	}
+}
+
+func TestReconcileExternalServices_missingDir(t *testing.T) {
+	dir := writeFiles(t, map[string]string{siteFile: `{}`})
+	defer os.RemoveAll(dir)
+	defer func(orig string) { Dir = orig }(Dir)
+	Dir = dir
+
+	db.Mocks.ExternalServices.List = func(db.ExternalServicesListOptions) ([]*types.ExternalService, error) {
+		t.Fatal("external services were listed (and would be deleted) without an external-services directory")
+		return nil, nil
+	}
+	defer func() { db.Mocks.ExternalServices = db.MockExternalServices{} }()
+
+	if problems := reconcileExternalServices(context.Background()); len(problems) != 0 {
+		t.Errorf("got problems %q, want none", problems)
+	}
+}
+
+type mockSource struct {
+	raw    conftypes.RawUnified
+	writes int
+}
+
+func (s *mockSource) Read(ctx context.Context) (conftypes.RawUnified, error) { return s.raw, nil }
+
+func (s *mockSource) Write(ctx context.Context, raw conftypes.RawUnified) error {
+	s.raw = raw
+	s.writes++
+	return nil
+}
+
+func TestSource(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		siteFile: `{"maxReposToSearch": 1}`,
+	})
+	defer os.RemoveAll(dir)
+	defer func(orig string) { Dir = orig }(Dir)
+	Dir = dir
+
+	db := &mockSource{raw: conftypes.RawUnified{Site: `{}`, Critical: `{"a": 1}`}}
+	s := &Source{DB: db}
+
+	// The site configuration is saved; the critical configuration is left unchanged.
+	want := conftypes.RawUnified{Site: `{"maxReposToSearch": 1}`, Critical: `{"a": 1}`}
+	for i := 0; i < 2; i++ {
+		raw, err := s.Read(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(raw, want) {
+			t.Errorf("got %+v, want %+v", raw, want)
+		}
+	}
+	if db.writes != 1 {
+		t.Errorf("got %d writes, want 1", db.writes)
+	}
+	if problems := Problems(); len(problems) != 0 {
+		t.Errorf("got problems %q, want none", problems)
+	}
+
+	// Invalid files are reported and the configuration in the database is used.
+	if err := ioutil.WriteFile(filepath.Join(dir, criticalFile), []byte(`{`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := s.Read(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(raw, want) {
+		t.Errorf("got %+v, want %+v", raw, want)
+	}
+	if problems := Problems(); len(problems) != 1 || !strings.HasPrefix(problems[0], criticalFile+":") {
+		t.Errorf("got problems %q, want a problem with %s", problems, criticalFile)
+	}
+
+	if err := s.Write(context.Background(), want); err != ErrReadOnly {
+		t.Errorf("got error %v, want %v", err, ErrReadOnly)
+	}
+}
@@ -0,0 +1,222 @@
+package configfiles
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/jsonx"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
+	"github.com/sourcegraph/sourcegraph/pkg/repoupdater"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// Watch reconciles the external services and global settings in the configuration files into the
+// database every few seconds, until ctx is done.
+func Watch(ctx context.Context) {
+	for {
+		setProblems("externalServices", reconcileExternalServices(ctx))
+		setProblems("globalSettings", reconcileGlobalSettings(ctx))
+
+		select {
+		case <-time.After(5 * time.Second):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// externalServiceFile is the format of the files in the external-services directory.
+type externalServiceFile struct {
+	// Kind is the kind of the external service (e.g., "GITHUB").
+	Kind string `json:"kind"`
+	// DisplayName identifies the external service. It defaults to the file name without the
+	// extension.
+	DisplayName string `json:"displayName"`
+	// Config is the configuration of the external service.
+	Config json.RawMessage `json:"config"`
+}
+
+// readExternalServiceFiles returns the external services defined by the files in dir, sorted by
+// file name, and messages describing the files that couldn't be loaded.
+func readExternalServiceFiles(dir string) (svcs []*types.ExternalService, problems []string) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+
+	seen := make(map[string]string, len(paths)) // display name -> file name
+	for _, path := range paths {
+		name := filepath.Join(externalServicesDir, filepath.Base(path))
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		var f externalServiceFile
+		if err := jsonc.Unmarshal(string(data), &f); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s", name, err))
+			continue
+		}
+		if f.Kind == "" || len(f.Config) == 0 {
+			problems = append(problems, fmt.Sprintf(`%s: the "kind" and "config" properties are required`, name))
+			continue
+		}
+		if f.DisplayName == "" {
+			f.DisplayName = strings.TrimSuffix(filepath.Base(path), ".json")
+		}
+		if other, ok := seen[f.DisplayName]; ok {
+			problems = append(problems, fmt.Sprintf("%s: the display name %q is already used by %s", name, f.DisplayName, other))
+			continue
+		}
+		seen[f.DisplayName] = name
+
+		svcs = append(svcs, &types.ExternalService{
+			Kind:        strings.ToUpper(f.Kind),
+			DisplayName: f.DisplayName,
+			Config:      propertyText(string(data), "config"),
+		})
+	}
+	return svcs, problems
+}
+
+// propertyText returns the text of the value of the top-level property in the JSONC document text
+// as it appears in the document (including comments and the original order of nested properties),
+// so that reformatting doesn't cause spurious updates. The document must be valid.
+func propertyText(text, property string) string {
+	root, _ := jsonx.ParseTree(text, jsonx.ParseOptions{Comments: true, TrailingCommas: true})
+	node := jsonx.FindNodeAtLocation(root, jsonx.MakePath(property))
+	if node == nil {
+		return ""
+	}
+	return string([]rune(text)[node.Offset : node.Offset+node.Length])
+}
+
+// reconcileExternalServices makes the external services in the database match the files in the
+// external-services directory. External services are matched by display name. If the directory
+// doesn't exist, the external services in the database are left unchanged. It returns messages
+// describing the problems it encountered.
+func reconcileExternalServices(ctx context.Context) []string {
+	dir := filepath.Join(Dir, externalServicesDir)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		// Like a missing configuration file, so that (e.g.) a volume that isn't mounted doesn't
+		// delete all external services.
+		return nil
+	} else if err != nil {
+		return []string{err.Error()}
+	}
+
+	want, problems := readExternalServiceFiles(dir)
+	// Don't delete the external services of files that couldn't be loaded.
+	canDelete := len(problems) == 0
+
+	have, err := db.ExternalServices.List(ctx, db.ExternalServicesListOptions{})
+	if err != nil {
+		return append(problems, fmt.Sprintf("unable to list external services: %s", err))
+	}
+	byName := make(map[string]*types.ExternalService, len(have))
+	for _, svc := range have {
+		if _, ok := byName[svc.DisplayName]; !ok {
+			byName[svc.DisplayName] = svc
+		}
+	}
+
+	var changed []*types.ExternalService
+	keep := make(map[int64]bool, len(want))
+	for _, svc := range want {
+		if existing := byName[svc.DisplayName]; existing != nil && existing.Kind == svc.Kind {
+			keep[existing.ID] = true
+			if existing.Config == svc.Config {
+				continue
+			}
+			if err := db.ExternalServices.Update(ctx, existing.ID, &db.ExternalServiceUpdate{Config: &svc.Config}); err != nil {
+				problems = append(problems, fmt.Sprintf("unable to update external service %q: %s", svc.DisplayName, err))
+				continue
+			}
+			existing.Config = svc.Config
+			changed = append(changed, existing)
+			continue
+		}
+
+		// If an external service with the same display name but a different kind exists, it is
+		// deleted below.
+		if err := db.ExternalServices.Create(ctx, svc); err != nil {
+			problems = append(problems, fmt.Sprintf("unable to create external service %q: %s", svc.DisplayName, err))
+			continue
+		}
+		changed = append(changed, svc)
+	}
+
+	if canDelete {
+		for _, svc := range have {
+			if keep[svc.ID] {
+				continue
+			}
+			if err := db.ExternalServices.Delete(ctx, svc.ID); err != nil {
+				problems = append(problems, fmt.Sprintf("unable to delete external service %q: %s", svc.DisplayName, err))
+				continue
+			}
+			changed = append(changed, svc)
+		}
+	}
+
+	// Eagerly trigger a repo-updater sync of the changed external services.
+	for _, svc := range changed {
+		res, err := repoupdater.DefaultClient.SyncExternalService(ctx, api.ExternalService{
+			ID:          svc.ID,
+			Kind:        svc.Kind,
+			DisplayName: svc.DisplayName,
+			Config:      svc.Config,
+			CreatedAt:   svc.CreatedAt,
+			UpdatedAt:   svc.UpdatedAt,
+			DeletedAt:   svc.DeletedAt,
+		})
+		if err == nil {
+			err = res.Error
+		}
+		if err != nil {
+			log15.Warn("Unable to sync external service loaded from configuration files.", "externalService", svc.DisplayName, "error", err)
+		}
+	}
+
+	return problems
+}
+
+// reconcileGlobalSettings saves the contents of the global-settings.json file as the global
+// settings if they differ from the latest global settings. It returns messages describing the
+// problems it encountered.
+func reconcileGlobalSettings(ctx context.Context) []string {
+	contents, err := readJSONCFile(filepath.Join(Dir, globalSettingsFile))
+	if err != nil {
+		return []string{err.Error()}
+	}
+	if contents == nil {
+		return nil
+	}
+
+	subject := api.SettingsSubject{Site: true}
+	latest, err := db.Settings.GetLatest(ctx, subject)
+	if err != nil {
+		return []string{fmt.Sprintf("unable to get global settings: %s", err)}
+	}
+	if latest != nil && latest.Contents == *contents {
+		return nil
+	}
+
+	var lastID *int32
+	if latest != nil {
+		lastID = &latest.ID
+	}
+	if _, err := db.Settings.CreateIfUpToDate(ctx, subject, lastID, nil, *contents); err != nil {
+		return []string{fmt.Sprintf("unable to save global settings: %s", err)}
+	}
+	return nil
+}
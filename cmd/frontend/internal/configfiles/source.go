@@ -0,0 +1,88 @@
+package configfiles
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/conf/conftypes"
+)
+
+// Source is a conf.ConfigurationSource that loads the site and critical configuration from the
+// site.json and critical.json files in Dir. Whenever the files change, their contents are saved to
+// the database (through DB) so that the configuration history records the changes.
+type Source struct {
+	DB conf.ConfigurationSource // the source that stores the configuration in the database
+
+	mu sync.Mutex // serializes reads so that each change is only saved once
+}
+
+var _ conf.ConfigurationSource = &Source{}
+
+// Read implements conf.ConfigurationSource. If a file can't be read (e.g., because it contains
+// invalid JSON), the configuration in the database is used instead and the problem is reported by
+// Problems.
+func (s *Source) Read(ctx context.Context) (conftypes.RawUnified, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	raw, err := s.DB.Read(ctx)
+	if err != nil {
+		return raw, err
+	}
+
+	var problems []string
+	want := raw
+	for _, f := range []struct {
+		name     string
+		contents *string
+	}{
+		{name: siteFile, contents: &want.Site},
+		{name: criticalFile, contents: &want.Critical},
+	} {
+		contents, err := readJSONCFile(filepath.Join(Dir, f.name))
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		if contents != nil {
+			*f.contents = *contents
+		}
+	}
+
+	if want.Site != raw.Site || want.Critical != raw.Critical {
+		if err := s.DB.Write(ctx, want); err != nil {
+			problems = append(problems, fmt.Sprintf("unable to save the site and critical configuration: %s", err))
+			want = raw
+		} else {
+			recordChange(ctx, audit.SiteConfigUpdate, raw.Site, want.Site)
+			recordChange(ctx, audit.CriticalConfigUpdate, raw.Critical, want.Critical)
+		}
+	}
+	setProblems("configuration", problems)
+	return want, nil
+}
+
+// Write implements conf.ConfigurationSource. It always returns ErrReadOnly, because the
+// configuration may only be changed by editing the files.
+func (s *Source) Write(ctx context.Context, input conftypes.RawUnified) error {
+	return ErrReadOnly
+}
+
+// recordChange records a change to the configuration files in the audit log. There is no actor,
+// because the files were edited outside of Sourcegraph.
+func recordChange(ctx context.Context, action, before, after string) {
+	if before == after {
+		return
+	}
+	audit.Record(ctx, audit.Event{
+		Action: action,
+		Details: map[string]interface{}{
+			"changedKeys": audit.ChangedKeys(before, after),
+			"source":      "files",
+		},
+	})
+}
@@ -0,0 +1,116 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+// auditLogExportPageSize is the number of audit log entries that are read from the database at a
+// time when exporting the audit log.
+const auditLogExportPageSize = 1000
+
+// serveAuditLogExport writes the audit log entries that match the query parameters (actor, action,
+// targetType, targetID, since, and until, which are the same as the arguments of the GraphQL
+// Site.auditLog field) as JSON lines, most recent first.
+func serveAuditLogExport(w http.ResponseWriter, r *http.Request) error {
+	// 🚨 SECURITY: Only site admins can export the audit log.
+	if err := backend.CheckCurrentUserIsSiteAdmin(r.Context()); err != nil {
+		return &errcode.HTTPErr{Status: http.StatusForbidden, Err: err}
+	}
+
+	opt, err := auditLogListOptions(r.URL.Query())
+	if err != nil {
+		return &errcode.HTTPErr{Status: http.StatusBadRequest, Err: err}
+	}
+	opt.LimitOffset = &db.LimitOffset{Limit: auditLogExportPageSize}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", `attachment; filename="audit-log.jsonl"`)
+	enc := json.NewEncoder(w)
+	for {
+		entries, err := db.AuditLog.List(r.Context(), opt)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := enc.Encode(newExportedAuditLogEntry(e)); err != nil {
+				return err
+			}
+		}
+		if len(entries) < opt.Limit {
+			return nil
+		}
+		// Page by ID (not by offset) so that entries recorded during the export don't cause
+		// entries to be repeated.
+		opt.BeforeID = entries[len(entries)-1].ID
+	}
+}
+
+func auditLogListOptions(q url.Values) (opt db.AuditLogListOptions, err error) {
+	if actor := q.Get("actor"); actor != "" {
+		if kind := relay.UnmarshalKind(graphql.ID(actor)); kind != "User" {
+			return opt, fmt.Errorf("invalid actor %q: expected a user ID", actor)
+		}
+		if err := relay.UnmarshalSpec(graphql.ID(actor), &opt.ActorUserID); err != nil {
+			return opt, err
+		}
+	}
+	opt.Action = q.Get("action")
+	opt.TargetType = q.Get("targetType")
+	opt.TargetID = q.Get("targetID")
+	for _, t := range []struct {
+		param string
+		opt   **time.Time
+	}{{"since", &opt.Since}, {"until", &opt.Until}} {
+		if v := q.Get(t.param); v != "" {
+			tm, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return opt, err
+			}
+			*t.opt = &tm
+		}
+	}
+	return opt, nil
+}
+
+// exportedAuditLogEntry is the JSON representation of an audit log entry in exports.
+type exportedAuditLogEntry struct {
+	ID           int64           `json:"id"`
+	CreatedAt    time.Time       `json:"createdAt"`
+	Actor        string          `json:"actor,omitempty"` // the actor's user ID (as in the GraphQL API)
+	IP           string          `json:"ip,omitempty"`
+	ForwardedFor string          `json:"forwardedFor,omitempty"`
+	Action       string          `json:"action"`
+	TargetType   string          `json:"targetType,omitempty"`
+	TargetID     string          `json:"targetID,omitempty"`
+	Details      json.RawMessage `json:"details"`
+}
+
+func newExportedAuditLogEntry(e *db.AuditLogEntry) *exportedAuditLogEntry {
+	x := &exportedAuditLogEntry{
+		ID:           e.ID,
+		CreatedAt:    e.CreatedAt,
+		IP:           e.IP,
+		ForwardedFor: e.ForwardedFor,
+		Action:       e.Action,
+		TargetType:   e.TargetType,
+		TargetID:     e.TargetID,
+		Details:      e.Details,
+	}
+	if e.ActorUserID != 0 {
+		x.Actor = string(relay.MarshalID("User", e.ActorUserID))
+	}
+	if len(x.Details) == 0 {
+		x.Details = json.RawMessage("{}")
+	}
+	return x
+}
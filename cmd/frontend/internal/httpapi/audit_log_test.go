@@ -0,0 +1,97 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+func TestAuditLogExport(t *testing.T) {
+	c := newTest()
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	t.Run("non-site-admin", func(t *testing.T) {
+		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{ID: 1}, nil
+		}
+		db.Mocks.AuditLog.List = func(db.AuditLogListOptions) ([]*db.AuditLogEntry, error) {
+			t.Error("want audit log not to be listed")
+			return nil, nil
+		}
+		resp, err := c.Get("/audit-log.jsonl")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("got status %d, want %d", resp.StatusCode, http.StatusForbidden)
+		}
+	})
+
+	t.Run("site admin", func(t *testing.T) {
+		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{ID: 1, SiteAdmin: true}, nil
+		}
+		created := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+		var calls []db.AuditLogListOptions
+		db.Mocks.AuditLog.List = func(opt db.AuditLogListOptions) ([]*db.AuditLogEntry, error) {
+			calls = append(calls, opt)
+			if opt.BeforeID != 0 {
+				return []*db.AuditLogEntry{{ID: 1, CreatedAt: created, Action: "user.sign_in"}}, nil
+			}
+			entries := make([]*db.AuditLogEntry, auditLogExportPageSize)
+			for i := range entries {
+				entries[i] = &db.AuditLogEntry{ID: int64(auditLogExportPageSize + 1 - i), CreatedAt: created, ActorUserID: 2, Action: "user.sign_in"}
+			}
+			return entries, nil
+		}
+
+		resp, err := c.GetOK("/audit-log.jsonl?action=user.sign_in&actor=VXNlcjoy&since=2019-01-01T00:00:00Z")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var n int
+		var last exportedAuditLogEntry
+		dec := json.NewDecoder(resp.Body)
+		for dec.More() {
+			last = exportedAuditLogEntry{} // don't keep fields that are omitted from this entry
+			if err := dec.Decode(&last); err != nil {
+				t.Fatal(err)
+			}
+			n++
+		}
+		if want := auditLogExportPageSize + 1; n != want {
+			t.Errorf("got %d entries, want %d", n, want)
+		}
+		if last.ID != 1 || last.Actor != "" || string(last.Details) != "{}" {
+			t.Errorf("got last entry %+v, want ID 1 with no actor and empty details", last)
+		}
+
+		if len(calls) != 2 {
+			t.Fatalf("got %d List calls, want 2", len(calls))
+		}
+		if opt := calls[0]; opt.Action != "user.sign_in" || opt.ActorUserID != 2 || opt.Since == nil || !opt.Since.Equal(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)) {
+			t.Errorf("got options %+v, want the query parameters", opt)
+		}
+		if want := int64(2); calls[1].BeforeID != want {
+			t.Errorf("got BeforeID %d, want %d", calls[1].BeforeID, want)
+		}
+	})
+
+	t.Run("invalid actor", func(t *testing.T) {
+		resp, err := c.Get("/audit-log.jsonl?actor=x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("got status %d (%q), want %d", resp.StatusCode, body, http.StatusBadRequest)
+		}
+	})
+}
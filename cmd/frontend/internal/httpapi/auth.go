@@ -1,8 +1,10 @@
 package httpapi
 
 import (
+	"fmt"
 	"net/http"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
@@ -61,19 +63,33 @@ func AccessTokenAuthMiddleware(next http.Handler) http.Handler {
 			//
 			// 🚨 SECURITY: It's important we check for the correct scopes to know what this token
 			// is allowed to do.
-			var requiredScope string
+			var requiredScopes []string
 			if sudoUser == "" {
-				requiredScope = authz.ScopeUserAll
+				requiredScopes = authz.UserScopes
 			} else {
-				requiredScope = authz.ScopeSiteAdminSudo
+				requiredScopes = []string{authz.ScopeSiteAdminSudo}
 			}
-			subjectUserID, err := db.AccessTokens.Lookup(r.Context(), token, requiredScope)
+			subjectUserID, scopes, err := db.AccessTokens.Lookup(r.Context(), token, requiredScopes...)
 			if err != nil {
 				log15.Error("Invalid access token.", "token", token, "err", err)
 				http.Error(w, "Invalid access token.", http.StatusUnauthorized)
 				return
 			}
 
+			// 🚨 SECURITY: Tokens without the "user:all" scope may only read data, and "search"
+			// tokens may only be used with the GraphQL API. GraphQL requests are always POSTs, so
+			// the GraphQL handler executes them against a schema restricted to the token's scopes.
+			if r.URL.Path != graphQLPath {
+				if authz.HasScope(scopes, authz.ScopeSearch) {
+					http.Error(w, "Search access tokens may only be used with the GraphQL API.", http.StatusForbidden)
+					return
+				}
+				if !authz.HasScope(scopes, authz.ScopeUserAll) && !isSafeMethod(r.Method) {
+					http.Error(w, "Read-only access tokens may not be used to make changes.", http.StatusForbidden)
+					return
+				}
+			}
+
 			// Determine the actor's user ID.
 			var actorUserID int32
 			if sudoUser == "" {
@@ -103,11 +119,30 @@ func AccessTokenAuthMiddleware(next http.Handler) http.Handler {
 				}
 				actorUserID = user.ID
 				log15.Debug("HTTP request used sudo token.", "requestURI", r.URL.RequestURI(), "tokenSubjectUserID", subjectUserID, "actorUserID", actorUserID, "actorUsername", user.Username)
+				// Record the first use of the token to act as the user in each hour (and not every
+				// request, which would flood the audit log).
+				audit.RecordOnce(actor.WithActor(r.Context(), actor.FromUser(subjectUserID)), fmt.Sprintf("%s:%d", token, user.ID), audit.Event{
+					Action:     audit.AccessTokenSudo,
+					TargetType: "User",
+					TargetID:   audit.UserID(user.ID),
+					Details:    map[string]string{"username": user.Username, "method": r.Method, "path": r.URL.Path},
+				})
 			}
 
-			r = r.WithContext(actor.WithActor(r.Context(), &actor.Actor{UID: actorUserID}))
+			// 🚨 SECURITY: The token's scopes are stored in the actor so that they are enforced
+			// wherever the actor is used (e.g., to restrict the repositories it can access).
+			r = r.WithContext(actor.WithActor(r.Context(), &actor.Actor{UID: actorUserID, Scopes: scopes}))
 		}
 
 		next.ServeHTTP(w, r)
 	})
 }
+
+// graphQLPath is the path of the GraphQL API endpoint, relative to the root of the external HTTP
+// handler.
+const graphQLPath = "/.api/graphql"
+
+// isSafeMethod reports whether method is an HTTP method that doesn't change data.
+func isSafeMethod(method string) bool {
+	return method == "GET" || method == "HEAD" || method == "OPTIONS"
+}
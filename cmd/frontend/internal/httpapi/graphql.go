@@ -9,8 +9,6 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 )
 
-var relayHandler = &relay.Handler{Schema: graphqlbackend.GraphQLSchema}
-
 func serveGraphQL(w http.ResponseWriter, r *http.Request) (err error) {
 	if r.Method != "POST" {
 		// The URL router should not have routed to this handler if method is not POST, but just in
@@ -18,6 +16,9 @@ func serveGraphQL(w http.ResponseWriter, r *http.Request) (err error) {
 		return errors.New("method must be POST")
 	}
 
+	// 🚨 SECURITY: Execute the request against the schema that the scopes of the access token (if
+	// any) that authenticated the request permit.
+	relayHandler := &relay.Handler{Schema: graphqlbackend.SchemaForActor(r.Context())}
 	relayHandler.ServeHTTP(w, r)
 	return nil
 }
@@ -45,6 +45,8 @@ func NewHandler(m *mux.Router) http.Handler {
 
 	m.Get(apirouter.GraphQL).Handler(trace.TraceRoute(handler(serveGraphQL)))
 
+	m.Get(apirouter.AuditLogExport).Handler(trace.TraceRoute(handler(serveAuditLogExport)))
+
 	m.Get(apirouter.Registry).Handler(trace.TraceRoute(handler(registry.HandleRegistry)))
 
 	m.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -72,17 +74,25 @@ func NewInternalHandler(m *mux.Router) http.Handler {
 	m.Get(apirouter.PhabricatorRepoCreate).Handler(trace.TraceRoute(handler(servePhabricatorRepoCreate)))
 	m.Get(apirouter.ReposCreateIfNotExists).Handler(trace.TraceRoute(handler(serveReposCreateIfNotExists)))
 	m.Get(apirouter.ReposUpdateMetadata).Handler(trace.TraceRoute(handler(serveReposUpdateMetadata)))
+	m.Get(apirouter.ReposRenamed).Handler(trace.TraceRoute(handler(serveReposRenamed)))
 	m.Get(apirouter.ReposList).Handler(trace.TraceRoute(handler(serveReposList)))
 	m.Get(apirouter.ReposListEnabled).Handler(trace.TraceRoute(handler(serveReposListEnabled)))
 	m.Get(apirouter.ReposGetByName).Handler(trace.TraceRoute(handler(serveReposGetByName)))
+	m.Get(apirouter.WebhooksEnqueue).Handler(trace.TraceRoute(handler(serveWebhooksEnqueue)))
 	m.Get(apirouter.SettingsGetForSubject).Handler(trace.TraceRoute(handler(serveSettingsGetForSubject)))
 	m.Get(apirouter.SavedQueriesListAll).Handler(trace.TraceRoute(handler(serveSavedQueriesListAll)))
 	m.Get(apirouter.SavedQueriesGetInfo).Handler(trace.TraceRoute(handler(serveSavedQueriesGetInfo)))
 	m.Get(apirouter.SavedQueriesSetInfo).Handler(trace.TraceRoute(handler(serveSavedQueriesSetInfo)))
 	m.Get(apirouter.SavedQueriesDeleteInfo).Handler(trace.TraceRoute(handler(serveSavedQueriesDeleteInfo)))
+	m.Get(apirouter.SavedQueriesReplyTo).Handler(trace.TraceRoute(handler(serveSavedQueriesReplyTo)))
+	m.Get(apirouter.SavedQueriesMutedUsers).Handler(trace.TraceRoute(handler(serveSavedQueriesMutedUsers)))
+	m.Get(apirouter.SavedSearchDigestsList).Handler(trace.TraceRoute(handler(serveSavedSearchDigestsList)))
+	m.Get(apirouter.SavedSearchDigestsSave).Handler(trace.TraceRoute(handler(serveSavedSearchDigestsSave)))
+	m.Get(apirouter.SavedSearchDigestsDelete).Handler(trace.TraceRoute(handler(serveSavedSearchDigestsDelete)))
 	m.Get(apirouter.OrgsListUsers).Handler(trace.TraceRoute(handler(serveOrgsListUsers)))
 	m.Get(apirouter.OrgsGetByName).Handler(trace.TraceRoute(handler(serveOrgsGetByName)))
 	m.Get(apirouter.UsersGetByUsername).Handler(trace.TraceRoute(handler(serveUsersGetByUsername)))
+	m.Get(apirouter.UsersGetEmailFrequency).Handler(trace.TraceRoute(handler(serveUsersGetEmailFrequency)))
 	m.Get(apirouter.UserEmailsGetEmail).Handler(trace.TraceRoute(handler(serveUserEmailsGetEmail)))
 	m.Get(apirouter.ExternalURL).Handler(trace.TraceRoute(handler(serveExternalURL)))
 	m.Get(apirouter.GitServerAddrs).Handler(trace.TraceRoute(handler(serveGitServerAddrs)))
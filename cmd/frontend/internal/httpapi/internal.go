@@ -12,6 +12,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/discussions"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
@@ -120,7 +121,9 @@ func serveExternalServiceConfigs(w http.ResponseWriter, r *http.Request) error {
 	for _, service := range services {
 		var config map[string]interface{}
 		// Raw configs may have comments in them so we have to use a json parser
-		// that supports comments in json.
+		// that supports comments in json. Secret references are resolved by
+		// the caller (see conf.GitHubConfigs), because they may only be
+		// resolvable in the caller's service.
 		if err := jsonc.Unmarshal(service.Config, &config); err != nil {
 			log15.Error(
 				"ignoring external service config that has invalid json",
@@ -310,6 +313,99 @@ func serveSavedQueriesDeleteInfo(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+func serveSavedQueriesReplyTo(w http.ResponseWriter, r *http.Request) error {
+	var req api.SavedQueryReplyToRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+	var replyTo *string
+	if conf.CanReadEmail() {
+		token, err := db.SavedSearchMailReplyTokens.Generate(r.Context(), db.SavedSearchMailReplyToken{
+			UserID:        req.UserID,
+			Subject:       req.Spec.Subject.String(),
+			SavedQueryKey: req.Spec.Key,
+		})
+		if err != nil {
+			return errors.Wrap(err, "SavedSearchMailReplyTokens.Generate")
+		}
+		address := discussions.MailSubAddress(token)
+		replyTo = &address
+	}
+	if err := json.NewEncoder(w).Encode(replyTo); err != nil {
+		return errors.Wrap(err, "Encode")
+	}
+	return nil
+}
+
+func serveSavedQueriesMutedUsers(w http.ResponseWriter, r *http.Request) error {
+	var spec api.SavedQueryIDSpec
+	err := json.NewDecoder(r.Body).Decode(&spec)
+	if err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+	userIDs, err := db.SavedSearchEmailMutes.ListMutedUsers(r.Context(), spec.Subject.String(), spec.Key)
+	if err != nil {
+		return errors.Wrap(err, "SavedSearchEmailMutes.ListMutedUsers")
+	}
+	if err := json.NewEncoder(w).Encode(userIDs); err != nil {
+		return errors.Wrap(err, "Encode")
+	}
+	return nil
+}
+
+func serveSavedSearchDigestsList(w http.ResponseWriter, r *http.Request) error {
+	digests, err := db.SavedSearchDigests.List(r.Context())
+	if err != nil {
+		return errors.Wrap(err, "SavedSearchDigests.List")
+	}
+	res := make([]*api.SavedSearchDigest, len(digests))
+	for i, d := range digests {
+		res[i] = &api.SavedSearchDigest{
+			UserID:   d.UserID,
+			DueAt:    d.DueAt,
+			Contents: json.RawMessage(d.Contents),
+		}
+	}
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		return errors.Wrap(err, "Encode")
+	}
+	return nil
+}
+
+func serveSavedSearchDigestsSave(w http.ResponseWriter, r *http.Request) error {
+	var digest api.SavedSearchDigest
+	err := json.NewDecoder(r.Body).Decode(&digest)
+	if err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+	err = db.SavedSearchDigests.Save(r.Context(), &db.SavedSearchDigest{
+		UserID:   digest.UserID,
+		DueAt:    digest.DueAt,
+		Contents: string(digest.Contents),
+	})
+	if err != nil {
+		return errors.Wrap(err, "SavedSearchDigests.Save")
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+	return nil
+}
+
+func serveSavedSearchDigestsDelete(w http.ResponseWriter, r *http.Request) error {
+	var req api.SavedSearchDigestsDeleteRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+	if err := db.SavedSearchDigests.Delete(r.Context(), req.UserID, req.DueAt); err != nil {
+		return errors.Wrap(err, "SavedSearchDigests.Delete")
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("OK"))
+	return nil
+}
+
 func serveSettingsGetForSubject(w http.ResponseWriter, r *http.Request) error {
 	var subject api.SettingsSubject
 	if err := json.NewDecoder(r.Body).Decode(&subject); err != nil {
@@ -377,6 +473,22 @@ func serveUsersGetByUsername(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+func serveUsersGetEmailFrequency(w http.ResponseWriter, r *http.Request) error {
+	var userID int32
+	err := json.NewDecoder(r.Body).Decode(&userID)
+	if err != nil {
+		return errors.Wrap(err, "Decode")
+	}
+	frequency, err := db.Users.GetSavedSearchEmailFrequency(r.Context(), userID)
+	if err != nil {
+		return errors.Wrap(err, "Users.GetSavedSearchEmailFrequency")
+	}
+	if err := json.NewEncoder(w).Encode(frequency); err != nil {
+		return errors.Wrap(err, "Encode")
+	}
+	return nil
+}
+
 func serveUserEmailsGetEmail(w http.ResponseWriter, r *http.Request) error {
 	var userID int32
 	err := json.NewDecoder(r.Body).Decode(&userID)
@@ -0,0 +1,131 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/jsonx"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/search/query/syntax"
+	"github.com/sourcegraph/sourcegraph/cmd/query-runner/queryrunnerapi"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// serveReposRenamed rewrites the repo: filters of saved searches which refer to the previous names
+// of renamed repos. Filters which only match a previous name as a regexp are logged instead, since
+// they can't be rewritten without possibly changing their meaning.
+func serveReposRenamed(w http.ResponseWriter, r *http.Request) error {
+	var req api.ReposRenamedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return err
+	}
+	if len(req.Renamed) == 0 {
+		return nil
+	}
+
+	settings, err := db.Settings.ListAll(r.Context(), "search.savedQueries")
+	if err != nil {
+		return errors.Wrap(err, "db.Settings.ListAll")
+	}
+
+	for _, s := range settings {
+		if err := rewriteSavedQueriesRepoFilters(r.Context(), s, req.Renamed); err != nil {
+			log15.Warn("failed to rewrite saved searches of renamed repos", "subject", s.Subject, "error", err)
+		}
+	}
+	return nil
+}
+
+func rewriteSavedQueriesRepoFilters(ctx context.Context, settings *api.Settings, renamed map[api.RepoName]api.RepoName) error {
+	var config api.PartialConfigSavedQueries
+	if err := jsonc.Unmarshal(settings.Contents, &config); err != nil {
+		return err
+	}
+
+	contents := settings.Contents
+	for i, q := range config.SavedQueries {
+		rewritten, unsafe := rewriteRepoFilters(q.Query, renamed)
+		for _, filter := range unsafe {
+			log15.Warn("saved search filter matches the previous name of a renamed repo", "subject", settings.Subject, "key", q.Key, "filter", filter)
+		}
+		if rewritten == q.Query {
+			continue
+		}
+
+		edits, _, err := jsonx.ComputePropertyEdit(contents, jsonx.MakePath("search.savedQueries", i, "query"), rewritten, nil, conf.FormatOptions)
+		if err != nil {
+			return err
+		}
+		if contents, err = jsonx.ApplyEdits(contents, edits...); err != nil {
+			return err
+		}
+		config.SavedQueries[i].Query = rewritten
+	}
+
+	if contents == settings.Contents {
+		return nil
+	}
+
+	// The edit is made by Sourcegraph itself, so it has no author. It fails if the settings were
+	// changed concurrently, in which case the saved searches are left as they are.
+	if _, err := db.Settings.CreateIfUpToDate(ctx, settings.Subject, &settings.ID, nil, contents); err != nil {
+		return err
+	}
+
+	go queryrunnerapi.Client.SavedQueryWasCreatedOrUpdated(context.Background(), settings.Subject, config, true)
+	return nil
+}
+
+// repoFilterFields are the names and aliases of the repo: filter.
+var repoFilterFields = map[string]bool{"repo": true, "r": true}
+
+// rewriteRepoFilters rewrites the repo: filters of query which match exactly one of the previous
+// names in renamed (optionally anchored or regexp-quoted) to match its new name instead. It also
+// returns the filters which only match a previous name as a regexp, and so aren't rewritten.
+func rewriteRepoFilters(query string, renamed map[api.RepoName]api.RepoName) (rewritten string, unsafe []string) {
+	q, err := syntax.Parse(query)
+	if err != nil {
+		return query, nil
+	}
+
+	changed := false
+	for _, e := range q.Expr {
+		if !repoFilterFields[e.Field] || e.ValueType != syntax.TokenLiteral {
+			continue
+		}
+
+		value, prefix, suffix := e.Value, "", ""
+		if strings.HasPrefix(value, "^") {
+			value, prefix = value[1:], "^"
+		}
+		if strings.HasSuffix(value, "$") {
+			value, suffix = value[:len(value)-1], "$"
+		}
+
+		for from, to := range renamed {
+			if value == string(from) {
+				e.Value, changed = prefix+string(to)+suffix, true
+				break
+			}
+			if value == regexp.QuoteMeta(string(from)) {
+				e.Value, changed = prefix+regexp.QuoteMeta(string(to))+suffix, true
+				break
+			}
+			if re, err := regexp.Compile("(?i)" + e.Value); err == nil && re.MatchString(string(from)) && !re.MatchString(string(to)) {
+				unsafe = append(unsafe, e.String())
+			}
+		}
+	}
+
+	if !changed {
+		return query, unsafe
+	}
+	return syntax.ExprString(q.Expr), unsafe
+}
@@ -0,0 +1,41 @@
+package httpapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+func TestRewriteRepoFilters(t *testing.T) {
+	renamed := map[api.RepoName]api.RepoName{"github.com/foo/old": "github.com/bar/new"}
+
+	tests := []struct {
+		query      string
+		wantQuery  string
+		wantUnsafe []string
+	}{
+		{query: "foo repo:github.com/foo/old", wantQuery: "foo repo:github.com/bar/new"},
+		{query: "r:^github.com/foo/old$ foo", wantQuery: "r:^github.com/bar/new$ foo"},
+		{query: `repo:^github\.com/foo/old$`, wantQuery: `repo:^github\.com/bar/new$`},
+		{query: "-repo:github.com/foo/old", wantQuery: "-repo:github.com/bar/new"},
+		{query: "repo:github.com/foo/other foo", wantQuery: "repo:github.com/foo/other foo"},
+		{query: "github.com/foo/old", wantQuery: "github.com/foo/old"},
+		{
+			query:      "repo:foo/ol",
+			wantQuery:  "repo:foo/ol",
+			wantUnsafe: []string{"repo:foo/ol"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			query, unsafe := rewriteRepoFilters(test.query, renamed)
+			if query != test.wantQuery {
+				t.Errorf("got query %q, want %q", query, test.wantQuery)
+			}
+			if !reflect.DeepEqual(unsafe, test.wantUnsafe) {
+				t.Errorf("got unsafe filters %q, want %q", unsafe, test.wantUnsafe)
+			}
+		})
+	}
+}
@@ -15,34 +15,44 @@ const (
 	RepoRefresh = "repo.refresh"
 	Telemetry   = "telemetry"
 
-	SavedQueriesListAll    = "internal.saved-queries.list-all"
-	SavedQueriesGetInfo    = "internal.saved-queries.get-info"
-	SavedQueriesSetInfo    = "internal.saved-queries.set-info"
-	SavedQueriesDeleteInfo = "internal.saved-queries.delete-info"
-	SettingsGetForSubject  = "internal.settings.get-for-subject"
-	OrgsListUsers          = "internal.orgs.list-users"
-	OrgsGetByName          = "internal.orgs.get-by-name"
-	UsersGetByUsername     = "internal.users.get-by-username"
-	UserEmailsGetEmail     = "internal.user-emails.get-email"
-	ExternalURL            = "internal.app-url"
-	GitServerAddrs         = "internal.git-server-addrs"
-	CanSendEmail           = "internal.can-send-email"
-	SendEmail              = "internal.send-email"
-	Extension              = "internal.extension"
-	GitResolveRevision     = "internal.git.resolve-revision"
-	GitTar                 = "internal.git.tar"
-	PhabricatorRepoCreate  = "internal.phabricator.repo.create"
-	ReposCreateIfNotExists = "internal.repos.create-if-not-exists"
-	ReposGetByName         = "internal.repos.get-by-name"
-	ReposInventoryUncached = "internal.repos.inventory-uncached"
-	ReposInventory         = "internal.repos.inventory"
-	ReposList              = "internal.repos.list"
-	ReposListEnabled       = "internal.repos.list-enabled"
-	ReposUpdateMetadata    = "internal.repos.update-metadata"
-	Configuration          = "internal.configuration"
-	SearchConfiguration    = "internal.search-configuration"
-	ExternalServiceConfigs = "internal.external-services.configs"
-	ExternalServicesList   = "internal.external-services.list"
+	AuditLogExport = "audit-log.export"
+
+	SavedQueriesListAll      = "internal.saved-queries.list-all"
+	SavedQueriesGetInfo      = "internal.saved-queries.get-info"
+	SavedQueriesSetInfo      = "internal.saved-queries.set-info"
+	SavedQueriesDeleteInfo   = "internal.saved-queries.delete-info"
+	SavedQueriesReplyTo      = "internal.saved-queries.reply-to"
+	SavedQueriesMutedUsers   = "internal.saved-queries.muted-users"
+	SavedSearchDigestsList   = "internal.saved-search-digests.list"
+	SavedSearchDigestsSave   = "internal.saved-search-digests.save"
+	SavedSearchDigestsDelete = "internal.saved-search-digests.delete"
+	SettingsGetForSubject    = "internal.settings.get-for-subject"
+	OrgsListUsers            = "internal.orgs.list-users"
+	OrgsGetByName            = "internal.orgs.get-by-name"
+	UsersGetByUsername       = "internal.users.get-by-username"
+	UsersGetEmailFrequency   = "internal.users.get-email-frequency"
+	UserEmailsGetEmail       = "internal.user-emails.get-email"
+	ExternalURL              = "internal.app-url"
+	GitServerAddrs           = "internal.git-server-addrs"
+	CanSendEmail             = "internal.can-send-email"
+	SendEmail                = "internal.send-email"
+	Extension                = "internal.extension"
+	GitResolveRevision       = "internal.git.resolve-revision"
+	GitTar                   = "internal.git.tar"
+	PhabricatorRepoCreate    = "internal.phabricator.repo.create"
+	ReposCreateIfNotExists   = "internal.repos.create-if-not-exists"
+	ReposGetByName           = "internal.repos.get-by-name"
+	ReposInventoryUncached   = "internal.repos.inventory-uncached"
+	ReposInventory           = "internal.repos.inventory"
+	ReposList                = "internal.repos.list"
+	ReposListEnabled         = "internal.repos.list-enabled"
+	ReposRenamed             = "internal.repos.renamed"
+	ReposUpdateMetadata      = "internal.repos.update-metadata"
+	Configuration            = "internal.configuration"
+	SearchConfiguration      = "internal.search-configuration"
+	ExternalServiceConfigs   = "internal.external-services.configs"
+	ExternalServicesList     = "internal.external-services.list"
+	WebhooksEnqueue          = "internal.webhooks.enqueue"
 )
 
 // New creates a new API router with route URL pattern definitions but
@@ -58,6 +68,8 @@ func New(base *mux.Router) *mux.Router {
 	addGraphQLRoute(base)
 	addTelemetryRoute(base)
 
+	base.Path("/audit-log.jsonl").Methods("GET").Name(AuditLogExport)
+
 	// repo contains routes that are NOT specific to a revision. In these routes, the URL may not contain a revspec after the repo (that is, no "github.com/foo/bar@myrevspec").
 	repoPath := `/repos/` + routevar.Repo
 
@@ -82,10 +94,16 @@ func NewInternal(base *mux.Router) *mux.Router {
 	base.Path("/saved-queries/get-info").Methods("POST").Name(SavedQueriesGetInfo)
 	base.Path("/saved-queries/set-info").Methods("POST").Name(SavedQueriesSetInfo)
 	base.Path("/saved-queries/delete-info").Methods("POST").Name(SavedQueriesDeleteInfo)
+	base.Path("/saved-queries/reply-to").Methods("POST").Name(SavedQueriesReplyTo)
+	base.Path("/saved-queries/muted-users").Methods("POST").Name(SavedQueriesMutedUsers)
+	base.Path("/saved-search-digests/list").Methods("POST").Name(SavedSearchDigestsList)
+	base.Path("/saved-search-digests/save").Methods("POST").Name(SavedSearchDigestsSave)
+	base.Path("/saved-search-digests/delete").Methods("POST").Name(SavedSearchDigestsDelete)
 	base.Path("/settings/get-for-subject").Methods("POST").Name(SettingsGetForSubject)
 	base.Path("/orgs/list-users").Methods("POST").Name(OrgsListUsers)
 	base.Path("/orgs/get-by-name").Methods("POST").Name(OrgsGetByName)
 	base.Path("/users/get-by-username").Methods("POST").Name(UsersGetByUsername)
+	base.Path("/users/get-email-frequency").Methods("POST").Name(UsersGetEmailFrequency)
 	base.Path("/user-emails/get-email").Methods("POST").Name(UserEmailsGetEmail)
 	base.Path("/app-url").Methods("POST").Name(ExternalURL)
 	base.Path("/git-server-addrs").Methods("POST").Name(GitServerAddrs)
@@ -103,9 +121,11 @@ func NewInternal(base *mux.Router) *mux.Router {
 	base.Path("/repos/list").Methods("POST").Name(ReposList)
 	base.Path("/repos/list-enabled").Methods("POST").Name(ReposListEnabled)
 	base.Path("/repos/update-metadata").Methods("POST").Name(ReposUpdateMetadata)
+	base.Path("/repos/renamed").Methods("POST").Name(ReposRenamed)
 	base.Path("/repos/{RepoName:.*}").Methods("POST").Name(ReposGetByName)
 	base.Path("/configuration").Methods("POST").Name(Configuration)
 	base.Path("/search/configuration").Methods("GET").Name(SearchConfiguration)
+	base.Path("/webhooks/enqueue").Methods("POST").Name(WebhooksEnqueue)
 	addRegistryRoute(base)
 	addGraphQLRoute(base)
 	addTelemetryRoute(base)
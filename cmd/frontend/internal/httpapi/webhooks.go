@@ -0,0 +1,23 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/webhooks"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+// serveWebhooksEnqueue enqueues the delivery of an event (sent by another service, such as
+// gitserver) to the outbound webhooks that subscribe to its type.
+func serveWebhooksEnqueue(w http.ResponseWriter, r *http.Request) error {
+	var req api.WebhookEnqueueRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return err
+	}
+	if err := webhooks.Enqueue(r.Context(), req); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusOK)
+	return nil
+}
@@ -0,0 +1,229 @@
+package discussions
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/sourcegraph/go-diff/diff"
+)
+
+// Anchor is the selection that a discussion thread was created on, as it was in
+// the file at the revision the thread was created on.
+type Anchor struct {
+	LineRange
+
+	// LinesBefore, Lines, and LinesAfter are the literal lines of (and
+	// surrounding) the selection, as captured when the thread was created.
+	LinesBefore, Lines, LinesAfter []string
+}
+
+// Reanchor computes where the anchor's selection is in a newer version of the
+// file.
+//
+// The hunks are the diff hunks of the file between the revision the thread was
+// created on and the newer revision. If none of the selected lines were changed
+// by the hunks, the selection is simply shifted by the lines added and removed
+// above it. Otherwise (or if hunks is nil, because the revision the thread was
+// created on is unknown), the selected lines are searched for in newContent,
+// using the surrounding lines to pick the best match.
+//
+// It returns nil if the selection can't be found in the newer version of the
+// file, in which case the thread is outdated.
+func Reanchor(a Anchor, hunks []*diff.Hunk, newContent string) *LineRange {
+	if hunks != nil {
+		if r, ok := mapLineRange(a.LineRange, hunks); ok {
+			return &r
+		}
+	}
+
+	// Prefer matches near where the selection would be if the lines around it
+	// were not changed.
+	hint := a.StartLine
+	if hunks != nil {
+		hint = mapLineNear(a.StartLine, hunks)
+	}
+	return findLines(a, strings.Split(newContent, "\n"), hint)
+}
+
+// hunkRange returns the zero-based line range of the hunk in the original and
+// new file. Unified diffs refer to the line before an empty range (e.g., the
+// line after which lines were inserted), so empty ranges start one line later.
+func hunkRange(h *diff.Hunk) (orig, updated LineRange) {
+	orig.StartLine = int(h.OrigStartLine) - 1
+	if h.OrigLines == 0 {
+		orig.StartLine++
+	}
+	orig.EndLine = orig.StartLine + int(h.OrigLines)
+	updated.StartLine = int(h.NewStartLine) - 1
+	if h.NewLines == 0 {
+		updated.StartLine++
+	}
+	updated.EndLine = updated.StartLine + int(h.NewLines)
+	return orig, updated
+}
+
+// mapLine returns the zero-based line number in the new file of the given
+// zero-based line in the original file. It returns -1 if the line was removed
+// or changed.
+func mapLine(line int, hunks []*diff.Hunk) int {
+	offset := 0
+	for _, h := range hunks {
+		orig, updated := hunkRange(h)
+		if line < orig.StartLine {
+			break
+		}
+		if line >= orig.EndLine {
+			offset = updated.EndLine - orig.EndLine
+			continue
+		}
+
+		// The line is in the hunk, so find out whether it is a context line.
+		o, n := orig.StartLine, updated.StartLine
+		for _, l := range bytes.Split(h.Body, []byte("\n")) {
+			if len(l) == 0 {
+				continue
+			}
+			switch l[0] {
+			case ' ':
+				if o == line {
+					return n
+				}
+				o++
+				n++
+			case '-':
+				if o == line {
+					return -1
+				}
+				o++
+			case '+':
+				n++
+			}
+		}
+		return -1
+	}
+	return line + offset
+}
+
+// mapLineNear is like mapLine, except that for removed or changed lines it
+// returns the start of the corresponding hunk in the new file.
+func mapLineNear(line int, hunks []*diff.Hunk) int {
+	if n := mapLine(line, hunks); n != -1 {
+		return n
+	}
+	for _, h := range hunks {
+		if orig, updated := hunkRange(h); line >= orig.StartLine && line < orig.EndLine {
+			return updated.StartLine
+		}
+	}
+	return line
+}
+
+// mapLineRange maps the line range through the hunks. It reports false if any
+// of the lines in the range were removed or changed, or if lines were added in
+// the range.
+func mapLineRange(r LineRange, hunks []*diff.Hunk) (LineRange, bool) {
+	if r.EndLine <= r.StartLine {
+		// An empty range (such as a position between two characters) moves
+		// with the line it is on.
+		start := mapLine(r.StartLine, hunks)
+		return LineRange{StartLine: start, EndLine: start}, start != -1
+	}
+	start := mapLine(r.StartLine, hunks)
+	end := mapLine(r.EndLine-1, hunks)
+	if start == -1 || end == -1 || end-start != r.EndLine-1-r.StartLine {
+		return LineRange{}, false
+	}
+	return LineRange{StartLine: start, EndLine: end + 1}, true
+}
+
+// minMatchLines is the minimum number of lines (selected lines and matching
+// surrounding lines) that a match found by findLines must consist of, because
+// short selections (such as a line with only "}") would otherwise match almost
+// anywhere.
+const minMatchLines = 4
+
+// findLines searches for the anchor's selected lines in the file's lines. If
+// there are multiple matches, it picks the match with the most matching
+// surrounding lines (and then the match nearest to the hint line). Lines are
+// first compared exactly, and then ignoring leading and trailing whitespace
+// (for changes that only reindent the code).
+//
+// Matches must consist of at least minMatchLines lines (or of all the lines
+// captured with the anchor, if there are fewer). Matches without any matching
+// surrounding lines are only used if they are unique.
+func findLines(a Anchor, fileLines []string, hint int) *LineRange {
+	minLines := len(a.LinesBefore) + len(a.Lines) + len(a.LinesAfter)
+	if minLines > minMatchLines {
+		minLines = minMatchLines
+	}
+
+	for _, normalize := range []func(string) string{
+		func(s string) string { return s },
+		strings.TrimSpace,
+	} {
+		equal := func(a, b string) bool { return normalize(a) == normalize(b) }
+
+		var (
+			best                  *LineRange
+			bestScore, bestOffset int
+			matches               int
+		)
+		for start := 0; start+len(a.Lines) <= len(fileLines); start++ {
+			if !linesEqualAt(fileLines, start, a.Lines, equal) {
+				continue
+			}
+			end := start + len(a.Lines)
+			score := contextScore(fileLines, start, end, a.LinesBefore, a.LinesAfter, equal)
+			if len(a.Lines) == 0 && score == 0 {
+				continue // an empty selection can only be found by its context
+			}
+			if len(a.Lines)+score < minLines {
+				continue // too few lines match to be confident
+			}
+			matches++
+			offset := start - hint
+			if offset < 0 {
+				offset = -offset
+			}
+			if best == nil || score > bestScore || (score == bestScore && offset < bestOffset) {
+				best = &LineRange{StartLine: start, EndLine: end}
+				bestScore, bestOffset = score, offset
+			}
+		}
+		if best != nil {
+			if bestScore == 0 && matches > 1 {
+				return nil // ambiguous
+			}
+			return best
+		}
+	}
+	return nil
+}
+
+func linesEqualAt(fileLines []string, start int, lines []string, equal func(a, b string) bool) bool {
+	for i, line := range lines {
+		if !equal(fileLines[start+i], line) {
+			return false
+		}
+	}
+	return true
+}
+
+// contextScore returns the number of lines before and after the range that
+// match the given surrounding lines (counting outward from the range).
+func contextScore(fileLines []string, start, end int, linesBefore, linesAfter []string, equal func(a, b string) bool) int {
+	score := 0
+	for i := 1; i <= len(linesBefore) && start-i >= 0; i++ {
+		if !equal(fileLines[start-i], linesBefore[len(linesBefore)-i]) {
+			break
+		}
+		score++
+	}
+	for i := 0; i < len(linesAfter) && end+i < len(fileLines); i++ {
+		if !equal(fileLines[end+i], linesAfter[i]) {
+			break
+		}
+		score++
+	}
+	return score
+}
@@ -0,0 +1,91 @@
+package discussions
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/go-diff/diff"
+)
+
+func TestReanchor(t *testing.T) {
+	// The original file has lines "0" through "9".
+	anchor := Anchor{
+		LineRange:   LineRange{StartLine: 4, EndLine: 6},
+		LinesBefore: []string{"1", "2", "3"},
+		Lines:       []string{"4", "5"},
+		LinesAfter:  []string{"6", "7", "8"},
+	}
+	lines := func(lines ...string) string { return strings.Join(lines, "\n") + "\n" }
+
+	tests := map[string]struct {
+		hunks      []*diff.Hunk
+		newContent string
+		want       *LineRange
+	}{
+		"unchanged file": {
+			hunks:      []*diff.Hunk{},
+			newContent: lines("0", "1", "2", "3", "4", "5", "6", "7", "8", "9"),
+			want:       &LineRange{StartLine: 4, EndLine: 6},
+		},
+		"lines added above": {
+			hunks: []*diff.Hunk{
+				{OrigStartLine: 1, OrigLines: 0, NewStartLine: 2, NewLines: 2, Body: []byte("+a\n+b\n")},
+			},
+			newContent: lines("0", "a", "b", "1", "2", "3", "4", "5", "6", "7", "8", "9"),
+			want:       &LineRange{StartLine: 6, EndLine: 8},
+		},
+		"lines removed above and added below": {
+			hunks: []*diff.Hunk{
+				{OrigStartLine: 1, OrigLines: 4, NewStartLine: 1, NewLines: 3, Body: []byte(" 0\n-1\n 2\n 3\n")},
+				{OrigStartLine: 8, OrigLines: 2, NewStartLine: 7, NewLines: 3, Body: []byte(" 7\n+x\n 8\n")},
+			},
+			newContent: lines("0", "2", "3", "4", "5", "6", "7", "x", "8", "9"),
+			want:       &LineRange{StartLine: 3, EndLine: 5},
+		},
+		"lines moved above": {
+			hunks: []*diff.Hunk{
+				{OrigStartLine: 1, OrigLines: 10, NewStartLine: 1, NewLines: 10, Body: []byte(" 0\n+6\n+7\n 1\n 2\n 3\n 4\n 5\n-6\n-7\n 8\n 9\n")},
+			},
+			newContent: lines("0", "6", "7", "1", "2", "3", "4", "5", "8", "9"),
+			want:       &LineRange{StartLine: 6, EndLine: 8},
+		},
+		"selected lines reindented": {
+			hunks: []*diff.Hunk{
+				{OrigStartLine: 4, OrigLines: 4, NewStartLine: 4, NewLines: 4, Body: []byte(" 3\n-4\n-5\n+  4\n+  5\n 6\n")},
+			},
+			newContent: lines("0", "1", "2", "3", "  4", "  5", "6", "7", "8", "9"),
+			want:       &LineRange{StartLine: 4, EndLine: 6},
+		},
+		"selected lines changed": {
+			hunks: []*diff.Hunk{
+				{OrigStartLine: 4, OrigLines: 4, NewStartLine: 4, NewLines: 3, Body: []byte(" 3\n-4\n-5\n+x\n 6\n")},
+			},
+			newContent: lines("0", "1", "2", "3", "x", "6", "7", "8", "9"),
+			want:       nil,
+		},
+		"unknown revision": {
+			hunks:      nil,
+			newContent: lines("a", "b", "1", "2", "3", "4", "5", "6", "7", "8", "9", "4", "5"),
+			want:       &LineRange{StartLine: 5, EndLine: 7},
+		},
+		"unknown revision, too few matching lines": {
+			hunks:      nil,
+			newContent: lines("a", "3", "4", "5", "b"),
+			want:       nil,
+		},
+		"ambiguous": {
+			hunks:      nil,
+			newContent: lines("4", "5", "x", "4", "5"),
+			want:       nil,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := Reanchor(anchor, test.hunks, test.newContent)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package mailreply
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+)
+
+// defaultMuteDuration is how long a bare "mute" command mutes notifications
+// for.
+const defaultMuteDuration = 7 * 24 * time.Hour
+
+// savedSearchCommand is a command sent in reply to a saved search email
+// notification.
+type savedSearchCommand struct {
+	// mute is whether notifications should be muted (true) or unmuted
+	// (false).
+	mute bool
+
+	// duration is how long notifications should be muted for. Zero means
+	// indefinitely (i.e., unsubscribe).
+	duration time.Duration
+}
+
+var muteDurationMatch = regexp.MustCompile(`^(\d+)\s*(m|min|mins|minutes?|h|hours?|d|days?|w|weeks?)$`)
+
+// parseSavedSearchCommand parses the command from the contents of a reply to
+// a saved search email notification. Only the first line is considered, so
+// that e.g. email signatures are ignored. Supported commands are:
+//
+// 	mute             mute notifications for 1 week
+// 	mute <duration>  mute notifications for e.g. "30m", "12h", "3d" or "2w"
+// 	unmute           unmute notifications
+// 	unsubscribe      stop receiving notifications
+// 	subscribe        resume receiving notifications
+func parseSavedSearchCommand(contents string) (*savedSearchCommand, error) {
+	line := strings.ToLower(strings.TrimSpace(strings.SplitN(strings.TrimSpace(contents), "\n", 2)[0]))
+	fields := strings.SplitN(line, " ", 2)
+	switch fields[0] {
+	case "unsubscribe":
+		return &savedSearchCommand{mute: true}, nil
+	case "unmute", "subscribe", "resubscribe":
+		return &savedSearchCommand{mute: false}, nil
+	case "mute":
+		if len(fields) == 1 {
+			return &savedSearchCommand{mute: true, duration: defaultMuteDuration}, nil
+		}
+		d, err := parseMuteDuration(strings.TrimSpace(fields[1]))
+		if err != nil {
+			return nil, err
+		}
+		return &savedSearchCommand{mute: true, duration: d}, nil
+	}
+	return nil, fmt.Errorf("unrecognized command %q", line)
+}
+
+// parseMuteDuration parses a duration such as "30m", "12h", "3d", "2w" or
+// "2 weeks".
+func parseMuteDuration(s string) (time.Duration, error) {
+	m := muteDurationMatch.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("invalid mute duration %q", s)
+	}
+	n, err := strconv.Atoi(m[1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid mute duration %q", s)
+	}
+	var unit time.Duration
+	switch m[2][0] {
+	case 'm':
+		unit = time.Minute
+	case 'h':
+		unit = time.Hour
+	case 'd':
+		unit = 24 * time.Hour
+	case 'w':
+		unit = 7 * 24 * time.Hour
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// confirmation returns the text of the email confirming that the command was
+// applied.
+func (c *savedSearchCommand) confirmation(now time.Time) string {
+	switch {
+	case !c.mute:
+		return "You will receive email notifications for this saved search again."
+	case c.duration == 0:
+		return "You have unsubscribed from email notifications for this saved search. Reply with \"subscribe\" to receive them again."
+	default:
+		return fmt.Sprintf("Email notifications for this saved search are muted until %s. Reply with \"unmute\" to receive them again.", now.Add(c.duration).UTC().Format("Mon, 02 Jan 2006 15:04 MST"))
+	}
+}
+
+const savedSearchCommandsHelp = `Reply to a saved search email notification with one of these commands:
+
+mute             mute notifications for 1 week
+mute <duration>  mute notifications for e.g. "12h", "3d" or "2w"
+unmute           unmute notifications
+unsubscribe      stop receiving notifications
+subscribe        resume receiving notifications`
+
+// handleSavedSearchCommand handles a reply to a saved search email
+// notification by applying the command in the reply to the user's
+// subscription to the saved search.
+func handleSavedSearchCommand(ctx context.Context, msg *Message, token string) error {
+	t, err := db.SavedSearchMailReplyTokens.Get(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	contents, err := messageContents(msg)
+	if err != nil || contents == "" {
+		return err // ignore empty replies
+	}
+
+	cmd, err := parseSavedSearchCommand(contents)
+	if err != nil {
+		return sendReply(ctx, t.UserID, msg, fmt.Sprintf("Sorry, %s.\n\n%s", err, savedSearchCommandsHelp))
+	}
+
+	now := time.Now()
+	if cmd.mute {
+		var until *time.Time
+		if cmd.duration != 0 {
+			v := now.Add(cmd.duration)
+			until = &v
+		}
+		err = db.SavedSearchEmailMutes.Mute(ctx, t.UserID, t.Subject, t.SavedQueryKey, until)
+	} else {
+		err = db.SavedSearchEmailMutes.Unmute(ctx, t.UserID, t.Subject, t.SavedQueryKey)
+	}
+	if err != nil {
+		return errors.Wrap(err, "SavedSearchEmailMutes")
+	}
+	return sendReply(ctx, t.UserID, msg, cmd.confirmation(now))
+}
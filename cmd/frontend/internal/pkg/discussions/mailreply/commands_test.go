@@ -0,0 +1,40 @@
+package mailreply
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSavedSearchCommand(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    *savedSearchCommand
+		wantErr bool
+	}{
+		{input: "unsubscribe", want: &savedSearchCommand{mute: true}},
+		{input: "Unsubscribe\r\n\r\n-- \r\nJane Doe", want: &savedSearchCommand{mute: true}},
+		{input: "subscribe", want: &savedSearchCommand{mute: false}},
+		{input: "unmute", want: &savedSearchCommand{mute: false}},
+		{input: "mute", want: &savedSearchCommand{mute: true, duration: defaultMuteDuration}},
+		{input: "mute 1w", want: &savedSearchCommand{mute: true, duration: 7 * 24 * time.Hour}},
+		{input: "mute 3d", want: &savedSearchCommand{mute: true, duration: 3 * 24 * time.Hour}},
+		{input: "MUTE 12h", want: &savedSearchCommand{mute: true, duration: 12 * time.Hour}},
+		{input: "mute 30 minutes", want: &savedSearchCommand{mute: true, duration: 30 * time.Minute}},
+		{input: "mute 2 weeks", want: &savedSearchCommand{mute: true, duration: 14 * 24 * time.Hour}},
+		{input: "mute 0d", wantErr: true},
+		{input: "mute forever", wantErr: true},
+		{input: "thanks!", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			got, err := parseSavedSearchCommand(test.input)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
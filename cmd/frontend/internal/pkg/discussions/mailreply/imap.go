@@ -143,17 +143,17 @@ func NewMailReader() (*MailReader, error) {
 	if !conf.CanReadEmail() {
 		return nil, nil
 	}
-	conf := conf.Get()
+	cfg := conf.Get()
 
 	// Connect to the IMAP server.
-	c, err := client.DialTLS(net.JoinHostPort(conf.EmailImap.Host, strconv.Itoa(conf.EmailImap.Port)), nil)
+	c, err := client.DialTLS(net.JoinHostPort(cfg.EmailImap.Host, strconv.Itoa(cfg.EmailImap.Port)), nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "DialTLS")
 	}
 
 	// Login, if needed.
-	if conf.EmailImap.Username != "" {
-		if err := c.Login(conf.EmailImap.Username, conf.EmailImap.Password); err != nil {
+	if cfg.EmailImap.Username != "" {
+		if err := c.Login(cfg.EmailImap.Username, cfg.EmailImap.Password); err != nil {
 			return nil, errors.Wrap(err, "Login")
 		}
 	}
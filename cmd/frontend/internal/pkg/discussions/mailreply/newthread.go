@@ -0,0 +1,156 @@
+package mailreply
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/discussions"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+	"github.com/sourcegraph/sourcegraph/pkg/gitserver"
+	"github.com/sourcegraph/sourcegraph/pkg/vcs/git"
+)
+
+// threadLocation is the location in a repository that a new discussion thread
+// created by email is about.
+type threadLocation struct {
+	path string
+
+	// startLine and endLine are the 1-based, inclusive line range. They are
+	// zero if no line was specified.
+	startLine, endLine int
+}
+
+var threadLocationMatch = regexp.MustCompile(`^(\S+?)(?::(\d+)(?:-(\d+))?)?$`)
+
+// parseNewThreadEmail parses the contents of an email that creates a new
+// discussion thread. The first line is the location the thread is about, one
+// of "path", "path:line" or "path:startLine-endLine". The remaining lines are
+// the contents of the thread's first comment.
+func parseNewThreadEmail(contents string) (loc *threadLocation, comment string, err error) {
+	lines := strings.SplitN(strings.TrimSpace(contents), "\n", 2)
+	m := threadLocationMatch.FindStringSubmatch(strings.TrimSpace(lines[0]))
+	if m == nil {
+		return nil, "", fmt.Errorf("the first line of your email must be a file path and line (e.g. \"path/to/file.go:12\"), not %q", strings.TrimSpace(lines[0]))
+	}
+	loc = &threadLocation{path: strings.TrimPrefix(m[1], "/")}
+	if m[2] != "" {
+		loc.startLine, _ = strconv.Atoi(m[2])
+		loc.endLine = loc.startLine
+		if m[3] != "" {
+			loc.endLine, _ = strconv.Atoi(m[3])
+		}
+		if loc.startLine <= 0 || loc.endLine < loc.startLine {
+			return nil, "", fmt.Errorf("invalid line range in %q", m[0])
+		}
+	}
+	if len(lines) == 2 {
+		comment = strings.TrimSpace(lines[1])
+	}
+	if comment == "" {
+		return nil, "", errors.New("your email must contain a comment after the file path and line")
+	}
+	return loc, comment, nil
+}
+
+// handleNewThread handles an email sent to a repository's discussions email
+// address by creating a new discussion thread on that repository.
+func handleNewThread(ctx context.Context, msg *Message, token string) error {
+	userID, repoID, err := db.DiscussionMailNewThreadTokens.Get(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	// 🚨 SECURITY: Only users with a verified email may create discussion
+	// threads (see the createThread GraphQL mutation).
+	if _, verified, err := db.UserEmails.GetPrimaryEmail(ctx, userID); err != nil {
+		return errors.Wrap(err, "GetPrimaryEmail")
+	} else if !verified {
+		return nil // ignore the message
+	}
+
+	contents, err := messageContents(msg)
+	if err != nil || contents == "" {
+		return err // ignore empty emails
+	}
+
+	// 🚨 SECURITY: Act as the user so that the repository is only accessible
+	// if the user has permission to view it.
+	ctx = actor.WithActor(ctx, &actor.Actor{UID: userID})
+	repo, err := db.Repos.Get(ctx, repoID)
+	if err != nil {
+		if errcode.IsNotFound(err) {
+			return sendReply(ctx, userID, msg, "Sorry, the repository for this email address was not found.")
+		}
+		return errors.Wrap(err, "Repos.Get")
+	}
+
+	loc, comment, err := parseNewThreadEmail(contents)
+	if err != nil {
+		return sendReply(ctx, userID, msg, fmt.Sprintf("Sorry, %s.", err))
+	}
+	target, err := newThreadTarget(ctx, repo, loc)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return sendReply(ctx, userID, msg, fmt.Sprintf("Sorry, the file %q was not found in %s.", loc.path, repo.Name))
+		}
+		return err
+	}
+
+	title := strings.TrimSpace(msg.Envelope.Subject)
+	if title == "" {
+		// Title defaults to first line of contents.
+		title = strings.TrimSpace(strings.SplitN(comment, "\n", 2)[0])
+	}
+	_, err = discussions.InsecureCreateThread(ctx, &types.DiscussionThread{
+		AuthorUserID: userID,
+		Title:        title,
+		TargetRepo:   target,
+	}, comment)
+	if err != nil {
+		return errors.Wrap(err, "InsecureCreateThread")
+	}
+	return nil
+}
+
+// newThreadTarget returns the target of a new thread at the given location in
+// the repository's default branch.
+func newThreadTarget(ctx context.Context, repo *types.Repo, loc *threadLocation) (*types.DiscussionThreadTargetRepo, error) {
+	gitRepo := gitserver.Repo{Name: repo.Name}
+	commitID, err := git.ResolveRevision(ctx, gitRepo, nil, "HEAD", nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "ResolveRevision")
+	}
+	content, err := git.ReadFile(ctx, gitRepo, commitID, loc.path)
+	if err != nil {
+		return nil, err
+	}
+
+	revision := string(commitID)
+	target := &types.DiscussionThreadTargetRepo{
+		RepoID:   repo.ID,
+		Path:     &loc.path,
+		Revision: &revision,
+	}
+	if loc.startLine == 0 {
+		return target, nil
+	}
+	selection := discussions.LineRange{StartLine: loc.startLine - 1, EndLine: loc.endLine}
+	linesBefore, lines, linesAfter := discussions.LinesForSelection(string(content), selection)
+	startLine, endLine, zero := int32(selection.StartLine), int32(selection.EndLine), int32(0)
+	target.StartLine = &startLine
+	target.EndLine = &endLine
+	target.StartCharacter = &zero
+	target.EndCharacter = &zero
+	target.LinesBefore = &linesBefore
+	target.Lines = &lines
+	target.LinesAfter = &linesAfter
+	return target, nil
+}
@@ -0,0 +1,69 @@
+package mailreply
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseNewThreadEmail(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantLoc     *threadLocation
+		wantComment string
+		wantErr     bool
+	}{
+		{
+			name:        "path",
+			input:       "mux.go\r\nWhy is this here?",
+			wantLoc:     &threadLocation{path: "mux.go"},
+			wantComment: "Why is this here?",
+		},
+		{
+			name:        "line",
+			input:       "\r\n/pkg/mux.go:12\r\n\r\nWhy is this here?\r\nAnd this?",
+			wantLoc:     &threadLocation{path: "pkg/mux.go", startLine: 12, endLine: 12},
+			wantComment: "Why is this here?\r\nAnd this?",
+		},
+		{
+			name:        "line range",
+			input:       "pkg/mux.go:12-15\nWhy is this here?",
+			wantLoc:     &threadLocation{path: "pkg/mux.go", startLine: 12, endLine: 15},
+			wantComment: "Why is this here?",
+		},
+		{
+			name:    "invalid line range",
+			input:   "pkg/mux.go:15-12\nWhy is this here?",
+			wantErr: true,
+		},
+		{
+			name:    "zero line",
+			input:   "pkg/mux.go:0\nWhy is this here?",
+			wantErr: true,
+		},
+		{
+			name:    "no location",
+			input:   "Why is this here?",
+			wantErr: true,
+		},
+		{
+			name:    "no comment",
+			input:   "pkg/mux.go:12",
+			wantErr: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			loc, comment, err := parseNewThreadEmail(test.input)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if !reflect.DeepEqual(loc, test.wantLoc) {
+				t.Errorf("got location %+v, want %+v", loc, test.wantLoc)
+			}
+			if comment != test.wantComment {
+				t.Errorf("got comment %q, want %q", comment, test.wantComment)
+			}
+		})
+	}
+}
@@ -1,5 +1,6 @@
 // Package mailreply implements an IMAP inbox monitor to consume email replies
-// to discussions.
+// to discussions and saved search notifications, and emails that create new
+// discussion threads.
 package mailreply
 
 import (
@@ -14,12 +15,15 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/rcache"
+	"github.com/sourcegraph/sourcegraph/pkg/txemail"
+	"github.com/sourcegraph/sourcegraph/pkg/txemail/txtypes"
 	log15 "gopkg.in/inconshreveable/log15.v2"
 )
 
 // StartWorker should be invoked only after the DB has been initialized. It
 // starts the background worker which is responsible for reading mail and
-// updating discussion threads based on email replies.
+// updating discussion threads and saved search subscriptions based on email
+// replies.
 //
 // It should be invoked in a separate goroutine.
 func StartWorker() {
@@ -74,57 +78,29 @@ func workForever(ctx context.Context) {
 			// would be completely insecure doing to being easily spoofed).
 			//
 			// See https://tools.ietf.org/html/rfc5233 for details on sub-addressing.
-			var (
-				haveAuthorization bool
-				userID            int32
-				threadID          int64
-			)
-			for _, toAddress := range msg.Envelope.To {
-				// Parse the token ("SomeSecret123") out of the mailbox name ("notifications+SomeSecret123").
-				split := strings.Split(toAddress.MailboxName, "+")
-				if len(split) < 2 {
-					continue
-				}
-				token := split[len(split)-1]
-
-				// Verify the token.
-				userID, threadID, err = db.DiscussionMailReplyTokens.Get(ctx, token)
-				if err == db.ErrInvalidToken {
-					log15.Debug("discussions: mailreply worker: ignoring email with invalid authorization token", "subject", msg.Envelope.Subject, "mailbox_name", toAddress.MailboxName)
-					msg.MarkSeenAndDeleted()
-					break // Invalid token / attacker
-				}
-				if err != nil {
-					log15.Error("discussions: mailreply worker: error while looking up token", "error", err)
-					continue
-				}
-				haveAuthorization = true
-				break
-			}
-			if !haveAuthorization {
+			token, ok := subAddressToken(msg)
+			if !ok {
 				continue // ignore the message
 			}
 
-			textContent, err := msg.TextContent()
-			if err != nil {
-				log15.Error("discussions: mailreply worker: error while reading TextContent", "error", err)
-				continue
+			// The token's prefix determines what kind of email this is, and
+			// each handler verifies the token against its own table.
+			var handle func(context.Context, *Message, string) error
+			switch {
+			case strings.HasPrefix(token, db.SavedSearchMailReplyTokenPrefix):
+				handle = handleSavedSearchCommand
+			case strings.HasPrefix(token, db.DiscussionMailNewThreadTokenPrefix):
+				handle = handleNewThread
+			default:
+				handle = handleThreadReply
 			}
-
-			contents := strings.TrimSpace(string(trimGmailReplyQuote(textContent)))
-			if contents == "" {
-				log15.Debug("discussions: mailreply worker: ignoring email with no effective content", "subject", msg.Envelope.Subject, "content", string(textContent))
-				msg.MarkSeenAndDeleted()
-				continue // ignore empty replies
-			}
-
-			_, err = discussions.InsecureAddCommentToThread(ctx, &types.DiscussionComment{
-				ThreadID:     threadID,
-				AuthorUserID: userID,
-				Contents:     contents,
-			})
-			if err != nil {
-				log15.Error("discussions: mailreply worker: error while adding comment to thread", "error", err)
+			if err := handle(ctx, msg, token); err != nil {
+				if err == db.ErrInvalidToken {
+					log15.Debug("discussions: mailreply worker: ignoring email with invalid authorization token", "subject", msg.Envelope.Subject)
+					msg.MarkSeenAndDeleted()
+					continue // Invalid token / attacker
+				}
+				log15.Error("discussions: mailreply worker: error while handling email", "error", err)
 				continue
 			}
 
@@ -148,6 +124,97 @@ func workForever(ctx context.Context) {
 	}
 }
 
+// subAddressToken returns the authorization token ("SomeSecret123") from the
+// sub-address of the first of the message's "to" addresses that has one (e.g.
+// "notifications+SomeSecret123@sourcegraph.com").
+func subAddressToken(msg *Message) (token string, ok bool) {
+	for _, toAddress := range msg.Envelope.To {
+		// Parse the token ("SomeSecret123") out of the mailbox name ("notifications+SomeSecret123").
+		split := strings.Split(toAddress.MailboxName, "+")
+		if len(split) < 2 {
+			continue
+		}
+		return split[len(split)-1], true
+	}
+	return "", false
+}
+
+// messageContents returns the effective text contents of the message, with any
+// quoted reply trimmed. The empty string is returned if the message has no
+// effective content.
+func messageContents(msg *Message) (string, error) {
+	textContent, err := msg.TextContent()
+	if err != nil {
+		return "", errors.Wrap(err, "TextContent")
+	}
+	contents := strings.TrimSpace(string(trimGmailReplyQuote(textContent)))
+	if contents == "" {
+		log15.Debug("discussions: mailreply worker: ignoring email with no effective content", "subject", msg.Envelope.Subject, "content", string(textContent))
+	}
+	return contents, nil
+}
+
+// handleThreadReply handles a reply to a discussion thread email notification
+// by adding the reply as a comment to the thread.
+func handleThreadReply(ctx context.Context, msg *Message, token string) error {
+	userID, threadID, err := db.DiscussionMailReplyTokens.Get(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	contents, err := messageContents(msg)
+	if err != nil || contents == "" {
+		return err // ignore empty replies
+	}
+
+	_, err = discussions.InsecureAddCommentToThread(ctx, &types.DiscussionComment{
+		ThreadID:     threadID,
+		AuthorUserID: userID,
+		Contents:     contents,
+	})
+	return errors.Wrap(err, "InsecureAddCommentToThread")
+}
+
+// sendReply replies to the message with the given text. The reply is sent to
+// the user's verified primary email address, never to the message's (easily
+// spoofed) "From" address.
+func sendReply(ctx context.Context, userID int32, msg *Message, text string) error {
+	email, verified, err := db.UserEmails.GetPrimaryEmail(ctx, userID)
+	if err != nil {
+		return errors.Wrap(err, "GetPrimaryEmail")
+	}
+	if !verified {
+		return nil // only send email to verified addresses
+	}
+
+	var references []string
+	if messageID := strings.Trim(msg.Envelope.MessageId, "<>"); messageID != "" {
+		references = []string{messageID}
+	}
+	subject := msg.Envelope.Subject
+	if !strings.HasPrefix(strings.ToLower(subject), "re:") {
+		subject = "Re: " + subject
+	}
+	return txemail.Send(ctx, txemail.Message{
+		To:         []string{email},
+		References: references,
+		Template:   replyEmailTemplates,
+		Data: struct {
+			Subject string
+			Text    string
+		}{
+			Subject: subject,
+			Text:    text,
+		},
+	})
+}
+
+var replyEmailTemplates = txemail.MustValidate(txtypes.Templates{
+	Subject: `{{.Subject}}`,
+	Text:    `{{.Text}}`,
+	HTML:    `<p style="white-space: pre-wrap">{{.Text}}</p>`,
+})
+
 var gmailQuoteMatch = regexp.MustCompile(`(\r\n|\n).*On .* at .*, (.|\r\n|\n)*wrote\:(.|\r\n|\n)*(\r\n|\n)+(>.*(\r\n|\n))+(.|\r\n|\n)*`)
 
 // trimGmailReplyQuote trims the gmail reply quotation out of the given
@@ -10,7 +10,6 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/goroutine"
-	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/discussions/mentions"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/markdown"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
@@ -25,7 +24,7 @@ import (
 //
 // It returns immediately and does not block.
 func NotifyNewThread(newThread *types.DiscussionThread, newComment *types.DiscussionComment) {
-	notifyMentions(&notifier{
+	notifySubscribers(&notifier{
 		typ:               newThreadNotification,
 		eventAuthorUserID: newComment.AuthorUserID,
 		thread:            newThread,
@@ -39,7 +38,7 @@ func NotifyNewThread(newThread *types.DiscussionThread, newComment *types.Discus
 //
 // It returns immediately and does not block.
 func NotifyNewComment(updatedThread *types.DiscussionThread, newComment *types.DiscussionComment) {
-	notifyMentions(&notifier{
+	notifySubscribers(&notifier{
 		typ:               newCommentNotification,
 		eventAuthorUserID: newComment.AuthorUserID,
 		thread:            updatedThread,
@@ -48,10 +47,10 @@ func NotifyNewComment(updatedThread *types.DiscussionThread, newComment *types.D
 	})
 }
 
-func notifyMentions(n *notifier) {
+func notifySubscribers(n *notifier) {
 	goroutine.Go(func() {
 		ctx := context.Background()
-		subscribers, err := n.subscribers(ctx)
+		subscribers, err := Subscribers(ctx, n.thread)
 		if err != nil {
 			log15.Error("discussions: determining subscribers", "error", err)
 		}
@@ -60,6 +59,9 @@ func notifyMentions(n *notifier) {
 				log15.Error("discussions: notifyUsername", "error", err)
 			}
 		}
+		if err := n.enqueueWebhooks(ctx, subscribers); err != nil {
+			log15.Error("discussions: enqueueing webhooks", "error", err)
+		}
 	})
 }
 
@@ -78,53 +80,6 @@ type notifier struct {
 	template          txtypes.Templates
 }
 
-// subscribers returns a list of all usernames who are subscribed to receive
-// notifications from the thread. Currently, there is no underlying
-// subscription store, so we rely on some simple mechanics to get a good-enough
-// result:
-//
-// 	1. If you were previously mentioned in the thread, you are subscribed.
-// 	2. If you previously authored a comment, you are subscribed.
-//
-func (n *notifier) subscribers(ctx context.Context) ([]string, error) {
-	comments, err := db.DiscussionComments.List(ctx, &db.DiscussionCommentsListOptions{
-		LimitOffset: &db.LimitOffset{
-			Limit: 1000,
-		},
-		ThreadID: &n.thread.ID,
-	})
-	if err != nil {
-		return nil, err
-	}
-	var (
-		subscribers []string
-		set         = make(map[string]struct{})
-	)
-	for _, mention := range mentions.Parse(n.thread.Title) {
-		if _, ok := set[mention]; !ok {
-			set[mention] = struct{}{}
-			subscribers = append(subscribers, mention)
-		}
-	}
-	for _, comment := range comments {
-		commentAuthor, err := db.Users.GetByID(ctx, comment.AuthorUserID)
-		if err != nil {
-			return nil, errors.Wrap(err, "CommentAuthor: GetByID")
-		}
-		if _, ok := set[commentAuthor.Username]; !ok {
-			set[commentAuthor.Username] = struct{}{}
-			subscribers = append(subscribers, commentAuthor.Username)
-		}
-		for _, mention := range mentions.Parse(comment.Contents) {
-			if _, ok := set[mention]; !ok {
-				set[mention] = struct{}{}
-				subscribers = append(subscribers, mention)
-			}
-		}
-	}
-	return subscribers, nil
-}
-
 func (n *notifier) notifyUsername(ctx context.Context, username string) error {
 	if !conf.CanSendEmail() {
 		// Can't send email, so we have nothing to do.
@@ -157,10 +112,9 @@ func (n *notifier) notifyUsername(ctx context.Context, username string) error {
 			return errors.Wrap(err, "DiscussionMailReplyTokens.Generate")
 		}
 
-		conf := conf.Get()
-		emailParts := strings.Split(conf.EmailImap.Username, "@")
-		secureReplyTo := fmt.Sprintf("%s+%s@%s", emailParts[0], secureToken, emailParts[1])
+		secureReplyTo := MailSubAddress(secureToken)
 		replyTo = &secureReplyTo
+		emailParts := strings.Split(conf.Get().EmailImap.Username, "@")
 
 		// Generate a unique message ID. This is used by e.g. Gmail to uniquely
 		// identify this email message and so that we can reference it in later
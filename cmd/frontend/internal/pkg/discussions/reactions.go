@@ -0,0 +1,14 @@
+package discussions
+
+// Reactions is the list of emoji that users may react to comments with.
+var Reactions = []string{"👍", "👎", "😄", "🎉", "😕", "❤️", "🚀", "👀"}
+
+// IsValidReaction tells if users may react to comments with the given emoji.
+func IsValidReaction(reaction string) bool {
+	for _, r := range Reactions {
+		if r == reaction {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,97 @@
+package reviewimport
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitlab"
+)
+
+func TestGitHubReviewComment(t *testing.T) {
+	comment := &github.PullRequestReviewComment{
+		ID:               2,
+		InReplyToID:      1,
+		Path:             "a/b.go",
+		DiffHunk:         "@@ -1,2 +1,3 @@\n a\n+b\n c",
+		OriginalCommitID: "0000000000000000000000000000000000000001",
+		Body:             "Nice!",
+		HTMLURL:          "https://github.com/o/r/pull/3#discussion_r2",
+	}
+	comment.User.ID = 4
+	comment.User.Login = "alice"
+
+	got := gitHubReviewComment("https://github.com/", comment)
+	want := &reviewComment{
+		externalThreadID: "https://github.com/#comment-1",
+		externalID:       "https://github.com/#comment-2",
+		url:              "https://github.com/o/r/pull/3#discussion_r2",
+		codeHost:         "GitHub",
+		authorID:         "4",
+		authorLogin:      "alice",
+		body:             "Nice!",
+		path:             "a/b.go",
+		revision:         "0000000000000000000000000000000000000001",
+		line:             3,
+		lines:            []string{"a", "b", "c"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestGitLabReviewComments(t *testing.T) {
+	oldLine := 7
+	mr := &gitlab.MergeRequest{WebURL: "https://gitlab.com/o/r/merge_requests/3"}
+	discussion := &gitlab.Discussion{
+		ID: "abc",
+		Notes: []*gitlab.Note{
+			{
+				ID:       10,
+				Type:     "DiffNote",
+				Body:     "Why remove this?",
+				Author:   gitlab.User{ID: 4, Username: "alice"},
+				Position: &gitlab.NotePosition{BaseSHA: "base", HeadSHA: "head", OldPath: "a.go", NewPath: "b.go", OldLine: &oldLine},
+			},
+			{ID: 11, Body: "changed this line in version 2 of the diff", System: true},
+			{ID: 12, Type: "DiffNote", Body: "Unused.", Author: gitlab.User{ID: 5, Username: "bob"}},
+		},
+	}
+
+	got := gitLabReviewComments("https://gitlab.com/", mr, discussion)
+	want := []*reviewComment{
+		{
+			externalThreadID: "https://gitlab.com/#discussion-abc",
+			externalID:       "https://gitlab.com/#note-10",
+			url:              "https://gitlab.com/o/r/merge_requests/3#note_10",
+			codeHost:         "GitLab",
+			authorID:         "4",
+			authorLogin:      "alice",
+			body:             "Why remove this?",
+			path:             "a.go",
+			revision:         "base",
+			line:             7,
+		},
+		{
+			externalThreadID: "https://gitlab.com/#discussion-abc",
+			externalID:       "https://gitlab.com/#note-12",
+			url:              "https://gitlab.com/o/r/merge_requests/3#note_12",
+			codeHost:         "GitLab",
+			authorID:         "5",
+			authorLogin:      "bob",
+			body:             "Unused.",
+			path:             "a.go",
+			revision:         "base",
+			line:             7,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// Discussions that are not on the diff are not imported.
+	discussion.Notes[0].Type = ""
+	if got := gitLabReviewComments("https://gitlab.com/", mr, discussion); got != nil {
+		t.Errorf("got %+v, want nil", got)
+	}
+}
@@ -0,0 +1,66 @@
+package reviewimport
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/github"
+)
+
+// importGitHub imports the review comments on the pull requests of a GitHub
+// repository that were updated since the given time (or all review comments
+// if since is zero).
+func importGitHub(ctx context.Context, client *github.Client, im *importer, since time.Time) error {
+	ghRepo, err := client.GetRepositoryByNodeID(ctx, "", im.repo.ExternalRepo.ID)
+	if err != nil {
+		return errors.Wrap(err, "GetRepositoryByNodeID")
+	}
+	owner, name, err := github.SplitRepositoryNameWithOwner(ghRepo.NameWithOwner)
+	if err != nil {
+		return err
+	}
+
+	// Comments are listed oldest first, so the first comment of a review
+	// thread is always imported before its replies.
+	for page := 1; ; page++ {
+		comments, hasNextPage, err := client.ListPullRequestReviewComments(ctx, owner, name, since, page)
+		if err != nil {
+			return errors.Wrap(err, "ListPullRequestReviewComments")
+		}
+		for _, comment := range comments {
+			if err := im.importComment(ctx, gitHubReviewComment(im.repo.ExternalRepo.ServiceID, comment)); err != nil {
+				return errors.Wrapf(err, "importing review comment %s", comment.HTMLURL)
+			}
+		}
+		if !hasNextPage {
+			return nil
+		}
+	}
+}
+
+// gitHubReviewComment converts a GitHub pull request review comment. Replies
+// are imported into the thread of the first comment they reply to.
+func gitHubReviewComment(serviceID string, comment *github.PullRequestReviewComment) *reviewComment {
+	threadID := comment.ID
+	if comment.InReplyToID != 0 {
+		threadID = comment.InReplyToID
+	}
+	c := &reviewComment{
+		externalThreadID: externalID(serviceID, "comment", threadID),
+		externalID:       externalID(serviceID, "comment", comment.ID),
+		url:              comment.HTMLURL,
+		codeHost:         "GitHub",
+		authorID:         strconv.FormatInt(comment.User.ID, 10),
+		authorLogin:      comment.User.Login,
+		body:             comment.Body,
+		path:             comment.Path,
+		revision:         comment.OriginalCommitID,
+	}
+	if line, lines, ok := parseDiffHunk(comment.DiffHunk); ok {
+		c.line = line
+		c.lines = lines
+	}
+	return c
+}
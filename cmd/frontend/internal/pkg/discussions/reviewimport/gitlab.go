@@ -0,0 +1,100 @@
+package reviewimport
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitlab"
+)
+
+// importGitLab imports the diff comments on the merge requests of a GitLab
+// project that were updated since the given time (or all diff comments if
+// since is zero).
+func importGitLab(ctx context.Context, client *gitlab.Client, im *importer, since time.Time) error {
+	q := url.Values{
+		"state":    []string{"all"},
+		"order_by": []string{"updated_at"},
+		"sort":     []string{"asc"},
+		"per_page": []string{"100"},
+	}
+	if !since.IsZero() {
+		q.Set("updated_after", since.UTC().Format(time.RFC3339))
+	}
+	nextPageURL := fmt.Sprintf("projects/%s/merge_requests?%s", im.repo.ExternalRepo.ID, q.Encode())
+	for {
+		mrs, next, err := client.ListMergeRequests(ctx, nextPageURL)
+		if err != nil {
+			return errors.Wrap(err, "ListMergeRequests")
+		}
+		for _, mr := range mrs {
+			if err := importGitLabMergeRequest(ctx, client, im, mr); err != nil {
+				return errors.Wrapf(err, "importing merge request %s", mr.WebURL)
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		nextPageURL = *next
+	}
+}
+
+func importGitLabMergeRequest(ctx context.Context, client *gitlab.Client, im *importer, mr *gitlab.MergeRequest) error {
+	nextPageURL := fmt.Sprintf("projects/%d/merge_requests/%d/discussions?per_page=100", mr.ProjectID, mr.IID)
+	for {
+		discussions, next, err := client.ListMergeRequestDiscussions(ctx, nextPageURL)
+		if err != nil {
+			return errors.Wrap(err, "ListMergeRequestDiscussions")
+		}
+		for _, discussion := range discussions {
+			for _, c := range gitLabReviewComments(im.repo.ExternalRepo.ServiceID, mr, discussion) {
+				if err := im.importComment(ctx, c); err != nil {
+					return errors.Wrapf(err, "importing note %s", c.url)
+				}
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		nextPageURL = *next
+	}
+}
+
+// gitLabReviewComments converts the notes of a GitLab merge request
+// discussion. Only discussions on the diff (i.e. that start with a diff note)
+// are converted, and notes created by GitLab itself are skipped.
+func gitLabReviewComments(serviceID string, mr *gitlab.MergeRequest, discussion *gitlab.Discussion) []*reviewComment {
+	if len(discussion.Notes) == 0 || discussion.Notes[0].Type != "DiffNote" || discussion.Notes[0].Position == nil {
+		return nil
+	}
+	var (
+		comments []*reviewComment
+		position = discussion.Notes[0].Position
+	)
+	for _, note := range discussion.Notes {
+		if note.System {
+			continue
+		}
+		c := &reviewComment{
+			externalThreadID: externalID(serviceID, "discussion", discussion.ID),
+			externalID:       externalID(serviceID, "note", note.ID),
+			url:              fmt.Sprintf("%s#note_%d", mr.WebURL, note.ID),
+			codeHost:         "GitLab",
+			authorID:         strconv.FormatInt(int64(note.Author.ID), 10),
+			authorLogin:      note.Author.Username,
+			body:             note.Body,
+		}
+		if position.NewLine != nil {
+			c.path, c.revision, c.line = position.NewPath, position.HeadSHA, *position.NewLine
+		} else if position.OldLine != nil {
+			// The commented line was removed, so it only exists in the merge
+			// request's base.
+			c.path, c.revision, c.line = position.OldPath, position.BaseSHA, *position.OldLine
+		}
+		comments = append(comments, c)
+	}
+	return comments
+}
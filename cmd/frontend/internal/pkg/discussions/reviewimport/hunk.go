@@ -0,0 +1,47 @@
+package reviewimport
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseDiffHunk parses the diff hunk of a GitHub pull request review comment,
+// which ends at the commented line. It returns the commented line (1-based) in
+// the new version of the file, and the lines of the hunk in the new version
+// of the file up to and including the commented line.
+//
+// ok is false if the hunk is malformed, or if the commented line was removed
+// (and therefore does not exist in the new version of the file).
+func parseDiffHunk(hunk string) (line int, newLines []string, ok bool) {
+	hunkLines := strings.Split(strings.TrimSuffix(hunk, "\n"), "\n")
+	var origStart, newStart int
+	if _, err := fmt.Sscanf(hunkLines[0], "@@ -%d", &origStart); err != nil {
+		return 0, nil, false
+	}
+	i := strings.Index(hunkLines[0], " +")
+	if i == -1 {
+		return 0, nil, false
+	}
+	if _, err := fmt.Sscanf(hunkLines[0][i:], " +%d", &newStart); err != nil {
+		return 0, nil, false
+	}
+
+	var last byte
+	for _, l := range hunkLines[1:] {
+		if l == "" {
+			l = " " // some tools strip the trailing space of empty context lines
+		}
+		switch l[0] {
+		case ' ', '+':
+			newLines = append(newLines, l[1:])
+		case '-':
+		default:
+			continue // e.g. "\ No newline at end of file"
+		}
+		last = l[0]
+	}
+	if len(newLines) == 0 || last == '-' {
+		return 0, nil, false
+	}
+	return newStart + len(newLines) - 1, newLines, true
+}
@@ -0,0 +1,74 @@
+package reviewimport
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDiffHunk(t *testing.T) {
+	tests := []struct {
+		name     string
+		hunk     string
+		line     int
+		newLines []string
+		ok       bool
+	}{
+		{
+			name:     "added line",
+			hunk:     "@@ -1,2 +1,3 @@\n a\n+b\n c",
+			line:     3,
+			newLines: []string{"a", "b", "c"},
+			ok:       true,
+		},
+		{
+			name:     "removed lines before commented line",
+			hunk:     "@@ -10,4 +12,3 @@ func f() {\n a\n-b\n-c\n+d",
+			line:     13,
+			newLines: []string{"a", "d"},
+			ok:       true,
+		},
+		{
+			name:     "single line range",
+			hunk:     "@@ -0,0 +1 @@\n+a\n\\ No newline at end of file",
+			line:     1,
+			newLines: []string{"a"},
+			ok:       true,
+		},
+		{
+			name:     "empty context line",
+			hunk:     "@@ -1,3 +1,3 @@\n a\n\n+c",
+			line:     3,
+			newLines: []string{"a", "", "c"},
+			ok:       true,
+		},
+		{
+			name: "removed commented line",
+			hunk: "@@ -1,2 +1,1 @@\n a\n-b",
+			ok:   false,
+		},
+		{
+			name: "malformed header",
+			hunk: "@@ foo @@\n a",
+			ok:   false,
+		},
+		{
+			name: "empty",
+			hunk: "",
+			ok:   false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			line, newLines, ok := parseDiffHunk(test.hunk)
+			if ok != test.ok {
+				t.Fatalf("got ok %v, want %v", ok, test.ok)
+			}
+			if line != test.line {
+				t.Errorf("got line %d, want %d", line, test.line)
+			}
+			if !reflect.DeepEqual(newLines, test.newLines) {
+				t.Errorf("got new lines %q, want %q", newLines, test.newLines)
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package reviewimport
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/discussions"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/gitserver"
+	"github.com/sourcegraph/sourcegraph/pkg/vcs/git"
+)
+
+// reviewComment is a code review comment on a code host, in a form that is
+// independent of the code host.
+type reviewComment struct {
+	// externalThreadID and externalID identify the comment's thread and the
+	// comment on the code host. They are namespaced by the code host's
+	// service ID (see externalID).
+	externalThreadID, externalID string
+
+	url         string // the URL of the comment on the code host
+	codeHost    string // the name of the code host, e.g. "GitHub"
+	authorID    string // the ID of the author's account on the code host
+	authorLogin string // the username of the author on the code host
+	body        string
+
+	// The location of the comment. These are only used when the comment is
+	// the first comment of its thread.
+	path     string
+	revision string   // absolute Git revision, or empty if unknown
+	line     int      // 1-based, or 0 if unknown
+	lines    []string // the lines of the file up to and including line (fallback if the file can't be read)
+}
+
+// externalID returns an ID that identifies an object on the code host with
+// the given service ID.
+func externalID(serviceID, kind string, id interface{}) string {
+	return fmt.Sprintf("%s#%s-%v", serviceID, kind, id)
+}
+
+// importer imports the review comments of a repository.
+type importer struct {
+	repo *types.Repo
+
+	// users maps the IDs of the code host's accounts to the Sourcegraph users
+	// they are linked to.
+	users map[string]int32
+
+	// fallbackUserID is the Sourcegraph user that comments are attributed to
+	// when their author has no linked account.
+	fallbackUserID int32
+}
+
+// newImporter returns an importer for a repository, which must have been
+// synced from a code host (i.e. repo.ExternalRepo must be set).
+func newImporter(ctx context.Context, repo *types.Repo, fallbackUserID int32) (*importer, error) {
+	accounts, err := db.ExternalAccounts.List(ctx, db.ExternalAccountsListOptions{
+		ServiceType: repo.ExternalRepo.ServiceType,
+		ServiceID:   repo.ExternalRepo.ServiceID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "ExternalAccounts.List")
+	}
+	users := make(map[string]int32, len(accounts))
+	for _, account := range accounts {
+		users[account.AccountID] = account.UserID
+	}
+	return &importer{repo: repo, users: users, fallbackUserID: fallbackUserID}, nil
+}
+
+// importComment imports the review comment as a discussion comment, creating
+// its thread if needed. Comments that were already imported are skipped.
+//
+// The thread, comment and import record are created in a single transaction,
+// so a failed import can be retried without creating duplicates.
+//
+// Imported comments are not notified to the thread's subscribers, because
+// they were already notified on the code host.
+func (im *importer) importComment(ctx context.Context, c *reviewComment) error {
+	exists, err := db.DiscussionImportedComments.Exists(ctx, c.externalID)
+	if err != nil {
+		return errors.Wrap(err, "DiscussionImportedComments.Exists")
+	}
+	if exists {
+		return nil
+	}
+
+	authorUserID, ok := im.users[c.authorID]
+	if !ok {
+		authorUserID = im.fallbackUserID
+	}
+	threadID, ok, err := db.DiscussionImportedComments.GetThreadID(ctx, c.externalThreadID)
+	if err != nil {
+		return errors.Wrap(err, "DiscussionImportedComments.GetThreadID")
+	}
+	var newThread *types.DiscussionThread
+	if !ok {
+		newThread = &types.DiscussionThread{
+			AuthorUserID: authorUserID,
+			Title:        threadTitle(c),
+			TargetRepo:   im.threadTarget(ctx, c),
+		}
+	}
+
+	_, err = db.DiscussionImportedComments.Import(ctx, newThread, &types.DiscussionComment{
+		ThreadID:     threadID,
+		AuthorUserID: authorUserID,
+		Contents:     commentContents(c),
+	}, &types.DiscussionImportedComment{
+		ExternalThreadID: c.externalThreadID,
+		ExternalID:       c.externalID,
+		URL:              c.url,
+	})
+	return errors.Wrap(err, "DiscussionImportedComments.Import")
+}
+
+// threadTarget returns the target of the thread started by the comment. The
+// selection is the commented line, if it is known.
+func (im *importer) threadTarget(ctx context.Context, c *reviewComment) *types.DiscussionThreadTargetRepo {
+	target := &types.DiscussionThreadTargetRepo{RepoID: im.repo.ID}
+	if c.path == "" {
+		return target
+	}
+	target.Path = &c.path
+	if !git.IsAbsoluteRevision(c.revision) {
+		return target
+	}
+	target.Revision = &c.revision
+	if c.line <= 0 {
+		return target
+	}
+
+	selection := discussions.LineRange{StartLine: c.line - 1, EndLine: c.line}
+	var linesBefore, lines, linesAfter []string
+	if content, err := git.ReadFile(ctx, gitserver.Repo{Name: im.repo.Name}, api.CommitID(c.revision), c.path); err == nil {
+		linesBefore, lines, linesAfter = discussions.LinesForSelection(string(content), selection)
+	} else if len(c.lines) > 0 {
+		// The revision may not be available (e.g. if the pull request's
+		// branch was deleted), so fall back to the lines that the code host
+		// provided.
+		lines = c.lines[len(c.lines)-1:]
+		linesBefore = c.lines[:len(c.lines)-1]
+		if len(linesBefore) > 3 {
+			linesBefore = linesBefore[len(linesBefore)-3:]
+		}
+		linesAfter = []string{}
+	} else {
+		return target
+	}
+	startLine, endLine, zero := int32(selection.StartLine), int32(selection.EndLine), int32(0)
+	target.StartLine = &startLine
+	target.EndLine = &endLine
+	target.StartCharacter = &zero
+	target.EndCharacter = &zero
+	target.LinesBefore = &linesBefore
+	target.Lines = &lines
+	target.LinesAfter = &linesAfter
+	return target
+}
+
+// threadTitle returns the title of the thread started by the comment: the
+// first line of the comment.
+func threadTitle(c *reviewComment) string {
+	title := strings.TrimSpace(strings.SplitN(strings.TrimSpace(c.body), "\n", 2)[0])
+	if title == "" {
+		title = fmt.Sprintf("%s review comment", c.codeHost)
+	}
+	if runes := []rune(title); len(runes) > 200 {
+		title = string(runes[:200]) + "…"
+	}
+	return title
+}
+
+// commentContents returns the contents of the imported comment, which link
+// back to the original comment.
+func commentContents(c *reviewComment) string {
+	return fmt.Sprintf("%s\n\n*Imported from a [%s review comment](%s) by **%s**.*", c.body, c.codeHost, c.url, c.authorLogin)
+}
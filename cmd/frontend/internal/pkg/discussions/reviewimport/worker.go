@@ -0,0 +1,157 @@
+// Package reviewimport implements a background worker that imports code review
+// comments (GitHub pull request review comments and GitLab merge request diff
+// comments) as discussion threads.
+package reviewimport
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitlab"
+	"github.com/sourcegraph/sourcegraph/pkg/ratelimit"
+	"github.com/sourcegraph/sourcegraph/pkg/rcache"
+	"github.com/sourcegraph/sourcegraph/schema"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// importInterval is how often review comments are imported.
+const importInterval = time.Hour
+
+// StartWorker should be invoked only after the DB has been initialized. It
+// starts the background worker which imports review comments when the
+// "discussions.importReviewComments" site config is set.
+//
+// It should be invoked in a separate goroutine.
+func StartWorker() {
+	w := &worker{lastImported: map[api.RepoID]time.Time{}}
+	for {
+		if dc := conf.Get().Discussions; dc != nil && dc.ImportReviewComments != nil {
+			// Only one frontend instance should import at a time, so we use a
+			// distributed lock to guarantee this.
+			ctx, release, ok := rcache.TryAcquireMutex(context.Background(), "discussionsReviewImportWorker")
+			if ok {
+				log15.Debug("discussions: review import worker running")
+				if err := w.importAll(ctx, dc.ImportReviewComments); err != nil {
+					log15.Error("discussions: review import failed", "error", err)
+				}
+				release()
+			}
+		}
+		time.Sleep(importInterval)
+	}
+}
+
+type worker struct {
+	// lastImported is when the review comments of each repository were last
+	// imported, so that subsequent imports only list the comments updated
+	// since then. It is not persisted: after a restart, all comments are
+	// listed again (and those that were already imported are skipped).
+	lastImported map[api.RepoID]time.Time
+}
+
+// importAll imports the review comments of all repositories synced from the
+// GitHub and GitLab connections.
+func (w *worker) importAll(ctx context.Context, c *schema.DiscussionsImportReviewComments) error {
+	fallbackUser, err := db.Users.GetByUsername(ctx, c.AuthorUsername)
+	if err != nil {
+		return errors.Wrapf(err, "getting review comments author %q", c.AuthorUsername)
+	}
+	repos, err := listExternalRepos(ctx)
+	if err != nil {
+		return err
+	}
+	httpClient := ratelimit.BudgetMiddleware("discussions")(http.DefaultClient)
+
+	githubs, err := db.ExternalServices.ListGitHubConnections(ctx)
+	if err != nil {
+		return errors.Wrap(err, "ListGitHubConnections")
+	}
+	for _, conn := range githubs {
+		baseURL, err := url.Parse(conn.Url)
+		if err != nil {
+			log15.Error("discussions: invalid GitHub connection URL", "url", conn.Url, "error", err)
+			continue
+		}
+		baseURL = extsvc.NormalizeBaseURL(baseURL)
+		apiURL, _ := github.APIRoot(baseURL)
+		client := github.NewClient(apiURL, conn.Token, httpClient)
+		w.importRepos(ctx, repos, github.ServiceType, baseURL.String(), fallbackUser.ID, func(ctx context.Context, im *importer, since time.Time) error {
+			return importGitHub(ctx, client, im, since)
+		})
+	}
+
+	gitlabs, err := db.ExternalServices.ListGitLabConnections(ctx)
+	if err != nil {
+		return errors.Wrap(err, "ListGitLabConnections")
+	}
+	for _, conn := range gitlabs {
+		baseURL, err := url.Parse(conn.Url)
+		if err != nil {
+			log15.Error("discussions: invalid GitLab connection URL", "url", conn.Url, "error", err)
+			continue
+		}
+		baseURL = extsvc.NormalizeBaseURL(baseURL)
+		client := gitlab.NewClientProvider(baseURL, httpClient).GetPATClient(conn.Token, "")
+		w.importRepos(ctx, repos, gitlab.ServiceType, baseURL.String(), fallbackUser.ID, func(ctx context.Context, im *importer, since time.Time) error {
+			return importGitLab(ctx, client, im, since)
+		})
+	}
+	return nil
+}
+
+// importRepos imports the review comments of the repositories on the code
+// host with the given service type and ID. Errors are logged, so that one
+// failing repository doesn't prevent importing the others.
+func (w *worker) importRepos(ctx context.Context, repos []*types.Repo, serviceType, serviceID string, fallbackUserID int32, importRepo func(ctx context.Context, im *importer, since time.Time) error) {
+	for _, repo := range repos {
+		if repo.ExternalRepo.ServiceType != serviceType || repo.ExternalRepo.ServiceID != serviceID {
+			continue
+		}
+		started := time.Now()
+		if last, ok := w.lastImported[repo.ID]; ok && started.Sub(last) < importInterval/2 {
+			continue // already imported via another connection to the same code host
+		}
+		im, err := newImporter(ctx, repo, fallbackUserID)
+		if err == nil {
+			err = importRepo(ctx, im, w.lastImported[repo.ID])
+		}
+		if err != nil {
+			log15.Error("discussions: importing review comments failed", "repo", repo.Name, "error", err)
+			continue
+		}
+		w.lastImported[repo.ID] = started
+	}
+}
+
+// listExternalRepos lists the enabled repositories that were synced from a
+// code host.
+func listExternalRepos(ctx context.Context) ([]*types.Repo, error) {
+	var (
+		repos []*types.Repo
+		opt   = db.ReposListOptions{Enabled: true, LimitOffset: &db.LimitOffset{Limit: 1000}}
+	)
+	for {
+		page, err := db.Repos.List(ctx, opt)
+		if err != nil {
+			return nil, errors.Wrap(err, "Repos.List")
+		}
+		for _, repo := range page {
+			if repo.ExternalRepo != nil {
+				repos = append(repos, repo)
+			}
+		}
+		if len(page) < opt.Limit {
+			return repos, nil
+		}
+		opt.Offset += opt.Limit
+	}
+}
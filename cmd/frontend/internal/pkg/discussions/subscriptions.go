@@ -0,0 +1,67 @@
+package discussions
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/discussions/mentions"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+// Subscribers returns a list of all usernames who are subscribed to receive
+// notifications from the thread. Users are subscribed to a thread when:
+//
+// 	1. They explicitly subscribed to the thread.
+// 	2. They were mentioned in the thread, or authored a comment in it, unless
+// 	   they explicitly unsubscribed from the thread.
+//
+func Subscribers(ctx context.Context, thread *types.DiscussionThread) ([]string, error) {
+	subscribers, err := db.DiscussionThreadSubscriptions.ListSubscribers(ctx, thread.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "DiscussionThreadSubscriptions.ListSubscribers")
+	}
+	return subscribers, nil
+}
+
+// IsSubscribed tells if the user is subscribed to receive notifications from
+// the thread (see Subscribers).
+func IsSubscribed(ctx context.Context, thread *types.DiscussionThread, user *types.User) (bool, error) {
+	subscribed, err := db.DiscussionThreadSubscriptions.Get(ctx, thread.ID, user.ID)
+	if err != nil {
+		return false, errors.Wrap(err, "DiscussionThreadSubscriptions.Get")
+	}
+	return subscribed != nil && *subscribed, nil
+}
+
+// addParticipants subscribes the comment's author and the users mentioned in
+// the comment (and in the thread's title, if the comment is the first in the
+// thread) to the thread, unless they unsubscribed from it.
+func addParticipants(ctx context.Context, thread *types.DiscussionThread, comment *types.DiscussionComment, first bool) error {
+	texts := []string{comment.Contents}
+	if first {
+		texts = append(texts, thread.Title)
+	}
+	if err := db.DiscussionThreadSubscriptions.AddParticipants(ctx, thread.ID, comment.AuthorUserID, mentionedUsernames(texts...)); err != nil {
+		return errors.Wrap(err, "DiscussionThreadSubscriptions.AddParticipants")
+	}
+	return nil
+}
+
+// mentionedUsernames returns the usernames mentioned in the texts, without
+// duplicates.
+func mentionedUsernames(texts ...string) []string {
+	var (
+		usernames []string
+		set       = make(map[string]struct{})
+	)
+	for _, text := range texts {
+		for _, mention := range mentions.Parse(text) {
+			if _, ok := set[mention]; !ok && mention != "" {
+				set[mention] = struct{}{}
+				usernames = append(usernames, mention)
+			}
+		}
+	}
+	return usernames
+}
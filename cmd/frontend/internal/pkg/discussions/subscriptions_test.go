@@ -0,0 +1,34 @@
+package discussions
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMentionedUsernames(t *testing.T) {
+	tests := map[string]struct {
+		texts []string
+		want  []string
+	}{
+		"none": {
+			texts: []string{"hello world", "email@example.com"},
+			want:  nil,
+		},
+		"deduplicated": {
+			texts: []string{"@alice and @bob", "cc @alice @carol"},
+			want:  []string{"alice", "bob", "carol"},
+		},
+		"bare @": {
+			texts: []string{"hello @ world"},
+			want:  nil,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := mentionedUsernames(test.texts...)
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package discussions
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -12,13 +13,25 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 )
 
+// MailSubAddress returns the sub-address of the IMAP inbox's address (the
+// email.imap username, e.g. "notifications@example.com") with the given token
+// (e.g. "notifications+TOKEN@example.com"). The mailreply worker reads emails
+// sent to such addresses.
+//
+// Precondition: conf.CanReadEmail()
+func MailSubAddress(token string) string {
+	emailParts := strings.SplitN(conf.Get().EmailImap.Username, "@", 2)
+	return fmt.Sprintf("%s+%s@%s", emailParts[0], token, emailParts[1])
+}
+
 // InsecureAddCommentToThread handles adding a new comment to an existing
 // thread. It handles:
 //
 // 1. Rate limiting (NOT general permission handling).
 // 2. Creating the actual database entry.
-// 3. Notifying other users of the new comment.
-// 4. Fetching and returning the updated thread.
+// 3. Subscribing the author and mentioned users to the thread.
+// 4. Notifying other users of the new comment.
+// 5. Fetching and returning the updated thread.
 //
 // It does NOT verify that the user has permission to create this comment. That
 // is the responsibility of the caller.
@@ -38,6 +51,49 @@ func InsecureAddCommentToThread(ctx context.Context, newComment *types.Discussio
 	if err != nil {
 		return nil, errors.Wrap(err, "DiscussionThreads.Get")
 	}
+	if err := addParticipants(ctx, updatedThread, newComment, false); err != nil {
+		return nil, err
+	}
 	NotifyNewComment(updatedThread, newComment)
 	return updatedThread, nil
 }
+
+// InsecureCreateThread handles creating a new thread with its first comment.
+// It handles:
+//
+// 1. Rate limiting (NOT general permission handling).
+// 2. Creating the actual database entries.
+// 3. Subscribing the author and mentioned users to the thread.
+// 4. Notifying other users of the new thread.
+//
+// It does NOT verify that the user has permission to create this thread. That
+// is the responsibility of the caller.
+func InsecureCreateThread(ctx context.Context, newThread *types.DiscussionThread, contents string) (*types.DiscussionThread, error) {
+	if dc := conf.Get().Discussions; dc != nil && dc.AbuseProtection {
+		if mustWait := ratelimit.TimeUntilUserCanCreateThread(ctx, newThread.AuthorUserID, newThread.Title, contents); mustWait != 0 {
+			return nil, fmt.Errorf("You are creating threads too quickly. You may create a new one after %v", mustWait.Round(time.Second))
+		}
+	}
+
+	// Create the thread.
+	thread, err := db.DiscussionThreads.Create(ctx, newThread)
+	if err != nil {
+		return nil, errors.Wrap(err, "DiscussionThreads.Create")
+	}
+
+	// Create the first comment in the thread.
+	newComment := &types.DiscussionComment{
+		ThreadID:     thread.ID,
+		AuthorUserID: thread.AuthorUserID,
+		Contents:     contents,
+	}
+	_, err = db.DiscussionComments.Create(ctx, newComment)
+	if err != nil {
+		return nil, errors.Wrap(err, "DiscussionComments.Create")
+	}
+	if err := addParticipants(ctx, thread, newComment, true); err != nil {
+		return nil, err
+	}
+	NotifyNewThread(thread, newComment)
+	return thread, nil
+}
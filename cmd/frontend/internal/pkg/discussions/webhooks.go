@@ -0,0 +1,70 @@
+package discussions
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/webhooks"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+// enqueueWebhooks delivers a discussion_comment.created event for the new
+// comment to the site webhooks and to the webhooks of the thread's
+// subscribers.
+func (n *notifier) enqueueWebhooks(ctx context.Context, subscribers []string) error {
+	var userIDs []int32
+	for _, username := range subscribers {
+		user, err := db.Users.GetByUsername(ctx, username)
+		if err != nil {
+			return errors.Wrap(err, "GetByUsername")
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+	author, err := db.Users.GetByID(ctx, n.comment.AuthorUserID)
+	if err != nil {
+		return errors.Wrap(err, "GetByID")
+	}
+
+	payload := struct {
+		ThreadID       int64   `json:"threadID"`
+		ThreadTitle    string  `json:"threadTitle"`
+		NewThread      bool    `json:"newThread"`
+		CommentID      int64   `json:"commentID"`
+		AuthorUsername string  `json:"authorUsername"`
+		Contents       string  `json:"contents"`
+		Repository     *string `json:"repository,omitempty"`
+		Path           *string `json:"path,omitempty"`
+		URL            *string `json:"url,omitempty"`
+	}{
+		ThreadID:       n.thread.ID,
+		ThreadTitle:    n.thread.Title,
+		NewThread:      n.typ == newThreadNotification,
+		CommentID:      n.comment.ID,
+		AuthorUsername: author.Username,
+		Contents:       n.comment.Contents,
+	}
+	if n.thread.TargetRepo != nil {
+		repo, err := db.Repos.Get(ctx, n.thread.TargetRepo.RepoID)
+		if err != nil {
+			return errors.Wrap(err, "db.Repos.Get")
+		}
+		name := string(repo.Name)
+		payload.Repository = &name
+		payload.Path = n.thread.TargetRepo.Path
+	}
+	url, err := URLToInlineComment(ctx, n.thread, n.comment)
+	if err != nil {
+		return errors.Wrap(err, "URLToInlineComment")
+	}
+	if url != nil {
+		s := url.String()
+		payload.URL = &s
+	}
+
+	return webhooks.Enqueue(ctx, api.WebhookEnqueueRequest{
+		Event:   api.WebhookEventDiscussionCommentCreated,
+		UserIDs: userIDs,
+		Payload: payload,
+	})
+}
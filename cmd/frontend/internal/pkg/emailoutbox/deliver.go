@@ -0,0 +1,66 @@
+package emailoutbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/pkg/txemail"
+)
+
+// maxAttempts is the number of attempts after which an email is abandoned.
+const maxAttempts = 10
+
+// backoff returns how long to wait before the next attempt to send an email
+// whose attempt number n (starting at 1) failed: 30 seconds after the first
+// attempt, doubling after each subsequent attempt, up to 6 hours.
+func backoff(n int32) time.Duration {
+	const max = 6 * time.Hour
+	d := 30 * time.Second
+	for i := int32(1); i < n; i++ {
+		d *= 2
+		if d >= max {
+			return max
+		}
+	}
+	return d
+}
+
+// send sends an email with the configured transport. It is a variable so that
+// tests can mock it.
+var send = txemail.Deliver
+
+// deliver attempts to send the email and returns the result of the attempt,
+// which is to be recorded with db.EmailOutbox.MarkAttempt.
+func deliver(ctx context.Context, e *db.OutboxEmail) db.OutboxEmailAttempt {
+	err := send(ctx, &txemail.Email{
+		FromName:    e.FromName,
+		FromAddress: e.FromAddress,
+		To:          e.ToAddress,
+		ReplyTo:     e.ReplyTo,
+		MessageID:   e.MessageID,
+		References:  e.References,
+		Subject:     e.Subject,
+		Text:        e.TextBody,
+		HTML:        e.HTMLBody,
+	})
+	if err == nil {
+		return db.OutboxEmailAttempt{State: db.OutboxEmailStateSent}
+	}
+
+	attempt := db.OutboxEmailAttempt{Error: err.Error()}
+	if perr, ok := err.(*txemail.PermanentError); ok {
+		if perr.Bounce {
+			attempt.State = db.OutboxEmailStateBounced
+		} else {
+			attempt.State = db.OutboxEmailStateFailed
+		}
+	} else if e.Attempts+1 >= maxAttempts {
+		attempt.State = db.OutboxEmailStateFailed
+	} else {
+		attempt.State = db.OutboxEmailStatePending
+		next := time.Now().Add(backoff(e.Attempts + 1))
+		attempt.NextAttemptAt = &next
+	}
+	return attempt
+}
@@ -0,0 +1,63 @@
+package emailoutbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/pkg/txemail"
+)
+
+func TestBackoff(t *testing.T) {
+	for n, want := range map[int32]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		10: 256 * time.Minute,
+		11: 6 * time.Hour,
+	} {
+		if got := backoff(n); got != want {
+			t.Errorf("attempt %d: got %s, want %s", n, got, want)
+		}
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	var sendErr error
+	var sent *txemail.Email
+	send = func(ctx context.Context, e *txemail.Email) error {
+		sent = e
+		return sendErr
+	}
+	defer func() { send = txemail.Deliver }()
+
+	e := &db.OutboxEmail{ToAddress: "a@example.com", Subject: "s", TextBody: "t", HTMLBody: "h"}
+	if attempt := deliver(context.Background(), e); attempt.State != db.OutboxEmailStateSent || attempt.Error != "" {
+		t.Errorf("got %+v, want a sent attempt", attempt)
+	}
+	if sent == nil || sent.To != "a@example.com" || sent.Subject != "s" || sent.Text != "t" || sent.HTML != "h" {
+		t.Errorf("got sent email %+v", sent)
+	}
+
+	// A transient failure is retried with backoff, until the last attempt.
+	sendErr = errors.New("connection refused")
+	if attempt := deliver(context.Background(), e); attempt.State != db.OutboxEmailStatePending || attempt.NextAttemptAt == nil || attempt.Error == "" {
+		t.Errorf("got %+v, want a pending attempt to retry", attempt)
+	}
+	e.Attempts = maxAttempts - 1
+	if attempt := deliver(context.Background(), e); attempt.State != db.OutboxEmailStateFailed {
+		t.Errorf("got %+v, want a failed attempt", attempt)
+	}
+
+	// Permanent failures are not retried.
+	e.Attempts = 0
+	sendErr = &txemail.PermanentError{Err: errors.New("rejected")}
+	if attempt := deliver(context.Background(), e); attempt.State != db.OutboxEmailStateFailed {
+		t.Errorf("got %+v, want a failed attempt", attempt)
+	}
+	sendErr = &txemail.PermanentError{Err: errors.New("no such user"), Bounce: true}
+	if attempt := deliver(context.Background(), e); attempt.State != db.OutboxEmailStateBounced || attempt.Error != "no such user" {
+		t.Errorf("got %+v, want a bounced attempt", attempt)
+	}
+}
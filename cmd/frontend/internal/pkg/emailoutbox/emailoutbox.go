@@ -0,0 +1,33 @@
+// Package emailoutbox implements the email outbox, which persists outgoing
+// transactional emails so that they are delivered by a background worker and
+// retried with exponential backoff if delivery fails (e.g., because the SMTP
+// server is temporarily unavailable).
+//
+// Emails that the recipient's mail server permanently rejects are recorded as
+// bounced, both in the outbox and against the recipient's user email address.
+package emailoutbox
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/pkg/txemail"
+)
+
+// Enqueue persists the rendered email in the outbox. It is delivered by the
+// worker (see StartWorker) as soon as possible.
+//
+// It is the frontend's txemail.Outbox.
+func Enqueue(ctx context.Context, e *txemail.Email) error {
+	return db.EmailOutbox.Create(ctx, &db.OutboxEmail{
+		FromName:    e.FromName,
+		FromAddress: e.FromAddress,
+		ToAddress:   e.To,
+		ReplyTo:     e.ReplyTo,
+		MessageID:   e.MessageID,
+		References:  e.References,
+		Subject:     e.Subject,
+		TextBody:    e.Text,
+		HTMLBody:    e.HTML,
+	})
+}
@@ -0,0 +1,72 @@
+package emailoutbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+const (
+	// pollInterval is how often the worker checks for emails that are due to
+	// be sent.
+	pollInterval = 10 * time.Second
+
+	// batchSize is the maximum number of emails sent per poll.
+	batchSize = 50
+
+	// retention is how long the history of sent (and abandoned) emails is
+	// kept.
+	retention = 30 * 24 * time.Hour
+)
+
+// StartWorker should be invoked only after the DB has been initialized. It
+// starts the background worker which sends the emails in the outbox.
+// Multiple frontend instances may run the worker concurrently, because each
+// dequeued email is leased to a single worker.
+//
+// It should be invoked in a separate goroutine.
+func StartWorker() {
+	ctx := context.Background()
+	var lastCleanup time.Time
+	for {
+		for {
+			n, err := deliverBatch(ctx)
+			if err != nil {
+				log15.Error("emailoutbox: sending emails failed", "error", err)
+			}
+			if err != nil || n < batchSize {
+				break
+			}
+		}
+		if time.Since(lastCleanup) > time.Hour {
+			if err := db.EmailOutbox.DeleteOlderThan(ctx, time.Now().Add(-retention)); err != nil {
+				log15.Error("emailoutbox: deleting old emails failed", "error", err)
+			}
+			lastCleanup = time.Now()
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// deliverBatch attempts to send the emails that are due and returns how many
+// it attempted.
+func deliverBatch(ctx context.Context) (int, error) {
+	emails, err := db.EmailOutbox.Dequeue(ctx, batchSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, e := range emails {
+		attempt := deliver(ctx, e)
+		if attempt.State == db.OutboxEmailStateBounced {
+			if err := db.UserEmails.RecordBounce(ctx, e.ToAddress, attempt.Error); err != nil {
+				return 0, err
+			}
+		}
+		if err := db.EmailOutbox.MarkAttempt(ctx, e.ID, attempt); err != nil {
+			return 0, err
+		}
+	}
+	return len(emails), nil
+}
@@ -0,0 +1,150 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/pkg/httpcli"
+)
+
+const (
+	// maxAttempts is the number of attempts after which a delivery is
+	// abandoned.
+	maxAttempts = 10
+
+	// deliveryTimeout is how long to wait for a webhook's endpoint to
+	// respond.
+	deliveryTimeout = 10 * time.Second
+
+	// maxResponseBodySize is the number of bytes of a response body that are
+	// recorded in the delivery history.
+	maxResponseBodySize = 1024
+)
+
+// backoff returns how long to wait before the next attempt of a delivery
+// whose attempt number n (starting at 1) failed: 30 seconds after the first
+// attempt, doubling after each subsequent attempt, up to 6 hours.
+func backoff(n int32) time.Duration {
+	const max = 6 * time.Hour
+	d := 30 * time.Second
+	for i := int32(1); i < n; i++ {
+		d *= 2
+		if d >= max {
+			return max
+		}
+	}
+	return d
+}
+
+// signature returns the value of the X-Sourcegraph-Signature header of a
+// delivery: the hex-encoded HMAC-SHA256 of the request body, keyed with the
+// webhook's secret. Receivers should compute it themselves and compare it to
+// the header's value to verify that the request was sent by Sourcegraph.
+func signature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// requestBody returns the JSON body of the HTTP request for a delivery.
+func requestBody(d *db.WebhookDelivery) ([]byte, error) {
+	return json.Marshal(struct {
+		Event    string          `json:"event"`
+		Delivery string          `json:"delivery"`
+		Data     json.RawMessage `json:"data"`
+	}{
+		Event:    d.EventType,
+		Delivery: strconv.FormatInt(d.ID, 10),
+		Data:     d.Payload,
+	})
+}
+
+// deliver attempts to deliver the event to the webhook and returns the
+// result of the attempt, which is to be recorded with
+// db.WebhookDeliveries.MarkAttempt.
+func deliver(ctx context.Context, client *http.Client, w *db.Webhook, d *db.WebhookDelivery) db.WebhookDeliveryAttempt {
+	attempt := post(ctx, client, w, d)
+	if attempt.Error == "" {
+		attempt.State = db.WebhookDeliveryStateSucceeded
+	} else if d.Attempts+1 >= maxAttempts {
+		attempt.State = db.WebhookDeliveryStateFailed
+	} else {
+		attempt.State = db.WebhookDeliveryStatePending
+		next := time.Now().Add(backoff(d.Attempts + 1))
+		attempt.NextAttemptAt = &next
+	}
+	return attempt
+}
+
+func post(ctx context.Context, client *http.Client, w *db.Webhook, d *db.WebhookDelivery) (attempt db.WebhookDeliveryAttempt) {
+	body, err := requestBody(d)
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req, err := http.NewRequest("POST", w.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Sourcegraph-Webhooks")
+	req.Header.Set("X-Sourcegraph-Event", d.EventType)
+	req.Header.Set("X-Sourcegraph-Delivery", strconv.FormatInt(d.ID, 10))
+	req.Header.Set("X-Sourcegraph-Signature", signature(w.Secret, body))
+
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+	resp, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxResponseBodySize))
+	attempt.ResponseStatusCode = int32(resp.StatusCode)
+	attempt.ResponseBody = string(respBody)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		attempt.Error = fmt.Sprintf("unexpected HTTP response status %d", resp.StatusCode)
+	}
+	return attempt
+}
+
+var (
+	// siteClient delivers events to site webhooks, which are registered by
+	// site admins and may target any address (including internal services).
+	siteClient = &http.Client{
+		// Redirects are not followed, so that the request (and its
+		// signature) is only sent to the registered URL.
+		CheckRedirect: noRedirects,
+	}
+
+	// userClient delivers events to users' webhooks. It refuses to connect
+	// to loopback, private, and link-local addresses, so that users can't
+	// use webhooks to send requests to internal services.
+	userClient = httpcli.NewExternalClient(deliveryTimeout)
+)
+
+func noRedirects(*http.Request, []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// clientFor returns the HTTP client to use for deliveries to the webhook.
+func clientFor(w *db.Webhook) *http.Client {
+	if w.UserID == 0 {
+		return siteClient
+	}
+	return userClient
+}
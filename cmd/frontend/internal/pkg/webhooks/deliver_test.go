@@ -0,0 +1,108 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+)
+
+func TestBackoff(t *testing.T) {
+	for n, want := range map[int32]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		3:  2 * time.Minute,
+		9:  128 * time.Minute,
+		10: 256 * time.Minute,
+		11: 6 * time.Hour,
+		50: 6 * time.Hour,
+	} {
+		if got := backoff(n); got != want {
+			t.Errorf("attempt %d: got %s, want %s", n, got, want)
+		}
+	}
+}
+
+func TestSignature(t *testing.T) {
+	// Computed with: echo -n '{}' | openssl dgst -sha256 -hmac s
+	want := "sha256=143ca8d517ba1b181025d732b1cf275d90104fca57bb02a565542978aa18c4b6"
+	if got := signature("s", []byte("{}")); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	var (
+		status  = http.StatusOK
+		gotReq  *http.Request
+		gotBody []byte
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotReq = r
+		gotBody, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(status)
+		w.Write([]byte("ok"))
+	}))
+	defer ts.Close()
+
+	w := &db.Webhook{URL: ts.URL, Secret: "s"}
+	d := &db.WebhookDelivery{ID: 7, EventType: "repo.cloned", Payload: json.RawMessage(`{"repo":"r"}`)}
+
+	attempt := deliver(context.Background(), siteClient, w, d)
+	if attempt.State != db.WebhookDeliveryStateSucceeded || attempt.ResponseStatusCode != 200 || attempt.ResponseBody != "ok" {
+		t.Errorf("got %+v, want a succeeded attempt", attempt)
+	}
+	if want := `{"event":"repo.cloned","delivery":"7","data":{"repo":"r"}}`; string(gotBody) != want {
+		t.Errorf("got body %s, want %s", gotBody, want)
+	}
+	if got, want := gotReq.Header.Get("X-Sourcegraph-Signature"), signature("s", gotBody); got != want {
+		t.Errorf("got signature %q, want %q", got, want)
+	}
+	if got := gotReq.Header.Get("X-Sourcegraph-Event"); got != "repo.cloned" {
+		t.Errorf("got event header %q, want %q", got, "repo.cloned")
+	}
+
+	// A failed attempt is retried with backoff, until the last attempt.
+	status = http.StatusInternalServerError
+	attempt = deliver(context.Background(), siteClient, w, d)
+	if attempt.State != db.WebhookDeliveryStatePending || attempt.NextAttemptAt == nil || attempt.Error == "" {
+		t.Errorf("got %+v, want a pending attempt to retry", attempt)
+	}
+	d.Attempts = maxAttempts - 1
+	if attempt := deliver(context.Background(), siteClient, w, d); attempt.State != db.WebhookDeliveryStateFailed {
+		t.Errorf("got %+v, want a failed attempt", attempt)
+	}
+
+	// Users' webhooks can't target internal addresses.
+	status = http.StatusOK
+	d.Attempts = 0
+	if attempt := deliver(context.Background(), userClient, w, d); attempt.State != db.WebhookDeliveryStatePending || attempt.ResponseStatusCode != 0 {
+		t.Errorf("got %+v, want the connection to be refused", attempt)
+	}
+}
+
+func TestValidateEventTypes(t *testing.T) {
+	if err := ValidateEventTypes([]string{"repo.cloned"}, true); err != nil {
+		t.Error(err)
+	}
+	if err := ValidateEventTypes([]string{"saved_search.results"}, false); err != nil {
+		t.Error(err)
+	}
+	for _, test := range []struct {
+		eventTypes []string
+		site       bool
+	}{
+		{nil, true},
+		{[]string{"x"}, true},
+		{[]string{"repo.cloned"}, false},
+	} {
+		if err := ValidateEventTypes(test.eventTypes, test.site); err == nil {
+			t.Errorf("%v (site %v): got no error", test.eventTypes, test.site)
+		}
+	}
+}
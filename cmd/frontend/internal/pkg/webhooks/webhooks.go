@@ -0,0 +1,101 @@
+// Package webhooks implements outbound webhooks, which notify HTTP endpoints
+// registered by site admins and users of platform events (such as new saved
+// search results and repository clones).
+//
+// Events are enqueued as deliveries in the database (one per subscribed
+// webhook) and delivered by a background worker, which retries failed
+// deliveries with exponential backoff.
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// EventTypes are all the event types that webhooks can subscribe to.
+var EventTypes = []string{
+	api.WebhookEventSavedSearchResults,
+	api.WebhookEventDiscussionCommentCreated,
+	api.WebhookEventRepoCloned,
+	api.WebhookEventRepoCloneFailed,
+	api.WebhookEventExternalServiceSynced,
+}
+
+// userEventTypes are the event types that users' webhooks can subscribe to.
+// The other event types concern the whole site, so only site admins'
+// webhooks can subscribe to them.
+var userEventTypes = map[string]bool{
+	api.WebhookEventSavedSearchResults:       true,
+	api.WebhookEventDiscussionCommentCreated: true,
+}
+
+// ValidateEventTypes returns an error if the event types are not a valid
+// subscription for a site webhook (if site is true) or a user's webhook.
+func ValidateEventTypes(eventTypes []string, site bool) error {
+	if len(eventTypes) == 0 {
+		return errors.New("a webhook must subscribe to at least one event type")
+	}
+	for _, eventType := range eventTypes {
+		known := false
+		for _, t := range EventTypes {
+			if eventType == t {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown webhook event type %q", eventType)
+		}
+		if !site && !userEventTypes[eventType] {
+			return fmt.Errorf("only site admins can subscribe to webhook event type %q", eventType)
+		}
+	}
+	return nil
+}
+
+// ValidateURL returns an error if the URL is not a valid webhook URL.
+func ValidateURL(urlStr string) error {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return errors.Wrap(err, "invalid webhook URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New("webhook URL must use http or https")
+	}
+	if u.Host == "" {
+		return errors.New("webhook URL must have a host")
+	}
+	return nil
+}
+
+// Enqueue enqueues the delivery of the event to each active webhook that
+// subscribes to its type: all site webhooks and the webhooks of the users
+// the event concerns.
+func Enqueue(ctx context.Context, event api.WebhookEnqueueRequest) error {
+	webhooks, err := db.Webhooks.ListForEvent(ctx, event.Event, event.UserIDs)
+	if err != nil {
+		return errors.Wrap(err, "listing webhooks for event")
+	}
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return errors.Wrap(err, "marshaling webhook payload")
+	}
+	for _, w := range webhooks {
+		if _, err := db.WebhookDeliveries.Create(ctx, w.ID, event.Event, payload); err != nil {
+			return errors.Wrapf(err, "enqueueing delivery to webhook %d", w.ID)
+		}
+	}
+	log15.Debug("webhooks: enqueued event", "event", event.Event, "webhooks", len(webhooks))
+	return nil
+}
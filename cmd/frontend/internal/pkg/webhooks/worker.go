@@ -0,0 +1,77 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+const (
+	// pollInterval is how often the worker checks for deliveries that are
+	// due to be attempted.
+	pollInterval = 10 * time.Second
+
+	// batchSize is the maximum number of deliveries attempted per poll.
+	batchSize = 50
+
+	// retention is how long the history of finished deliveries is kept.
+	retention = 30 * 24 * time.Hour
+)
+
+// StartWorker should be invoked only after the DB has been initialized. It
+// starts the background worker which delivers enqueued events to webhooks.
+// Multiple frontend instances may run the worker concurrently, because each
+// dequeued delivery is leased to a single worker.
+//
+// It should be invoked in a separate goroutine.
+func StartWorker() {
+	ctx := context.Background()
+	var lastCleanup time.Time
+	for {
+		for {
+			n, err := deliverBatch(ctx)
+			if err != nil {
+				log15.Error("webhooks: delivering events failed", "error", err)
+			}
+			if err != nil || n < batchSize {
+				break
+			}
+		}
+		if time.Since(lastCleanup) > time.Hour {
+			if err := db.WebhookDeliveries.DeleteOlderThan(ctx, time.Now().Add(-retention)); err != nil {
+				log15.Error("webhooks: deleting old deliveries failed", "error", err)
+			}
+			lastCleanup = time.Now()
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// deliverBatch attempts the deliveries that are due and returns how many it
+// attempted.
+func deliverBatch(ctx context.Context) (int, error) {
+	deliveries, err := db.WebhookDeliveries.Dequeue(ctx, batchSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, d := range deliveries {
+		var attempt db.WebhookDeliveryAttempt
+		w, err := db.Webhooks.GetByID(ctx, d.WebhookID)
+		switch {
+		case err == db.ErrWebhookNotFound:
+			continue // the webhook (and so the delivery) was deleted
+		case err != nil:
+			return 0, err
+		case !w.Active:
+			attempt = db.WebhookDeliveryAttempt{State: db.WebhookDeliveryStateFailed, Error: "webhook is inactive"}
+		default:
+			attempt = deliver(ctx, clientFor(w), w, d)
+		}
+		if err := db.WebhookDeliveries.MarkAttempt(ctx, d.ID, attempt); err != nil {
+			return 0, err
+		}
+	}
+	return len(deliveries), nil
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/pkg/actor"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
@@ -161,13 +162,16 @@ func GetData(r *http.Request, key string, value interface{}) error {
 	return nil
 }
 
-// SetActor sets the actor in the session, or removes it if actor == nil. If no session exists, a
-// new session is created.
+// SetActor sets the actor in the session, or removes it if a == nil. If no session exists, a new
+// session is created.
 //
 // If expiryPeriod is 0, the default expiry period is used.
-func SetActor(w http.ResponseWriter, r *http.Request, actor *actor.Actor, expiryPeriod time.Duration) error {
+//
+// The sign-in (or sign-out) is recorded in the audit log. All auth providers call SetActor, so the
+// request path identifies the auth provider that was used.
+func SetActor(w http.ResponseWriter, r *http.Request, a *actor.Actor, expiryPeriod time.Duration) error {
 	var value *sessionInfo
-	if actor != nil {
+	if a != nil {
 		if expiryPeriod == 0 {
 			if cfgExpiry, err := time.ParseDuration(conf.Get().Critical.AuthSessionExpiry); err == nil {
 				expiryPeriod = cfgExpiry
@@ -175,9 +179,21 @@ func SetActor(w http.ResponseWriter, r *http.Request, actor *actor.Actor, expiry
 				expiryPeriod = defaultExpiryPeriod
 			}
 		}
-		value = &sessionInfo{Actor: actor, ExpiryPeriod: expiryPeriod, LastActive: time.Now()}
+		value = &sessionInfo{Actor: a, ExpiryPeriod: expiryPeriod, LastActive: time.Now()}
+	}
+	if err := SetData(w, r, "actor", value); err != nil {
+		return err
+	}
+
+	details := map[string]string{"path": r.URL.Path}
+	if a != nil {
+		audit.Record(actor.WithActor(r.Context(), a), audit.Event{Action: audit.UserSignIn, TargetType: "User", TargetID: audit.UserID(a.UID), Details: details})
+	} else if prev := actor.FromContext(r.Context()); prev.IsAuthenticated() {
+		// Only record sign-outs of signed-in users (and not, e.g., the second sign-out request of
+		// an SSO logout flow).
+		audit.Record(r.Context(), audit.Event{Action: audit.UserSignOut, TargetType: "User", TargetID: audit.UserID(prev.UID), Details: details})
 	}
-	return SetData(w, r, "actor", value)
+	return nil
 }
 
 func hasSessionCookie(r *http.Request) bool {
@@ -294,8 +310,9 @@ func authenticateByCookie(r *http.Request, w http.ResponseWriter) context.Contex
 			return actor.WithActor(r.Context(), &actor.Actor{})
 		}
 
-		// Check that user still exists.
-		if _, err := db.Users.GetByID(r.Context(), info.Actor.UID); err != nil {
+		// Check that user still exists and is not suspended.
+		user, err := db.Users.GetByID(r.Context(), info.Actor.UID)
+		if err != nil {
 			if errcode.IsNotFound(err) {
 				_ = deleteSession(w, r) // clear the bad value
 			} else {
@@ -305,6 +322,10 @@ func authenticateByCookie(r *http.Request, w http.ResponseWriter) context.Contex
 			}
 			return r.Context() // not authenticated
 		}
+		if user.Suspended {
+			_ = deleteSession(w, r)
+			return r.Context() // not authenticated
+		}
 
 		// Renew session
 		if time.Since(info.LastActive) > 5*time.Minute {
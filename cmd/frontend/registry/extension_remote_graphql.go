@@ -7,6 +7,7 @@ import (
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/app/ui/router"
 	"github.com/sourcegraph/sourcegraph/pkg/registry"
 )
@@ -61,6 +62,10 @@ func (r *registryExtensionRemoteResolver) PublishedAt(context.Context) (*string,
 	return strptr(r.v.PublishedAt.Format(time.RFC3339)), nil
 }
 
+func (r *registryExtensionRemoteResolver) Releases(context.Context, *graphqlutil.ConnectionArgs) (graphqlbackend.RegistryExtensionReleaseConnection, error) {
+	return nil, nil // the release history of remote extensions is not available
+}
+
 func (r *registryExtensionRemoteResolver) URL() string {
 	return router.Extension(r.v.ExtensionID)
 }
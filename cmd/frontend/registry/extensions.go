@@ -165,6 +165,27 @@ var IsRemoteExtensionAllowed = func(extensionID string) bool {
 	return true
 }
 
+// IsMirroringRemoteExtensions reports whether remote extensions are mirrored into the local
+// registry. If so, remote extensions are retrieved from the mirror (with GetMirroredExtension and
+// ListMirroredExtensions) instead of from the remote registry.
+//
+// It can be overridden to use custom logic.
+var IsMirroringRemoteExtensions = func() bool {
+	// By default, remote extensions are not mirrored.
+	return false
+}
+
+var (
+	// GetMirroredExtension gets the remote extension from the mirror in the local registry. The
+	// field is either "uuid" or "extensionID". It must be set if IsMirroringRemoteExtensions can
+	// return true.
+	GetMirroredExtension func(ctx context.Context, field, value string) (*registry.Extension, error)
+
+	// ListMirroredExtensions lists the remote extensions in the mirror in the local registry that
+	// match the query. It must be set if IsMirroringRemoteExtensions can return true.
+	ListMirroredExtensions func(ctx context.Context, query string) ([]*registry.Extension, error)
+)
+
 var mockGetRemoteRegistryExtension func(field, value string) (*registry.Extension, error)
 
 // getRemoteRegistryExtension gets the remote registry extension and rewrites its fields to be from
@@ -174,22 +195,30 @@ func getRemoteRegistryExtension(ctx context.Context, field, value string) (*regi
 		return mockGetRemoteRegistryExtension(field, value)
 	}
 
-	registryURL, err := getRemoteRegistryURL()
-	if registryURL == nil || err != nil {
-		return nil, err
-	}
+	var (
+		x   *registry.Extension
+		err error
+	)
+	if IsMirroringRemoteExtensions() {
+		x, err = GetMirroredExtension(ctx, field, value)
+	} else {
+		var registryURL *url.URL
+		registryURL, err = getRemoteRegistryURL()
+		if registryURL == nil || err != nil {
+			return nil, err
+		}
 
-	var x *registry.Extension
-	switch field {
-	case "uuid":
-		x, err = registry.GetByUUID(ctx, registryURL, value)
-	case "extensionID":
-		x, err = registry.GetByExtensionID(ctx, registryURL, value)
-	default:
-		panic("unexpected field: " + field)
-	}
-	if x != nil {
-		x.RegistryURL = registryURL.String()
+		switch field {
+		case "uuid":
+			x, err = registry.GetByUUID(ctx, registryURL, value)
+		case "extensionID":
+			x, err = registry.GetByExtensionID(ctx, registryURL, value)
+		default:
+			panic("unexpected field: " + field)
+		}
+		if x != nil {
+			x.RegistryURL = registryURL.String()
+		}
 	}
 
 	if x != nil && !IsRemoteExtensionAllowed(x.ExtensionID) {
@@ -211,6 +240,14 @@ var FilterRemoteExtensions = func(extensions []*registry.Extension) []*registry.
 // listRemoteRegistryExtensions lists the remote registry extensions and rewrites their fields to be
 // from the frame-of-reference of this site.
 func listRemoteRegistryExtensions(ctx context.Context, query string) ([]*registry.Extension, error) {
+	if IsMirroringRemoteExtensions() {
+		xs, err := ListMirroredExtensions(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		return FilterRemoteExtensions(xs), nil
+	}
+
 	registryURL, err := getRemoteRegistryURL()
 	if registryURL == nil || err != nil {
 		return nil, err
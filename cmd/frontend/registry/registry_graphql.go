@@ -27,12 +27,13 @@ var ExtensionRegistry extensionRegistryResolver
 // Some methods are only implemented if there is a local extension registry. For these methods, the
 // implementation (if one exists) is set on the XyzFunc struct field.
 type extensionRegistryResolver struct {
-	ViewerPublishersFunc func(context.Context) ([]graphqlbackend.RegistryPublisher, error)
-	PublishersFunc       func(context.Context, *graphqlutil.ConnectionArgs) (graphqlbackend.RegistryPublisherConnection, error)
-	CreateExtensionFunc  func(context.Context, *graphqlbackend.ExtensionRegistryCreateExtensionArgs) (graphqlbackend.ExtensionRegistryMutationResult, error)
-	UpdateExtensionFunc  func(context.Context, *graphqlbackend.ExtensionRegistryUpdateExtensionArgs) (graphqlbackend.ExtensionRegistryMutationResult, error)
-	PublishExtensionFunc func(context.Context, *graphqlbackend.ExtensionRegistryPublishExtensionArgs) (graphqlbackend.ExtensionRegistryMutationResult, error)
-	DeleteExtensionFunc  func(context.Context, *graphqlbackend.ExtensionRegistryDeleteExtensionArgs) (*graphqlbackend.EmptyResponse, error)
+	ViewerPublishersFunc     func(context.Context) ([]graphqlbackend.RegistryPublisher, error)
+	PublishersFunc           func(context.Context, *graphqlutil.ConnectionArgs) (graphqlbackend.RegistryPublisherConnection, error)
+	CreateExtensionFunc      func(context.Context, *graphqlbackend.ExtensionRegistryCreateExtensionArgs) (graphqlbackend.ExtensionRegistryMutationResult, error)
+	UpdateExtensionFunc      func(context.Context, *graphqlbackend.ExtensionRegistryUpdateExtensionArgs) (graphqlbackend.ExtensionRegistryMutationResult, error)
+	PublishExtensionFunc     func(context.Context, *graphqlbackend.ExtensionRegistryPublishExtensionArgs) (graphqlbackend.ExtensionRegistryMutationResult, error)
+	YankExtensionReleaseFunc func(context.Context, *graphqlbackend.ExtensionRegistryYankExtensionReleaseArgs) (*graphqlbackend.EmptyResponse, error)
+	DeleteExtensionFunc      func(context.Context, *graphqlbackend.ExtensionRegistryDeleteExtensionArgs) (*graphqlbackend.EmptyResponse, error)
 }
 
 var errNoLocalExtensionRegistry = errors.New("no local extension registry exists")
@@ -90,6 +91,13 @@ func (r *extensionRegistryResolver) PublishExtension(ctx context.Context, args *
 	return r.PublishExtensionFunc(ctx, args)
 }
 
+func (r *extensionRegistryResolver) YankExtensionRelease(ctx context.Context, args *graphqlbackend.ExtensionRegistryYankExtensionReleaseArgs) (*graphqlbackend.EmptyResponse, error) {
+	if r.YankExtensionReleaseFunc == nil {
+		return nil, errNoLocalExtensionRegistry
+	}
+	return r.YankExtensionReleaseFunc(ctx, args)
+}
+
 func (r *extensionRegistryResolver) DeleteExtension(ctx context.Context, args *graphqlbackend.ExtensionRegistryDeleteExtensionArgs) (*graphqlbackend.EmptyResponse, error) {
 	if r.DeleteExtensionFunc == nil {
 		return nil, errNoLocalExtensionRegistry
@@ -104,7 +112,7 @@ func (*extensionRegistryResolver) LocalExtensionIDPrefix() *string {
 // ImplementsLocalExtensionRegistry reports whether there is an implementation of a local extension
 // registry (which is a Sourcegraph Enterprise feature).
 func (r *extensionRegistryResolver) ImplementsLocalExtensionRegistry() bool {
-	return r.ViewerPublishersFunc != nil && r.PublishersFunc != nil && r.CreateExtensionFunc != nil && r.UpdateExtensionFunc != nil && r.PublishExtensionFunc != nil && r.DeleteExtensionFunc != nil
+	return r.ViewerPublishersFunc != nil && r.PublishersFunc != nil && r.CreateExtensionFunc != nil && r.UpdateExtensionFunc != nil && r.PublishExtensionFunc != nil && r.YankExtensionReleaseFunc != nil && r.DeleteExtensionFunc != nil
 }
 
 func (r *extensionRegistryResolver) FilterRemoteExtensions(ids []string) []string {
@@ -9,14 +9,16 @@ import (
 // DiscussionThread mirrors the underlying discussion_threads field types exactly.
 // It intentionally does not try to e.g. alleviate null fields.
 type DiscussionThread struct {
-	ID           int64
-	AuthorUserID int32
-	Title        string
-	TargetRepo   *DiscussionThreadTargetRepo
-	CreatedAt    time.Time
-	ArchivedAt   *time.Time
-	UpdatedAt    time.Time
-	DeletedAt    *time.Time
+	ID               int64
+	AuthorUserID     int32
+	Title            string
+	TargetRepo       *DiscussionThreadTargetRepo
+	CreatedAt        time.Time
+	ArchivedAt       *time.Time
+	UpdatedAt        time.Time
+	DeletedAt        *time.Time
+	ResolvedAt       *time.Time
+	ResolvedByUserID *int32
 }
 
 // DiscussionThreadTargetRepo mirrors the underlying discussion_threads_target_repo field types exactly.
@@ -56,3 +58,21 @@ type DiscussionComment struct {
 	DeletedAt    *time.Time
 	Reports      []string
 }
+
+// DiscussionCommentReaction mirrors the underlying discussion_comment_reactions field types exactly.
+type DiscussionCommentReaction struct {
+	CommentID int64
+	UserID    int32
+	Reaction  string
+	CreatedAt time.Time
+}
+
+// DiscussionImportedComment mirrors the underlying discussion_imported_comments field types exactly.
+type DiscussionImportedComment struct {
+	CommentID        int64
+	ThreadID         int64
+	ExternalThreadID string
+	ExternalID       string
+	URL              string
+	CreatedAt        time.Time
+}
@@ -61,6 +61,9 @@ type User struct {
 	UpdatedAt   time.Time
 	SiteAdmin   bool
 	Tags        []string
+	// Suspended is whether the user is suspended and therefore can't sign in or use their
+	// existing sessions and access tokens.
+	Suspended bool
 }
 
 type Org struct {
@@ -22,6 +22,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/debugserver"
 	"github.com/sourcegraph/sourcegraph/pkg/env"
+	"github.com/sourcegraph/sourcegraph/pkg/ratelimit"
 	"github.com/sourcegraph/sourcegraph/pkg/tracer"
 )
 
@@ -31,7 +32,9 @@ var (
 
 const port = "3180"
 
-// requestMu ensures we only do one request at a time to prevent tripping abuse detection.
+// requestMu ensures we only do one request at a time to prevent tripping abuse detection. The
+// request budgets (shared with the rest of Sourcegraph, which doesn't wait on them for the
+// requests it sends through github-proxy) limit the rate of requests, but not their concurrency.
 var requestMu sync.Mutex
 
 var rateLimitRemainingGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
@@ -98,6 +101,7 @@ func main() {
 				h2[k] = v
 			}
 		}
+		ratelimit.StripProxyHeaders(h2)
 
 		// Authenticate for higher rate limits.
 		authenticateRequestMu.RLock()
@@ -119,6 +123,12 @@ func main() {
 			Header: h2,
 		}
 
+		budget := ratelimit.DefaultBudgets.Get(ratelimit.RequestBudgetName(req2))
+		if err := budget.Wait(ratelimit.ProxiedRequestContext(r, "github-proxy"), 1); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
 		requestMu.Lock()
 		resp, err := http.DefaultClient.Do(req2)
 		requestMu.Unlock()
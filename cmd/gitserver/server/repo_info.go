@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"os"
 	"path"
@@ -169,3 +170,68 @@ func (s *Server) deleteRepo(repo api.RepoName) error {
 
 	return s.removeRepoDirectory(dir)
 }
+
+func (s *Server) handleRepoRename(w http.ResponseWriter, r *http.Request) {
+	var req protocol.RepoRenameRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := s.renameRepo(req.From, req.To); err != nil {
+		log15.Error("failed to rename repository", "from", req.From, "to", req.To, "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log15.Info("renamed repository", "from", req.From, "to", req.To)
+}
+
+// renameRepo moves the clone of a repository to the directory of its new
+// name, so that it doesn't need to be recloned. It is a no-op if the
+// repository isn't cloned.
+func (s *Server) renameRepo(from, to api.RepoName) error {
+	from, to = protocol.NormalizeRepo(from), protocol.NormalizeRepo(to)
+	fromDir := filepath.Join(s.ReposDir, string(from))
+	toDir := filepath.Join(s.ReposDir, string(to))
+	if fromDir == toDir {
+		return nil
+	}
+
+	// Hold both locks so that we don't race with a clone or another rename.
+	fromLock, ok := s.locker.TryAcquire(fromDir, "renaming")
+	if !ok {
+		return fmt.Errorf("repository %s is locked", from)
+	}
+	defer fromLock.Release()
+	toLock, ok := s.locker.TryAcquire(toDir, "renaming")
+	if !ok {
+		return fmt.Errorf("repository %s is locked", to)
+	}
+	defer toLock.Release()
+
+	if !repoCloned(fromDir) {
+		return nil
+	}
+	if repoCloned(toDir) {
+		return &os.PathError{Op: "renameRepo", Path: toDir, Err: os.ErrExist}
+	}
+
+	// Only new style clones (with a .git directory) can be moved, since old
+	// style clones share their directory with nested repositories.
+	gitDir := filepath.Join(fromDir, ".git")
+	if _, err := os.Stat(gitDir); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(toDir, os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.Rename(gitDir, filepath.Join(toDir, ".git")); err != nil {
+		return err
+	}
+
+	// Best-effort removal of the now empty directory.
+	_ = os.Remove(fromDir)
+
+	return nil
+}
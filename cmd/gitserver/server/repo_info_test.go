@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"reflect"
 	"testing"
 	"time"
@@ -125,3 +126,36 @@ func TestServer_handleRepoInfo(t *testing.T) {
 		}
 	})
 }
+
+func TestServer_renameRepo(t *testing.T) {
+	root, cleanup := tmpDir(t)
+	defer cleanup()
+
+	mkFiles(t, root,
+		"github.com/foo/old/.git/HEAD",
+		"github.com/foo/survivor/.git/HEAD",
+	)
+	s := &Server{ReposDir: root, locker: &RepositoryLocker{}}
+
+	if err := s.renameRepo("github.com/foo/old", "github.com/bar/new"); err != nil {
+		t.Fatal(err)
+	}
+	assertPaths(t, root,
+		"github.com/bar/new/.git/HEAD",
+		"github.com/foo/survivor/.git/HEAD",
+	)
+
+	// Renaming onto an existing clone fails and leaves both in place.
+	if err := s.renameRepo("github.com/bar/new", "github.com/foo/survivor"); !os.IsExist(err) {
+		t.Fatalf("got error %v, want os.ErrExist", err)
+	}
+	assertPaths(t, root,
+		"github.com/bar/new/.git/HEAD",
+		"github.com/foo/survivor/.git/HEAD",
+	)
+
+	// Renaming a repo which isn't cloned is a no-op.
+	if err := s.renameRepo("github.com/foo/missing", "github.com/foo/other"); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -37,6 +37,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/pkg/honey"
 	"github.com/sourcegraph/sourcegraph/pkg/mutablelimiter"
+	"github.com/sourcegraph/sourcegraph/pkg/ratelimit"
 	"github.com/sourcegraph/sourcegraph/pkg/repotrackutil"
 	"github.com/sourcegraph/sourcegraph/pkg/trace"
 	"github.com/sourcegraph/sourcegraph/pkg/vcs/git"
@@ -226,6 +227,7 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/repo", s.handleDeprecatedRepoInfo) // TODO(slimsag): Remove this after 3.3 is released.
 	mux.HandleFunc("/repos", s.handleRepoInfo)
 	mux.HandleFunc("/delete", s.handleRepoDelete)
+	mux.HandleFunc("/rename", s.handleRepoRename)
 	mux.HandleFunc("/repo-update", s.handleRepoUpdate)
 	mux.HandleFunc("/getGitolitePhabricatorMetadata", s.handleGetGitolitePhabricatorMetadata)
 	mux.HandleFunc("/create-commit-from-patch", s.handleCreateCommitFromPatch)
@@ -400,6 +402,7 @@ func (s *Server) handleRepoUpdate(w http.ResponseWriter, r *http.Request) {
 		if err != nil {
 			log15.Warn("error cloning repo", "repo", req.Repo, "err", err)
 			resp.Error = err.Error()
+			resp.ErrorKind = classifyUpdateError(err)
 		}
 	} else {
 		resp.Cloned = true
@@ -428,12 +431,14 @@ func (s *Server) handleRepoUpdate(w http.ResponseWriter, r *http.Request) {
 			// report this error in-band, but still produce a valid response with the
 			// other information.
 			resp.Error = statusErr.Error()
+			resp.ErrorKind = protocol.RepoUpdateErrorOther
 		}
 		// If an error occurred during update, report it but don't actually make
 		// it into an http error; we want the client to get the information cleanly.
 		// An update error "wins" over a status error.
 		if updateErr != nil {
 			resp.Error = updateErr.Error()
+			resp.ErrorKind = classifyUpdateError(updateErr)
 		}
 	}
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
@@ -688,7 +693,7 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 	}
 	defer cancel()
 	if err := s.isCloneable(ctx, url); err != nil {
-		return "", fmt.Errorf("error cloning repo: repo %s (%s) not cloneable: %s", repo, url, err)
+		return "", &notCloneableError{repo: repo, url: url, err: err}
 	}
 
 	// Mark this repo as currently being cloned. We have to check again if someone else isn't already
@@ -720,6 +725,10 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 		ctx, cancel2 := context.WithTimeout(ctx, longGitCommandTimeout)
 		defer cancel2()
 
+		if err := waitCloneBudget(ctx, url); err != nil {
+			return err
+		}
+
 		dstPath := filepath.Join(dir, ".git")
 		overwrite := opts != nil && opts.Overwrite
 		if !overwrite {
@@ -785,7 +794,9 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 
 	if opts != nil && opts.Block {
 		// We are blocking, so use the passed in context.
-		if err := doClone(ctx); err != nil {
+		err := doClone(ctx)
+		enqueueCloneWebhook(repo, url, err)
+		if err != nil {
 			return "", errors.Wrapf(err, "failed to clone %s", repo)
 		}
 		return "", nil
@@ -795,7 +806,9 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 		// Create a new context because this is in a background goroutine.
 		ctx, cancel := s.serverContext()
 		defer cancel()
-		if err := doClone(ctx); err != nil {
+		err := doClone(ctx)
+		enqueueCloneWebhook(repo, url, err)
+		if err != nil {
 			log15.Error("failed to clone repo", "repo", repo, "error", err)
 		}
 	}()
@@ -887,6 +900,20 @@ func scanCRLF(data []byte, atEOF bool) (advance int, token []byte, err error) {
 	return 0, nil, nil
 }
 
+// waitCloneBudget waits on the shared request budget of the code host the
+// remote URL points to (including SSH and SCP-style remotes) and the
+// credential in it, so that clones don't exhaust the rate limit the rest of
+// Sourcegraph relies on. Remotes without a host (e.g. local paths) are not
+// budgeted.
+func waitCloneBudget(ctx context.Context, remoteURL string) error {
+	name := ratelimit.RemoteBudgetName(remoteURL)
+	if name == "" {
+		return nil
+	}
+	ctx = ratelimit.WithConsumer(ctx, "gitserver-clone")
+	return ratelimit.DefaultBudgets.Get(name).Wait(ctx, 1)
+}
+
 // testRepoExists is a test fixture that overrides the return value
 // for isCloneable when it is set.
 var testRepoExists func(ctx context.Context, url string) error
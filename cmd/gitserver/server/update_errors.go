@@ -0,0 +1,89 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/gitserver/protocol"
+)
+
+// notCloneableError is returned by cloneRepo when the remote of a repo can't be listed.
+type notCloneableError struct {
+	repo api.RepoName
+	url  string
+	err  error
+}
+
+func (e *notCloneableError) Error() string {
+	return fmt.Sprintf("error cloning repo: repo %s (%s) not cloneable: %s", e.repo, e.url, e.err)
+}
+
+// The output of git (and of the code hosts it talks to) is the only indication of why a
+// clone or fetch failed, so errors are classified by looking for these messages in it. The
+// messages are specific to the failures that git and the code hosts report, so that other
+// errors that happen to contain words like "timed out" or "not found" aren't misclassified.
+var (
+	timeoutErrorMessages = []string{
+		context.DeadlineExceeded.Error(),
+		"Connection timed out", // connect(2) via curl or ssh
+		"Operation timed out",  // curl (e.g., "Operation timed out after 300000 milliseconds")
+	}
+
+	authErrorMessages = []string{
+		"Authentication failed",
+		"could not read Username",
+		"could not read Password",
+		"terminal prompts disabled",
+		"Permission denied (publickey",
+		"Host key verification failed",
+		"The requested URL returned error: 401",
+		"The requested URL returned error: 403",
+	}
+
+	notFoundErrorMessages = []string{
+		"Repository not found",                                // GitHub, Bitbucket
+		"The project you were looking for could not be found", // GitLab
+		"does not appear to be a git repository",
+		"Could not read from remote repository",
+		"The requested URL returned error: 404",
+	}
+
+	// notFoundErrorPattern matches git's message for a repository that doesn't exist at an HTTP(S) URL.
+	notFoundErrorPattern = regexp.MustCompile(`fatal: repository '[^']*' not found`)
+)
+
+// classifyUpdateError returns the kind of the error of a repo clone or fetch.
+func classifyUpdateError(err error) protocol.RepoUpdateErrorKind {
+	if err == nil {
+		return ""
+	}
+
+	msg := err.Error()
+	switch {
+	case errors.Cause(err) == context.DeadlineExceeded || containsAny(msg, timeoutErrorMessages):
+		return protocol.RepoUpdateErrorTimeout
+	case containsAny(msg, authErrorMessages):
+		return protocol.RepoUpdateErrorAuth
+	case containsAny(msg, notFoundErrorMessages) || notFoundErrorPattern.MatchString(msg):
+		return protocol.RepoUpdateErrorNotCloneable
+	}
+
+	if _, ok := errors.Cause(err).(*notCloneableError); ok {
+		return protocol.RepoUpdateErrorNotCloneable
+	}
+
+	return protocol.RepoUpdateErrorOther
+}
+
+func containsAny(s string, substrs []string) bool {
+	for _, substr := range substrs {
+		if strings.Contains(s, substr) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/gitserver/protocol"
+)
+
+func TestClassifyUpdateError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want protocol.RepoUpdateErrorKind
+	}{
+		{err: nil, want: ""},
+		{err: pkgerrors.Wrap(context.DeadlineExceeded, "repo foo:"), want: protocol.RepoUpdateErrorTimeout},
+		{err: errors.New("fatal: unable to access 'https://example.com/foo/': Failed to connect to example.com port 443: Connection timed out"), want: protocol.RepoUpdateErrorTimeout},
+		{err: errors.New("fatal: Authentication failed for 'https://example.com/foo/'"), want: protocol.RepoUpdateErrorAuth},
+		{err: errors.New("fatal: could not read Username for 'https://github.com': terminal prompts disabled"), want: protocol.RepoUpdateErrorAuth},
+		{err: errors.New("remote: Repository not found.\nfatal: repository 'https://github.com/foo/bar/' not found"), want: protocol.RepoUpdateErrorNotCloneable},
+		{err: &notCloneableError{repo: "foo", url: "https://example.com/foo", err: errors.New("exit status 128")}, want: protocol.RepoUpdateErrorNotCloneable},
+		{err: errors.New("fatal: repository 'https://example.com/foo/' not found"), want: protocol.RepoUpdateErrorNotCloneable},
+		{err: errors.New("remote: The project you were looking for could not be found.\nfatal: Could not read from remote repository."), want: protocol.RepoUpdateErrorNotCloneable},
+		{err: errors.New("fatal: early EOF"), want: protocol.RepoUpdateErrorOther},
+		{err: errors.New("error: git upload-pack: git-pack-objects died: ref not found"), want: protocol.RepoUpdateErrorOther},
+		{err: errors.New("error: hook declined: lock acquisition timed out"), want: protocol.RepoUpdateErrorOther},
+	}
+	for _, test := range tests {
+		if got := classifyUpdateError(test.err); got != test.want {
+			t.Errorf("classifyUpdateError(%v): got %q, want %q", test.err, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package server
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// enqueueCloneWebhook notifies the frontend that the repository was cloned (if
+// cloneErr is nil) or that cloning it failed, so that the event is delivered to
+// the site's outbound webhooks. It returns immediately and does not block.
+func enqueueCloneWebhook(repo api.RepoName, url string, cloneErr error) {
+	if pathErr, ok := cloneErr.(*os.PathError); ok && os.IsExist(pathErr.Err) {
+		return // the repository was already cloned
+	}
+
+	req := api.WebhookEnqueueRequest{Event: api.WebhookEventRepoCloned}
+	payload := struct {
+		Repo  api.RepoName `json:"repo"`
+		Error string       `json:"error,omitempty"`
+	}{Repo: repo}
+	if cloneErr != nil {
+		req.Event = api.WebhookEventRepoCloneFailed
+		// 🚨 SECURITY: The error could include the clone URL, which may contain a sensitive token.
+		payload.Error = newURLRedactor(url).redact(cloneErr.Error())
+	}
+	req.Payload = payload
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := api.InternalClient.WebhooksEnqueue(ctx, req); err != nil {
+			log15.Warn("failed to enqueue webhook event", "event", req.Event, "repo", repo, "error", err)
+		}
+	}()
+}
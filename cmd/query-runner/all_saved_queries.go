@@ -174,10 +174,8 @@ func notifySavedQueryWasCreatedOrUpdated(oldValue, newValue api.SavedQuerySpecAn
 				log15.Error("Failed to send unsubscribed email notification.", "recipient", removedRecipient, "error", err)
 			}
 		}
-		if removedRecipient.slack {
-			if err := slackNotifyUnsubscribed(ctx, removedRecipient, oldValue); err != nil {
-				log15.Error("Failed to send unsubscribed Slack notification.", "recipient", removedRecipient, "error", err)
-			}
+		if err := chatNotify(ctx, removedRecipient, newChatMessage(chatEventDisabled, oldValue.Config)); err != nil {
+			log15.Error("Failed to send unsubscribed chat notification.", "recipient", removedRecipient, "error", err)
 		}
 	}
 	for _, addedRecipient := range addedRecipients {
@@ -186,10 +184,8 @@ func notifySavedQueryWasCreatedOrUpdated(oldValue, newValue api.SavedQuerySpecAn
 				log15.Error("Failed to send subscribed email notification.", "recipient", addedRecipient, "error", err)
 			}
 		}
-		if addedRecipient.slack {
-			if err := slackNotifySubscribed(ctx, addedRecipient, newValue); err != nil {
-				log15.Error("Failed to send subscribed Slack notification.", "recipient", addedRecipient, "error", err)
-			}
+		if err := chatNotify(ctx, addedRecipient, newChatMessage(chatEventEnabled, newValue.Config)); err != nil {
+			log15.Error("Failed to send subscribed chat notification.", "recipient", addedRecipient, "error", err)
 		}
 	}
 	return nil
@@ -223,9 +219,8 @@ func serveTestNotification(w http.ResponseWriter, r *http.Request) {
 			writeError(w, fmt.Errorf("error sending email notifications to %s: %s", recipient.spec, err))
 			return
 		}
-		if err := slackNotify(context.Background(), recipient,
-			fmt.Sprintf(`It worked! This is a test notification for the Sourcegraph saved search <%s|"%s">.`, searchURL(query.Config.Query, utmSourceSlack), query.Config.Description)); err != nil {
-			writeError(w, fmt.Errorf("error sending email notifications to %s: %s", recipient.spec, err))
+		if err := chatNotify(context.Background(), recipient, newChatMessage(chatEventTest, query.Config)); err != nil {
+			writeError(w, fmt.Errorf("error sending chat notifications to %s: %s", recipient.spec, err))
 			return
 		}
 	}
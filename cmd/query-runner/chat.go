@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/schema"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// chatEvent is the kind of a saved search notification that is posted by a chatNotifier.
+type chatEvent string
+
+const (
+	chatEventResults  chatEvent = "results"  // the saved search has new results
+	chatEventEnabled  chatEvent = "enabled"  // notifications for the saved search were enabled
+	chatEventDisabled chatEvent = "disabled" // notifications for the saved search were disabled
+	chatEventTest     chatEvent = "test"     // a test notification requested by the user
+)
+
+// chatMessage is a saved search notification that is posted by a chatNotifier.
+type chatMessage struct {
+	event                  chatEvent
+	description            string // the saved search's description
+	query                  string // the saved search's query
+	searchQuery            string // the query to link to (e.g., the query for only the new results)
+	approximateResultCount string // the number of new results (only for chatEventResults)
+}
+
+func newChatMessage(event chatEvent, query api.ConfigSavedQuery) *chatMessage {
+	return &chatMessage{
+		event:       event,
+		description: query.Description,
+		query:       query.Query,
+		searchQuery: query.Query,
+	}
+}
+
+// chatFormat describes the markup of a chat service's messages.
+type chatFormat struct {
+	bold func(text string) string
+	link func(url, text string) string
+}
+
+// text returns the human-readable text of the message, which links to the search results on
+// Sourcegraph. The service is the name of the chat service, as shown to users (e.g., "Slack").
+func (m *chatMessage) text(service, url string, f chatFormat) string {
+	link := f.link(url, `"`+m.description+`"`)
+	switch m.event {
+	case chatEventResults:
+		plural := ""
+		if m.approximateResultCount != "1" {
+			plural = "s"
+		}
+		return fmt.Sprintf("%s new result%s found for saved search %s", f.bold(m.approximateResultCount), plural, link)
+	case chatEventEnabled:
+		return fmt.Sprintf("%s notifications enabled for the saved search %s. Notifications will be sent here when new results are available.", service, link)
+	case chatEventDisabled:
+		return fmt.Sprintf("%s notifications for the saved search %s disabled.", service, link)
+	case chatEventTest:
+		return fmt.Sprintf("It worked! This is a test notification for the Sourcegraph saved search %s.", link)
+	}
+	return ""
+}
+
+// A chatNotifier posts saved search notifications to a chat service (or another HTTP endpoint)
+// that is configured in the settings of the recipient.
+type chatNotifier interface {
+	// name is the name of the notifier, which is used in log messages and in the names of the
+	// logged events (e.g., "Slack" for "SavedSearchSlackNotificationSent").
+	name() string
+
+	// enabled reports whether the recipient receives notifications from this notifier.
+	enabled(r *recipient) bool
+
+	// post posts the message to the endpoint configured in the recipient's settings.
+	post(ctx context.Context, r *recipient, settings *schema.Settings, msg *chatMessage) error
+}
+
+// chatNotifiers are all chat notifiers. Each saved search selects the notifiers it uses (with its
+// notifySlack, notifyMSTeams and notifyWebhook properties).
+var chatNotifiers = []chatNotifier{
+	slackNotifier{},
+	msTeamsNotifier{},
+	genericWebhookNotifier{},
+}
+
+// chatNotify posts the message to the recipient with each of the chat notifiers that the
+// recipient has enabled.
+func chatNotify(ctx context.Context, r *recipient, msg *chatMessage) error {
+	var (
+		settings *schema.Settings
+		errs     error
+	)
+	for _, notifier := range chatNotifiers {
+		if !notifier.enabled(r) {
+			continue
+		}
+		if settings == nil {
+			var err error
+			settings, _, err = api.InternalClient.SettingsGetForSubject(ctx, r.subject())
+			if err != nil {
+				return err
+			}
+		}
+		if err := notifier.post(ctx, r, settings, msg); err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "%s notification", notifier.name()))
+			continue
+		}
+		if msg.event != chatEventTest {
+			// TODO(Dan): find all users in the recipient list and log events for all of them
+			logEvent(0, "", "SavedSearch"+notifier.name()+"NotificationSent", string(msg.event))
+		}
+	}
+	return errs
+}
+
+func (n *notifier) chatNotify(ctx context.Context) {
+	msg := newChatMessage(chatEventResults, n.query)
+	msg.searchQuery = n.newQuery
+	msg.approximateResultCount = n.results.Data.Search.Results.ApproximateResultCount
+	for _, recipient := range n.recipients {
+		if err := chatNotify(ctx, recipient, msg); err != nil {
+			log15.Error("Failed to post chat notification message.", "recipient", recipient, "error", err)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestChatMessageText(t *testing.T) {
+	msg := &chatMessage{
+		event:                  chatEventResults,
+		description:            "my search",
+		approximateResultCount: "3",
+	}
+	const u = "https://example.com/search?q=x"
+	tests := map[string]struct {
+		format chatFormat
+		want   string
+	}{
+		"slack":   {slackFormat, `*3* new results found for saved search <https://example.com/search?q=x|"my search">`},
+		"msteams": {msTeamsFormat, `**3** new results found for saved search ["my search"](https://example.com/search?q=x)`},
+		"plain":   {plainFormat, `3 new results found for saved search "my search" (https://example.com/search?q=x)`},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := msg.text("Slack", u, test.format); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		msg := &chatMessage{event: chatEventEnabled, description: "my search"}
+		want := `Microsoft Teams notifications enabled for the saved search ["my search"](https://example.com/search?q=x). Notifications will be sent here when new results are available.`
+		if got := msg.text("Microsoft Teams", u, msTeamsFormat); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("singular", func(t *testing.T) {
+		msg := &chatMessage{event: chatEventResults, description: "d", approximateResultCount: "1"}
+		want := `1 new result found for saved search "d" (https://example.com/search?q=x)`
+		if got := msg.text("Webhook", u, plainFormat); got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+}
+
+func TestGenericWebhookBody(t *testing.T) {
+	data := &genericWebhookData{
+		Event:       "results",
+		Text:        `say "hi"`,
+		Description: "d",
+		Query:       "q",
+		URL:         "https://example.com",
+	}
+
+	t.Run("default", func(t *testing.T) {
+		body, err := genericWebhookBody("", data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"event":"results","text":"say \"hi\"","description":"d","query":"q","url":"https://example.com"}`
+		if string(body) != want {
+			t.Errorf("got %s, want %s", body, want)
+		}
+	})
+
+	t.Run("template", func(t *testing.T) {
+		body, err := genericWebhookBody(`{"content": {{json .Text}}, "event": "{{.Event}}"}`, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := `{"content": "say \"hi\"", "event": "results"}`
+		if string(body) != want {
+			t.Errorf("got %s, want %s", body, want)
+		}
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		if _, err := genericWebhookBody(`{{.NoSuchField}}`, data); err == nil {
+			t.Error("got nil error, want error")
+		}
+	})
+}
+
+func TestPostJSON(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request to a loopback address was sent")
+	}))
+	defer ts.Close()
+
+	if err := postJSON(context.Background(), ts.URL, nil, []byte("{}")); err == nil {
+		t.Error("got no error for a request to a loopback address")
+	}
+	err := postJSON(context.Background(), "https://chat.example.com", map[string]string{"authorization": "x"}, []byte("{}"))
+	if err == nil || !strings.Contains(err.Error(), "can't be set") {
+		t.Errorf("got error %v, want the Authorization header to be disallowed", err)
+	}
+}
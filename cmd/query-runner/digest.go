@@ -0,0 +1,391 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/txemail"
+	"github.com/sourcegraph/sourcegraph/pkg/txemail/txtypes"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// maxDigestTopResults is the maximum number of results that are listed for each saved search in a
+// digest email.
+const maxDigestTopResults = 3
+
+// digestEntry summarizes the new results of a saved search since the digest was started.
+type digestEntry struct {
+	description string
+	ownership   string
+	searchQuery string // the query for the new results of the first run included in the digest
+
+	resultCount       int
+	resultCountIsMore bool     // whether resultCount is a lower bound (e.g., "30+")
+	topResults        []string // descriptions of the most recent results, newest first
+}
+
+// digest accumulates the new results of a user's saved searches until it is due to be sent.
+type digest struct {
+	userID  int32
+	due     time.Time
+	entries map[string]*digestEntry // keyed on the saved search's description and query
+	order   []string                // entries keys in the order they were added
+}
+
+// digestAccumulator batches new saved search results for users who receive digest emails.
+type digestAccumulator struct {
+	mu      sync.Mutex
+	digests map[int32]*digest // keyed on user ID
+
+	// persist is whether the pending digests are saved in the database (through the frontend's
+	// internal API), so that results that have not yet been sent aren't lost if query-runner is
+	// restarted.
+	persist bool
+}
+
+var digests = &digestAccumulator{digests: map[int32]*digest{}, persist: true}
+
+// digestDue returns the time at which a digest of the given frequency that is started at now is
+// due to be sent: the start of the next hour (for hourly digests) or the next day in UTC (for daily
+// digests).
+func digestDue(now time.Time, frequency string) time.Time {
+	now = now.UTC()
+	if frequency == api.SavedSearchEmailFrequencyDaily {
+		return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	}
+	return now.Truncate(time.Hour).Add(time.Hour)
+}
+
+// add adds the new results of a saved search to the user's digest, starting a new digest if the
+// user has none.
+func (a *digestAccumulator) add(ctx context.Context, now time.Time, userID int32, frequency string, n *notifier, ownership string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	d, ok := a.digests[userID]
+	if !ok {
+		d = &digest{
+			userID:  userID,
+			due:     digestDue(now, frequency),
+			entries: map[string]*digestEntry{},
+		}
+		a.digests[userID] = d
+	}
+
+	key := n.query.Description + "\x00" + n.query.Query
+	e, ok := d.entries[key]
+	if !ok {
+		e = &digestEntry{
+			description: n.query.Description,
+			ownership:   ownership,
+			searchQuery: n.newQuery,
+		}
+		d.entries[key] = e
+		d.order = append(d.order, key)
+	}
+
+	count, isMore := parseApproximateResultCount(n.results.Data.Search.Results.ApproximateResultCount)
+	e.resultCount += count
+	e.resultCountIsMore = e.resultCountIsMore || isMore
+
+	var topResults []string
+	for _, result := range n.results.Data.Search.Results.Results {
+		if len(topResults) == maxDigestTopResults {
+			break
+		}
+		if s := describeResult(result); s != "" {
+			topResults = append(topResults, s)
+		}
+	}
+	e.topResults = append(topResults, e.topResults...)
+	if len(e.topResults) > maxDigestTopResults {
+		e.topResults = e.topResults[:maxDigestTopResults]
+	}
+
+	// Save the digest while holding the lock, so that concurrent saves of the same user's digest
+	// can't overwrite it with an older version.
+	if a.persist {
+		if err := saveDigest(ctx, d); err != nil {
+			log15.Error("Failed to save saved search digest (new results will be lost if query-runner is restarted).", "userID", userID, "error", err)
+		}
+	}
+}
+
+// load loads the pending digests that were saved before query-runner was (re)started, retrying
+// until the frontend is reachable. Digests that were started in the meantime are kept.
+func (a *digestAccumulator) load(ctx context.Context) {
+	for {
+		saved, err := api.InternalClient.SavedSearchDigestsList(ctx)
+		if err == nil {
+			a.mu.Lock()
+			defer a.mu.Unlock()
+			for _, s := range saved {
+				if _, ok := a.digests[s.UserID]; ok {
+					continue
+				}
+				d, err := parseDigest(s)
+				if err != nil {
+					log15.Error("Ignoring invalid saved search digest.", "userID", s.UserID, "error", err)
+					continue
+				}
+				a.digests[s.UserID] = d
+			}
+			return
+		}
+		log15.Error("Failed to load saved search digests (retrying).", "error", err)
+
+		select {
+		case <-time.After(5 * time.Second):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// takeDue removes and returns the digests that are due to be sent at now.
+func (a *digestAccumulator) takeDue(now time.Time) []*digest {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var due []*digest
+	for userID, d := range a.digests {
+		if !now.Before(d.due) {
+			due = append(due, d)
+			delete(a.digests, userID)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].userID < due[j].userID })
+	return due
+}
+
+// digestContents is the JSON representation of a digest's entries, which is saved in the database.
+type digestContents struct {
+	Entries []digestEntryContents `json:"entries"` // in the order they were added
+}
+
+type digestEntryContents struct {
+	Key               string   `json:"key"`
+	Description       string   `json:"description"`
+	Ownership         string   `json:"ownership"`
+	SearchQuery       string   `json:"searchQuery"`
+	ResultCount       int      `json:"resultCount"`
+	ResultCountIsMore bool     `json:"resultCountIsMore"`
+	TopResults        []string `json:"topResults"`
+}
+
+// saveDigest saves the digest in the database, replacing the user's previously saved digest.
+func saveDigest(ctx context.Context, d *digest) error {
+	contents, err := d.contents()
+	if err != nil {
+		return err
+	}
+	return api.InternalClient.SavedSearchDigestsSave(ctx, &api.SavedSearchDigest{
+		UserID:   d.userID,
+		DueAt:    d.due,
+		Contents: contents,
+	})
+}
+
+// contents returns the JSON representation of the digest's entries.
+func (d *digest) contents() (json.RawMessage, error) {
+	var c digestContents
+	for _, key := range d.order {
+		e := d.entries[key]
+		c.Entries = append(c.Entries, digestEntryContents{
+			Key:               key,
+			Description:       e.description,
+			Ownership:         e.ownership,
+			SearchQuery:       e.searchQuery,
+			ResultCount:       e.resultCount,
+			ResultCountIsMore: e.resultCountIsMore,
+			TopResults:        e.topResults,
+		})
+	}
+	return json.Marshal(c)
+}
+
+// parseDigest returns the digest that was saved by saveDigest.
+func parseDigest(s *api.SavedSearchDigest) (*digest, error) {
+	var c digestContents
+	if err := json.Unmarshal(s.Contents, &c); err != nil {
+		return nil, err
+	}
+	d := &digest{
+		userID:  s.UserID,
+		due:     s.DueAt,
+		entries: make(map[string]*digestEntry, len(c.Entries)),
+	}
+	for _, e := range c.Entries {
+		if _, ok := d.entries[e.Key]; ok {
+			continue
+		}
+		d.entries[e.Key] = &digestEntry{
+			description:       e.Description,
+			ownership:         e.Ownership,
+			searchQuery:       e.SearchQuery,
+			resultCount:       e.ResultCount,
+			resultCountIsMore: e.ResultCountIsMore,
+			topResults:        e.TopResults,
+		}
+		d.order = append(d.order, e.Key)
+	}
+	return d, nil
+}
+
+// parseApproximateResultCount parses a search's approximate result count, such as "3" or "30+".
+func parseApproximateResultCount(s string) (count int, isMore bool) {
+	isMore = strings.HasSuffix(s, "+")
+	count, _ = strconv.Atoi(strings.TrimSuffix(s, "+"))
+	return count, isMore
+}
+
+func formatResultCount(count int, isMore bool) string {
+	if isMore {
+		return strconv.Itoa(count) + "+"
+	}
+	return strconv.Itoa(count)
+}
+
+// describeResult returns a short description of a search result (from gqlSearchQuery), such as
+// "github.com/foo/bar › dir/file.go" for a file match, or the empty string if the result's type is
+// unknown.
+func describeResult(result interface{}) string {
+	m, _ := result.(map[string]interface{})
+	switch typeName, _ := m["__typename"].(string); typeName {
+	case "FileMatch":
+		resource, _ := m["resource"].(string)
+		u, err := url.Parse(resource) // example: git://github.com/foo/bar?rev#dir/file.go
+		if err != nil || u.Host == "" {
+			return ""
+		}
+		if u.Fragment == "" {
+			return u.Host + u.Path
+		}
+		return u.Host + u.Path + " › " + u.Fragment
+	case "CommitSearchResult":
+		commit, _ := m["commit"].(map[string]interface{})
+		repository, _ := commit["repository"].(map[string]interface{})
+		repoName, _ := repository["name"].(string)
+		oid, _ := commit["abbreviatedOID"].(string)
+		message, _ := commit["message"].(string)
+		if i := strings.Index(message, "\n"); i != -1 {
+			message = message[:i]
+		}
+		return fmt.Sprintf("%s %s: %s", repoName, oid, message)
+	}
+	return ""
+}
+
+// runDigests periodically sends the digest emails that are due.
+func runDigests(ctx context.Context) {
+	for {
+		for _, d := range digests.takeDue(time.Now()) {
+			if err := sendDigest(ctx, d); err != nil {
+				log15.Error("Failed to send saved search digest email.", "userID", d.userID, "error", err)
+			}
+			// The saved digest is deleted even if sending failed, as it would be if it were only kept in
+			// memory. A new digest that was started after d was taken is due later and is kept.
+			if digests.persist {
+				if err := api.InternalClient.SavedSearchDigestsDelete(ctx, d.userID, d.due); err != nil {
+					log15.Error("Failed to delete saved search digest.", "userID", d.userID, "error", err)
+				}
+			}
+		}
+
+		select {
+		case <-time.After(time.Minute):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+type digestSearch struct {
+	Description            string
+	Ownership              string
+	URL                    string
+	ApproximateResultCount string
+	PluralResults          string
+	TopResults             []string
+}
+
+func sendDigest(ctx context.Context, d *digest) error {
+	if err := canSendEmail(ctx); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	var (
+		searches         []digestSearch
+		totalCount       int
+		totalCountIsMore bool
+	)
+	for _, key := range d.order {
+		e := d.entries[key]
+		totalCount += e.resultCount
+		totalCountIsMore = totalCountIsMore || e.resultCountIsMore
+		searches = append(searches, digestSearch{
+			Description:            e.description,
+			Ownership:              e.ownership,
+			URL:                    searchURL(e.searchQuery, utmSourceEmailDigest),
+			ApproximateResultCount: formatResultCount(e.resultCount, e.resultCountIsMore),
+			PluralResults:          pluralResults(e.resultCount, e.resultCountIsMore),
+			TopResults:             e.topResults,
+		})
+	}
+	pluralSearches := ""
+	if len(searches) != 1 {
+		pluralSearches = "es"
+	}
+
+	return sendEmail(ctx, d.userID, "digest", digestEmailTemplates, nil, struct {
+		ApproximateResultCount string
+		PluralResults          string
+		SearchCount            int
+		PluralSearches         string
+		Searches               []digestSearch
+	}{
+		ApproximateResultCount: formatResultCount(totalCount, totalCountIsMore),
+		PluralResults:          pluralResults(totalCount, totalCountIsMore),
+		SearchCount:            len(searches),
+		PluralSearches:         pluralSearches,
+		Searches:               searches,
+	})
+}
+
+func pluralResults(count int, isMore bool) string {
+	if count == 1 && !isMore {
+		return ""
+	}
+	return "s"
+}
+
+var digestEmailTemplates = txemail.MustValidate(txtypes.Templates{
+	Subject: `[{{.ApproximateResultCount}} new result{{.PluralResults}}] Saved search digest`,
+	Text: `
+{{.ApproximateResultCount}} new search result{{.PluralResults}} found for {{.SearchCount}} saved search{{.PluralSearches}}:
+{{range .Searches}}
+  "{{.Description}}" ({{.Ownership}} saved search): {{.ApproximateResultCount}} new result{{.PluralResults}}
+{{range .TopResults}}    - {{.}}
+{{end}}    View the new result{{.PluralResults}} on Sourcegraph: {{.URL}}
+{{end}}`,
+	HTML: `
+<p><strong>{{.ApproximateResultCount}}</strong> new search result{{.PluralResults}} found for {{.SearchCount}} saved search{{.PluralSearches}}:</p>
+
+{{range .Searches}}
+<p style="padding-left: 16px">&quot;{{.Description}}&quot; ({{.Ownership}} saved search): <strong>{{.ApproximateResultCount}}</strong> new result{{.PluralResults}}</p>
+{{if .TopResults}}<ul style="padding-left: 32px">{{range .TopResults}}<li><code>{{.}}</code></li>{{end}}</ul>{{end}}
+<p style="padding-left: 16px"><a href="{{.URL}}">View the new result{{.PluralResults}} on Sourcegraph</a></p>
+{{end}}
+`,
+})
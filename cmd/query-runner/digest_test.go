@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+func TestDigestDue(t *testing.T) {
+	now := time.Date(2019, 1, 31, 13, 45, 0, 0, time.UTC)
+	tests := map[string]time.Time{
+		api.SavedSearchEmailFrequencyHourly: time.Date(2019, 1, 31, 14, 0, 0, 0, time.UTC),
+		api.SavedSearchEmailFrequencyDaily:  time.Date(2019, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for frequency, want := range tests {
+		t.Run(frequency, func(t *testing.T) {
+			if got := digestDue(now, frequency); !got.Equal(want) {
+				t.Errorf("got %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestDigestAccumulator(t *testing.T) {
+	newNotifier := func(description, newQuery, approximateResultCount string, results ...interface{}) *notifier {
+		n := &notifier{
+			query:    api.ConfigSavedQuery{Description: description, Query: "q"},
+			newQuery: newQuery,
+			results:  &gqlSearchResponse{},
+		}
+		n.results.Data.Search.Results.ApproximateResultCount = approximateResultCount
+		n.results.Data.Search.Results.Results = results
+		return n
+	}
+	fileMatch := func(path string) interface{} {
+		return map[string]interface{}{
+			"__typename": "FileMatch",
+			"resource":   "git://github.com/foo/bar?master#" + path,
+		}
+	}
+
+	ctx := context.Background()
+	a := &digestAccumulator{digests: map[int32]*digest{}}
+	now := time.Date(2019, 1, 31, 13, 45, 0, 0, time.UTC)
+	a.add(ctx, now, 1, api.SavedSearchEmailFrequencyHourly, newNotifier("d1", "q after:1", "2", fileMatch("a"), fileMatch("b")), "your")
+	a.add(ctx, now, 1, api.SavedSearchEmailFrequencyHourly, newNotifier("d2", "q after:2", "30+"), "your")
+	a.add(ctx, now.Add(time.Minute), 1, api.SavedSearchEmailFrequencyHourly, newNotifier("d1", "q after:3", "2", fileMatch("c"), fileMatch("d")), "your")
+	a.add(ctx, now, 2, api.SavedSearchEmailFrequencyDaily, newNotifier("d1", "q after:1", "1", fileMatch("a")), "the")
+
+	if due := a.takeDue(now.Add(10 * time.Minute)); len(due) != 0 {
+		t.Errorf("got %d due digests, want none", len(due))
+	}
+
+	due := a.takeDue(now.Add(15 * time.Minute))
+	if len(due) != 1 || due[0].userID != 1 {
+		t.Fatalf("got due digests %+v, want only user 1's digest", due)
+	}
+	d := due[0]
+	if want := []string{"d1\x00q", "d2\x00q"}; !reflect.DeepEqual(d.order, want) {
+		t.Errorf("got order %q, want %q", d.order, want)
+	}
+	if want := (&digestEntry{
+		description: "d1",
+		ownership:   "your",
+		searchQuery: "q after:1",
+		resultCount: 4,
+		topResults: []string{
+			"github.com/foo/bar › c",
+			"github.com/foo/bar › d",
+			"github.com/foo/bar › a",
+		},
+	}); !reflect.DeepEqual(d.entries["d1\x00q"], want) {
+		t.Errorf("got entry %+v, want %+v", d.entries["d1\x00q"], want)
+	}
+	if e := d.entries["d2\x00q"]; e.resultCount != 30 || !e.resultCountIsMore {
+		t.Errorf("got result count %d (isMore %v), want 30+", e.resultCount, e.resultCountIsMore)
+	}
+
+	// The daily digest is still pending.
+	if due := a.takeDue(now.Add(24 * time.Hour)); len(due) != 1 || due[0].userID != 2 {
+		t.Errorf("got due digests %+v, want only user 2's digest", due)
+	}
+}
+
+func TestParseDigest(t *testing.T) {
+	want := &digest{
+		userID: 1,
+		due:    time.Date(2019, 1, 31, 14, 0, 0, 0, time.UTC),
+		entries: map[string]*digestEntry{
+			"d2\x00q": {description: "d2", ownership: "the", searchQuery: "q after:2", resultCount: 30, resultCountIsMore: true},
+			"d1\x00q": {description: "d1", ownership: "your", searchQuery: "q after:1", resultCount: 2, topResults: []string{"a", "b"}},
+		},
+		order: []string{"d2\x00q", "d1\x00q"},
+	}
+	contents, err := want.contents()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := parseDigest(&api.SavedSearchDigest{UserID: want.userID, DueAt: want.due, Contents: contents})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestDescribeResult(t *testing.T) {
+	tests := map[string]struct {
+		result interface{}
+		want   string
+	}{
+		"file match": {
+			result: map[string]interface{}{
+				"__typename": "FileMatch",
+				"resource":   "git://github.com/foo/bar?master#dir/file.go",
+			},
+			want: "github.com/foo/bar › dir/file.go",
+		},
+		"commit": {
+			result: map[string]interface{}{
+				"__typename": "CommitSearchResult",
+				"commit": map[string]interface{}{
+					"repository":     map[string]interface{}{"name": "github.com/foo/bar"},
+					"abbreviatedOID": "abc1234",
+					"message":        "Fix bug\n\nLonger description.",
+				},
+			},
+			want: "github.com/foo/bar abc1234: Fix bug",
+		},
+		"unknown": {
+			result: map[string]interface{}{"__typename": "Repository"},
+			want:   "",
+		},
+		"invalid": {
+			result: "x",
+			want:   "",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := describeResult(test.result); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
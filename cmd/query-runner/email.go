@@ -34,7 +34,22 @@ func (n *notifier) emailNotify(ctx context.Context) {
 		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 		defer cancel()
 
+		// Users who muted (or unsubscribed from) this saved search's email notifications by replying
+		// to one are not notified.
+		muted := map[int32]bool{}
+		mutedUserIDs, err := api.InternalClient.SavedQueriesMutedUsers(ctx, n.spec)
+		if err != nil {
+			log15.Error("Failed to list users who muted saved search email notifications.", "error", err)
+		}
+		for _, userID := range mutedUserIDs {
+			muted[userID] = true
+		}
+
 		for _, recipient := range n.recipients {
+			if !recipient.email || muted[recipient.spec.userID] {
+				continue
+			}
+
 			ownership := "the" // example: "new search results have been found for {{.Ownership}} saved search"
 			if n.spec.Subject.User != nil && *n.spec.Subject.User == recipient.spec.userID {
 				ownership = "your"
@@ -43,17 +58,31 @@ func (n *notifier) emailNotify(ctx context.Context) {
 				ownership = "your organization's"
 			}
 
+			// Users who receive digest emails are notified of the new results when their digest is
+			// due.
+			frequency, err := api.InternalClient.UsersGetEmailFrequency(ctx, recipient.spec.userID)
+			if err != nil {
+				log15.Error("Failed to get saved search email frequency (sending email immediately).", "userID", recipient.spec.userID, "error", err)
+				frequency = api.SavedSearchEmailFrequencyImmediate
+			}
+			if frequency != api.SavedSearchEmailFrequencyImmediate {
+				digests.add(ctx, time.Now(), recipient.spec.userID, frequency, n, ownership)
+				continue
+			}
+
 			plural := ""
 			if n.results.Data.Search.Results.ApproximateResultCount != "1" {
 				plural = "s"
 			}
-			if err := sendEmail(ctx, recipient.spec.userID, "results", newSearchResultsEmailTemplates, struct {
+			replyTo := savedSearchReplyTo(ctx, recipient.spec.userID, n.spec)
+			if err := sendEmail(ctx, recipient.spec.userID, "results", newSearchResultsEmailTemplates, replyTo, struct {
 				URL                    string
 				Description            string
 				Query                  string
 				ApproximateResultCount string
 				Ownership              string
 				PluralResults          string
+				CanReply               bool
 			}{
 				URL:                    searchURL(n.newQuery, utmSourceEmail),
 				Description:            n.query.Description,
@@ -61,6 +90,7 @@ func (n *notifier) emailNotify(ctx context.Context) {
 				ApproximateResultCount: n.results.Data.Search.Results.ApproximateResultCount,
 				Ownership:              ownership,
 				PluralResults:          plural,
+				CanReply:               replyTo != nil,
 			}); err != nil {
 				log15.Error("Failed to send email notification for new saved search results.", "userID", recipient.spec.userID, "error", err)
 			}
@@ -76,14 +106,18 @@ var newSearchResultsEmailTemplates = txemail.MustValidate(txtypes.Templates{
   "{{.Description}}"
 
 View the new result{{.PluralResults}} on Sourcegraph: {{.URL}}
-`,
+{{if .CanReply}}
+Reply "mute 1w" to mute these notifications for a week, or "unsubscribe" to stop receiving them.
+{{end}}`,
 	HTML: `
 <strong>{{.ApproximateResultCount}}</strong> new search result{{.PluralResults}} found for {{.Ownership}} saved search:
 
 <p style="padding-left: 16px">&quot;{{.Description}}&quot;</p>
 
 <p><a href="{{.URL}}">View the new result{{.PluralResults}} on Sourcegraph</a></p>
-`,
+{{if .CanReply}}
+<p style="font-size: small; color: #666;">Reply "mute 1w" to mute these notifications for a week, or "unsubscribe" to stop receiving them.</p>
+{{end}}`,
 })
 
 func emailNotifySubscribeUnsubscribe(ctx context.Context, recipient *recipient, query api.SavedQuerySpecAndConfig, template txtypes.Templates) error {
@@ -116,16 +150,36 @@ func emailNotifySubscribeUnsubscribe(ctx context.Context, recipient *recipient,
 		ownership = "your organization's"
 	}
 
-	return sendEmail(ctx, recipient.spec.userID, eventType, template, struct {
+	// Replies to the subscribed email can mute or unsubscribe from the saved search.
+	var replyTo *string
+	if template == notifySubscribedTemplate {
+		replyTo = savedSearchReplyTo(ctx, recipient.spec.userID, query.Spec)
+	}
+
+	return sendEmail(ctx, recipient.spec.userID, eventType, template, replyTo, struct {
 		Ownership   string
 		Description string
+		CanReply    bool
 	}{
 		Ownership:   ownership,
 		Description: query.Config.Description,
+		CanReply:    replyTo != nil,
 	})
 }
 
-func sendEmail(ctx context.Context, userID int32, eventType string, template txtypes.Templates, data interface{}) error {
+// savedSearchReplyTo returns the "Reply-To" address of the user's email notifications for the saved
+// search, or nil if replies are not supported. Replying to the address with a command (such as
+// "mute 1w" or "unsubscribe") updates the user's subscription.
+func savedSearchReplyTo(ctx context.Context, userID int32, spec api.SavedQueryIDSpec) *string {
+	replyTo, err := api.InternalClient.SavedQueriesReplyTo(ctx, userID, spec)
+	if err != nil {
+		log15.Error("Failed to get saved search email Reply-To address (replies will not be supported).", "userID", userID, "error", err)
+		return nil
+	}
+	return replyTo
+}
+
+func sendEmail(ctx context.Context, userID int32, eventType string, template txtypes.Templates, replyTo *string, data interface{}) error {
 	email, err := api.InternalClient.UserEmailsGetEmail(ctx, userID)
 	if err != nil {
 		return errors.Wrap(err, fmt.Sprintf("InternalClient.UserEmailsGetEmail for userID=%d", userID))
@@ -136,6 +190,7 @@ func sendEmail(ctx context.Context, userID int32, eventType string, template txt
 
 	if err := api.InternalClient.SendEmail(ctx, txtypes.Message{
 		To:       []string{*email},
+		ReplyTo:  replyTo,
 		Template: template,
 		Data:     data,
 	}); err != nil {
@@ -153,14 +208,18 @@ You are now receiving notifications for {{.Ownership}} saved search:
   "{{.Description}}"
 
 When new search results become available, we will notify you.
-`,
+{{if .CanReply}}
+Reply "mute 1w" to mute these notifications for a week, or "unsubscribe" to stop receiving them.
+{{end}}`,
 	HTML: `
 <p>You are now receiving notifications for {{.Ownership}} saved search:</p>
 
 <p style="padding-left: 16px">&quot;{{.Description}}&quot;</p>
 
 <p>When new search results become available, we will notify you.</p>
-`,
+{{if .CanReply}}
+<p style="font-size: small; color: #666;">Reply "mute 1w" to mute these notifications for a week, or "unsubscribe" to stop receiving them.</p>
+{{end}}`,
 })
 
 var notifyUnsubscribedTemplate = txemail.MustValidate(txtypes.Templates{
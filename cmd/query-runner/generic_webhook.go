@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"text/template"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// genericWebhookNotifier POSTs saved search notifications to the URL in the "notifications.webhook"
+// setting. The request body is the JSON-encoded genericWebhookData, or the result of executing the
+// setting's bodyTemplate (a Go text/template) on it.
+type genericWebhookNotifier struct{}
+
+func (genericWebhookNotifier) name() string { return "Webhook" }
+
+func (genericWebhookNotifier) enabled(r *recipient) bool { return r.webhook }
+
+var plainFormat = chatFormat{
+	bold: func(text string) string { return text },
+	link: func(url, text string) string { return fmt.Sprintf("%s (%s)", text, url) },
+}
+
+// genericWebhookData is the data that is JSON-encoded as the default request body of a generic
+// webhook notification, and the data that a custom body template is executed on.
+type genericWebhookData struct {
+	Event                  string `json:"event"`
+	Text                   string `json:"text"`
+	Description            string `json:"description"`
+	Query                  string `json:"query"`
+	URL                    string `json:"url"`
+	ApproximateResultCount string `json:"approximateResultCount,omitempty"`
+}
+
+var bodyTemplateFuncs = template.FuncMap{
+	// json returns the JSON encoding of v, for use in templates that produce JSON (e.g.,
+	// `{"text": {{json .Text}}}`).
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+// genericWebhookBody returns the request body of a generic webhook notification.
+func genericWebhookBody(bodyTemplate string, data *genericWebhookData) ([]byte, error) {
+	if bodyTemplate == "" {
+		return json.Marshal(data)
+	}
+	tmpl, err := template.New("bodyTemplate").Funcs(bodyTemplateFuncs).Parse(bodyTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing notifications.webhook bodyTemplate")
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, errors.Wrap(err, "executing notifications.webhook bodyTemplate")
+	}
+	return buf.Bytes(), nil
+}
+
+func (genericWebhookNotifier) post(ctx context.Context, r *recipient, settings *schema.Settings, msg *chatMessage) error {
+	if settings.NotificationsWebhook == nil || settings.NotificationsWebhook.Url == "" {
+		return fmt.Errorf("unable to send webhook notification because recipient (%s) has no notification webhook URL configured", r.spec)
+	}
+
+	url := searchURL(msg.searchQuery, utmSourceWebhook)
+	body, err := genericWebhookBody(settings.NotificationsWebhook.BodyTemplate, &genericWebhookData{
+		Event:                  string(msg.event),
+		Text:                   msg.text("Webhook", url, plainFormat),
+		Description:            msg.description,
+		Query:                  msg.query,
+		URL:                    url,
+		ApproximateResultCount: msg.approximateResultCount,
+	})
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, settings.NotificationsWebhook.Url, settings.NotificationsWebhook.Headers, body)
+}
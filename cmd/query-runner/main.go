@@ -53,6 +53,11 @@ func main() {
 	ctx := context.Background()
 
 	go func() {
+		// Load the pending digests before running saved searches, so that their new results are
+		// added to the digests instead of replacing them.
+		digests.load(ctx)
+		go runDigests(ctx)
+
 		err := executor.run(ctx)
 		if err != nil {
 			log15.Error("executor: failed to run due to error", "error", err)
@@ -130,7 +135,7 @@ func (e *executorT) run(ctx context.Context) error {
 // runQuery runs the given query if an appropriate amount of time has elapsed
 // since it last ran.
 func (e *executorT) runQuery(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSavedQuery) error {
-	if !query.Notify && !query.NotifySlack {
+	if !query.Notify && !query.NotifySlack && !query.NotifyMSTeams && !query.NotifyWebhook {
 		// No need to run this query because there will be nobody to notify.
 		return nil
 	}
@@ -289,9 +294,12 @@ func notify(ctx context.Context, spec api.SavedQueryIDSpec, query api.ConfigSave
 		recipients: recipients,
 	}
 
-	// Send Slack and email notifications.
-	n.slackNotify(ctx)
+	// Send chat (Slack, Microsoft Teams, and webhook) and email notifications.
+	n.chatNotify(ctx)
 	n.emailNotify(ctx)
+	if err := n.webhookNotify(ctx); err != nil {
+		log15.Error("Failed to enqueue webhook event.", "description", query.Description, "error", err)
+	}
 	return nil
 }
 
@@ -304,8 +312,10 @@ type notifier struct {
 }
 
 const (
-	utmSourceEmail = "saved-search-email"
-	utmSourceSlack = "saved-search-slack"
+	utmSourceEmail       = "saved-search-email"
+	utmSourceEmailDigest = "saved-search-email-digest"
+	utmSourceSlack       = "saved-search-slack"
+	utmSourceMSTeams     = "saved-search-msteams"
 )
 
 func searchURL(query, utmSource string) string {
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/httpcli"
+	"github.com/sourcegraph/sourcegraph/schema"
+	"golang.org/x/net/context/ctxhttp"
+)
+
+// chatHTTPClient is the HTTP client used to post Microsoft Teams and generic webhook notifications.
+// Their URLs are set by users in their own settings, so it refuses to connect to internal addresses
+// (query-runner runs inside the cluster, next to services that users must not be able to reach).
+var chatHTTPClient = httpcli.NewExternalClient(30 * time.Second)
+
+// disallowedHeaders are the HTTP request headers that the headers of the "notifications.webhook"
+// setting may not set, because they would change where the request is routed or let users send
+// requests with credentials that they don't control.
+var disallowedHeaders = map[string]bool{
+	"Host":                true,
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+}
+
+// msTeamsNotifier posts saved search notifications to the Microsoft Teams incoming webhook in the
+// "notifications.msteams" setting.
+type msTeamsNotifier struct{}
+
+func (msTeamsNotifier) name() string { return "MSTeams" }
+
+func (msTeamsNotifier) enabled(r *recipient) bool { return r.msteams }
+
+var msTeamsFormat = chatFormat{
+	bold: func(text string) string { return "**" + text + "**" },
+	link: func(url, text string) string { return fmt.Sprintf("[%s](%s)", text, url) },
+}
+
+// msTeamsMessageCard is a Microsoft Teams message card (the legacy actionable message card format
+// that Teams incoming webhooks accept).
+type msTeamsMessageCard struct {
+	Type       string `json:"@type"`
+	Context    string `json:"@context"`
+	Summary    string `json:"summary"`
+	Text       string `json:"text"`
+	ThemeColor string `json:"themeColor,omitempty"`
+}
+
+func (msTeamsNotifier) post(ctx context.Context, r *recipient, settings *schema.Settings, msg *chatMessage) error {
+	if settings.NotificationsMsteams == nil || settings.NotificationsMsteams.WebhookURL == "" {
+		return fmt.Errorf("unable to send Microsoft Teams notification because recipient (%s) has no Microsoft Teams webhook URL configured", r.spec)
+	}
+
+	card := msTeamsMessageCard{
+		Type:       "MessageCard",
+		Context:    "http://schema.org/extensions",
+		Summary:    fmt.Sprintf("Sourcegraph saved search %q", msg.description),
+		Text:       msg.text("Microsoft Teams", searchURL(msg.searchQuery, utmSourceMSTeams), msTeamsFormat),
+		ThemeColor: "0078D7",
+	}
+	body, err := json.Marshal(card)
+	if err != nil {
+		return err
+	}
+	return postJSON(ctx, settings.NotificationsMsteams.WebhookURL, nil, body)
+}
+
+// postJSON POSTs the JSON body to the URL with the additional HTTP request headers.
+func postJSON(ctx context.Context, url string, headers map[string]string, body []byte) error {
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		if disallowedHeaders[http.CanonicalHeaderKey(k)] {
+			return errors.Errorf("the HTTP request header %q can't be set in notification settings", k)
+		}
+		req.Header.Set(k, v)
+	}
+	resp, err := ctxhttp.Do(ctx, chatHTTPClient, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// The response body is not included, because the error is shown to the user who configured
+		// the URL (e.g., when sending a test notification).
+		return errors.Errorf("POST %s failed with HTTP status %d", url, resp.StatusCode)
+	}
+	return nil
+}
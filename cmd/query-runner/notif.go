@@ -23,13 +23,15 @@ func (r recipientSpec) String() string {
 // recipient describes a recipient of a saved search notification and the type of notifications
 // they're configured to receive.
 type recipient struct {
-	spec  recipientSpec // the recipient's identity
-	email bool          // send an email to the recipient
-	slack bool          // post a Slack message to the recipient
+	spec    recipientSpec // the recipient's identity
+	email   bool          // send an email to the recipient
+	slack   bool          // post a Slack message to the recipient
+	msteams bool          // post a Microsoft Teams message to the recipient
+	webhook bool          // post to the recipient's notification webhook
 }
 
 func (r *recipient) String() string {
-	return fmt.Sprintf("{%s email:%v slack:%v}", r.spec, r.email, r.slack)
+	return fmt.Sprintf("{%s email:%v slack:%v msteams:%v webhook:%v}", r.spec, r.email, r.slack, r.msteams, r.webhook)
 }
 
 func (r recipient) subject() api.SettingsSubject {
@@ -48,9 +50,11 @@ func getNotificationRecipients(ctx context.Context, spec api.SavedQueryIDSpec, q
 	switch {
 	case spec.Subject.User != nil:
 		recipients.add(recipient{
-			spec:  recipientSpec{userID: *spec.Subject.User},
-			email: query.Notify,
-			slack: query.NotifySlack,
+			spec:    recipientSpec{userID: *spec.Subject.User},
+			email:   query.Notify,
+			slack:   query.NotifySlack,
+			msteams: query.NotifyMSTeams,
+			webhook: query.NotifyWebhook,
 		})
 
 	case spec.Subject.Org != nil:
@@ -69,8 +73,10 @@ func getNotificationRecipients(ctx context.Context, spec api.SavedQueryIDSpec, q
 		}
 
 		recipients.add(recipient{
-			spec:  recipientSpec{orgID: *spec.Subject.Org},
-			slack: query.NotifySlack,
+			spec:    recipientSpec{orgID: *spec.Subject.Org},
+			slack:   query.NotifySlack,
+			msteams: query.NotifyMSTeams,
+			webhook: query.NotifyWebhook,
 		})
 	}
 
@@ -87,6 +93,8 @@ func (rs *recipients) add(r recipient) {
 			// Merge into existing recipient.
 			r2.email = r2.email || r.email
 			r2.slack = r2.slack || r.slack
+			r2.msteams = r2.msteams || r.msteams
+			r2.webhook = r2.webhook || r.webhook
 			return
 		}
 	}
@@ -120,17 +128,21 @@ func diffNotificationRecipients(old, new recipients) (removed, added recipients)
 			return nil, nil
 		}
 		removed = &recipient{
-			spec:  spec,
-			email: old.email && !new.email,
-			slack: old.slack && !new.slack,
+			spec:    spec,
+			email:   old.email && !new.email,
+			slack:   old.slack && !new.slack,
+			msteams: old.msteams && !new.msteams,
+			webhook: old.webhook && !new.webhook,
 		}
 		if *removed == empty {
 			removed = nil
 		}
 		added = &recipient{
-			spec:  spec,
-			email: new.email && !old.email,
-			slack: new.slack && !old.slack,
+			spec:    spec,
+			email:   new.email && !old.email,
+			slack:   new.slack && !old.slack,
+			msteams: new.msteams && !old.msteams,
+			webhook: new.webhook && !old.webhook,
 		}
 		if *added == empty {
 			added = nil
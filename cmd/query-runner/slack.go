@@ -4,70 +4,26 @@ import (
 	"context"
 	"fmt"
 
-	log15 "gopkg.in/inconshreveable/log15.v2"
-
-	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/slack"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
-func (n *notifier) slackNotify(ctx context.Context) {
-	plural := ""
-	if n.results.Data.Search.Results.ApproximateResultCount != "1" {
-		plural = "s"
-	}
+// slackNotifier posts saved search notifications to the Slack incoming webhook in the
+// "notifications.slack" setting.
+type slackNotifier struct{}
 
-	text := fmt.Sprintf(`*%s* new result%s found for saved search <%s|"%s">`,
-		n.results.Data.Search.Results.ApproximateResultCount,
-		plural,
-		searchURL(n.newQuery, utmSourceSlack),
-		n.query.Description,
-	)
-	for _, recipient := range n.recipients {
-		if err := slackNotify(ctx, recipient, text); err != nil {
-			log15.Error("Failed to post Slack notification message.", "recipient", recipient, "text", text, "error", err)
-		}
-	}
-	// TODO(Dan): find all users in the recipient list and log events for all of them
-	logEvent(0, "", "SavedSearchSlackNotificationSent", "results")
-}
+func (slackNotifier) name() string { return "Slack" }
 
-func slackNotifySubscribed(ctx context.Context, recipient *recipient, query api.SavedQuerySpecAndConfig) error {
-	text := fmt.Sprintf(`Slack notifications enabled for the saved search <%s|"%s">. Notifications will be sent here when new results are available.`,
-		searchURL(query.Config.Query, utmSourceSlack),
-		query.Config.Description,
-	)
-	if err := slackNotify(ctx, recipient, text); err != nil {
-		return err
-	}
-	// TODO(Dan): find all users in the recipient list and log events for all of them
-	logEvent(0, "", "SavedSearchSlackNotificationSent", "enabled")
-	return nil
-}
+func (slackNotifier) enabled(r *recipient) bool { return r.slack }
 
-func slackNotifyUnsubscribed(ctx context.Context, recipient *recipient, query api.SavedQuerySpecAndConfig) error {
-	text := fmt.Sprintf(`Slack notifications for the saved search <%s|"%s"> disabled.`,
-		searchURL(query.Config.Query, utmSourceSlack),
-		query.Config.Description,
-	)
-	if err := slackNotify(ctx, recipient, text); err != nil {
-		return err
-	}
-	// TODO(Dan): find all users in the recipient list and log events for all of them
-	logEvent(0, "", "SavedSearchSlackNotificationSent", "disabled")
-	return nil
+var slackFormat = chatFormat{
+	bold: func(text string) string { return "*" + text + "*" },
+	link: func(url, text string) string { return fmt.Sprintf("<%s|%s>", url, text) },
 }
 
-func slackNotify(ctx context.Context, recipient *recipient, text string) error {
-	if !recipient.slack {
-		return nil
-	}
-
-	settings, _, err := api.InternalClient.SettingsGetForSubject(ctx, recipient.subject())
-	if err != nil {
-		return err
-	}
+func (slackNotifier) post(ctx context.Context, r *recipient, settings *schema.Settings, msg *chatMessage) error {
 	if settings.NotificationsSlack == nil || settings.NotificationsSlack.WebhookURL == "" {
-		return fmt.Errorf("unable to send Slack notification because recipient (%s) has no Slack webhook URL configured", recipient.spec)
+		return fmt.Errorf("unable to send Slack notification because recipient (%s) has no Slack webhook URL configured", r.spec)
 	}
 
 	payload := &slack.Payload{
@@ -75,7 +31,7 @@ func slackNotify(ctx context.Context, recipient *recipient, text string) error {
 		IconEmoji:   ":mag:",
 		UnfurlLinks: false,
 		UnfurlMedia: false,
-		Text:        text,
+		Text:        msg.text("Slack", searchURL(msg.searchQuery, utmSourceSlack), slackFormat),
 	}
 	client := slack.New(settings.NotificationsSlack.WebhookURL, true)
 	return slack.Post(payload, client.WebhookURL)
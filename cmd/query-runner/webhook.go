@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+)
+
+const utmSourceWebhook = "saved-search-webhook"
+
+// webhookNotify enqueues a saved_search.results event, which is delivered to the site's webhooks
+// and to the webhooks of the saved search's owner (or, for an organization's saved search, of the
+// organization's members).
+func (n *notifier) webhookNotify(ctx context.Context) error {
+	var userIDs []int32
+	switch {
+	case n.spec.Subject.User != nil:
+		userIDs = []int32{*n.spec.Subject.User}
+	case n.spec.Subject.Org != nil:
+		orgMembers, err := api.InternalClient.OrgsListUsers(ctx, *n.spec.Subject.Org)
+		if err != nil {
+			return errors.Wrap(err, "OrgsListUsers")
+		}
+		userIDs = orgMembers
+	}
+
+	return api.InternalClient.WebhooksEnqueue(ctx, api.WebhookEnqueueRequest{
+		Event:   api.WebhookEventSavedSearchResults,
+		UserIDs: userIDs,
+		Payload: struct {
+			Key         string `json:"key"`
+			UserID      *int32 `json:"userID,omitempty"`
+			OrgID       *int32 `json:"orgID,omitempty"`
+			Description string `json:"description"`
+			Query       string `json:"query"`
+			ResultCount string `json:"approximateResultCount"`
+			URL         string `json:"url"`
+		}{
+			Key:         n.spec.Key,
+			UserID:      n.spec.Subject.User,
+			OrgID:       n.spec.Subject.Org,
+			Description: n.query.Description,
+			Query:       n.query.Query,
+			ResultCount: n.results.Data.Search.Results.ApproximateResultCount,
+			URL:         searchURL(n.newQuery, utmSourceWebhook),
+		},
+	})
+}
@@ -72,6 +72,8 @@ func main() {
 			m.UpsertRepos,
 			m.ListExternalServices,
 			m.UpsertExternalServices,
+			m.ListScheduledUpdates,
+			m.UpsertScheduledUpdates,
 		} {
 			om.MustRegister(prometheus.DefaultRegisterer)
 		}
@@ -106,6 +108,7 @@ func main() {
 			"GITHUB",
 			"GITLAB",
 			"BITBUCKETSERVER",
+			"PLUGIN",
 			"OTHER",
 		)
 		migrations = append(migrations,
@@ -119,6 +122,11 @@ func main() {
 		}
 	}
 
+	// Restore the update schedule before any repos are synced into it.
+	if err := repos.Scheduler.Persist(ctx, store); err != nil {
+		log15.Error("failed to restore repo update schedule", "error", err)
+	}
+
 	newSyncerEnabled := make(map[string]bool, len(kinds))
 	for _, kind := range kinds {
 		newSyncerEnabled[kind] = true
@@ -133,6 +141,7 @@ func main() {
 		"GITLAB",
 		"GITOLITE",
 		"PHABRICATOR",
+		"PLUGIN",
 		"OTHER",
 	} {
 		if newSyncerEnabled[kind] {
@@ -156,6 +165,8 @@ func main() {
 			go repos.RunGitoliteRepositorySyncWorker(ctx)
 		case "PHABRICATOR":
 			go repos.RunPhabricatorRepositorySyncWorker(ctx, store)
+		case "PLUGIN":
+			log15.Warn("Plugin external service kind only supported with SRC_SYNCER_ENABLED=true")
 		case "OTHER":
 			log15.Warn("Other external service kind only supported with SRC_SYNCER_ENABLED=true")
 		default:
@@ -187,9 +198,16 @@ func main() {
 					log15.Debug("syncer.sync", "diff.deleted", diff.Deleted.Names())
 				}
 
+				if len(diff.Renamed) > 0 {
+					log15.Debug("syncer.sync", "diff.renamed", diff.Renamed)
+					repos.HandleRenames(ctx, diff.Renamed)
+				}
+
 				if !conf.Get().DisableAutoGitUpdates {
 					repos.Scheduler.Update(diff.Repos()...)
 				}
+
+				repos.EnqueueSyncedWebhook(ctx, kinds, diff)
 			}
 		}()
 	}
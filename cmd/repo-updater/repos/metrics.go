@@ -102,6 +102,12 @@ var (
 		Name:      "sched_error",
 		Help:      "Incremented each time we encounter an error updating a repository.",
 	})
+	schedUpdateFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "src",
+		Subsystem: "repoupdater",
+		Name:      "sched_update_failures_total",
+		Help:      "Total number of failed repository updates, by kind of error.",
+	}, []string{"kind"})
 	schedLoops = promauto.NewCounter(prometheus.CounterOpts{
 		Namespace: "src",
 		Subsystem: "repoupdater",
@@ -142,6 +142,8 @@ type StoreMetrics struct {
 	ListRepos              *OperationMetrics
 	UpsertExternalServices *OperationMetrics
 	ListExternalServices   *OperationMetrics
+	UpsertScheduledUpdates *OperationMetrics
+	ListScheduledUpdates   *OperationMetrics
 }
 
 // NewStoreMetrics returns StoreMetrics that need to be registered
@@ -268,6 +270,46 @@ func NewStoreMetrics() StoreMetrics {
 				Help:      "Total number of errors when listing external_services",
 			}, []string{}),
 		},
+		UpsertScheduledUpdates: &OperationMetrics{
+			Duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Namespace: "src",
+				Subsystem: "repoupdater",
+				Name:      "store_upsert_scheduled_updates_duration_seconds",
+				Help:      "Time spent upserting scheduled repo updates",
+			}, []string{}),
+			Count: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: "src",
+				Subsystem: "repoupdater",
+				Name:      "store_upsert_scheduled_updates_total",
+				Help:      "Total number of upserted scheduled repo updates",
+			}, []string{}),
+			Errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: "src",
+				Subsystem: "repoupdater",
+				Name:      "store_upsert_scheduled_updates_errors_total",
+				Help:      "Total number of errors when upserting scheduled repo updates",
+			}, []string{}),
+		},
+		ListScheduledUpdates: &OperationMetrics{
+			Duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+				Namespace: "src",
+				Subsystem: "repoupdater",
+				Name:      "store_list_scheduled_updates_duration_seconds",
+				Help:      "Time spent listing scheduled repo updates",
+			}, []string{}),
+			Count: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: "src",
+				Subsystem: "repoupdater",
+				Name:      "store_list_scheduled_updates_total",
+				Help:      "Total number of listed scheduled repo updates",
+			}, []string{}),
+			Errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+				Namespace: "src",
+				Subsystem: "repoupdater",
+				Name:      "store_list_scheduled_updates_errors_total",
+				Help:      "Total number of errors when listing scheduled repo updates",
+			}, []string{}),
+		},
 	}
 }
 
@@ -433,6 +475,44 @@ func (o *ObservedStore) UpsertRepos(ctx context.Context, repos ...*Repo) (err er
 	return o.store.UpsertRepos(ctx, repos...)
 }
 
+// ListScheduledUpdates calls into the inner Store and registers the observed results.
+func (o *ObservedStore) ListScheduledUpdates(ctx context.Context) (us []*ScheduledUpdate, err error) {
+	tr, ctx := o.trace(ctx, "Store.ListScheduledUpdates")
+
+	defer func(began time.Time) {
+		secs := time.Since(began).Seconds()
+		count := float64(len(us))
+
+		o.metrics.ListScheduledUpdates.Observe(secs, count, &err)
+		log(o.log, "store.list-scheduled-updates", &err, "count", len(us))
+
+		tr.LogFields(otlog.Int("count", len(us)))
+		tr.SetError(err)
+		tr.Finish()
+	}(time.Now())
+
+	return o.store.ListScheduledUpdates(ctx)
+}
+
+// UpsertScheduledUpdates calls into the inner Store and registers the observed results.
+func (o *ObservedStore) UpsertScheduledUpdates(ctx context.Context, updates ...*ScheduledUpdate) (err error) {
+	tr, ctx := o.trace(ctx, "Store.UpsertScheduledUpdates")
+	tr.LogFields(otlog.Int("count", len(updates)))
+
+	defer func(began time.Time) {
+		secs := time.Since(began).Seconds()
+		count := float64(len(updates))
+
+		o.metrics.UpsertScheduledUpdates.Observe(secs, count, &err)
+		log(o.log, "store.upsert-scheduled-updates", &err, "count", len(updates))
+
+		tr.SetError(err)
+		tr.Finish()
+	}(time.Now())
+
+	return o.store.UpsertScheduledUpdates(ctx, updates...)
+}
+
 func (o *ObservedStore) trace(ctx context.Context, family string) (*trace.Trace, context.Context) {
 	txctx := o.txctx
 	if txctx == nil {
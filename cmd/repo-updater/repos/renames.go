@@ -0,0 +1,33 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/gitserver"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// HandleRenames propagates the renames of a Diff to gitserver and the frontend: clones are moved to
+// the new names of their repos and saved searches referring to the previous names are rewritten.
+// Redirects from the previous names are recorded by the database itself when a repo is renamed.
+//
+// Failures are only logged, since a clone which couldn't be moved is recloned under the new name.
+func HandleRenames(ctx context.Context, renamed map[string]string) {
+	if len(renamed) == 0 {
+		return
+	}
+
+	names := make(map[api.RepoName]api.RepoName, len(renamed))
+	for from, to := range renamed {
+		names[api.RepoName(from)] = api.RepoName(to)
+
+		if err := gitserver.DefaultClient.Rename(ctx, api.RepoName(from), api.RepoName(to)); err != nil {
+			log15.Warn("failed to move clone of renamed repo", "from", from, "to", to, "error", err)
+		}
+	}
+
+	if err := api.InternalClient.ReposRenamed(ctx, names); err != nil {
+		log15.Warn("failed to update references to renamed repos", "error", err)
+	}
+}
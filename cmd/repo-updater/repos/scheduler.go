@@ -93,6 +93,10 @@ const (
 // then the next update will be scheduled 6 hours from then.
 // This heuristic is simple to compute and has nice backoff properties.
 //
+// Repos whose updates fail are instead backed off exponentially, starting at twice minDelay and
+// doubling with every consecutive failure up to maxDelay, so that repos which were deleted upstream
+// or whose credentials were revoked don't keep being fetched like healthy ones.
+//
 // When it is time for a repo to update, the scheduler inserts the repo into a queue.
 //
 // A worker continuously dequeues repos and sends updates to gitserver, but its concurrency
@@ -135,12 +139,63 @@ func newUpdateScheduler() *updateScheduler {
 			notifyEnqueue: make(chan struct{}, notifyChanBuffer),
 		},
 		schedule: &schedule{
-			index:  make(map[uint32]*scheduledRepoUpdate),
-			wakeup: make(chan struct{}, notifyChanBuffer),
+			index:    make(map[uint32]*scheduledRepoUpdate),
+			dirty:    make(map[uint32]bool),
+			restored: make(map[uint32]*ScheduledUpdate),
+			wakeup:   make(chan struct{}, notifyChanBuffer),
 		},
 	}
 }
 
+// persistInterval is how often changes to the schedule are persisted.
+var persistInterval = 30 * time.Second
+
+// Persist restores the schedule persisted in the given store and starts persisting changes to it
+// until ctx is done. It must be called before any repos are added to the scheduler, so that
+// restarts of repo-updater don't reset the update interval of every repo and enqueue all of them
+// at once.
+func (s *updateScheduler) Persist(ctx context.Context, store Store) error {
+	updates, err := store.ListScheduledUpdates(ctx)
+	if err != nil {
+		return err
+	}
+
+	s.schedule.mu.Lock()
+	for _, u := range updates {
+		s.schedule.restored[u.RepoID] = u
+	}
+	s.schedule.mu.Unlock()
+
+	log15.Info("restored repo update schedule", "count", len(updates))
+
+	go s.runPersistLoop(ctx, store)
+	return nil
+}
+
+// runPersistLoop periodically persists the changed entries of the schedule to the given store.
+func (s *updateScheduler) runPersistLoop(ctx context.Context, store Store) {
+	ticker := time.NewTicker(persistInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		updates := s.schedule.takeDirty()
+		if len(updates) == 0 {
+			continue
+		}
+
+		if err := store.UpsertScheduledUpdates(ctx, updates...); err != nil {
+			log15.Error("failed to persist repo update schedule", "count", len(updates), "error", err)
+			s.schedule.markDirty(updates)
+		}
+	}
+}
+
 // runScheduleLoop starts the loop that schedules updates by enqueuing them into the updateQueue.
 func (s *updateScheduler) runScheduleLoop(ctx context.Context) {
 	for {
@@ -170,6 +225,7 @@ func (s *updateScheduler) runSchedule() {
 		schedAutoFetch.Inc()
 		s.updateQueue.enqueue(repoUpdate.Repo, priorityLow)
 		repoUpdate.Due = timeNow().Add(repoUpdate.Interval)
+		s.schedule.dirty[repoUpdate.Repo.ID] = true
 		heap.Fix(s.schedule, 0)
 	}
 }
@@ -208,7 +264,23 @@ func (s *updateScheduler) runUpdateLoop(ctx context.Context) {
 					schedError.Inc()
 					log15.Warn("error requesting repo update", "uri", repo.Name, "err", err)
 				}
-				if resp != nil && resp.LastFetched != nil && resp.LastChanged != nil {
+
+				switch {
+				case err != nil && ctx.Err() == context.Canceled:
+					// The scheduler is stopping, which isn't a failure of the repo.
+				case err != nil:
+					kind := gitserverprotocol.RepoUpdateErrorOther
+					if e, ok := err.(interface{ Timeout() bool }); ok && e.Timeout() {
+						kind = gitserverprotocol.RepoUpdateErrorTimeout
+					}
+					s.schedule.updateFailed(repo, kind, err.Error())
+				case resp != nil && resp.Error != "":
+					kind := resp.ErrorKind
+					if kind == "" {
+						kind = gitserverprotocol.RepoUpdateErrorOther
+					}
+					s.schedule.updateFailed(repo, kind, resp.Error)
+				case resp != nil && resp.LastFetched != nil && resp.LastChanged != nil:
 					// This is the heuristic that is described in the updateScheduler documentation.
 					// Update that documentation if you update this logic.
 					interval := resp.LastFetched.Sub(*resp.LastChanged) / 2
@@ -260,8 +332,13 @@ func (s *updateScheduler) Update(rs ...*Repo) {
 func (s *updateScheduler) upsert(r *Repo) {
 	repo := configuredRepo2FromRepo(r)
 
-	updated := s.schedule.upsert(repo)
-	log15.Debug("scheduler.schedule.upserted", "repo", r.Name, "updated", updated)
+	updated, restored := s.schedule.upsert(repo)
+	log15.Debug("scheduler.schedule.upserted", "repo", r.Name, "updated", updated, "restored", restored)
+
+	if restored {
+		// The repo was scheduled before repo-updater restarted, so it's updated when it's due.
+		return
+	}
 
 	updated = s.updateQueue.enqueue(repo, priorityLow)
 	log15.Debug("scheduler.updateQueue.enqueued", "repo", r.Name, "updated", updated)
@@ -413,6 +490,7 @@ func (s *updateScheduler) ScheduleInfo(id uint32) *protocol.RepoUpdateSchedulerI
 			Total:           len(s.schedule.index),
 			IntervalSeconds: int(update.Interval / time.Second),
 			Due:             update.Due,
+			LastError:       update.LastError,
 		}
 	}
 	s.schedule.mu.Unlock()
@@ -430,6 +508,38 @@ func (s *updateScheduler) ScheduleInfo(id uint32) *protocol.RepoUpdateSchedulerI
 	return &result
 }
 
+// FailingRepos returns the repos in the schedule whose last update failed, ordered by their number
+// of consecutive failures.
+func (s *updateScheduler) FailingRepos() *protocol.FailingReposResult {
+	var result protocol.FailingReposResult
+
+	s.schedule.mu.Lock()
+	for _, update := range s.schedule.heap {
+		if update.Failures == 0 {
+			continue
+		}
+		result.Repos = append(result.Repos, &protocol.FailingRepo{
+			ID:        update.Repo.ID,
+			Name:      update.Repo.Name,
+			Failures:  update.Failures,
+			ErrorKind: update.ErrorKind,
+			LastError: update.LastError,
+			Due:       update.Due,
+		})
+	}
+	s.schedule.mu.Unlock()
+
+	sort.Slice(result.Repos, func(i, j int) bool {
+		a, b := result.Repos[i], result.Repos[j]
+		if a.Failures != b.Failures {
+			return a.Failures > b.Failures
+		}
+		return a.Name < b.Name
+	})
+
+	return &result
+}
+
 // updateQueue is a priority queue of repos to update.
 // A repo can't have more than one location in the queue.
 type updateQueue struct {
@@ -607,6 +717,9 @@ type schedule struct {
 	heap  []*scheduledRepoUpdate // min heap of scheduledRepoUpdates based on their due time.
 	index map[uint32]*scheduledRepoUpdate
 
+	dirty    map[uint32]bool             // the repos whose updates changed since they were last persisted
+	restored map[uint32]*ScheduledUpdate // the persisted updates of repos which aren't scheduled yet
+
 	// timer sends a value on the wakeup channel when it is time
 	timer  *time.Timer
 	wakeup chan struct{}
@@ -614,14 +727,18 @@ type schedule struct {
 
 // scheduledRepoUpdate is the update schedule for a single repo.
 type scheduledRepoUpdate struct {
-	Repo     *configuredRepo2 // the repo to update
-	Interval time.Duration    // how regularly the repo is updated
-	Due      time.Time        // the next time that the repo will be enqueued for a update
-	Index    int              `json:"-"` // the index in the heap
-}
-
-// upsert inserts or updates a repo in the schedule.
-func (s *schedule) upsert(repo *configuredRepo2) (updated bool) {
+	Repo      *configuredRepo2 // the repo to update
+	Interval  time.Duration    // how regularly the repo is updated
+	Due       time.Time        // the next time that the repo will be enqueued for a update
+	LastError string           // the error of the last update of the repo, if it failed
+	ErrorKind string           // the kind of LastError, as classified by gitserver
+	Failures  int              // the number of consecutive failed updates of the repo
+	Index     int              `json:"-"` // the index in the heap
+}
+
+// upsert inserts or updates a repo in the schedule. A repo which isn't in the schedule yet is
+// inserted with its persisted update, if there is one, in which case restored is true.
+func (s *schedule) upsert(repo *configuredRepo2) (updated, restored bool) {
 	if repo.ID == 0 {
 		panic("repo.id is zero")
 	}
@@ -631,21 +748,40 @@ func (s *schedule) upsert(repo *configuredRepo2) (updated bool) {
 
 	if update := s.index[repo.ID]; update != nil {
 		update.Repo = repo
-		return true
+		return true, false
 	}
 
-	heap.Push(s, &scheduledRepoUpdate{
+	update := &scheduledRepoUpdate{
 		Repo:     repo,
 		Interval: minDelay,
 		Due:      timeNow().Add(minDelay),
-	})
+	}
+
+	if r := s.restored[repo.ID]; r != nil {
+		delete(s.restored, repo.ID)
+		update.Interval = clampInterval(r.Interval)
+		update.Due = r.Due
+		update.LastError = r.LastError
+		update.ErrorKind = r.LastErrorKind
+		update.Failures = r.Failures
+		// Don't wait longer than the interval for repos whose updates were due
+		// further in the future, e.g. because the interval limits were lowered.
+		if due := timeNow().Add(update.Interval); update.Due.After(due) {
+			update.Due = due
+		}
+		restored = true
+	}
+
+	heap.Push(s, update)
+	s.dirty[repo.ID] = true
 
 	s.rescheduleTimer()
 
-	return false
+	return false, restored
 }
 
-// updateInterval updates the update interval of a repo in the schedule.
+// updateInterval updates the update interval of a repo in the schedule after a successful update,
+// which also resets its failures.
 // It does nothing if the repo is not in the schedule.
 func (s *schedule) updateInterval(repo *configuredRepo2, interval time.Duration) {
 	if repo.ID == 0 {
@@ -654,15 +790,10 @@ func (s *schedule) updateInterval(repo *configuredRepo2, interval time.Duration)
 
 	s.mu.Lock()
 	if update := s.index[repo.ID]; update != nil {
-		switch {
-		case interval > maxDelay:
-			update.Interval = maxDelay
-		case interval < minDelay:
-			update.Interval = minDelay
-		default:
-			update.Interval = interval
-		}
+		update.Failures, update.LastError, update.ErrorKind = 0, "", ""
+		update.Interval = clampInterval(interval)
 		update.Due = timeNow().Add(update.Interval)
+		s.dirty[repo.ID] = true
 		log15.Debug("updated repo", "repo", repo.Name, "due", update.Due.Sub(timeNow()))
 		heap.Fix(s, update.Index)
 		s.rescheduleTimer()
@@ -670,6 +801,95 @@ func (s *schedule) updateInterval(repo *configuredRepo2, interval time.Duration)
 	s.mu.Unlock()
 }
 
+// clampInterval returns the given update interval limited to the range [minDelay, maxDelay].
+func clampInterval(interval time.Duration) time.Duration {
+	switch {
+	case interval > maxDelay:
+		return maxDelay
+	case interval < minDelay:
+		return minDelay
+	default:
+		return interval
+	}
+}
+
+// updateFailed records a failed update of a repo in the schedule and backs off its next update
+// exponentially in the number of consecutive failures.
+// It does nothing if the repo is not in the schedule.
+func (s *schedule) updateFailed(repo *configuredRepo2, kind gitserverprotocol.RepoUpdateErrorKind, lastError string) {
+	if repo.ID == 0 {
+		panic("repo.id is zero")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	update := s.index[repo.ID]
+	if update == nil {
+		return
+	}
+
+	update.Failures++
+	update.LastError = lastError
+	update.ErrorKind = string(kind)
+	update.Interval = backoffInterval(update.Failures)
+	update.Due = timeNow().Add(update.Interval)
+	s.dirty[repo.ID] = true
+
+	schedUpdateFailures.WithLabelValues(string(kind)).Inc()
+	log15.Debug("backed off failing repo", "repo", repo.Name, "failures", update.Failures, "kind", kind, "due", update.Due.Sub(timeNow()))
+
+	heap.Fix(s, update.Index)
+	s.rescheduleTimer()
+}
+
+// backoffInterval returns the update interval of a repo whose last updates failed.
+func backoffInterval(failures int) time.Duration {
+	// minDelay << 16 is way beyond maxDelay, so capping the shift avoids overflows
+	// without changing the result.
+	if failures > 16 {
+		failures = 16
+	}
+	return clampInterval(minDelay << uint(failures))
+}
+
+// takeDirty returns the updates of the repos in the schedule which changed since they were
+// last persisted, and marks them as persisted.
+func (s *schedule) takeDirty() []*ScheduledUpdate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updates := make([]*ScheduledUpdate, 0, len(s.dirty))
+	for id := range s.dirty {
+		update := s.index[id]
+		if update == nil {
+			// The repo was removed from the schedule.
+			continue
+		}
+		updates = append(updates, &ScheduledUpdate{
+			RepoID:        id,
+			Interval:      update.Interval,
+			Due:           update.Due,
+			LastError:     update.LastError,
+			LastErrorKind: update.ErrorKind,
+			Failures:      update.Failures,
+		})
+	}
+	s.dirty = make(map[uint32]bool)
+
+	return updates
+}
+
+// markDirty marks the given updates as not persisted, so that they're persisted again.
+func (s *schedule) markDirty(updates []*ScheduledUpdate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range updates {
+		s.dirty[u.RepoID] = true
+	}
+}
+
 // remove removes a repo from the schedule.
 func (s *schedule) remove(repo *configuredRepo2) (removed bool) {
 	if repo.ID == 0 {
@@ -714,6 +934,7 @@ func (s *schedule) reset() {
 
 	s.heap = s.heap[:0]
 	s.index = map[uint32]*scheduledRepoUpdate{}
+	s.dirty = map[uint32]bool{}
 	s.wakeup = make(chan struct{}, notifyChanBuffer)
 	if s.timer != nil {
 		s.timer.Stop()
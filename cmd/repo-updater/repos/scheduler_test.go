@@ -12,6 +12,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	gitserverprotocol "github.com/sourcegraph/sourcegraph/pkg/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/pkg/mutablelimiter"
+	"github.com/sourcegraph/sourcegraph/pkg/repoupdater/protocol"
 )
 
 var defaultTime = time.Date(2000, 1, 1, 1, 1, 1, 1, time.UTC)
@@ -699,6 +700,95 @@ func TestSchedule_upsert(t *testing.T) {
 	}
 }
 
+func TestSchedule_upsertRestored(t *testing.T) {
+	a := &configuredRepo2{ID: 1, Name: "a", URL: "a.com"}
+	b := &configuredRepo2{ID: 2, Name: "b", URL: "b.com"}
+
+	_, stop := startRecording()
+	defer stop()
+
+	s := newUpdateScheduler()
+	s.schedule.restored[a.ID] = &ScheduledUpdate{
+		RepoID:    a.ID,
+		Interval:  time.Hour,
+		Due:       defaultTime.Add(30 * time.Minute),
+		LastError: "fetch failed",
+	}
+	s.schedule.restored[b.ID] = &ScheduledUpdate{
+		RepoID:   b.ID,
+		Interval: 24 * time.Hour,
+		Due:      defaultTime.Add(24 * time.Hour),
+	}
+
+	for _, repo := range []*configuredRepo2{a, b} {
+		if updated, restored := s.schedule.upsert(repo); updated || !restored {
+			t.Fatalf("upsert(%q): got updated=%t, restored=%t, want updated=false, restored=true", repo.Name, updated, restored)
+		}
+	}
+
+	if len(s.schedule.restored) != 0 {
+		t.Fatalf("expected restored updates to be consumed, got %s", spew.Sdump(s.schedule.restored))
+	}
+
+	wantPersisted := []*ScheduledUpdate{
+		{RepoID: a.ID, Interval: time.Hour, Due: defaultTime.Add(30 * time.Minute), LastError: "fetch failed"},
+		{RepoID: b.ID, Interval: maxDelay, Due: defaultTime.Add(maxDelay)},
+	}
+	store := new(FakeStore)
+	if err := store.UpsertScheduledUpdates(context.Background(), s.schedule.takeDirty()...); err != nil {
+		t.Fatal(err)
+	}
+	if persisted, err := store.ListScheduledUpdates(context.Background()); err != nil {
+		t.Fatal(err)
+	} else if !reflect.DeepEqual(persisted, wantPersisted) {
+		t.Fatalf("\nexpected persisted updates\n%s\ngot\n%s", spew.Sdump(wantPersisted), spew.Sdump(persisted))
+	}
+
+	if dirty := s.schedule.takeDirty(); len(dirty) != 0 {
+		t.Fatalf("expected no dirty updates after persisting, got %s", spew.Sdump(dirty))
+	}
+
+	verifySchedule(t, s, []*scheduledRepoUpdate{
+		{Repo: a, Interval: time.Hour, Due: defaultTime.Add(30 * time.Minute), LastError: "fetch failed"},
+		{Repo: b, Interval: maxDelay, Due: defaultTime.Add(maxDelay)},
+	})
+}
+
+func TestUpdateScheduler_FailingRepos(t *testing.T) {
+	a := &configuredRepo2{ID: 1, Name: "a", URL: "a.com"}
+	b := &configuredRepo2{ID: 2, Name: "b", URL: "b.com"}
+	c := &configuredRepo2{ID: 3, Name: "c", URL: "c.com"}
+
+	s := newUpdateScheduler()
+	setupInitialSchedule(s, []*scheduledRepoUpdate{
+		{Repo: a, Interval: minDelay, Due: defaultTime},
+		{Repo: b, Interval: 2 * minDelay, Due: defaultTime.Add(time.Minute), LastError: "not found", ErrorKind: "not-cloneable", Failures: 1},
+		{Repo: c, Interval: 8 * minDelay, Due: defaultTime.Add(time.Hour), LastError: "denied", ErrorKind: "auth", Failures: 3},
+	})
+
+	want := []*protocol.FailingRepo{
+		{ID: c.ID, Name: c.Name, Failures: 3, ErrorKind: "auth", LastError: "denied", Due: defaultTime.Add(time.Hour)},
+		{ID: b.ID, Name: b.Name, Failures: 1, ErrorKind: "not-cloneable", LastError: "not found", Due: defaultTime.Add(time.Minute)},
+	}
+	if have := s.FailingRepos().Repos; !reflect.DeepEqual(have, want) {
+		t.Fatalf("\nexpected failing repos\n%s\ngot\n%s", spew.Sdump(want), spew.Sdump(have))
+	}
+}
+
+func TestBackoffInterval(t *testing.T) {
+	for failures, want := range map[int]time.Duration{
+		1:    2 * minDelay,
+		2:    4 * minDelay,
+		3:    8 * minDelay,
+		10:   maxDelay,
+		1000: maxDelay,
+	} {
+		if have := backoffInterval(failures); have != want {
+			t.Errorf("backoffInterval(%d): have %s, want %s", failures, have, want)
+		}
+	}
+}
+
 func TestSchedule_updateInterval(t *testing.T) {
 	a := &configuredRepo2{ID: 1, Name: "a", URL: "a.com"}
 	b := &configuredRepo2{ID: 2, Name: "b", URL: "b.com"}
@@ -1218,6 +1308,61 @@ func TestUpdateScheduler_runUpdateLoop(t *testing.T) {
 				return []chan struct{}{s.schedule.wakeup}
 			},
 		},
+		{
+			name:                   "failed update backs off",
+			gitMaxConcurrentClones: 1,
+			initialSchedule: []*scheduledRepoUpdate{
+				{Repo: a, Interval: 2 * minDelay, Due: defaultTime.Add(time.Hour), LastError: "timeout", ErrorKind: "timeout", Failures: 1},
+			},
+			initialQueue: []*repoUpdate{
+				{Repo: a, Seq: 1},
+			},
+			mockRequestRepoUpdates: []*mockRequestRepoUpdate{
+				{
+					repo: a,
+					resp: &gitserverprotocol.RepoUpdateResponse{
+						Cloned:      true,
+						LastFetched: timePtr(defaultTime.Add(2 * time.Minute)),
+						LastChanged: timePtr(defaultTime),
+						Error:       "fatal: Authentication failed",
+						ErrorKind:   gitserverprotocol.RepoUpdateErrorAuth,
+					},
+				},
+			},
+			finalSchedule: []*scheduledRepoUpdate{
+				{Repo: a, Interval: 4 * minDelay, Due: defaultTime.Add(4 * minDelay), LastError: "fatal: Authentication failed", ErrorKind: "auth", Failures: 2},
+			},
+			timeAfterFuncDelays: []time.Duration{4 * minDelay},
+			expectedNotifications: func(s *updateScheduler) []chan struct{} {
+				return []chan struct{}{s.schedule.wakeup}
+			},
+		},
+		{
+			name:                   "successful update resets failures",
+			gitMaxConcurrentClones: 1,
+			initialSchedule: []*scheduledRepoUpdate{
+				{Repo: a, Interval: 4 * minDelay, Due: defaultTime.Add(time.Hour), LastError: "timeout", ErrorKind: "timeout", Failures: 2},
+			},
+			initialQueue: []*repoUpdate{
+				{Repo: a, Seq: 1},
+			},
+			mockRequestRepoUpdates: []*mockRequestRepoUpdate{
+				{
+					repo: a,
+					resp: &gitserverprotocol.RepoUpdateResponse{
+						LastFetched: timePtr(defaultTime.Add(2 * time.Minute)),
+						LastChanged: timePtr(defaultTime),
+					},
+				},
+			},
+			finalSchedule: []*scheduledRepoUpdate{
+				{Repo: a, Interval: time.Minute, Due: defaultTime.Add(time.Minute)},
+			},
+			timeAfterFuncDelays: []time.Duration{time.Minute},
+			expectedNotifications: func(s *updateScheduler) []chan struct{} {
+				return []chan struct{}{s.schedule.wakeup}
+			},
+		},
 	}
 
 	for _, test := range tests {
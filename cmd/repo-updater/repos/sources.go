@@ -2,6 +2,7 @@ package repos
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -20,9 +21,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitolite"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/phabricator"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/plugin"
 	"github.com/sourcegraph/sourcegraph/pkg/gitserver"
 	"github.com/sourcegraph/sourcegraph/pkg/httpcli"
-	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -88,6 +89,8 @@ func NewSource(svc *ExternalService, cf httpcli.Factory) (Source, error) {
 		return NewPhabricatorSource(svc, cf)
 	case "other":
 		return NewOtherSource(svc)
+	case "plugin":
+		return NewPluginSource(svc, cf)
 	default:
 		panic(fmt.Sprintf("source not implemented for external service kind %q", svc.Kind))
 	}
@@ -222,7 +225,7 @@ type GithubSource struct {
 // NewGithubSource returns a new GithubSource from the given external service.
 func NewGithubSource(svc *ExternalService, cf httpcli.Factory) (*GithubSource, error) {
 	var c schema.GitHubConnection
-	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+	if err := svc.unmarshalConfig(&c); err != nil {
 		return nil, fmt.Errorf("external service id=%d config error: %s", svc.ID, err)
 	}
 	return newGithubSource(svc, &c, cf)
@@ -296,7 +299,7 @@ type GitLabSource struct {
 // NewGitLabSource returns a new GitLabSource from the given external service.
 func NewGitLabSource(svc *ExternalService, cf httpcli.Factory) (*GitLabSource, error) {
 	var c schema.GitLabConnection
-	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+	if err := svc.unmarshalConfig(&c); err != nil {
 		return nil, fmt.Errorf("external service id=%d config error: %s", svc.ID, err)
 	}
 	return newGitLabSource(svc, &c, cf)
@@ -358,7 +361,7 @@ type BitbucketServerSource struct {
 // NewBitbucketServerSource returns a new BitbucketServerSource from the given external service.
 func NewBitbucketServerSource(svc *ExternalService, cf httpcli.Factory) (*BitbucketServerSource, error) {
 	var c schema.BitbucketServerConnection
-	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+	if err := svc.unmarshalConfig(&c); err != nil {
 		return nil, fmt.Errorf("external service id=%d config error: %s", svc.ID, err)
 	}
 	return newBitbucketServerSource(svc, &c, cf)
@@ -425,7 +428,7 @@ type GitoliteSource struct {
 // NewGitoliteSource returns a new GitoliteSource from the given external service.
 func NewGitoliteSource(svc *ExternalService, cf httpcli.Factory) (*GitoliteSource, error) {
 	var c schema.GitoliteConnection
-	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+	if err := svc.unmarshalConfig(&c); err != nil {
 		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
 	}
 
@@ -517,7 +520,7 @@ type PhabricatorSource struct {
 // NewPhabricatorSource returns a new PhabricatorSource from the given external service.
 func NewPhabricatorSource(svc *ExternalService, cf httpcli.Factory) (*PhabricatorSource, error) {
 	var c schema.PhabricatorConnection
-	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+	if err := svc.unmarshalConfig(&c); err != nil {
 		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
 	}
 	return &PhabricatorSource{svc: svc, conn: &c, cf: cf}, nil
@@ -680,7 +683,7 @@ type OtherSource struct {
 // NewOtherSource returns a new OtherSource from the given external service.
 func NewOtherSource(svc *ExternalService) (*OtherSource, error) {
 	var c schema.OtherExternalServiceConnection
-	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+	if err := svc.unmarshalConfig(&c); err != nil {
 		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
 	}
 	return &OtherSource{svc: svc, conn: &c}, nil
@@ -773,3 +776,124 @@ func otherRepoFromCloneURL(urn string, u *url.URL) *Repo {
 		},
 	}
 }
+
+// A PluginSource yields repositories from a single repository listing plugin configured
+// in Sourcegraph via the external services configuration.
+type PluginSource struct {
+	svc       *ExternalService
+	conn      *schema.PluginConnection
+	serviceID string
+	client    *plugin.Client
+}
+
+// NewPluginSource returns a new PluginSource from the given external service.
+func NewPluginSource(svc *ExternalService, cf httpcli.Factory) (*PluginSource, error) {
+	var c schema.PluginConnection
+	if err := svc.unmarshalConfig(&c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+
+	src := &PluginSource{svc: svc, conn: &c, serviceID: c.ServiceID}
+
+	switch {
+	case c.Url != "":
+		u, err := url.Parse(c.Url)
+		if err != nil {
+			return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+		}
+
+		hc, err := cf.NewClient()
+		if err != nil {
+			return nil, err
+		}
+
+		src.client = plugin.NewHTTPClient(u, c.Token, hc)
+		if src.serviceID == "" {
+			id := *u
+			id.User, id.RawQuery, id.Fragment = nil, "", ""
+			src.serviceID = id.String()
+		}
+	case len(c.Command) > 0:
+		src.client = plugin.NewCommandClient(c.Command)
+		if src.serviceID == "" {
+			src.serviceID = svc.URN()
+		}
+	default:
+		return nil, fmt.Errorf("external service id=%d config error: either url or command must be set", svc.ID)
+	}
+
+	return src, nil
+}
+
+// maxPluginPages bounds the number of pages listed from a plugin, so that
+// a plugin which never stops paginating can't stall syncs forever.
+const maxPluginPages = 10000
+
+// ListRepos returns all the repositories listed by the plugin.
+func (s PluginSource) ListRepos(ctx context.Context) ([]*Repo, error) {
+	var repos []*Repo
+
+	cursor := ""
+	for i := 0; ; i++ {
+		if i == maxPluginPages {
+			return nil, fmt.Errorf("plugin listed more than %d pages", maxPluginPages)
+		}
+
+		page, err := s.client.ListRepos(ctx, cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, r := range page.Repos {
+			repo, err := s.makeRepo(r)
+			if err != nil {
+				return nil, err
+			}
+			repos = append(repos, repo)
+		}
+
+		if page.Next == "" || page.Next == cursor {
+			break
+		}
+		cursor = page.Next
+	}
+
+	return repos, nil
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s PluginSource) ExternalServices() ExternalServices {
+	return ExternalServices{s.svc}
+}
+
+func (s PluginSource) makeRepo(r *plugin.Repo) (*Repo, error) {
+	// The metadata is decoded so that it compares equal to the metadata
+	// stored in the database, which doesn't preserve its formatting.
+	var metadata interface{}
+	if len(r.Metadata) > 0 {
+		if err := json.Unmarshal(r.Metadata, &metadata); err != nil {
+			return nil, errors.Wrapf(err, "invalid metadata of plugin repo %q", r.Name)
+		}
+	}
+
+	urn := s.svc.URN()
+	return &Repo{
+		Name:        r.Name,
+		Description: r.Description,
+		Fork:        r.Fork,
+		Archived:    r.Archived,
+		Enabled:     true,
+		ExternalRepo: api.ExternalRepoSpec{
+			ID:          r.ExternalID,
+			ServiceType: plugin.ServiceType,
+			ServiceID:   s.serviceID,
+		},
+		Sources: map[string]*SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: r.CloneURL,
+			},
+		},
+		Metadata: &metadata,
+	}, nil
+}
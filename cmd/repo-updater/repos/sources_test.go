@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
@@ -55,6 +56,60 @@ func TestOtherRepoName(t *testing.T) {
 	}
 }
 
+func TestPluginSource_ListRepos(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			fmt.Fprint(w, `{"repos": [{"name": "git.example.com/foo", "externalID": "1", "cloneURL": "https://git.example.com/foo.git", "metadata": {"team": "a"}}], "next": "2"}`)
+		case "2":
+			fmt.Fprint(w, `{"repos": [{"name": "git.example.com/bar", "externalID": "2", "cloneURL": "https://git.example.com/bar.git", "archived": true}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	svc := &ExternalService{
+		ID:     1,
+		Kind:   "PLUGIN",
+		Config: marshalJSON(t, &schema.PluginConnection{Url: srv.URL}),
+	}
+
+	src, err := NewPluginSource(svc, httpcli.NewFactory(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	have, err := src.ListRepos(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var fooMetadata, barMetadata interface{} = map[string]interface{}{"team": "a"}, nil
+	urn := svc.URN()
+	want := []*Repo{
+		{
+			Name:         "git.example.com/foo",
+			Enabled:      true,
+			ExternalRepo: api.ExternalRepoSpec{ID: "1", ServiceType: "plugin", ServiceID: srv.URL},
+			Sources:      map[string]*SourceInfo{urn: {ID: urn, CloneURL: "https://git.example.com/foo.git"}},
+			Metadata:     &fooMetadata,
+		},
+		{
+			Name:         "git.example.com/bar",
+			Enabled:      true,
+			Archived:     true,
+			ExternalRepo: api.ExternalRepoSpec{ID: "2", ServiceType: "plugin", ServiceID: srv.URL},
+			Sources:      map[string]*SourceInfo{urn: {ID: urn, CloneURL: "https://git.example.com/bar.git"}},
+			Metadata:     &barMetadata,
+		},
+	}
+
+	if diff := cmp.Diff(have, want); diff != "" {
+		t.Fatalf("ListRepos:\n%s", diff)
+	}
+}
+
 func TestNewSourcer(t *testing.T) {
 	now := time.Now()
 
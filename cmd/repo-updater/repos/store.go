@@ -22,6 +22,9 @@ type Store interface {
 
 	ListRepos(context.Context, StoreListReposArgs) ([]*Repo, error)
 	UpsertRepos(ctx context.Context, repos ...*Repo) error
+
+	ListScheduledUpdates(context.Context) ([]*ScheduledUpdate, error)
+	UpsertScheduledUpdates(ctx context.Context, updates ...*ScheduledUpdate) error
 }
 
 // StoreListReposArgs is a query arguments type used by
@@ -612,6 +615,151 @@ LEFT JOIN inserted ON batch.name = inserted.name
 ORDER BY batch.ordinality
 `
 
+// ListScheduledUpdates lists the persisted update schedule of all repos.
+func (s DBStore) ListScheduledUpdates(ctx context.Context) (updates []*ScheduledUpdate, _ error) {
+	return updates, s.paginate(ctx, listScheduledUpdatesQuery, func(sc scanner) (int64, error) {
+		var u ScheduledUpdate
+		if err := scanScheduledUpdate(&u, sc); err != nil {
+			return 0, err
+		}
+		updates = append(updates, &u)
+		return int64(u.RepoID), nil
+	})
+}
+
+const listScheduledUpdatesQueryFmtstr = `
+-- source: cmd/repo-updater/repos/store.go:DBStore.ListScheduledUpdates
+SELECT
+  repo_id,
+  interval_seconds,
+  due_at,
+  last_error,
+  last_error_kind,
+  failures,
+  updated_at
+FROM repo_update_schedule
+WHERE repo_id > %s
+ORDER BY repo_id ASC LIMIT %s
+`
+
+func listScheduledUpdatesQuery(cursor, limit int64) *sqlf.Query {
+	return sqlf.Sprintf(listScheduledUpdatesQueryFmtstr, cursor, limit)
+}
+
+// UpsertScheduledUpdates updates or inserts the persisted update schedule of the given repos.
+func (s DBStore) UpsertScheduledUpdates(ctx context.Context, updates ...*ScheduledUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	q, err := upsertScheduledUpdatesQuery(updates)
+	if err != nil {
+		return err
+	}
+
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[uint32]*ScheduledUpdate, len(updates))
+	for _, u := range updates {
+		byID[u.RepoID] = u
+	}
+
+	_, err = scanAll(rows, func(sc scanner) (int64, error) {
+		var id uint32
+		var updatedAt time.Time
+		if err := sc.Scan(&id, &updatedAt); err != nil {
+			return 0, err
+		}
+		if u := byID[id]; u != nil {
+			u.UpdatedAt = updatedAt
+		}
+		return int64(id), nil
+	})
+
+	return err
+}
+
+func upsertScheduledUpdatesQuery(updates []*ScheduledUpdate) (*sqlf.Query, error) {
+	type record struct {
+		RepoID          uint32    `json:"repo_id"`
+		IntervalSeconds int64     `json:"interval_seconds"`
+		DueAt           time.Time `json:"due_at"`
+		LastError       *string   `json:"last_error,omitempty"`
+		LastErrorKind   *string   `json:"last_error_kind,omitempty"`
+		Failures        int       `json:"failures"`
+	}
+
+	records := make([]record, 0, len(updates))
+	for _, u := range updates {
+		records = append(records, record{
+			RepoID:          u.RepoID,
+			IntervalSeconds: int64(u.Interval / time.Second),
+			DueAt:           u.Due.UTC(),
+			LastError:       nullStringColumn(u.LastError),
+			LastErrorKind:   nullStringColumn(u.LastErrorKind),
+			Failures:        u.Failures,
+		})
+	}
+
+	batch, err := json.MarshalIndent(records, "    ", "    ")
+	if err != nil {
+		return nil, err
+	}
+
+	return sqlf.Sprintf(upsertScheduledUpdatesQueryFmtstr, string(batch)), nil
+}
+
+// upsertScheduledUpdatesQueryFmtstr uses the same JSON batching technique as
+// upsertReposQueryFmtstr. Updates of repos which have been removed in the
+// meantime are skipped, so the returned rows may be fewer than the batch.
+const upsertScheduledUpdatesQueryFmtstr = `
+-- source: cmd/repo-updater/repos/store.go:DBStore.UpsertScheduledUpdates
+WITH batch AS (
+  SELECT * FROM ROWS FROM (
+  json_to_recordset(%s)
+  AS (
+      repo_id          integer,
+      interval_seconds integer,
+      due_at           timestamptz,
+      last_error       text,
+      last_error_kind  text,
+      failures         integer
+    )
+  )
+)
+INSERT INTO repo_update_schedule (
+  repo_id,
+  interval_seconds,
+  due_at,
+  last_error,
+  last_error_kind,
+  failures,
+  updated_at
+)
+SELECT
+  repo_id,
+  interval_seconds,
+  due_at,
+  last_error,
+  last_error_kind,
+  failures,
+  now()
+FROM batch
+WHERE EXISTS (SELECT 1 FROM repo WHERE repo.id = batch.repo_id)
+ON CONFLICT (repo_id) DO UPDATE
+SET
+  interval_seconds = excluded.interval_seconds,
+  due_at           = excluded.due_at,
+  last_error       = excluded.last_error,
+  last_error_kind  = excluded.last_error_kind,
+  failures         = excluded.failures,
+  updated_at       = excluded.updated_at
+RETURNING repo_id, updated_at
+`
+
 func nullTimeColumn(t time.Time) *time.Time {
 	if t.IsZero() {
 		return nil
@@ -682,6 +830,21 @@ func scanExternalService(svc *ExternalService, s scanner) error {
 	)
 }
 
+func scanScheduledUpdate(u *ScheduledUpdate, s scanner) error {
+	var intervalSeconds int64
+	err := s.Scan(
+		&u.RepoID,
+		&intervalSeconds,
+		&u.Due,
+		&nullString{&u.LastError},
+		&nullString{&u.LastErrorKind},
+		&u.Failures,
+		&u.UpdatedAt,
+	)
+	u.Interval = time.Duration(intervalSeconds) * time.Second
+	return err
+}
+
 func scanRepo(r *Repo, s scanner) error {
 	var sources, metadata json.RawMessage
 	err := s.Scan(
@@ -718,6 +881,8 @@ func scanRepo(r *Repo, s scanner) error {
 		r.Metadata = new(gitlab.Project)
 	case "bitbucketserver":
 		r.Metadata = new(bitbucketserver.Repo)
+	case "plugin":
+		r.Metadata = new(interface{})
 	default:
 		return nil
 	}
@@ -34,6 +34,7 @@ func TestFakeStore(t *testing.T) {
 		{"UpsertExternalServices", testStoreUpsertExternalServices},
 		{"ListRepos", testStoreListRepos},
 		{"UpsertRepos", testStoreUpsertRepos},
+		{"UpsertScheduledUpdates", testStoreUpsertScheduledUpdates},
 	} {
 		t.Run(tc.name, tc.test(repos.NewObservedStore(
 			new(repos.FakeStore),
@@ -452,6 +453,76 @@ func testStoreUpsertRepos(store repos.Store) func(*testing.T) {
 	}
 }
 
+func testStoreUpsertScheduledUpdates(store repos.Store) func(*testing.T) {
+	clock := repos.NewFakeClock(time.Now(), 0)
+	now := clock.Now()
+
+	return func(t *testing.T) {
+		t.Helper()
+
+		ctx := context.Background()
+
+		t.Run("no updates", func(t *testing.T) {
+			if err := store.UpsertScheduledUpdates(ctx); err != nil {
+				t.Fatalf("UpsertScheduledUpdates error: %s", err)
+			}
+		})
+
+		t.Run("many updates", transact(ctx, store, func(t testing.TB, tx repos.Store) {
+			rs := mkRepos(3, &repos.Repo{
+				Name: "git-host.com/org/foo",
+				ExternalRepo: api.ExternalRepoSpec{
+					ID:          "git-host.com/org/foo",
+					ServiceID:   "https://git-host.com/",
+					ServiceType: "other",
+				},
+			})
+
+			if err := tx.UpsertRepos(ctx, rs...); err != nil {
+				t.Fatalf("UpsertRepos error: %s", err)
+			}
+
+			var want []*repos.ScheduledUpdate
+			for i, r := range rs {
+				want = append(want, &repos.ScheduledUpdate{
+					RepoID:   r.ID,
+					Interval: time.Duration(i+1) * time.Minute,
+					Due:      now.Add(time.Duration(i+1) * time.Minute),
+				})
+			}
+
+			if err := tx.UpsertScheduledUpdates(ctx, want...); err != nil {
+				t.Fatalf("UpsertScheduledUpdates error: %s", err)
+			}
+
+			have, err := tx.ListScheduledUpdates(ctx)
+			if err != nil {
+				t.Fatalf("ListScheduledUpdates error: %s", err)
+			}
+
+			if diff := pretty.Compare(have, want); diff != "" {
+				t.Fatalf("ListScheduledUpdates:\n%s", diff)
+			}
+
+			for _, u := range want {
+				u.Interval *= 2
+				u.Due = u.Due.Add(time.Hour)
+				u.LastError = "fetch failed"
+				u.LastErrorKind = "auth"
+				u.Failures++
+			}
+
+			if err = tx.UpsertScheduledUpdates(ctx, want...); err != nil {
+				t.Errorf("UpsertScheduledUpdates error: %s", err)
+			} else if have, err = tx.ListScheduledUpdates(ctx); err != nil {
+				t.Errorf("ListScheduledUpdates error: %s", err)
+			} else if diff := pretty.Compare(have, want); diff != "" {
+				t.Errorf("ListScheduledUpdates:\n%s", diff)
+			}
+		}))
+	}
+}
+
 func testStoreListRepos(store repos.Store) func(*testing.T) {
 	clock := repos.NewFakeClock(time.Now(), 0)
 	now := clock.Now()
@@ -125,6 +125,9 @@ type Diff struct {
 	Deleted    Repos
 	Modified   Repos
 	Unmodified Repos
+
+	// Renamed maps the previous names of renamed repos to their new names.
+	Renamed map[string]string
 }
 
 // Sort sorts all Diff elements by Repo.IDs.
@@ -189,8 +192,15 @@ func NewDiff(sourced, stored []*Repo) (diff Diff) {
 				diff.Unmodified = append(diff.Unmodified, old)
 			}
 		} else if !old.IsDeleted() {
+			name := old.Name
 			if old.Update(src) {
 				diff.Modified = append(diff.Modified, old)
+				if old.Name != name {
+					if diff.Renamed == nil {
+						diff.Renamed = map[string]string{}
+					}
+					diff.Renamed[name] = old.Name
+				}
 			} else {
 				diff.Unmodified = append(diff.Unmodified, old)
 			}
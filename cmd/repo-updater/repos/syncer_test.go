@@ -284,9 +284,12 @@ func testSyncerSync(s repos.Store) func(*testing.T) {
 					r.Name = "old-name"
 				})},
 				now: clock.Now,
-				diff: repos.Diff{Modified: repos.Repos{tc.repo.With(
-					repos.Opt.RepoModifiedAt(clock.Time(1))),
-				}},
+				diff: repos.Diff{
+					Modified: repos.Repos{tc.repo.With(
+						repos.Opt.RepoModifiedAt(clock.Time(1))),
+					},
+					Renamed: map[string]string{"old-name": tc.repo.Name},
+				},
 				err: "<nil>",
 			},
 			testCase{
@@ -481,9 +484,12 @@ func TestDiff(t *testing.T) {
 			source: repos.Repos{
 				{Name: "2", ExternalRepo: eid("1"), Description: "foo"},
 			},
-			diff: repos.Diff{Modified: repos.Repos{
-				{Name: "2", ExternalRepo: eid("1"), Description: "foo"},
-			}},
+			diff: repos.Diff{
+				Modified: repos.Repos{
+					{Name: "2", ExternalRepo: eid("1"), Description: "foo"},
+				},
+				Renamed: map[string]string{"1": "2"},
+			},
 		},
 		{
 			name: "duplicate with added external id is merged correctly",
@@ -546,6 +552,7 @@ func TestDiff(t *testing.T) {
 				Added: repos.Repos{
 					{Name: "1", ExternalRepo: eid("new"), Description: "bar"},
 				},
+				Renamed: map[string]string{"1": "2"},
 			},
 		},
 		{
@@ -563,6 +570,7 @@ func TestDiff(t *testing.T) {
 					{Name: "bar", ExternalRepo: eid("1"), Description: "bar"},
 					{Name: "foo", ExternalRepo: eid("2"), Description: "foo"},
 				},
+				Renamed: map[string]string{"foo": "bar", "bar": "foo"},
 			},
 		},
 	}
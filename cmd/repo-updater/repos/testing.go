@@ -58,12 +58,15 @@ type FakeStore struct {
 	GetRepoByNameError          error // error to be returned in GetRepoByName
 	ListReposError              error // error to be returned in ListRepos
 	UpsertReposError            error // error to be returned in UpsertRepos
-
-	svcIDSeq   int64
-	repoIDSeq  uint32
-	svcByID    map[int64]*ExternalService
-	repoByName map[string]*Repo
-	repoByID   map[api.ExternalRepoSpec]*Repo
+	ListScheduledUpdatesError   error // error to be returned in ListScheduledUpdates
+	UpsertScheduledUpdatesError error // error to be returned in UpsertScheduledUpdates
+
+	svcIDSeq       int64
+	repoIDSeq      uint32
+	svcByID        map[int64]*ExternalService
+	repoByName     map[string]*Repo
+	repoByID       map[api.ExternalRepoSpec]*Repo
+	scheduleByRepo map[uint32]*ScheduledUpdate
 }
 
 // Transact returns a TxStore whose methods operate within the context of a transaction.
@@ -81,18 +84,27 @@ func (s *FakeStore) Transact(ctx context.Context) (TxStore, error) {
 		repoByID[r.ExternalRepo] = clone
 	}
 
+	scheduleByRepo := make(map[uint32]*ScheduledUpdate, len(s.scheduleByRepo))
+	for id, u := range s.scheduleByRepo {
+		clone := *u
+		scheduleByRepo[id] = &clone
+	}
+
 	return &FakeStore{
 		ListExternalServicesError:   s.ListExternalServicesError,
 		UpsertExternalServicesError: s.UpsertExternalServicesError,
 		GetRepoByNameError:          s.GetRepoByNameError,
 		ListReposError:              s.ListReposError,
 		UpsertReposError:            s.UpsertReposError,
-
-		svcIDSeq:   s.svcIDSeq,
-		svcByID:    svcByID,
-		repoIDSeq:  s.repoIDSeq,
-		repoByName: repoByName,
-		repoByID:   repoByID,
+		ListScheduledUpdatesError:   s.ListScheduledUpdatesError,
+		UpsertScheduledUpdatesError: s.UpsertScheduledUpdatesError,
+
+		svcIDSeq:       s.svcIDSeq,
+		svcByID:        svcByID,
+		repoIDSeq:      s.repoIDSeq,
+		repoByName:     repoByName,
+		repoByID:       repoByID,
+		scheduleByRepo: scheduleByRepo,
 	}, nil
 }
 
@@ -256,6 +268,41 @@ func (s *FakeStore) UpsertRepos(ctx context.Context, upserts ...*Repo) error {
 	return nil
 }
 
+// ListScheduledUpdates lists the persisted update schedule of all repos in the store.
+func (s FakeStore) ListScheduledUpdates(ctx context.Context) ([]*ScheduledUpdate, error) {
+	if s.ListScheduledUpdatesError != nil {
+		return nil, s.ListScheduledUpdatesError
+	}
+
+	updates := make([]*ScheduledUpdate, 0, len(s.scheduleByRepo))
+	for _, u := range s.scheduleByRepo {
+		updates = append(updates, u)
+	}
+
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].RepoID < updates[j].RepoID
+	})
+
+	return updates, nil
+}
+
+// UpsertScheduledUpdates upserts the persisted update schedule of the given repos in the store.
+func (s *FakeStore) UpsertScheduledUpdates(ctx context.Context, updates ...*ScheduledUpdate) error {
+	if s.UpsertScheduledUpdatesError != nil {
+		return s.UpsertScheduledUpdatesError
+	}
+
+	if s.scheduleByRepo == nil {
+		s.scheduleByRepo = make(map[uint32]*ScheduledUpdate, len(updates))
+	}
+
+	for _, u := range updates {
+		s.scheduleByRepo[u.RepoID] = u
+	}
+
+	return nil
+}
+
 //
 // Assertions
 //
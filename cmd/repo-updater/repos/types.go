@@ -13,6 +13,7 @@ import (
 	multierror "github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
 	"github.com/sourcegraph/sourcegraph/schema"
 	"github.com/xeipuuv/gojsonschema"
@@ -85,10 +86,22 @@ func (e ExternalService) Configuration() (cfg interface{}, _ error) {
 		cfg = &schema.PhabricatorConnection{}
 	case "other":
 		cfg = &schema.OtherExternalServiceConnection{}
+	case "plugin":
+		cfg = &schema.PluginConnection{}
 	default:
 		return nil, fmt.Errorf("unknown external service kind %q", e.Kind)
 	}
-	return cfg, jsonc.Unmarshal(e.Config, cfg)
+	return cfg, e.unmarshalConfig(cfg)
+}
+
+// unmarshalConfig unmarshals the configuration of the external service into cfg, resolving secret
+// references (like "$env:SRC_SECRET_GITHUB_TOKEN").
+func (e ExternalService) unmarshalConfig(cfg interface{}) error {
+	config, err := conf.ResolveSecrets(e.Config)
+	if err != nil {
+		return err
+	}
+	return jsonc.Unmarshal(config, cfg)
 }
 
 // Exclude changes the configuration of an external service to exclude the given
@@ -857,3 +870,22 @@ func (es ExternalServices) Filter(pred func(*ExternalService) bool) (fs External
 	}
 	return fs
 }
+
+// A ScheduledUpdate is the persisted state of a repo in the update scheduler,
+// which survives repo-updater restarts.
+type ScheduledUpdate struct {
+	// RepoID is the ID of the scheduled repo.
+	RepoID uint32
+	// Interval is the learned interval between updates of the repo.
+	Interval time.Duration
+	// Due is the next time the repo will be enqueued for an update.
+	Due time.Time
+	// LastError is the error of the last update of the repo, if it failed.
+	LastError string
+	// LastErrorKind is the classification of LastError reported by gitserver.
+	LastErrorKind string
+	// Failures is the number of consecutive failed updates of the repo.
+	Failures int
+	// UpdatedAt is when this state was last persisted.
+	UpdatedAt time.Time
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/httpcli"
+	"github.com/sourcegraph/sourcegraph/pkg/ratelimit"
 
 	"github.com/gregjones/httpcache"
 	"github.com/opentracing-contrib/go-stdlib/nethttp"
@@ -43,6 +44,7 @@ func NewHTTPClientFactory() httpcli.Factory {
 		// TODO(tsenart): Use middle for Prometheus instrumentation later.
 		httpcli.NewMiddleware(
 			httpcli.ContextErrorMiddleware,
+			ratelimit.BudgetMiddleware("repo-updater"),
 		),
 		httpcli.TracedTransportOpt,
 		httpcli.NewCachedTransportOpt(httputil.Cache, true),
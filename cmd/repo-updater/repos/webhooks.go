@@ -0,0 +1,37 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// EnqueueSyncedWebhook notifies the frontend that the repositories of the external services of the
+// given kinds were synced, so that the event (with a summary of the sync's Diff) is delivered to the
+// site's outbound webhooks.
+//
+// Failures are only logged, since webhooks are a best-effort notification mechanism.
+func EnqueueSyncedWebhook(ctx context.Context, kinds []string, diff Diff) {
+	err := api.InternalClient.WebhooksEnqueue(ctx, api.WebhookEnqueueRequest{
+		Event: api.WebhookEventExternalServiceSynced,
+		Payload: struct {
+			Kinds      []string `json:"kinds"`
+			Added      int      `json:"added"`
+			Modified   int      `json:"modified"`
+			Deleted    int      `json:"deleted"`
+			Unmodified int      `json:"unmodified"`
+			Renamed    int      `json:"renamed"`
+		}{
+			Kinds:      kinds,
+			Added:      len(diff.Added),
+			Modified:   len(diff.Modified),
+			Deleted:    len(diff.Deleted),
+			Unmodified: len(diff.Unmodified),
+			Renamed:    len(diff.Renamed),
+		},
+	})
+	if err != nil {
+		log15.Warn("failed to enqueue external service synced webhook event", "error", err)
+	}
+}
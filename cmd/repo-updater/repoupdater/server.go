@@ -18,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/bitbucketserver"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitlab"
+	"github.com/sourcegraph/sourcegraph/pkg/ratelimit"
 	"github.com/sourcegraph/sourcegraph/pkg/repoupdater/protocol"
 	"github.com/sourcegraph/sourcegraph/pkg/trace"
 	log15 "gopkg.in/inconshreveable/log15.v2"
@@ -38,6 +39,7 @@ type Server struct {
 func (s *Server) Handler() http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/repo-update-scheduler-info", s.handleRepoUpdateSchedulerInfo)
+	mux.HandleFunc("/failing-repos", s.handleFailingRepos)
 	mux.HandleFunc("/repo-lookup", s.handleRepoLookup)
 	mux.HandleFunc("/repo-external-services", s.handleRepoExternalServices)
 	mux.HandleFunc("/enqueue-repo-update", s.handleEnqueueRepoUpdate)
@@ -215,6 +217,14 @@ func (s *Server) handleRepoUpdateSchedulerInfo(w http.ResponseWriter, r *http.Re
 	}
 }
 
+func (s *Server) handleFailingRepos(w http.ResponseWriter, r *http.Request) {
+	result := repos.Scheduler.FailingRepos()
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
 func (s *Server) handleRepoLookup(w http.ResponseWriter, r *http.Request) {
 	var args protocol.RepoLookupArgs
 	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
@@ -222,8 +232,13 @@ func (s *Server) handleRepoLookup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Lookups are made on behalf of a waiting user, so they take precedence
+	// over background syncs in the code host request budgets.
+	ctx := ratelimit.WithPriority(r.Context(), ratelimit.PriorityInteractive)
+	ctx = ratelimit.WithConsumer(ctx, "repoLookup")
+
 	t := time.Now()
-	result, err := s.repoLookup(r.Context(), args)
+	result, err := s.repoLookup(ctx, args)
 	if err != nil {
 		if err == context.Canceled {
 			http.Error(w, "request canceled", http.StatusGatewayTimeout)
@@ -3,8 +3,10 @@ package httpheader
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/pkg/actor"
@@ -92,6 +94,16 @@ func middleware(next http.Handler) http.Handler {
 		}
 
 		r = r.WithContext(actor.WithActor(r.Context(), &actor.Actor{UID: userID}))
+
+		// The user is signed in on every request (there is no session), so only record the first
+		// sign-in in each hour.
+		audit.RecordOnce(r.Context(), providerType+":"+strconv.Itoa(int(userID)), audit.Event{
+			Action:     audit.UserSignIn,
+			TargetType: "User",
+			TargetID:   audit.UserID(userID),
+			Details:    map[string]string{"path": r.URL.Path, "provider": providerType},
+		})
+
 		next.ServeHTTP(w, r)
 	})
 }
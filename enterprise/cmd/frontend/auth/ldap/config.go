@@ -0,0 +1,132 @@
+package ldap
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+	ldapv3 "gopkg.in/ldap.v3"
+)
+
+const (
+	pkgName      = "ldap"
+	providerType = "ldap"
+)
+
+func init() {
+	conf.ContributeValidator(func(cfg conf.Unified) (problems []string) {
+		_, problems = parseConfig(&cfg)
+		return problems
+	})
+}
+
+// parseConfig returns the LDAP auth providers in the configuration and any problems with their
+// configuration. Providers with problems are omitted.
+func parseConfig(cfg *conf.Unified) (ps []providers.Provider, problems []string) {
+	seen := map[string]int{}
+	for i, pr := range cfg.Critical.AuthProviders {
+		if pr.Ldap == nil {
+			continue
+		}
+		p, providerProblems := parseProvider(pr.Ldap)
+		for _, problem := range providerProblems {
+			problems = append(problems, fmt.Sprintf("LDAP auth provider at index %d: %s", i, problem))
+		}
+		if p == nil {
+			continue
+		}
+		id := p.ConfigID().ID
+		if j, ok := seen[id]; ok {
+			problems = append(problems, fmt.Sprintf("LDAP auth provider at index %d is duplicate of index %d, ignoring", i, j))
+			continue
+		}
+		seen[id] = i
+		ps = append(ps, p)
+	}
+	return ps, problems
+}
+
+func parseProvider(c *schema.LDAPAuthProvider) (p *provider, problems []string) {
+	u, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, []string{fmt.Sprintf("could not parse url %q: %s", c.Url, err)}
+	}
+	if u.Scheme != "ldap" && u.Scheme != "ldaps" {
+		return nil, []string{fmt.Sprintf("url %q must have the ldap:// or ldaps:// scheme", c.Url)}
+	}
+	if u.Scheme == "ldaps" && c.StartTLS {
+		problems = append(problems, "startTLS can't be used with an ldaps:// url, which already uses TLS")
+	}
+	if c.Certificate != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(c.Certificate)) {
+		problems = append(problems, "certificate is not a valid PEM-encoded certificate")
+	}
+	if _, err := ldapv3.CompileFilter(userFilter(c)); err != nil {
+		problems = append(problems, fmt.Sprintf("invalid userFilter %q: %s", c.UserFilter, err))
+	}
+	if len(c.GroupOrgMap) > 0 && c.GroupBaseDN == "" {
+		problems = append(problems, "groupOrgMap requires groupBaseDN to be set")
+	}
+	if c.BindDN != "" && c.BindPassword == "" {
+		problems = append(problems, "bindPassword is required when bindDN is set")
+	}
+	if len(problems) > 0 {
+		return nil, problems
+	}
+	return &provider{config: *c}, nil
+}
+
+// providerConfigID produces a semi-stable identifier for an LDAP auth provider config object. It is
+// used to distinguish between multiple auth providers of the same type. Its value is never
+// persisted, and it must be deterministic. It is derived only from the properties that identify
+// the directory and its users (and not from bindPassword, because it appears in URLs).
+func providerConfigID(c *schema.LDAPAuthProvider) string {
+	b := sha256.Sum256([]byte(c.Url + "\x00" + c.UserBaseDN + "\x00" + usernameAttribute(c)))
+	return base64.RawURLEncoding.EncodeToString(b[:16])
+}
+
+func userFilter(c *schema.LDAPAuthProvider) string {
+	if c.UserFilter != "" {
+		return c.UserFilter
+	}
+	return "(objectClass=person)"
+}
+
+func usernameAttribute(c *schema.LDAPAuthProvider) string {
+	if c.UsernameAttribute != "" {
+		return c.UsernameAttribute
+	}
+	return "uid"
+}
+
+func emailAttribute(c *schema.LDAPAuthProvider) string {
+	if c.EmailAttribute != "" {
+		return c.EmailAttribute
+	}
+	return "mail"
+}
+
+func displayNameAttribute(c *schema.LDAPAuthProvider) string {
+	if c.DisplayNameAttribute != "" {
+		return c.DisplayNameAttribute
+	}
+	return "cn"
+}
+
+func groupMemberAttribute(c *schema.LDAPAuthProvider) string {
+	if c.GroupMemberAttribute != "" {
+		return c.GroupMemberAttribute
+	}
+	return "member"
+}
+
+func groupNameAttribute(c *schema.LDAPAuthProvider) string {
+	if c.GroupNameAttribute != "" {
+		return c.GroupNameAttribute
+	}
+	return "cn"
+}
@@ -0,0 +1,20 @@
+package ldap
+
+import (
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+)
+
+// Watch for configuration changes related to the LDAP auth providers.
+func init() {
+	go func() {
+		conf.Watch(func() {
+			ps, _ := parseConfig(conf.Get())
+			if len(ps) == 0 {
+				providers.Update(pkgName, nil)
+				return
+			}
+			providers.Update(pkgName, ps)
+		})
+	}()
+}
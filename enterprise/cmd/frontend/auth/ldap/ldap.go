@@ -0,0 +1,178 @@
+package ldap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+	ldapv3 "gopkg.in/ldap.v3"
+)
+
+// errInvalidCredentials is returned when the username or password is incorrect. It intentionally
+// doesn't distinguish between the two cases.
+var errInvalidCredentials = errors.New("invalid username or password")
+
+// conn is the subset of *ldapv3.Conn that is used (so that it can be mocked in tests).
+type conn interface {
+	Bind(username, password string) error
+	Search(req *ldapv3.SearchRequest) (*ldapv3.SearchResult, error)
+	Close()
+}
+
+const timeout = 10 * time.Second
+
+// dial connects to the LDAP server and binds as the service account (or anonymously if no bindDN
+// is configured). It is a variable so that it can be mocked in tests.
+var dial = func(c *schema.LDAPAuthProvider) (conn, error) {
+	u, err := url.Parse(c.Url)
+	if err != nil {
+		return nil, err
+	}
+	host, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		host, port = u.Host, "389"
+		if u.Scheme == "ldaps" {
+			port = "636"
+		}
+	}
+	addr := net.JoinHostPort(host, port)
+
+	tlsConfig := &tls.Config{ServerName: host, InsecureSkipVerify: c.InsecureSkipVerify}
+	if c.Certificate != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(c.Certificate)) {
+			return nil, errors.New("invalid certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	var lc *ldapv3.Conn
+	if u.Scheme == "ldaps" {
+		lc, err = ldapv3.DialTLS("tcp", addr, tlsConfig)
+	} else {
+		lc, err = ldapv3.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	lc.SetTimeout(timeout)
+	if c.StartTLS {
+		if err := lc.StartTLS(tlsConfig); err != nil {
+			lc.Close()
+			return nil, err
+		}
+	}
+	if err := bindServiceAccount(lc, c); err != nil {
+		lc.Close()
+		return nil, err
+	}
+	return lc, nil
+}
+
+func bindServiceAccount(lc conn, c *schema.LDAPAuthProvider) error {
+	if c.BindDN == "" {
+		return nil // anonymous
+	}
+	if err := lc.Bind(c.BindDN, c.BindPassword); err != nil {
+		return fmt.Errorf("binding as service account %q: %s", c.BindDN, err)
+	}
+	return nil
+}
+
+// ldapUser is a user in the LDAP directory.
+type ldapUser struct {
+	DN          string   `json:"dn"`
+	Username    string   `json:"username"`
+	Email       string   `json:"email,omitempty"`
+	DisplayName string   `json:"displayName,omitempty"`
+	Groups      []string `json:"groups,omitempty"`
+}
+
+// authenticate looks up the user with the given username in the LDAP directory and verifies the
+// password by binding as the user. If groupBaseDN is configured, it also looks up the names of the
+// groups that the user is a member of.
+func authenticate(c *schema.LDAPAuthProvider, username, password string) (*ldapUser, error) {
+	// 🚨 SECURITY: Many LDAP servers treat a bind with an empty password as an unauthenticated bind,
+	// which succeeds for any DN.
+	if username == "" || password == "" {
+		return nil, errInvalidCredentials
+	}
+
+	lc, err := dial(c)
+	if err != nil {
+		return nil, err
+	}
+	defer lc.Close()
+
+	// 🚨 SECURITY: The username must be escaped to prevent LDAP filter injection.
+	filter := fmt.Sprintf("(&%s(%s=%s))", userFilter(c), usernameAttribute(c), ldapv3.EscapeFilter(username))
+	res, err := lc.Search(ldapv3.NewSearchRequest(
+		c.UserBaseDN, ldapv3.ScopeWholeSubtree, ldapv3.NeverDerefAliases, 2, int(timeout/time.Second), false,
+		filter,
+		[]string{usernameAttribute(c), emailAttribute(c), displayNameAttribute(c)},
+		nil,
+	))
+	if err != nil && !ldapv3.IsErrorWithCode(err, ldapv3.LDAPResultSizeLimitExceeded) {
+		return nil, fmt.Errorf("searching for user: %s", err)
+	}
+	if res == nil || len(res.Entries) != 1 {
+		// 🚨 SECURITY: Refuse to guess which entry is meant when the username is ambiguous.
+		return nil, errInvalidCredentials
+	}
+	entry := res.Entries[0]
+
+	if err := lc.Bind(entry.DN, password); err != nil {
+		if ldapv3.IsErrorWithCode(err, ldapv3.LDAPResultInvalidCredentials) {
+			return nil, errInvalidCredentials
+		}
+		return nil, fmt.Errorf("binding as user: %s", err)
+	}
+
+	user := &ldapUser{
+		DN:          entry.DN,
+		Username:    entry.GetAttributeValue(usernameAttribute(c)),
+		Email:       entry.GetAttributeValue(emailAttribute(c)),
+		DisplayName: entry.GetAttributeValue(displayNameAttribute(c)),
+	}
+	if user.Username == "" {
+		user.Username = username
+	}
+
+	if c.GroupBaseDN != "" {
+		// The user may not be allowed to search for groups, so search as the service account.
+		if err := bindServiceAccount(lc, c); err != nil {
+			return nil, err
+		}
+		if user.Groups, err = groups(lc, c, entry.DN); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// groups returns the names of the groups under groupBaseDN that the user with the given DN is a
+// member of.
+func groups(lc conn, c *schema.LDAPAuthProvider, userDN string) ([]string, error) {
+	filter := fmt.Sprintf("(%s=%s)", groupMemberAttribute(c), ldapv3.EscapeFilter(userDN))
+	res, err := lc.Search(ldapv3.NewSearchRequest(
+		c.GroupBaseDN, ldapv3.ScopeWholeSubtree, ldapv3.NeverDerefAliases, 0, int(timeout/time.Second), false,
+		filter,
+		[]string{groupNameAttribute(c)},
+		nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("searching for groups: %s", err)
+	}
+	var names []string
+	for _, entry := range res.Entries {
+		if name := entry.GetAttributeValue(groupNameAttribute(c)); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
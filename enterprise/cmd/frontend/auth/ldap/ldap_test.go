@@ -0,0 +1,181 @@
+package ldap
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+	ldapv3 "gopkg.in/ldap.v3"
+)
+
+// mockConn is an in-memory directory with a service account and users with the given passwords.
+type mockConn struct {
+	passwords map[string]string // DN -> password
+	entries   []*ldapv3.Entry
+	groups    []*ldapv3.Entry
+
+	bound   string
+	filters []string
+}
+
+func (c *mockConn) Bind(username, password string) error {
+	if p, ok := c.passwords[username]; !ok || p != password {
+		return ldapv3.NewError(ldapv3.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+	}
+	c.bound = username
+	return nil
+}
+
+func (c *mockConn) Search(req *ldapv3.SearchRequest) (*ldapv3.SearchResult, error) {
+	c.filters = append(c.filters, req.Filter)
+	switch req.BaseDN {
+	case "ou=people,dc=example,dc=com":
+		var entries []*ldapv3.Entry
+		for _, e := range c.entries {
+			if req.Filter == "(&(objectClass=person)(uid="+e.GetAttributeValue("uid")+"))" {
+				entries = append(entries, e)
+			}
+		}
+		return &ldapv3.SearchResult{Entries: entries}, nil
+	case "ou=groups,dc=example,dc=com":
+		if c.bound != "cn=sourcegraph,dc=example,dc=com" {
+			return nil, errors.New("insufficient access")
+		}
+		return &ldapv3.SearchResult{Entries: c.groups}, nil
+	}
+	return &ldapv3.SearchResult{}, nil
+}
+
+func (c *mockConn) Close() {}
+
+func TestAuthenticate(t *testing.T) {
+	config := schema.LDAPAuthProvider{
+		Type:         "ldap",
+		Url:          "ldap://ldap.example.com",
+		BindDN:       "cn=sourcegraph,dc=example,dc=com",
+		BindPassword: "s3cret",
+		UserBaseDN:   "ou=people,dc=example,dc=com",
+		GroupBaseDN:  "ou=groups,dc=example,dc=com",
+	}
+	newConn := func() *mockConn {
+		return &mockConn{
+			passwords: map[string]string{
+				"cn=sourcegraph,dc=example,dc=com":      "s3cret",
+				"uid=alice,ou=people,dc=example,dc=com": "pw",
+			},
+			entries: []*ldapv3.Entry{
+				ldapv3.NewEntry("uid=alice,ou=people,dc=example,dc=com", map[string][]string{
+					"uid":  {"alice"},
+					"mail": {"alice@example.com"},
+					"cn":   {"Alice Smith"},
+				}),
+			},
+			groups: []*ldapv3.Entry{
+				ldapv3.NewEntry("cn=eng,ou=groups,dc=example,dc=com", map[string][]string{"cn": {"eng"}}),
+			},
+		}
+	}
+	var lc *mockConn
+	dial = func(c *schema.LDAPAuthProvider) (conn, error) {
+		lc = newConn()
+		return lc, bindServiceAccount(lc, c)
+	}
+	defer func(orig func(*schema.LDAPAuthProvider) (conn, error)) { dial = orig }(dial)
+
+	t.Run("valid credentials", func(t *testing.T) {
+		user, err := authenticate(&config, "alice", "pw")
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := &ldapUser{
+			DN:          "uid=alice,ou=people,dc=example,dc=com",
+			Username:    "alice",
+			Email:       "alice@example.com",
+			DisplayName: "Alice Smith",
+			Groups:      []string{"eng"},
+		}
+		if !reflect.DeepEqual(user, want) {
+			t.Errorf("got user %+v, want %+v", user, want)
+		}
+		if want := `(member=uid=alice,ou=people,dc=example,dc=com)`; lc.filters[1] != want {
+			t.Errorf("got group filter %q, want %q", lc.filters[1], want)
+		}
+	})
+
+	for name, test := range map[string]struct{ username, password string }{
+		"wrong password": {"alice", "x"},
+		"empty password": {"alice", ""},
+		"unknown user":   {"bob", "pw"},
+		"injection":      {"*", "pw"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := authenticate(&config, test.username, test.password); err != errInvalidCredentials {
+				t.Errorf("got error %v, want %v", err, errInvalidCredentials)
+			}
+		})
+	}
+
+	t.Run("filter is escaped", func(t *testing.T) {
+		_, _ = authenticate(&config, "a*)(uid=*", "pw")
+		if want := `(&(objectClass=person)(uid=a\2a\29\28uid=\2a))`; len(lc.filters) != 1 || lc.filters[0] != want {
+			t.Errorf("got filters %q, want [%q]", lc.filters, want)
+		}
+	})
+}
+
+func TestParseConfig(t *testing.T) {
+	valid := schema.LDAPAuthProvider{Type: "ldap", Url: "ldaps://ldap.example.com", UserBaseDN: "dc=example,dc=com"}
+	tests := map[string]struct {
+		providers     []schema.LDAPAuthProvider
+		wantProviders int
+		wantProblems  []string
+	}{
+		"valid": {
+			providers:     []schema.LDAPAuthProvider{valid},
+			wantProviders: 1,
+		},
+		"duplicate": {
+			providers:     []schema.LDAPAuthProvider{valid, valid},
+			wantProviders: 1,
+			wantProblems:  []string{"LDAP auth provider at index 1 is duplicate of index 0, ignoring"},
+		},
+		"bad scheme": {
+			providers:    []schema.LDAPAuthProvider{{Type: "ldap", Url: "https://ldap.example.com", UserBaseDN: "dc=example,dc=com"}},
+			wantProblems: []string{`LDAP auth provider at index 0: url "https://ldap.example.com" must have the ldap:// or ldaps:// scheme`},
+		},
+		"startTLS with ldaps and groupOrgMap without groupBaseDN": {
+			providers: []schema.LDAPAuthProvider{{
+				Type:        "ldap",
+				Url:         "ldaps://ldap.example.com",
+				UserBaseDN:  "dc=example,dc=com",
+				StartTLS:    true,
+				GroupOrgMap: map[string][]string{"eng": {"engineering"}},
+			}},
+			wantProblems: []string{
+				"LDAP auth provider at index 0: startTLS can't be used with an ldaps:// url, which already uses TLS",
+				"LDAP auth provider at index 0: groupOrgMap requires groupBaseDN to be set",
+			},
+		},
+		"invalid userFilter": {
+			providers:    []schema.LDAPAuthProvider{{Type: "ldap", Url: "ldap://ldap.example.com", UserBaseDN: "dc=example,dc=com", UserFilter: "objectClass=person"}},
+			wantProblems: []string{`LDAP auth provider at index 0: invalid userFilter "objectClass=person": LDAP Result Code 201 "Filter Compile Error": ldap: filter does not start with an '('`},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var cfg conf.Unified
+			for i := range test.providers {
+				cfg.Critical.AuthProviders = append(cfg.Critical.AuthProviders, schema.AuthProviders{Ldap: &test.providers[i]})
+			}
+			ps, problems := parseConfig(&cfg)
+			if len(ps) != test.wantProviders {
+				t.Errorf("got %d providers, want %d", len(ps), test.wantProviders)
+			}
+			if !reflect.DeepEqual(problems, test.wantProblems) {
+				t.Errorf("got problems %q, want %q", problems, test.wantProblems)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// Package ldap implements auth via an LDAP directory (such as Active Directory or OpenLDAP).
+package ldap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/external/session"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+	"github.com/sourcegraph/sourcegraph/schema"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// All LDAP endpoints are under this path prefix.
+const authPrefix = auth.AuthURLPrefix + "/ldap"
+
+// Middleware is middleware for LDAP authentication, adding the sign-in endpoint under the auth
+// path prefix ("/.auth/ldap/sign-in"). Unlike the SSO auth providers, the user enters their LDAP
+// username and password in the Sourcegraph sign-in form, and Sourcegraph verifies them against the
+// directory.
+//
+// 🚨 SECURITY
+var Middleware = &auth.Middleware{
+	API: func(next http.Handler) http.Handler { return next },
+	App: func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == authPrefix+"/sign-in" {
+				handleSignIn(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	},
+}
+
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func handleSignIn(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, fmt.Sprintf("Unsupported method %s", r.Method), http.StatusBadRequest)
+		return
+	}
+	p, ok := providers.GetProviderByConfigID(providers.ConfigID{Type: providerType, ID: r.URL.Query().Get("pc")}).(*provider)
+	if !ok {
+		http.Error(w, "Misconfigured LDAP auth provider.", http.StatusNotFound)
+		return
+	}
+	var creds credentials
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		http.Error(w, "Could not decode request body", http.StatusBadRequest)
+		return
+	}
+
+	user, err := authenticate(&p.config, creds.Username, creds.Password)
+	if err == errInvalidCredentials {
+		log15.Info("LDAP authentication failed.", "username", creds.Username)
+		http.Error(w, "Authentication failed", http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		log15.Error("Error authenticating with LDAP.", "url", p.config.Url, "username", creds.Username, "err", err)
+		http.Error(w, "Error communicating with the LDAP server. Site admins may check the logs for more information.", http.StatusBadGateway)
+		return
+	}
+
+	userID, safeErrMsg, err := getOrCreateUser(r.Context(), &p.config, user)
+	if err != nil {
+		log15.Error("Error looking up LDAP-authenticated user.", "username", user.Username, "err", err, "userErr", safeErrMsg)
+		http.Error(w, safeErrMsg, http.StatusForbidden)
+		return
+	}
+	if err := syncOrgMemberships(r.Context(), &p.config, userID, user.Groups); err != nil {
+		// Don't prevent the user from signing in because of a failure to sync organizations.
+		log15.Error("Error syncing organization memberships from LDAP groups.", "userID", userID, "err", err)
+	}
+
+	if err := session.SetActor(w, r, &actor.Actor{UID: userID}, 0); err != nil {
+		log15.Error("Error creating session for LDAP-authenticated user.", "userID", userID, "err", err)
+		http.Error(w, "Could not create new user session", http.StatusInternalServerError)
+		return
+	}
+}
+
+// getOrCreateUser gets or creates the Sourcegraph user for the LDAP user. It returns the user ID if
+// successful; otherwise it returns a friendly error message (safeErrMsg) that is safe to display to
+// users, and a non-nil err with lower-level error details.
+func getOrCreateUser(ctx context.Context, c *schema.LDAPAuthProvider, user *ldapUser) (userID int32, safeErrMsg string, err error) {
+	var data extsvc.ExternalAccountData
+	data.SetAccountData(user)
+
+	username, err := auth.NormalizeUsername(user.Username)
+	if err != nil {
+		return 0, fmt.Sprintf("Error normalizing the username %q. See https://docs.sourcegraph.com/admin/auth/#username-normalization.", user.Username), err
+	}
+
+	return auth.GetAndSaveUser(ctx, auth.GetAndSaveUserOp{
+		UserProps: db.NewUser{
+			Username:        username,
+			Email:           user.Email,
+			EmailIsVerified: user.Email != "", // emails in the directory are assumed to be verified
+			DisplayName:     user.DisplayName,
+		},
+		ExternalAccount: extsvc.ExternalAccountSpec{
+			ServiceType: providerType,
+			ServiceID:   c.Url,
+			ClientID:    c.UserBaseDN,
+			// Store the unnormalized username, so that distinct directory users are never merged.
+			AccountID: user.Username,
+		},
+		ExternalAccountData: data,
+		CreateIfNotExist:    c.AllowSignup,
+	})
+}
+
+// syncOrgMemberships adds the user to the organizations that their LDAP groups map to in
+// groupOrgMap, and removes them from the other organizations in groupOrgMap. Memberships in
+// organizations that don't appear in groupOrgMap are left unchanged.
+func syncOrgMemberships(ctx context.Context, c *schema.LDAPAuthProvider, userID int32, groups []string) error {
+	if len(c.GroupOrgMap) == 0 {
+		return nil
+	}
+	want := map[string]bool{}
+	for _, group := range groups {
+		for _, org := range c.GroupOrgMap[group] {
+			want[org] = true
+		}
+	}
+	managed := map[string]bool{}
+	for _, orgs := range c.GroupOrgMap {
+		for _, org := range orgs {
+			managed[org] = true
+		}
+	}
+
+	for name := range managed {
+		org, err := db.Orgs.GetByName(ctx, name)
+		if _, ok := err.(*db.OrgNotFoundError); ok {
+			log15.Warn("Organization in LDAP groupOrgMap does not exist.", "org", name)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		_, err = db.OrgMembers.GetByOrgIDAndUserID(ctx, org.ID, userID)
+		isMember := err == nil
+		if err != nil && !errcode.IsNotFound(err) {
+			return err
+		}
+		switch {
+		case want[name] && !isMember:
+			if _, err := db.OrgMembers.Create(ctx, org.ID, userID); err != nil {
+				return err
+			}
+		case !want[name] && isMember:
+			if err := db.OrgMembers.Remove(ctx, org.ID, userID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package ldap
+
+import (
+	"context"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type provider struct {
+	config schema.LDAPAuthProvider
+}
+
+// ConfigID implements providers.Provider.
+func (p *provider) ConfigID() providers.ConfigID {
+	return providers.ConfigID{Type: providerType, ID: providerConfigID(&p.config)}
+}
+
+// Config implements providers.Provider.
+func (p *provider) Config() schema.AuthProviders { return schema.AuthProviders{Ldap: &p.config} }
+
+// Refresh implements providers.Provider.
+func (p *provider) Refresh(context.Context) error { return nil }
+
+// CachedInfo implements providers.Provider.
+func (p *provider) CachedInfo() *providers.Info {
+	displayName := p.config.DisplayName
+	if displayName == "" {
+		displayName = "LDAP"
+	}
+	return &providers.Info{
+		ServiceID:         p.config.Url,
+		ClientID:          p.config.UserBaseDN,
+		DisplayName:       displayName,
+		AuthenticationURL: authPrefix + "/sign-in?pc=" + providerConfigID(&p.config),
+	}
+}
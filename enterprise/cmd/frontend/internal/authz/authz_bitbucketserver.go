@@ -0,0 +1,115 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	permbbs "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func bitbucketServerProviders(
+	ctx context.Context,
+	cfg *conf.Unified,
+	bitbucketServers []*schema.BitbucketServerConnection,
+) (
+	authzProviders []authz.Provider,
+	seriousProblems []string,
+	warnings []string,
+) {
+	for _, bbs := range bitbucketServers {
+		p, err := bitbucketServerProvider(bbs.Authorization, bbs.Url, cfg.Critical.AuthProviders)
+		if err != nil {
+			seriousProblems = append(seriousProblems, err.Error())
+			continue
+		}
+		if p != nil {
+			authzProviders = append(authzProviders, p)
+		}
+	}
+	for _, provider := range authzProviders {
+		for _, problem := range provider.Validate() {
+			warnings = append(warnings, fmt.Sprintf("Bitbucket Server config for %s was invalid: %s", provider.ServiceID(), problem))
+		}
+	}
+	return authzProviders, seriousProblems, warnings
+}
+
+func bitbucketServerProvider(a *schema.BitbucketServerAuthorization, instanceURL string, ps []schema.AuthProviders) (authz.Provider, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	bbsURL, err := url.Parse(instanceURL)
+	if err != nil {
+		return nil, fmt.Errorf("Could not parse URL for Bitbucket Server instance %q: %s", instanceURL, err)
+	}
+
+	ttl, err := parseTTL(a.Ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	op := permbbs.ProviderOp{
+		BaseURL:     bbsURL,
+		ConsumerKey: a.Oauth.ConsumerKey,
+		SigningKey:  a.Oauth.SigningKey,
+		CacheTTL:    ttl,
+	}
+	switch idp := a.IdentityProvider; idp.Type {
+	case "username":
+		op.UseNativeUsername = true
+	case "external":
+		if idp.AuthProviderType == "" || idp.AuthProviderID == "" {
+			return nil, fmt.Errorf(`The "authProviderType" and "authProviderID" of the identityProvider are required if its type is "external"`)
+		}
+		if !authProviderExists(ps, idp.AuthProviderType, idp.AuthProviderID) {
+			return nil, fmt.Errorf("Did not find authentication provider matching type %s and configID %s", idp.AuthProviderType, idp.AuthProviderID)
+		}
+		op.AuthnConfigID = providers.ConfigID{Type: idp.AuthProviderType, ID: idp.AuthProviderID}
+	default:
+		return nil, fmt.Errorf("No identityProvider was specified")
+	}
+
+	p, err := NewBitbucketServerProvider(op)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid OAuth configuration for Bitbucket Server instance %q: %s", instanceURL, err)
+	}
+	return p, nil
+}
+
+// authProviderExists reports whether there is an authentication provider with the given type and
+// configID. Like for GitLab, only SAML and OpenID Connect authentication providers (which have a
+// configID) are supported.
+func authProviderExists(ps []schema.AuthProviders, typ, id string) bool {
+	for _, p := range ps {
+		saml := p.Saml
+		foundMatchingSAML := saml != nil && saml.ConfigID == id && saml.Type == typ
+		oidc := p.Openidconnect
+		foundMatchingOIDC := oidc != nil && oidc.ConfigID == id && oidc.Type == typ
+		if foundMatchingSAML || foundMatchingOIDC {
+			return true
+		}
+	}
+	return false
+}
+
+// NewBitbucketServerProvider is a mockable constructor for new bitbucketserver.Provider instances.
+var NewBitbucketServerProvider = func(op permbbs.ProviderOp) (authz.Provider, error) {
+	p, err := permbbs.NewProvider(op)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// ValidateBitbucketServerAuthz validates the authorization fields of the given Bitbucket Server
+// external service config.
+func ValidateBitbucketServerAuthz(cfg *schema.BitbucketServerConnection, ps []schema.AuthProviders) error {
+	_, err := bitbucketServerProvider(cfg.Authorization, cfg.Url, ps)
+	return err
+}
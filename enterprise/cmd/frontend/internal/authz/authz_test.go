@@ -11,6 +11,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/explicit"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/gitlab"
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
@@ -324,6 +326,33 @@ func Test_providersFromConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "explicit permissions enabled (after the code host authz providers)",
+			cfg: conf.Unified{
+				SiteConfiguration: schema.SiteConfiguration{PermissionsExplicitEnabled: true},
+			},
+			gitlabConnections: []*schema.GitLabConnection{
+				{
+					Authorization: &schema.GitLabAuthorization{
+						IdentityProvider: schema.IdentityProvider{Username: &schema.UsernameIdentity{Type: "username"}},
+					},
+					Url:   "https://gitlab.mine",
+					Token: "asdf",
+				},
+			},
+			expAuthzAllowAccessByDefault: true,
+			expAuthzProviders: []authz.Provider{
+				gitlabAuthzProviderParams{
+					SudoOp: gitlab.SudoProviderOp{
+						BaseURL:           mustURLParse(t, "https://gitlab.mine"),
+						SudoToken:         "asdf",
+						CacheTTL:          3 * time.Hour,
+						UseNativeUsername: true,
+					},
+				},
+				explicit.NewProvider(),
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -360,8 +389,9 @@ func asJSON(t *testing.T, v interface{}) string {
 }
 
 type fakeStore struct {
-	gitlabs []*schema.GitLabConnection
-	githubs []*schema.GitHubConnection
+	gitlabs          []*schema.GitLabConnection
+	githubs          []*schema.GitHubConnection
+	bitbucketServers []*schema.BitbucketServerConnection
 }
 
 func (s fakeStore) ListGitHubConnections(context.Context) ([]*schema.GitHubConnection, error) {
@@ -371,3 +401,76 @@ func (s fakeStore) ListGitHubConnections(context.Context) ([]*schema.GitHubConne
 func (s fakeStore) ListGitLabConnections(context.Context) ([]*schema.GitLabConnection, error) {
 	return s.gitlabs, nil
 }
+
+func (s fakeStore) ListBitbucketServerConnections(context.Context) ([]*schema.BitbucketServerConnection, error) {
+	return s.bitbucketServers, nil
+}
+
+func Test_bitbucketServerProvider(t *testing.T) {
+	defer func(orig func(bitbucketserver.ProviderOp) (authz.Provider, error)) { NewBitbucketServerProvider = orig }(NewBitbucketServerProvider)
+	var gotOp *bitbucketserver.ProviderOp
+	NewBitbucketServerProvider = func(op bitbucketserver.ProviderOp) (authz.Provider, error) {
+		gotOp = &op
+		return gitlabAuthzProviderParams{}, nil
+	}
+
+	authProviders := []schema.AuthProviders{
+		{HttpHeader: &schema.HTTPHeaderAuthProvider{Type: "http-header", UsernameHeader: "X-User"}},
+		{Saml: &schema.SAMLAuthProvider{Type: "saml", ConfigID: "okta"}},
+	}
+	oauth := schema.BitbucketServerOAuth{ConsumerKey: "sourcegraph", SigningKey: "key"}
+	tests := []struct {
+		description string
+		idp         schema.BitbucketServerIdentityProvider
+		wantOp      *bitbucketserver.ProviderOp
+		wantErr     string
+	}{
+		{
+			description: "username",
+			idp:         schema.BitbucketServerIdentityProvider{Type: "username"},
+			wantOp:      &bitbucketserver.ProviderOp{UseNativeUsername: true},
+		},
+		{
+			description: "external without auth provider ID",
+			idp:         schema.BitbucketServerIdentityProvider{Type: "external", AuthProviderType: "saml"},
+			wantErr:     `The "authProviderType" and "authProviderID" of the identityProvider are required if its type is "external"`,
+		},
+		{
+			description: "external http-header",
+			idp:         schema.BitbucketServerIdentityProvider{Type: "external", AuthProviderType: "http-header", AuthProviderID: "x"},
+			wantErr:     "Did not find authentication provider matching type http-header and configID x",
+		},
+		{
+			description: "external SAML",
+			idp:         schema.BitbucketServerIdentityProvider{Type: "external", AuthProviderType: "saml", AuthProviderID: "okta"},
+			wantOp:      &bitbucketserver.ProviderOp{AuthnConfigID: providers.ConfigID{Type: "saml", ID: "okta"}},
+		},
+		{
+			description: "external with no matching auth provider",
+			idp:         schema.BitbucketServerIdentityProvider{Type: "external", AuthProviderType: "saml", AuthProviderID: "onelogin"},
+			wantErr:     "Did not find authentication provider matching type saml and configID onelogin",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			gotOp = nil
+			_, err := bitbucketServerProvider(&schema.BitbucketServerAuthorization{IdentityProvider: test.idp, Oauth: oauth, Ttl: "1h"}, "https://bitbucket.mine", authProviders)
+			if test.wantErr != "" {
+				if err == nil || err.Error() != test.wantErr {
+					t.Fatalf("got error %v, want %q", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			test.wantOp.BaseURL = mustURLParse(t, "https://bitbucket.mine")
+			test.wantOp.ConsumerKey = "sourcegraph"
+			test.wantOp.SigningKey = "key"
+			test.wantOp.CacheTTL = time.Hour
+			if !reflect.DeepEqual(gotOp, test.wantOp) {
+				t.Errorf("got provider op %+v, want %+v", gotOp, test.wantOp)
+			}
+		})
+	}
+}
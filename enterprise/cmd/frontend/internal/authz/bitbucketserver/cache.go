@@ -0,0 +1,55 @@
+package bitbucketserver
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, b []byte)
+	Delete(key string)
+}
+
+// userReposCacheKey returns the key for caching the IDs of the repositories that the given
+// Bitbucket Server user can read. An empty username refers to unauthenticated users, who can only
+// read public repositories.
+func userReposCacheKey(username string) string {
+	if username == "" {
+		return "public"
+	}
+	return "user:" + username
+}
+
+type userReposCacheVal struct {
+	// RepoIDs are the Bitbucket Server IDs of the repositories that the user can read.
+	RepoIDs []string
+
+	TTL time.Duration
+}
+
+func cacheGetUserRepos(c cache, username string, ttl time.Duration) (v userReposCacheVal, exists bool) {
+	k := userReposCacheKey(username)
+	b, exists := c.Get(k)
+	if !exists {
+		return userReposCacheVal{}, false
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		c.Delete(k)
+		return userReposCacheVal{}, false
+	}
+	if v.TTL != ttl {
+		c.Delete(k)
+		return userReposCacheVal{}, false
+	}
+	return v, true
+}
+
+func cacheSetUserRepos(c cache, username string, v userReposCacheVal) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	c.Set(userReposCacheKey(username), b)
+	return nil
+}
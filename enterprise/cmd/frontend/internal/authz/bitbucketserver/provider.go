@@ -0,0 +1,272 @@
+// Package bitbucketserver contains an authorization provider for Bitbucket Server.
+package bitbucketserver
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/pkg/ratelimit"
+	"github.com/sourcegraph/sourcegraph/pkg/rcache"
+)
+
+// Provider is an implementation of authz.Provider that provides repository permissions as
+// determined from a Bitbucket Server instance. It lists the repositories that a user can read on
+// behalf of the user (with 2-legged OAuth with user impersonation), so it requires an Application
+// Link for Sourcegraph in Bitbucket Server. For documentation of specific fields, see the
+// docstrings of ProviderOp.
+type Provider struct {
+	client            *bitbucketserver.Client
+	codeHost          *bitbucketserver.CodeHost
+	authnConfigID     providers.ConfigID
+	useNativeUsername bool
+	cache             cache
+	cacheTTL          time.Duration
+}
+
+var _ authz.Provider = ((*Provider)(nil))
+
+type ProviderOp struct {
+	// BaseURL is the URL of the Bitbucket Server instance.
+	BaseURL *url.URL
+
+	// ConsumerKey is the OAuth consumer key of the Application Link's incoming authentication.
+	ConsumerKey string
+
+	// SigningKey is the base64-encoded PEM RSA private key of the OAuth consumer.
+	//
+	// 🚨 SECURITY: This value contains secret information that must not be shown to non-site-admins.
+	SigningKey string
+
+	// AuthnConfigID identifies the authn provider whose external accounts' IDs are the Bitbucket
+	// Server usernames of users. It is required (including AuthnConfigID.ID) unless
+	// UseNativeUsername is true, because the external accounts of other authn providers of the same
+	// type may have the same IDs but belong to other people.
+	AuthnConfigID providers.ConfigID
+
+	// UseNativeUsername, if true, maps Sourcegraph users to Bitbucket Server users using username
+	// equivalency instead of the authn provider's external accounts. This is insecure if users can
+	// change their Sourcegraph username.
+	UseNativeUsername bool
+
+	// CacheTTL is the TTL of cached permissions lists from the Bitbucket Server API.
+	CacheTTL time.Duration
+
+	// MockCache, if non-nil, replaces the default Redis-based cache with the supplied cache mock.
+	// Should only be used in tests.
+	MockCache cache
+}
+
+// NewProvider returns a new Bitbucket Server authz provider. It returns an error if the OAuth
+// signing key is invalid.
+func NewProvider(op ProviderOp) (*Provider, error) {
+	client := bitbucketserver.NewClient(op.BaseURL, ratelimit.BudgetMiddleware("authz")(http.DefaultClient))
+	if err := client.SetOAuth(op.ConsumerKey, op.SigningKey); err != nil {
+		return nil, err
+	}
+
+	p := &Provider{
+		client:            client,
+		codeHost:          bitbucketserver.NewCodeHost(op.BaseURL),
+		authnConfigID:     op.AuthnConfigID,
+		useNativeUsername: op.UseNativeUsername,
+		cache:             op.MockCache,
+		cacheTTL:          op.CacheTTL,
+	}
+	if p.cache == nil {
+		p.cache = rcache.NewWithTTL(fmt.Sprintf("bitbucketServerAuthz:%s", op.BaseURL.String()), int(math.Ceil(op.CacheTTL.Seconds())))
+	}
+	return p, nil
+}
+
+func (p *Provider) Validate() (problems []string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, _, err := p.client.Repos(ctx, &bitbucketserver.PageToken{Limit: 1}); err != nil {
+		if ctx.Err() != nil {
+			problems = append(problems, fmt.Sprintf("Bitbucket Server API did not respond within 5s (%s)", err.Error()))
+		} else {
+			problems = append(problems, fmt.Sprintf("OAuth request to the Bitbucket Server API failed (check the consumer key and the Application Link's incoming authentication): %s", err))
+		}
+	}
+	return problems
+}
+
+func (p *Provider) ServiceID() string {
+	return p.codeHost.ServiceID()
+}
+
+func (p *Provider) ServiceType() string {
+	return p.codeHost.ServiceType()
+}
+
+func (p *Provider) Repos(ctx context.Context, repos map[authz.Repo]struct{}) (mine map[authz.Repo]struct{}, others map[authz.Repo]struct{}) {
+	return authz.GetCodeHostRepos(p.codeHost, repos)
+}
+
+// RepoPerms implements the authz.Provider interface. It fetches (and caches) the full list of
+// repositories that the user can read, because Bitbucket Server has no API to check a user's
+// permissions on a repository.
+func (p *Provider) RepoPerms(ctx context.Context, account *extsvc.ExternalAccount, repos map[authz.Repo]struct{}) (map[api.RepoName]map[authz.Perm]bool, error) {
+	mine, _ := p.Repos(ctx, repos)
+	if len(mine) == 0 {
+		return nil, nil
+	}
+
+	username := "" // empty means unauthenticated to the code host
+	if account != nil && account.ServiceID == p.codeHost.ServiceID() && account.ServiceType == p.codeHost.ServiceType() {
+		username = account.AccountID
+	}
+
+	readable, err := p.readableRepoIDs(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	perms := make(map[api.RepoName]map[authz.Perm]bool, len(mine))
+	for repo := range mine {
+		_, canRead := readable[repo.ExternalRepoSpec.ID]
+		perms[repo.RepoName] = map[authz.Perm]bool{authz.Read: canRead}
+	}
+	return perms, nil
+}
+
+// readableRepoIDs returns the set of IDs of the repositories that the Bitbucket Server user with
+// the given username can read (or of the public repositories, if username is empty). It consults
+// and updates the cache.
+func (p *Provider) readableRepoIDs(ctx context.Context, username string) (map[string]struct{}, error) {
+	v, exists := cacheGetUserRepos(p.cache, username, p.cacheTTL)
+	if !exists {
+		repoIDs, err := p.fetchReadableRepoIDs(ctx, username)
+		if err != nil {
+			return nil, err
+		}
+		v = userReposCacheVal{RepoIDs: repoIDs, TTL: p.cacheTTL}
+		if err := cacheSetUserRepos(p.cache, username, v); err != nil {
+			return nil, err
+		}
+	}
+
+	readable := make(map[string]struct{}, len(v.RepoIDs))
+	for _, id := range v.RepoIDs {
+		readable[id] = struct{}{}
+	}
+	return readable, nil
+}
+
+// fetchReadableRepoIDs lists the IDs of the repositories that the Bitbucket Server user with the
+// given username can read (or of the public repositories, if username is empty) from the Bitbucket
+// Server API.
+func (p *Provider) fetchReadableRepoIDs(ctx context.Context, username string) ([]string, error) {
+	client, query := p.client, "visibility=public"
+	if username != "" {
+		var err error
+		if client, err = p.client.Sudo(username); err != nil {
+			return nil, err
+		}
+		query = "permission=REPO_READ"
+	}
+
+	repoIDs := []string{}
+	for t := (&bitbucketserver.PageToken{Limit: 1000}); ; {
+		repos, next, err := client.Repos(ctx, t, query)
+		if err != nil {
+			return nil, err
+		}
+		for _, repo := range repos {
+			repoIDs = append(repoIDs, strconv.Itoa(repo.ID))
+		}
+		if next == nil || !next.HasMore() {
+			return repoIDs, nil
+		}
+		t = next
+	}
+}
+
+// FetchAccount implements the authz.Provider interface. It determines the Bitbucket Server username
+// of the user (from their Sourcegraph username or from their external account of the authn
+// provider specified in the ProviderOp) and fetches the Bitbucket Server user with that username.
+func (p *Provider) FetchAccount(ctx context.Context, user *types.User, current []*extsvc.ExternalAccount) (mine *extsvc.ExternalAccount, err error) {
+	if user == nil {
+		return nil, nil
+	}
+
+	var username string
+	if p.useNativeUsername {
+		username = user.Username
+	} else {
+		serviceID, ok := p.authnServiceID()
+		if !ok {
+			return nil, nil
+		}
+		for _, acct := range current {
+			if acct.ServiceType == p.authnConfigID.Type && acct.ServiceID == serviceID {
+				username = acct.AccountID
+				break
+			}
+		}
+	}
+	if username == "" {
+		return nil, nil
+	}
+
+	bbUser, err := p.fetchUser(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if bbUser == nil {
+		return nil, nil
+	}
+
+	var accountData extsvc.ExternalAccountData
+	accountData.SetAccountData(bbUser)
+	return &extsvc.ExternalAccount{
+		UserID: user.ID,
+		ExternalAccountSpec: extsvc.ExternalAccountSpec{
+			ServiceType: p.codeHost.ServiceType(),
+			ServiceID:   p.codeHost.ServiceID(),
+			AccountID:   bbUser.Name,
+		},
+		ExternalAccountData: accountData,
+	}, nil
+}
+
+// authnServiceID returns the service ID of the external accounts of the authn provider specified by
+// p.authnConfigID. It returns false if the authn provider doesn't exist.
+func (p *Provider) authnServiceID() (string, bool) {
+	if p.authnConfigID.ID == "" {
+		return "", false
+	}
+	authnProvider := getProviderByConfigID(p.authnConfigID)
+	if authnProvider == nil {
+		return "", false
+	}
+	return authnProvider.CachedInfo().ServiceID, true
+}
+
+// fetchUser returns the Bitbucket Server user with the given username, or nil if there is none.
+func (p *Provider) fetchUser(ctx context.Context, username string) (*bitbucketserver.User, error) {
+	users, _, err := p.client.Users(ctx, &bitbucketserver.PageToken{Limit: 1000}, username)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		// Bitbucket Server usernames are case-insensitive.
+		if strings.EqualFold(u.Name, username) {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+var getProviderByConfigID = providers.GetProviderByConfigID
@@ -0,0 +1,242 @@
+package bitbucketserver
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth/providers"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+type mockCache map[string]string
+
+func (m mockCache) Get(key string) ([]byte, bool) {
+	v, ok := m[key]
+	return []byte(v), ok
+}
+func (m mockCache) Set(key string, b []byte) { m[key] = string(b) }
+func (m mockCache) Delete(key string)        { delete(m, key) }
+
+// newTestServer returns a fake Bitbucket Server on which the user "alice" can read the repositories
+// with IDs 1, 2 and 3, of which only 1 is public.
+func newTestServer(t *testing.T, requests *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); !strings.HasPrefix(auth, "OAuth ") || !strings.Contains(auth, `oauth_consumer_key="sourcegraph"`) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		q := r.URL.Query()
+		*requests = append(*requests, r.URL.Path+"?"+r.URL.RawQuery)
+
+		type repo struct {
+			ID int `json:"id"`
+		}
+		var resp interface{}
+		switch {
+		case r.URL.Path == "/rest/api/1.0/repos" && q.Get("user_id") == "alice" && q.Get("permission") == "REPO_READ":
+			// Return the repositories in 2 pages.
+			if q.Get("start") == "" {
+				resp = map[string]interface{}{"values": []repo{{1}, {2}}, "isLastPage": false, "nextPageStart": 2, "limit": 1000}
+			} else {
+				resp = map[string]interface{}{"values": []repo{{3}}, "isLastPage": true}
+			}
+		case r.URL.Path == "/rest/api/1.0/repos" && q.Get("user_id") == "" && q.Get("visibility") == "public":
+			resp = map[string]interface{}{"values": []repo{{1}}, "isLastPage": true}
+		case r.URL.Path == "/rest/api/1.0/users" && q.Get("filter") == "alice":
+			resp = map[string]interface{}{"values": []map[string]interface{}{{"name": "alicex", "id": 6}, {"name": "Alice", "id": 7}}, "isLastPage": true}
+		case r.URL.Path == "/rest/api/1.0/users":
+			resp = map[string]interface{}{"values": []interface{}{}, "isLastPage": true}
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+func newTestProvider(t *testing.T, baseURL string, op ProviderOp) *Provider {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	op.BaseURL, _ = url.Parse(baseURL)
+	op.ConsumerKey = "sourcegraph"
+	op.SigningKey = base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	op.CacheTTL = time.Hour
+	op.MockCache = mockCache{}
+	p, err := NewProvider(op)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestProvider_RepoPerms(t *testing.T) {
+	var requests []string
+	srv := newTestServer(t, &requests)
+	defer srv.Close()
+	p := newTestProvider(t, srv.URL, ProviderOp{UseNativeUsername: true})
+
+	repos := map[authz.Repo]struct{}{}
+	for _, id := range []string{"1", "2", "3", "4"} {
+		repos[authz.Repo{
+			RepoName:         api.RepoName("bitbucket.example.com/PRJ/" + id),
+			ExternalRepoSpec: api.ExternalRepoSpec{ID: id, ServiceType: "bitbucketServer", ServiceID: srv.URL + "/"},
+		}] = struct{}{}
+	}
+	repos[authz.Repo{
+		RepoName:         "gitlab.com/other",
+		ExternalRepoSpec: api.ExternalRepoSpec{ID: "1", ServiceType: "gitlab", ServiceID: "https://gitlab.com/"},
+	}] = struct{}{}
+
+	alice := &extsvc.ExternalAccount{ExternalAccountSpec: extsvc.ExternalAccountSpec{ServiceType: "bitbucketServer", ServiceID: srv.URL + "/", AccountID: "alice"}}
+	tests := []struct {
+		description string
+		account     *extsvc.ExternalAccount
+		want        map[api.RepoName]map[authz.Perm]bool
+	}{
+		{
+			description: "user",
+			account:     alice,
+			want: map[api.RepoName]map[authz.Perm]bool{
+				"bitbucket.example.com/PRJ/1": {authz.Read: true},
+				"bitbucket.example.com/PRJ/2": {authz.Read: true},
+				"bitbucket.example.com/PRJ/3": {authz.Read: true},
+				"bitbucket.example.com/PRJ/4": {authz.Read: false},
+			},
+		},
+		{
+			description: "unauthenticated",
+			want: map[api.RepoName]map[authz.Perm]bool{
+				"bitbucket.example.com/PRJ/1": {authz.Read: true},
+				"bitbucket.example.com/PRJ/2": {authz.Read: false},
+				"bitbucket.example.com/PRJ/3": {authz.Read: false},
+				"bitbucket.example.com/PRJ/4": {authz.Read: false},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			requests = nil
+			for i := 0; i < 2; i++ {
+				perms, err := p.RepoPerms(context.Background(), test.account, repos)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(perms, test.want) {
+					t.Errorf("got perms %v, want %v", perms, test.want)
+				}
+			}
+			if test.account != nil && len(requests) != 2 {
+				t.Errorf("got requests %q, want 2 requests (2 pages, then cached)", requests)
+			} else if test.account == nil && len(requests) != 1 {
+				t.Errorf("got requests %q, want 1 request (then cached)", requests)
+			}
+		})
+	}
+}
+
+func TestProvider_FetchAccount(t *testing.T) {
+	var requests []string
+	srv := newTestServer(t, &requests)
+	defer srv.Close()
+
+	wantAccount := func(userID int32) *extsvc.ExternalAccount {
+		acct := &extsvc.ExternalAccount{
+			UserID:              userID,
+			ExternalAccountSpec: extsvc.ExternalAccountSpec{ServiceType: "bitbucketServer", ServiceID: srv.URL + "/", AccountID: "Alice"},
+		}
+		acct.SetAccountData(&bitbucketserver.User{Name: "Alice", ID: 7})
+		return acct
+	}
+
+	t.Run("username", func(t *testing.T) {
+		p := newTestProvider(t, srv.URL, ProviderOp{UseNativeUsername: true})
+		acct, err := p.FetchAccount(context.Background(), &types.User{ID: 1, Username: "alice"}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := wantAccount(1); !reflect.DeepEqual(acct, want) {
+			t.Errorf("got account %+v, want %+v", acct, want)
+		}
+
+		// The Sourcegraph user has no Bitbucket Server user.
+		if acct, err := p.FetchAccount(context.Background(), &types.User{ID: 2, Username: "bob"}, nil); err != nil || acct != nil {
+			t.Errorf("got account %+v and error %v, want nil", acct, err)
+		}
+	})
+
+	t.Run("external", func(t *testing.T) {
+		providers.MockProviders = []providers.Provider{
+			mockAuthnProvider{configID: providers.ConfigID{Type: "saml", ID: "okta"}, serviceID: "https://okta.mine/"},
+			mockAuthnProvider{configID: providers.ConfigID{Type: "saml", ID: "onelogin"}, serviceID: "https://onelogin.mine/"},
+		}
+		defer func() { providers.MockProviders = nil }()
+
+		p := newTestProvider(t, srv.URL, ProviderOp{AuthnConfigID: providers.ConfigID{Type: "saml", ID: "okta"}})
+		current := []*extsvc.ExternalAccount{
+			{ExternalAccountSpec: extsvc.ExternalAccountSpec{ServiceType: "github", ServiceID: "https://github.com/", AccountID: "123"}},
+			{ExternalAccountSpec: extsvc.ExternalAccountSpec{ServiceType: "saml", ServiceID: "https://okta.mine/", AccountID: "alice"}},
+		}
+		acct, err := p.FetchAccount(context.Background(), &types.User{ID: 1, Username: "a"}, current)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := wantAccount(1); !reflect.DeepEqual(acct, want) {
+			t.Errorf("got account %+v, want %+v", acct, want)
+		}
+
+		// The user has no external account of the authn provider.
+		if acct, err := p.FetchAccount(context.Background(), &types.User{ID: 1, Username: "alice"}, current[:1]); err != nil || acct != nil {
+			t.Errorf("got account %+v and error %v, want nil", acct, err)
+		}
+
+		// The user's external account of another authn provider of the same type is not used.
+		other := []*extsvc.ExternalAccount{
+			{ExternalAccountSpec: extsvc.ExternalAccountSpec{ServiceType: "saml", ServiceID: "https://onelogin.mine/", AccountID: "alice"}},
+		}
+		if acct, err := p.FetchAccount(context.Background(), &types.User{ID: 1, Username: "a"}, other); err != nil || acct != nil {
+			t.Errorf("got account %+v and error %v, want nil", acct, err)
+		}
+
+		// Without an authn provider ID, no external account is used.
+		p = newTestProvider(t, srv.URL, ProviderOp{AuthnConfigID: providers.ConfigID{Type: "saml"}})
+		if acct, err := p.FetchAccount(context.Background(), &types.User{ID: 1, Username: "a"}, current); err != nil || acct != nil {
+			t.Errorf("got account %+v and error %v, want nil", acct, err)
+		}
+	})
+}
+
+type mockAuthnProvider struct {
+	configID  providers.ConfigID
+	serviceID string
+}
+
+func (m mockAuthnProvider) ConfigID() providers.ConfigID { return m.configID }
+
+func (m mockAuthnProvider) Config() schema.AuthProviders {
+	return schema.AuthProviders{Saml: &schema.SAMLAuthProvider{Type: m.configID.Type, ConfigID: m.configID.ID}}
+}
+
+func (m mockAuthnProvider) CachedInfo() *providers.Info {
+	return &providers.Info{ServiceID: m.serviceID}
+}
+
+func (m mockAuthnProvider) Refresh(context.Context) error { return nil }
@@ -0,0 +1,182 @@
+// Package explicit contains an authorization provider for the repository permissions that site
+// admins set explicitly with the GraphQL API.
+package explicit
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// ServiceType is the ServiceType of the explicit permissions authz provider and of its external
+// accounts.
+const ServiceType = "explicit"
+
+// Provider is an implementation of authz.Provider that provides the repository permissions stored
+// in the database (see db.ExplicitRepoPermission). It claims the repositories that have explicit
+// permissions, and grants read access on them to the listed users and the members of the listed
+// organizations.
+//
+// Its external accounts are transient: they identify the Sourcegraph user (by the account's UserID)
+// and are never saved.
+type Provider struct {
+	mu        sync.Mutex
+	rules     *matcher                  // all explicit permissions (cached for rulesTTL)
+	fetchedAt time.Time                 // when rules were fetched
+	compiled  map[string]*regexp.Regexp // compiled patterns of rules, keyed by RepoPattern
+}
+
+// rulesTTL is how long the explicit permissions that determine which repositories the provider
+// claims are cached. Which users are granted access is never cached.
+const rulesTTL = 10 * time.Second
+
+var _ authz.TransientAccountsProvider = ((*Provider)(nil))
+
+// NewProvider returns a new explicit permissions authz provider.
+func NewProvider() *Provider {
+	return &Provider{}
+}
+
+func (*Provider) TransientAccounts() {}
+
+func (*Provider) ServiceID() string { return "" }
+
+func (*Provider) ServiceType() string { return ServiceType }
+
+func (*Provider) Validate() (problems []string) { return nil }
+
+func (p *Provider) Repos(ctx context.Context, repos map[authz.Repo]struct{}) (mine map[authz.Repo]struct{}, others map[authz.Repo]struct{}) {
+	m, err := p.allRules(ctx)
+	if err != nil {
+		// 🚨 SECURITY: Claim all of the repositories, so that access to them is denied (because
+		// RepoPerms fails, too) instead of being determined by authz.allowAccessByDefault.
+		log15.Error("Failed to list explicit repository permissions.", "error", err)
+		return repos, map[authz.Repo]struct{}{}
+	}
+
+	mine, others = map[authz.Repo]struct{}{}, map[authz.Repo]struct{}{}
+	for repo := range repos {
+		if m.match(repo.RepoName) {
+			mine[repo] = struct{}{}
+		} else {
+			others[repo] = struct{}{}
+		}
+	}
+	return mine, others
+}
+
+// RepoPerms implements the authz.Provider interface. The user has read access to a repository if
+// any of the explicit permissions that match the repository list the user or an organization that
+// the user is a member of. Unauthenticated users have no access.
+func (p *Provider) RepoPerms(ctx context.Context, account *extsvc.ExternalAccount, repos map[authz.Repo]struct{}) (map[api.RepoName]map[authz.Perm]bool, error) {
+	if account == nil || account.ServiceType != ServiceType || account.ServiceID != "" || account.UserID == 0 {
+		return nil, nil
+	}
+
+	granted, err := db.ExplicitRepoPermissions.ListGranted(ctx, account.UserID)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	m := newMatcher(granted, p.compiled)
+	p.mu.Unlock()
+	perms := make(map[api.RepoName]map[authz.Perm]bool, len(repos))
+	for repo := range repos {
+		perms[repo.RepoName] = map[authz.Perm]bool{authz.Read: m.match(repo.RepoName)}
+	}
+	return perms, nil
+}
+
+// FetchAccount implements the authz.Provider interface. It returns an account that identifies the
+// Sourcegraph user.
+func (*Provider) FetchAccount(ctx context.Context, user *types.User, current []*extsvc.ExternalAccount) (mine *extsvc.ExternalAccount, err error) {
+	if user == nil {
+		return nil, nil
+	}
+	return &extsvc.ExternalAccount{
+		UserID: user.ID,
+		ExternalAccountSpec: extsvc.ExternalAccountSpec{
+			ServiceType: ServiceType,
+			ServiceID:   "",
+			AccountID:   strconv.Itoa(int(user.ID)),
+		},
+	}, nil
+}
+
+// allRules returns a matcher for all explicit permissions, which is cached for rulesTTL.
+func (p *Provider) allRules(ctx context.Context) (*matcher, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.rules != nil && time.Since(p.fetchedAt) < rulesTTL {
+		return p.rules, nil
+	}
+
+	all, err := db.ExplicitRepoPermissions.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Keep only the compiled patterns that are still in use, so that the cache doesn't grow.
+	compiled := make(map[string]*regexp.Regexp, len(all))
+	p.rules = newMatcher(all, p.compiled)
+	for _, re := range p.rules.patterns {
+		compiled[re.pattern] = re.re
+	}
+	p.compiled, p.fetchedAt = compiled, time.Now()
+	return p.rules, nil
+}
+
+// matcher matches repository names against a list of explicit repository permissions.
+type matcher struct {
+	names    map[api.RepoName]struct{}
+	patterns []compiledPattern
+}
+
+type compiledPattern struct {
+	pattern string // the explicit permissions' RepoPattern
+	re      *regexp.Regexp
+}
+
+// newMatcher returns a matcher for the explicit permissions. It reuses the previously compiled
+// patterns in compiled (keyed by RepoPattern), if any.
+func newMatcher(perms []*db.ExplicitRepoPermission, compiled map[string]*regexp.Regexp) *matcher {
+	m := &matcher{names: map[api.RepoName]struct{}{}}
+	for _, p := range perms {
+		if p.RepoPattern == "" {
+			m.names[p.RepoName] = struct{}{}
+			continue
+		}
+		re, ok := compiled[p.RepoPattern]
+		if !ok {
+			var err error
+			re, err = db.CompileExplicitRepoPattern(p.RepoPattern)
+			if err != nil {
+				// This should not happen, because patterns are validated before they are stored.
+				log15.Error("Ignoring invalid explicit repository permissions pattern.", "pattern", p.RepoPattern, "error", err)
+				continue
+			}
+		}
+		m.patterns = append(m.patterns, compiledPattern{pattern: p.RepoPattern, re: re})
+	}
+	return m
+}
+
+func (m *matcher) match(name api.RepoName) bool {
+	if _, ok := m.names[name]; ok {
+		return true
+	}
+	for _, p := range m.patterns {
+		if p.re.MatchString(string(name)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package explicit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/api"
+	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
+)
+
+func repoSet(names ...api.RepoName) map[authz.Repo]struct{} {
+	repos := make(map[authz.Repo]struct{}, len(names))
+	for _, name := range names {
+		repos[authz.Repo{RepoName: name, ExternalRepoSpec: api.ExternalRepoSpec{ID: string(name), ServiceType: "gitolite", ServiceID: "git@gitolite.example.com"}}] = struct{}{}
+	}
+	return repos
+}
+
+func TestProvider_Repos(t *testing.T) {
+	defer func() { db.Mocks.ExplicitRepoPermissions = db.MockExplicitRepoPermissions{} }()
+	db.Mocks.ExplicitRepoPermissions.List = func() ([]*db.ExplicitRepoPermission, error) {
+		return []*db.ExplicitRepoPermission{
+			{ID: 1, RepoID: 1, RepoName: "gitolite.example.com/a"},
+			{ID: 2, RepoPattern: `gitolite\.example\.com/secret/.*`},
+		}, nil
+	}
+
+	mine, others := NewProvider().Repos(context.Background(), repoSet("gitolite.example.com/a", "gitolite.example.com/b", "gitolite.example.com/secret/c"))
+	if want := repoSet("gitolite.example.com/a", "gitolite.example.com/secret/c"); !reflect.DeepEqual(mine, want) {
+		t.Errorf("got mine %v, want %v", mine, want)
+	}
+	if want := repoSet("gitolite.example.com/b"); !reflect.DeepEqual(others, want) {
+		t.Errorf("got others %v, want %v", others, want)
+	}
+
+	// 🚨 SECURITY: All repositories are claimed if the explicit permissions can't be listed.
+	db.Mocks.ExplicitRepoPermissions.List = func() ([]*db.ExplicitRepoPermission, error) {
+		return nil, errors.New("x")
+	}
+	repos := repoSet("gitolite.example.com/a", "gitolite.example.com/b")
+	if mine, others := NewProvider().Repos(context.Background(), repos); !reflect.DeepEqual(mine, repos) || len(others) != 0 {
+		t.Errorf("got mine %v and others %v, want all repositories claimed", mine, others)
+	}
+}
+
+func TestProvider_Repos_anchoredPattern(t *testing.T) {
+	defer func() { db.Mocks.ExplicitRepoPermissions = db.MockExplicitRepoPermissions{} }()
+	db.Mocks.ExplicitRepoPermissions.List = func() ([]*db.ExplicitRepoPermission, error) {
+		return []*db.ExplicitRepoPermission{{ID: 1, RepoPattern: `gitolite\.example\.com/foo`}}, nil
+	}
+
+	mine, others := NewProvider().Repos(context.Background(), repoSet("gitolite.example.com/foo", "gitolite.example.com/foo-secret", "x/gitolite.example.com/foo"))
+	if want := repoSet("gitolite.example.com/foo"); !reflect.DeepEqual(mine, want) {
+		t.Errorf("got mine %v, want %v", mine, want)
+	}
+	if want := repoSet("gitolite.example.com/foo-secret", "x/gitolite.example.com/foo"); !reflect.DeepEqual(others, want) {
+		t.Errorf("got others %v, want %v", others, want)
+	}
+}
+
+func TestProvider_Repos_cached(t *testing.T) {
+	defer func() { db.Mocks.ExplicitRepoPermissions = db.MockExplicitRepoPermissions{} }()
+	calls := 0
+	db.Mocks.ExplicitRepoPermissions.List = func() ([]*db.ExplicitRepoPermission, error) {
+		calls++
+		return []*db.ExplicitRepoPermission{{ID: 1, RepoID: 1, RepoName: "gitolite.example.com/a"}}, nil
+	}
+
+	p := NewProvider()
+	repos := repoSet("gitolite.example.com/a", "gitolite.example.com/b")
+	for i := 0; i < 3; i++ {
+		if mine, _ := p.Repos(context.Background(), repos); len(mine) != 1 {
+			t.Errorf("got mine %v, want 1 repository", mine)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("got %d List calls, want 1", calls)
+	}
+}
+
+func TestProvider_RepoPerms(t *testing.T) {
+	defer func() { db.Mocks.ExplicitRepoPermissions = db.MockExplicitRepoPermissions{} }()
+	db.Mocks.ExplicitRepoPermissions.ListGranted = func(userID int32) ([]*db.ExplicitRepoPermission, error) {
+		if userID != 1 {
+			return nil, nil
+		}
+		return []*db.ExplicitRepoPermission{
+			{ID: 1, RepoID: 1, RepoName: "gitolite.example.com/a", UserIDs: []int32{1}},
+			{ID: 2, RepoPattern: `gitolite\.example\.com/secret/.*`, OrgIDs: []int32{3}},
+		}, nil
+	}
+
+	p := NewProvider()
+	repos := repoSet("gitolite.example.com/a", "gitolite.example.com/b", "gitolite.example.com/secret/c")
+	account := func(userID int32) *extsvc.ExternalAccount {
+		acct, err := p.FetchAccount(context.Background(), &types.User{ID: userID}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return acct
+	}
+
+	tests := []struct {
+		description string
+		account     *extsvc.ExternalAccount
+		want        map[api.RepoName]map[authz.Perm]bool
+	}{
+		{
+			description: "granted user",
+			account:     account(1),
+			want: map[api.RepoName]map[authz.Perm]bool{
+				"gitolite.example.com/a":        {authz.Read: true},
+				"gitolite.example.com/b":        {authz.Read: false},
+				"gitolite.example.com/secret/c": {authz.Read: true},
+			},
+		},
+		{
+			description: "other user",
+			account:     account(2),
+			want: map[api.RepoName]map[authz.Perm]bool{
+				"gitolite.example.com/a":        {authz.Read: false},
+				"gitolite.example.com/b":        {authz.Read: false},
+				"gitolite.example.com/secret/c": {authz.Read: false},
+			},
+		},
+		{
+			description: "unauthenticated",
+		},
+		{
+			description: "account of another authz provider",
+			account: &extsvc.ExternalAccount{
+				UserID:              1,
+				ExternalAccountSpec: extsvc.ExternalAccountSpec{ServiceType: "gitlab", ServiceID: "https://gitlab.com/", AccountID: "1"},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.description, func(t *testing.T) {
+			perms, err := p.RepoPerms(context.Background(), test.account, repos)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(perms, test.want) {
+				t.Errorf("got perms %v, want %v", perms, test.want)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitlab"
+	"github.com/sourcegraph/sourcegraph/pkg/ratelimit"
 	"github.com/sourcegraph/sourcegraph/pkg/rcache"
 	log15 "gopkg.in/inconshreveable/log15.v2"
 )
@@ -45,7 +46,7 @@ type GitLabOAuthAuthzProviderOp struct {
 
 func NewOAuthProvider(op GitLabOAuthAuthzProviderOp) *GitLabOAuthAuthzProvider {
 	p := &GitLabOAuthAuthzProvider{
-		clientProvider: gitlab.NewClientProvider(op.BaseURL, nil),
+		clientProvider: gitlab.NewClientProvider(op.BaseURL, ratelimit.BudgetMiddleware("authz")(http.DefaultClient)),
 		clientURL:      op.BaseURL,
 		codeHost:       gitlab.NewCodeHost(op.BaseURL),
 		cache:          op.MockCache,
@@ -17,6 +17,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc"
 	"github.com/sourcegraph/sourcegraph/pkg/extsvc/gitlab"
+	"github.com/sourcegraph/sourcegraph/pkg/ratelimit"
 	"github.com/sourcegraph/sourcegraph/pkg/rcache"
 	log15 "gopkg.in/inconshreveable/log15.v2"
 )
@@ -81,7 +82,7 @@ func NewSudoProvider(op SudoProviderOp) *SudoProvider {
 	p := &SudoProvider{
 		sudoToken: op.SudoToken,
 
-		clientProvider:    gitlab.NewClientProvider(op.BaseURL, nil),
+		clientProvider:    gitlab.NewClientProvider(op.BaseURL, ratelimit.BudgetMiddleware("authz")(http.DefaultClient)),
 		clientURL:         op.BaseURL,
 		codeHost:          gitlab.NewCodeHost(op.BaseURL),
 		cache:             op.MockCache,
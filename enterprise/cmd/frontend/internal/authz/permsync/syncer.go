@@ -0,0 +1,144 @@
+// Package permsync syncs users' repository permissions from the authz providers to the database in
+// the background, so that repository permissions can be checked with a single database query
+// instead of by calling the code host when repositories are accessed.
+package permsync
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+const (
+	// batchSize is the maximum number of users whose stale permissions are synced at once.
+	batchSize = 100
+
+	// minRequestInterval is the minimum time between two requested syncs of the same user's
+	// permissions (because the http-header auth provider requests a sync on every request).
+	minRequestInterval = 5 * time.Minute
+)
+
+func init() {
+	conf.ContributeValidator(func(cfg conf.Unified) (problems []string) {
+		if c := cfg.PermissionsBackgroundSync; c != nil && c.Interval != "" {
+			if d, err := time.ParseDuration(c.Interval); err != nil {
+				problems = append(problems, fmt.Sprintf("permissions.backgroundSync.interval: %s", err))
+			} else if d < time.Minute {
+				problems = append(problems, "permissions.backgroundSync.interval must be at least 1m")
+			}
+		}
+		return problems
+	})
+}
+
+// Syncer syncs users' repository permissions in the background, both periodically (for users whose
+// permissions have not been synced within the "permissions.backgroundSync" interval) and on request
+// (for example, when a user signs in).
+type Syncer struct {
+	requests chan int32
+
+	mu        sync.Mutex
+	requested map[int32]time.Time // when a sync of each user's permissions was last requested
+}
+
+// NewSyncer returns a new permissions syncer. Call Run to start syncing.
+func NewSyncer() *Syncer {
+	return &Syncer{
+		requests:  make(chan int32, batchSize),
+		requested: map[int32]time.Time{},
+	}
+}
+
+// RequestSync implements authz.PermissionsSyncer.
+func (s *Syncer) RequestSync(userID int32) {
+	if !conf.PermissionsBackgroundSyncEnabled() {
+		return
+	}
+
+	s.mu.Lock()
+	if t, ok := s.requested[userID]; ok && time.Since(t) < minRequestInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.requested[userID] = time.Now()
+	s.mu.Unlock()
+
+	select {
+	case s.requests <- userID:
+	default:
+		// The queue is full. The user's permissions will be synced by the periodic sync.
+	}
+}
+
+// Run syncs permissions until ctx is done.
+func (s *Syncer) Run(ctx context.Context) {
+	// 🚨 SECURITY: The syncer needs to see all repositories (not just those that the current user
+	// has access to), and it only stores permissions (it never returns repositories to users).
+	ctx = actor.WithActor(ctx, &actor.Actor{Internal: true})
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		if conf.PermissionsBackgroundSyncEnabled() {
+			if err := s.syncStale(ctx); err != nil {
+				log15.Error("Failed to sync stale repository permissions.", "error", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case userID := <-s.requests:
+			if err := s.syncUsers(ctx, []int32{userID}); err != nil {
+				log15.Error("Failed to sync repository permissions.", "userID", userID, "error", err)
+			}
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncStale syncs the permissions of the users whose permissions are due to be synced (because the
+// last attempt was longer than the sync interval ago, or because it failed and is retried).
+func (s *Syncer) syncStale(ctx context.Context) error {
+	userIDs, err := db.UserPermissions.ListUsersToSync(ctx, conf.PermissionsBackgroundSyncInterval(), batchSize)
+	if err != nil || len(userIDs) == 0 {
+		return err
+	}
+	return s.syncUsers(ctx, userIDs)
+}
+
+func (s *Syncer) syncUsers(ctx context.Context, userIDs []int32) error {
+	if _, authzProviders := authz.GetProviders(); len(authzProviders) == 0 {
+		return nil
+	}
+
+	reposListedAt := time.Now()
+	repos, err := listRepos(ctx)
+	if err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		syncErr := db.UserPermissions.Sync(ctx, userID, repos, reposListedAt)
+		if syncErr != nil {
+			// Continue syncing the other users' permissions. The failed sync is retried with
+			// backoff (see db.UserPermissions.ListUsersToSync).
+			log15.Error("Failed to sync user's repository permissions.", "userID", userID, "error", syncErr)
+		}
+		if err := db.UserPermissions.RecordSyncAttempt(ctx, userID, syncErr != nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var listRepos = func(ctx context.Context) ([]*types.Repo, error) {
+	return db.Repos.List(ctx, db.ReposListOptions{Enabled: true, Disabled: true})
+}
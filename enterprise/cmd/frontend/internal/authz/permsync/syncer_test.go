@@ -0,0 +1,46 @@
+package permsync
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestSyncer_RequestSync(t *testing.T) {
+	requested := func(s *Syncer) (userIDs []int32) {
+		for {
+			select {
+			case userID := <-s.requests:
+				userIDs = append(userIDs, userID)
+			default:
+				return userIDs
+			}
+		}
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		conf.Mock(&conf.Unified{})
+		defer conf.Mock(nil)
+
+		s := NewSyncer()
+		s.RequestSync(1)
+		if got := requested(s); len(got) != 0 {
+			t.Errorf("got requested syncs %v, want none", got)
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{PermissionsBackgroundSync: &schema.PermissionsBackgroundSync{Enabled: true}}})
+		defer conf.Mock(nil)
+
+		s := NewSyncer()
+		s.RequestSync(1)
+		s.RequestSync(2)
+		s.RequestSync(1) // deduplicated
+		if got, want := requested(s), []int32{1, 2}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got requested syncs %v, want %v", got, want)
+		}
+	})
+}
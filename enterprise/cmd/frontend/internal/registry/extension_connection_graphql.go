@@ -30,9 +30,10 @@ func listLocalRegistryExtensions(ctx context.Context, args graphqlbackend.Regist
 	if err := prefixLocalExtensionID(xs...); err != nil {
 		return nil, err
 	}
+	versions := &viewerExtensionVersions{}
 	xs2 := make([]graphqlbackend.RegistryExtension, len(xs))
 	for i, x := range xs {
-		xs2[i] = &extensionDBResolver{v: x}
+		xs2[i] = &extensionDBResolver{v: x, versions: versions}
 	}
 	return xs2, nil
 }
@@ -3,17 +3,29 @@ package registry
 import (
 	"context"
 	"strings"
+	"sync"
 	"time"
 
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/registry"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 // extensionDBResolver implements the GraphQL type RegistryExtension.
 type extensionDBResolver struct {
 	v *dbExtension
+
+	// versions is shared among the resolvers for a request (nil if this is the only one)
+	versions *viewerExtensionVersions
+
+	// cache the release's manifest because it is used by multiple fields
+	once        sync.Once
+	manifest    *string
+	publishedAt time.Time
+	err         error
 }
 
 func (r *extensionDBResolver) ID() graphql.ID {
@@ -34,8 +46,49 @@ func (r *extensionDBResolver) Publisher(ctx context.Context) (graphqlbackend.Reg
 }
 
 func (r *extensionDBResolver) Name() string { return r.v.Name }
+
+// release returns the manifest and publish date of the release that the viewer uses: the latest
+// release, or the latest release in the version range that the viewer's settings pin the extension
+// to (in the "extensions.versions" settings property).
+func (r *extensionDBResolver) release(ctx context.Context) (*string, time.Time, error) {
+	r.once.Do(func() {
+		if r.versions == nil {
+			r.versions = &viewerExtensionVersions{}
+		}
+		var versionRange string
+		versionRange, r.err = r.versions.versionRange(ctx, r.v.NonCanonicalExtensionID)
+		if r.err != nil {
+			return
+		}
+		r.manifest, r.publishedAt, r.err = getExtensionManifestWithBundleURL(ctx, r.v.NonCanonicalExtensionID, r.v.ID, "release", versionRange)
+	})
+	return r.manifest, r.publishedAt, r.err
+}
+
+// viewerExtensionVersions looks up the version ranges that the viewer's settings pin extensions to
+// (in the "extensions.versions" settings property). The viewer's settings are computed at most
+// once, so a single viewerExtensionVersions should be shared among all of a request's resolvers.
+type viewerExtensionVersions struct {
+	once   sync.Once
+	ranges map[string]string
+	err    error
+}
+
+// versionRange returns the version range that the viewer's settings pin the extension to, or "" if
+// there is none.
+func (v *viewerExtensionVersions) versionRange(ctx context.Context, extensionID string) (string, error) {
+	v.once.Do(func() {
+		var settings *schema.Settings
+		settings, v.err = graphqlbackend.ViewerFinalSettings(ctx)
+		if settings != nil {
+			v.ranges = settings.ExtensionsVersions
+		}
+	})
+	return v.ranges[extensionID], v.err
+}
+
 func (r *extensionDBResolver) Manifest(ctx context.Context) (graphqlbackend.ExtensionManifest, error) {
-	manifest, _, err := getExtensionManifestWithBundleURL(ctx, r.v.NonCanonicalExtensionID, r.v.ID, "release")
+	manifest, _, err := r.release(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -51,13 +104,17 @@ func (r *extensionDBResolver) UpdatedAt() *string {
 }
 
 func (r *extensionDBResolver) PublishedAt(ctx context.Context) (*string, error) {
-	_, publishedAt, err := getExtensionManifestWithBundleURL(ctx, r.v.NonCanonicalExtensionID, r.v.ID, "release")
+	_, publishedAt, err := r.release(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return strptr(publishedAt.Format(time.RFC3339)), nil
 }
 
+func (r *extensionDBResolver) Releases(ctx context.Context, args *graphqlutil.ConnectionArgs) (graphqlbackend.RegistryExtensionReleaseConnection, error) {
+	return &registryExtensionReleaseConnection{registryExtensionID: r.v.ID, first: args.First}, nil
+}
+
 func (r *extensionDBResolver) URL() string {
 	return registry.ExtensionURL(r.v.NonCanonicalExtensionID)
 }
@@ -13,6 +13,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/errcode"
 	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
+	log15 "gopkg.in/inconshreveable/log15.v2"
 )
 
 // validateExtensionManifest validates a JSON extension manifest for syntax.
@@ -24,11 +25,11 @@ func validateExtensionManifest(text string) error {
 }
 
 // getExtensionManifestWithBundleURL returns the extension manifest as JSON. If there are no
-// releases, it returns a nil manifest. If the manifest has no "url" field itself, a "url" field
-// pointing to the extension's bundle is inserted. It also returns the date that the release was
-// published.
-func getExtensionManifestWithBundleURL(ctx context.Context, extensionID string, registryExtensionID int32, releaseTag string) (manifest *string, publishedAt time.Time, err error) {
-	release, err := dbReleases{}.GetLatest(ctx, registryExtensionID, releaseTag, false)
+// releases (or no releases in the version range, if versionRange is non-empty), it returns a nil
+// manifest. If the manifest has no "url" field itself, a "url" field pointing to the extension's
+// bundle is inserted. It also returns the date that the release was published.
+func getExtensionManifestWithBundleURL(ctx context.Context, extensionID string, registryExtensionID int32, releaseTag, versionRange string) (manifest *string, publishedAt time.Time, err error) {
+	release, err := getLatestRelease(ctx, registryExtensionID, releaseTag, versionRange)
 	if err != nil && !errcode.IsNotFound(err) {
 		return nil, time.Time{}, err
 	}
@@ -63,6 +64,31 @@ func getExtensionManifestWithBundleURL(ctx context.Context, extensionID string,
 	return manifest, publishedAt, nil
 }
 
+// getLatestRelease returns the latest release of the extension that was not yanked. If
+// versionRange is non-empty, it returns the release with the greatest version in the range instead.
+// If versionRange is invalid, it is ignored (so that a typo in settings doesn't break the
+// extension).
+func getLatestRelease(ctx context.Context, registryExtensionID int32, releaseTag, versionRange string) (*dbRelease, error) {
+	if versionRange == "" {
+		return dbReleases{}.GetLatest(ctx, registryExtensionID, releaseTag, false)
+	}
+
+	r, err := parseVersionRange(versionRange)
+	if err != nil {
+		log15.Warn("Ignoring invalid extension version range in settings.", "registryExtensionID", registryExtensionID, "versionRange", versionRange, "error", err)
+		return dbReleases{}.GetLatest(ctx, registryExtensionID, releaseTag, false)
+	}
+	releases, err := dbReleases{}.List(ctx, registryExtensionID, releaseTag)
+	if err != nil {
+		return nil, err
+	}
+	release := latestReleaseInRange(releases, r)
+	if release == nil {
+		return nil, releaseNotFoundError{[]interface{}{fmt.Sprintf("registry extension ID %d tag %q version range %q", registryExtensionID, releaseTag, versionRange)}}
+	}
+	return release, nil
+}
+
 var nonLettersDigits = regexp.MustCompile(`[^a-zA-Z0-9-]`)
 
 func makeExtensionBundleURL(registryExtensionReleaseID int64, timestamp int64, extensionIDHint string) (string, error) {
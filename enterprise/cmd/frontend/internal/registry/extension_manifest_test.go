@@ -28,7 +28,7 @@ func TestGetExtensionManifestWithBundleURL(t *testing.T) {
 			}, nil
 		}
 		defer func() { mocks.releases.GetLatest = nil }()
-		manifest, publishedAt, err := getExtensionManifestWithBundleURL(ctx, "x", 1, "t")
+		manifest, publishedAt, err := getExtensionManifestWithBundleURL(ctx, "x", 1, "t", "")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -48,7 +48,7 @@ func TestGetExtensionManifestWithBundleURL(t *testing.T) {
 			}, nil
 		}
 		defer func() { mocks.releases.GetLatest = nil }()
-		manifest, publishedAt, err := getExtensionManifestWithBundleURL(ctx, "x", 1, "t")
+		manifest, publishedAt, err := getExtensionManifestWithBundleURL(ctx, "x", 1, "t", "")
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -59,6 +59,54 @@ func TestGetExtensionManifestWithBundleURL(t *testing.T) {
 			t.Errorf("got %v, want %v", publishedAt, t0)
 		}
 	})
+
+	t.Run("version range", func(t *testing.T) {
+		t1 := time.Unix(5678, 0)
+		mocks.releases.List = func(registryExtensionID int32, releaseTag string) ([]*dbRelease, error) {
+			return []*dbRelease{
+				{ID: 4, ReleaseVersion: strptr("2.0.0"), Manifest: `{"v":4}`, CreatedAt: t1},
+				{ID: 3, ReleaseVersion: strptr("1.3.0"), Manifest: `{"v":3}`, CreatedAt: t1, YankedAt: &t1},
+				{ID: 2, ReleaseVersion: strptr("1.2.0"), Manifest: `{"v":2}`, CreatedAt: t0},
+				{ID: 1, ReleaseVersion: strptr("1.1.0"), Manifest: `{"v":1}`, CreatedAt: t0},
+			}, nil
+		}
+		defer func() { mocks.releases.List = nil }()
+		manifest, publishedAt, err := getExtensionManifestWithBundleURL(ctx, "x", 1, "t", "^1.1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `{"v":2,"url":"/-/static/extension/2-x.js?fqw3qlts--x"}`; manifest == nil || !jsonDeepEqual(*manifest, want) {
+			t.Errorf("got %q, want %q", nilOrEmpty(manifest), want)
+		}
+		if publishedAt != t0 {
+			t.Errorf("got %v, want %v", publishedAt, t0)
+		}
+
+		manifest, _, err = getExtensionManifestWithBundleURL(ctx, "x", 1, "t", "3.x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if manifest != nil {
+			t.Errorf("got %q, want nil (no release in range)", *manifest)
+		}
+	})
+
+	t.Run("invalid version range", func(t *testing.T) {
+		mocks.releases.GetLatest = func(registryExtensionID int32, releaseTag string, includeArtifacts bool) (*dbRelease, error) {
+			return &dbRelease{
+				Manifest:  `{"name":"x","url":"u"}`,
+				CreatedAt: t0,
+			}, nil
+		}
+		defer func() { mocks.releases.GetLatest = nil }()
+		manifest, _, err := getExtensionManifestWithBundleURL(ctx, "x", 1, "t", "^1.a.3")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `{"name":"x","url":"u"}`; manifest == nil || !jsonDeepEqual(*manifest, want) {
+			t.Errorf("got %q, want %q (the latest release)", nilOrEmpty(manifest), want)
+		}
+	})
 }
 
 func jsonDeepEqual(a, b string) bool {
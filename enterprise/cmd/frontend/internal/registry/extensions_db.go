@@ -265,7 +265,9 @@ WHERE (%s)
                     AND rer2.deleted_at IS NULL
                     AND rer2.created_at > rer.created_at
   )
-  AND x.deleted_at IS NULL`,
+  AND x.deleted_at IS NULL
+  -- Extensions mirrored from a remote registry are listed separately (see dbMirroredExtensions).
+  AND x.mirrored_extension_id IS NULL`,
 		sqlf.Join(conds, ") AND ("))
 }
 
@@ -69,8 +69,53 @@ var (
 	}
 )
 
+// registryArchive returns an extension archive with the latest releases of the extensions in the
+// registry (or only the extensions with the given extension IDs, if any are given). Other sites
+// can mirror extensions from the archive.
+func registryArchive(ctx context.Context, extensionIDs []string) (*registry.Archive, error) {
+	include := make(map[string]bool, len(extensionIDs))
+	for _, id := range extensionIDs {
+		include[id] = true
+	}
+
+	vs, err := dbExtensions{}.List(ctx, dbExtensionsListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	archive := &registry.Archive{
+		RegistryURL: strings.TrimSuffix(conf.Get().Critical.ExternalURL, "/") + "/.api/registry",
+		Extensions:  []*registry.ArchivedExtension{},
+	}
+	for _, v := range vs {
+		if len(include) > 0 && !include[v.NonCanonicalExtensionID] {
+			continue
+		}
+		release, err := dbReleases{}.GetLatest(ctx, v.ID, "release", true)
+		if errcode.IsNotFound(err) {
+			continue // no releases
+		} else if err != nil {
+			return nil, err
+		}
+		x, err := toRegistryAPIExtension(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+
+		// Use the manifest as published (without the "url" field pointing to the bundle on this
+		// site), because the bundle is included in the archive.
+		x.Manifest = &release.Manifest
+		x.PublishedAt = release.CreatedAt
+		archive.Extensions = append(archive.Extensions, &registry.ArchivedExtension{
+			Extension: *x,
+			Bundle:    release.Bundle,
+			SourceMap: release.SourceMap,
+		})
+	}
+	return archive, nil
+}
+
 func toRegistryAPIExtension(ctx context.Context, v *dbExtension) (*registry.Extension, error) {
-	manifest, publishedAt, err := getExtensionManifestWithBundleURL(ctx, v.NonCanonicalExtensionID, v.ID, "release")
+	manifest, publishedAt, err := getExtensionManifestWithBundleURL(ctx, v.NonCanonicalExtensionID, v.ID, "release", "")
 	if err != nil {
 		return nil, err
 	}
@@ -148,6 +193,17 @@ func handleRegistry(w http.ResponseWriter, r *http.Request) (err error) {
 		ev.AddField("results_count", len(xs))
 		result = xs
 
+	case urlPath == extensionsPath+"/archive":
+		extensionIDs := r.URL.Query()["extensionID"]
+		ev.AddField("extension-ids", extensionIDs)
+		archive, err := registryArchive(r.Context(), extensionIDs)
+		if err != nil {
+			return err
+		}
+		ev.AddField("results_count", len(archive.Extensions))
+		w.Header().Set("Content-Disposition", `attachment; filename="extensions.json"`)
+		result = archive
+
 	case strings.HasPrefix(urlPath, extensionsPath+"/"):
 		var (
 			spec = strings.TrimPrefix(urlPath, extensionsPath+"/")
@@ -0,0 +1,305 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	frontendregistry "github.com/sourcegraph/sourcegraph/cmd/frontend/registry"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/licensing"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
+	"github.com/sourcegraph/sourcegraph/pkg/registry"
+	"github.com/sourcegraph/sourcegraph/schema"
+	"golang.org/x/net/context/ctxhttp"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+func init() {
+	frontendregistry.IsMirroringRemoteExtensions = func() bool {
+		return getMirrorConfig() != nil
+	}
+	frontendregistry.GetMirroredExtension = func(ctx context.Context, field, value string) (*registry.Extension, error) {
+		var (
+			v   *dbMirroredExtension
+			err error
+		)
+		switch field {
+		case "uuid":
+			v, err = dbMirroredExtensions{}.GetByUUID(ctx, value)
+		case "extensionID":
+			v, err = dbMirroredExtensions{}.GetByExtensionID(ctx, value)
+		default:
+			panic("unexpected field: " + field)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return toMirroredRegistryAPIExtension(ctx, v)
+	}
+	frontendregistry.ListMirroredExtensions = func(ctx context.Context, query string) ([]*registry.Extension, error) {
+		vs, err := dbMirroredExtensions{}.List(ctx)
+		if err != nil {
+			return nil, err
+		}
+		xs := make([]*registry.Extension, 0, len(vs))
+		for _, v := range vs {
+			x, err := toMirroredRegistryAPIExtension(ctx, v)
+			if err != nil {
+				return nil, err
+			}
+			if x.Manifest == nil {
+				continue // not yet mirrored
+			}
+			xs = append(xs, x)
+		}
+		return frontendregistry.FilterRegistryExtensions(xs, query), nil
+	}
+}
+
+// getMirrorConfig returns the site configuration for mirroring remote extensions, or nil if
+// mirroring is not enabled.
+func getMirrorConfig() *schema.ExtensionsMirror {
+	if conf.Extensions() == nil {
+		return nil
+	}
+
+	// Mirrored extensions are stored in the private extension registry, so mirroring requires the
+	// same license feature.
+	if !licensing.IsFeatureEnabledLenient(licensing.FeatureExtensionRegistry) {
+		return nil
+	}
+
+	if c := conf.Get().Extensions; c != nil {
+		return c.Mirror
+	}
+	return nil
+}
+
+// toMirroredRegistryAPIExtension converts a mirrored extension to the external API type, as though
+// it was retrieved from the remote registry that it was mirrored from.
+func toMirroredRegistryAPIExtension(ctx context.Context, v *dbMirroredExtension) (*registry.Extension, error) {
+	manifest, publishedAt, err := getExtensionManifestWithBundleURL(ctx, v.ExtensionID, v.ID, "release", "")
+	if err != nil {
+		return nil, err
+	}
+
+	_, publisher, _, err := frontendregistry.SplitExtensionID(v.ExtensionID)
+	if err != nil {
+		return nil, err
+	}
+	x := &registry.Extension{
+		UUID:        v.UUID,
+		ExtensionID: v.ExtensionID,
+		Publisher:   registry.Publisher{Name: publisher},
+		Name:        v.Name,
+		Manifest:    manifest,
+		CreatedAt:   v.CreatedAt,
+		UpdatedAt:   v.UpdatedAt,
+		PublishedAt: publishedAt,
+		RegistryURL: v.Source,
+	}
+
+	// Link to the extension on the Sourcegraph site that hosts the source registry.
+	if baseURL := strings.TrimSuffix(strings.TrimSuffix(v.Source, "/"), "/.api/registry"); baseURL != v.Source {
+		x.URL = baseURL + frontendregistry.ExtensionURL(v.ExtensionID)
+	}
+	return x, nil
+}
+
+// RunMirror periodically mirrors remote extensions into the local extension registry, as specified
+// by the "extensions"."mirror" site configuration property. It runs until ctx is done.
+func RunMirror(ctx context.Context) {
+	var lastRun time.Time
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		if c := getMirrorConfig(); c != nil && time.Since(lastRun) >= mirrorInterval(c) {
+			lastRun = time.Now()
+			if err := mirrorExtensions(ctx, c); err != nil {
+				log15.Error("Failed to mirror extensions into the extension registry.", "error", err)
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func mirrorInterval(c *schema.ExtensionsMirror) time.Duration {
+	if c.IntervalMinutes <= 0 {
+		return 60 * time.Minute
+	}
+	return time.Duration(c.IntervalMinutes) * time.Minute
+}
+
+// mirrorExtensions creates or updates the mirrored extensions from the mirror source, and deletes
+// mirrored extensions that are no longer in the mirror source (or no longer allowed).
+func mirrorExtensions(ctx context.Context, c *schema.ExtensionsMirror) error {
+	source, xs, err := readMirrorSource(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	keep := make([]int32, 0, len(xs))
+	for _, x := range xs {
+		if x.Manifest == nil || !frontendregistry.IsRemoteExtensionAllowed(x.ExtensionID) {
+			continue
+		}
+		id, err := mirrorExtension(ctx, source, x)
+		if err != nil {
+			// Keep the previously mirrored release (if any) so that a temporary error doesn't
+			// remove the extension.
+			log15.Error("Failed to mirror extension.", "extension", x.ExtensionID, "source", source, "error", err)
+		}
+		if id != 0 {
+			keep = append(keep, id)
+		}
+	}
+	return dbMirroredExtensions{}.DeleteAllExcept(ctx, keep)
+}
+
+// readMirrorSource reads the extensions from the extension archive file (if configured) or else
+// from the registry.
+func readMirrorSource(ctx context.Context, c *schema.ExtensionsMirror) (source string, xs []*registry.ArchivedExtension, err error) {
+	if c.Archive != "" {
+		data, err := ioutil.ReadFile(c.Archive)
+		if err != nil {
+			return "", nil, err
+		}
+		var archive registry.Archive
+		if err := json.Unmarshal(data, &archive); err != nil {
+			return "", nil, fmt.Errorf("invalid extension archive %q: %s", c.Archive, err)
+		}
+		return archive.RegistryURL, archive.Extensions, nil
+	}
+
+	registryURLStr := c.Registry
+	if registryURLStr == "" {
+		registryURLStr = conf.Extensions().RemoteRegistryURL
+	}
+	if registryURLStr == "" {
+		return "", nil, fmt.Errorf("no registry to mirror extensions from (set extensions.mirror.registry or extensions.mirror.archive in site configuration)")
+	}
+	registryURL, err := url.Parse(registryURLStr)
+	if err != nil {
+		return "", nil, err
+	}
+	list, err := registry.List(ctx, registryURL, "")
+	if err != nil {
+		return "", nil, err
+	}
+	xs = make([]*registry.ArchivedExtension, len(list))
+	for i, x := range list {
+		xs[i] = &registry.ArchivedExtension{Extension: *x}
+	}
+	return registryURL.String(), xs, nil
+}
+
+// mirrorExtension creates or updates the mirrored extension. If its latest release is newer than
+// the mirrored release, the latest release (including its bundle and source map) is mirrored. It
+// returns the ID of the mirrored extension (which is nonzero even if mirroring its release failed).
+func mirrorExtension(ctx context.Context, source string, x *registry.ArchivedExtension) (id int32, err error) {
+	id, err = dbMirroredExtensions{}.Upsert(ctx, x.UUID, x.ExtensionID, source)
+	if err != nil {
+		return 0, err
+	}
+
+	// PostgreSQL timestamps have microsecond precision.
+	publishedAt := x.PublishedAt.Truncate(time.Microsecond)
+	latest, err := dbReleases{}.GetLatest(ctx, id, "release", false)
+	if err != nil && !errcode.IsNotFound(err) {
+		return id, err
+	}
+	if latest != nil && latest.CreatedAt.Equal(publishedAt) {
+		return id, nil // already up to date
+	}
+
+	release, err := fetchMirroredRelease(ctx, x)
+	if err != nil {
+		return id, err
+	}
+	release.RegistryExtensionID = id
+	release.CreatedAt = publishedAt
+	_, err = dbMirroredExtensions{}.AddRelease(ctx, release)
+	return id, err
+}
+
+// sourceMappingURLRegex matches the URL in a `//# sourceMappingURL=` directive in a bundle.
+var sourceMappingURLRegex = regexp.MustCompile(`(?m)^//# sourceMappingURL=(\S+)\s*$`)
+
+// fetchMirroredRelease returns the latest release of the extension, fetching its bundle (and
+// source map, if any) from the URL in its manifest unless they are included in the archive. The
+// "url" field is removed from the manifest so that the mirrored bundle is used.
+func fetchMirroredRelease(ctx context.Context, x *registry.ArchivedExtension) (*dbRelease, error) {
+	var manifest map[string]interface{}
+	if err := jsonc.Unmarshal(*x.Manifest, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing extension manifest: %s", err)
+	}
+	if manifest == nil {
+		manifest = map[string]interface{}{}
+	}
+
+	bundle, sourceMap := x.Bundle, x.SourceMap
+	if bundle == nil {
+		bundleURLStr, _ := manifest["url"].(string)
+		if bundleURLStr == "" {
+			return nil, fmt.Errorf("extension manifest has no bundle URL")
+		}
+		bundleURL, err := url.Parse(bundleURLStr)
+		if err != nil {
+			return nil, err
+		}
+		data, err := fetchExtensionArtifact(ctx, bundleURL)
+		if err != nil {
+			return nil, err
+		}
+		bundle = &data
+
+		// The source map is optional, so ignore errors fetching it.
+		if m := sourceMappingURLRegex.FindStringSubmatch(data); m != nil {
+			if sourceMapURL, err := bundleURL.Parse(m[1]); err == nil && (sourceMapURL.Scheme == "http" || sourceMapURL.Scheme == "https") {
+				if data, err := fetchExtensionArtifact(ctx, sourceMapURL); err == nil {
+					sourceMap = &data
+				} else {
+					log15.Warn("Failed to fetch source map of mirrored extension.", "extension", x.ExtensionID, "url", sourceMapURL, "error", err)
+				}
+			}
+		}
+	}
+
+	delete(manifest, "url")
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return &dbRelease{
+		ReleaseTag: "release",
+		Manifest:   string(manifestJSON),
+		Bundle:     bundle,
+		SourceMap:  sourceMap,
+	}, nil
+}
+
+func fetchExtensionArtifact(ctx context.Context, u *url.URL) (string, error) {
+	resp, err := ctxhttp.Get(ctx, registry.HTTPClient, u.String())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching %s: HTTP error %d", u, resp.StatusCode)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	return string(data), err
+}
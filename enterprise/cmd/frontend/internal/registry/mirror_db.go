@@ -0,0 +1,208 @@
+package registry
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/keegancsmith/sqlf"
+	"github.com/lib/pq"
+	frontendregistry "github.com/sourcegraph/sourcegraph/cmd/frontend/registry"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
+)
+
+// dbMirroredExtension describes an extension that was mirrored from a remote registry (or from an
+// extension archive) into the local extension registry.
+//
+// Mirrored extensions are stored in the same table as local extensions, but they have no local
+// publisher and they keep their remote extension ID (such as "alice/myextension"). They are never
+// returned by dbExtensions, because they are used in place of the remote registry instead of as
+// local extensions.
+type dbMirroredExtension struct {
+	ID          int32
+	UUID        string
+	ExtensionID string // the extension ID on the remote registry
+	Name        string
+	Source      string // the URL of the registry that the extension was mirrored from
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type dbMirroredExtensions struct{}
+
+// Upsert creates or updates the mirrored extension (by UUID). If a mirrored extension was
+// previously deleted, it is restored. It returns the ID of the extension in the local registry.
+func (dbMirroredExtensions) Upsert(ctx context.Context, uuid, extensionID, source string) (id int32, err error) {
+	if mocks.mirroredExtensions.Upsert != nil {
+		return mocks.mirroredExtensions.Upsert(uuid, extensionID, source)
+	}
+
+	prefix, _, name, err := frontendregistry.SplitExtensionID(extensionID)
+	if err != nil {
+		return 0, err
+	}
+	if prefix != "" {
+		return 0, fmt.Errorf("invalid mirrored extension ID %q (registry prefix is not allowed)", extensionID)
+	}
+
+	// The remote registry may have recreated an extension with the same extension ID (and a new
+	// UUID), so delete any other mirrored extension with this extension ID first.
+	if _, err := dbconn.Global.ExecContext(ctx,
+		"UPDATE registry_extensions SET deleted_at=now() WHERE mirrored_extension_id=$1 AND uuid<>$2 AND deleted_at IS NULL",
+		extensionID, uuid,
+	); err != nil {
+		return 0, err
+	}
+
+	if err := dbconn.Global.QueryRowContext(ctx,
+		// The WHERE clause prevents a remote extension from overwriting a local extension that has
+		// the same UUID.
+		`
+INSERT INTO registry_extensions(uuid, name, mirrored_extension_id, mirror_source)
+VALUES($1, $2, $3, $4)
+ON CONFLICT (uuid) DO UPDATE SET name=excluded.name, mirrored_extension_id=excluded.mirrored_extension_id, mirror_source=excluded.mirror_source, updated_at=now(), deleted_at=NULL
+WHERE registry_extensions.mirrored_extension_id IS NOT NULL
+RETURNING id
+`,
+		uuid, name, extensionID, source,
+	).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, fmt.Errorf("unable to mirror extension %q: a local extension with UUID %q already exists", extensionID, uuid)
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// GetByUUID retrieves the mirrored extension (if any) given its UUID on the remote registry.
+func (s dbMirroredExtensions) GetByUUID(ctx context.Context, uuid string) (*dbMirroredExtension, error) {
+	if mocks.mirroredExtensions.GetByUUID != nil {
+		return mocks.mirroredExtensions.GetByUUID(uuid)
+	}
+	return s.getBy(ctx, sqlf.Sprintf("uuid=%s", uuid), uuid)
+}
+
+// GetByExtensionID retrieves the mirrored extension (if any) given its extension ID on the remote
+// registry.
+func (s dbMirroredExtensions) GetByExtensionID(ctx context.Context, extensionID string) (*dbMirroredExtension, error) {
+	if mocks.mirroredExtensions.GetByExtensionID != nil {
+		return mocks.mirroredExtensions.GetByExtensionID(extensionID)
+	}
+	return s.getBy(ctx, sqlf.Sprintf("mirrored_extension_id=%s", extensionID), fmt.Sprintf("extensionID %q", extensionID))
+}
+
+func (s dbMirroredExtensions) getBy(ctx context.Context, cond *sqlf.Query, errArg interface{}) (*dbMirroredExtension, error) {
+	results, err := s.list(ctx, cond)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, extensionNotFoundError{[]interface{}{errArg}}
+	}
+	return results[0], nil
+}
+
+// List lists all mirrored extensions, ordered by extension ID.
+func (s dbMirroredExtensions) List(ctx context.Context) ([]*dbMirroredExtension, error) {
+	if mocks.mirroredExtensions.List != nil {
+		return mocks.mirroredExtensions.List()
+	}
+	return s.list(ctx, sqlf.Sprintf("TRUE"))
+}
+
+func (dbMirroredExtensions) list(ctx context.Context, cond *sqlf.Query) ([]*dbMirroredExtension, error) {
+	q := sqlf.Sprintf(`
+SELECT id, uuid, mirrored_extension_id, name, COALESCE(mirror_source, ''), created_at, updated_at
+FROM registry_extensions
+WHERE (%s) AND mirrored_extension_id IS NOT NULL AND deleted_at IS NULL
+ORDER BY mirrored_extension_id ASC`, cond)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*dbMirroredExtension
+	for rows.Next() {
+		var t dbMirroredExtension
+		if err := rows.Scan(&t.ID, &t.UUID, &t.ExtensionID, &t.Name, &t.Source, &t.CreatedAt, &t.UpdatedAt); err != nil {
+			return nil, err
+		}
+		results = append(results, &t)
+	}
+	return results, rows.Err()
+}
+
+// DeleteAllExcept marks all mirrored extensions as deleted, except for those with the given IDs.
+// It is used to remove extensions that are no longer present in (or allowed from) the mirror
+// source.
+func (dbMirroredExtensions) DeleteAllExcept(ctx context.Context, ids []int32) error {
+	if mocks.mirroredExtensions.DeleteAllExcept != nil {
+		return mocks.mirroredExtensions.DeleteAllExcept(ids)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx,
+		"UPDATE registry_extensions SET deleted_at=now() WHERE mirrored_extension_id IS NOT NULL AND deleted_at IS NULL AND NOT (id = ANY($1))",
+		pq.Array(ids),
+	)
+	return err
+}
+
+// AddRelease adds the release to the mirrored extension, and then deletes the extension's previous
+// releases (only the latest release of a mirrored extension is kept). The release's creation date
+// is the date that it was published on the remote registry. The release.ID and
+// release.CreatorUserID fields are ignored.
+func (dbMirroredExtensions) AddRelease(ctx context.Context, release *dbRelease) (id int64, err error) {
+	if mocks.mirroredExtensions.AddRelease != nil {
+		return mocks.mirroredExtensions.AddRelease(release)
+	}
+
+	tx, err := dbconn.Global.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			rollErr := tx.Rollback()
+			if rollErr != nil {
+				err = multierror.Append(err, rollErr)
+			}
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	if err := tx.QueryRowContext(ctx,
+		// Only add releases to mirrored extensions (never to local extensions).
+		`
+INSERT INTO registry_extension_releases(registry_extension_id, creator_user_id, release_version, release_tag, manifest, bundle, source_map, created_at)
+SELECT id, NULL, $2, $3, $4, $5, $6, $7 FROM registry_extensions WHERE id=$1 AND mirrored_extension_id IS NOT NULL
+RETURNING id
+`,
+		release.RegistryExtensionID, release.ReleaseVersion, release.ReleaseTag, release.Manifest, release.Bundle, release.SourceMap, release.CreatedAt,
+	).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, extensionNotFoundError{[]interface{}{fmt.Sprintf("mirrored extension %d", release.RegistryExtensionID)}}
+		}
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Message == "invalid input syntax for type json" {
+			return 0, errInvalidJSONInManifest
+		}
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM registry_extension_releases WHERE registry_extension_id=$1 AND id<>$2", release.RegistryExtensionID, id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// mockMirroredExtensions mocks the mirrored registry extensions store.
+type mockMirroredExtensions struct {
+	Upsert           func(uuid, extensionID, source string) (int32, error)
+	GetByUUID        func(uuid string) (*dbMirroredExtension, error)
+	GetByExtensionID func(extensionID string) (*dbMirroredExtension, error)
+	List             func() ([]*dbMirroredExtension, error)
+	DeleteAllExcept  func(ids []int32) error
+	AddRelease       func(release *dbRelease) (int64, error)
+}
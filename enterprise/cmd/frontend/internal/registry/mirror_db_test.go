@@ -0,0 +1,125 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+func TestMirroredExtensions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	ctx := dbtesting.TestContext(t)
+
+	const (
+		uuid1 = "00000000-0000-0000-0000-000000000001"
+		uuid2 = "00000000-0000-0000-0000-000000000002"
+	)
+
+	id, err := dbMirroredExtensions{}.Upsert(ctx, uuid1, "alice/x", "https://example.com/.api/registry")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id2, err := (dbMirroredExtensions{}).Upsert(ctx, uuid1, "alice/x", "https://example.com/.api/registry"); err != nil {
+		t.Fatal(err)
+	} else if id2 != id {
+		t.Errorf("got ID %d after 2nd upsert, want %d", id2, id)
+	}
+	if _, err := (dbMirroredExtensions{}).Upsert(ctx, uuid2, "example.com/alice/y", ""); err == nil {
+		t.Error("want error for extension ID with registry prefix")
+	}
+
+	x, err := dbMirroredExtensions{}.GetByExtensionID(ctx, "alice/x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x.ID != id || x.UUID != uuid1 || x.Name != "x" || x.Source != "https://example.com/.api/registry" {
+		t.Errorf("got %+v", x)
+	}
+	if _, err := (dbMirroredExtensions{}).GetByUUID(ctx, uuid2); !errcode.IsNotFound(err) {
+		t.Errorf("got err %v, want errcode.IsNotFound", err)
+	}
+
+	t.Run("not a local extension", func(t *testing.T) {
+		user, err := db.Users.Create(ctx, db.NewUser{Username: "alice"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		localID, err := dbExtensions{}.Create(ctx, user.ID, 0, "x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := (dbExtensions{}).GetByID(ctx, id); !errcode.IsNotFound(err) {
+			t.Errorf("got err %v, want errcode.IsNotFound", err)
+		}
+		if local, err := (dbExtensions{}).GetByExtensionID(ctx, "alice/x"); err != nil {
+			t.Fatal(err)
+		} else if local.ID != localID {
+			t.Errorf("got local extension %d, want %d", local.ID, localID)
+		}
+		if n, err := (dbExtensions{}).Count(ctx, dbExtensionsListOptions{}); err != nil {
+			t.Fatal(err)
+		} else if n != 1 {
+			t.Errorf("got %d local extensions, want 1", n)
+		}
+		if _, err := (dbMirroredExtensions{}).AddRelease(ctx, &dbRelease{RegistryExtensionID: localID, ReleaseTag: "release", Manifest: `{}`}); !errcode.IsNotFound(err) {
+			t.Errorf("got err %v, want errcode.IsNotFound", err)
+		}
+	})
+
+	t.Run("AddRelease", func(t *testing.T) {
+		publishedAt := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+		if _, err := (dbMirroredExtensions{}).AddRelease(ctx, &dbRelease{RegistryExtensionID: id, ReleaseTag: "release", Manifest: `{"v": 1}`, Bundle: strptr("b1"), CreatedAt: publishedAt.Add(-time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+		releaseID, err := dbMirroredExtensions{}.AddRelease(ctx, &dbRelease{RegistryExtensionID: id, ReleaseTag: "release", Manifest: `{"v": 2}`, Bundle: strptr("b2"), CreatedAt: publishedAt})
+		if err != nil {
+			t.Fatal(err)
+		}
+		releases, err := dbReleases{}.List(ctx, id, "release")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(releases) != 1 || releases[0].ID != releaseID || releases[0].CreatorUserID != 0 || !releases[0].CreatedAt.Equal(publishedAt) {
+			t.Errorf("got releases %+v, want only release %d", releases, releaseID)
+		}
+		if bundle, _, err := (dbReleases{}).GetArtifacts(ctx, releaseID); err != nil {
+			t.Fatal(err)
+		} else if string(bundle) != "b2" {
+			t.Errorf("got bundle %q, want %q", bundle, "b2")
+		}
+	})
+
+	t.Run("DeleteAllExcept", func(t *testing.T) {
+		id2, err := dbMirroredExtensions{}.Upsert(ctx, uuid2, "bob/y", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := (dbMirroredExtensions{}).DeleteAllExcept(ctx, []int32{id2}); err != nil {
+			t.Fatal(err)
+		}
+		xs, err := dbMirroredExtensions{}.List(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(xs) != 1 || xs[0].ID != id2 {
+			t.Errorf("got %+v, want only extension %d", xs, id2)
+		}
+
+		// Upserting a deleted extension restores it.
+		if restoredID, err := (dbMirroredExtensions{}).Upsert(ctx, uuid1, "alice/x", ""); err != nil {
+			t.Fatal(err)
+		} else if restoredID != id {
+			t.Errorf("got ID %d, want %d", restoredID, id)
+		}
+		if xs, err := (dbMirroredExtensions{}).List(ctx); err != nil {
+			t.Fatal(err)
+		} else if len(xs) != 2 {
+			t.Errorf("got %d mirrored extensions, want 2", len(xs))
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/licensing"
+	"github.com/sourcegraph/sourcegraph/enterprise/pkg/license"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/registry"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestMirrorExtensions(t *testing.T) {
+	resetMocks()
+	defer resetMocks()
+	licensing.MockGetConfiguredProductLicenseInfo = func() (*license.Info, string, error) {
+		return &license.Info{Tags: licensing.EnterpriseTags}, "test-signature", nil
+	}
+	defer func() { licensing.MockGetConfiguredProductLicenseInfo = nil }()
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{Extensions: &schema.Extensions{
+		AllowRemoteExtensions: []string{"alice/new", "alice/current"},
+	}}})
+	defer conf.Mock(nil)
+
+	publishedAt := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	archive := registry.Archive{
+		RegistryURL: "https://example.com/.api/registry",
+		Extensions: []*registry.ArchivedExtension{
+			{
+				Extension: registry.Extension{UUID: "u1", ExtensionID: "alice/new", Manifest: strptr(`{"url": "https://example.com/x.js", "activationEvents": ["*"]}`), PublishedAt: publishedAt},
+				Bundle:    strptr("b"),
+				SourceMap: strptr("sm"),
+			},
+			{
+				Extension: registry.Extension{UUID: "u2", ExtensionID: "alice/current", Manifest: strptr(`{}`), PublishedAt: publishedAt},
+				Bundle:    strptr("b"),
+			},
+			{
+				Extension: registry.Extension{UUID: "u3", ExtensionID: "alice/disallowed", Manifest: strptr(`{}`), PublishedAt: publishedAt},
+				Bundle:    strptr("b"),
+			},
+		},
+	}
+	tmpDir, err := ioutil.TempDir("", "mirror")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	archivePath := filepath.Join(tmpDir, "extensions.json")
+	data, err := json.Marshal(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(archivePath, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := map[string]int32{"u1": 1, "u2": 2, "u3": 3}
+	mocks.mirroredExtensions.Upsert = func(uuid, extensionID, source string) (int32, error) {
+		if source != archive.RegistryURL {
+			t.Errorf("got source %q, want %q", source, archive.RegistryURL)
+		}
+		return ids[uuid], nil
+	}
+	mocks.releases.GetLatest = func(registryExtensionID int32, releaseTag string, includeArtifacts bool) (*dbRelease, error) {
+		if registryExtensionID == 2 {
+			return &dbRelease{CreatedAt: publishedAt}, nil
+		}
+		return nil, releaseNotFoundError{}
+	}
+	var added []*dbRelease
+	mocks.mirroredExtensions.AddRelease = func(release *dbRelease) (int64, error) {
+		added = append(added, release)
+		return 1, nil
+	}
+	var kept []int32
+	mocks.mirroredExtensions.DeleteAllExcept = func(ids []int32) error {
+		kept = ids
+		return nil
+	}
+
+	if err := mirrorExtensions(context.Background(), &schema.ExtensionsMirror{Archive: archivePath}); err != nil {
+		t.Fatal(err)
+	}
+	want := []*dbRelease{{
+		RegistryExtensionID: 1,
+		ReleaseTag:          "release",
+		Manifest:            "{\n  \"activationEvents\": [\n    \"*\"\n  ]\n}",
+		Bundle:              strptr("b"),
+		SourceMap:           strptr("sm"),
+		CreatedAt:           publishedAt,
+	}}
+	if !reflect.DeepEqual(added, want) {
+		t.Errorf("got added releases %+v, want %+v", added, want)
+	}
+	if want := []int32{1, 2}; !reflect.DeepEqual(kept, want) {
+		t.Errorf("got kept extensions %v, want %v", kept, want)
+	}
+}
+
+func TestFetchMirroredRelease(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/bundle/x.js":
+			w.Write([]byte("console.log(1)\n//# sourceMappingURL=x.js.map"))
+		case "/bundle/x.js.map":
+			w.Write([]byte("{}"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	release, err := fetchMirroredRelease(context.Background(), &registry.ArchivedExtension{
+		Extension: registry.Extension{ExtensionID: "alice/x", Manifest: strptr(`{"url": "` + ts.URL + `/bundle/x.js"}`)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{}"; release.Manifest != want {
+		t.Errorf("got manifest %q, want %q", release.Manifest, want)
+	}
+	if release.Bundle == nil || *release.Bundle != "console.log(1)\n//# sourceMappingURL=x.js.map" {
+		t.Errorf("got bundle %v", release.Bundle)
+	}
+	if release.SourceMap == nil || *release.SourceMap != "{}" {
+		t.Errorf("got source map %v", release.SourceMap)
+	}
+
+	if _, err := fetchMirroredRelease(context.Background(), &registry.ArchivedExtension{
+		Extension: registry.Extension{ExtensionID: "alice/y", Manifest: strptr(`{"url": "` + ts.URL + `/missing.js"}`)},
+	}); err == nil {
+		t.Error("want error for missing bundle")
+	}
+}
@@ -5,8 +5,9 @@ func resetMocks() {
 }
 
 type dbMocks struct {
-	extensions mockExtensions
-	releases   mockReleases
+	extensions         mockExtensions
+	releases           mockReleases
+	mirroredExtensions mockMirroredExtensions
 }
 
 var mocks dbMocks
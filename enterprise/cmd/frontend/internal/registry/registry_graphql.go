@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/coreos/go-semver/semver"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	frontendregistry "github.com/sourcegraph/sourcegraph/cmd/frontend/registry"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/licensing"
@@ -19,6 +21,7 @@ func init() {
 	frontendregistry.ExtensionRegistry.UpdateExtensionFunc = extensionRegistryUpdateExtension
 	frontendregistry.ExtensionRegistry.DeleteExtensionFunc = extensionRegistryDeleteExtension
 	frontendregistry.ExtensionRegistry.PublishExtensionFunc = extensionRegistryPublishExtension
+	frontendregistry.ExtensionRegistry.YankExtensionReleaseFunc = extensionRegistryYankExtensionRelease
 }
 
 func registryExtensionByIDInt32(ctx context.Context, id int32) (graphqlbackend.RegistryExtension, error) {
@@ -174,9 +177,19 @@ func extensionRegistryPublishExtension(ctx context.Context, args *graphqlbackend
 		}
 	}
 
+	var version *string
+	if args.Version != nil {
+		v, err := parseReleaseVersion(*args.Version)
+		if err != nil {
+			return nil, err
+		}
+		version = &v
+	}
+
 	release := dbRelease{
 		RegistryExtensionID: id.LocalID,
 		CreatorUserID:       actor.FromContext(ctx).UID,
+		ReleaseVersion:      version,
 		ReleaseTag:          "release",
 		Manifest:            args.Manifest,
 		Bundle:              args.Bundle,
@@ -187,3 +200,38 @@ func extensionRegistryPublishExtension(ctx context.Context, args *graphqlbackend
 	}
 	return &frontendregistry.ExtensionRegistryMutationResult{ID: id.LocalID}, nil
 }
+
+func extensionRegistryYankExtensionRelease(ctx context.Context, args *graphqlbackend.ExtensionRegistryYankExtensionReleaseArgs) (*graphqlbackend.EmptyResponse, error) {
+	if err := licensing.CheckFeature(licensing.FeatureExtensionRegistry); err != nil {
+		return nil, err
+	}
+
+	id, err := frontendregistry.UnmarshalRegistryExtensionID(args.Extension)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Check that the current user is authorized to yank the extension's releases.
+	if err := viewerCanAdministerExtension(ctx, id); err != nil {
+		return nil, err
+	}
+
+	version, err := parseReleaseVersion(args.Version)
+	if err != nil {
+		return nil, err
+	}
+	if err := (dbReleases{}).SetYanked(ctx, id.LocalID, version, args.Yanked); err != nil {
+		return nil, err
+	}
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
+// parseReleaseVersion validates that the release version is a semantic version (such as 1.2.3) and
+// returns it in canonical form.
+func parseReleaseVersion(version string) (string, error) {
+	v, err := semver.NewVersion(strings.TrimPrefix(version, "v"))
+	if err != nil {
+		return "", fmt.Errorf("invalid extension release version %q (it must be a semantic version, such as 1.2.3): %s", version, err)
+	}
+	return v.String(), nil
+}
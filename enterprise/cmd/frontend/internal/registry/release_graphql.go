@@ -0,0 +1,90 @@
+package registry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+// registryExtensionReleaseConnection resolves the release history of an extension in the
+// extension registry.
+type registryExtensionReleaseConnection struct {
+	registryExtensionID int32
+	first               *int32
+
+	// cache results because they are used by multiple fields
+	once     sync.Once
+	releases []*dbRelease
+	err      error
+}
+
+func (r *registryExtensionReleaseConnection) compute(ctx context.Context) ([]*dbRelease, error) {
+	r.once.Do(func() {
+		r.releases, r.err = dbReleases{}.List(ctx, r.registryExtensionID, "release")
+	})
+	return r.releases, r.err
+}
+
+func (r *registryExtensionReleaseConnection) Nodes(ctx context.Context) ([]graphqlbackend.RegistryExtensionRelease, error) {
+	releases, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.first != nil && len(releases) > int(*r.first) {
+		releases = releases[:*r.first]
+	}
+
+	l := make([]graphqlbackend.RegistryExtensionRelease, len(releases))
+	for i, release := range releases {
+		l[i] = &registryExtensionReleaseResolver{v: release}
+	}
+	return l, nil
+}
+
+func (r *registryExtensionReleaseConnection) TotalCount(ctx context.Context) (int32, error) {
+	releases, err := r.compute(ctx)
+	return int32(len(releases)), err
+}
+
+func (r *registryExtensionReleaseConnection) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	releases, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.first != nil && len(releases) > int(*r.first)), nil
+}
+
+// registryExtensionReleaseResolver implements the GraphQL type RegistryExtensionRelease.
+type registryExtensionReleaseResolver struct {
+	v *dbRelease
+}
+
+func (r *registryExtensionReleaseResolver) Version() *string { return r.v.ReleaseVersion }
+
+func (r *registryExtensionReleaseResolver) Creator(ctx context.Context) (*graphqlbackend.UserResolver, error) {
+	if r.v.CreatorUserID == 0 {
+		return nil, nil // the release was mirrored from a remote registry
+	}
+	user, err := graphqlbackend.UserByIDInt32(ctx, r.v.CreatorUserID)
+	if errcode.IsNotFound(err) {
+		return nil, nil // the user was deleted
+	}
+	return user, err
+}
+
+func (r *registryExtensionReleaseResolver) CreatedAt() string {
+	return r.v.CreatedAt.Format(time.RFC3339)
+}
+
+func (r *registryExtensionReleaseResolver) Yanked() bool { return r.v.YankedAt != nil }
+
+func (r *registryExtensionReleaseResolver) YankedAt() *string {
+	if r.v.YankedAt == nil {
+		return nil
+	}
+	return strptr(r.v.YankedAt.Format(time.RFC3339))
+}
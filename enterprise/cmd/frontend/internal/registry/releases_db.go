@@ -16,13 +16,14 @@ import (
 type dbRelease struct {
 	ID                  int64
 	RegistryExtensionID int32
-	CreatorUserID       int32
+	CreatorUserID       int32 // zero for releases of extensions mirrored from a remote registry
 	ReleaseVersion      *string
 	ReleaseTag          string
 	Manifest            string
 	Bundle              *string
 	SourceMap           *string
 	CreatedAt           time.Time
+	YankedAt            *time.Time // when the publisher marked the release as not to be used (nil if not yanked)
 }
 
 type dbReleases struct{}
@@ -40,7 +41,10 @@ func (err releaseNotFoundError) Error() string {
 	return fmt.Sprintf("registry extension release not found: %v", err.args)
 }
 
-var errInvalidJSONInManifest = errors.New("invalid syntax in extension manifest JSON")
+var (
+	errInvalidJSONInManifest = errors.New("invalid syntax in extension manifest JSON")
+	errReleaseVersionExists  = errors.New("a release of the extension with the same version already exists")
+)
 
 // Create creates a new release of an extension in the extension registry. The release.ID and
 // release.CreatedAt fields are ignored (they are populated automatically by the database).
@@ -61,6 +65,9 @@ RETURNING id
 			if pqErr.Message == "invalid input syntax for type json" {
 				return 0, errInvalidJSONInManifest
 			}
+			if pqErr.Constraint == "registry_extension_releases_version" {
+				return 0, errReleaseVersionExists
+			}
 		}
 		return 0, err
 	}
@@ -68,7 +75,7 @@ RETURNING id
 }
 
 // GetLatest gets the latest release for the extension with the given release tag (e.g.,
-// "release"). If includeArtifacts is true, it populates the
+// "release"), skipping releases that were yanked. If includeArtifacts is true, it populates the
 // (*dbRelease).{Bundle,SourceMap} fields, which may be large.
 func (dbReleases) GetLatest(ctx context.Context, registryExtensionID int32, releaseTag string, includeArtifacts bool) (*dbRelease, error) {
 	if mocks.releases.GetLatest != nil {
@@ -76,13 +83,13 @@ func (dbReleases) GetLatest(ctx context.Context, registryExtensionID int32, rele
 	}
 
 	q := sqlf.Sprintf(`
-SELECT id, registry_extension_id, creator_user_id, release_version, release_tag, manifest, CASE WHEN %v::boolean THEN bundle ELSE null END AS bundle, CASE WHEN %v::boolean THEN source_map ELSE null END AS source_map, created_at
+SELECT id, registry_extension_id, COALESCE(creator_user_id, 0), release_version, release_tag, manifest, CASE WHEN %v::boolean THEN bundle ELSE null END AS bundle, CASE WHEN %v::boolean THEN source_map ELSE null END AS source_map, created_at, yanked_at
 FROM registry_extension_releases
-WHERE registry_extension_id=%d AND release_tag=%s AND deleted_at IS NULL
+WHERE registry_extension_id=%d AND release_tag=%s AND deleted_at IS NULL AND yanked_at IS NULL
 ORDER BY created_at DESC
 LIMIT 1`, includeArtifacts, includeArtifacts, registryExtensionID, releaseTag)
 	var r dbRelease
-	err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&r.ID, &r.RegistryExtensionID, &r.CreatorUserID, &r.ReleaseVersion, &r.ReleaseTag, &r.Manifest, &r.Bundle, &r.SourceMap, &r.CreatedAt)
+	err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&r.ID, &r.RegistryExtensionID, &r.CreatorUserID, &r.ReleaseVersion, &r.ReleaseTag, &r.Manifest, &r.Bundle, &r.SourceMap, &r.CreatedAt, &r.YankedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, releaseNotFoundError{[]interface{}{fmt.Sprintf("latest for registry extension ID %d tag %q", registryExtensionID, releaseTag)}}
@@ -92,6 +99,60 @@ LIMIT 1`, includeArtifacts, includeArtifacts, registryExtensionID, releaseTag)
 	return &r, nil
 }
 
+// List lists all releases for the extension with the given release tag, including releases that
+// were yanked, most recent first. It does not populate the (*dbRelease).{Bundle,SourceMap} fields.
+func (dbReleases) List(ctx context.Context, registryExtensionID int32, releaseTag string) ([]*dbRelease, error) {
+	if mocks.releases.List != nil {
+		return mocks.releases.List(registryExtensionID, releaseTag)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT id, registry_extension_id, COALESCE(creator_user_id, 0), release_version, release_tag, manifest, created_at, yanked_at
+FROM registry_extension_releases
+WHERE registry_extension_id=%d AND release_tag=%s AND deleted_at IS NULL
+ORDER BY created_at DESC, id DESC`, registryExtensionID, releaseTag)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var releases []*dbRelease
+	for rows.Next() {
+		var r dbRelease
+		if err := rows.Scan(&r.ID, &r.RegistryExtensionID, &r.CreatorUserID, &r.ReleaseVersion, &r.ReleaseTag, &r.Manifest, &r.CreatedAt, &r.YankedAt); err != nil {
+			return nil, err
+		}
+		releases = append(releases, &r)
+	}
+	return releases, rows.Err()
+}
+
+// SetYanked marks the release of the extension with the given version as yanked (or not yanked).
+// Yanked releases are listed in the extension's release history, but they are never used as the
+// latest release or the release in a version range.
+func (dbReleases) SetYanked(ctx context.Context, registryExtensionID int32, version string, yanked bool) error {
+	if mocks.releases.SetYanked != nil {
+		return mocks.releases.SetYanked(registryExtensionID, version, yanked)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx,
+		"UPDATE registry_extension_releases SET yanked_at=(CASE WHEN $3::boolean THEN COALESCE(yanked_at, now()) ELSE NULL END) WHERE registry_extension_id=$1 AND release_version=$2 AND deleted_at IS NULL",
+		registryExtensionID, version, yanked,
+	)
+	if err != nil {
+		return err
+	}
+	nrows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if nrows == 0 {
+		return releaseNotFoundError{[]interface{}{fmt.Sprintf("registry extension ID %d version %q", registryExtensionID, version)}}
+	}
+	return nil
+}
+
 // GetArtifacts gets the bundled JavaScript source file contents and the source map for a release
 // (by ID).
 func (dbReleases) GetArtifacts(ctx context.Context, id int64) (bundle, sourcemap []byte, err error) {
@@ -115,4 +176,6 @@ WHERE id=%d AND deleted_at IS NULL`, id)
 type mockReleases struct {
 	Create    func(release *dbRelease) (int64, error)
 	GetLatest func(registryExtensionID int32, releaseTag string, includeArtifacts bool) (*dbRelease, error)
+	List      func(registryExtensionID int32, releaseTag string) ([]*dbRelease, error)
+	SetYanked func(registryExtensionID int32, version string, yanked bool) error
 }
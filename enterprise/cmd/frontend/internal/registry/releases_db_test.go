@@ -161,4 +161,58 @@ func TestRegistryExtensionReleases(t *testing.T) {
 			t.Error("sourcemap != nil")
 		}
 	})
+
+	t.Run("versions and yanking", func(t *testing.T) {
+		xid, err := (dbExtensions{}).Create(ctx, user.ID, 0, "y")
+		if err != nil {
+			t.Fatal(err)
+		}
+		create := func(version string) int64 {
+			id, err := dbReleases{}.Create(ctx, &dbRelease{
+				RegistryExtensionID: xid,
+				CreatorUserID:       user.ID,
+				ReleaseVersion:      strptr(version),
+				ReleaseTag:          "release",
+				Manifest:            `{}`,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			return id
+		}
+		id1 := create("1.0.0")
+		id2 := create("1.1.0")
+		if _, err := (dbReleases{}).Create(ctx, &dbRelease{RegistryExtensionID: xid, CreatorUserID: user.ID, ReleaseVersion: strptr("1.1.0"), ReleaseTag: "release", Manifest: `{}`}); err != errReleaseVersionExists {
+			t.Errorf("got error %v, want %v", err, errReleaseVersionExists)
+		}
+
+		if err := (dbReleases{}).SetYanked(ctx, xid, "1.1.0", true); err != nil {
+			t.Fatal(err)
+		}
+		if err := (dbReleases{}).SetYanked(ctx, xid, "9.9.9", true); !errcode.IsNotFound(err) {
+			t.Errorf("got err %v, want errcode.IsNotFound", err)
+		}
+		if r, err := (dbReleases{}).GetLatest(ctx, xid, "release", false); err != nil {
+			t.Fatal(err)
+		} else if r.ID != id1 {
+			t.Errorf("got latest release %d, want %d (the release before the yanked release)", r.ID, id1)
+		}
+
+		releases, err := dbReleases{}.List(ctx, xid, "release")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(releases) != 2 || releases[0].ID != id2 || releases[0].YankedAt == nil || releases[1].ID != id1 || releases[1].YankedAt != nil {
+			t.Errorf("got releases %+v, want %d (yanked) and %d", releases, id2, id1)
+		}
+
+		if err := (dbReleases{}).SetYanked(ctx, xid, "1.1.0", false); err != nil {
+			t.Fatal(err)
+		}
+		if r, err := (dbReleases{}).GetLatest(ctx, xid, "release", false); err != nil {
+			t.Fatal(err)
+		} else if r.ID != id2 {
+			t.Errorf("got latest release %d, want %d", r.ID, id2)
+		}
+	})
 }
@@ -0,0 +1,141 @@
+package registry
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-semver/semver"
+)
+
+// versionRange is a range of extension release versions, as specified in the "extensions.versions"
+// settings property to pin an extension.
+//
+// The following syntaxes are supported (with the same meaning as in npm):
+//
+//   - "1.2.3" or "=1.2.3": exactly version 1.2.3
+//   - "^1.2.3": >=1.2.3 <2.0.0 (or <0.3.0 if the major version is 0)
+//   - "~1.2.3": >=1.2.3 <1.3.0
+//   - "1.2", "1.2.x", or "~1.2": >=1.2.0 <1.3.0
+//   - "^1.2": >=1.2.0 <2.0.0
+//   - "1", "1.x", or "^1": >=1.0.0 <2.0.0
+//   - "*", "x", or "latest": any version
+//
+// Pre-release versions (such as 1.2.3-beta.1) are only in a range that specifies them exactly.
+type versionRange struct {
+	min   semver.Version
+	max   *semver.Version // exclusive upper bound (nil for no upper bound)
+	exact bool
+}
+
+func parseVersionRange(s string) (*versionRange, error) {
+	spec := strings.TrimSpace(s)
+	switch spec {
+	case "", "*", "x", "X", "latest":
+		return &versionRange{}, nil
+	}
+
+	var op byte
+	switch spec[0] {
+	case '^', '~', '=':
+		op = spec[0]
+		spec = spec[1:]
+	}
+	spec = strings.TrimPrefix(spec, "v")
+
+	// Exact versions (which may have a pre-release suffix).
+	if strings.Count(spec, ".") == 2 && !strings.ContainsAny(spec, "xX*") {
+		v, err := semver.NewVersion(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid extension version range %q: %s", s, err)
+		}
+		r := &versionRange{min: *v}
+		switch op {
+		case 0, '=':
+			r.exact = true
+		case '^':
+			switch {
+			case v.Major > 0:
+				r.max = &semver.Version{Major: v.Major + 1}
+			case v.Minor > 0:
+				r.max = &semver.Version{Minor: v.Minor + 1}
+			default:
+				r.max = &semver.Version{Patch: v.Patch + 1}
+			}
+		case '~':
+			r.max = &semver.Version{Major: v.Major, Minor: v.Minor + 1}
+		}
+		return r, nil
+	}
+
+	// Partial versions (such as "1.2" or "1.x").
+	if op == '=' {
+		return nil, fmt.Errorf("invalid extension version range %q: exact versions must have 3 parts", s)
+	}
+	parts := strings.Split(spec, ".")
+	if len(parts) > 3 {
+		return nil, fmt.Errorf("invalid extension version range %q", s)
+	}
+	var nums []int64
+	for _, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			break
+		}
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid extension version range %q", s)
+		}
+		nums = append(nums, n)
+	}
+	switch len(nums) {
+	case 0:
+		return &versionRange{}, nil
+	case 1:
+		return &versionRange{
+			min: semver.Version{Major: nums[0]},
+			max: &semver.Version{Major: nums[0] + 1},
+		}, nil
+	default:
+		r := &versionRange{
+			min: semver.Version{Major: nums[0], Minor: nums[1]},
+			max: &semver.Version{Major: nums[0], Minor: nums[1] + 1},
+		}
+		if op == '^' && nums[0] > 0 {
+			r.max = &semver.Version{Major: nums[0] + 1}
+		}
+		return r, nil
+	}
+}
+
+// contains reports whether the version is in the range.
+func (r *versionRange) contains(v semver.Version) bool {
+	if r.exact {
+		return v.Equal(r.min)
+	}
+	if v.PreRelease != "" {
+		return false
+	}
+	return !v.LessThan(r.min) && (r.max == nil || v.LessThan(*r.max))
+}
+
+// latestReleaseInRange returns the non-yanked release with the greatest version in the range, or
+// nil if there is none. Releases without a (valid) version are never in a range.
+func latestReleaseInRange(releases []*dbRelease, r *versionRange) *dbRelease {
+	var (
+		latest        *dbRelease
+		latestVersion *semver.Version
+	)
+	for _, release := range releases {
+		if release.YankedAt != nil || release.ReleaseVersion == nil {
+			continue
+		}
+		v, err := semver.NewVersion(*release.ReleaseVersion)
+		if err != nil || !r.contains(*v) {
+			continue
+		}
+		if latestVersion == nil || latestVersion.LessThan(*v) {
+			latest, latestVersion = release, v
+		}
+	}
+	return latest
+}
@@ -0,0 +1,78 @@
+package registry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/coreos/go-semver/semver"
+)
+
+func TestParseVersionRange(t *testing.T) {
+	tests := map[string]struct {
+		in, out []string
+	}{
+		"":         {in: []string{"0.0.1", "1.2.3", "9.0.0"}, out: []string{"1.0.0-beta.1"}},
+		"latest":   {in: []string{"0.0.1", "9.0.0"}},
+		"1.2.3":    {in: []string{"1.2.3"}, out: []string{"1.2.4", "1.2.2"}},
+		"=1.2.3":   {in: []string{"1.2.3"}, out: []string{"1.2.4"}},
+		"v1.2.3":   {in: []string{"1.2.3"}, out: []string{"1.2.4"}},
+		"^1.2.3":   {in: []string{"1.2.3", "1.9.0"}, out: []string{"1.2.2", "2.0.0", "1.3.0-beta.1"}},
+		"^0.2.3":   {in: []string{"0.2.3", "0.2.9"}, out: []string{"0.3.0", "1.0.0"}},
+		"^0.0.3":   {in: []string{"0.0.3"}, out: []string{"0.0.4"}},
+		"~1.2.3":   {in: []string{"1.2.3", "1.2.9"}, out: []string{"1.3.0"}},
+		"1.2":      {in: []string{"1.2.0", "1.2.9"}, out: []string{"1.3.0", "1.1.9"}},
+		"1.2.x":    {in: []string{"1.2.0", "1.2.9"}, out: []string{"1.3.0"}},
+		"^1.2":     {in: []string{"1.2.0", "1.9.0"}, out: []string{"2.0.0", "1.1.0"}},
+		"1":        {in: []string{"1.0.0", "1.9.9"}, out: []string{"2.0.0", "0.9.0"}},
+		"1.x":      {in: []string{"1.0.0", "1.9.9"}, out: []string{"2.0.0"}},
+		"1.2.3-b1": {in: []string{"1.2.3-b1"}, out: []string{"1.2.3"}},
+	}
+	for spec, test := range tests {
+		r, err := parseVersionRange(spec)
+		if err != nil {
+			t.Errorf("%q: %s", spec, err)
+			continue
+		}
+		for _, v := range test.in {
+			if !r.contains(*semver.New(v)) {
+				t.Errorf("%q: want %s to be in range", spec, v)
+			}
+		}
+		for _, v := range test.out {
+			if r.contains(*semver.New(v)) {
+				t.Errorf("%q: want %s not to be in range", spec, v)
+			}
+		}
+	}
+
+	for _, spec := range []string{"a", "1.2.3.4", "=1.2", "^1.a.3", "1.-2"} {
+		if _, err := parseVersionRange(spec); err == nil {
+			t.Errorf("%q: got no error for invalid version range", spec)
+		}
+	}
+}
+
+func TestLatestReleaseInRange(t *testing.T) {
+	now := time.Now()
+	releases := []*dbRelease{
+		{ID: 5},
+		{ID: 4, ReleaseVersion: strptr("1.3.0"), YankedAt: &now},
+		{ID: 3, ReleaseVersion: strptr("1.0.1")},
+		{ID: 2, ReleaseVersion: strptr("1.2.0")},
+		{ID: 1, ReleaseVersion: strptr("0.9.0")},
+	}
+	r, err := parseVersionRange("^1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := latestReleaseInRange(releases, r); got == nil || got.ID != 2 {
+		t.Errorf("got %+v, want release 2", got)
+	}
+	r, err = parseVersionRange("2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := latestReleaseInRange(releases, r); got != nil {
+		t.Errorf("got %+v, want nil", got)
+	}
+}
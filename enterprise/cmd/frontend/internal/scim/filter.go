@@ -0,0 +1,47 @@
+package scim
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// filterPattern matches the only form of SCIM filter that is supported: a single equality
+// comparison of an attribute with a string value (such as `userName eq "alice"`), which is what
+// identity providers use to look up existing resources.
+var filterPattern = regexp.MustCompile(`^\s*([A-Za-z][\w.]*)\s+(?i:eq)\s+("(?:[^"\\]|\\.)*")\s*$`)
+
+// parseFilter parses a filter of the form `attr eq "value"` and returns the attribute name and
+// value.
+func parseFilter(filter string) (attr, value string, err error) {
+	m := filterPattern.FindStringSubmatch(filter)
+	if m == nil {
+		return "", "", badRequest("invalidFilter", "Unsupported filter %q. Only filters of the form `attribute eq \"value\"` are supported.", filter)
+	}
+	value, err = strconv.Unquote(m[2])
+	if err != nil {
+		return "", "", badRequest("invalidFilter", "Invalid filter value in %q.", filter)
+	}
+	return m[1], value, nil
+}
+
+// parseValuePath parses a PATCH operation path of the form `attr[value eq "x"]` (such as
+// `members[value eq "123"]`) and returns the attribute name and the value. If path has no
+// filter, it returns path and an empty value.
+func parseValuePath(path string) (attr, value string, err error) {
+	i := strings.Index(path, "[")
+	if i == -1 {
+		return path, "", nil
+	}
+	if !strings.HasSuffix(path, "]") {
+		return "", "", badRequest("invalidPath", "Invalid path %q.", path)
+	}
+	filterAttr, value, err := parseFilter(path[i+1 : len(path)-1])
+	if err != nil {
+		return "", "", err
+	}
+	if !strings.EqualFold(filterAttr, "value") {
+		return "", "", badRequest("invalidPath", "Unsupported path %q. Only filters on the \"value\" attribute are supported.", path)
+	}
+	return path[:i], value, nil
+}
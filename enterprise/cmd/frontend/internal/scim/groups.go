@@ -0,0 +1,380 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+const groupSchema = "urn:ietf:params:scim:schemas:core:2.0:Group"
+
+// groupResource is a SCIM group, which corresponds to an organization.
+type groupResource struct {
+	Schemas     []string      `json:"schemas"`
+	ID          string        `json:"id,omitempty"`
+	DisplayName string        `json:"displayName"`
+	Members     []groupMember `json:"members"`
+	Meta        *meta         `json:"meta,omitempty"`
+}
+
+// groupMember is a member of a SCIM group. Its value is the ID of the user.
+type groupMember struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// orgName returns the name of the organization for a SCIM group with the given display name.
+func orgName(displayName string) (string, error) {
+	name, err := auth.NormalizeUsername(strings.Replace(strings.TrimSpace(displayName), "@", "-", -1))
+	if err != nil {
+		return "", badRequest("invalidValue", "Invalid displayName: %s", err)
+	}
+	return name, nil
+}
+
+func toGroupResource(ctx context.Context, org *types.Org) (*groupResource, error) {
+	members, err := db.OrgMembers.GetByOrgID(ctx, org.ID)
+	if err != nil {
+		return nil, err
+	}
+	res := &groupResource{
+		Schemas:     []string{groupSchema},
+		ID:          strconv.Itoa(int(org.ID)),
+		DisplayName: org.Name,
+		Members:     make([]groupMember, 0, len(members)),
+		Meta:        newMeta("Group", "Groups", strconv.Itoa(int(org.ID))),
+	}
+	if org.DisplayName != nil && *org.DisplayName != "" {
+		res.DisplayName = *org.DisplayName
+	}
+	res.Meta.Created = org.CreatedAt.Format(time.RFC3339)
+	res.Meta.LastModified = org.UpdatedAt.Format(time.RFC3339)
+	for _, m := range members {
+		id := strconv.Itoa(int(m.UserID))
+		res.Members = append(res.Members, groupMember{Value: id, Ref: newMeta("User", "Users", id).Location})
+	}
+	return res, nil
+}
+
+func getGroup(ctx context.Context, id string) (*types.Org, error) {
+	orgID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	return db.Orgs.GetByID(ctx, orgID)
+}
+
+func writeGroup(ctx context.Context, w http.ResponseWriter, status int, orgID int32) error {
+	org, err := db.Orgs.GetByID(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	res, err := toGroupResource(ctx, org)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, status, res)
+}
+
+func serveListGroups(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	startIndex, count := pagination(r)
+
+	var (
+		orgs  []*types.Org
+		total int
+	)
+	if filter := r.URL.Query().Get("filter"); filter != "" {
+		attr, value, err := parseFilter(filter)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(attr, "displayName") {
+			return badRequest("invalidFilter", "Unsupported filter attribute %q. Only displayName is supported.", attr)
+		}
+		if name, err := orgName(value); err == nil {
+			org, err := db.Orgs.GetByName(ctx, name)
+			if err != nil && !errcode.IsNotFound(err) {
+				return err
+			}
+			if org != nil {
+				total = 1
+				if startIndex == 1 && count > 0 {
+					orgs = append(orgs, org)
+				}
+			}
+		}
+	} else {
+		var err error
+		if total, err = db.Orgs.Count(ctx, db.OrgsListOptions{}); err != nil {
+			return err
+		}
+		if count > 0 {
+			orgs, err = db.Orgs.List(ctx, &db.OrgsListOptions{LimitOffset: &db.LimitOffset{Limit: count, Offset: startIndex - 1}})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	resources := make([]*groupResource, len(orgs))
+	for i, org := range orgs {
+		var err error
+		if resources[i], err = toGroupResource(ctx, org); err != nil {
+			return err
+		}
+	}
+	return writeJSON(w, http.StatusOK, newListResponse(total, startIndex, len(resources), resources))
+}
+
+func serveCreateGroup(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	var res groupResource
+	if err := readJSON(r, &res); err != nil {
+		return err
+	}
+	name, err := orgName(res.DisplayName)
+	if err != nil {
+		return err
+	}
+	userIDs, err := memberUserIDs(ctx, res.Members)
+	if err != nil {
+		return err
+	}
+
+	if _, err := db.Orgs.GetByName(ctx, name); err == nil {
+		return &scimError{status: http.StatusConflict, scimType: "uniqueness", detail: "A group with the displayName already exists."}
+	} else if !errcode.IsNotFound(err) {
+		return err
+	}
+	org, err := db.Orgs.Create(ctx, name, &res.DisplayName)
+	if err != nil {
+		return err
+	}
+	record(ctx, audit.OrgCreate, "Org", audit.OrgID(org.ID), map[string]interface{}{"name": org.Name})
+	if err := setMembers(ctx, org.ID, userIDs); err != nil {
+		return err
+	}
+	return writeGroup(ctx, w, http.StatusCreated, org.ID)
+}
+
+func serveGetGroup(w http.ResponseWriter, r *http.Request, id string) error {
+	org, err := getGroup(r.Context(), id)
+	if err != nil {
+		return err
+	}
+	return writeGroup(r.Context(), w, http.StatusOK, org.ID)
+}
+
+func serveReplaceGroup(w http.ResponseWriter, r *http.Request, id string) error {
+	ctx := r.Context()
+	org, err := getGroup(ctx, id)
+	if err != nil {
+		return err
+	}
+	var res groupResource
+	if err := readJSON(r, &res); err != nil {
+		return err
+	}
+	if err := updateGroup(ctx, org, &res); err != nil {
+		return err
+	}
+	return writeGroup(ctx, w, http.StatusOK, org.ID)
+}
+
+func servePatchGroup(w http.ResponseWriter, r *http.Request, id string) error {
+	ctx := r.Context()
+	org, err := getGroup(ctx, id)
+	if err != nil {
+		return err
+	}
+	req, err := readPatchRequest(r)
+	if err != nil {
+		return err
+	}
+
+	// Apply the operations to the current resource and then save it as if it had been replaced.
+	res, err := toGroupResource(ctx, org)
+	if err != nil {
+		return err
+	}
+	for _, op := range req.Operations {
+		if err := patchGroup(res, op); err != nil {
+			return err
+		}
+	}
+	if err := updateGroup(ctx, org, res); err != nil {
+		return err
+	}
+	return writeGroup(ctx, w, http.StatusOK, org.ID)
+}
+
+func serveDeleteGroup(w http.ResponseWriter, r *http.Request, id string) error {
+	orgID, err := parseID(id)
+	if err != nil {
+		return err
+	}
+	if err := db.Orgs.Delete(r.Context(), orgID); err != nil {
+		return err
+	}
+	record(r.Context(), audit.OrgDelete, "Org", audit.OrgID(orgID), nil)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// updateGroup updates the organization's display name and members to match res. The members are left
+// unchanged if they are absent. The organization's name can't be changed.
+func updateGroup(ctx context.Context, org *types.Org, res *groupResource) error {
+	userIDs, err := memberUserIDs(ctx, res.Members)
+	if err != nil {
+		return err
+	}
+	if res.DisplayName != "" && (org.DisplayName == nil || *org.DisplayName != res.DisplayName) {
+		if _, err := db.Orgs.Update(ctx, org.ID, &res.DisplayName); err != nil {
+			return err
+		}
+		record(ctx, audit.OrgUpdate, "Org", audit.OrgID(org.ID), map[string]interface{}{"displayName": res.DisplayName})
+	}
+	if res.Members == nil {
+		return nil
+	}
+	return setMembers(ctx, org.ID, userIDs)
+}
+
+// memberUserIDs returns the IDs of the users that are the group members.
+func memberUserIDs(ctx context.Context, members []groupMember) ([]int32, error) {
+	userIDs := make([]int32, 0, len(members))
+	for _, m := range members {
+		user, err := getUser(ctx, m.Value)
+		if _, ok := err.(*scimError); ok || errcode.IsNotFound(err) {
+			return nil, badRequest("invalidValue", "The member %q is not a user.", m.Value)
+		}
+		if err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+	return userIDs, nil
+}
+
+// setMembers makes the organization's members match userIDs.
+func setMembers(ctx context.Context, orgID int32, userIDs []int32) error {
+	have, err := db.OrgMembers.GetByOrgID(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	isMember := make(map[int32]bool, len(have))
+	for _, m := range have {
+		isMember[m.UserID] = true
+	}
+	want := make(map[int32]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if want[userID] {
+			continue
+		}
+		want[userID] = true
+		if !isMember[userID] {
+			if _, err := db.OrgMembers.Create(ctx, orgID, userID); err != nil {
+				return err
+			}
+			record(ctx, audit.OrgMemberAdd, "Org", audit.OrgID(orgID), map[string]interface{}{"user": audit.UserID(userID)})
+		}
+	}
+	for _, m := range have {
+		if !want[m.UserID] {
+			if err := db.OrgMembers.Remove(ctx, orgID, m.UserID); err != nil {
+				return err
+			}
+			record(ctx, audit.OrgMemberRemove, "Org", audit.OrgID(orgID), map[string]interface{}{"user": audit.UserID(m.UserID)})
+		}
+	}
+	return nil
+}
+
+// patchGroup applies a PATCH operation to res.
+func patchGroup(res *groupResource, op patchOperation) error {
+	if op.Path == "" {
+		if op.Op == "remove" {
+			return badRequest("noTarget", "A remove operation requires a path.")
+		}
+		var attrs map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &attrs); err != nil {
+			return badRequest("invalidValue", "The value of an operation without a path must be an object.")
+		}
+		for attr, value := range attrs {
+			if err := setGroupAttr(res, op.Op, attr, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	attr, value, err := parseValuePath(op.Path)
+	if err != nil {
+		return err
+	}
+	if op.Op == "remove" {
+		switch strings.ToLower(attr) {
+		case "members":
+			// The members to remove are given either in the path or in the value.
+			remove := map[string]bool{}
+			if value != "" {
+				remove[value] = true
+			} else if len(op.Value) > 0 {
+				var members []groupMember
+				if err := json.Unmarshal(op.Value, &members); err != nil {
+					return badRequest("invalidValue", "Invalid value for the members attribute: %s", err)
+				}
+				for _, m := range members {
+					remove[m.Value] = true
+				}
+			}
+			members := []groupMember{}
+			for _, m := range res.Members {
+				if len(remove) > 0 && !remove[m.Value] {
+					members = append(members, m)
+				}
+			}
+			res.Members = members
+		case "displayname":
+			return badRequest("mutability", "The %s attribute can't be removed.", attr)
+		}
+		return nil
+	}
+	if value != "" {
+		return badRequest("invalidPath", "Unsupported path %q for an %s operation.", op.Path, op.Op)
+	}
+	return setGroupAttr(res, op.Op, attr, op.Value)
+}
+
+// setGroupAttr sets an attribute of res for an add or replace PATCH operation. Unsupported
+// attributes are ignored.
+func setGroupAttr(res *groupResource, op, attr string, value json.RawMessage) error {
+	var err error
+	switch strings.ToLower(attr) {
+	case "displayname":
+		err = json.Unmarshal(value, &res.DisplayName)
+	case "members":
+		var members []groupMember
+		if err = json.Unmarshal(value, &members); err == nil {
+			if op == "add" {
+				members = append(res.Members, members...)
+			}
+			res.Members = append([]groupMember{}, members...)
+		}
+	}
+	if err != nil {
+		return badRequest("invalidValue", "Invalid value for the %s attribute: %s", attr, err)
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+// Package scim implements the SCIM 2.0 API (RFC 7643 and RFC 7644) that identity providers use to
+// provision users and organizations on Sourcegraph.
+//
+// SCIM users map to Sourcegraph users, and SCIM groups map to organizations. Deactivating a user
+// in the identity provider suspends the Sourcegraph user, which immediately revokes their sessions
+// and access tokens.
+package scim
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+// basePath is the URL path prefix of the SCIM API.
+const basePath = "/.api/scim/v2/"
+
+const (
+	errorSchema        = "urn:ietf:params:scim:api:messages:2.0:Error"
+	listResponseSchema = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+)
+
+// Middleware serves the SCIM API for requests under basePath and passes all other requests to next.
+//
+// 🚨 SECURITY: Requests to the SCIM API are authenticated only by the bearer token in the
+// auth.scimBearerToken critical configuration property, which grants full control over all users
+// and organizations. The middleware must run before the auth middlewares, which would otherwise
+// reject the requests.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, basePath) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token := conf.Get().Critical.AuthScimBearerToken
+		if token == "" {
+			writeError(w, &scimError{status: http.StatusNotFound, detail: "The SCIM API is not enabled. A site admin must set auth.scimBearerToken in the critical configuration to enable it."})
+			return
+		}
+		if !isAuthorized(r, token) {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="SCIM"`)
+			writeError(w, &scimError{status: http.StatusUnauthorized, detail: "Invalid or missing bearer token."})
+			return
+		}
+
+		if err := serve(w, r, strings.TrimPrefix(r.URL.Path, basePath)); err != nil {
+			if _, ok := err.(*scimError); !ok {
+				if errcode.IsNotFound(err) {
+					err = &scimError{status: http.StatusNotFound, detail: "Resource not found."}
+				} else {
+					log15.Error("SCIM API request failed.", "method", r.Method, "path", r.URL.Path, "error", err)
+					err = &scimError{status: http.StatusInternalServerError, detail: "Internal error. Site admins may check the logs for more information."}
+				}
+			}
+			writeError(w, err.(*scimError))
+		}
+	})
+}
+
+// isAuthorized reports whether the request has an "Authorization: Bearer" header with the token.
+func isAuthorized(r *http.Request, token string) bool {
+	parts := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return false
+	}
+	// 🚨 SECURITY: Use a constant-time comparison to avoid leaking the token through timing.
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(parts[1])), []byte(token)) == 1
+}
+
+// serve handles a request for the SCIM endpoint at path (relative to basePath).
+func serve(w http.ResponseWriter, r *http.Request, path string) error {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) > 2 {
+		return &scimError{status: http.StatusNotFound, detail: "Unknown endpoint."}
+	}
+	endpoint := parts[0]
+	var id string
+	if len(parts) == 2 {
+		id = parts[1]
+	}
+
+	switch {
+	case endpoint == "ServiceProviderConfig" && id == "" && r.Method == "GET":
+		return writeJSON(w, http.StatusOK, serviceProviderConfig)
+	case endpoint == "ResourceTypes" && id == "" && r.Method == "GET":
+		return writeJSON(w, http.StatusOK, newListResponse(len(resourceTypes), 1, len(resourceTypes), resourceTypes))
+
+	case endpoint == "Users" && id == "" && r.Method == "GET":
+		return serveListUsers(w, r)
+	case endpoint == "Users" && id == "" && r.Method == "POST":
+		return serveCreateUser(w, r)
+	case endpoint == "Users" && id != "" && r.Method == "GET":
+		return serveGetUser(w, r, id)
+	case endpoint == "Users" && id != "" && r.Method == "PUT":
+		return serveReplaceUser(w, r, id)
+	case endpoint == "Users" && id != "" && r.Method == "PATCH":
+		return servePatchUser(w, r, id)
+	case endpoint == "Users" && id != "" && r.Method == "DELETE":
+		return serveDeleteUser(w, r, id)
+
+	case endpoint == "Groups" && id == "" && r.Method == "GET":
+		return serveListGroups(w, r)
+	case endpoint == "Groups" && id == "" && r.Method == "POST":
+		return serveCreateGroup(w, r)
+	case endpoint == "Groups" && id != "" && r.Method == "GET":
+		return serveGetGroup(w, r, id)
+	case endpoint == "Groups" && id != "" && r.Method == "PUT":
+		return serveReplaceGroup(w, r, id)
+	case endpoint == "Groups" && id != "" && r.Method == "PATCH":
+		return servePatchGroup(w, r, id)
+	case endpoint == "Groups" && id != "" && r.Method == "DELETE":
+		return serveDeleteGroup(w, r, id)
+
+	case endpoint == "ServiceProviderConfig" || endpoint == "ResourceTypes" || endpoint == "Users" || endpoint == "Groups":
+		return &scimError{status: http.StatusMethodNotAllowed, detail: fmt.Sprintf("Method %s is not supported for this endpoint.", r.Method)}
+	}
+	return &scimError{status: http.StatusNotFound, detail: "Unknown endpoint."}
+}
+
+var serviceProviderConfig = map[string]interface{}{
+	"schemas":          []string{"urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"},
+	"documentationUri": "https://docs.sourcegraph.com/admin/auth/scim",
+	"patch":            map[string]bool{"supported": true},
+	"bulk":             map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+	"filter":           map[string]interface{}{"supported": true, "maxResults": maxCount},
+	"changePassword":   map[string]bool{"supported": false},
+	"sort":             map[string]bool{"supported": false},
+	"etag":             map[string]bool{"supported": false},
+	"authenticationSchemes": []map[string]interface{}{{
+		"type":        "oauthbearertoken",
+		"name":        "Bearer token",
+		"description": "Authentication with the bearer token in the auth.scimBearerToken critical configuration property",
+		"primary":     true,
+	}},
+}
+
+var resourceTypes = []map[string]interface{}{
+	{
+		"schemas":  []string{"urn:ietf:params:scim:schemas:core:2.0:ResourceType"},
+		"id":       "User",
+		"name":     "User",
+		"endpoint": "/Users",
+		"schema":   userSchema,
+	},
+	{
+		"schemas":  []string{"urn:ietf:params:scim:schemas:core:2.0:ResourceType"},
+		"id":       "Group",
+		"name":     "Group",
+		"endpoint": "/Groups",
+		"schema":   groupSchema,
+	},
+}
+
+// scimError is an error that is returned to the client as a SCIM error response.
+type scimError struct {
+	status   int
+	scimType string // see https://tools.ietf.org/html/rfc7644#section-3.12
+	detail   string
+}
+
+func (e *scimError) Error() string { return e.detail }
+
+func badRequest(scimType, format string, args ...interface{}) error {
+	return &scimError{status: http.StatusBadRequest, scimType: scimType, detail: fmt.Sprintf(format, args...)}
+}
+
+// record records a change made through the SCIM API in the audit log. SCIM requests are not made by
+// a user, so the event's details mark it as made by the identity provider.
+func record(ctx context.Context, action, targetType, targetID string, details map[string]interface{}) {
+	if details == nil {
+		details = map[string]interface{}{}
+	}
+	details["scim"] = true
+	audit.Record(ctx, audit.Event{Action: action, TargetType: targetType, TargetID: targetID, Details: details})
+}
+
+func writeError(w http.ResponseWriter, e *scimError) {
+	v := map[string]interface{}{
+		"schemas": []string{errorSchema},
+		"status":  strconv.Itoa(e.status),
+		"detail":  e.detail,
+	}
+	if e.scimType != "" {
+		v["scimType"] = e.scimType
+	}
+	_ = writeJSON(w, e.status, v)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/scim+json")
+	w.WriteHeader(status)
+	_, err = w.Write(data)
+	return err
+}
+
+func readJSON(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return badRequest("invalidSyntax", "Invalid request body: %s", err)
+	}
+	return nil
+}
+
+// meta is the "meta" attribute of a resource.
+type meta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created,omitempty"`
+	LastModified string `json:"lastModified,omitempty"`
+	Location     string `json:"location"`
+}
+
+func newMeta(resourceType, endpoint, id string) *meta {
+	return &meta{
+		ResourceType: resourceType,
+		Location:     strings.TrimSuffix(conf.Get().Critical.ExternalURL, "/") + basePath + endpoint + "/" + id,
+	}
+}
+
+const (
+	defaultCount = 100
+	maxCount     = 200
+)
+
+// pagination returns the 1-based start index and the count of resources requested by the
+// startIndex and count query parameters.
+func pagination(r *http.Request) (startIndex, count int) {
+	startIndex, _ = strconv.Atoi(r.URL.Query().Get("startIndex"))
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	count, err := strconv.Atoi(r.URL.Query().Get("count"))
+	if err != nil || count < 0 {
+		count = defaultCount
+	}
+	if count > maxCount {
+		count = maxCount
+	}
+	return startIndex, count
+}
+
+type listResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+func newListResponse(totalResults, startIndex, itemsPerPage int, resources interface{}) *listResponse {
+	return &listResponse{
+		Schemas:      []string{listResponseSchema},
+		TotalResults: totalResults,
+		StartIndex:   startIndex,
+		ItemsPerPage: itemsPerPage,
+		Resources:    resources,
+	}
+}
+
+// patchRequest is the body of a PATCH request.
+type patchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []patchOperation `json:"Operations"`
+}
+
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+func readPatchRequest(r *http.Request) (*patchRequest, error) {
+	var req patchRequest
+	if err := readJSON(r, &req); err != nil {
+		return nil, err
+	}
+	if len(req.Operations) == 0 {
+		return nil, badRequest("invalidValue", "The PATCH request has no operations.")
+	}
+	for i, op := range req.Operations {
+		req.Operations[i].Op = strings.ToLower(op.Op)
+		switch req.Operations[i].Op {
+		case "add", "replace", "remove":
+		default:
+			return nil, badRequest("invalidSyntax", "Invalid PATCH operation %q.", op.Op)
+		}
+	}
+	return &req, nil
+}
+
+// parseID parses the ID of a user or organization.
+func parseID(id string) (int32, error) {
+	n, err := strconv.ParseInt(id, 10, 32)
+	if err != nil {
+		return 0, &scimError{status: http.StatusNotFound, detail: fmt.Sprintf("Resource %q not found.", id)}
+	}
+	return int32(n), nil
+}
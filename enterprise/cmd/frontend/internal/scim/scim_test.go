@@ -0,0 +1,200 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const testToken = "0123456789abcdef0123456789abcdef"
+
+func TestMiddleware(t *testing.T) {
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "next")
+	}))
+
+	conf.Mock(&conf.Unified{Critical: schema.CriticalConfiguration{AuthScimBearerToken: testToken}})
+	defer conf.Mock(nil)
+
+	tests := map[string]struct {
+		path          string
+		authorization string
+		wantStatus    int
+		wantBody      string
+	}{
+		"other path":     {path: "/.api/graphql", wantStatus: http.StatusOK, wantBody: "next"},
+		"no token":       {path: basePath + "Users", wantStatus: http.StatusUnauthorized},
+		"wrong token":    {path: basePath + "Users", authorization: "Bearer x" + testToken, wantStatus: http.StatusUnauthorized},
+		"wrong scheme":   {path: basePath + "Users", authorization: "token " + testToken, wantStatus: http.StatusUnauthorized},
+		"unknown":        {path: basePath + "Foo", authorization: "Bearer " + testToken, wantStatus: http.StatusNotFound},
+		"service config": {path: basePath + "ServiceProviderConfig", authorization: "Bearer " + testToken, wantStatus: http.StatusOK},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req, _ := http.NewRequest("GET", test.path, nil)
+			if test.authorization != "" {
+				req.Header.Set("Authorization", test.authorization)
+			}
+			handler.ServeHTTP(rr, req)
+			if rr.Code != test.wantStatus {
+				t.Errorf("got status %d, want %d", rr.Code, test.wantStatus)
+			}
+			if test.wantBody != "" && rr.Body.String() != test.wantBody {
+				t.Errorf("got body %q, want %q", rr.Body.String(), test.wantBody)
+			}
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		conf.Mock(&conf.Unified{})
+		rr := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", basePath+"Users", nil)
+		req.Header.Set("Authorization", "Bearer ")
+		handler.ServeHTTP(rr, req)
+		if rr.Code != http.StatusNotFound {
+			t.Errorf("got status %d, want %d", rr.Code, http.StatusNotFound)
+		}
+	})
+}
+
+func TestParseFilter(t *testing.T) {
+	tests := map[string]struct {
+		attr, value string
+		wantErr     bool
+	}{
+		`userName eq "alice"`:         {attr: "userName", value: "alice"},
+		` displayName EQ "a \"b\"" `:  {attr: "displayName", value: `a "b"`},
+		`userName eq "a" and id eq 1`: {wantErr: true},
+		`userName co "a"`:             {wantErr: true},
+		`userName eq alice`:           {wantErr: true},
+	}
+	for filter, test := range tests {
+		attr, value, err := parseFilter(filter)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: got error %v, want error %v", filter, err, test.wantErr)
+			continue
+		}
+		if attr != test.attr || value != test.value {
+			t.Errorf("%q: got (%q, %q), want (%q, %q)", filter, attr, value, test.attr, test.value)
+		}
+	}
+}
+
+func TestPatchUser(t *testing.T) {
+	active := flexBool(true)
+	res := &userResource{
+		UserName:    "alice",
+		DisplayName: "Alice",
+		Active:      &active,
+		Emails:      []email{{Value: "alice@example.com", Primary: true}, {Value: "a@example.com"}},
+	}
+	ops := []patchOperation{
+		// Some identity providers send booleans as strings.
+		{Op: "replace", Path: "active", Value: json.RawMessage(`"False"`)},
+		{Op: "replace", Value: json.RawMessage(`{"displayName": "Alice A", "title": "ignored"}`)},
+		{Op: "remove", Path: `emails[value eq "a@example.com"]`},
+		{Op: "add", Path: "emails", Value: json.RawMessage(`[{"value": "b@example.com"}]`)},
+	}
+	for _, op := range ops {
+		if err := patchUser(res, op); err != nil {
+			t.Fatal(err)
+		}
+	}
+	inactive := flexBool(false)
+	want := &userResource{
+		UserName:    "alice",
+		DisplayName: "Alice A",
+		Active:      &inactive,
+		Emails:      []email{{Value: "alice@example.com", Primary: true}, {Value: "b@example.com"}},
+	}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+
+	if err := patchUser(res, patchOperation{Op: "remove", Path: "userName"}); err == nil {
+		t.Error("got no error when removing userName")
+	}
+}
+
+func TestPatchGroup(t *testing.T) {
+	res := &groupResource{DisplayName: "Engineering", Members: []groupMember{{Value: "1"}, {Value: "2"}}}
+	ops := []patchOperation{
+		{Op: "add", Path: "members", Value: json.RawMessage(`[{"value": "3"}]`)},
+		{Op: "remove", Path: `members[value eq "1"]`},
+		{Op: "remove", Path: "members", Value: json.RawMessage(`[{"value": "2"}]`)},
+		{Op: "replace", Path: "displayName", Value: json.RawMessage(`"Eng"`)},
+	}
+	for _, op := range ops {
+		if err := patchGroup(res, op); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := &groupResource{DisplayName: "Eng", Members: []groupMember{{Value: "3"}}}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("got %+v, want %+v", res, want)
+	}
+}
+
+func TestPatchUserRequest(t *testing.T) {
+	conf.Mock(&conf.Unified{Critical: schema.CriticalConfiguration{AuthScimBearerToken: testToken, ExternalURL: "https://example.com"}})
+	defer conf.Mock(nil)
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	user := &types.User{ID: 1, Username: "alice", DisplayName: "Alice", CreatedAt: time.Now(), UpdatedAt: time.Now()}
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		if id != user.ID {
+			t.Fatalf("got user ID %d, want %d", id, user.ID)
+		}
+		return user, nil
+	}
+	db.Mocks.Users.Update = func(userID int32, update db.UserUpdate) error {
+		t.Fatalf("unexpected update %+v", update)
+		return nil
+	}
+	db.Mocks.Users.SetSuspended = func(id int32, suspended bool) error {
+		user.Suspended = suspended
+		return nil
+	}
+	now := time.Now()
+	db.Mocks.UserEmails.ListByUser = func(id int32) ([]*db.UserEmail, error) {
+		return []*db.UserEmail{{UserID: 1, Email: "alice@example.com", VerifiedAt: &now}}, nil
+	}
+
+	body := `{"schemas": ["urn:ietf:params:scim:api:messages:2.0:PatchOp"], "Operations": [{"op": "Replace", "path": "active", "value": false}]}`
+	req, _ := http.NewRequest("PATCH", basePath+"Users/1", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	rr := httptest.NewRecorder()
+	Middleware(http.NotFoundHandler()).ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d (body %s)", rr.Code, http.StatusOK, rr.Body)
+	}
+	if !user.Suspended {
+		t.Error("user was not suspended")
+	}
+
+	var res userResource
+	if err := json.Unmarshal(rr.Body.Bytes(), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.ID != "1" || res.UserName != "alice" || res.Active == nil || *res.Active {
+		t.Errorf("got user %+v, want inactive user alice with ID 1", res)
+	}
+	if want := []email{{Value: "alice@example.com", Type: "work", Primary: true}}; !reflect.DeepEqual(res.Emails, want) {
+		t.Errorf("got emails %+v, want %+v", res.Emails, want)
+	}
+	if want := "https://example.com/.api/scim/v2/Users/1"; res.Meta == nil || res.Meta.Location != want {
+		t.Errorf("got meta %+v, want location %q", res.Meta, want)
+	}
+}
@@ -0,0 +1,485 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/audit"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/auth"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/pkg/errcode"
+)
+
+const userSchema = "urn:ietf:params:scim:schemas:core:2.0:User"
+
+// userResource is a SCIM user.
+type userResource struct {
+	Schemas     []string  `json:"schemas"`
+	ID          string    `json:"id,omitempty"`
+	UserName    string    `json:"userName"`
+	DisplayName string    `json:"displayName,omitempty"`
+	Name        *userName `json:"name,omitempty"`
+	Active      *flexBool `json:"active,omitempty"`
+	Emails      []email   `json:"emails,omitempty"`
+	Meta        *meta     `json:"meta,omitempty"`
+}
+
+type userName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type email struct {
+	Value   string   `json:"value"`
+	Type    string   `json:"type,omitempty"`
+	Primary flexBool `json:"primary,omitempty"`
+}
+
+// flexBool is a boolean that can also be unmarshaled from the strings "true" and "false" (in any
+// case), which some identity providers send.
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		v, err := strconv.ParseBool(strings.ToLower(s))
+		if err != nil {
+			return err
+		}
+		*b = flexBool(v)
+		return nil
+	}
+	var v bool
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	*b = flexBool(v)
+	return nil
+}
+
+// displayName returns the display name of the user, falling back to their full name.
+func (u *userResource) displayName() string {
+	if u.DisplayName != "" || u.Name == nil {
+		return u.DisplayName
+	}
+	if u.Name.Formatted != "" {
+		return u.Name.Formatted
+	}
+	return strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+}
+
+// emailAddresses returns the user's email addresses, with the primary email address first.
+func (u *userResource) emailAddresses() []string {
+	var addrs []string
+	seen := map[string]bool{}
+	add := func(e email) {
+		if e.Value == "" || seen[strings.ToLower(e.Value)] {
+			return
+		}
+		seen[strings.ToLower(e.Value)] = true
+		addrs = append(addrs, e.Value)
+	}
+	for _, e := range u.Emails {
+		if e.Primary {
+			add(e)
+		}
+	}
+	for _, e := range u.Emails {
+		add(e)
+	}
+	return addrs
+}
+
+func toUserResource(ctx context.Context, user *types.User) (*userResource, error) {
+	emails, err := db.UserEmails.ListByUser(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	active := flexBool(!user.Suspended)
+	res := &userResource{
+		Schemas:     []string{userSchema},
+		ID:          strconv.Itoa(int(user.ID)),
+		UserName:    user.Username,
+		DisplayName: user.DisplayName,
+		Active:      &active,
+		Emails:      []email{},
+		Meta:        newMeta("User", "Users", strconv.Itoa(int(user.ID))),
+	}
+	res.Meta.Created = user.CreatedAt.Format(time.RFC3339)
+	res.Meta.LastModified = user.UpdatedAt.Format(time.RFC3339)
+	hasPrimary := false
+	for _, e := range emails {
+		// The primary email is the oldest verified email (see db.UserEmails.GetPrimaryEmail).
+		primary := !hasPrimary && e.VerifiedAt != nil
+		hasPrimary = hasPrimary || primary
+		res.Emails = append(res.Emails, email{Value: e.Email, Type: "work", Primary: flexBool(primary)})
+	}
+	return res, nil
+}
+
+func getUser(ctx context.Context, id string) (*types.User, error) {
+	userID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	return db.Users.GetByID(ctx, userID)
+}
+
+func writeUser(ctx context.Context, w http.ResponseWriter, status int, userID int32) error {
+	user, err := db.Users.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	res, err := toUserResource(ctx, user)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, status, res)
+}
+
+func serveListUsers(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	startIndex, count := pagination(r)
+
+	var (
+		users []*types.User
+		total int
+	)
+	if filter := r.URL.Query().Get("filter"); filter != "" {
+		attr, value, err := parseFilter(filter)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(attr, "userName") {
+			return badRequest("invalidFilter", "Unsupported filter attribute %q. Only userName is supported.", attr)
+		}
+		// Usernames are normalized when users are created, so normalize the value in the same way.
+		if username, err := auth.NormalizeUsername(value); err == nil {
+			user, err := db.Users.GetByUsername(ctx, username)
+			if err != nil && !errcode.IsNotFound(err) {
+				return err
+			}
+			if user != nil {
+				total = 1
+				if startIndex == 1 && count > 0 {
+					users = append(users, user)
+				}
+			}
+		}
+	} else {
+		var err error
+		if total, err = db.Users.Count(ctx, nil); err != nil {
+			return err
+		}
+		if count > 0 {
+			users, err = db.Users.List(ctx, &db.UsersListOptions{LimitOffset: &db.LimitOffset{Limit: count, Offset: startIndex - 1}})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	resources := make([]*userResource, len(users))
+	for i, user := range users {
+		var err error
+		if resources[i], err = toUserResource(ctx, user); err != nil {
+			return err
+		}
+	}
+	return writeJSON(w, http.StatusOK, newListResponse(total, startIndex, len(resources), resources))
+}
+
+func serveCreateUser(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	var res userResource
+	if err := readJSON(r, &res); err != nil {
+		return err
+	}
+	username, err := auth.NormalizeUsername(res.UserName)
+	if err != nil {
+		return badRequest("invalidValue", "Invalid userName: %s", err)
+	}
+
+	// 🚨 SECURITY: The email addresses are trusted to be verified, because they come from the
+	// identity provider (which is authenticated by the site admin-configured bearer token).
+	newUser := db.NewUser{Username: username, DisplayName: res.displayName()}
+	emails := res.emailAddresses()
+	if len(emails) > 0 {
+		newUser.Email = emails[0]
+		newUser.EmailIsVerified = true
+	}
+	user, err := db.Users.Create(ctx, newUser)
+	switch {
+	case db.IsUsernameExists(err):
+		return &scimError{status: http.StatusConflict, scimType: "uniqueness", detail: "A user with the userName already exists."}
+	case db.IsEmailExists(err):
+		return &scimError{status: http.StatusConflict, scimType: "uniqueness", detail: "A user with the email address already exists."}
+	case errcode.PresentationMessage(err) != "":
+		return &scimError{status: http.StatusForbidden, detail: errcode.PresentationMessage(err)}
+	case err != nil:
+		return err
+	}
+
+	record(ctx, audit.UserCreate, "User", audit.UserID(user.ID), map[string]interface{}{"username": user.Username})
+
+	if len(emails) > 1 {
+		if err := setEmails(ctx, user.ID, emails); err != nil {
+			return err
+		}
+	}
+	if res.Active != nil && !*res.Active {
+		if err := setSuspended(ctx, user.ID, true); err != nil {
+			return err
+		}
+	}
+	return writeUser(ctx, w, http.StatusCreated, user.ID)
+}
+
+func serveGetUser(w http.ResponseWriter, r *http.Request, id string) error {
+	user, err := getUser(r.Context(), id)
+	if err != nil {
+		return err
+	}
+	return writeUser(r.Context(), w, http.StatusOK, user.ID)
+}
+
+func serveReplaceUser(w http.ResponseWriter, r *http.Request, id string) error {
+	ctx := r.Context()
+	user, err := getUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	var res userResource
+	if err := readJSON(r, &res); err != nil {
+		return err
+	}
+	if err := updateUser(ctx, user, &res); err != nil {
+		return err
+	}
+	return writeUser(ctx, w, http.StatusOK, user.ID)
+}
+
+func servePatchUser(w http.ResponseWriter, r *http.Request, id string) error {
+	ctx := r.Context()
+	user, err := getUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	req, err := readPatchRequest(r)
+	if err != nil {
+		return err
+	}
+
+	// Apply the operations to the current resource and then save it as if it had been replaced.
+	res, err := toUserResource(ctx, user)
+	if err != nil {
+		return err
+	}
+	for _, op := range req.Operations {
+		if err := patchUser(res, op); err != nil {
+			return err
+		}
+	}
+	if err := updateUser(ctx, user, res); err != nil {
+		return err
+	}
+	return writeUser(ctx, w, http.StatusOK, user.ID)
+}
+
+func serveDeleteUser(w http.ResponseWriter, r *http.Request, id string) error {
+	userID, err := parseID(id)
+	if err != nil {
+		return err
+	}
+	if err := db.Users.Delete(r.Context(), userID); err != nil {
+		return err
+	}
+	record(r.Context(), audit.UserDelete, "User", audit.UserID(userID), nil)
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// updateUser updates the user to match res. The emails and active attributes are left unchanged if
+// they are absent.
+func updateUser(ctx context.Context, user *types.User, res *userResource) error {
+	username, err := auth.NormalizeUsername(res.UserName)
+	if err != nil {
+		return badRequest("invalidValue", "Invalid userName: %s", err)
+	}
+	var update db.UserUpdate
+	details := map[string]interface{}{}
+	if username != user.Username {
+		update.Username = username
+		details["username"] = username
+	}
+	if displayName := res.displayName(); displayName != user.DisplayName {
+		update.DisplayName = &displayName
+		details["displayName"] = displayName
+	}
+	if update != (db.UserUpdate{}) {
+		if err := db.Users.Update(ctx, user.ID, update); err != nil {
+			if db.IsUsernameExists(err) {
+				return &scimError{status: http.StatusConflict, scimType: "uniqueness", detail: "A user with the userName already exists."}
+			}
+			return err
+		}
+		record(ctx, audit.UserUpdate, "User", audit.UserID(user.ID), details)
+	}
+
+	if res.Emails != nil {
+		if err := setEmails(ctx, user.ID, res.emailAddresses()); err != nil {
+			return err
+		}
+	}
+	if res.Active != nil && bool(*res.Active) == user.Suspended {
+		if err := setSuspended(ctx, user.ID, !bool(*res.Active)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setSuspended suspends or unsuspends the user.
+func setSuspended(ctx context.Context, userID int32, suspended bool) error {
+	if err := db.Users.SetSuspended(ctx, userID, suspended); err != nil {
+		return err
+	}
+	action := audit.UserUnsuspend
+	if suspended {
+		action = audit.UserSuspend
+	}
+	record(ctx, action, "User", audit.UserID(userID), nil)
+	return nil
+}
+
+// setEmails makes the user's verified email addresses match emails.
+//
+// 🚨 SECURITY: The email addresses are trusted to be verified, because they come from the identity
+// provider.
+func setEmails(ctx context.Context, userID int32, emails []string) error {
+	have, err := db.UserEmails.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	haveByAddr := make(map[string]*db.UserEmail, len(have))
+	for _, e := range have {
+		haveByAddr[strings.ToLower(e.Email)] = e
+	}
+	want := make(map[string]bool, len(emails))
+	for _, addr := range emails {
+		want[strings.ToLower(addr)] = true
+		e, ok := haveByAddr[strings.ToLower(addr)]
+		if ok && e.VerifiedAt != nil {
+			continue
+		}
+		if !ok {
+			if err := db.UserEmails.Add(ctx, userID, addr, nil); err != nil {
+				return err
+			}
+		} else {
+			addr = e.Email
+		}
+		if err := db.UserEmails.SetVerified(ctx, userID, addr, true); err != nil {
+			return err
+		}
+	}
+	for _, e := range have {
+		if !want[strings.ToLower(e.Email)] {
+			if err := db.UserEmails.Remove(ctx, userID, e.Email); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// patchUser applies a PATCH operation to res.
+func patchUser(res *userResource, op patchOperation) error {
+	if op.Path == "" {
+		if op.Op == "remove" {
+			return badRequest("noTarget", "A remove operation requires a path.")
+		}
+		var attrs map[string]json.RawMessage
+		if err := json.Unmarshal(op.Value, &attrs); err != nil {
+			return badRequest("invalidValue", "The value of an operation without a path must be an object.")
+		}
+		for attr, value := range attrs {
+			if err := setUserAttr(res, op.Op, attr, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	attr, value, err := parseValuePath(op.Path)
+	if err != nil {
+		return err
+	}
+	if op.Op == "remove" {
+		switch strings.ToLower(attr) {
+		case "displayname":
+			res.DisplayName = ""
+		case "name":
+			res.Name = nil
+		case "emails":
+			emails := []email{}
+			for _, e := range res.Emails {
+				if value != "" && !strings.EqualFold(e.Value, value) {
+					emails = append(emails, e)
+				}
+			}
+			res.Emails = emails
+		case "username", "active":
+			return badRequest("mutability", "The %s attribute can't be removed.", attr)
+		}
+		return nil
+	}
+	if value != "" {
+		return badRequest("invalidPath", "Unsupported path %q for an %s operation.", op.Path, op.Op)
+	}
+	return setUserAttr(res, op.Op, attr, op.Value)
+}
+
+// setUserAttr sets an attribute of res for an add or replace PATCH operation. Unsupported attributes
+// are ignored.
+func setUserAttr(res *userResource, op, attr string, value json.RawMessage) error {
+	var err error
+	switch strings.ToLower(attr) {
+	case "username":
+		err = json.Unmarshal(value, &res.UserName)
+	case "displayname":
+		err = json.Unmarshal(value, &res.DisplayName)
+	case "active":
+		err = json.Unmarshal(value, &res.Active)
+	case "name":
+		err = json.Unmarshal(value, &res.Name)
+	case "name.formatted", "name.givenname", "name.familyname":
+		if res.Name == nil {
+			res.Name = &userName{}
+		}
+		field := map[string]*string{
+			"name.formatted":  &res.Name.Formatted,
+			"name.givenname":  &res.Name.GivenName,
+			"name.familyname": &res.Name.FamilyName,
+		}[strings.ToLower(attr)]
+		err = json.Unmarshal(value, field)
+	case "emails":
+		var emails []email
+		if err = json.Unmarshal(value, &emails); err == nil {
+			if op == "add" {
+				emails = append(res.Emails, emails...)
+			}
+			res.Emails = append([]email{}, emails...)
+		}
+	}
+	if err != nil {
+		return badRequest("invalidValue", "Invalid value for the %s attribute: %s", attr, err)
+	}
+	return nil
+}
@@ -23,10 +23,12 @@ import (
 	_ "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/auth"
 	edb "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/db"
 	iauthz "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/authz/permsync"
 	_ "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/graphqlbackend"
 	_ "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/httpapi"
 	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/licensing"
-	_ "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/registry"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/registry"
+	"github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/internal/scim"
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"gopkg.in/inconshreveable/log15.v2"
 )
@@ -34,6 +36,7 @@ import (
 func main() {
 	initLicensing()
 	initAuthz()
+	initSCIM()
 
 	hooks.AfterDBInit = func() {
 		ctx := context.Background()
@@ -46,6 +49,12 @@ func main() {
 			}
 		}()
 		go licensing.StartMaxUserCount(&usersStore{})
+
+		permsSyncer := permsync.NewSyncer()
+		authz.SetPermissionsSyncer(permsSyncer)
+		go permsSyncer.Run(ctx)
+
+		go registry.RunMirror(ctx)
 	}
 
 	debug, _ := strconv.ParseBool(os.Getenv("DEBUG"))
@@ -136,6 +145,20 @@ func initAuthz() {
 			}
 		}
 
+		bitbucketServers, err := db.ExternalServices.ListBitbucketServerConnections(ctx)
+		if err != nil {
+			return []*graphqlbackend.Alert{{
+				TypeValue:    graphqlbackend.AlertTypeError,
+				MessageValue: fmt.Sprintf("Unable to fetch Bitbucket Server external services: %s", err),
+			}}
+		}
+		for _, b := range bitbucketServers {
+			if b.Authorization != nil {
+				authzTypes = append(authzTypes, "Bitbucket Server")
+				break
+			}
+		}
+
 		if len(authzTypes) > 0 {
 			return []*graphqlbackend.Alert{{
 				TypeValue:    graphqlbackend.AlertTypeError,
@@ -165,6 +188,15 @@ func initAuthz() {
 	}
 }
 
+func initSCIM() {
+	// Serve the SCIM user and group provisioning API. It authenticates requests with its own bearer
+	// token, so it must run before the auth middlewares (but after license enforcement).
+	licenseMiddleware := hooks.PreAuthMiddleware
+	hooks.PreAuthMiddleware = func(next http.Handler) http.Handler {
+		return licenseMiddleware(scim.Middleware(next))
+	}
+}
+
 type usersStore struct{}
 
 func (usersStore) Count(ctx context.Context) (int, error) {
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS trig_record_repo_redirect ON repo;
+DROP FUNCTION IF EXISTS record_repo_redirect();
+DROP TABLE IF EXISTS repo_redirects;
+
+COMMIT;
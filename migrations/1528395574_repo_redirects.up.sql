@@ -0,0 +1,28 @@
+BEGIN;
+
+CREATE TABLE repo_redirects (
+	name citext PRIMARY KEY,
+	repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+	created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX repo_redirects_repo_id ON repo_redirects(repo_id);
+
+-- Record a redirect from the previous name of a repo whenever it is renamed, so
+-- that URLs and API requests using the old name keep working. A redirect is
+-- dropped once its name is taken again.
+CREATE FUNCTION record_repo_redirect() RETURNS trigger AS $$
+BEGIN
+	IF OLD.name <> NEW.name THEN
+		INSERT INTO repo_redirects (name, repo_id) VALUES (OLD.name, NEW.id)
+			ON CONFLICT (name) DO UPDATE SET repo_id = EXCLUDED.repo_id, created_at = now();
+		DELETE FROM repo_redirects WHERE name = NEW.name;
+	END IF;
+	RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trig_record_repo_redirect AFTER UPDATE OF name ON repo FOR EACH ROW
+	EXECUTE PROCEDURE record_repo_redirect();
+
+COMMIT;
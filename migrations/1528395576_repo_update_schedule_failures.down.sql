@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE repo_update_schedule
+	DROP COLUMN failures,
+	DROP COLUMN last_error_kind;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE repo_update_schedule
+	ADD COLUMN failures integer NOT NULL DEFAULT 0,
+	ADD COLUMN last_error_kind text;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE access_tokens DROP COLUMN expires_at;
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE critical_and_site_config ADD COLUMN author_user_id integer REFERENCES users(id) ON DELETE SET NULL;
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+CREATE TABLE user_permissions (
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    permission text NOT NULL,
+    provider_service_type text NOT NULL,
+    provider_service_id text NOT NULL,
+    repo_ids integer[] NOT NULL,
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, permission, provider_service_type, provider_service_id)
+);
+CREATE INDEX user_permissions_updated_at ON user_permissions(updated_at);
+
+COMMIT;
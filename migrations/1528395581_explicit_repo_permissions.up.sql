@@ -0,0 +1,16 @@
+BEGIN;
+
+CREATE TABLE explicit_repo_permissions (
+    id serial PRIMARY KEY,
+    repo_id integer REFERENCES repo(id) ON DELETE CASCADE,
+    repo_pattern text,
+    user_ids integer[] NOT NULL,
+    org_ids integer[] NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT explicit_repo_permissions_repo_or_pattern CHECK ((repo_id IS NULL) <> (repo_pattern IS NULL))
+);
+CREATE UNIQUE INDEX explicit_repo_permissions_repo_id ON explicit_repo_permissions(repo_id);
+CREATE UNIQUE INDEX explicit_repo_permissions_repo_pattern ON explicit_repo_permissions(repo_pattern);
+
+COMMIT;
@@ -0,0 +1,31 @@
+BEGIN;
+
+CREATE TABLE audit_log (
+	id bigserial PRIMARY KEY,
+	created_at timestamp with time zone NOT NULL DEFAULT now(),
+	actor_user_id integer,
+	ip text,
+	forwarded_for text,
+	action text NOT NULL,
+	target_type text,
+	target_id text,
+	details jsonb NOT NULL DEFAULT '{}'
+);
+
+CREATE INDEX audit_log_created_at ON audit_log(created_at);
+CREATE INDEX audit_log_actor_user_id ON audit_log(actor_user_id);
+CREATE INDEX audit_log_action ON audit_log(action);
+CREATE INDEX audit_log_target ON audit_log(target_type, target_id);
+
+-- The audit log is append-only: reject any attempt to change or remove an
+-- entry. (TRUNCATE is not affected, so that tests can reset the table.)
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trig_audit_log_append_only BEFORE UPDATE OR DELETE ON audit_log FOR EACH ROW
+	EXECUTE PROCEDURE audit_log_append_only();
+
+COMMIT;
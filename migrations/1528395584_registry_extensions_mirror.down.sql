@@ -0,0 +1,18 @@
+BEGIN;
+
+DELETE FROM registry_extension_releases WHERE creator_user_id IS NULL;
+DELETE FROM registry_extensions WHERE mirrored_extension_id IS NOT NULL;
+
+ALTER TABLE registry_extension_releases ALTER COLUMN creator_user_id SET NOT NULL;
+
+DROP INDEX IF EXISTS registry_extensions_mirrored_extension_id;
+DROP INDEX registry_extensions_publisher_name;
+CREATE UNIQUE INDEX registry_extensions_publisher_name ON registry_extensions((COALESCE(publisher_user_id, 0)), (COALESCE(publisher_org_id, 0)), name) WHERE deleted_at IS NULL;
+
+ALTER TABLE registry_extensions DROP CONSTRAINT registry_extensions_single_publisher;
+ALTER TABLE registry_extensions ADD CONSTRAINT registry_extensions_single_publisher CHECK ((publisher_user_id IS NULL) <> (publisher_org_id IS NULL));
+
+ALTER TABLE registry_extensions DROP COLUMN IF EXISTS mirror_source;
+ALTER TABLE registry_extensions DROP COLUMN IF EXISTS mirrored_extension_id;
+
+COMMIT;
@@ -0,0 +1,21 @@
+BEGIN;
+
+-- Extensions that are mirrored from a remote registry have no local publisher. They keep the
+-- extension ID that they have on the remote registry.
+ALTER TABLE registry_extensions ADD COLUMN mirrored_extension_id citext;
+ALTER TABLE registry_extensions ADD COLUMN mirror_source text;
+
+ALTER TABLE registry_extensions DROP CONSTRAINT registry_extensions_single_publisher;
+ALTER TABLE registry_extensions ADD CONSTRAINT registry_extensions_single_publisher CHECK (
+  CASE WHEN mirrored_extension_id IS NULL THEN (publisher_user_id IS NULL) <> (publisher_org_id IS NULL)
+  ELSE publisher_user_id IS NULL AND publisher_org_id IS NULL END
+);
+
+DROP INDEX registry_extensions_publisher_name;
+CREATE UNIQUE INDEX registry_extensions_publisher_name ON registry_extensions((COALESCE(publisher_user_id, 0)), (COALESCE(publisher_org_id, 0)), name) WHERE deleted_at IS NULL AND mirrored_extension_id IS NULL;
+CREATE UNIQUE INDEX registry_extensions_mirrored_extension_id ON registry_extensions(mirrored_extension_id) WHERE deleted_at IS NULL AND mirrored_extension_id IS NOT NULL;
+
+-- Releases of mirrored extensions have no local creator.
+ALTER TABLE registry_extension_releases ALTER COLUMN creator_user_id DROP NOT NULL;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP TABLE IF EXISTS discussion_comment_reactions;
+DROP TABLE IF EXISTS discussion_thread_subscriptions;
+
+ALTER TABLE discussion_threads DROP COLUMN IF EXISTS resolved_by_user_id;
+ALTER TABLE discussion_threads DROP COLUMN IF EXISTS resolved_at;
+
+COMMIT;
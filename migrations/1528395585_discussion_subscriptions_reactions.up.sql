@@ -0,0 +1,26 @@
+BEGIN;
+
+ALTER TABLE discussion_threads ADD COLUMN resolved_at timestamp with time zone;
+ALTER TABLE discussion_threads ADD COLUMN resolved_by_user_id integer REFERENCES users (id) ON DELETE RESTRICT;
+
+-- A user's explicit subscription to a thread. Users who participate in a thread are implicitly
+-- subscribed to it unless they have unsubscribed (subscribed=false).
+CREATE TABLE discussion_thread_subscriptions (
+    thread_id bigint NOT NULL REFERENCES discussion_threads (id) ON DELETE RESTRICT,
+    user_id integer NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+    subscribed boolean NOT NULL,
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (thread_id, user_id)
+);
+CREATE INDEX discussion_thread_subscriptions_user_id ON discussion_thread_subscriptions(user_id);
+
+CREATE TABLE discussion_comment_reactions (
+    comment_id bigint NOT NULL REFERENCES discussion_comments (id) ON DELETE RESTRICT,
+    user_id integer NOT NULL REFERENCES users (id) ON DELETE RESTRICT,
+    reaction text NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (comment_id, user_id, reaction)
+);
+CREATE INDEX discussion_comment_reactions_user_id ON discussion_comment_reactions(user_id);
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+-- Discussion comments that were imported from a code host (e.g. GitHub pull request review comments).
+-- The external IDs are namespaced by the code host's service ID, so that importing the same comment
+-- again is a no-op.
+CREATE TABLE discussion_imported_comments (
+    comment_id bigint PRIMARY KEY REFERENCES discussion_comments (id) ON DELETE RESTRICT,
+    thread_id bigint NOT NULL REFERENCES discussion_threads (id) ON DELETE RESTRICT,
+    external_thread_id text NOT NULL,
+    external_id text NOT NULL UNIQUE,
+    url text NOT NULL,
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX discussion_imported_comments_thread_id ON discussion_imported_comments(thread_id);
+CREATE INDEX discussion_imported_comments_external_thread_id ON discussion_imported_comments(external_thread_id);
+
+COMMIT;
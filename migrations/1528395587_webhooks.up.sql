@@ -0,0 +1,36 @@
+BEGIN;
+
+-- Outbound webhooks. A webhook with a NULL user_id was registered by a site admin and receives all
+-- events of its event types; a user's webhook only receives the events that concern the user.
+CREATE TABLE webhooks (
+    id serial PRIMARY KEY,
+    user_id integer REFERENCES users(id) ON DELETE CASCADE,
+    url text NOT NULL,
+    secret text NOT NULL,
+    event_types text[] NOT NULL,
+    active boolean NOT NULL DEFAULT true,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+CREATE INDEX webhooks_user_id ON webhooks(user_id);
+
+-- The queue (and history) of deliveries of events to outbound webhooks.
+CREATE TABLE webhook_deliveries (
+    id bigserial PRIMARY KEY,
+    webhook_id integer NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_type text NOT NULL,
+    payload jsonb NOT NULL,
+    state text NOT NULL DEFAULT 'pending',
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamp with time zone NOT NULL DEFAULT now(),
+    last_attempt_at timestamp with time zone,
+    response_status_code integer,
+    response_body text,
+    error text,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT webhook_deliveries_state_check CHECK (state IN ('pending', 'succeeded', 'failed'))
+);
+CREATE INDEX webhook_deliveries_state_next_attempt_at ON webhook_deliveries(state, next_attempt_at);
+CREATE INDEX webhook_deliveries_webhook_id ON webhook_deliveries(webhook_id);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE users DROP COLUMN saved_search_email_frequency;
+
+COMMIT;
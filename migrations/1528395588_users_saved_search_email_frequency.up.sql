@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE users ADD COLUMN saved_search_email_frequency text NOT NULL DEFAULT 'immediate';
+ALTER TABLE users ADD CONSTRAINT users_saved_search_email_frequency_check CHECK (saved_search_email_frequency IN ('immediate', 'hourly', 'daily'));
+
+COMMIT;
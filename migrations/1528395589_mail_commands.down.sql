@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS discussion_mail_new_thread_tokens;
+DROP TABLE IF EXISTS saved_search_email_mutes;
+DROP TABLE IF EXISTS saved_search_mail_reply_tokens;
+
+COMMIT;
@@ -0,0 +1,30 @@
+BEGIN;
+
+CREATE TABLE saved_search_mail_reply_tokens (
+    token text PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    subject text NOT NULL,
+    saved_query_key text NOT NULL,
+    deleted_at timestamp with time zone
+);
+CREATE INDEX saved_search_mail_reply_tokens_user_id_subject_key ON saved_search_mail_reply_tokens(user_id, subject, saved_query_key);
+
+CREATE TABLE saved_search_email_mutes (
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    subject text NOT NULL,
+    saved_query_key text NOT NULL,
+    muted_until timestamp with time zone,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, subject, saved_query_key)
+);
+CREATE INDEX saved_search_email_mutes_subject_key ON saved_search_email_mutes(subject, saved_query_key);
+
+CREATE TABLE discussion_mail_new_thread_tokens (
+    token text PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    deleted_at timestamp with time zone
+);
+CREATE INDEX discussion_mail_new_thread_tokens_user_id_repo_id ON discussion_mail_new_thread_tokens(user_id, repo_id);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE user_emails DROP COLUMN bounce_reason;
+ALTER TABLE user_emails DROP COLUMN bounced_at;
+DROP TABLE IF EXISTS email_outbox;
+
+COMMIT;
@@ -0,0 +1,30 @@
+BEGIN;
+
+-- The queue (and history) of outgoing emails. Each row is a rendered email to a single recipient.
+CREATE TABLE email_outbox (
+    id bigserial PRIMARY KEY,
+    from_name text NOT NULL,
+    from_address text NOT NULL,
+    to_address text NOT NULL,
+    reply_to text,
+    message_id text,
+    message_references text[] NOT NULL DEFAULT '{}',
+    subject text NOT NULL,
+    text_body text NOT NULL,
+    html_body text NOT NULL,
+    state text NOT NULL DEFAULT 'pending',
+    attempts integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamp with time zone NOT NULL DEFAULT now(),
+    last_attempt_at timestamp with time zone,
+    error text,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    CONSTRAINT email_outbox_state_check CHECK (state IN ('pending', 'sent', 'failed', 'bounced'))
+);
+CREATE INDEX email_outbox_state_next_attempt_at ON email_outbox(state, next_attempt_at);
+
+-- The last time that an email to the address bounced (i.e., was permanently rejected by the
+-- recipient's mail server), and why.
+ALTER TABLE user_emails ADD COLUMN bounced_at timestamp with time zone;
+ALTER TABLE user_emails ADD COLUMN bounce_reason text;
+
+COMMIT;
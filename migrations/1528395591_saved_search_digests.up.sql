@@ -0,0 +1,13 @@
+BEGIN;
+
+-- The pending saved search digest email of each user who receives digests, which query-runner
+-- saves so that the new results that haven't been sent yet survive restarts. The contents are the
+-- digest's entries (opaque to the frontend).
+CREATE TABLE saved_search_digests (
+    user_id integer PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    due_at timestamp with time zone NOT NULL,
+    contents jsonb NOT NULL,
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+CREATE INDEX user_permissions_updated_at ON user_permissions(updated_at);
+
+DROP TABLE IF EXISTS user_permissions_sync;
+
+COMMIT;
@@ -0,0 +1,12 @@
+BEGIN;
+
+CREATE TABLE user_permissions_sync (
+    user_id integer PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    last_attempt_at timestamp with time zone NOT NULL,
+    failures integer NOT NULL DEFAULT 0
+);
+CREATE INDEX user_permissions_sync_last_attempt_at ON user_permissions_sync(last_attempt_at);
+
+DROP INDEX user_permissions_updated_at;
+
+COMMIT;
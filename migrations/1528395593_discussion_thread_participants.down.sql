@@ -0,0 +1,2 @@
+-- Participants can't be distinguished from users who explicitly subscribed, so the subscriptions
+-- are kept.
@@ -0,0 +1,21 @@
+BEGIN;
+
+-- Users who participate in a thread are subscribed to it. Subscribe the participants of existing
+-- threads: the authors of comments, and the users mentioned in comments and thread titles (with
+-- the same pattern as the mentions package).
+INSERT INTO discussion_thread_subscriptions(thread_id, user_id, subscribed)
+SELECT DISTINCT thread_id, author_user_id, true FROM discussion_comments WHERE deleted_at IS NULL
+ON CONFLICT (thread_id, user_id) DO NOTHING;
+
+INSERT INTO discussion_thread_subscriptions(thread_id, user_id, subscribed)
+SELECT DISTINCT t.thread_id, users.id, true
+FROM (
+    SELECT thread_id, contents AS text FROM discussion_comments WHERE deleted_at IS NULL
+    UNION ALL
+    SELECT id, title FROM discussion_threads WHERE deleted_at IS NULL
+) t
+CROSS JOIN LATERAL regexp_matches(t.text, '(^|\s)@(\S*)', 'g') AS m(groups)
+INNER JOIN users ON users.username=m.groups[2] AND users.deleted_at IS NULL
+ON CONFLICT (thread_id, user_id) DO NOTHING;
+
+COMMIT;
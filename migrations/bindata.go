@@ -210,6 +210,46 @@
 // 1528395572_.up.sql (181B)
 // 1528395573_recent_searches.down.sql (55B)
 // 1528395573_recent_searches.up.sql (142B)
+// 1528395574_repo_redirects.down.sql (160B)
+// 1528395574_repo_redirects.up.sql (945B)
+// 1528395575_repo_update_schedule.down.sql (60B)
+// 1528395575_repo_update_schedule.up.sql (281B)
+// 1528395576_repo_update_schedule_failures.down.sql (103B)
+// 1528395576_repo_update_schedule_failures.up.sql (133B)
+// 1528395577_access_tokens_expires_at.down.sql (67B)
+// 1528395577_access_tokens_expires_at.up.sql (91B)
+// 1528395578_critical_and_site_config_author.down.sql (82B)
+// 1528395578_critical_and_site_config_author.up.sql (129B)
+// 1528395579_users_suspended_at.down.sql (61B)
+// 1528395579_users_suspended_at.up.sql (85B)
+// 1528395580_user_permissions.down.sql (56B)
+// 1528395580_user_permissions.up.sql (484B)
+// 1528395581_explicit_repo_permissions.down.sql (65B)
+// 1528395581_explicit_repo_permissions.up.sql (668B)
+// 1528395582_audit_log.down.sql (162B)
+// 1528395582_audit_log.up.sql (956B)
+// 1528395583_registry_extension_releases_yanked.down.sql (90B)
+// 1528395583_registry_extension_releases_yanked.up.sql (104B)
+// 1528395584_registry_extensions_mirror.down.sql (919B)
+// 1528395584_registry_extensions_mirror.up.sql (1.229kB)
+// 1528395585_discussion_subscriptions_reactions.down.sql (263B)
+// 1528395585_discussion_subscriptions_reactions.up.sql (1.252kB)
+// 1528395586_discussion_imported_comments.down.sql (68B)
+// 1528395586_discussion_imported_comments.up.sql (836B)
+// 1528395587_webhooks.down.sql (89B)
+// 1528395587_webhooks.up.sql (1.517kB)
+// 1528395588_users_saved_search_email_frequency.down.sql (77B)
+// 1528395588_users_saved_search_email_frequency.up.sql (258B)
+// 1528395589_mail_commands.down.sql (173B)
+// 1528395589_mail_commands.up.sql (1.214kB)
+// 1528395590_email_outbox.down.sql (151B)
+// 1528395590_email_outbox.up.sql (1.165kB)
+// 1528395591_saved_search_digests.down.sql (60B)
+// 1528395591_saved_search_digests.up.sql (506B)
+// 1528395592_user_permissions_sync.down.sql (136B)
+// 1528395592_user_permissions_sync.up.sql (359B)
+// 1528395593_discussion_thread_participants.down.sql (111B)
+// 1528395593_discussion_thread_participants.up.sql (979B)
 
 package migrations
 
@@ -4478,6 +4518,806 @@ func _1528395573_recent_searchesUpSql() (*asset, error) {
 	return a, nil
 }
 
+var __1528395574_repo_redirectsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x09\xf2\x74\x77\x77\x0d\x52\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x29\xca\x4c\x8f\x2f\x4a\x4d\xce\x2f\x4a\x01\x52\x05\xf9\x40\x22\x25\x13\xc8\x2f\x51\xf0\xf7\x53\x00\x09\x58\x43\x74\xba\x85\xfa\x39\x87\x78\x02\xc5\x10\x5a\xb1\xe9\xd2\xd0\x84\xaa\x0f\x71\x74\xf2\x71\x45\x51\x8c\xa4\xaa\x18\xe8\x1e\x67\x7f\x5f\x5f\xcf\x10\x6b\x2e\x00\xd1\x42\xc8\x3c\xa0\x00\x00\x00")
+
+func _1528395574_repo_redirectsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395574_repo_redirectsDownSql,
+		"1528395574_repo_redirects.down.sql",
+	)
+}
+
+func _1528395574_repo_redirectsDownSql() (*asset, error) {
+	bytes, err := _1528395574_repo_redirectsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395574_repo_redirects.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xba, 0x25, 0x34, 0xcf, 0x95, 0xd9, 0x7e, 0x59, 0x42, 0x2c, 0x1f, 0x3, 0x9d, 0x19, 0x33, 0x79, 0xc2, 0x15, 0x92, 0xce, 0x27, 0x61, 0x3a, 0x23, 0xe6, 0xa5, 0x99, 0x96, 0x73, 0xf4, 0xb7, 0x85}}
+	return a, nil
+}
+
+var __1528395574_repo_redirectsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x75\x53\xcb\x6e\xdb\x30\x10\x3c\x5b\x5f\x31\x07\x1f\x6c\xc0\xf1\x0f\xb8\x29\xc0\x48\x2b\x47\xa8\x4c\x19\x34\x95\xc7\xc9\x10\x2c\xc6\x26\x12\x8b\x8a\xc4\x24\x45\xbf\xbe\x2b\xc9\x6e\x1e\x6d\x6f\x14\x67\x39\x3b\x33\xbb\xba\xa2\x65\x22\x17\x41\x10\x2a\x12\x9a\xa0\xc5\x55\x4a\x68\x4c\xed\xb6\x8d\x29\x6d\x63\x76\xbe\xc5\x24\x18\x55\xc5\xd1\x60\x67\xbd\xf9\xe9\xb1\x56\xc9\x4a\xa8\x7b\xfc\xa0\xfb\x59\x30\xea\x6b\x6d\x09\x5b\x79\xb3\x37\x0d\x64\xa6\x21\xf3\x34\x85\xa2\x98\x14\xc9\x90\x36\x3d\xdf\xc4\x96\x53\x64\x12\x11\xa5\xc4\x8d\x42\xb1\x09\x45\x44\x4c\xb0\x6b\x4c\xe1\x4d\xb9\x2d\x3c\xbc\x3d\x9a\xd6\x17\xc7\x1a\x6f\xd6\x1f\xfa\x4f\xfc\x72\x95\x79\x27\x8d\x28\x16\x79\xaa\x51\xb9\xb7\xc9\x34\x98\xbe\x0b\x4f\x64\x44\x77\x5f\x84\x6f\xcf\xda\xb8\xed\x67\x64\x72\x42\x3a\x82\x8b\x0b\x28\xb3\x73\x4d\x89\x02\xe7\x02\x3c\x34\xee\x08\x7f\x30\xa8\x1b\xf3\x6a\xdd\x4b\x8b\x3e\x01\xf7\xd0\x17\xd5\x0e\x6f\x07\x53\x99\x57\xf6\x6b\x3d\x6c\xcb\x77\x1d\x5e\xce\xd0\xba\x8e\xd0\x1f\xd8\x4d\xae\xd2\x16\x45\x55\x42\xac\x13\x2e\x78\x7e\x61\x6f\x2d\x5e\x5a\x5b\xed\x7b\x6a\xf7\x54\x0e\xac\x8f\xc6\xb0\x63\xd7\x3c\x32\x32\x87\x78\x57\x61\xdb\x8e\xac\x6c\x5c\x5d\x9b\x12\xae\xda\x19\x6e\x77\x92\xc2\x4d\x7d\xf1\x68\x2a\x14\xfb\xc2\x56\xf3\x73\x0e\x71\x2e\x43\x9d\xf4\x86\x3b\x4f\xdb\x4f\xbe\x27\x53\x9e\x8a\xce\x95\xdc\xc0\x37\x76\xdf\x8d\x4b\x6c\x30\x1e\x07\x57\xdd\x16\x04\xa3\x24\x46\x96\x46\xf3\x9e\xff\xdb\x77\x48\xba\x1d\xce\xfa\x9a\x18\x1d\x25\x72\x43\x4a\x73\xd4\x3a\xfb\x6b\x45\xba\xba\x19\xce\xb1\xe2\x46\xa4\x39\x0f\x7e\x72\xa6\x9b\xf5\x64\x8c\x30\xcd\x88\xd5\x85\x99\x8c\xd3\x24\xd4\xc3\xc3\x29\xa2\x0c\xf9\x3a\xea\x0c\x6c\x48\x9f\x69\x70\x09\xba\x0b\xd3\x3c\xa2\x68\x7e\xba\x9a\xe1\xc3\xba\x5c\x0e\x6b\xb0\x60\xce\xd3\x56\xc5\x2a\x5b\x7d\x95\x76\x7b\xcd\x6b\x38\x64\x76\xf9\xc7\x12\xbf\x21\x19\x21\x89\xf9\x30\x44\xd2\x41\x8b\x80\x2f\x17\xc1\x78\x8c\x54\xc8\x65\x2e\x96\x84\xfa\xa9\xde\xb7\xcf\x4f\x1f\xfe\x10\x95\x2c\x97\xa4\xfa\x00\xb7\xff\x0a\x19\x22\xd6\x8c\x9f\xec\x64\xf1\xd0\xfa\xb4\x82\x88\x33\x05\x12\xe1\x35\x54\x76\xcb\x1a\xee\x28\xcc\xb9\x6a\xad\xb2\x90\xa2\x5c\xd1\x7f\xc6\xd6\xb5\xcf\x56\xab\x44\x2f\x82\xdf\x61\x0d\x77\x21\xb1\x03\x00\x00")
+
+func _1528395574_repo_redirectsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395574_repo_redirectsUpSql,
+		"1528395574_repo_redirects.up.sql",
+	)
+}
+
+func _1528395574_repo_redirectsUpSql() (*asset, error) {
+	bytes, err := _1528395574_repo_redirectsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395574_repo_redirects.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x7b, 0x18, 0xde, 0xd, 0x63, 0x88, 0x4e, 0xb7, 0xd0, 0x54, 0x1c, 0x37, 0x5e, 0xc3, 0x7d, 0x5f, 0xe6, 0xfa, 0xc7, 0xd3, 0x6d, 0xef, 0x2c, 0x5b, 0xd8, 0xbb, 0x8e, 0xf8, 0x62, 0x15, 0x5b, 0x46}}
+	return a, nil
+}
+
+var __1528395575_repo_update_scheduleDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x4a\x2d\xc8\x8f\x2f\x2d\x48\x49\x2c\x49\x8d\x2f\x4e\xce\x48\x4d\x29\xcd\x49\x05\xaa\x75\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\xb0\xbf\x92\xc4\x3c\x00\x00\x00")
+
+func _1528395575_repo_update_scheduleDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395575_repo_update_scheduleDownSql,
+		"1528395575_repo_update_schedule.down.sql",
+	)
+}
+
+func _1528395575_repo_update_scheduleDownSql() (*asset, error) {
+	bytes, err := _1528395575_repo_update_scheduleDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395575_repo_update_schedule.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x2c, 0xaa, 0x7f, 0x73, 0x93, 0x72, 0x71, 0xe2, 0x24, 0x16, 0x69, 0xa3, 0x6b, 0x30, 0xe0, 0x65, 0x9c, 0x45, 0x81, 0x40, 0x5e, 0x47, 0xc6, 0x54, 0x97, 0x9a, 0x20, 0x4f, 0xfc, 0x94, 0x65, 0xa8}}
+	return a, nil
+}
+
+var __1528395575_repo_update_scheduleUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\x90\xc1\x6a\xc3\x30\x10\x44\xcf\xd1\x57\xec\xd1\x86\xfe\x41\x4e\x8a\xb3\x29\xa6\xb2\x5c\x14\xe5\x90\x93\x10\xd1\x92\x08\x1c\xcb\x48\xeb\xa6\xf4\xeb\xeb\xb8\x50\x7a\xec\x71\x86\x99\xc7\x30\x3b\x7c\x6d\xf5\x56\x88\xc6\xa0\xb4\x08\x56\xee\x14\x42\xa6\x29\xb9\x79\x0a\x9e\xc9\x95\xcb\x8d\xc2\x3c\x10\x54\x62\xb3\xfa\x31\x40\x1c\x99\xae\x94\xe1\xdd\xb4\x9d\x34\x67\x78\xc3\x33\x18\x3c\xa0\x41\xdd\xe0\x71\xad\x57\x31\xd4\xd0\x6b\xd8\xa3\xc2\x85\xdb\xc8\x63\x23\xf7\xf8\x22\x36\xcf\x6e\xfe\xf0\x83\x2b\x74\x49\x63\x28\xbf\x30\xdd\x5b\xd0\x27\xa5\x96\x4c\x98\xc9\x79\x06\x8e\x77\x2a\xec\xef\x13\x3c\x22\xdf\x56\x09\x5f\x69\xa4\xbf\xd1\xc1\x17\x76\x94\x73\xca\xc0\xf4\xc9\x8b\xf3\xb3\x3b\xfc\x0b\xb0\xcc\x3b\xc8\x93\xb2\x30\xa6\x47\x55\x8b\xfa\x79\x44\xdf\x75\xad\xdd\x8a\x6f\x48\x66\x9e\x82\x19\x01\x00\x00")
+
+func _1528395575_repo_update_scheduleUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395575_repo_update_scheduleUpSql,
+		"1528395575_repo_update_schedule.up.sql",
+	)
+}
+
+func _1528395575_repo_update_scheduleUpSql() (*asset, error) {
+	bytes, err := _1528395575_repo_update_scheduleUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395575_repo_update_schedule.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x63, 0x68, 0x99, 0x8e, 0xb0, 0x79, 0x6f, 0x70, 0x8e, 0xb4, 0x78, 0xee, 0x28, 0x5d, 0x70, 0xd9, 0x53, 0x6c, 0xc9, 0x49, 0x95, 0x23, 0xb9, 0x1d, 0xf, 0x7a, 0x12, 0x10, 0x63, 0x14, 0x73, 0x8a}}
+	return a, nil
+}
+
+var __1528395576_repo_update_schedule_failuresDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x4a\x2d\xc8\x8f\x2f\x2d\x48\x49\x2c\x49\x8d\x2f\x4e\xce\x48\x4d\x29\xcd\x49\xe5\xe2\x74\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\x48\x4b\xcc\xcc\x29\x2d\x4a\x2d\xd6\x41\x15\xce\x49\x2c\x2e\x89\x4f\x2d\x2a\xca\x2f\x8a\xcf\xce\xcc\x4b\x01\x1a\xeb\xec\xef\xeb\xeb\x19\x62\xcd\x05\x00\x34\x45\x2d\x83\x67\x00\x00\x00")
+
+func _1528395576_repo_update_schedule_failuresDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395576_repo_update_schedule_failuresDownSql,
+		"1528395576_repo_update_schedule_failures.down.sql",
+	)
+}
+
+func _1528395576_repo_update_schedule_failuresDownSql() (*asset, error) {
+	bytes, err := _1528395576_repo_update_schedule_failuresDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395576_repo_update_schedule_failures.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x86, 0xc, 0xc2, 0xbc, 0x27, 0xc8, 0xad, 0x79, 0xe1, 0xab, 0x98, 0x8e, 0xbf, 0x5, 0xbe, 0x65, 0x2, 0xbe, 0x1e, 0xd, 0xaa, 0x4b, 0x97, 0x95, 0xbf, 0x11, 0x20, 0x33, 0xe0, 0xaf, 0x2b, 0x7f}}
+	return a, nil
+}
+
+var __1528395576_repo_update_schedule_failuresUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x55\xcd\x41\x0a\xc3\x20\x10\x00\xc0\x73\x7d\xc5\x3e\xa0\x87\xde\x73\x32\xd1\x96\xc0\xaa\x10\xf4\x2c\x52\xb7\x6d\xa8\x24\x61\x55\xe8\xf3\xdb\x6b\x1f\x30\xcc\xa8\x6f\xb3\x1d\x84\x90\xe8\xf5\x02\x5e\x8e\xa8\x81\xe9\xd8\x63\x3f\x72\x6a\x14\xeb\xfd\x45\xb9\x17\x12\x27\xa9\x14\x4c\x0e\x83\xb1\xf0\x48\x6b\xe9\x4c\x15\xd6\xad\xd1\x93\x18\xac\xf3\x60\x03\x22\x28\x7d\x95\x01\x3d\x5c\xce\x7f\xa0\xa4\xda\x22\x31\xef\x1c\xdf\xeb\x96\xa1\xd1\xa7\xfd\xd2\xc9\x19\x33\xfb\x41\x7c\x01\xc4\x84\xcb\xd8\x85\x00\x00\x00")
+
+func _1528395576_repo_update_schedule_failuresUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395576_repo_update_schedule_failuresUpSql,
+		"1528395576_repo_update_schedule_failures.up.sql",
+	)
+}
+
+func _1528395576_repo_update_schedule_failuresUpSql() (*asset, error) {
+	bytes, err := _1528395576_repo_update_schedule_failuresUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395576_repo_update_schedule_failures.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x90, 0xe8, 0x9a, 0xa0, 0x12, 0x34, 0x9, 0xad, 0xaf, 0x92, 0xe8, 0x1, 0xf5, 0xe1, 0x44, 0xeb, 0xd, 0xcc, 0xfc, 0xa3, 0x8c, 0x4b, 0x1e, 0xe1, 0xf2, 0xd2, 0x92, 0x15, 0x52, 0x86, 0x1b, 0xd}}
+	return a, nil
+}
+
+var __1528395577_access_tokens_expires_atDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x4c\x4e\x4e\x2d\x2e\x8e\x2f\xc9\xcf\x4e\xcd\x2b\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\x48\xad\x28\xc8\x2c\x4a\x2d\x8e\x4f\x2c\x01\x6a\x71\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\xe1\x60\x55\x04\x43\x00\x00\x00")
+
+func _1528395577_access_tokens_expires_atDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395577_access_tokens_expires_atDownSql,
+		"1528395577_access_tokens_expires_at.down.sql",
+	)
+}
+
+func _1528395577_access_tokens_expires_atDownSql() (*asset, error) {
+	bytes, err := _1528395577_access_tokens_expires_atDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395577_access_tokens_expires_at.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe0, 0xdf, 0xc6, 0x65, 0x87, 0x89, 0xbc, 0x8d, 0x2b, 0xbe, 0x2e, 0xb2, 0xc7, 0x32, 0x57, 0x7f, 0x29, 0xf7, 0x34, 0x5a, 0x1f, 0x77, 0xb0, 0x11, 0xea, 0x64, 0xce, 0xa0, 0x7, 0x9a, 0x32, 0x9d}}
+	return a, nil
+}
+
+var __1528395577_access_tokens_expires_atUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x4c\x4e\x4e\x2d\x2e\x8e\x2f\xc9\xcf\x4e\xcd\x2b\x56\x70\x74\x71\x51\x70\xf6\xf7\x09\xf5\xf5\x53\x48\xad\x28\xc8\x2c\x4a\x2d\x8e\x4f\x2c\x51\x28\xc9\xcc\x4d\x2d\x2e\x49\xcc\x2d\x50\x28\xcf\x2c\xc9\x00\x73\x15\xaa\xf2\xf3\x52\x81\x46\x39\xfb\xfb\xfa\x7a\x86\x58\x73\x01\x00\x51\x00\x9b\x79\x5b\x00\x00\x00")
+
+func _1528395577_access_tokens_expires_atUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395577_access_tokens_expires_atUpSql,
+		"1528395577_access_tokens_expires_at.up.sql",
+	)
+}
+
+func _1528395577_access_tokens_expires_atUpSql() (*asset, error) {
+	bytes, err := _1528395577_access_tokens_expires_atUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395577_access_tokens_expires_at.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x54, 0x37, 0x2e, 0x84, 0x31, 0xab, 0x9f, 0x76, 0xde, 0xc1, 0x34, 0x2b, 0xae, 0xce, 0xda, 0x4d, 0x9c, 0xd5, 0x4, 0x47, 0x1d, 0x5d, 0x6e, 0xdd, 0xc3, 0xe5, 0xe, 0x32, 0x6d, 0x21, 0xe5, 0xdb}}
+	return a, nil
+}
+
+var __1528395578_critical_and_site_config_authorDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x48\x2e\xca\x2c\xc9\x4c\x4e\xcc\x89\x4f\xcc\x4b\x89\x2f\xce\x2c\x49\x8d\x4f\xce\xcf\x4b\xcb\x4c\x57\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\x48\x2c\x2d\xc9\xc8\x2f\x8a\x2f\x2d\x4e\x2d\x8a\xcf\x4c\x01\x9a\xe0\xec\xef\xeb\xeb\x19\x62\xcd\x05\x00\x0b\x35\x81\x46\x52\x00\x00\x00")
+
+func _1528395578_critical_and_site_config_authorDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395578_critical_and_site_config_authorDownSql,
+		"1528395578_critical_and_site_config_author.down.sql",
+	)
+}
+
+func _1528395578_critical_and_site_config_authorDownSql() (*asset, error) {
+	bytes, err := _1528395578_critical_and_site_config_authorDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395578_critical_and_site_config_author.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf3, 0xb4, 0xf7, 0x5a, 0x55, 0xd8, 0x49, 0x0, 0xfb, 0x9b, 0xcb, 0x8b, 0x76, 0xdc, 0x76, 0xa2, 0xe6, 0x90, 0xf6, 0xe8, 0xd7, 0x8a, 0x4f, 0x10, 0xe9, 0x87, 0x26, 0xc6, 0x62, 0xab, 0x49, 0xd9}}
+	return a, nil
+}
+
+var __1528395578_critical_and_site_config_authorUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x15\xcd\xb1\x0a\xc3\x20\x10\x00\xd0\xdd\xaf\xb8\xb1\xfd\x86\x4c\x46\xaf\x25\x70\x51\x30\x66\x16\x51\x9b\x1e\x14\x03\x6a\xfe\xbf\xed\xfc\x86\x37\xe3\x73\x31\x93\x10\x92\x3c\x3a\xf0\x72\x26\x84\xd4\x78\x70\x8a\x9f\x10\x6b\x0e\x9d\x47\x09\xe9\xac\x2f\x3e\x40\x6a\x0d\xca\xd2\xbe\x1a\x88\xd7\x78\x9f\x2d\x5c\xbd\xb4\xc0\x19\xb8\x8e\x72\x94\x06\x0e\x1f\xe8\xd0\x28\xdc\xe0\x4f\xfd\xc6\xf9\x0e\xd6\x80\x46\x42\x8f\xb0\xa1\x07\xb3\x13\xfd\x42\x65\xd7\x75\xf1\x93\xf8\x02\xd7\x5a\x9b\xaa\x81\x00\x00\x00")
+
+func _1528395578_critical_and_site_config_authorUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395578_critical_and_site_config_authorUpSql,
+		"1528395578_critical_and_site_config_author.up.sql",
+	)
+}
+
+func _1528395578_critical_and_site_config_authorUpSql() (*asset, error) {
+	bytes, err := _1528395578_critical_and_site_config_authorUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395578_critical_and_site_config_author.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x93, 0xa3, 0xc7, 0x68, 0x49, 0x1a, 0xff, 0x79, 0xc5, 0xfe, 0x4d, 0x28, 0xd5, 0x43, 0x20, 0x34, 0xe1, 0x68, 0xed, 0x50, 0x11, 0x86, 0xea, 0xcb, 0x70, 0x2c, 0x63, 0x4c, 0xf6, 0x54, 0xee, 0xe2}}
+	return a, nil
+}
+
+var __1528395579_users_suspended_atDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x2d\x4e\x2d\x2a\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\x28\x2e\x2d\x2e\x48\xcd\x4b\x49\x4d\x89\x4f\x2c\x01\x2a\x76\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\x68\xda\xae\x76\x3d\x00\x00\x00")
+
+func _1528395579_users_suspended_atDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395579_users_suspended_atDownSql,
+		"1528395579_users_suspended_at.down.sql",
+	)
+}
+
+func _1528395579_users_suspended_atDownSql() (*asset, error) {
+	bytes, err := _1528395579_users_suspended_atDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395579_users_suspended_at.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x35, 0xc9, 0x87, 0xa6, 0x83, 0xbb, 0xfd, 0xd, 0xd4, 0x1d, 0x84, 0xc2, 0x1a, 0xdd, 0xd4, 0x75, 0x21, 0x31, 0x7d, 0x7c, 0x90, 0xa, 0x49, 0x95, 0x3e, 0x78, 0xd7, 0x9, 0x68, 0x25, 0x56, 0x93}}
+	return a, nil
+}
+
+var __1528395579_users_suspended_atUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x2d\x4e\x2d\x2a\x56\x70\x74\x71\x51\x70\xf6\xf7\x09\xf5\xf5\x53\x28\x2e\x2d\x2e\x48\xcd\x4b\x49\x4d\x89\x4f\x2c\x51\x28\xc9\xcc\x4d\x2d\x2e\x49\xcc\x2d\x50\x28\xcf\x2c\xc9\x00\x73\x15\xaa\xf2\xf3\x52\x81\x86\x38\xfb\xfb\xfa\x7a\x86\x58\x73\x01\x00\x1e\x81\x4e\x88\x55\x00\x00\x00")
+
+func _1528395579_users_suspended_atUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395579_users_suspended_atUpSql,
+		"1528395579_users_suspended_at.up.sql",
+	)
+}
+
+func _1528395579_users_suspended_atUpSql() (*asset, error) {
+	bytes, err := _1528395579_users_suspended_atUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395579_users_suspended_at.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x40, 0x99, 0x83, 0xa9, 0xaf, 0x8e, 0x61, 0xf7, 0xee, 0xbe, 0x9, 0xb2, 0xf6, 0xc2, 0x65, 0xa, 0x88, 0x6e, 0xae, 0x9, 0x45, 0x45, 0xed, 0x7e, 0x94, 0x8f, 0xd7, 0xc6, 0x41, 0x9b, 0x35, 0x97}}
+	return a, nil
+}
+
+var __1528395580_user_permissionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x2d\x4e\x2d\x8a\x2f\x48\x2d\xca\xcd\x2c\x2e\xce\xcc\xcf\x2b\x06\xaa\x73\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\x72\x9d\x7d\x74\x38\x00\x00\x00")
+
+func _1528395580_user_permissionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395580_user_permissionsDownSql,
+		"1528395580_user_permissions.down.sql",
+	)
+}
+
+func _1528395580_user_permissionsDownSql() (*asset, error) {
+	bytes, err := _1528395580_user_permissionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395580_user_permissions.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x99, 0x3e, 0xe, 0xa0, 0x73, 0x4c, 0x4a, 0xe0, 0xbd, 0xaf, 0x8d, 0xb5, 0x8c, 0xd8, 0x14, 0x5c, 0xe4, 0x36, 0xc1, 0x51, 0xae, 0x19, 0x3d, 0xcd, 0xaa, 0x9a, 0x14, 0xaf, 0x84, 0x19, 0x4b, 0xac}}
+	return a, nil
+}
+
+var __1528395580_user_permissionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\x51\xcb\x6e\x83\x30\x10\xbc\xf3\x15\x7b\xc4\x12\x7f\xc0\xc9\x81\x4d\x85\x6a\x4c\xe4\x10\xa9\x51\x55\x21\x54\x56\xad\x0f\x60\x64\x3b\x8f\xf6\xeb\xeb\xd2\xa4\x44\x29\x87\xfa\x66\xcf\x78\x76\x66\x67\x85\x0f\x85\x4c\xa3\x28\x53\xc8\x6b\x84\x9a\xaf\x04\xc2\xc1\x91\x6d\x46\xb2\xbd\x76\x4e\x9b\xc1\x41\x1c\x41\x38\xd3\xb3\xee\x40\x0f\x9e\xde\xc8\x82\xac\x6a\x90\x3b\x21\x40\xe1\x1a\x15\xca\x0c\xb7\x13\xc7\xc5\xba\x63\x50\x49\xc8\x51\x60\x10\xcd\xf8\x36\xe3\x39\x26\x93\xc8\x2c\x0b\x9e\xce\xfe\x57\xe4\x82\x5a\x73\xd4\x5d\x18\x13\x64\x8e\xfa\x95\x1a\xff\x31\xd2\xbf\x88\xc1\xd7\x02\xcd\xd2\x68\x02\xe4\xae\x9e\x9f\x5f\xee\x08\x87\xb1\x6b\x3d\x75\x4d\xeb\xc1\xeb\x9e\x9c\x6f\xfb\x11\x4e\xda\xbf\x4f\x57\xf8\x34\x03\xcd\x39\x73\x5c\xf3\x9d\xa8\x61\x30\xa7\x98\xfd\xfc\xdf\xa8\xa2\xe4\x6a\x0f\x8f\xb8\x87\xf8\xb2\xa0\xe4\x26\x64\xb2\x1c\x29\x59\x0a\xc0\x22\x96\x5e\x8b\x28\x64\x8e\x4f\x7f\x8a\x68\x6e\xec\x86\xfd\xde\xc3\xf1\x0c\xb3\xef\x4e\xab\xb2\x2c\xea\x34\xfa\x02\x65\xf2\x91\xfb\xe4\x01\x00\x00")
+
+func _1528395580_user_permissionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395580_user_permissionsUpSql,
+		"1528395580_user_permissions.up.sql",
+	)
+}
+
+func _1528395580_user_permissionsUpSql() (*asset, error) {
+	bytes, err := _1528395580_user_permissionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395580_user_permissions.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xe4, 0xe8, 0xea, 0x99, 0xc4, 0xa4, 0xa, 0x9, 0xcc, 0x25, 0x34, 0x96, 0x71, 0xd0, 0x99, 0xad, 0x3a, 0x13, 0x30, 0xe2, 0x73, 0x4, 0x69, 0x41, 0x9c, 0x80, 0x23, 0x68, 0x27, 0x4b, 0x3e, 0x10}}
+	return a, nil
+}
+
+var __1528395581_explicit_repo_permissionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\xad\x28\xc8\xc9\x4c\xce\x2c\x89\x2f\x4a\x2d\xc8\x8f\x2f\x48\x2d\xca\xcd\x2c\x2e\xce\xcc\xcf\x2b\x06\x6a\x70\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\x3f\x46\x96\x67\x41\x00\x00\x00")
+
+func _1528395581_explicit_repo_permissionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395581_explicit_repo_permissionsDownSql,
+		"1528395581_explicit_repo_permissions.down.sql",
+	)
+}
+
+func _1528395581_explicit_repo_permissionsDownSql() (*asset, error) {
+	bytes, err := _1528395581_explicit_repo_permissionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395581_explicit_repo_permissions.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xea, 0x8e, 0x80, 0xdb, 0x37, 0x15, 0x3b, 0x42, 0xa, 0xab, 0x69, 0x20, 0x52, 0x73, 0x9, 0xec, 0x13, 0xfd, 0x7b, 0x65, 0x75, 0xa, 0x28, 0x39, 0x78, 0x56, 0x9d, 0x45, 0xdf, 0xf8, 0x98, 0xac}}
+	return a, nil
+}
+
+var __1528395581_explicit_repo_permissionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa5\x52\xdd\x4a\xc3\x30\x14\xbe\xef\x53\x9c\xcb\x16\x7c\x83\x8a\x90\xa5\x67\x1a\xd6\xa6\xda\xa6\xe0\x10\x29\x65\x0d\x33\xb0\x36\x25\x89\x6c\xf8\xf4\xc6\x6e\x19\x7a\x31\x07\x9a\xbb\x9c\xef\x7c\x3f\x39\x27\x0b\xbc\x67\x3c\x8d\x22\x5a\x21\x11\x08\x82\x2c\x72\x04\x79\x98\x76\x6a\xa3\x5c\x6b\xe4\xa4\xdb\x49\x9a\x41\x59\xab\xf4\x68\x21\x8e\xc0\x1f\xd5\x83\x95\x46\x75\x3b\x78\xac\x58\x41\xaa\x35\xac\x70\x7d\x33\x43\x33\xc3\xe3\x6a\x74\x72\x2b\x0d\x54\xb8\xc4\x0a\x39\xc5\x7a\x86\x62\xd5\x27\x50\x72\xc8\x30\x47\x6f\x47\x49\x4d\x49\x86\xdf\xa8\x53\xe7\x9c\x34\x23\x38\x79\x70\xc7\xf2\xbb\xb7\xf2\x8a\x36\x48\xbe\xbc\x02\x2f\x05\xf0\x26\xcf\x8f\x0d\xda\x6c\x7f\xc5\x37\x46\x76\x4e\xf6\x6d\xe7\xc0\xa9\x41\x5a\xd7\x0d\x13\xec\x95\x7b\x9b\xaf\xf0\xa1\x47\x79\x66\xf8\x60\x4b\xd2\xe4\x02\x46\xbd\x8f\x93\x53\x80\xa9\xff\x17\x9f\x96\xbc\x16\x15\x61\x5c\x5c\x1e\xec\xb1\xa0\xcd\xf9\xfd\xf4\x01\xe9\x0a\xe2\x38\xcc\x93\xd5\xb3\x7e\x02\xb7\x77\x10\xff\x98\x54\x40\x92\x28\x49\xc3\x1a\x1b\xce\x9e\x1a\x04\xc6\x33\x7c\xbe\x66\xea\xc5\xfd\x42\x2e\x36\x85\x04\x7f\x13\x0f\x21\xaf\x3b\x9c\x3a\x93\xaf\xbf\x58\x16\x05\x13\x69\xf4\x09\x16\x9d\xef\xe4\x9c\x02\x00\x00")
+
+func _1528395581_explicit_repo_permissionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395581_explicit_repo_permissionsUpSql,
+		"1528395581_explicit_repo_permissions.up.sql",
+	)
+}
+
+func _1528395581_explicit_repo_permissionsUpSql() (*asset, error) {
+	bytes, err := _1528395581_explicit_repo_permissionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395581_explicit_repo_permissions.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x2a, 0x57, 0xad, 0xeb, 0xe, 0x1a, 0x5, 0xb1, 0x40, 0xea, 0x29, 0xd1, 0x78, 0xe6, 0xa8, 0xed, 0x59, 0x28, 0xd5, 0xcc, 0x20, 0xee, 0xf2, 0xda, 0x5c, 0x75, 0x28, 0x5f, 0xf8, 0x73, 0x56, 0x78}}
+	return a, nil
+}
+
+var __1528395582_audit_logDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x09\xf2\x74\x77\x77\x0d\x52\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x29\xca\x4c\x8f\x4f\x2c\x4d\xc9\x2c\x89\xcf\xc9\x07\xb2\x0a\x0a\x52\xf3\x52\xe2\xf3\xf3\x72\x2a\x15\xfc\xfd\x14\xe0\x12\xd6\x10\xfd\x6e\xa1\x7e\xce\x21\x9e\x40\x09\x84\x01\x58\xf5\x6a\x68\x42\x35\x84\x38\x3a\xf9\xb8\x62\x53\x0d\x74\x91\xb3\xbf\xaf\xaf\x67\x88\x35\x17\x00\x52\xb5\x00\xcf\xa2\x00\x00\x00")
+
+func _1528395582_audit_logDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395582_audit_logDownSql,
+		"1528395582_audit_log.down.sql",
+	)
+}
+
+func _1528395582_audit_logDownSql() (*asset, error) {
+	bytes, err := _1528395582_audit_logDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395582_audit_log.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf9, 0xf5, 0xa4, 0xe4, 0x4b, 0x7, 0xe2, 0x97, 0x9d, 0x8d, 0x94, 0x22, 0xe5, 0xa4, 0x6e, 0xf1, 0xe4, 0x57, 0xb8, 0x26, 0xec, 0xcb, 0x7b, 0x50, 0xc8, 0x12, 0xb9, 0xc3, 0x8d, 0x5, 0x5c, 0x40}}
+	return a, nil
+}
+
+var __1528395582_audit_logUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7d\x53\xc1\x8e\xda\x30\x10\x3d\xe3\xaf\x98\x03\x12\x20\xc1\x7e\x40\x39\x85\xc4\xd0\xa8\x21\x41\x26\xd1\xb2\xa7\xc8\x10\x13\xbc\x0a\x76\xea\xcc\x96\xd2\xaa\xff\xde\x09\x2c\x0b\xb4\xa5\x37\xfb\x65\xde\x9b\xf7\xc6\x93\x09\x9f\x85\xf1\x98\x31\x5f\x70\x2f\xe5\x90\x7a\x93\x88\x83\x7c\x2b\x34\xe6\x95\x2d\xa1\xcf\x3a\xba\x80\xb5\x2e\x1b\xe5\xb4\xac\x60\x21\xc2\xb9\x27\x5e\xe0\x0b\x7f\x19\xb2\xce\xc6\x29\x89\xaa\xc8\x25\x02\xea\xbd\x6a\x50\xee\x6b\x38\x68\xdc\x9d\xae\xf0\xc3\x1a\x05\x71\x92\x42\x9c\x45\x11\x04\x7c\xea\x65\x51\x0a\xc6\x1e\xfa\x03\x22\xcb\x0d\x5a\x97\xbf\x91\x70\x4e\x2d\xb4\x41\x55\x2a\x47\xb8\xae\x01\xd5\x77\xa4\xd3\xd6\xba\x83\x74\x05\x35\xa0\xd3\x05\x24\x9a\xb6\xe6\x74\xfb\xd0\x26\x18\xa5\x2b\x15\xe6\x78\xac\xd5\xa5\xf2\x1d\x22\xf1\x77\xa0\x50\x28\x75\xd5\xc0\x6b\x63\xcd\xfa\x6f\x63\xbd\x9f\xbf\x7a\x6c\x70\x9d\x45\x18\x07\x7c\x75\x9d\x45\x7e\x93\x36\x89\xaf\x78\xff\x8a\x13\xf9\x01\xf7\x3e\xec\x1d\xfd\xee\xd3\x7f\x15\xda\xdc\x7f\x52\x09\x7b\xcc\x39\x4f\xe0\x9e\x73\x33\xa8\x21\x7c\x8c\xa8\x8d\x3d\x1a\x41\xba\x53\xe7\x52\x68\x1f\x5f\x37\x20\xeb\x5a\x99\x62\x64\x4d\x75\xfc\x04\x4e\xbd\xaa\x0d\x82\x34\x47\x90\x88\x6a\x5f\xd3\xb3\x5b\xd8\xec\xa4\x29\x15\xd0\x0b\x39\xb5\xb7\xdf\x48\xc0\xb4\x52\xca\xa0\x3b\x3e\x41\x3f\x15\x59\xec\xb7\xe6\x48\xcd\x58\x62\x6f\xb7\x24\xa2\x8a\x21\x34\x16\x70\xd7\xae\x0e\x2d\x4e\x03\x1b\x69\x48\xa0\x21\xbb\x48\x26\x50\xae\x2b\xf5\x34\xb8\x04\x9b\x92\x46\x1a\xde\xe6\xc8\xcf\xce\xf2\xd6\x59\x7f\x00\x82\xa7\x99\x88\x97\x80\x4e\x97\xb4\x46\xe0\x2d\xa1\xdb\x65\x93\x76\xb9\x59\x47\x78\xe1\x92\x03\x5f\xf9\x7c\x71\x12\xe9\x5d\xf7\xfb\x3e\x62\x6f\xcc\x78\x1c\x8c\x59\xb7\x0b\x91\x17\xcf\x32\x6f\xc6\xa1\xae\xea\xb2\xf9\x5a\xdd\xfc\x22\x22\x9c\xcd\xb8\x38\xb5\xca\xff\xe9\x07\x26\x7c\x9a\x08\x0e\xd9\x22\x68\x09\x89\xa0\x15\x8b\x78\x7b\xba\x09\x00\x54\x02\xdc\xf3\x3f\x83\x48\x9e\x59\x87\xaf\xb8\x9f\x51\xc9\x42\x24\x3e\x0f\x32\xc1\x1f\x45\x6d\x8d\x24\xf3\x79\x98\x8e\xd9\x6f\x17\x71\xe6\x65\xbc\x03\x00\x00")
+
+func _1528395582_audit_logUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395582_audit_logUpSql,
+		"1528395582_audit_log.up.sql",
+	)
+}
+
+func _1528395582_audit_logUpSql() (*asset, error) {
+	bytes, err := _1528395582_audit_logUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395582_audit_log.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd1, 0xdc, 0xc1, 0x7d, 0x41, 0xae, 0x3b, 0xf7, 0x37, 0xa4, 0xc, 0x80, 0x17, 0x6a, 0xc8, 0xa2, 0x1d, 0x87, 0xd3, 0x67, 0x5d, 0xb1, 0x32, 0xf9, 0x4, 0x4b, 0x91, 0x8, 0xa0, 0x63, 0xbb, 0xe6}}
+	return a, nil
+}
+
+var __1528395583_registry_extension_releases_yankedDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x0d\xcc\x4b\x0a\x80\x20\x14\x00\xc0\xbd\xa7\x78\xf7\x68\xa5\x66\x21\xf8\x09\x35\x68\x27\x42\x8f\x90\xc2\x40\x5d\xd4\xed\x6b\x0e\x30\x4c\xcc\xd2\x0c\x84\x50\x15\x84\x83\x40\x99\x12\x50\xf1\xc8\xad\xd7\x37\xe2\xd3\xb1\xb4\x7c\x97\x58\xf1\xc2\xd4\xb0\xc1\xe8\xec\x02\xdc\xaa\x55\x1b\x90\x13\x88\x4d\xfa\xe0\xe1\x4d\xe5\xc4\x3d\xa6\xfe\x4f\xdc\x6a\x2d\xc3\x40\x3e\x4d\x03\xf6\xf7\x5a\x00\x00\x00")
+
+func _1528395583_registry_extension_releases_yankedDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395583_registry_extension_releases_yankedDownSql,
+		"1528395583_registry_extension_releases_yanked.down.sql",
+	)
+}
+
+func _1528395583_registry_extension_releases_yankedDownSql() (*asset, error) {
+	bytes, err := _1528395583_registry_extension_releases_yankedDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395583_registry_extension_releases_yanked.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa5, 0x71, 0xae, 0x50, 0xeb, 0xcb, 0xad, 0xcd, 0x44, 0x40, 0x91, 0x64, 0xaf, 0xb, 0xa2, 0xdd, 0x56, 0xaa, 0x3b, 0xe, 0x43, 0xfe, 0x21, 0xac, 0xcd, 0x61, 0xcf, 0xef, 0x5c, 0x96, 0xb5, 0xa3}}
+	return a, nil
+}
+
+var __1528395583_registry_extension_releases_yankedUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x1d\xcc\x41\x0a\x83\x30\x10\x05\xd0\x7d\x4e\x31\xf7\x70\x15\x35\x88\x90\x28\x48\xba\x0e\x81\x7e\xda\xd0\x1a\x25\x33\x60\xe3\xe9\x95\x2e\xdf\xe6\xb5\x66\x18\xa7\x46\x29\x6d\xbd\x59\xc8\xeb\xd6\x1a\x2a\x78\x25\x96\x52\x03\x7e\x82\xcc\x69\xcb\xa1\xe0\x8b\xc8\x60\xd2\x7d\x4f\xdd\x6c\x1f\x6e\xa2\x1a\xf3\x07\xcf\x10\x85\x24\xad\x60\x89\xeb\x4e\x47\x92\xf7\x9f\x74\x6e\x19\xf7\xdb\xcd\xce\x8d\xbe\x51\x17\x0d\x80\x7a\xd0\x68\x00\x00\x00")
+
+func _1528395583_registry_extension_releases_yankedUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395583_registry_extension_releases_yankedUpSql,
+		"1528395583_registry_extension_releases_yanked.up.sql",
+	)
+}
+
+func _1528395583_registry_extension_releases_yankedUpSql() (*asset, error) {
+	bytes, err := _1528395583_registry_extension_releases_yankedUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395583_registry_extension_releases_yanked.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x86, 0xf2, 0xfd, 0x6d, 0x6f, 0xcd, 0xd3, 0x8d, 0x3b, 0xd4, 0x0, 0x52, 0x9f, 0x56, 0xe2, 0x8, 0xa9, 0x54, 0x7e, 0xfa, 0xa1, 0x8a, 0x70, 0xc6, 0x90, 0xe, 0x52, 0xab, 0xe4, 0x26, 0x35, 0xf}}
+	return a, nil
+}
+
+var __1528395584_registry_extensions_mirrorDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa5\x52\xc1\x6e\x83\x30\x14\xbb\xf3\x15\xef\x08\x52\x0f\xbb\x67\x9a\x44\xc3\xeb\x1a\x2d\x24\x1b\x04\xad\x37\xc4\x4a\xc4\x90\x28\x4c\x09\x48\xdb\xdf\x8f\xb5\x74\x54\x02\x95\x4e\x3b\xc7\x76\x6c\x3f\xaf\xf1\x91\x09\xe2\x38\x01\x72\x54\x08\x9b\x48\x86\x60\x74\x51\xda\xd6\x7c\xa5\xfa\xb3\xd5\xb5\x2d\x9b\x3a\x35\xba\xd2\x99\xd5\x16\x5e\xb7\x18\x21\xec\x8d\xce\xda\xc6\xa4\x9d\xd5\x26\x2d\x73\x60\x31\x88\x84\x73\xb2\x20\x73\xa6\x1f\x4a\x63\x1a\xa3\xf3\x8b\x0f\x06\x11\xa9\x06\x21\xc7\xe7\x0a\x23\x50\xfe\x9a\xe3\x55\x43\x27\x1c\x95\x3c\x09\xc5\xc4\x57\x8c\xea\x52\x33\x88\xe4\x33\x30\x11\xe0\x0e\xd8\x06\x70\xc7\x62\x15\xcf\xd9\x4c\x67\x0d\x92\x4b\xfe\x1c\xeb\xa3\x7b\xab\x4a\xfb\xde\x7f\x5d\x67\x07\x4d\x1c\x1a\xa1\xdf\x97\x91\x08\xf6\x92\xe0\xcd\x3c\x90\x62\x0e\xe5\xba\x54\xfa\x1c\x63\x8a\xee\x88\x1f\x72\xae\xe0\xce\xf3\x56\x30\x87\x68\x4c\x31\x02\x7e\xe4\xbd\xe1\x06\x79\x5f\x60\xdb\x27\xcc\xda\xf1\x7a\x0b\xa5\x5b\x38\x16\x40\xa5\x88\x55\xe4\x33\xa1\x66\xd3\xd8\xb2\x2e\x2a\x3d\x86\x22\x8b\xaa\x7e\x10\xfc\x55\x14\xe8\x16\xe9\x13\xb8\xd3\x2e\xce\x69\x3c\xb8\x7f\x80\x49\x11\xbf\xaf\xde\xed\x69\x8f\xcb\x1a\xf7\x72\xda\x46\x6a\x9b\xce\xec\x35\xf9\x97\xc8\x64\x60\x0e\x95\x61\xc8\x14\x71\xbe\x01\xe6\x31\xc6\xc9\x97\x03\x00\x00")
+
+func _1528395584_registry_extensions_mirrorDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395584_registry_extensions_mirrorDownSql,
+		"1528395584_registry_extensions_mirror.down.sql",
+	)
+}
+
+func _1528395584_registry_extensions_mirrorDownSql() (*asset, error) {
+	bytes, err := _1528395584_registry_extensions_mirrorDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395584_registry_extensions_mirror.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xdd, 0x6d, 0x8b, 0xa9, 0x73, 0x5a, 0x46, 0x98, 0xc6, 0x28, 0x98, 0xf5, 0x1, 0x33, 0xe6, 0xa, 0xa9, 0x74, 0xcd, 0xee, 0xf1, 0x7, 0xe2, 0x19, 0x5a, 0x75, 0x80, 0x48, 0xb0, 0x5, 0xe8, 0x80}}
+	return a, nil
+}
+
+var __1528395584_registry_extensions_mirrorUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9d\x54\x5d\x4f\x83\x30\x14\x7d\xe7\x57\x9c\xc7\x91\x4c\xe3\xfb\x8c\x09\x83\xc6\x11\x59\x51\xd6\x45\xdf\x08\x6e\x77\x1b\x91\xd1\xa5\xed\x8c\xfe\x7b\xcb\x3e\x00\x95\xe9\xf4\x85\xa6\xbd\xe7\x9c\xde\x7b\xb8\xb7\x43\x76\x1b\xf2\x81\xe3\x5c\x5c\x80\xbd\x19\x2a\x75\x2e\x4b\x0d\xb3\xca\x0c\x32\x45\x58\xe7\x4a\x49\x45\x73\x2c\x94\x5c\x23\x83\xa2\xb5\x34\x64\x97\x65\xae\x8d\x7a\xc7\x2a\x7b\x25\x94\x12\x85\x9c\x65\x05\x36\xdb\xe7\x22\xd7\x2b\x52\x97\x10\x2b\x7a\xc7\x0b\xd1\xc6\x6a\x51\xa5\x4e\x47\x75\x84\xc1\x5e\xdf\x54\x90\x9d\x80\x3d\xb4\x9b\xaf\xe2\x97\x8e\x17\x09\x96\x40\x78\xc3\x88\xd5\xa7\x29\x35\x69\x7a\x41\x00\x3f\x8e\xa6\x63\x5e\x27\xda\x84\xd3\x7c\x8e\x59\x6e\xec\x7e\xf0\x77\xa1\x54\xcb\xad\x9a\x11\xf6\xf4\x5f\xf9\x41\x12\xdf\x5b\x01\x3e\x11\x89\x17\x72\xd1\x85\x49\x75\x5e\x2e\x0b\x4a\x6b\x8f\xce\xcd\xea\x4f\xa2\xf0\x47\xcc\xbf\x43\xcf\x01\x7c\x6f\xc2\xf0\x38\x62\xa7\xbc\x09\x27\xe0\xd3\x28\x82\xa8\x20\xbd\x5a\x21\xdd\x6a\xfb\x69\xe2\x2e\xae\x6f\xda\x61\xa9\x96\xed\xa8\xbd\x88\x45\xf6\xa2\x93\x7c\x78\x3c\xc0\x29\x3a\x18\x0f\x1c\xd7\x1a\xbc\x33\x30\xe4\x01\x7b\xea\x2c\xb3\xe1\x97\xd9\x9a\x06\x8e\x9f\x30\x4f\x30\x4c\x79\xf8\x30\x65\x67\xf3\x10\xf3\x2e\x54\xaf\xe7\xc7\x5e\xc4\x26\x3e\xfb\xee\x42\x1f\x57\xae\xdb\x47\x17\x62\x5f\xc9\x01\x50\xc9\xbb\x95\xdb\x09\xc3\x9c\x0a\x32\xd6\x6d\xdb\xe2\x6d\x0f\x7e\xfc\x0b\xe7\x97\xd4\x2d\x73\xa2\xb2\x4e\xf0\xbf\xf3\x8c\xc5\x21\xd7\x6a\x9e\x13\xcb\xce\x34\x69\xc8\x45\xf3\x4a\xb4\x9a\xf7\xf3\xcb\x30\x53\x94\x19\xa9\x7e\x1b\xe9\x54\x1d\x55\xf7\xb8\xc3\x4c\x1e\xd8\x75\x6b\xed\xba\xa5\x95\x8e\x1f\x8f\xc7\xa1\x18\x38\x1f\x2c\x7a\x34\x37\xcd\x04\x00\x00")
+
+func _1528395584_registry_extensions_mirrorUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395584_registry_extensions_mirrorUpSql,
+		"1528395584_registry_extensions_mirror.up.sql",
+	)
+}
+
+func _1528395584_registry_extensions_mirrorUpSql() (*asset, error) {
+	bytes, err := _1528395584_registry_extensions_mirrorUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395584_registry_extensions_mirror.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xf7, 0xa0, 0xbf, 0x2b, 0x32, 0x34, 0x4b, 0x6e, 0x70, 0x57, 0xd7, 0xda, 0x8, 0xe6, 0xfb, 0x67, 0x63, 0xdc, 0xbb, 0xcf, 0x56, 0x6f, 0x15, 0xfd, 0x7f, 0xd7, 0xb5, 0xfe, 0xf5, 0x7b, 0xf1, 0xf3}}
+	return a, nil
+}
+
+var __1528395585_discussion_subscriptions_reactionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa5\x8e\xb1\x0a\xc3\x20\x14\x45\x77\xbf\xc2\xff\x70\x4a\x52\x5b\x04\x8d\x21\xb1\xd0\xed\x91\xa8\x50\xa1\x89\xc5\xa7\x85\xfe\x7d\xa5\x64\x28\x74\xe8\xd0\xf5\x72\xce\xe1\xb6\xfc\x24\x7a\x46\xc8\x61\xd4\x03\x35\x4d\x2b\x39\x15\x47\xca\x2f\x62\x32\x13\x75\x01\x6d\x41\x0c\x71\x03\x1b\xd7\xd5\x6f\x19\x92\x9f\x6d\xae\x03\xb2\x9f\x4a\xbe\x56\xd8\x01\x96\x05\x6d\x0a\xf7\xdd\x22\x8d\x34\x7c\xdc\xbd\x2f\x1a\xe9\xbb\xda\x69\x79\x56\xfd\x47\x36\x79\x8c\xb7\x87\x77\xb0\x3c\xa1\xa0\x4f\x10\x1c\xfb\xb3\x34\xe7\x7a\xa6\xd3\x4a\x09\xc3\xc8\x0b\x05\x72\xe9\x3a\x07\x01\x00\x00")
+
+func _1528395585_discussion_subscriptions_reactionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395585_discussion_subscriptions_reactionsDownSql,
+		"1528395585_discussion_subscriptions_reactions.down.sql",
+	)
+}
+
+func _1528395585_discussion_subscriptions_reactionsDownSql() (*asset, error) {
+	bytes, err := _1528395585_discussion_subscriptions_reactionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395585_discussion_subscriptions_reactions.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb2, 0x82, 0x89, 0x33, 0xaf, 0xdf, 0x95, 0xec, 0x4e, 0xd, 0xc8, 0x27, 0x2d, 0xf6, 0x14, 0x97, 0xaa, 0x13, 0xe1, 0xa8, 0xad, 0xac, 0x80, 0x36, 0x91, 0xc2, 0x73, 0x33, 0x13, 0xce, 0x7a, 0x69}}
+	return a, nil
+}
+
+var __1528395585_discussion_subscriptions_reactionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xbd\x94\xdb\x4e\x83\x40\x10\x86\xef\x79\x8a\xb9\x13\x92\xda\x17\x68\xbc\xc0\xb2\x35\x44\x0a\x86\xd2\x44\xaf\xc8\x02\x63\xbb\x09\xb0\x84\x5d\x6c\xeb\xd3\x3b\x3d\x40\xf1\x40\x35\xc6\xc8\x15\xec\xcc\xfc\x33\xf3\xfd\x1b\x6e\xd9\x9d\xeb\x4f\x0c\xc3\xf6\x22\x16\x42\x64\xdf\x7a\x0c\x32\xa1\xd2\x46\x29\x21\xcb\x58\xaf\x6b\xe4\x99\x02\xdb\x71\x60\x1a\x78\xcb\xb9\x0f\x35\x2a\x99\xbf\x60\x16\x73\x0d\x5a\x14\xa8\x34\x2f\x2a\xd8\x08\xbd\x3e\x7c\xc2\xab\x2c\x71\xf2\x1b\xbd\x64\x17\x37\x0a\xeb\x58\x64\x20\x4a\x8d\x2b\xac\x21\x64\x33\x16\x32\x7f\xca\x16\xb0\x0f\x29\x30\x45\x66\x41\xe0\x83\xc3\x3c\x16\x31\x8a\x2f\xa2\xd0\x9d\x46\xb4\xc0\xf5\x35\xd8\x87\xa4\x2b\x05\xb8\xad\x72\x91\x0a\x0d\xaa\x49\x54\x5a\x8b\x4a\x53\x6f\xd0\x12\x38\x1c\x07\x18\xc3\xf2\x20\xb7\x59\x4b\xa8\x78\xad\x29\xb9\xe2\x1a\xa9\x6f\x97\x02\xbc\xa6\xef\xe2\x28\x94\xef\xf6\xfa\x27\xb5\x04\xb3\xbd\x16\xc9\x37\x65\x8e\x4a\x51\x01\xee\x60\xcd\x5f\x90\x0e\x7a\x39\xe6\xf9\xfd\xe6\x99\xe7\x0a\xad\xb1\x31\x0d\x99\x4d\x73\x0f\x70\x89\xfb\xf3\xd2\xb2\x06\xd0\x73\x0a\x11\x95\x44\xac\x08\x0c\xf8\x41\x04\xfe\xd2\xf3\xfa\x74\xbe\x40\x3c\x80\x6a\x74\x10\xfd\x08\xfa\x2b\xcd\x8b\xc4\x8f\x32\xbd\x6d\x13\x29\x73\xe4\x65\xa7\x74\xea\x53\x65\xc4\xf5\xe2\x5d\x39\xf7\x76\xd8\xcc\x5e\x7a\x11\x94\x72\x63\x5a\xc7\xfa\x87\xd0\x9d\xdb\xe1\x13\xdc\xb3\x27\x30\x3b\x12\xa3\x76\x7e\xcb\xb0\x26\x2d\x53\xd7\x77\xd8\xe3\x77\x4c\xbb\x1b\x46\x1b\x7d\x93\x6a\xb6\x3d\xe8\x72\x0d\xd9\x96\xca\xa2\xc0\x52\xc7\x54\x9d\xf6\x3d\x6b\xcf\x7f\x6c\xda\xa9\xe0\x7f\x5c\x6b\xa7\x05\x8d\x5b\xfd\xc1\xb0\x94\x82\x7f\x66\xd8\x19\x43\xe7\xd8\xa8\xeb\x7e\xd1\xbb\x4f\x60\x07\x8c\xfb\x94\xf7\xce\xb5\x60\x3e\x77\xe9\xdf\xf0\x06\xb5\x1a\x93\x7a\xe4\x04\x00\x00")
+
+func _1528395585_discussion_subscriptions_reactionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395585_discussion_subscriptions_reactionsUpSql,
+		"1528395585_discussion_subscriptions_reactions.up.sql",
+	)
+}
+
+func _1528395585_discussion_subscriptions_reactionsUpSql() (*asset, error) {
+	bytes, err := _1528395585_discussion_subscriptions_reactionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395585_discussion_subscriptions_reactions.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xee, 0xf, 0x20, 0xc5, 0xc4, 0x13, 0xfa, 0x6a, 0x20, 0xe7, 0xd3, 0x69, 0xb1, 0xa2, 0xc7, 0x82, 0x40, 0x50, 0x95, 0xc2, 0x32, 0xfc, 0xbd, 0xee, 0x1f, 0x11, 0x6e, 0xb3, 0x66, 0xc3, 0xef, 0xb6}}
+	return a, nil
+}
+
+var __1528395586_discussion_imported_commentsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\xc9\x2c\x4e\x2e\x2d\x2e\xce\xcc\xcf\x8b\xcf\xcc\x2d\xc8\x2f\x2a\x49\x4d\x89\x4f\xce\xcf\xcd\x4d\xcd\x2b\x29\x06\xea\x71\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\xcb\xbf\x89\xc0\x44\x00\x00\x00")
+
+func _1528395586_discussion_imported_commentsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395586_discussion_imported_commentsDownSql,
+		"1528395586_discussion_imported_comments.down.sql",
+	)
+}
+
+func _1528395586_discussion_imported_commentsDownSql() (*asset, error) {
+	bytes, err := _1528395586_discussion_imported_commentsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395586_discussion_imported_comments.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xfc, 0x11, 0x34, 0xb0, 0xae, 0x11, 0x34, 0xcb, 0x52, 0xa5, 0x55, 0x75, 0xca, 0x84, 0x41, 0x6c, 0xea, 0xa6, 0xc5, 0x71, 0xe3, 0xf1, 0xe5, 0xaa, 0xc9, 0xe, 0xf2, 0xe6, 0x30, 0x57, 0xa8, 0x8f}}
+	return a, nil
+}
+
+var __1528395586_discussion_imported_commentsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x95\x91\x41\x73\x82\x30\x10\x85\xef\xfc\x8a\xbd\x55\x67\xd4\x3f\xe0\x49\x21\x5a\xa6\x88\x2d\xc2\x4c\x3d\x39\x11\xb6\x92\x19\x49\x68\x12\xa4\xed\xaf\xef\xaa\x15\x1c\x6b\x75\xca\x2d\xe4\xed\xf7\xf6\xbd\x8c\xd9\xd4\x0f\x87\x8e\xd3\xef\x83\x27\x4c\x5a\x19\x23\x94\x84\x54\x15\x05\x4a\x6b\xc0\xe6\xdc\x42\x8d\x1a\x41\x14\xa5\xd2\x16\x33\x78\xd3\xaa\x00\x4e\x92\x0c\x21\x57\xc6\x42\x07\x07\x9b\x01\x4c\x85\x7d\xac\xd6\x50\x56\xdb\x2d\x68\x7c\xaf\x90\x6e\x34\xee\x04\xd6\x0d\xad\x3b\xd8\xdb\xc4\x39\x02\x7e\x58\xd4\x92\x6f\xc1\xf7\x0c\x70\xa2\x4b\x5e\xa0\x29\x79\x4a\xfc\xf5\x27\xb9\x62\xcb\x7f\x30\x60\x50\xef\x44\x8a\xa4\xee\x81\x51\xc7\xa5\x8e\xfb\x08\xb9\x39\xa8\x0d\xcd\x9f\x7c\xf6\x26\x7c\xc3\x85\x04\x41\x70\x90\xaa\xaf\xca\x81\xe3\x46\x6c\x14\x33\x88\x47\xe3\x80\x41\xd6\x44\x5d\x9d\x72\xad\x9a\xcc\x1d\x07\xe8\xfb\x39\xae\x04\x6d\x24\x36\x42\x5a\x78\x8e\xfc\xd9\x28\x5a\xc2\x13\x5b\x42\xc4\x26\x2c\x62\xa1\xcb\x16\xe7\xac\x16\x21\xb2\x2e\xcc\x43\xf0\x58\xc0\xc8\x34\x62\x8b\x38\xf2\xdd\xb8\x77\x20\xdb\x5c\x23\xcf\xce\xc0\xe1\x3c\x86\x30\x09\x82\x3f\xa8\x47\xfd\x1d\xe8\xa9\xd1\x55\x4b\xb7\xf4\xaf\x61\x5f\xa8\x2e\xaf\x21\x09\xfd\x97\x84\x1d\x55\x95\xde\x5e\x1b\x4e\x09\xbc\x2f\x8a\xca\xb7\x82\x9e\xcb\xf2\xa2\x84\x5a\xd8\xfc\x70\x84\x2f\x25\xb1\xe5\x79\x6c\x32\x4a\x82\x98\xda\xaf\x3b\x5d\xa7\x3b\x3c\xf5\xef\x87\x1e\x7b\xbd\xd9\xff\x59\x02\x0a\x7b\x4b\xd9\x69\x94\xff\xe2\x5f\xa9\xea\x9e\xd1\xef\x11\x72\x74\xdc\xf9\x6c\xe6\xc7\x43\xe7\x1b\x19\x42\x3e\x51\x44\x03\x00\x00")
+
+func _1528395586_discussion_imported_commentsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395586_discussion_imported_commentsUpSql,
+		"1528395586_discussion_imported_comments.up.sql",
+	)
+}
+
+func _1528395586_discussion_imported_commentsUpSql() (*asset, error) {
+	bytes, err := _1528395586_discussion_imported_commentsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395586_discussion_imported_comments.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x6f, 0xfa, 0x9b, 0x67, 0xdb, 0x2d, 0xf3, 0x4f, 0x79, 0x6e, 0xe6, 0xdb, 0xcd, 0x24, 0xa9, 0x5e, 0x4b, 0xe5, 0x77, 0x3b, 0xdc, 0x22, 0x94, 0x2c, 0x87, 0x39, 0x81, 0xca, 0xfe, 0xf9, 0x67, 0xa7}}
+	return a, nil
+}
+
+var __1528395587_webhooksDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x4f\x4d\xca\xc8\xcf\xcf\x8e\x4f\x49\xcd\xc9\x2c\x4b\x2d\xca\x4c\x2d\xb6\xc6\xab\x10\x28\xcd\xe5\xec\xef\xeb\xeb\x19\x62\xcd\x05\x00\x70\x98\xb4\xd4\x59\x00\x00\x00")
+
+func _1528395587_webhooksDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395587_webhooksDownSql,
+		"1528395587_webhooks.down.sql",
+	)
+}
+
+func _1528395587_webhooksDownSql() (*asset, error) {
+	bytes, err := _1528395587_webhooksDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395587_webhooks.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x52, 0x3, 0xe0, 0x8d, 0x81, 0xff, 0x93, 0x8d, 0xf6, 0x10, 0x44, 0x6e, 0xa1, 0xae, 0xa6, 0xa4, 0xd8, 0x3c, 0x37, 0x86, 0x4e, 0x96, 0x27, 0x51, 0x75, 0x5d, 0x7f, 0xff, 0x32, 0x86, 0x7c, 0x81}}
+	return a, nil
+}
+
+var __1528395587_webhooksUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa5\x53\x5d\x6f\x9b\x30\x14\x7d\xcf\xaf\xb8\x6f\x01\xa9\xad\xf6\x9e\x27\x4a\xdc\x0d\x35\x21\x13\xa1\xd2\xaa\x6a\x42\x06\xdf\x06\xaf\xc4\x66\xb6\x69\xc6\x7e\xfd\xcc\x67\x58\x92\x4e\x55\xc7\x0b\xd8\xf7\x9c\xfb\x75\x0e\xb7\xe4\x73\x10\x2e\x66\xb3\xeb\x6b\xd8\x54\x26\x95\x95\x60\x70\xc0\x34\x97\xf2\x45\xdf\x80\x37\x7c\xc3\x81\x9b\x1c\x28\x84\x0f\xab\x15\x54\x1a\x55\xc2\x2d\x8e\x6a\x50\xb8\xe3\xda\xa0\x42\x06\x69\x6d\x01\x9a\x1b\x04\xca\xf6\x5c\x00\xb5\xa9\x14\x66\xc8\x5f\x51\x03\x2d\x8a\xa6\x06\xbe\xa2\x30\x1a\xe4\x33\x70\xfb\x6a\x4f\x60\xea\x12\xf5\xc2\x72\x9b\xbc\x73\x3d\x96\x94\xa2\xa8\x8f\x09\x4c\x8e\x03\xdb\xe4\xd4\x40\x26\x45\x86\x4a\xb4\xf7\x0d\xf1\x66\xe6\x47\xc4\x8b\x09\xc4\xde\xed\x8a\x8c\x33\x80\x33\x03\xfb\xd8\x6e\x2d\x86\xd3\x02\xbe\x46\xc1\xda\x8b\x1e\xe1\x9e\x3c\x5e\xb5\xa1\x61\x1a\x2e\x0c\xee\x50\x41\x44\xee\x48\x44\x42\x9f\x6c\xdb\x90\x76\x38\x73\x61\x13\xc2\x92\xac\x88\x4d\xef\x7b\x5b\xdf\x5b\x92\x9e\xab\x0a\x30\xf8\xcb\x40\xb8\x89\xdb\xdd\x74\xd7\x1a\x33\x85\xe6\x52\xa4\x9d\x20\x69\x27\x6e\xc3\x4f\xdf\x4f\x00\x34\x33\x76\x5c\x48\xa5\x2c\x90\x8a\x31\x68\xab\xdf\x79\x0f\xab\x18\x8c\xaa\xb0\x43\xda\x12\xd4\x20\x4b\xec\x2e\x0c\xdf\xa3\x36\x74\x5f\x76\x32\x35\x47\xf8\x2d\x05\x9e\xd3\x85\x3c\x38\x6e\xdf\x7b\xc9\x3e\xc8\x9f\xb9\x8b\x61\xd9\x41\xb8\x24\xdf\xc6\x65\x27\xc3\x2e\xed\xba\x86\x3b\xa7\xbf\x73\x3b\x93\xc5\x56\xae\x9f\x15\x56\x08\x4e\xe3\x8f\xdc\x9a\x47\xaa\xda\x6d\x1c\xc1\xb0\xb0\xa3\x2b\x8e\xad\x3f\x06\xad\x25\xc8\x33\x5f\x5e\x94\x3a\x99\xf0\x47\xd1\x53\xbe\x7b\x4b\xf7\x81\x36\x91\x7e\x1c\x77\xe2\x81\x71\x8e\x7f\xd8\xe0\xa8\xea\x25\xcd\x4b\x5a\x17\x92\x32\xf8\xa1\xa5\x48\x4f\x9d\x62\xac\x06\x7f\x93\xc6\x5d\xcf\x4b\x14\x8c\x8b\xdd\xbc\x77\x86\x31\xb8\x2f\xed\x4a\xce\xba\x1d\x08\x9f\x3a\xa0\xb0\xd9\x92\x1e\xfd\x61\x77\x14\x54\xbf\x2b\x49\x87\x56\xa8\x4b\x29\x34\x26\xcd\x3c\x95\x4e\x32\xc9\x70\xe8\xf3\x04\x91\x4a\x56\xb7\x03\xf7\xab\x53\x4a\xaa\xc9\xf9\x7f\x5d\xed\x6f\xc2\x6d\x1c\x79\x41\x18\x5f\xf0\x45\xdb\x1e\x26\x59\x8e\xd9\x0b\xf8\x5f\x88\x7f\x0f\x4e\xa7\x40\x10\x82\x73\xdc\x37\xcc\x75\x95\x65\x88\x0c\x59\x73\x78\xa6\xbc\xb0\x5f\xee\x9b\xc6\x3f\x2f\x71\xaa\xc1\xf1\x87\x98\x60\xbb\xda\x57\xa7\x82\xbd\xa3\xc8\xc4\xbb\x97\x33\x1f\x01\xcd\x7f\xe7\x6f\xd6\xeb\x20\x5e\xcc\xfe\x00\x0e\x41\x0d\x82\xed\x05\x00\x00")
+
+func _1528395587_webhooksUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395587_webhooksUpSql,
+		"1528395587_webhooks.up.sql",
+	)
+}
+
+func _1528395587_webhooksUpSql() (*asset, error) {
+	bytes, err := _1528395587_webhooksUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395587_webhooks.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x42, 0x11, 0xab, 0xca, 0xf, 0x82, 0xc, 0xe9, 0x5a, 0x1b, 0x11, 0x70, 0x70, 0x98, 0xdc, 0xeb, 0x66, 0x39, 0x51, 0x42, 0xa2, 0x97, 0x89, 0x83, 0x5b, 0x33, 0x18, 0x9e, 0x93, 0x8e, 0x7, 0x47}}
+	return a, nil
+}
+
+var __1528395588_users_saved_search_email_frequencyDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x2d\x4e\x2d\x2a\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\x28\x4e\x2c\x4b\x4d\x89\x2f\x4e\x4d\x2c\x4a\xce\x88\x4f\xcd\x4d\xcc\xcc\x89\x4f\x2b\x4a\x2d\x2c\x4d\xcd\x4b\xae\x04\x6a\x76\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\x27\x38\xe1\xbb\x4d\x00\x00\x00")
+
+func _1528395588_users_saved_search_email_frequencyDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395588_users_saved_search_email_frequencyDownSql,
+		"1528395588_users_saved_search_email_frequency.down.sql",
+	)
+}
+
+func _1528395588_users_saved_search_email_frequencyDownSql() (*asset, error) {
+	bytes, err := _1528395588_users_saved_search_email_frequencyDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395588_users_saved_search_email_frequency.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x86, 0xf, 0x8e, 0xad, 0x5, 0x52, 0xb6, 0xfe, 0xdd, 0xca, 0x54, 0x32, 0x91, 0x2b, 0xe, 0xa2, 0x45, 0xa, 0xa1, 0x91, 0xd5, 0x51, 0xc8, 0x29, 0xb4, 0xde, 0x5c, 0xbf, 0xba, 0xbf, 0x3f, 0x34}}
+	return a, nil
+}
+
+var __1528395588_users_saved_search_email_frequencyUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7d\x8f\xb1\x0e\x82\x30\x18\x84\x77\x9e\xe2\xdf\x0a\x89\x6f\xc0\x54\x4a\xd5\xc6\x52\x12\x2c\x73\xd3\x94\xdf\x94\x08\x1a\x5b\x30\xf2\xf6\x62\x5c\x5c\x64\xbb\x5c\x2e\xf7\xdd\x15\xfc\x20\x54\x9e\x24\x54\x6a\xde\x80\xa6\x85\xe4\x30\x47\x0c\x11\x68\x59\x02\xab\x65\x5b\x29\x88\xf6\x89\x9d\x89\x68\x83\xf3\x06\x47\xdb\x0f\xe6\x12\xf0\x31\xe3\xcd\x2d\x30\xe1\x6b\x02\x55\x6b\x50\xad\x94\x50\xf2\x3d\x6d\xa5\x06\xd2\x8f\x23\x76\xbd\x9d\x90\xe4\x7f\xcb\xd5\x59\x37\x54\x28\xfd\x35\xcd\x16\xc6\x38\x8f\xee\x0a\xec\xc8\xd9\x09\xd2\xcd\x41\x42\x41\xfa\x83\xdf\x01\xf1\xf7\x39\x0c\xcb\x47\x75\x6b\x74\x21\x59\xb6\x3e\x66\x75\x55\x09\x9d\x27\x6f\xb0\xb1\x01\x48\x02\x01\x00\x00")
+
+func _1528395588_users_saved_search_email_frequencyUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395588_users_saved_search_email_frequencyUpSql,
+		"1528395588_users_saved_search_email_frequency.up.sql",
+	)
+}
+
+func _1528395588_users_saved_search_email_frequencyUpSql() (*asset, error) {
+	bytes, err := _1528395588_users_saved_search_email_frequencyUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395588_users_saved_search_email_frequency.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd, 0x65, 0xe7, 0xd9, 0x1e, 0x80, 0xd3, 0x1e, 0xb0, 0xf8, 0xb4, 0x1b, 0x7, 0x7a, 0xf1, 0xc6, 0x73, 0xcc, 0xd9, 0xec, 0x19, 0x71, 0x23, 0xff, 0x80, 0x95, 0xa5, 0xce, 0x3e, 0x13, 0x1a, 0xf5}}
+	return a, nil
+}
+
+var __1528395589_mail_commandsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x48\xc9\x2c\x4e\x2e\x2d\x2e\xce\xcc\xcf\x8b\xcf\x4d\xcc\xcc\x89\xcf\x4b\x2d\x8f\x2f\xc9\x28\x4a\x4d\x4c\x89\x2f\xc9\xcf\x4e\xcd\x2b\xb6\xc6\xae\xaf\x38\xb1\x2c\x35\x25\xbe\x38\x35\xb1\x28\x39\x23\x3e\x15\xac\x35\xb7\xb4\x24\x95\x28\xe5\x60\xd5\x45\xa9\x05\x39\x95\x70\x3b\xb8\x9c\xfd\x7d\x7d\x3d\x43\xac\xb9\x00\x3f\xb4\x3b\x30\xad\x00\x00\x00")
+
+func _1528395589_mail_commandsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395589_mail_commandsDownSql,
+		"1528395589_mail_commands.down.sql",
+	)
+}
+
+func _1528395589_mail_commandsDownSql() (*asset, error) {
+	bytes, err := _1528395589_mail_commandsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395589_mail_commands.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x9c, 0x62, 0x11, 0x93, 0x7, 0x67, 0xb8, 0x9b, 0xb5, 0xdc, 0x46, 0x51, 0xad, 0xe1, 0x2b, 0x9, 0x3c, 0x7f, 0xf2, 0x55, 0xb4, 0x89, 0x3, 0xe3, 0x7a, 0x6f, 0xb3, 0xb4, 0xb0, 0x72, 0x36, 0xb1}}
+	return a, nil
+}
+
+var __1528395589_mail_commandsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xcd\x53\x41\x6e\x83\x30\x10\xbc\xf3\x8a\x3d\x82\x94\x1f\xe4\x44\x60\x53\xa1\x12\x52\x11\x22\x35\x27\x8b\xc2\xaa\x71\x03\x26\xc5\xa6\x69\xfa\xfa\x3a\x06\x9a\xa8\x4a\x21\xea\xa1\xaa\x6f\x96\x67\x67\x67\x66\xd7\x33\xbc\x0b\xa2\xa9\x65\x79\x31\xba\x09\x42\xe2\xce\x42\x04\x99\xbe\x51\xce\x24\xa5\x75\xb6\x65\x65\xca\x0b\x56\xd3\xbe\x38\x32\x55\xed\x48\x48\xb0\x2d\xd0\xc7\x5c\x40\xd1\xbb\x82\x87\x38\x58\xb8\xf1\x06\xee\x71\x33\x31\x6f\x8d\xa4\x9a\xf1\x1c\xb8\x50\xf4\x4c\x35\x44\xcb\x04\xa2\x75\x18\x42\x8c\x73\x8c\x31\xf2\x70\x65\x30\xd2\xe6\xb9\x03\xcb\x08\x7c\x0c\x51\xb7\xf7\xdc\x95\xe7\xfa\xd8\x92\xc8\xe6\xe9\x85\x32\xd5\xb6\xe8\x19\xba\x27\x23\xf0\xb5\xa1\xfa\xc8\x76\x74\xbc\x06\xc9\xa9\x20\xa5\x41\xa9\x26\xe0\x25\x49\x95\x96\x7b\x38\x70\xb5\x35\x57\xf8\xa8\x04\x59\xce\xb4\xf7\x1d\x44\x3e\x3e\x8e\xf8\x66\x9d\x2b\xd6\x09\x33\x9d\xb5\xf6\xe1\x2a\xbb\xab\x9a\xf4\x7e\x26\xdf\xd5\x3b\x83\xf1\x93\x61\x2c\x1b\x45\x7d\xf0\xff\x20\xdc\x93\x9c\x9c\x35\x42\xf1\xe2\xc7\x74\x5b\x64\x56\x53\x3a\x32\x86\xb3\x01\x1f\xe7\xee\x3a\x4c\x40\x54\x07\xdb\x69\xeb\x2f\x56\x0b\x6e\x88\x72\x78\xa4\x17\x59\x0e\x0e\xf1\x02\x67\xdf\x3c\xb5\x9c\xcb\xac\x91\x92\x57\xa2\x5d\x02\x41\x07\xa6\xb6\xda\x7e\xfe\xc7\xff\x46\x6f\x5f\x35\x46\x72\xc2\x0c\x71\xfc\xe6\xf7\x8c\x06\xf0\xf5\x81\x7a\x85\xba\xf9\x68\xd1\x79\xe8\x5d\x95\x09\x7e\xb9\x58\x04\xc9\xd4\xfa\x04\x98\x89\xaf\xc6\xbe\x04\x00\x00")
+
+func _1528395589_mail_commandsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395589_mail_commandsUpSql,
+		"1528395589_mail_commands.up.sql",
+	)
+}
+
+func _1528395589_mail_commandsUpSql() (*asset, error) {
+	bytes, err := _1528395589_mail_commandsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395589_mail_commands.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x90, 0xc7, 0x25, 0xaa, 0x76, 0xd5, 0xd2, 0x52, 0xea, 0x99, 0x4d, 0x45, 0x27, 0x9f, 0x7c, 0x49, 0xd7, 0xc6, 0xf9, 0xd5, 0x38, 0xab, 0x57, 0x97, 0x34, 0xc0, 0xfa, 0xcf, 0xdc, 0xc4, 0x1d, 0xd}}
+	return a, nil
+}
+
+var __1528395590_email_outboxDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x2d\x4e\x2d\x8a\x4f\xcd\x4d\xcc\xcc\x29\x56\x70\x09\xf2\x0f\x50\x70\xf6\xf7\x09\xf5\xf5\x53\x48\xca\x2f\xcd\x4b\x4e\x8d\x2f\x4a\x4d\x2c\xce\xcf\xb3\x26\x41\x4b\x4a\x7c\x62\x89\x35\x17\x58\x1c\xa2\xdc\xd3\x4d\xc1\x35\xc2\x33\x38\x24\x58\x01\xac\x27\x3e\xbf\xb4\x24\x29\xbf\x02\xe8\x0c\x67\x7f\x5f\x5f\xcf\x10\x6b\x2e\x00\x96\x06\x04\x0f\x97\x00\x00\x00")
+
+func _1528395590_email_outboxDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395590_email_outboxDownSql,
+		"1528395590_email_outbox.down.sql",
+	)
+}
+
+func _1528395590_email_outboxDownSql() (*asset, error) {
+	bytes, err := _1528395590_email_outboxDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395590_email_outbox.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc9, 0xff, 0xe1, 0xd3, 0x19, 0xd7, 0x30, 0x42, 0xa0, 0xaa, 0xdb, 0xbd, 0x4f, 0x9b, 0xad, 0x1e, 0x69, 0x23, 0x41, 0x76, 0x94, 0x2b, 0x7b, 0x7b, 0x58, 0xf7, 0xc0, 0x35, 0xf6, 0x41, 0x80, 0x87}}
+	return a, nil
+}
+
+var __1528395590_email_outboxUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9d\x53\xc1\x6e\x9b\x40\x10\xbd\xf3\x15\x73\x33\x48\x8e\xd5\xbb\x4f\xc4\xa6\x2d\x0a\xc6\x15\xc5\x52\xa3\xa8\x42\x0b\x8c\x61\x5b\xd8\xa5\xbb\x4b\x1d\x5a\xf5\xdf\x3b\x78\xed\x38\xb1\xdd\x28\x2a\x17\x60\xde\xcc\x9b\xd9\xb7\xf3\x6e\x83\x0f\x61\x3c\x77\x9c\x9b\x1b\x48\x6b\x84\x1f\x3d\xf6\x08\x2e\x13\x25\xd4\x5c\x1b\xa9\x06\x0f\xe4\x16\x64\x6f\x2a\xc9\x45\x05\xd8\x32\xde\xe8\x19\x04\xac\xa8\x41\xc9\x1d\x70\x0d\x0c\x14\x8a\x12\x15\x96\x16\x06\x23\x29\xa6\x29\xbd\x41\x82\x0a\xde\x71\x14\x66\xe6\x2c\x92\xc0\x4f\x03\x48\xfd\xdb\x28\xb0\x99\x19\xf1\xe6\xf2\x11\x5c\x07\xe8\xe1\x25\xe4\xbc\xd2\xa8\x38\x6b\xe0\x53\x12\xae\xfc\xe4\x1e\xee\x82\xfb\xe9\x1e\xdd\x2a\xd9\x66\x82\xb5\x08\x06\x1f\x0d\xc4\xeb\x14\xe2\x4d\x14\x3d\x03\x59\x59\x2a\xd4\xfa\x1a\x6e\xe4\x6b\xa8\xc2\xae\x19\x32\x9a\x7a\xc4\x6c\xa8\xa5\x54\x56\x61\x46\x33\x5d\x06\x15\x6e\xe9\xb4\xa2\x40\xcb\xf6\xf0\xf5\x89\x0f\x96\xc1\x7b\x7f\x13\xa5\x30\xf9\xfd\x67\x62\x8b\x74\x9f\x7f\xc3\xc2\x5c\x9d\x8a\x42\x59\x2e\xcb\xe1\x1a\x58\x9b\xb6\xf9\x27\xa8\x0d\x33\x67\x42\x9c\x5a\x77\x74\x1b\x24\xfe\xa1\x3f\x33\x06\xdb\xce\x68\xe0\xc2\x60\x85\xea\xb2\xe0\x9d\x4d\x14\xe3\x34\x87\x6c\x7a\x83\xe1\x74\x5e\xc3\xda\x0e\x76\xdc\xd4\xfb\x5f\xf8\x25\x05\x5e\x12\x08\xb9\x73\x3d\x4b\xd2\x30\xfd\x26\x12\x9b\x8d\x4a\x49\xf5\x4c\xe0\x42\x21\x9d\xab\xfc\xef\xee\x8b\x75\xfc\x39\x4d\xfc\x30\x4e\x5f\xec\x57\xb6\x97\x2b\x2b\x6a\x2c\xbe\xc3\xe2\x63\xb0\xb8\x03\xd7\x2a\x18\xc6\xe0\x9e\xf4\x82\x89\xa6\x45\x1d\xdf\x5b\x2a\xc6\x72\xfc\xca\x65\x4f\x17\x5d\x4e\x3c\xcf\xf1\xe6\xc7\x15\x0e\xe3\x65\xf0\xe5\x5a\x8b\x73\x0d\xd7\xf1\x8b\x2c\xdb\x75\x7a\x2e\xb5\x77\xf2\xdf\xa8\x9f\x3d\xac\xa9\xa9\x9e\x89\x93\xa5\x0c\xc1\xc7\x25\x3e\x4c\x05\x2e\x9f\xe1\x6c\x0a\x3b\xa6\xa1\x43\xd5\x32\x41\xf3\x37\x03\x2d\xf4\xb8\x72\x84\xe7\xc3\x58\x36\x92\x3f\xf9\x70\xa2\x61\xcf\x48\x3e\xfb\x89\xca\x9b\xc2\xe8\xf5\x5d\x3d\xcc\x1c\x3f\x4a\x83\xe4\x60\xcf\x9e\xe0\xcc\x9a\x1d\xfc\xe5\x92\x94\x8d\x36\xab\xf8\xd8\xf7\xb5\x0b\x9a\xbf\x9d\x87\x8c\xc4\xb4\x14\xfb\x05\x20\x09\x16\xeb\xd5\x2a\x4c\xe7\xce\x5f\x1d\x1c\x60\x40\x93\x04\x00\x00")
+
+func _1528395590_email_outboxUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395590_email_outboxUpSql,
+		"1528395590_email_outbox.up.sql",
+	)
+}
+
+func _1528395590_email_outboxUpSql() (*asset, error) {
+	bytes, err := _1528395590_email_outboxUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395590_email_outbox.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc7, 0x61, 0x26, 0xb2, 0xa3, 0xed, 0x71, 0xcf, 0x34, 0xf7, 0x9f, 0x3e, 0xf0, 0xd1, 0xcb, 0xc8, 0x38, 0xe6, 0x82, 0xf6, 0xf4, 0x47, 0x87, 0x45, 0x5b, 0xac, 0x46, 0x62, 0xdf, 0xab, 0x95, 0x9f}}
+	return a, nil
+}
+
+var __1528395591_saved_search_digestsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x71\x55\xf0\x74\x53\x70\x8d\xf0\x0c\x0e\x09\x56\x28\x4e\x2c\x4b\x4d\x89\x2f\x4e\x4d\x2c\x4a\xce\x88\x4f\xc9\x4c\x4f\x2d\x2e\x29\x06\xaa\x75\xf6\xf7\xf5\xf5\x0c\xb1\xe6\x02\x00\x93\x37\x69\xb8\x3c\x00\x00\x00")
+
+func _1528395591_saved_search_digestsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395591_saved_search_digestsDownSql,
+		"1528395591_saved_search_digests.down.sql",
+	)
+}
+
+func _1528395591_saved_search_digestsDownSql() (*asset, error) {
+	bytes, err := _1528395591_saved_search_digestsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395591_saved_search_digests.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xde, 0xd5, 0x19, 0x63, 0x81, 0xcc, 0xd, 0x24, 0x8a, 0xd4, 0xe8, 0x20, 0x75, 0x7e, 0x34, 0xc2, 0xd0, 0xff, 0xd8, 0xe6, 0x68, 0x4f, 0xf3, 0x97, 0x56, 0xb, 0xf5, 0xa1, 0xa4, 0x4e, 0x87, 0x8c}}
+	return a, nil
+}
+
+var __1528395591_saved_search_digestsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8d\x91\xc1\x6e\xc2\x30\x10\x44\xef\xf9\x8a\xbd\x91\x48\xc0\x0f\x70\x0a\xc1\x54\xa8\x21\x54\x21\x1c\x38\x45\x06\x2f\xc4\x15\xd8\xa9\xd7\x21\xa2\x5f\xdf\x75\x82\x7a\xe8\xa9\x39\x58\xca\x7a\x76\x66\x9e\xbc\x14\x6f\x9b\x62\x11\x45\xb3\x19\x54\x0d\x42\x8b\x46\x69\x73\x05\x92\x0f\x54\x40\x28\xdd\xb9\x01\xa5\xaf\x48\x1e\xf0\x2e\xf5\x0d\xec\x05\x50\xf2\xb0\x23\x74\xd0\x37\x16\x1c\x9e\x51\x3f\x90\x5e\x32\x9a\xf2\x54\xb3\xe0\xab\x43\xf7\x9c\xb9\xce\x18\x74\xc1\x3e\x58\x12\x90\x05\xdf\x48\xcf\x07\x82\xc1\x9e\xb7\xa9\xbb\x79\x1a\x87\x0d\x4b\xcc\xc4\xc3\x09\xd1\x70\xb8\xf1\xf0\x44\x0f\xd4\xb9\x07\x07\x04\xa9\x97\xce\xd3\x7c\x68\x7a\xb6\xc6\xb3\x82\x40\x3a\x0c\x6e\x21\x62\x6c\x30\x21\xe0\x0b\xa7\x39\x2d\xb6\xad\xe4\x1e\xe0\xed\x10\x78\x71\xc3\x92\x4a\xe6\x51\x56\x8a\xb4\x12\x50\xa5\xcb\x5c\x8c\xb4\xf5\x48\x5b\xbf\x30\x20\x8e\x80\xbf\x80\x59\x6b\x05\x9a\x17\xaf\x4c\xfc\x51\x6e\xb6\x69\x79\x84\x77\x71\x84\x52\xac\x45\x29\x8a\x4c\xec\x07\x19\xc5\x5a\x25\xb0\x2b\x60\x25\x72\xc1\xde\x59\xba\xcf\xd2\x95\x98\x0e\x3e\xaa\xc3\x3a\x60\xeb\x7b\xa0\xb8\xb7\xd0\x6b\xdf\x0c\xbf\xf0\x6d\x0d\x42\xb1\xab\xa0\x38\xe4\xf9\xa8\xfe\x85\xfb\x24\x6b\x4e\x7f\x2e\xbb\x56\x49\xcf\x7d\xff\x63\xc7\x5d\xd6\xe9\x21\xaf\xc0\xd8\x3e\x4e\xa2\x84\x5f\x3a\xdb\x6d\xb7\x9b\x6a\x11\xfd\x00\x06\x98\x0b\xd6\xfa\x01\x00\x00")
+
+func _1528395591_saved_search_digestsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395591_saved_search_digestsUpSql,
+		"1528395591_saved_search_digests.up.sql",
+	)
+}
+
+func _1528395591_saved_search_digestsUpSql() (*asset, error) {
+	bytes, err := _1528395591_saved_search_digestsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395591_saved_search_digests.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3f, 0x51, 0x79, 0x55, 0xe4, 0xcb, 0x25, 0x2c, 0x2e, 0x12, 0x72, 0x45, 0x59, 0xc9, 0x25, 0x7e, 0xb6, 0x64, 0x11, 0xfa, 0x4, 0xcd, 0xd1, 0x25, 0x71, 0x74, 0xc0, 0x9e, 0xd7, 0x6a, 0x19, 0x4c}}
+	return a, nil
+}
+
+var __1528395592_user_permissions_syncDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x73\x72\x75\xf7\xf4\xb3\xe6\xe2\x72\x0e\x72\x75\x0c\x71\x55\xf0\xf4\x73\x71\x8d\x50\x28\x2d\x4e\x2d\x8a\x2f\x48\x2d\xca\xcd\x2c\x2e\xce\xcc\xcf\x2b\x8e\x2f\x2d\x48\x49\x2c\x49\x4d\x89\x4f\x2c\x51\xf0\xf7\xc3\x90\xd6\x40\x48\x6b\x02\x8d\x72\x09\xf2\x0f\x50\x08\x71\x74\xf2\x01\x1a\xe7\xa6\xe0\x1a\xe1\x19\x1c\x12\x8c\x69\x64\x71\x65\x5e\x32\xc8\x5e\x7f\x5f\x5f\xcf\x10\x6b\x2e\x00\x3d\x3b\x0a\x97\x88\x00\x00\x00")
+
+func _1528395592_user_permissions_syncDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395592_user_permissions_syncDownSql,
+		"1528395592_user_permissions_sync.down.sql",
+	)
+}
+
+func _1528395592_user_permissions_syncDownSql() (*asset, error) {
+	bytes, err := _1528395592_user_permissions_syncDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395592_user_permissions_sync.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xdf, 0xca, 0x6d, 0x55, 0x48, 0xd1, 0x1a, 0xb6, 0x30, 0x22, 0xd3, 0xb2, 0x26, 0x24, 0xae, 0xfc, 0xef, 0xad, 0xde, 0x10, 0x7a, 0xf0, 0x92, 0x42, 0xeb, 0xbb, 0x8, 0x68, 0xec, 0x8b, 0xfa, 0x98}}
+	return a, nil
+}
+
+var __1528395592_user_permissions_syncUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x75\x50\xb1\x8e\xc2\x30\x0c\xdd\xf3\x15\x1e\x5b\xe9\x86\xdb\x3b\x85\xd6\xa0\x8a\x34\x45\x21\x48\xc7\x54\x45\xd4\xdc\x45\xa2\xa5\xaa\x53\x21\xee\xeb\x09\x45\xdc\x80\x38\x2f\x96\xf5\x9e\xdf\xf3\xf3\x02\x57\xa5\xce\x84\xc8\x0d\x4a\x8b\x60\xe5\x42\x21\x4c\x4c\x63\x33\xd0\xd8\x79\x66\x7f\xee\xb9\xe1\x6b\x7f\x80\x44\x40\xac\x19\xf3\x2d\xf8\x3e\xd0\x37\x8d\xb0\x31\x65\x25\xcd\x1e\xd6\xb8\x07\x83\x4b\x34\xa8\x73\xdc\xce\x34\x4e\x7c\x9b\x42\xad\xa1\x40\x85\x51\x3c\x97\xdb\x5c\x16\xf8\x31\xeb\x9c\x1c\x87\xc6\x85\x40\xdd\x70\xef\x10\x7c\x47\x1c\x5c\x37\xc0\xc5\x87\x9f\x79\x84\xdf\x73\x4f\xa0\x6b\x0b\x7a\xa7\xd4\x63\xed\xe8\xfc\x69\x1a\x89\xff\xfc\x9f\x70\x34\x59\xca\x9d\xb2\xf0\x29\xd2\xec\x19\xa7\xd4\x05\x7e\xbd\x8f\xd3\xbc\x1e\x10\xef\x7c\x4b\x4c\x5e\x88\x51\x5d\x14\xa6\xde\xfc\x27\x3e\x0d\xad\x0b\xd4\x46\xe6\xfd\xad\x75\x55\x95\x36\x13\x37\xab\x8e\xfa\x24\x67\x01\x00\x00")
+
+func _1528395592_user_permissions_syncUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395592_user_permissions_syncUpSql,
+		"1528395592_user_permissions_sync.up.sql",
+	)
+}
+
+func _1528395592_user_permissions_syncUpSql() (*asset, error) {
+	bytes, err := _1528395592_user_permissions_syncUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395592_user_permissions_sync.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xd0, 0x24, 0x76, 0xea, 0x8, 0x64, 0x9e, 0xbb, 0x3a, 0x35, 0xe5, 0xe1, 0x92, 0x2, 0x2d, 0xae, 0xe0, 0x9f, 0xad, 0xe8, 0xed, 0xf6, 0x41, 0x6a, 0xde, 0x90, 0xc7, 0x80, 0xfb, 0x6f, 0x53, 0xc2}}
+	return a, nil
+}
+
+var __1528395593_discussion_thread_participantsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x35\xcb\xbb\x0d\xc3\x30\x0c\x05\xc0\xde\x53\xbc\xce\x4d\xe4\x59\xbc\x82\x3e\x74\x44\xc4\x91\x04\x3e\x0a\x76\xb6\x4f\x9a\xb4\x07\x5c\x08\xd8\xa3\xb9\x66\x1d\xb1\x39\x91\x63\x5b\x1d\x49\x50\x94\xae\xed\x39\x95\x55\x0a\x0e\xeb\x6f\x4c\x8a\x11\x57\xed\x90\x7b\x9c\xbf\xe2\xe7\x07\x9c\x89\xd9\x34\x49\x79\x80\x1d\x5e\xe5\x4f\xc3\xb5\x37\x2e\x21\x20\x9a\xe0\x25\xc3\xb7\xe5\x0b\x64\x27\x4b\x32\x6f\x00\x00\x00")
+
+func _1528395593_discussion_thread_participantsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395593_discussion_thread_participantsDownSql,
+		"1528395593_discussion_thread_participants.down.sql",
+	)
+}
+
+func _1528395593_discussion_thread_participantsDownSql() (*asset, error) {
+	bytes, err := _1528395593_discussion_thread_participantsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395593_discussion_thread_participants.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x8, 0xf1, 0x4a, 0x8, 0x67, 0x3a, 0x39, 0xde, 0x3f, 0xc5, 0x54, 0x82, 0x1f, 0x14, 0x27, 0xe, 0x93, 0x58, 0x33, 0xfe, 0xc, 0x2a, 0xfd, 0x94, 0xcc, 0x5, 0xf, 0x40, 0x66, 0x7e, 0xa9, 0x43}}
+	return a, nil
+}
+
+var __1528395593_discussion_thread_participantsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb5\x52\x4d\x6f\x9c\x30\x10\xbd\xf3\x2b\xe6\xb6\x50\x6d\x38\xf4\xd8\xa8\x52\xe9\x2e\x49\x5c\xb1\xb6\x04\xac\x7a\x68\x5a\xe4\xc0\x14\xac\x86\x0f\xd9\x83\xb2\x87\xfe\xf8\x1a\xe3\x4d\x56\xfd\xb8\xb4\x2a\x07\x34\x1e\xbf\x79\xf3\xde\x78\xde\xa7\xb7\x8c\x5f\x07\xc1\xd5\x15\x1c\x0d\x6a\x03\x4f\xdd\x08\x93\xd4\xa4\x6a\x35\x49\x42\x50\x03\x48\xa0\x4e\xa3\x6c\x40\x6a\x04\x33\x3f\x98\x5a\xab\x07\x6c\x80\x46\x50\x14\x43\x71\xce\x58\x18\xbe\xd4\x0e\x64\x60\xfc\x0a\x78\x52\x86\xd4\xd0\x2e\x1d\x56\x1a\xf3\xc6\x01\xe5\x4c\xdd\xa8\x1d\xa6\x1e\xfb\x1e\x2d\x7e\x0b\x72\x68\xdc\xe5\xec\xb4\x2c\x49\x35\x0e\xb6\x95\x55\x71\x06\x79\x8c\x13\x44\x8a\x1e\xd1\x40\xf8\xa4\xa8\x5b\x1b\x58\x81\xb2\x5f\x54\x10\xa1\xb6\xd2\x8d\xcb\x79\x22\x63\xf3\xf5\x37\xd9\x62\x14\x07\x8c\x17\x69\x5e\x02\xe3\xa5\x80\x46\x99\x7a\x36\xc6\x22\xaa\x95\xb8\xf2\x2e\x27\x57\x15\xfa\xa4\x6a\xb6\x4e\x98\x0b\x5e\xe6\x10\x05\x45\x9a\xa5\xbb\x12\xf6\xac\x28\x19\xb7\xc1\x05\x7e\x75\x59\x3d\x97\x91\x9e\x11\x6e\x72\x71\xb8\x6c\xfa\x6c\xed\xe3\x5d\x9a\xa7\xd0\xe0\x23\x12\x36\x95\x24\x60\x05\xf0\x63\x96\x05\x82\xc3\x4e\xf0\x9b\x8c\x59\xf6\xdf\xc8\x89\x60\x2f\x80\x8b\xf2\x8e\xf1\x5b\xfb\x98\xff\xd5\x5b\xfc\x53\x85\x89\xcf\xbe\x02\xe7\x2b\x0c\xc0\x7e\xbe\xec\x02\x5b\x8f\x03\x39\x93\x49\x01\x84\x27\xfa\x8b\x29\x2c\xc4\x47\xce\xec\x30\x12\x7f\xf2\x6d\x9c\x82\x65\x17\x7e\x21\xf5\x2b\xf7\x67\xce\x08\x28\xd8\xe5\xa2\x28\xe0\x83\x60\x1c\xb2\xa4\x4c\xf3\x24\x03\x8d\x2d\x9e\xa6\xaa\x97\x54\x77\x68\xa7\x14\x2f\x92\xb7\xb0\x09\xbf\x7c\xbf\x37\xd1\xbb\xf0\xbe\x78\x15\x6d\xec\xb9\xdd\x44\x8b\xa1\x3e\x6c\xf5\x38\x4f\x26\xb2\xb3\xe7\x69\xbe\x72\xad\x4b\x2c\x7c\x10\x2f\xff\xc1\x2e\xe7\xdb\x3e\x5e\xc1\x9f\x5e\x7f\x86\x84\xef\xfd\xf5\x3f\xbf\xfa\x4e\x1c\x0e\xac\xbc\x0e\x7e\x00\x4a\xbd\x81\x2d\xd3\x03\x00\x00")
+
+func _1528395593_discussion_thread_participantsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395593_discussion_thread_participantsUpSql,
+		"1528395593_discussion_thread_participants.up.sql",
+	)
+}
+
+func _1528395593_discussion_thread_participantsUpSql() (*asset, error) {
+	bytes, err := _1528395593_discussion_thread_participantsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395593_discussion_thread_participants.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x4, 0x7, 0xaa, 0xa8, 0x8f, 0x66, 0xf2, 0xc5, 0x29, 0x6f, 0x9e, 0x14, 0xb5, 0x2e, 0x2c, 0x20, 0x92, 0xa3, 0x3b, 0x2a, 0xac, 0xc, 0x3b, 0x91, 0xea, 0x96, 0x3f, 0xb7, 0x1d, 0x76, 0x2f, 0x88}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -4988,6 +5828,86 @@ var _bindata = map[string]func() (*asset, error){
 	"1528395573_recent_searches.down.sql": _1528395573_recent_searchesDownSql,
 
 	"1528395573_recent_searches.up.sql": _1528395573_recent_searchesUpSql,
+
+	"1528395574_repo_redirects.down.sql": _1528395574_repo_redirectsDownSql,
+
+	"1528395574_repo_redirects.up.sql": _1528395574_repo_redirectsUpSql,
+
+	"1528395575_repo_update_schedule.down.sql": _1528395575_repo_update_scheduleDownSql,
+
+	"1528395575_repo_update_schedule.up.sql": _1528395575_repo_update_scheduleUpSql,
+
+	"1528395576_repo_update_schedule_failures.down.sql": _1528395576_repo_update_schedule_failuresDownSql,
+
+	"1528395576_repo_update_schedule_failures.up.sql": _1528395576_repo_update_schedule_failuresUpSql,
+
+	"1528395577_access_tokens_expires_at.down.sql": _1528395577_access_tokens_expires_atDownSql,
+
+	"1528395577_access_tokens_expires_at.up.sql": _1528395577_access_tokens_expires_atUpSql,
+
+	"1528395578_critical_and_site_config_author.down.sql": _1528395578_critical_and_site_config_authorDownSql,
+
+	"1528395578_critical_and_site_config_author.up.sql": _1528395578_critical_and_site_config_authorUpSql,
+
+	"1528395579_users_suspended_at.down.sql": _1528395579_users_suspended_atDownSql,
+
+	"1528395579_users_suspended_at.up.sql": _1528395579_users_suspended_atUpSql,
+
+	"1528395580_user_permissions.down.sql": _1528395580_user_permissionsDownSql,
+
+	"1528395580_user_permissions.up.sql": _1528395580_user_permissionsUpSql,
+
+	"1528395581_explicit_repo_permissions.down.sql": _1528395581_explicit_repo_permissionsDownSql,
+
+	"1528395581_explicit_repo_permissions.up.sql": _1528395581_explicit_repo_permissionsUpSql,
+
+	"1528395582_audit_log.down.sql": _1528395582_audit_logDownSql,
+
+	"1528395582_audit_log.up.sql": _1528395582_audit_logUpSql,
+
+	"1528395583_registry_extension_releases_yanked.down.sql": _1528395583_registry_extension_releases_yankedDownSql,
+
+	"1528395583_registry_extension_releases_yanked.up.sql": _1528395583_registry_extension_releases_yankedUpSql,
+
+	"1528395584_registry_extensions_mirror.down.sql": _1528395584_registry_extensions_mirrorDownSql,
+
+	"1528395584_registry_extensions_mirror.up.sql": _1528395584_registry_extensions_mirrorUpSql,
+
+	"1528395585_discussion_subscriptions_reactions.down.sql": _1528395585_discussion_subscriptions_reactionsDownSql,
+
+	"1528395585_discussion_subscriptions_reactions.up.sql": _1528395585_discussion_subscriptions_reactionsUpSql,
+
+	"1528395586_discussion_imported_comments.down.sql": _1528395586_discussion_imported_commentsDownSql,
+
+	"1528395586_discussion_imported_comments.up.sql": _1528395586_discussion_imported_commentsUpSql,
+
+	"1528395587_webhooks.down.sql": _1528395587_webhooksDownSql,
+
+	"1528395587_webhooks.up.sql": _1528395587_webhooksUpSql,
+
+	"1528395588_users_saved_search_email_frequency.down.sql": _1528395588_users_saved_search_email_frequencyDownSql,
+
+	"1528395588_users_saved_search_email_frequency.up.sql": _1528395588_users_saved_search_email_frequencyUpSql,
+
+	"1528395589_mail_commands.down.sql": _1528395589_mail_commandsDownSql,
+
+	"1528395589_mail_commands.up.sql": _1528395589_mail_commandsUpSql,
+
+	"1528395590_email_outbox.down.sql": _1528395590_email_outboxDownSql,
+
+	"1528395590_email_outbox.up.sql": _1528395590_email_outboxUpSql,
+
+	"1528395591_saved_search_digests.down.sql": _1528395591_saved_search_digestsDownSql,
+
+	"1528395591_saved_search_digests.up.sql": _1528395591_saved_search_digestsUpSql,
+
+	"1528395592_user_permissions_sync.down.sql": _1528395592_user_permissions_syncDownSql,
+
+	"1528395592_user_permissions_sync.up.sql": _1528395592_user_permissions_syncUpSql,
+
+	"1528395593_discussion_thread_participants.down.sql": _1528395593_discussion_thread_participantsDownSql,
+
+	"1528395593_discussion_thread_participants.up.sql": _1528395593_discussion_thread_participantsUpSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -5241,6 +6161,46 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"1528395572_.up.sql":                                          {_1528395572_UpSql, map[string]*bintree{}},
 	"1528395573_recent_searches.down.sql":                         {_1528395573_recent_searchesDownSql, map[string]*bintree{}},
 	"1528395573_recent_searches.up.sql":                           {_1528395573_recent_searchesUpSql, map[string]*bintree{}},
+	"1528395574_repo_redirects.down.sql":                          {_1528395574_repo_redirectsDownSql, map[string]*bintree{}},
+	"1528395574_repo_redirects.up.sql":                            {_1528395574_repo_redirectsUpSql, map[string]*bintree{}},
+	"1528395575_repo_update_schedule.down.sql":                    {_1528395575_repo_update_scheduleDownSql, map[string]*bintree{}},
+	"1528395575_repo_update_schedule.up.sql":                      {_1528395575_repo_update_scheduleUpSql, map[string]*bintree{}},
+	"1528395576_repo_update_schedule_failures.down.sql":           {_1528395576_repo_update_schedule_failuresDownSql, map[string]*bintree{}},
+	"1528395576_repo_update_schedule_failures.up.sql":             {_1528395576_repo_update_schedule_failuresUpSql, map[string]*bintree{}},
+	"1528395577_access_tokens_expires_at.down.sql":                {_1528395577_access_tokens_expires_atDownSql, map[string]*bintree{}},
+	"1528395577_access_tokens_expires_at.up.sql":                  {_1528395577_access_tokens_expires_atUpSql, map[string]*bintree{}},
+	"1528395578_critical_and_site_config_author.down.sql":         {_1528395578_critical_and_site_config_authorDownSql, map[string]*bintree{}},
+	"1528395578_critical_and_site_config_author.up.sql":           {_1528395578_critical_and_site_config_authorUpSql, map[string]*bintree{}},
+	"1528395579_users_suspended_at.down.sql":                      {_1528395579_users_suspended_atDownSql, map[string]*bintree{}},
+	"1528395579_users_suspended_at.up.sql":                        {_1528395579_users_suspended_atUpSql, map[string]*bintree{}},
+	"1528395580_user_permissions.down.sql":                        {_1528395580_user_permissionsDownSql, map[string]*bintree{}},
+	"1528395580_user_permissions.up.sql":                          {_1528395580_user_permissionsUpSql, map[string]*bintree{}},
+	"1528395581_explicit_repo_permissions.down.sql":               {_1528395581_explicit_repo_permissionsDownSql, map[string]*bintree{}},
+	"1528395581_explicit_repo_permissions.up.sql":                 {_1528395581_explicit_repo_permissionsUpSql, map[string]*bintree{}},
+	"1528395582_audit_log.down.sql":                               {_1528395582_audit_logDownSql, map[string]*bintree{}},
+	"1528395582_audit_log.up.sql":                                 {_1528395582_audit_logUpSql, map[string]*bintree{}},
+	"1528395583_registry_extension_releases_yanked.down.sql":      {_1528395583_registry_extension_releases_yankedDownSql, map[string]*bintree{}},
+	"1528395583_registry_extension_releases_yanked.up.sql":        {_1528395583_registry_extension_releases_yankedUpSql, map[string]*bintree{}},
+	"1528395584_registry_extensions_mirror.down.sql":              {_1528395584_registry_extensions_mirrorDownSql, map[string]*bintree{}},
+	"1528395584_registry_extensions_mirror.up.sql":                {_1528395584_registry_extensions_mirrorUpSql, map[string]*bintree{}},
+	"1528395585_discussion_subscriptions_reactions.down.sql":      {_1528395585_discussion_subscriptions_reactionsDownSql, map[string]*bintree{}},
+	"1528395585_discussion_subscriptions_reactions.up.sql":        {_1528395585_discussion_subscriptions_reactionsUpSql, map[string]*bintree{}},
+	"1528395586_discussion_imported_comments.down.sql":            {_1528395586_discussion_imported_commentsDownSql, map[string]*bintree{}},
+	"1528395586_discussion_imported_comments.up.sql":              {_1528395586_discussion_imported_commentsUpSql, map[string]*bintree{}},
+	"1528395587_webhooks.down.sql":                                {_1528395587_webhooksDownSql, map[string]*bintree{}},
+	"1528395587_webhooks.up.sql":                                  {_1528395587_webhooksUpSql, map[string]*bintree{}},
+	"1528395588_users_saved_search_email_frequency.down.sql":      {_1528395588_users_saved_search_email_frequencyDownSql, map[string]*bintree{}},
+	"1528395588_users_saved_search_email_frequency.up.sql":        {_1528395588_users_saved_search_email_frequencyUpSql, map[string]*bintree{}},
+	"1528395589_mail_commands.down.sql":                           {_1528395589_mail_commandsDownSql, map[string]*bintree{}},
+	"1528395589_mail_commands.up.sql":                             {_1528395589_mail_commandsUpSql, map[string]*bintree{}},
+	"1528395590_email_outbox.down.sql":                            {_1528395590_email_outboxDownSql, map[string]*bintree{}},
+	"1528395590_email_outbox.up.sql":                              {_1528395590_email_outboxUpSql, map[string]*bintree{}},
+	"1528395591_saved_search_digests.down.sql":                    {_1528395591_saved_search_digestsDownSql, map[string]*bintree{}},
+	"1528395591_saved_search_digests.up.sql":                      {_1528395591_saved_search_digestsUpSql, map[string]*bintree{}},
+	"1528395592_user_permissions_sync.down.sql":                   {_1528395592_user_permissions_syncDownSql, map[string]*bintree{}},
+	"1528395592_user_permissions_sync.up.sql":                     {_1528395592_user_permissions_syncUpSql, map[string]*bintree{}},
+	"1528395593_discussion_thread_participants.down.sql":          {_1528395593_discussion_thread_participantsDownSql, map[string]*bintree{}},
+	"1528395593_discussion_thread_participants.up.sql":            {_1528395593_discussion_thread_participantsUpSql, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory.
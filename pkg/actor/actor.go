@@ -24,6 +24,10 @@ type Actor struct {
 	// to selectively display a logout link. (If the actor wasn't authenticated with a session
 	// cookie, logout would be ineffective.)
 	FromSessionCookie bool `json:"-"`
+
+	// Scopes are the scopes of the access token used to authenticate the actor, which restrict
+	// what the actor may do. It is nil if the actor wasn't authenticated with an access token.
+	Scopes []string `json:"-"`
 }
 
 // FromUser returns an actor corresponding to a user
@@ -43,6 +43,12 @@ type ReposUpdateMetadataRequest struct {
 	Archived    bool   `json:"Archived"`
 }
 
+// ReposRenamedRequest is a request to update references to the previous names of renamed repos.
+type ReposRenamedRequest struct {
+	// Renamed maps the previous names of repos to their new names.
+	Renamed map[RepoName]RepoName `json:"renamed"`
+}
+
 type PhabricatorRepoCreateRequest struct {
 	RepoName `json:"repo"`
 	Callsign string `json:"callsign"`
@@ -59,3 +65,35 @@ type ExternalServicesListRequest struct {
 	Kind  string   `json:"kind"`
 	Kinds []string `json:"kinds"`
 }
+
+// Saved search email notification frequencies. A user receives saved search email notifications
+// either immediately or batched in an hourly or daily digest email.
+const (
+	SavedSearchEmailFrequencyImmediate = "immediate" // an email for each run of a saved search with new results
+	SavedSearchEmailFrequencyHourly    = "hourly"    // an hourly digest email
+	SavedSearchEmailFrequencyDaily     = "daily"     // a daily digest email
+)
+
+// Webhook event types. An outbound webhook receives the events of the types it subscribes to.
+const (
+	WebhookEventSavedSearchResults       = "saved_search.results"       // a saved search has new results
+	WebhookEventDiscussionCommentCreated = "discussion_comment.created" // a comment was added to a discussion thread
+	WebhookEventRepoCloned               = "repo.cloned"                // a repository was cloned
+	WebhookEventRepoCloneFailed          = "repo.clone_failed"          // cloning a repository failed
+	WebhookEventExternalServiceSynced    = "external_service.synced"    // the repositories of external services were synced
+)
+
+// WebhookEnqueueRequest is a request to deliver an event to the outbound webhooks that subscribe to
+// its type.
+type WebhookEnqueueRequest struct {
+	// Event is the event type (e.g., WebhookEventRepoCloned).
+	Event string `json:"event"`
+
+	// UserIDs are the users that the event concerns (e.g., the owners of a saved search). Webhooks
+	// registered by users only receive the events that concern them. Webhooks registered by site
+	// admins receive all events.
+	UserIDs []int32 `json:"userIDs,omitempty"`
+
+	// Payload is the event's data, which is delivered to the webhooks as JSON.
+	Payload interface{} `json:"payload"`
+}
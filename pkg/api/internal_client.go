@@ -78,11 +78,13 @@ type SavedQueryIDSpec struct {
 // ConfigSavedQuery is the JSON shape of a saved query entry in the JSON configuration
 // (i.e., an entry in the {"search.savedQueries": [...]} array).
 type ConfigSavedQuery struct {
-	Key         string `json:"key,omitempty"`
-	Description string `json:"description"`
-	Query       string `json:"query"`
-	Notify      bool   `json:"notify,omitempty"`
-	NotifySlack bool   `json:"notifySlack,omitempty"`
+	Key           string `json:"key,omitempty"`
+	Description   string `json:"description"`
+	Query         string `json:"query"`
+	Notify        bool   `json:"notify,omitempty"`
+	NotifySlack   bool   `json:"notifySlack,omitempty"`
+	NotifyMSTeams bool   `json:"notifyMSTeams,omitempty"`
+	NotifyWebhook bool   `json:"notifyWebhook,omitempty"`
 }
 
 func (sq ConfigSavedQuery) Equals(other ConfigSavedQuery) bool {
@@ -155,6 +157,74 @@ func (c *internalClient) SavedQueriesDeleteInfo(ctx context.Context, query strin
 	return c.postInternal(ctx, "saved-queries/delete-info", query, nil)
 }
 
+// SavedSearchDigest is a user's pending saved search digest email, which query-runner saves so that
+// the new results that haven't been sent yet aren't lost when it restarts.
+type SavedSearchDigest struct {
+	UserID int32
+	DueAt  time.Time
+
+	// Contents are the digest's entries. They are opaque to the frontend.
+	Contents json.RawMessage
+}
+
+// SavedSearchDigestsDeleteRequest is the request to delete a user's pending saved search digest
+// that is due at DueAt.
+type SavedSearchDigestsDeleteRequest struct {
+	UserID int32
+	DueAt  time.Time
+}
+
+// SavedSearchDigestsList lists all pending saved search digests.
+func (c *internalClient) SavedSearchDigestsList(ctx context.Context) ([]*SavedSearchDigest, error) {
+	var digests []*SavedSearchDigest
+	err := c.postInternal(ctx, "saved-search-digests/list", nil, &digests)
+	if err != nil {
+		return nil, err
+	}
+	return digests, nil
+}
+
+// SavedSearchDigestsSave creates or replaces the user's pending saved search digest.
+func (c *internalClient) SavedSearchDigestsSave(ctx context.Context, digest *SavedSearchDigest) error {
+	return c.postInternal(ctx, "saved-search-digests/save", digest, nil)
+}
+
+// SavedSearchDigestsDelete deletes the user's pending saved search digest if it is due at dueAt (a
+// digest with a different due time is a newer digest, which is kept).
+func (c *internalClient) SavedSearchDigestsDelete(ctx context.Context, userID int32, dueAt time.Time) error {
+	return c.postInternal(ctx, "saved-search-digests/delete", &SavedSearchDigestsDeleteRequest{UserID: userID, DueAt: dueAt}, nil)
+}
+
+// SavedQueryReplyToRequest is the request to get the "Reply-To" address of a
+// user's email notifications for a saved query.
+type SavedQueryReplyToRequest struct {
+	UserID int32
+	Spec   SavedQueryIDSpec
+}
+
+// SavedQueriesReplyTo returns the "Reply-To" address of the user's email
+// notifications for the saved query. Replying to the address with a command
+// (such as "mute 1w" or "unsubscribe") updates the user's subscription to the
+// saved query. nil is returned if replies cannot be read (i.e., if email.imap
+// is not configured).
+func (c *internalClient) SavedQueriesReplyTo(ctx context.Context, userID int32, spec SavedQueryIDSpec) (replyTo *string, err error) {
+	err = c.postInternal(ctx, "saved-queries/reply-to", SavedQueryReplyToRequest{UserID: userID, Spec: spec}, &replyTo)
+	if err != nil {
+		return nil, err
+	}
+	return replyTo, nil
+}
+
+// SavedQueriesMutedUsers returns the IDs of the users who have muted (or
+// unsubscribed from) email notifications for the saved query.
+func (c *internalClient) SavedQueriesMutedUsers(ctx context.Context, spec SavedQueryIDSpec) (userIDs []int32, err error) {
+	err = c.postInternal(ctx, "saved-queries/muted-users", spec, &userIDs)
+	if err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
 func (c *internalClient) SettingsGetForSubject(ctx context.Context, subject SettingsSubject) (parsed *schema.Settings, settings *Settings, err error) {
 	err = c.postInternal(ctx, "settings/get-for-subject", subject, &settings)
 	if err == nil {
@@ -192,6 +262,16 @@ func (c *internalClient) UsersGetByUsername(ctx context.Context, username string
 	return user, nil
 }
 
+// UsersGetEmailFrequency returns how often the user receives saved search email notifications (one of
+// the SavedSearchEmailFrequency* values).
+func (c *internalClient) UsersGetEmailFrequency(ctx context.Context, userID int32) (frequency string, err error) {
+	err = c.postInternal(ctx, "users/get-email-frequency", userID, &frequency)
+	if err != nil {
+		return "", err
+	}
+	return frequency, nil
+}
+
 func (c *internalClient) UserEmailsGetEmail(ctx context.Context, userID int32) (email *string, err error) {
 	err = c.postInternal(ctx, "user-emails/get-email", userID, &email)
 	if err != nil {
@@ -274,6 +354,12 @@ func (c *internalClient) ReposUpdateMetadata(ctx context.Context, repo RepoName,
 	}, nil)
 }
 
+// ReposRenamed updates references to the previous names of renamed repos, such as repo: filters in
+// saved searches.
+func (c *internalClient) ReposRenamed(ctx context.Context, renamed map[RepoName]RepoName) error {
+	return c.postInternal(ctx, "repos/renamed", ReposRenamedRequest{Renamed: renamed}, nil)
+}
+
 func (c *internalClient) ReposGetByName(ctx context.Context, repoName RepoName) (*Repo, error) {
 	var repo Repo
 	err := c.postInternal(ctx, "repos/"+string(repoName), nil, &repo)
@@ -283,6 +369,12 @@ func (c *internalClient) ReposGetByName(ctx context.Context, repoName RepoName)
 	return &repo, nil
 }
 
+// WebhooksEnqueue enqueues the delivery of an event to the outbound webhooks that subscribe to its
+// type.
+func (c *internalClient) WebhooksEnqueue(ctx context.Context, req WebhookEnqueueRequest) error {
+	return c.postInternal(ctx, "webhooks/enqueue", req, nil)
+}
+
 func (c *internalClient) PhabricatorRepoCreate(ctx context.Context, repo RepoName, callsign, url string) error {
 	return c.postInternal(ctx, "phabricator/repo-create", PhabricatorRepoCreateRequest{
 		RepoName: repo,
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/sourcegraph/sourcegraph/pkg/api"
 	"github.com/sourcegraph/sourcegraph/pkg/conf/confdefaults"
@@ -91,9 +92,18 @@ func defaultConfigForDeployment() conftypes.RawUnified {
 	}
 }
 
+// externalServiceConfigs fetches the configs of the external services of the kind into result
+// and resolves their secret references (such as code host tokens) in this service.
+func externalServiceConfigs(ctx context.Context, kind string, result interface{}) error {
+	if err := api.InternalClient.ExternalServiceConfigs(ctx, kind, result); err != nil {
+		return err
+	}
+	return resolveSecretsIn(result, ResolveSecret)
+}
+
 func AWSCodeCommitConfigs(ctx context.Context) ([]*schema.AWSCodeCommitConnection, error) {
 	var config []*schema.AWSCodeCommitConnection
-	if err := api.InternalClient.ExternalServiceConfigs(ctx, "AWSCODECOMMIT", &config); err != nil {
+	if err := externalServiceConfigs(ctx, "AWSCODECOMMIT", &config); err != nil {
 		return nil, err
 	}
 	return config, nil
@@ -101,7 +111,7 @@ func AWSCodeCommitConfigs(ctx context.Context) ([]*schema.AWSCodeCommitConnectio
 
 func BitbucketServerConfigs(ctx context.Context) ([]*schema.BitbucketServerConnection, error) {
 	var config []*schema.BitbucketServerConnection
-	if err := api.InternalClient.ExternalServiceConfigs(ctx, "BITBUCKETSERVER", &config); err != nil {
+	if err := externalServiceConfigs(ctx, "BITBUCKETSERVER", &config); err != nil {
 		return nil, err
 	}
 	return config, nil
@@ -109,7 +119,7 @@ func BitbucketServerConfigs(ctx context.Context) ([]*schema.BitbucketServerConne
 
 func GitHubConfigs(ctx context.Context) ([]*schema.GitHubConnection, error) {
 	var config []*schema.GitHubConnection
-	if err := api.InternalClient.ExternalServiceConfigs(ctx, "GITHUB", &config); err != nil {
+	if err := externalServiceConfigs(ctx, "GITHUB", &config); err != nil {
 		return nil, err
 	}
 	return config, nil
@@ -117,7 +127,7 @@ func GitHubConfigs(ctx context.Context) ([]*schema.GitHubConnection, error) {
 
 func GitLabConfigs(ctx context.Context) ([]*schema.GitLabConnection, error) {
 	var config []*schema.GitLabConnection
-	if err := api.InternalClient.ExternalServiceConfigs(ctx, "GITLAB", &config); err != nil {
+	if err := externalServiceConfigs(ctx, "GITLAB", &config); err != nil {
 		return nil, err
 	}
 	return config, nil
@@ -125,7 +135,7 @@ func GitLabConfigs(ctx context.Context) ([]*schema.GitLabConnection, error) {
 
 func GitoliteConfigs(ctx context.Context) ([]*schema.GitoliteConnection, error) {
 	var config []*schema.GitoliteConnection
-	if err := api.InternalClient.ExternalServiceConfigs(ctx, "GITOLITE", &config); err != nil {
+	if err := externalServiceConfigs(ctx, "GITOLITE", &config); err != nil {
 		return nil, err
 	}
 	return config, nil
@@ -133,7 +143,7 @@ func GitoliteConfigs(ctx context.Context) ([]*schema.GitoliteConnection, error)
 
 func PhabricatorConfigs(ctx context.Context) ([]*schema.PhabricatorConnection, error) {
 	var config []*schema.PhabricatorConnection
-	if err := api.InternalClient.ExternalServiceConfigs(ctx, "PHABRICATOR", &config); err != nil {
+	if err := externalServiceConfigs(ctx, "PHABRICATOR", &config); err != nil {
 		return nil, err
 	}
 	return config, nil
@@ -172,7 +182,8 @@ func AccessTokensAllow() AccessTokAllow {
 //
 // It's false for sites that do not have an email sending API key set up.
 func EmailVerificationRequired() bool {
-	return Get().EmailSmtp != nil
+	c := Get()
+	return c.EmailSmtp != nil || c.EmailHttp != nil
 }
 
 // CanSendEmail returns whether the site can send emails (e.g., to reset a password or
@@ -180,7 +191,8 @@ func EmailVerificationRequired() bool {
 //
 // It's false for sites that do not have an email sending API key set up.
 func CanSendEmail() bool {
-	return Get().EmailSmtp != nil
+	c := Get()
+	return c.EmailSmtp != nil || c.EmailHttp != nil
 }
 
 // CanReadEmail tells if an IMAP server is configured and reading email is possible.
@@ -258,6 +270,24 @@ func SearchIndexEnabled() bool {
 	return DeployType() != DeployDocker
 }
 
+// PermissionsBackgroundSyncEnabled returns whether repository permissions are synced in the
+// background and checked using the permissions stored in the database.
+func PermissionsBackgroundSyncEnabled() bool {
+	cfg := Get().PermissionsBackgroundSync
+	return cfg != nil && cfg.Enabled
+}
+
+// PermissionsBackgroundSyncInterval returns how often each user's repository permissions are
+// synced in the background.
+func PermissionsBackgroundSyncInterval() time.Duration {
+	if cfg := Get().PermissionsBackgroundSync; cfg != nil && cfg.Interval != "" {
+		if d, err := time.ParseDuration(cfg.Interval); err == nil && d > 0 {
+			return d
+		}
+	}
+	return time.Hour
+}
+
 // SrcGitServers represents the SRC_GIT_SERVERS environment variable.
 //
 // Non-frontend callers should go through api.InternalClient.GitServerAddrs() instead.
@@ -3,11 +3,35 @@ package conf
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
+	"github.com/sourcegraph/sourcegraph/pkg/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
+// DiffRaw returns the sorted names of the configuration properties whose values differ between
+// the two raw configurations (e.g., "auth.providers" or "critical::externalURL"). It returns an
+// error if either configuration can't be parsed.
+func DiffRaw(before, after conftypes.RawUnified) ([]string, error) {
+	b, err := ParseConfig(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := ParseConfig(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := diff(b, a)
+	fields := make([]string, 0, len(changed))
+	for f := range changed {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
 // diff returns names of the Go fields that have different values between the
 // two configurations.
 func diff(before, after *Unified) (fields map[string]struct{}) {
@@ -5,6 +5,7 @@ import (
 	"sort"
 	"testing"
 
+	"github.com/sourcegraph/sourcegraph/pkg/conf/conftypes"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -89,6 +90,30 @@ func TestDiff(t *testing.T) {
 	}
 }
 
+func TestDiffRaw(t *testing.T) {
+	before := conftypes.RawUnified{
+		Critical: `{"externalURL": "https://a.example.com"}`,
+		Site:     `{"maxReposToSearch": 10, "experimentalFeatures": {"discussions": "enabled"}}`,
+	}
+	after := conftypes.RawUnified{
+		Critical: `{"externalURL": "https://b.example.com", // comment
+}`,
+		Site: `{"maxReposToSearch": 10, "experimentalFeatures": {"discussions": "disabled"}}`,
+	}
+
+	got, err := DiffRaw(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"critical::externalURL", "experimentalFeatures::discussions"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := DiffRaw(before, conftypes.RawUnified{Site: "{"}); err == nil {
+		t.Error("want error for invalid configuration")
+	}
+}
+
 func toSlice(m map[string]struct{}) []string {
 	var s []string
 	for v := range m {
@@ -9,7 +9,7 @@ import (
 )
 
 // parseConfigData parses the provided config string into the given cfg struct
-// pointer.
+// pointer. Secret references are not resolved (see resolveConfigSecrets).
 func parseConfigData(data string, cfg interface{}) error {
 	if data != "" {
 		data, err := jsonc.Parse(data)
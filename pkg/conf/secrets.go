@@ -0,0 +1,310 @@
+package conf
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/sourcegraph/jsonx"
+	"github.com/sourcegraph/sourcegraph/pkg/env"
+	"github.com/sourcegraph/sourcegraph/pkg/jsonc"
+)
+
+// Secret references are string values in configuration of the form "$env:NAME" (the value of the
+// environment variable NAME) or "$file:PATH" (the contents of the file at PATH, without trailing
+// newlines), so that secrets (such as code host tokens and passwords) don't need to be stored in
+// the configuration.
+//
+// References in the site and critical configuration are resolved once, when each service loads the
+// configuration (see resolveConfigSecrets), and references in external service configuration when
+// the external service's configuration is read (see ResolveSecrets), so that code that uses a
+// configuration value never sees a reference. A reference may only be resolvable in the containers
+// of the services that use its value, so a reference that can't be resolved doesn't prevent the
+// rest of the configuration from being loaded.
+//
+// 🚨 SECURITY: References can only refer to environment variables whose names start with
+// secretEnvPrefix and to files in secretsDir. Otherwise site admins could read any environment
+// variable or file of any service (e.g., by using a reference as a token that is sent to a URL
+// they control).
+const (
+	envSecretPrefix  = "$env:"
+	fileSecretPrefix = "$file:"
+
+	// secretEnvPrefix is the prefix of the names of the environment variables that "$env:"
+	// references can refer to.
+	secretEnvPrefix = "SRC_SECRET_"
+)
+
+// secretsDir is the directory of the files that "$file:" references can refer to.
+var secretsDir = env.Get("SRC_SECRETS_DIR", "/run/secrets", "directory of the files that $file: secret references in configuration can refer to")
+
+// isSecretReference reports whether s is a secret reference.
+func isSecretReference(s string) bool {
+	return strings.HasPrefix(s, envSecretPrefix) || strings.HasPrefix(s, fileSecretPrefix)
+}
+
+// checkSecretReference returns an error if ref is a secret reference that is not allowed to be
+// resolved. It doesn't check whether the reference can be resolved.
+func checkSecretReference(ref string) error {
+	switch {
+	case strings.HasPrefix(ref, envSecretPrefix):
+		if name := strings.TrimPrefix(ref, envSecretPrefix); !strings.HasPrefix(name, secretEnvPrefix) || name == secretEnvPrefix {
+			return fmt.Errorf("invalid secret reference %q: the environment variable's name must start with %s", ref, secretEnvPrefix)
+		}
+	case strings.HasPrefix(ref, fileSecretPrefix):
+		if !isInSecretsDir(strings.TrimPrefix(ref, fileSecretPrefix), secretsDir) {
+			return fmt.Errorf("invalid secret reference %q: the file must be in the secrets directory %s", ref, secretsDir)
+		}
+	}
+	return nil
+}
+
+// isInSecretsDir reports whether path is in (a subdirectory of) dir.
+func isInSecretsDir(path, dir string) bool {
+	if !filepath.IsAbs(path) {
+		return false
+	}
+	rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(path))
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// ResolveSecret returns the value that the secret reference ref refers to. If ref is not a secret
+// reference, it is returned unchanged.
+func ResolveSecret(ref string) (string, error) {
+	if !isSecretReference(ref) {
+		return ref, nil
+	}
+	if err := checkSecretReference(ref); err != nil {
+		return "", err
+	}
+
+	if strings.HasPrefix(ref, envSecretPrefix) {
+		name := strings.TrimPrefix(ref, envSecretPrefix)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("unable to resolve secret reference %q: environment variable %s is not set", ref, name)
+		}
+		return value, nil
+	}
+
+	// 🚨 SECURITY: Check the path again after resolving symlinks, so that a symlink in the secrets
+	// directory can't be used to read other files.
+	path, err := filepath.EvalSymlinks(strings.TrimPrefix(ref, fileSecretPrefix))
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve secret reference %q: %s", ref, err)
+	}
+	dir, err := filepath.EvalSymlinks(secretsDir)
+	if err != nil || !isInSecretsDir(path, dir) {
+		return "", fmt.Errorf("invalid secret reference %q: the file must be in the secrets directory %s", ref, secretsDir)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve secret reference %q: %s", ref, err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// ResolveSecrets returns the JSONC configuration input as JSON, with all secret references in
+// string values replaced by the values they refer to (see ResolveSecret). An error is returned if
+// input is invalid or a secret reference can't be resolved.
+//
+// It is used to read external service configuration (e.g., by repo-updater's
+// ExternalService.Configuration).
+func ResolveSecrets(input string) (string, error) {
+	if strings.TrimSpace(input) == "" {
+		return input, nil
+	}
+	data, err := jsonc.Parse(input)
+	if err != nil {
+		return "", err
+	}
+	data, err = resolveSecretsJSON(data, ResolveSecret)
+	return string(data), err
+}
+
+// resolveSecretsJSON is like ResolveSecrets, except that it takes and returns standard JSON and
+// resolves each string value with resolve.
+func resolveSecretsJSON(data []byte, resolve func(string) (string, error)) ([]byte, error) {
+	if !bytes.Contains(data, []byte(envSecretPrefix)) && !bytes.Contains(data, []byte(fileSecretPrefix)) {
+		return data, nil // fast path
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber() // preserve numbers exactly
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if err := walkStrings(v, func(s *string) error {
+		var err error
+		*s, err = resolve(*s)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// resolveSecretsIn resolves the secret references in the string values of v (with resolve), which
+// must be a pointer to a value that can be marshaled to and unmarshaled from JSON.
+func resolveSecretsIn(v interface{}, resolve func(string) (string, error)) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	resolved, err := resolveSecretsJSON(data, resolve)
+	if err != nil || bytes.Equal(resolved, data) {
+		return err
+	}
+	// Unmarshal into the zero value, so that values that were resolved to the empty string (and
+	// omitted from resolved) don't keep their references.
+	elem := reflect.ValueOf(v).Elem()
+	elem.Set(reflect.Zero(elem.Type()))
+	return json.Unmarshal(resolved, v)
+}
+
+// resolveConfigSecrets resolves the secret references in the site and critical configuration in
+// place. A reference that can't be resolved in this service (e.g., because its environment
+// variable is only set in the containers of other services) is replaced by the empty string, so
+// that the reference itself is never used as a secret, and is described by one of the returned
+// errors.
+func resolveConfigSecrets(cfg *Unified) (errs []error) {
+	resolve := func(ref string) (string, error) {
+		value, err := ResolveSecret(ref)
+		if err != nil {
+			errs = append(errs, err)
+			return "", nil
+		}
+		return value, nil
+	}
+	if err := resolveSecretsIn(&cfg.Critical, resolve); err != nil {
+		errs = append(errs, err)
+	}
+	if err := resolveSecretsIn(&cfg.SiteConfiguration, resolve); err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// ValidateSecretReferences returns an error if the JSONC configuration input contains a secret
+// reference that is not allowed (e.g., to a file outside of the secrets directory). It doesn't
+// check whether the references can be resolved, because they may only be resolvable by the
+// services that use them.
+func ValidateSecretReferences(input string) error {
+	if strings.TrimSpace(input) == "" {
+		return nil
+	}
+	var v interface{}
+	if err := jsonc.Unmarshal(input, &v); err != nil {
+		return err
+	}
+	return walkStrings(v, func(s *string) error { return checkSecretReference(*s) })
+}
+
+// walkStrings calls f with a pointer to each string value (not object key) in v, which is a value
+// decoded from JSON.
+func walkStrings(v interface{}, f func(*string) error) error {
+	switch v := v.(type) {
+	case []interface{}:
+		for i, e := range v {
+			if s, ok := e.(string); ok {
+				if err := f(&s); err != nil {
+					return err
+				}
+				v[i] = s
+			} else if err := walkStrings(e, f); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		for k, e := range v {
+			if s, ok := e.(string); ok {
+				if err := f(&s); err != nil {
+					return err
+				}
+				v[k] = s
+			} else if err := walkStrings(e, f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// RedactSecrets returns the JSONC configuration input (such as a previous revision of the
+// configuration) with each string value replaced by a secret reference if the current
+// configuration has that secret reference at the same JSON path and the value is the value that
+// the secret reference refers to. It is used to avoid exposing secrets that were stored inline in
+// configuration before they were replaced by secret references (e.g., in the configuration
+// history). Values at other paths are never changed, even if they are equal to a secret's value.
+func RedactSecrets(input, current string) string {
+	refs := map[string]string{} // JSON path -> secret reference
+	currentRoot, _ := jsonx.ParseTree(current, jsonx.ParseOptions{Comments: true, TrailingCommas: true})
+	walkStringNodes(currentRoot, nil, func(path jsonx.Path, node *jsonx.Node) {
+		if ref := node.Value.(string); isSecretReference(ref) {
+			refs[pathKey(path)] = ref
+		}
+	})
+	if len(refs) == 0 {
+		return input
+	}
+
+	var edits []jsonx.Edit
+	root, _ := jsonx.ParseTree(input, jsonx.ParseOptions{Comments: true, TrailingCommas: true})
+	walkStringNodes(root, nil, func(path jsonx.Path, node *jsonx.Node) {
+		ref, ok := refs[pathKey(path)]
+		if !ok {
+			return
+		}
+		if value, err := ResolveSecret(ref); err != nil || value == "" || value != node.Value.(string) {
+			return
+		}
+		edits = append(edits, jsonx.Edit{Offset: node.Offset, Length: node.Length, Content: jsonString(ref)})
+	})
+	redacted, err := jsonx.ApplyEdits(input, edits...)
+	if err != nil {
+		return input
+	}
+	return redacted
+}
+
+// walkStringNodes calls f with each string value node (not object key) in the JSON parse tree, in
+// document order.
+func walkStringNodes(node *jsonx.Node, path jsonx.Path, f func(jsonx.Path, *jsonx.Node)) {
+	if node == nil {
+		return
+	}
+	switch node.Type {
+	case jsonx.String:
+		f(path, node)
+	case jsonx.Object:
+		for _, prop := range node.Children {
+			if len(prop.Children) == 2 {
+				walkStringNodes(prop.Children[1], append(path[:len(path):len(path)], jsonx.Segment{IsProperty: true, Property: prop.Children[0].Value.(string)}), f)
+			}
+		}
+	case jsonx.Array:
+		for i, e := range node.Children {
+			walkStringNodes(e, append(path[:len(path):len(path)], jsonx.Segment{Index: i}), f)
+		}
+	}
+}
+
+func pathKey(path jsonx.Path) string {
+	b, _ := json.Marshal(path)
+	return string(b)
+}
+
+// jsonString returns the JSON string literal for s.
+func jsonString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,251 @@
+package conf
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/pkg/conf/conftypes"
+)
+
+// setupSecrets sets secretsDir to a temporary directory that contains the file "token" and sets
+// the environment variable SRC_SECRET_TEST. It returns the secrets directory and a function that
+// undoes the setup.
+func setupSecrets(t *testing.T) (dir string, done func()) {
+	tmp, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir = filepath.Join(tmp, "secrets")
+	if err := os.Mkdir(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "token"), []byte("file-secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Setenv("SRC_SECRET_TEST", "env-secret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Setenv("TEST_NOT_A_SECRET", "not-a-secret"); err != nil {
+		t.Fatal(err)
+	}
+	origSecretsDir := secretsDir
+	secretsDir = dir
+	return dir, func() {
+		secretsDir = origSecretsDir
+		os.Unsetenv("SRC_SECRET_TEST")
+		os.Unsetenv("TEST_NOT_A_SECRET")
+		os.RemoveAll(tmp)
+	}
+}
+
+func TestResolveSecret(t *testing.T) {
+	dir, done := setupSecrets(t)
+	defer done()
+
+	// A file outside of the secrets directory, and a symlink to it in the secrets directory.
+	outside := filepath.Join(filepath.Dir(dir), "outside")
+	if err := ioutil.WriteFile(outside, []byte("outside"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		"not a reference": {ref: "abc", want: "abc"},
+		"env":             {ref: "$env:SRC_SECRET_TEST", want: "env-secret"},
+		"file":            {ref: "$file:" + filepath.Join(dir, "token"), want: "file-secret"},
+		"unset env":       {ref: "$env:SRC_SECRET_UNSET", wantErr: true},
+		"missing file":    {ref: "$file:" + filepath.Join(dir, "missing"), wantErr: true},
+		"env without prefix": {
+			ref:     "$env:TEST_NOT_A_SECRET",
+			wantErr: true,
+		},
+		"file outside of secrets dir": {
+			ref:     "$file:" + outside,
+			wantErr: true,
+		},
+		"relative path out of secrets dir": {
+			ref:     "$file:" + dir + "/../outside",
+			wantErr: true,
+		},
+		"relative file": {
+			ref:     "$file:token",
+			wantErr: true,
+		},
+		"symlink out of secrets dir": {
+			ref:     "$file:" + filepath.Join(dir, "link"),
+			wantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveSecret(test.ref)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestResolveSecrets(t *testing.T) {
+	dir, done := setupSecrets(t)
+	defer done()
+	file := filepath.Join(dir, "token")
+
+	tests := map[string]struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		"no references": {
+			input: `{"a": "b", "n": 1.50, /* comment */}`,
+			want:  `{"a": "b", "n": 1.50}`,
+		},
+		"env": {
+			input: `{"a": {"token": "$env:SRC_SECRET_TEST"}, "n": 1.50}`,
+			want:  `{"a": {"token": "env-secret"}, "n": 1.50}`,
+		},
+		"file": {
+			input: `{"a": ["$file:` + file + `"]}`,
+			want:  `{"a": ["file-secret"]}`,
+		},
+		"keys are not resolved": {
+			input: `{"$env:SRC_SECRET_TEST": true}`,
+			want:  `{"$env:SRC_SECRET_TEST": true}`,
+		},
+		"unset env": {
+			input:   `{"a": "$env:SRC_SECRET_UNSET"}`,
+			wantErr: true,
+		},
+		"disallowed env": {
+			input:   `{"a": "$env:TEST_NOT_A_SECRET"}`,
+			wantErr: true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ResolveSecrets(test.input)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			if test.wantErr {
+				return
+			}
+			var gotValue, wantValue interface{}
+			if err := json.Unmarshal([]byte(got), &gotValue); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(test.want), &wantValue); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(gotValue, wantValue) {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestResolveConfigSecrets(t *testing.T) {
+	_, done := setupSecrets(t)
+	defer done()
+
+	cfg, err := ParseConfig(conftypes.RawUnified{
+		Critical: `{"auth.scimBearerToken": "$env:SRC_SECRET_TEST", "lightstepAccessToken": "$env:SRC_SECRET_UNSET"}`,
+		Site:     `{"githubClientSecret": "$file:token", "email.smtp": {"host": "h", "port": 25, "authentication": "none", "password": "p"}}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	errs := resolveConfigSecrets(cfg)
+	if len(errs) != 2 {
+		t.Errorf("got errors %v, want 2 errors (for the unset environment variable and the relative file)", errs)
+	}
+	if got, want := cfg.Critical.AuthScimBearerToken, "env-secret"; got != want {
+		t.Errorf("got auth.scimBearerToken %q, want %q", got, want)
+	}
+	// References that can't be resolved are never used as secrets.
+	if got := cfg.Critical.LightstepAccessToken; got != "" {
+		t.Errorf("got lightstepAccessToken %q, want empty", got)
+	}
+	if got := cfg.GithubClientSecret; got != "" {
+		t.Errorf("got githubClientSecret %q, want empty", got)
+	}
+	if got, want := cfg.EmailSmtp.Password, "p"; got != want {
+		t.Errorf("got email.smtp password %q, want %q", got, want)
+	}
+	if cfg.ExperimentalFeatures == nil {
+		t.Error("got nil experimentalFeatures")
+	}
+}
+
+func TestValidateSecretReferences(t *testing.T) {
+	dir, done := setupSecrets(t)
+	defer done()
+
+	tests := map[string]bool{ // input -> want error
+		`{"a": "b"}`: false,
+		`{"a": ["$env:SRC_SECRET_UNSET", "$file:` + filepath.Join(dir, "missing") + `"]}`: false,
+		`{"a": "$env:TEST_NOT_A_SECRET"}`:                                                 true,
+		`{"a": {"b": "$file:/etc/passwd"}}`:                                               true,
+		`{"a": "b", // comment
+}`: false,
+	}
+	for input, wantErr := range tests {
+		if err := ValidateSecretReferences(input); (err != nil) != wantErr {
+			t.Errorf("%s: got error %v, want error %v", input, err, wantErr)
+		}
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	dir, done := setupSecrets(t)
+	defer done()
+	fileRef := "$file:" + filepath.Join(dir, "token")
+
+	current := `{
+  "token": "$env:SRC_SECRET_TEST",
+  "repos": [{"password": "` + fileRef + `"}],
+  "unresolvable": "$env:SRC_SECRET_UNSET"
+}`
+	tests := map[string]struct {
+		input string
+		want  string
+	}{
+		"inline secrets at the paths of references": {
+			input: `{"token": "env-secret", /* comment */ "repos": [{"password": "file-secret"}]}`,
+			want:  `{"token": "$env:SRC_SECRET_TEST", /* comment */ "repos": [{"password": "` + fileRef + `"}]}`,
+		},
+		"secret values at other paths are unchanged": {
+			input: `{"other": "env-secret", "repos": [{}, {"password": "file-secret"}]}`,
+			want:  `{"other": "env-secret", "repos": [{}, {"password": "file-secret"}]}`,
+		},
+		"other values at the paths of references are unchanged": {
+			input: `{"token": "old-secret", "unresolvable": ""}`,
+			want:  `{"token": "old-secret", "unresolvable": ""}`,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := RedactSecrets(test.input, current); got != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+
+	// Without references in the current configuration, nothing is redacted.
+	if input := `{"token": "env-secret"}`; RedactSecrets(input, `{}`) != input {
+		t.Error("got redacted input, want input unchanged")
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/pkg/conf/conftypes"
+	log15 "gopkg.in/inconshreveable/log15.v2"
 )
 
 // Store manages the in-memory storage, access,
@@ -107,6 +108,9 @@ func (s *Store) MaybeUpdate(rawConfig conftypes.RawUnified) (UpdateResult, error
 	if err != nil {
 		return result, errors.Wrap(err, "when parsing rawConfig during update")
 	}
+	for _, err := range resolveConfigSecrets(newConfig) {
+		log15.Warn("Unable to resolve secret reference in configuration (using an empty value instead).", "error", err)
+	}
 
 	result.Changed = true
 	result.New = newConfig
@@ -80,6 +80,11 @@ func ValidateSite(input string) (problems []string, err error) {
 }
 
 func doValidate(inputStr, schema string) (problems []string, err error) {
+	// The secret references are validated, not the values they refer to, because they may only be
+	// resolvable by the services that use them.
+	if err := ValidateSecretReferences(inputStr); err != nil {
+		return []string{err.Error()}, nil
+	}
 	input := []byte(jsonc.Normalize(inputStr))
 
 	res, err := validate([]byte(schema), input)
@@ -91,6 +96,9 @@ func doValidate(inputStr, schema string) (problems []string, err error) {
 		if _, ok := ignoreLegacyKubernetesFields[e.Field()]; ok {
 			continue
 		}
+		if isSecretReferenceStringError(e) {
+			continue
+		}
 
 		var keyPath string
 		if c := e.Context(); c != nil {
@@ -104,6 +112,18 @@ func doValidate(inputStr, schema string) (problems []string, err error) {
 	return problems, nil
 }
 
+// isSecretReferenceStringError reports whether e is an error about the length, pattern or format of
+// a string value that is a secret reference. These constraints apply to the value that the
+// reference refers to, which may only be resolvable by the services that use it.
+func isSecretReferenceStringError(e gojsonschema.ResultError) bool {
+	switch e.Type() {
+	case "string_gte", "string_lte", "pattern", "format":
+		s, ok := e.Value().(string)
+		return ok && isSecretReference(s)
+	}
+	return false
+}
+
 func validate(schema, input []byte) (*gojsonschema.Result, error) {
 	if len(input) > 0 {
 		// HACK: Remove the "settings" field from site config because
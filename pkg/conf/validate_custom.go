@@ -47,6 +47,9 @@ func validateCustom(cfg Unified) (problems []string) {
 		if hasSMTP && cfg.EmailAddress == "" {
 			invalid(`should set email.address because email.smtp is set`)
 		}
+		if cfg.EmailHttp != nil && cfg.EmailAddress == "" {
+			invalid(`should set email.address because email.http is set`)
+		}
 		if hasSMTPAuth && (cfg.EmailSmtp.Username == "" && cfg.EmailSmtp.Password == "") {
 			invalid(`must set email.smtp username and password for email.smtp authentication`)
 		}
@@ -30,6 +30,31 @@ func TestValidate(t *testing.T) {
 	})
 }
 
+func TestDoValidate_secretReferences(t *testing.T) {
+	// String constraints (such as the minimum length of auth.scimBearerToken) apply to the values
+	// that secret references refer to, not to the references.
+	tests := map[string]bool{ // input -> want a problem with auth.scimBearerToken
+		`{"auth.scimBearerToken": "$env:SRC_SECRET_SCIM_TOKEN"}`: false,
+		`{"auth.scimBearerToken": "too-short"}`:                  true,
+		`{"auth.scimBearerToken": 123}`:                          true,
+	}
+	for input, wantProblem := range tests {
+		problems, err := doValidate(input, schema.CriticalSchemaJSON)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotProblem := false
+		for _, p := range problems {
+			if strings.HasPrefix(p, "auth.scimBearerToken:") {
+				gotProblem = true
+			}
+		}
+		if gotProblem != wantProblem {
+			t.Errorf("%s: got problems %q, want a problem with auth.scimBearerToken %v", input, problems, wantProblem)
+		}
+	}
+}
+
 func TestValidateCustom(t *testing.T) {
 	tests := map[string]struct {
 		rawCritical, rawSite string
@@ -11,6 +11,7 @@ import (
 
 	"github.com/keegancsmith/sqlf"
 	"github.com/sourcegraph/jsonx"
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
 	"github.com/sourcegraph/sourcegraph/pkg/conf/confdefaults"
 	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
 )
@@ -22,6 +23,10 @@ type Config struct {
 	Contents  string    // the raw JSON content (with comments and trailing commas allowed)
 	CreatedAt time.Time // the date when this config was created
 	UpdatedAt time.Time // the date when this config was updated
+
+	// AuthorUserID is the ID of the user who saved this config, or nil if it was saved by
+	// Sourcegraph itself (e.g., the default config) or the user was deleted.
+	AuthorUserID *int32
 }
 
 // SiteConfig contains the contents of a site config along with associated metadata.
@@ -30,6 +35,10 @@ type SiteConfig Config
 // CriticalConfig contains the contents of a critical config along with associated metadata.
 type CriticalConfig Config
 
+// ErrRevisionNotFound is returned by GetRevision when the requested config revision does not
+// exist.
+var ErrRevisionNotFound = errors.New("configuration revision not found")
+
 // ErrNewerEdit is returned by SiteCreateIfUpToDate and
 // CriticalCreateifUpToDate when a newer edit has already been applied and the
 // edit has been rejected.
@@ -39,7 +48,8 @@ var ErrNewerEdit = errors.New("someone else has already applied a newer edit")
 // supplied "lastID" is equal to the one that was most recently saved to the database.
 //
 // The site config that was most recently saved to the database is returned.
-// An error is returned if "contents" is invalid JSON.
+// An error is returned if "contents" is invalid JSON. The user in ctx (if any)
+// is recorded as the author of the new config.
 //
 // 🚨 SECURITY: This method does NOT verify the user is an admin. The caller is
 // responsible for ensuring this or that the response never makes it to a user.
@@ -50,7 +60,7 @@ func SiteCreateIfUpToDate(ctx context.Context, lastID *int32, contents string) (
 	}
 	defer done()
 
-	newLastID, err := addDefault(ctx, tx, typeSite, confdefaults.Default.Site)
+	newLastID, err := addDefault(ctx, tx, TypeSite, confdefaults.Default.Site)
 	if err != nil {
 		return nil, err
 	}
@@ -58,7 +68,7 @@ func SiteCreateIfUpToDate(ctx context.Context, lastID *int32, contents string) (
 		lastID = newLastID
 	}
 
-	criticalSite, err := createIfUpToDate(ctx, tx, typeSite, lastID, contents)
+	criticalSite, err := createIfUpToDate(ctx, tx, TypeSite, lastID, contents)
 	return (*SiteConfig)(criticalSite), err
 }
 
@@ -67,7 +77,8 @@ func SiteCreateIfUpToDate(ctx context.Context, lastID *int32, contents string) (
 // recently saved to the database (i.e. SiteGetlatest's ID field).
 //
 // The critical config that was most recently saved to the database is returned.
-// An error is returned if "contents" is invalid JSON.
+// An error is returned if "contents" is invalid JSON. The user in ctx (if any)
+// is recorded as the author of the new config.
 //
 // 🚨 SECURITY: This method does NOT verify the user is an admin. The caller is
 // responsible for ensuring this or that the response never makes it to a user.
@@ -78,7 +89,7 @@ func CriticalCreateIfUpToDate(ctx context.Context, lastID *int32, contents strin
 	}
 	defer done()
 
-	newLastID, err := addDefault(ctx, tx, typeCritical, confdefaults.Default.Critical)
+	newLastID, err := addDefault(ctx, tx, TypeCritical, confdefaults.Default.Critical)
 	if err != nil {
 		return nil, err
 	}
@@ -86,7 +97,7 @@ func CriticalCreateIfUpToDate(ctx context.Context, lastID *int32, contents strin
 		lastID = newLastID
 	}
 
-	criticalSite, err := createIfUpToDate(ctx, tx, typeCritical, lastID, contents)
+	criticalSite, err := createIfUpToDate(ctx, tx, TypeCritical, lastID, contents)
 	return (*CriticalConfig)(criticalSite), err
 }
 
@@ -102,12 +113,12 @@ func SiteGetLatest(ctx context.Context) (latest *SiteConfig, err error) {
 	}
 	defer done()
 
-	_, err = addDefault(ctx, tx, typeSite, confdefaults.Default.Site)
+	_, err = addDefault(ctx, tx, TypeSite, confdefaults.Default.Site)
 	if err != nil {
 		return nil, err
 	}
 
-	site, err := getLatest(ctx, tx, typeSite)
+	site, err := getLatest(ctx, tx, TypeSite)
 	return (*SiteConfig)(site), err
 }
 
@@ -123,15 +134,91 @@ func CriticalGetLatest(ctx context.Context) (latest *CriticalConfig, err error)
 	}
 	defer done()
 
-	_, err = addDefault(ctx, tx, typeCritical, confdefaults.Default.Critical)
+	_, err = addDefault(ctx, tx, TypeCritical, confdefaults.Default.Critical)
 	if err != nil {
 		return nil, err
 	}
 
-	critical, err := getLatest(ctx, tx, typeCritical)
+	critical, err := getLatest(ctx, tx, TypeCritical)
 	return (*CriticalConfig)(critical), err
 }
 
+// ListOptions specifies the config revisions returned by ListRevisions.
+type ListOptions struct {
+	Limit  int // the maximum number of revisions to return (0 means no limit)
+	Offset int // the number of most recent revisions to skip
+
+	// AfterID, if nonzero, is the ID of a revision. Only the revisions that come after it in the
+	// list (i.e., that were saved before it) are returned.
+	AfterID int32
+}
+
+// ListRevisions returns the saved revisions of the config of the given type, most recent first.
+//
+// 🚨 SECURITY: This method does NOT verify the user is an admin. The caller is
+// responsible for ensuring this or that the response never makes it to a user.
+func ListRevisions(ctx context.Context, typ Type, opt ListOptions) ([]*Config, error) {
+	limit := sqlf.Sprintf("")
+	if opt.Limit > 0 {
+		limit = sqlf.Sprintf("LIMIT %d", opt.Limit)
+	}
+	conds := []*sqlf.Query{sqlf.Sprintf("type=%s", typ)}
+	if opt.AfterID != 0 {
+		conds = append(conds, sqlf.Sprintf("id<%d", opt.AfterID))
+	}
+	q := sqlf.Sprintf("SELECT s.id, s.type, s.contents, s.created_at, s.updated_at, s.author_user_id FROM critical_and_site_config s WHERE %s ORDER BY id DESC %s OFFSET %d", sqlf.Join(conds, "AND"), limit, opt.Offset)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	return parseQueryRows(ctx, rows)
+}
+
+// CountRevisions returns the number of saved revisions of the config of the given type.
+func CountRevisions(ctx context.Context, typ Type) (int, error) {
+	var count int
+	err := dbconn.Global.QueryRowContext(ctx, "SELECT COUNT(*) FROM critical_and_site_config WHERE type=$1", typ).Scan(&count)
+	return count, err
+}
+
+// GetRevision returns the revision with the given ID of the config of the given type. If there is
+// no such revision, ErrRevisionNotFound is returned.
+//
+// 🚨 SECURITY: This method does NOT verify the user is an admin. The caller is
+// responsible for ensuring this or that the response never makes it to a user.
+func GetRevision(ctx context.Context, typ Type, id int32) (*Config, error) {
+	return getRevision(ctx, sqlf.Sprintf("type=%s AND id=%d", typ, id))
+}
+
+// GetPreviousRevision returns the revision of the config of the given type that was saved just
+// before the revision with the given ID, or nil if there is none.
+//
+// 🚨 SECURITY: This method does NOT verify the user is an admin. The caller is
+// responsible for ensuring this or that the response never makes it to a user.
+func GetPreviousRevision(ctx context.Context, typ Type, id int32) (*Config, error) {
+	c, err := getRevision(ctx, sqlf.Sprintf("type=%s AND id<%d", typ, id))
+	if err == ErrRevisionNotFound {
+		return nil, nil
+	}
+	return c, err
+}
+
+func getRevision(ctx context.Context, cond *sqlf.Query) (*Config, error) {
+	q := sqlf.Sprintf("SELECT s.id, s.type, s.contents, s.created_at, s.updated_at, s.author_user_id FROM critical_and_site_config s WHERE %s ORDER BY id DESC LIMIT 1", cond)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	versions, err := parseQueryRows(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, ErrRevisionNotFound
+	}
+	return versions[0], nil
+}
+
 func newTransaction(ctx context.Context) (tx queryable, done func(), err error) {
 	rtx, err := dbconn.Global.BeginTx(ctx, nil)
 	if err != nil {
@@ -150,8 +237,8 @@ func newTransaction(ctx context.Context) (tx queryable, done func(), err error)
 	}, nil
 }
 
-func addDefault(ctx context.Context, tx queryable, configType configType, contents string) (newLastID *int32, err error) {
-	latest, err := getLatest(ctx, tx, configType)
+func addDefault(ctx context.Context, tx queryable, typ Type, contents string) (newLastID *int32, err error) {
+	latest, err := getLatest(ctx, tx, typ)
 	if err != nil {
 		return nil, err
 	}
@@ -160,15 +247,15 @@ func addDefault(ctx context.Context, tx queryable, configType configType, conten
 		return nil, nil
 	}
 
-	// Create the default.
-	latest, err = createIfUpToDate(ctx, tx, configType, nil, contents)
+	// Create the default. It has no author, even if the actor in ctx is a user.
+	latest, err = createIfUpToDate(actor.WithActor(ctx, nil), tx, typ, nil, contents)
 	if err != nil {
 		return nil, err
 	}
 	return &latest.ID, nil
 }
 
-func createIfUpToDate(ctx context.Context, tx queryable, configType configType, lastID *int32, contents string) (latest *Config, err error) {
+func createIfUpToDate(ctx context.Context, tx queryable, typ Type, lastID *int32, contents string) (latest *Config, err error) {
 	// Validate JSON syntax before saving.
 	if _, errs := jsonx.Parse(contents, jsonx.ParseOptions{Comments: true, TrailingCommas: true}); len(errs) > 0 {
 		return nil, fmt.Errorf("invalid settings JSON: %v", errs)
@@ -178,7 +265,7 @@ func createIfUpToDate(ctx context.Context, tx queryable, configType configType,
 		Contents: contents,
 	}
 
-	latest, err = getLatest(ctx, tx, configType)
+	latest, err = getLatest(ctx, tx, typ)
 	if err != nil {
 		return nil, err
 	}
@@ -186,19 +273,26 @@ func createIfUpToDate(ctx context.Context, tx queryable, configType configType,
 		return nil, ErrNewerEdit
 	}
 
+	var authorUserID *int32
+	if a := actor.FromContext(ctx); a.IsAuthenticated() && !a.Internal {
+		authorUserID = &a.UID
+	}
+
 	err = tx.QueryRowContext(
 		ctx,
-		"INSERT INTO critical_and_site_config(type, contents) VALUES($1, $2) RETURNING id, created_at, updated_at",
-		configType, new.Contents,
+		"INSERT INTO critical_and_site_config(type, contents, author_user_id) VALUES($1, $2, $3) RETURNING id, created_at, updated_at",
+		typ, new.Contents, authorUserID,
 	).Scan(&new.ID, &new.CreatedAt, &new.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
+	new.Type = string(typ)
+	new.AuthorUserID = authorUserID
 	return &new, nil
 }
 
-func getLatest(ctx context.Context, tx queryable, configType configType) (*Config, error) {
-	q := sqlf.Sprintf("SELECT s.id, s.type, s.contents, s.created_at, s.updated_at FROM critical_and_site_config s WHERE type=%s ORDER BY id DESC LIMIT 1", configType)
+func getLatest(ctx context.Context, tx queryable, typ Type) (*Config, error) {
+	q := sqlf.Sprintf("SELECT s.id, s.type, s.contents, s.created_at, s.updated_at, s.author_user_id FROM critical_and_site_config s WHERE type=%s ORDER BY id DESC LIMIT 1", typ)
 	rows, err := tx.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
 	if err != nil {
 		return nil, err
@@ -219,7 +313,7 @@ func parseQueryRows(ctx context.Context, rows *sql.Rows) ([]*Config, error) {
 	defer rows.Close()
 	for rows.Next() {
 		f := Config{}
-		err := rows.Scan(&f.ID, &f.Type, &f.Contents, &f.CreatedAt, &f.UpdatedAt)
+		err := rows.Scan(&f.ID, &f.Type, &f.Contents, &f.CreatedAt, &f.UpdatedAt, &f.AuthorUserID)
 		if err != nil {
 			return nil, err
 		}
@@ -238,9 +332,10 @@ type queryable interface {
 	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
-type configType string
+// Type is the type of a configuration: critical or site.
+type Type string
 
 const (
-	typeCritical configType = "critical"
-	typeSite     configType = "site"
+	TypeCritical Type = "critical"
+	TypeSite     Type = "site"
 )
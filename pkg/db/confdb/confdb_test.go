@@ -4,6 +4,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/sourcegraph/sourcegraph/pkg/actor"
+	"github.com/sourcegraph/sourcegraph/pkg/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/pkg/db/dbtesting"
 )
 
@@ -194,3 +196,67 @@ func TestCriticalCreateIfUpToDate(t *testing.T) {
 		})
 	}
 }
+
+func TestRevisions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := dbtesting.TestContext(t)
+
+	var userID int32
+	if err := dbconn.Global.QueryRowContext(ctx, "INSERT INTO users(username) VALUES('u') RETURNING id").Scan(&userID); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := SiteCreateIfUpToDate(ctx, nil, `{"a": 1}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := SiteCreateIfUpToDate(actor.WithActor(ctx, &actor.Actor{UID: userID}), &first.ID, `{"a": 2}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.AuthorUserID == nil || *second.AuthorUserID != userID {
+		t.Errorf("got author %v, want %d", second.AuthorUserID, userID)
+	}
+
+	revisions, err := ListRevisions(ctx, TypeSite, ListOptions{Limit: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 2 || revisions[0].ID != second.ID || revisions[1].ID != first.ID {
+		t.Fatalf("got revisions %+v, want [%d %d]", revisions, second.ID, first.ID)
+	}
+	if revisions[0].AuthorUserID == nil || *revisions[0].AuthorUserID != userID || revisions[1].AuthorUserID != nil {
+		t.Errorf("got authors %v and %v, want %d and nil", revisions[0].AuthorUserID, revisions[1].AuthorUserID, userID)
+	}
+
+	revisions, err = ListRevisions(ctx, TypeSite, ListOptions{Limit: 1, AfterID: second.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 1 || revisions[0].ID != first.ID {
+		t.Fatalf("got revisions %+v after %d, want [%d]", revisions, second.ID, first.ID)
+	}
+
+	count, err := CountRevisions(ctx, TypeSite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 2; count < want {
+		t.Errorf("got count %d, want at least %d", count, want)
+	}
+
+	prev, err := GetPreviousRevision(ctx, TypeSite, second.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prev == nil || prev.ID != first.ID || prev.Contents != `{"a": 1}` {
+		t.Errorf("got previous revision %+v, want %d", prev, first.ID)
+	}
+
+	if _, err := GetRevision(ctx, TypeCritical, second.ID); err != ErrRevisionNotFound {
+		t.Errorf("got error %v, want %v", err, ErrRevisionNotFound)
+	}
+}
@@ -59,6 +59,13 @@ type Client struct {
 	// RateLimit is the self-imposed rate limiter (since Bitbucket does not have a concept
 	// of rate limiting in HTTP response headers).
 	RateLimit *rate.Limiter
+
+	// oauth, if set with SetOAuth, is used to authenticate requests instead of the Token or
+	// Username/Password fields.
+	oauth *oauthConsumer
+
+	// sudo is the username of the user that requests are made on behalf of (see Sudo).
+	sudo string
 }
 
 // NewClient returns a new Bitbucket Server API client at url. If a nil
@@ -141,12 +148,45 @@ func (c *Client) RecentRepos(ctx context.Context, pageToken *PageToken) ([]*Repo
 	return resp.Values, resp.PageToken, nil
 }
 
+// Users returns the users whose username, name or email address contains the filter string (or all
+// users, if filter is empty).
+func (c *Client) Users(ctx context.Context, pageToken *PageToken, filter string) ([]*User, *PageToken, error) {
+	qry := pageToken.Values()
+	if filter != "" {
+		qry.Set("filter", filter)
+	}
+
+	u := fmt.Sprintf("rest/api/1.0/users?%s", qry.Encode())
+	req, err := http.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var resp struct {
+		*PageToken
+		Values []*User
+	}
+	err = c.do(ctx, req, &resp)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Values, resp.PageToken, nil
+}
+
 func (c *Client) do(ctx context.Context, req *http.Request, result interface{}) error {
 	req.URL = c.URL.ResolveReference(req.URL)
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
 
-	// Authenticate request, preferring token.
-	if c.Token != "" {
+	// Authenticate request, preferring OAuth and then token.
+	if c.oauth != nil {
+		if c.sudo != "" {
+			q := req.URL.Query()
+			q.Set("user_id", c.sudo)
+			req.URL.RawQuery = q.Encode()
+		}
+		if err := c.oauth.authorize(req); err != nil {
+			return err
+		}
+	} else if c.Token != "" {
 		req.Header.Set("Authorization", "Bearer "+c.Token)
 	} else if c.Username != "" || c.Password != "" {
 		req.SetBasicAuth(c.Username, c.Password)
@@ -273,6 +313,16 @@ func (r *Repo) IsPersonalRepository() bool {
 	return r.Project.Type == "PERSONAL"
 }
 
+type User struct {
+	Name         string `json:"name"`
+	EmailAddress string `json:"emailAddress"`
+	ID           int    `json:"id"`
+	DisplayName  string `json:"displayName"`
+	Active       bool   `json:"active"`
+	Slug         string `json:"slug"`
+	Type         string `json:"type"`
+}
+
 type Project struct {
 	Key    string `json:"key"`
 	ID     int    `json:"id"`
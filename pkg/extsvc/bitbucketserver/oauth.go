@@ -0,0 +1,141 @@
+package bitbucketserver
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// oauthConsumer signs requests with OAuth 1.0a (using the RSA-SHA1 signature method), as the
+// consumer of a Bitbucket Server Application Link with incoming authentication.
+type oauthConsumer struct {
+	key        string
+	signingKey *rsa.PrivateKey
+}
+
+// SetOAuth configures the client to authenticate requests with 2-legged OAuth 1.0a as the consumer
+// with the given key, instead of with the Token or Username/Password fields. The signingKey is the
+// base64-encoded PEM RSA private key of the consumer.
+func (c *Client) SetOAuth(consumerKey, signingKey string) error {
+	pemKey, err := base64.StdEncoding.DecodeString(signingKey)
+	if err != nil {
+		return errors.Wrap(err, "signingKey is not valid base64")
+	}
+	block, _ := pem.Decode(pemKey)
+	if block == nil {
+		return errors.New("signingKey does not contain a PEM-encoded private key")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		pkcs8Key, err2 := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err2 != nil {
+			return errors.Wrap(err, "signingKey is not a valid RSA private key")
+		}
+		var ok bool
+		if key, ok = pkcs8Key.(*rsa.PrivateKey); !ok {
+			return errors.New("signingKey is not an RSA private key")
+		}
+	}
+	c.oauth = &oauthConsumer{key: consumerKey, signingKey: key}
+	return nil
+}
+
+// Sudo returns a copy of the client that makes requests on behalf of the Bitbucket Server user with
+// the given username (2-legged OAuth with user impersonation). It returns an error if the client
+// isn't configured to use OAuth (with SetOAuth).
+func (c *Client) Sudo(username string) (*Client, error) {
+	if c.oauth == nil {
+		return nil, errors.New("bitbucketserver.Client: OAuth is required for user impersonation")
+	}
+	sudo := *c
+	sudo.sudo = username
+	return &sudo, nil
+}
+
+// authorize sets the OAuth Authorization header of the request.
+func (o *oauthConsumer) authorize(req *http.Request) error {
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	oauthParams := map[string]string{
+		"oauth_consumer_key":     o.key,
+		"oauth_nonce":            hex.EncodeToString(nonce),
+		"oauth_signature_method": "RSA-SHA1",
+		"oauth_timestamp":        strconv.FormatInt(time.Now().Unix(), 10),
+		"oauth_version":          "1.0",
+	}
+
+	hashed := sha1.Sum([]byte(oauthSignatureBase(req.Method, req.URL, oauthParams)))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, o.signingKey, crypto.SHA1, hashed[:])
+	if err != nil {
+		return err
+	}
+	oauthParams["oauth_signature"] = base64.StdEncoding.EncodeToString(sig)
+
+	keys := make([]string, 0, len(oauthParams))
+	for k := range oauthParams {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	header := make([]string, len(keys))
+	for i, k := range keys {
+		header[i] = fmt.Sprintf(`%s="%s"`, k, oauthPercentEncode(oauthParams[k]))
+	}
+	req.Header.Set("Authorization", "OAuth "+strings.Join(header, ", "))
+	return nil
+}
+
+// oauthSignatureBase returns the OAuth 1.0a signature base string of a request (see
+// https://tools.ietf.org/html/rfc5849#section-3.4.1).
+func oauthSignatureBase(method string, u *url.URL, oauthParams map[string]string) string {
+	var params []string
+	for k, vs := range u.Query() {
+		for _, v := range vs {
+			params = append(params, oauthPercentEncode(k)+"="+oauthPercentEncode(v))
+		}
+	}
+	for k, v := range oauthParams {
+		params = append(params, oauthPercentEncode(k)+"="+oauthPercentEncode(v))
+	}
+	// Sorting the encoded "key=value" pairs sorts them by key and then by value, because '=' sorts
+	// before all characters that can appear in encoded keys.
+	sort.Strings(params)
+
+	host := strings.ToLower(u.Host)
+	if scheme := strings.ToLower(u.Scheme); (scheme == "http" && strings.HasSuffix(host, ":80")) || (scheme == "https" && strings.HasSuffix(host, ":443")) {
+		host = host[:strings.LastIndex(host, ":")]
+	}
+	baseURL := strings.ToLower(u.Scheme) + "://" + host + u.EscapedPath()
+
+	return strings.ToUpper(method) + "&" + oauthPercentEncode(baseURL) + "&" + oauthPercentEncode(strings.Join(params, "&"))
+}
+
+// oauthPercentEncode percent-encodes s as specified by OAuth 1.0a (see
+// https://tools.ietf.org/html/rfc5849#section-3.6).
+func oauthPercentEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '.', c == '_', c == '~':
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
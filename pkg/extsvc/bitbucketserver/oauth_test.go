@@ -0,0 +1,100 @@
+package bitbucketserver
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"testing"
+)
+
+func TestOAuthSignatureBase(t *testing.T) {
+	// The example from https://tools.ietf.org/html/rfc5849#section-3.4.1.1 (with the body
+	// parameters moved to the query).
+	u, err := url.Parse("http://EXAMPLE.COM:80/request?b5=%3D%253D&a3=a&c%40=&a2=r%20b&c2=&a3=2%20q")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := oauthSignatureBase("post", u, map[string]string{
+		"oauth_consumer_key":     "9djdj82h48djs9d2",
+		"oauth_token":            "kkk9d7dh3k39sjv7",
+		"oauth_signature_method": "HMAC-SHA1",
+		"oauth_timestamp":        "137131201",
+		"oauth_nonce":            "7d8f3e4a",
+	})
+	want := "POST&http%3A%2F%2Fexample.com%2Frequest&a2%3Dr%2520b%26a3%3D2%2520q%26a3%3Da%26b5%3D%253D%25253D%26c%2540%3D%26c2%3D%26oauth_consumer_key%3D9djdj82h48djs9d2%26oauth_nonce%3D7d8f3e4a%26oauth_signature_method%3DHMAC-SHA1%26oauth_timestamp%3D137131201%26oauth_token%3Dkkk9d7dh3k39sjv7"
+	if got != want {
+		t.Errorf("got signature base\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestClient_Sudo(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var verifyErr error
+	var userID string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID = r.URL.Query().Get("user_id")
+
+		// Verify the signature with the consumer's public key.
+		params := map[string]string{}
+		for _, m := range regexp.MustCompile(`(\w+)="([^"]*)"`).FindAllStringSubmatch(r.Header.Get("Authorization"), -1) {
+			params[m[1]], _ = url.QueryUnescape(m[2])
+		}
+		sig, _ := base64.StdEncoding.DecodeString(params["oauth_signature"])
+		delete(params, "oauth_signature")
+		u := *r.URL
+		u.Scheme, u.Host = "http", r.Host
+		hashed := sha1.Sum([]byte(oauthSignatureBase(r.Method, &u, params)))
+		verifyErr = rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA1, hashed[:], sig)
+
+		_, _ = w.Write([]byte(`{"values": [], "isLastPage": true}`))
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	c := NewClient(u, nil)
+	if _, err := c.Sudo("alice"); err == nil {
+		t.Error("got no error from Sudo without OAuth")
+	}
+	signingKey := base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	if err := c.SetOAuth("sourcegraph", signingKey); err != nil {
+		t.Fatal(err)
+	}
+	sudo, err := c.Sudo("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := sudo.Repos(context.Background(), &PageToken{Limit: 10}, "permission=REPO_READ"); err != nil {
+		t.Fatal(err)
+	}
+	if verifyErr != nil {
+		t.Errorf("invalid signature: %s", verifyErr)
+	}
+	if userID != "alice" {
+		t.Errorf("got user_id %q, want %q", userID, "alice")
+	}
+
+	// The original client doesn't impersonate the user.
+	if _, _, err := c.Repos(context.Background(), &PageToken{Limit: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if verifyErr != nil {
+		t.Errorf("invalid signature: %s", verifyErr)
+	}
+	if userID != "" {
+		t.Errorf("got user_id %q, want none", userID)
+	}
+}
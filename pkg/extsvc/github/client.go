@@ -39,6 +39,11 @@ var (
 	requestCounter = metrics.NewRequestCounter("github", "Total number of requests sent to the GitHub API.")
 )
 
+func init() {
+	// github-proxy waits on the GitHub.com request budget for the requests it forwards.
+	ratelimit.RegisterProxy(githubProxyURL.String())
+}
+
 // Client is a caching GitHub API client.
 //
 // All instances use a map of rcache.Cache instances for caching (see the `repoCache` field). These
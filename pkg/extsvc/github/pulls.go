@@ -0,0 +1,47 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// PullRequestReviewComment is a review comment on the diff of a GitHub pull request.
+type PullRequestReviewComment struct {
+	ID               int64  `json:"id"`
+	InReplyToID      int64  `json:"in_reply_to_id"` // the ID of the first comment of the review thread (if a reply)
+	Path             string `json:"path"`
+	DiffHunk         string `json:"diff_hunk"` // the diff hunk ending at the commented line
+	OriginalCommitID string `json:"original_commit_id"`
+	User             struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+	} `json:"user"`
+	Body           string    `json:"body"`
+	CreatedAt      time.Time `json:"created_at"`
+	HTMLURL        string    `json:"html_url"`
+	PullRequestURL string    `json:"pull_request_url"` // the API URL of the pull request
+}
+
+// ListPullRequestReviewComments lists the review comments on all pull requests
+// in a repository, oldest first. If since is non-zero, only comments updated at
+// or after since are listed. page is the page of results to return. Pages are
+// 1-indexed (so the first call should be for page 1).
+func (c *Client) ListPullRequestReviewComments(ctx context.Context, owner, name string, since time.Time, page int) (comments []*PullRequestReviewComment, hasNextPage bool, err error) {
+	urlValues := url.Values{
+		"sort":      []string{"created"},
+		"direction": []string{"asc"},
+		"page":      []string{strconv.Itoa(page)},
+		"per_page":  []string{"100"},
+	}
+	if !since.IsZero() {
+		urlValues.Set("since", since.UTC().Format(time.RFC3339))
+	}
+	path := fmt.Sprintf("repos/%s/%s/pulls/comments?%s", owner, name, urlValues.Encode())
+	if err := c.requestGet(ctx, "", path, &comments); err != nil {
+		return nil, false, err
+	}
+	return comments, len(comments) == 100, nil
+}
@@ -0,0 +1,96 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/peterhellberg/link"
+)
+
+// MergeRequest is a GitLab merge request (equivalent to a GitHub pull request).
+type MergeRequest struct {
+	ID        int       `json:"id"`
+	IID       int       `json:"iid"`        // ID of the merge request within its project
+	ProjectID int       `json:"project_id"` // ID of the target project
+	Title     string    `json:"title"`
+	WebURL    string    `json:"web_url"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Discussion is a thread of notes (comments) on a GitLab merge request.
+type Discussion struct {
+	ID             string  `json:"id"`
+	IndividualNote bool    `json:"individual_note"` // whether the discussion is a single note that can't be replied to
+	Notes          []*Note `json:"notes"`
+}
+
+// Note is a comment in a GitLab discussion.
+type Note struct {
+	ID        int           `json:"id"`
+	Type      string        `json:"type"` // "DiffNote" for comments on the diff
+	Body      string        `json:"body"`
+	Author    User          `json:"author"`
+	CreatedAt time.Time     `json:"created_at"`
+	System    bool          `json:"system"`             // whether the note was created by GitLab (e.g. "added 1 commit")
+	Position  *NotePosition `json:"position,omitempty"` // the position of a diff note
+}
+
+// NotePosition is the position of a diff note. The commented line is NewLine
+// of NewPath in HeadSHA, or OldLine of OldPath in BaseSHA if the line was
+// removed. Lines are 1-based.
+type NotePosition struct {
+	BaseSHA  string `json:"base_sha"`
+	StartSHA string `json:"start_sha"`
+	HeadSHA  string `json:"head_sha"`
+	OldPath  string `json:"old_path"`
+	NewPath  string `json:"new_path"`
+	OldLine  *int   `json:"old_line"`
+	NewLine  *int   `json:"new_line"`
+}
+
+// ListMergeRequests lists GitLab merge requests.
+func (c *Client) ListMergeRequests(ctx context.Context, urlStr string) (mrs []*MergeRequest, nextPageURL *string, err error) {
+	if MockListMergeRequests != nil {
+		return MockListMergeRequests(c, ctx, urlStr)
+	}
+
+	req, err := http.NewRequest("GET", urlStr, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	respHeader, err := c.do(ctx, req, &mrs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Get URL to next page. See https://docs.gitlab.com/ee/api/README.html#pagination-link-header.
+	if l := link.Parse(respHeader.Get("Link"))["next"]; l != nil {
+		nextPageURL = &l.URI
+	}
+
+	return mrs, nextPageURL, nil
+}
+
+// ListMergeRequestDiscussions lists the discussions of a GitLab merge request.
+func (c *Client) ListMergeRequestDiscussions(ctx context.Context, urlStr string) (discussions []*Discussion, nextPageURL *string, err error) {
+	if MockListMergeRequestDiscussions != nil {
+		return MockListMergeRequestDiscussions(c, ctx, urlStr)
+	}
+
+	req, err := http.NewRequest("GET", urlStr, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	respHeader, err := c.do(ctx, req, &discussions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Get URL to next page. See https://docs.gitlab.com/ee/api/README.html#pagination-link-header.
+	if l := link.Parse(respHeader.Get("Link"))["next"]; l != nil {
+		nextPageURL = &l.URI
+	}
+
+	return discussions, nextPageURL, nil
+}
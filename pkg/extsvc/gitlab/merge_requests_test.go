@@ -0,0 +1,72 @@
+package gitlab
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestClient_ListMergeRequestDiscussions(t *testing.T) {
+	mock := mockHTTPResponseBody{
+		responseBody: `
+[
+	{
+		"id": "6a9c1750b37d513a43987b574953fceb50b03ce7",
+		"individual_note": false,
+		"notes": [
+			{
+				"id": 1126,
+				"type": "DiffNote",
+				"body": "discussion text",
+				"author": {"id": 1, "username": "alice"},
+				"created_at": "2018-03-03T21:54:39.668Z",
+				"system": false,
+				"position": {
+					"base_sha": "b5d6e7b1613fca24d250fa8e5bc7bcc3dd6002ef",
+					"start_sha": "7c9c2ead8a320fb7ba0b4e234bd9529a2614e306",
+					"head_sha": "4803c71e6b1833ca72b8b26ef2ecd5adc8a38031",
+					"old_path": "package.json",
+					"new_path": "package.json",
+					"old_line": null,
+					"new_line": 27
+				}
+			}
+		]
+	}
+]
+`}
+	c := newTestClient(t)
+	c.httpClient = &mock
+
+	newLine := 27
+	want := []*Discussion{{
+		ID: "6a9c1750b37d513a43987b574953fceb50b03ce7",
+		Notes: []*Note{{
+			ID:        1126,
+			Type:      "DiffNote",
+			Body:      "discussion text",
+			Author:    User{ID: 1, Username: "alice"},
+			CreatedAt: time.Date(2018, 3, 3, 21, 54, 39, 668000000, time.UTC),
+			Position: &NotePosition{
+				BaseSHA:  "b5d6e7b1613fca24d250fa8e5bc7bcc3dd6002ef",
+				StartSHA: "7c9c2ead8a320fb7ba0b4e234bd9529a2614e306",
+				HeadSHA:  "4803c71e6b1833ca72b8b26ef2ecd5adc8a38031",
+				OldPath:  "package.json",
+				NewPath:  "package.json",
+				NewLine:  &newLine,
+			},
+		}},
+	}}
+
+	discussions, nextPageURL, err := c.ListMergeRequestDiscussions(context.Background(), "projects/1/merge_requests/2/discussions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nextPageURL != nil {
+		t.Errorf("got nextPageURL %q, want nil", *nextPageURL)
+	}
+	if !reflect.DeepEqual(discussions, want) {
+		t.Errorf("got discussions %+v, want %+v", discussions, want)
+	}
+}
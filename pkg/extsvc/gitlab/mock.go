@@ -18,3 +18,9 @@ var MockGetProject func(c *Client, ctx context.Context, op GetProjectOp) (*Proje
 
 // MockListTree, if non-nil, will be called instead of Client.ListTree
 var MockListTree func(c *Client, ctx context.Context, op ListTreeOp) ([]*Tree, error)
+
+// MockListMergeRequests, if non-nil, will be called instead of Client.ListMergeRequests
+var MockListMergeRequests func(c *Client, ctx context.Context, urlStr string) (mrs []*MergeRequest, nextPageURL *string, err error)
+
+// MockListMergeRequestDiscussions, if non-nil, will be called instead of Client.ListMergeRequestDiscussions
+var MockListMergeRequestDiscussions func(c *Client, ctx context.Context, urlStr string) (discussions []*Discussion, nextPageURL *string, err error)
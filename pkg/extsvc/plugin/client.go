@@ -0,0 +1,187 @@
+// Package plugin implements the repository listing plugin protocol, which lets Sourcegraph sync
+// the repositories of Git hosts it has no native integration for.
+//
+// A plugin is either an HTTP endpoint or a local command. Command plugins can only run the
+// executables allowed by SRC_PLUGIN_ALLOWED_COMMANDS in repo-updater's environment. It lists the repositories of a Git host
+// one page at a time: the first page is requested without a cursor, and every following page with
+// the cursor returned as Next by the previous one, until a page with no Next cursor is returned.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/pkg/env"
+	"github.com/sourcegraph/sourcegraph/pkg/httpcli"
+)
+
+// ServiceType is the external service type of repositories listed by plugins.
+const ServiceType = "plugin"
+
+// CursorEnv is the environment variable that holds the cursor of the page a command plugin lists.
+const CursorEnv = "SRC_PLUGIN_CURSOR"
+
+// allowedCommands is the set of executables that command plugins may run.
+//
+// 🚨 SECURITY: Command plugins run in repo-updater, and external services can be configured by any
+// site admin, so only the executables allowed by whoever deploys Sourcegraph may be run.
+var allowedCommands = parseAllowedCommands(env.Get("SRC_PLUGIN_ALLOWED_COMMANDS", "", `Comma-separated list of the executables that command plugins may run, exactly as they appear in the "command" of the plugin configuration (e.g. "/usr/local/bin/list-repos"). Command plugins are disabled if empty.`))
+
+func parseAllowedCommands(s string) map[string]bool {
+	m := map[string]bool{}
+	for _, executable := range strings.Split(s, ",") {
+		if executable = strings.TrimSpace(executable); executable != "" {
+			m[executable] = true
+		}
+	}
+	return m
+}
+
+// Repo is a repository listed by a plugin.
+type Repo struct {
+	// Name is the name of the repository on Sourcegraph, e.g. "git.example.com/foo/bar".
+	Name string `json:"name"`
+	// ExternalID uniquely and stably identifies the repository on the Git host, so that
+	// renamed repositories can be told apart from new ones.
+	ExternalID string `json:"externalID"`
+	// CloneURL is the URL that the repository is cloned from.
+	CloneURL string `json:"cloneURL"`
+
+	Description string `json:"description,omitempty"`
+	Fork        bool   `json:"fork,omitempty"`
+	Archived    bool   `json:"archived,omitempty"`
+
+	// Metadata is arbitrary metadata about the repository, which is stored as is.
+	Metadata json.RawMessage `json:"metadata,omitempty"`
+}
+
+// Page is a page of the repositories listed by a plugin.
+type Page struct {
+	Repos []*Repo `json:"repos"`
+	// Next is the cursor of the next page, or empty if this is the last page.
+	Next string `json:"next,omitempty"`
+}
+
+// Client lists the repositories of a plugin. Exactly one of URL and Command must be set.
+type Client struct {
+	// URL is the URL of an HTTP plugin.
+	URL *url.URL
+	// Token is sent as a bearer token in the requests to an HTTP plugin.
+	Token string
+	// Command is the executable and the arguments of a command plugin.
+	Command []string
+
+	httpClient httpcli.Doer
+}
+
+// NewHTTPClient returns a client of the HTTP plugin at the given URL. If a nil httpClient is
+// provided, http.DefaultClient will be used.
+func NewHTTPClient(u *url.URL, token string, httpClient httpcli.Doer) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{URL: u, Token: token, httpClient: httpClient}
+}
+
+// NewCommandClient returns a client of the command plugin with the given executable and arguments.
+func NewCommandClient(command []string) *Client {
+	return &Client{Command: command}
+}
+
+// ListRepos returns the page of repositories at the given cursor, which is empty for the first page.
+func (c *Client) ListRepos(ctx context.Context, cursor string) (*Page, error) {
+	var (
+		data []byte
+		err  error
+	)
+
+	switch {
+	case c.URL != nil:
+		data, err = c.get(ctx, cursor)
+	case len(c.Command) > 0:
+		data, err = c.run(ctx, cursor)
+	default:
+		return nil, errors.New("plugin has neither a URL nor a command")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var page Page
+	if err := json.Unmarshal(data, &page); err != nil {
+		return nil, errors.Wrap(err, "invalid plugin response")
+	}
+
+	for i, r := range page.Repos {
+		if r == nil || r.Name == "" || r.ExternalID == "" || r.CloneURL == "" {
+			return nil, fmt.Errorf("invalid plugin response: repos.%d: name, externalID and cloneURL are required", i)
+		}
+	}
+
+	return &page, nil
+}
+
+func (c *Client) get(ctx context.Context, cursor string) ([]byte, error) {
+	u := *c.URL
+	if cursor != "" {
+		q := u.Query()
+		q.Set("cursor", cursor)
+		u.RawQuery = q.Encode()
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		// The URL is omitted from the error since it may contain credentials.
+		return nil, fmt.Errorf("plugin request failed with status %d: %s", resp.StatusCode, bytes.TrimSpace(data))
+	}
+
+	return data, nil
+}
+
+func (c *Client) run(ctx context.Context, cursor string) ([]byte, error) {
+	if !allowedCommands[c.Command[0]] {
+		return nil, fmt.Errorf("plugin command %q is not allowed (the executable must be listed in SRC_PLUGIN_ALLOWED_COMMANDS in repo-updater's environment)", c.Command[0])
+	}
+
+	cmd := exec.CommandContext(ctx, c.Command[0], c.Command[1:]...)
+	cmd.Env = append(os.Environ(), CursorEnv+"="+cursor)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, errors.Wrapf(err, "plugin command %q failed: %s", c.Command[0], bytes.TrimSpace(stderr.Bytes()))
+	}
+
+	return out, nil
+}
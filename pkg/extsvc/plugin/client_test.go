@@ -0,0 +1,94 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestClient_ListRepos(t *testing.T) {
+	pages := map[string]*Page{
+		"": {
+			Repos: []*Repo{{Name: "git.example.com/foo", ExternalID: "1", CloneURL: "https://git.example.com/foo.git"}},
+			Next:  "2",
+		},
+		"2": {
+			Repos: []*Repo{{Name: "git.example.com/bar", ExternalID: "2", CloneURL: "https://git.example.com/bar.git", Fork: true}},
+		},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if have, want := r.Header.Get("Authorization"), "Bearer secret"; have != want {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		page, ok := pages[r.URL.Query().Get("cursor")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(page)
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	ctx := context.Background()
+
+	t.Run("pages", func(t *testing.T) {
+		cli := NewHTTPClient(u, "secret", nil)
+		for _, cursor := range []string{"", "2"} {
+			page, err := cli.ListRepos(ctx, cursor)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(page, pages[cursor]) {
+				t.Errorf("cursor %q: have %+v, want %+v", cursor, page, pages[cursor])
+			}
+		}
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		cli := NewHTTPClient(u, "", nil)
+		if _, err := cli.ListRepos(ctx, ""); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("invalid repo", func(t *testing.T) {
+		pages["invalid"] = &Page{Repos: []*Repo{{Name: "git.example.com/baz"}}}
+		cli := NewHTTPClient(u, "secret", nil)
+		if _, err := cli.ListRepos(ctx, "invalid"); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+}
+
+func TestClient_ListRepos_Command(t *testing.T) {
+	orig := allowedCommands
+	defer func() { allowedCommands = orig }()
+	allowedCommands = parseAllowedCommands("/bin/true, sh")
+
+	cli := NewCommandClient([]string{"sh", "-c", `printf '{"repos": [], "next": "%s-next"}' "$` + CursorEnv + `"`})
+
+	page, err := cli.ListRepos(context.Background(), "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := page.Next, "abc-next"; have != want {
+		t.Errorf("have next cursor %q, want %q", have, want)
+	}
+
+	cli = NewCommandClient([]string{"sh", "-c", "echo boom >&2; exit 1"})
+	if _, err := cli.ListRepos(context.Background(), ""); err == nil {
+		t.Fatal("expected error")
+	}
+
+	cli = NewCommandClient([]string{"bash", "-c", `echo '{"repos": []}'`})
+	if _, err := cli.ListRepos(context.Background(), ""); err == nil {
+		t.Fatal("expected error for an executable that is not allowed")
+	}
+}
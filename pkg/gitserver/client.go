@@ -677,6 +677,32 @@ func (c *Client) Remove(ctx context.Context, repo api.RepoName) error {
 	return nil
 }
 
+// Rename moves the clone of a renamed repository to its new name, so that it
+// doesn't need to be recloned. If the new name is stored on a different
+// gitserver shard, the old clone is removed instead and the repository will
+// be cloned under its new name when next requested.
+func (c *Client) Rename(ctx context.Context, from, to api.RepoName) error {
+	if c.addrForRepo(ctx, from) != c.addrForRepo(ctx, to) {
+		return c.Remove(ctx, from)
+	}
+
+	req := &protocol.RepoRenameRequest{
+		From: from,
+		To:   to,
+	}
+	resp, err := c.httpPost(ctx, from, "rename", req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		// best-effort inclusion of body in error message
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 200))
+		return &url.Error{URL: resp.Request.URL.String(), Op: "RepoRename", Err: fmt.Errorf("RepoRename: http status %d: %s", resp.StatusCode, string(body))}
+	}
+	return nil
+}
+
 // httpPost performs a POST request to a gitserver, sharding based on the given
 // repo name (the repo name is otherwise not used).
 func (c *Client) httpPost(ctx context.Context, repo api.RepoName, method string, payload interface{}) (resp *http.Response, err error) {
@@ -46,6 +46,23 @@ type RepoUpdateRequest struct {
 	Since time.Duration `json:"since"` // debounce interval for queries, used only with request-repo-update
 }
 
+// RepoUpdateErrorKind classifies the errors reported by repo updates, so that callers can
+// tell repos which can't be updated at all apart from transient failures.
+type RepoUpdateErrorKind string
+
+const (
+	// RepoUpdateErrorNotCloneable is the kind of error of repos which can't be cloned
+	// or fetched, e.g. because they were deleted upstream.
+	RepoUpdateErrorNotCloneable RepoUpdateErrorKind = "not-cloneable"
+	// RepoUpdateErrorAuth is the kind of error of repos whose credentials are missing
+	// or were rejected by the code host.
+	RepoUpdateErrorAuth RepoUpdateErrorKind = "auth"
+	// RepoUpdateErrorTimeout is the kind of error of updates which timed out.
+	RepoUpdateErrorTimeout RepoUpdateErrorKind = "timeout"
+	// RepoUpdateErrorOther is the kind of all other errors.
+	RepoUpdateErrorOther RepoUpdateErrorKind = "other"
+)
+
 // RepoUpdateResponse returns meta information of the repo enqueued for
 // update.
 //
@@ -55,9 +72,10 @@ type RepoUpdateResponse struct {
 	CloneInProgress bool
 	LastFetched     *time.Time
 	LastChanged     *time.Time
-	Error           string // an error reported by the update, as opposed to a protocol error
-	QueueCap        int    // size of the clone queue
-	QueueLen        int    // current clone operations
+	Error           string              // an error reported by the update, as opposed to a protocol error
+	ErrorKind       RepoUpdateErrorKind `json:",omitempty"` // the classification of Error
+	QueueCap        int                 // size of the clone queue
+	QueueLen        int                 // current clone operations
 	// Following items likely provided only if the request specified waiting.
 	Received *time.Time // time request was received by handler function
 	Started  *time.Time // time request actually started processing
@@ -129,6 +147,15 @@ type RepoDeleteRequest struct {
 	Repo api.RepoName
 }
 
+// RepoRenameRequest is a request to move a repository clone on gitserver
+// after the repository was renamed on its code host.
+type RepoRenameRequest struct {
+	// From is the previous name of the repository.
+	From api.RepoName
+	// To is the new name of the repository.
+	To api.RepoName
+}
+
 // RepoInfo is the information requests about a single repository
 // via a RepoInfoRequest.
 type RepoInfo struct {
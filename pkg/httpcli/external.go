@@ -0,0 +1,77 @@
+package httpcli
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// NewExternalClient returns an HTTP client for requests to URLs supplied by
+// users (such as webhook URLs in their settings). It refuses to connect to
+// loopback, private, and link-local addresses, so that users can't use it to
+// send requests to internal services, and it doesn't follow redirects.
+//
+// It doesn't use the proxy in the environment, because the addresses would
+// then be checked against the proxy's address instead of the destination's.
+func NewExternalClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext: (&net.Dialer{
+				Timeout: timeout,
+				Control: RejectInternalAddrs,
+			}).DialContext,
+			TLSHandshakeTimeout: timeout,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// RejectInternalAddrs is a net.Dialer Control function that rejects
+// connections to internal addresses (see IsInternalIP). It is called with the
+// resolved address, so it can't be bypassed with a hostname that resolves to
+// an internal address.
+func RejectInternalAddrs(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("address %q is not an IP address", host)
+	}
+	if IsInternalIP(ip) {
+		return fmt.Errorf("address %s is not a public IP address", ip)
+	}
+	return nil
+}
+
+var privateNetworks = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "100.64.0.0/10", "fc00::/7"} {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+// IsInternalIP reports whether ip is a loopback, private, link-local, or
+// unspecified address.
+func IsInternalIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	for _, n := range privateNetworks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,439 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sourcegraph/sourcegraph/pkg/env"
+	"github.com/sourcegraph/sourcegraph/pkg/httpcli"
+	"github.com/sourcegraph/sourcegraph/pkg/rcache"
+	log15 "gopkg.in/inconshreveable/log15.v2"
+)
+
+var (
+	budgetRequestsPerHour, _    = strconv.Atoi(env.Get("SRC_CODE_HOST_REQUESTS_PER_HOUR", "5000", "Shared budget of requests per hour made to each code host with each credential by all Sourcegraph services."))
+	budgetRequestsPerHourByHost = parseRequestsPerHourByHost(env.Get("SRC_CODE_HOST_REQUESTS_PER_HOUR_BY_HOST", "", `Comma-separated overrides of SRC_CODE_HOST_REQUESTS_PER_HOUR for individual code hosts (e.g. "github.com=5000,gitlab.example.com=600").`))
+	budgetInteractiveReserve, _ = strconv.ParseFloat(env.Get("SRC_CODE_HOST_INTERACTIVE_RESERVE", "0.2", "Fraction of each code host request budget reserved for interactive requests."), 64)
+)
+
+// Priority is the priority of a request made against a Budget.
+type Priority int
+
+const (
+	// PriorityBackground is the priority of periodic background operations,
+	// like syncing repositories. It is the default priority.
+	PriorityBackground Priority = iota
+	// PriorityInteractive is the priority of operations a user is waiting on,
+	// like looking up a repository. Interactive requests may consume the part
+	// of a Budget reserved for them.
+	PriorityInteractive
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityInteractive:
+		return "interactive"
+	default:
+		return "background"
+	}
+}
+
+type contextKey int
+
+const (
+	priorityKey contextKey = iota
+	consumerKey
+)
+
+// WithPriority returns a copy of ctx for which requests are made with the given priority.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey, p)
+}
+
+// PriorityFromContext returns the request priority stored in ctx, defaulting to PriorityBackground.
+func PriorityFromContext(ctx context.Context) Priority {
+	p, _ := ctx.Value(priorityKey).(Priority)
+	return p
+}
+
+// WithConsumer returns a copy of ctx whose requests are accounted to the named consumer (e.g.
+// "repoLookup") in budget metrics.
+func WithConsumer(ctx context.Context, consumer string) context.Context {
+	return context.WithValue(ctx, consumerKey, consumer)
+}
+
+// ConsumerFromContext returns the consumer stored in ctx, or "" if there is none.
+func ConsumerFromContext(ctx context.Context) string {
+	c, _ := ctx.Value(consumerKey).(string)
+	return c
+}
+
+// bucket is the token bucket backing a Budget. It is satisfied by *rcache.TokenBucket.
+type bucket interface {
+	Capacity() int
+	Take(n, reserve int) (wait time.Duration, remaining int, err error)
+}
+
+// A Budget is a request budget for a single external service that is shared by all the processes
+// talking to it (sources, authz providers, gitserver clones, etc.), as opposed to a Monitor, which
+// only observes the rate limit of a single API client.
+//
+// Part of the budget is reserved for interactive requests, so that user facing lookups are not
+// starved by background syncs.
+type Budget struct {
+	// Name identifies the external service and credential the budget is for (e.g. "github.com" or
+	// "github.com#0123456789abcdef", see BudgetName and CredentialBudgetName).
+	Name string
+	// Reserve is the fraction of the budget's capacity that only interactive requests may consume.
+	Reserve float64
+
+	bucket bucket
+}
+
+// NewBudget returns a Budget named name that allows requestsPerHour requests per hour. A
+// non-positive requestsPerHour returns an unlimited Budget.
+func NewBudget(name string, requestsPerHour int, reserve float64) *Budget {
+	if requestsPerHour <= 0 {
+		return &Budget{Name: name}
+	}
+	return &Budget{
+		Name:    name,
+		Reserve: reserve,
+		bucket:  rcache.NewTokenBucket("budget:"+name, requestsPerHour, float64(requestsPerHour)/3600),
+	}
+}
+
+// Wait blocks until cost requests may be made against the budget with the priority stored in ctx,
+// or until ctx is done.
+//
+// If the shared budget can't be reached, Wait logs a warning and returns without waiting, since
+// failing every request to a code host is worse than exceeding its rate limit.
+func (b *Budget) Wait(ctx context.Context, cost int) error {
+	if b.bucket == nil {
+		return nil
+	}
+
+	p := PriorityFromContext(ctx)
+
+	reserve := 0
+	if p < PriorityInteractive {
+		reserve = int(float64(b.bucket.Capacity()) * b.Reserve)
+	}
+
+	labels := prometheus.Labels{
+		"budget":   b.Name,
+		"consumer": consumerOrDefault(ConsumerFromContext(ctx)),
+		"priority": p.String(),
+	}
+
+	start := time.Now()
+	defer func() {
+		budgetWaitDuration.With(labels).Observe(time.Since(start).Seconds())
+	}()
+
+	for {
+		wait, remaining, err := b.bucket.Take(cost, reserve)
+		if err != nil {
+			log15.Warn("ratelimit: failed to take from budget, proceeding without it", "budget", b.Name, "error", err)
+			return nil
+		}
+
+		budgetRemaining.WithLabelValues(b.Name).Set(float64(remaining))
+
+		if wait == 0 {
+			budgetConsumed.With(labels).Add(float64(cost))
+			return nil
+		}
+
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func consumerOrDefault(c string) string {
+	if c == "" {
+		return "unknown"
+	}
+	return c
+}
+
+// Budgets is a registry of Budgets keyed by external service name.
+type Budgets struct {
+	mu      sync.Mutex
+	budgets map[string]*Budget
+	create  func(name string) *Budget
+}
+
+// NewBudgets returns a registry which lazily creates Budgets with the given constructor.
+func NewBudgets(create func(name string) *Budget) *Budgets {
+	return &Budgets{budgets: map[string]*Budget{}, create: create}
+}
+
+// Get returns the Budget of the named external service, creating it if needed.
+func (bs *Budgets) Get(name string) *Budget {
+	name = strings.ToLower(name)
+
+	bs.mu.Lock()
+	defer bs.mu.Unlock()
+
+	b, ok := bs.budgets[name]
+	if !ok {
+		b = bs.create(name)
+		bs.budgets[name] = b
+	}
+	return b
+}
+
+// DefaultBudgets is the registry of Budgets shared by all code host API clients and git clones.
+// Budgets are keyed by code host and credential (see RequestBudgetName and RemoteBudgetName), since
+// code hosts enforce their rate limits per credential.
+var DefaultBudgets = NewBudgets(func(name string) *Budget {
+	return NewBudget(name, requestsPerHour(name), budgetInteractiveReserve)
+})
+
+// requestsPerHour returns the configured number of requests per hour of the DefaultBudgets budget
+// named name.
+func requestsPerHour(name string) int {
+	host := strings.SplitN(name, "#", 2)[0]
+	if n, ok := budgetRequestsPerHourByHost[host]; ok {
+		return n
+	}
+	return budgetRequestsPerHour
+}
+
+// parseRequestsPerHourByHost parses the value of SRC_CODE_HOST_REQUESTS_PER_HOUR_BY_HOST. Invalid
+// entries are logged and ignored.
+func parseRequestsPerHourByHost(s string) map[string]int {
+	m := map[string]int{}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			log15.Warn("ratelimit: ignoring invalid SRC_CODE_HOST_REQUESTS_PER_HOUR_BY_HOST entry (want host=requestsPerHour)", "entry", entry)
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil {
+			log15.Warn("ratelimit: ignoring invalid SRC_CODE_HOST_REQUESTS_PER_HOUR_BY_HOST entry (want host=requestsPerHour)", "entry", entry, "error", err)
+			continue
+		}
+		m[strings.ToLower(strings.TrimSpace(parts[0]))] = n
+	}
+	return m
+}
+
+// apiHosts maps the host names of code host APIs that are not served from the code host's own host
+// name to the code host's host name, so that API requests and git clones share a budget.
+var apiHosts = map[string]string{
+	"api.github.com": "github.com",
+}
+
+// BudgetName returns the name of the code host that rawURL points to, or "" if rawURL doesn't point
+// to a code host (e.g., a local path). rawURL may be an API URL or a git remote URL, including SSH
+// and SCP-style (e.g., "git@github.com:foo/bar.git") remotes.
+//
+// Code hosts are named after their host name, without the port, so that requests to their API,
+// their web host name and their SSH and HTTPS git remotes all share one budget per credential.
+func BudgetName(rawURL string) string {
+	host := remoteHostname(rawURL)
+	if h, ok := apiHosts[host]; ok {
+		return h
+	}
+	return host
+}
+
+// CredentialBudgetName returns the name of the DefaultBudgets budget of requests to the code host
+// named name (see BudgetName) that are authenticated with credential. Requests without a
+// credential share the code host's unauthenticated budget.
+//
+// The credential is hashed, so that it isn't stored in budget keys or metric labels.
+func CredentialBudgetName(name, credential string) string {
+	if name == "" || credential == "" {
+		return name
+	}
+	sum := sha256.Sum256([]byte(credential))
+	return name + "#" + hex.EncodeToString(sum[:8])
+}
+
+// RequestBudgetName returns the name of the DefaultBudgets budget of req, keyed by its code host and
+// the credential it is authenticated with, or "" if req isn't sent to a code host.
+func RequestBudgetName(req *http.Request) string {
+	return CredentialBudgetName(BudgetName(req.URL.String()), requestCredential(req))
+}
+
+// RemoteBudgetName returns the name of the DefaultBudgets budget of the git remote rawURL, keyed by
+// its code host and the credential in its user info (if any), or "" if rawURL doesn't point to a
+// code host.
+func RemoteBudgetName(rawURL string) string {
+	var credential string
+	if strings.Contains(rawURL, "://") {
+		if u, err := url.Parse(rawURL); err == nil && u.User != nil {
+			credential = u.User.String()
+		}
+	}
+	return CredentialBudgetName(BudgetName(rawURL), credential)
+}
+
+// requestCredential returns the credential that req is authenticated with, or "" if it has none.
+func requestCredential(req *http.Request) string {
+	if v := req.Header.Get("Authorization"); v != "" {
+		return v
+	}
+	if v := req.Header.Get("Private-Token"); v != "" { // GitLab
+		return v
+	}
+	if req.URL.User != nil {
+		return req.URL.User.String()
+	}
+	if v := req.URL.Query().Get("client_id"); v != "" { // GitHub OAuth application
+		return v
+	}
+	return ""
+}
+
+// remoteHostname returns the lowercased host name of the URL or SCP-style git remote rawURL.
+func remoteHostname(rawURL string) string {
+	if strings.Contains(rawURL, "://") {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return ""
+		}
+		return strings.ToLower(u.Hostname())
+	}
+
+	// Like git, treat "[user@]host:path" as an SCP-style remote if there is no slash before the
+	// first colon.
+	i := strings.Index(rawURL, ":")
+	if i <= 0 || strings.Contains(rawURL[:i], "/") {
+		return ""
+	}
+	host := rawURL[:i]
+	if j := strings.LastIndex(host, "@"); j >= 0 {
+		host = host[j+1:]
+	}
+	return strings.ToLower(strings.Trim(host, "[]"))
+}
+
+// Headers with which requests sent to a proxy registered with RegisterProxy carry their priority
+// and consumer, so that the proxy can wait on the code host's budget on their behalf.
+const (
+	priorityHeader = "X-Sourcegraph-Request-Priority"
+	consumerHeader = "X-Sourcegraph-Request-Consumer"
+)
+
+var (
+	proxiesMu sync.RWMutex
+	proxies   = map[string]bool{} // host names of proxies
+)
+
+// RegisterProxy registers the internal proxy at proxyURL (such as github-proxy), which forwards
+// requests to a code host and waits on the code host's budget for each request (see
+// ProxiedRequestContext). BudgetMiddleware doesn't wait on a budget for requests sent to the
+// proxy, so that they are not counted twice.
+func RegisterProxy(proxyURL string) {
+	proxiesMu.Lock()
+	defer proxiesMu.Unlock()
+	proxies[remoteHostname(proxyURL)] = true
+}
+
+func isProxy(host string) bool {
+	proxiesMu.RLock()
+	defer proxiesMu.RUnlock()
+	return proxies[strings.ToLower(host)]
+}
+
+// ProxiedRequestContext returns a copy of the context of the request r received by a proxy
+// registered with RegisterProxy, with the priority and consumer of the client that sent it. The
+// consumer defaults to the given consumer.
+func ProxiedRequestContext(r *http.Request, consumer string) context.Context {
+	ctx := r.Context()
+	if r.Header.Get(priorityHeader) == PriorityInteractive.String() {
+		ctx = WithPriority(ctx, PriorityInteractive)
+	}
+	if c := r.Header.Get(consumerHeader); c != "" {
+		consumer = c
+	}
+	return WithConsumer(ctx, consumer)
+}
+
+// StripProxyHeaders removes the headers that carry the priority and consumer of a proxied request
+// (see ProxiedRequestContext) from h, so that they aren't forwarded to the code host.
+func StripProxyHeaders(h http.Header) {
+	h.Del(priorityHeader)
+	h.Del(consumerHeader)
+}
+
+// BudgetMiddleware returns an httpcli.Middleware which waits on the DefaultBudgets budget of each
+// request's code host and credential before sending it. Requests are accounted to the consumer stored in their
+// context, falling back to the given default consumer.
+func BudgetMiddleware(consumer string) httpcli.Middleware {
+	return func(cli httpcli.Doer) httpcli.Doer {
+		return httpcli.DoerFunc(func(req *http.Request) (*http.Response, error) {
+			ctx := req.Context()
+			if ConsumerFromContext(ctx) == "" {
+				ctx = WithConsumer(ctx, consumer)
+			}
+
+			if isProxy(req.URL.Hostname()) {
+				// The proxy waits on the budget.
+				req.Header.Set(priorityHeader, PriorityFromContext(ctx).String())
+				req.Header.Set(consumerHeader, ConsumerFromContext(ctx))
+				return cli.Do(req)
+			}
+
+			if name := RequestBudgetName(req); name != "" {
+				if err := DefaultBudgets.Get(name).Wait(ctx, 1); err != nil {
+					return nil, err
+				}
+			}
+
+			return cli.Do(req)
+		})
+	}
+}
+
+var (
+	budgetConsumed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "src",
+		Subsystem: "ratelimit",
+		Name:      "budget_consumed_total",
+		Help:      "Number of requests taken from code host request budgets.",
+	}, []string{"budget", "consumer", "priority"})
+
+	budgetWaitDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "src",
+		Subsystem: "ratelimit",
+		Name:      "budget_wait_seconds",
+		Help:      "Time spent waiting on code host request budgets.",
+		Buckets:   []float64{0.01, 0.1, 1, 5, 15, 60, 300, 900},
+	}, []string{"budget", "consumer", "priority"})
+
+	budgetRemaining = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "src",
+		Subsystem: "ratelimit",
+		Name:      "budget_remaining",
+		Help:      "Number of requests remaining in code host request budgets.",
+	}, []string{"budget"})
+)
+
+func init() {
+	prometheus.MustRegister(budgetConsumed)
+	prometheus.MustRegister(budgetWaitDuration)
+	prometheus.MustRegister(budgetRemaining)
+}
@@ -0,0 +1,174 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/pkg/httpcli"
+)
+
+type fakeBucket struct {
+	capacity int
+	tokens   int
+}
+
+func (b *fakeBucket) Capacity() int { return b.capacity }
+
+func (b *fakeBucket) Take(n, reserve int) (time.Duration, int, error) {
+	if b.tokens-n < reserve {
+		return time.Hour, b.tokens, nil
+	}
+	b.tokens -= n
+	return 0, b.tokens, nil
+}
+
+func TestBudget_Wait(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		priority Priority
+		tokens   int
+		wantErr  bool
+	}{
+		{name: "background with budget", priority: PriorityBackground, tokens: 50},
+		{name: "background in reserve", priority: PriorityBackground, tokens: 20, wantErr: true},
+		{name: "interactive in reserve", priority: PriorityInteractive, tokens: 20},
+		{name: "interactive exhausted", priority: PriorityInteractive, tokens: 0, wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			b := &Budget{
+				Name:    "example.com",
+				Reserve: 0.2,
+				bucket:  &fakeBucket{capacity: 100, tokens: tc.tokens},
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+			defer cancel()
+
+			err := b.Wait(WithPriority(ctx, tc.priority), 1)
+			if have, want := err != nil, tc.wantErr; have != want {
+				t.Fatalf("have err: %v, want err: %t", err, want)
+			}
+		})
+	}
+}
+
+func TestBudgetName(t *testing.T) {
+	for rawURL, want := range map[string]string{
+		"https://github.com/foo/bar":           "github.com",
+		"https://api.github.com/repos/foo/bar": "github.com",
+		"https://GitHub.com:443/foo/bar.git":   "github.com",
+		"ssh://git@github.com:22/foo/bar.git":  "github.com",
+		"git@github.com:foo/bar.git":           "github.com",
+		"github.com:foo/bar":                   "github.com",
+		"https://ghe.example.com/api/v3/repos": "ghe.example.com",
+		"/data/repos/foo":                      "",
+		"file:///data/repos/foo":               "",
+		"foo/bar:baz":                          "",
+	} {
+		if got := BudgetName(rawURL); got != want {
+			t.Errorf("%s: got %q, want %q", rawURL, got, want)
+		}
+	}
+}
+
+func TestRequestBudgetName(t *testing.T) {
+	newRequest := func(rawURL string, header http.Header) *http.Request {
+		req, err := http.NewRequest("GET", rawURL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		return req
+	}
+
+	unauthenticated := RequestBudgetName(newRequest("https://api.github.com/repos/foo/bar", nil))
+	if unauthenticated != "github.com" {
+		t.Errorf("got %q, want %q", unauthenticated, "github.com")
+	}
+
+	tokenA := RequestBudgetName(newRequest("https://api.github.com/repos/foo/bar", http.Header{"Authorization": {"token a"}}))
+	tokenA2 := RequestBudgetName(newRequest("https://github.com/foo/bar.git", http.Header{"Authorization": {"token a"}}))
+	tokenB := RequestBudgetName(newRequest("https://api.github.com/repos/foo/bar", http.Header{"Authorization": {"token b"}}))
+	if tokenA != tokenA2 {
+		t.Errorf("got different budgets %q and %q for the same credential", tokenA, tokenA2)
+	}
+	if tokenA == tokenB || tokenA == unauthenticated {
+		t.Errorf("got the same budget %q for different credentials", tokenA)
+	}
+	if !strings.HasPrefix(tokenA, "github.com#") || strings.Contains(tokenA, "token a") {
+		t.Errorf("got %q, want the code host followed by a hash of the credential", tokenA)
+	}
+
+	gitlab := RequestBudgetName(newRequest("https://gitlab.example.com/api/v4/projects", http.Header{"Private-Token": {"a"}}))
+	clientID := RequestBudgetName(newRequest("https://api.github.com/repos/foo/bar?client_id=a&client_secret=b", nil))
+	if gitlab == "gitlab.example.com" || clientID == "github.com" {
+		t.Errorf("got unauthenticated budgets %q and %q for authenticated requests", gitlab, clientID)
+	}
+
+	if got, want := RemoteBudgetName("https://x-oauth-basic:a@github.com/foo/bar.git"), CredentialBudgetName("github.com", "x-oauth-basic:a"); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := RemoteBudgetName("git@github.com:foo/bar.git"); got != "github.com" {
+		t.Errorf("got %q, want %q", got, "github.com")
+	}
+}
+
+func TestRequestsPerHour(t *testing.T) {
+	orig, origByHost := budgetRequestsPerHour, budgetRequestsPerHourByHost
+	defer func() { budgetRequestsPerHour, budgetRequestsPerHourByHost = orig, origByHost }()
+
+	budgetRequestsPerHour = 5000
+	budgetRequestsPerHourByHost = parseRequestsPerHourByHost(" GitLab.example.com=600,invalid,ghe.example.com=x,bitbucket.example.com = 0")
+	if want := map[string]int{"gitlab.example.com": 600, "bitbucket.example.com": 0}; !reflect.DeepEqual(budgetRequestsPerHourByHost, want) {
+		t.Errorf("got %v, want %v", budgetRequestsPerHourByHost, want)
+	}
+
+	for name, want := range map[string]int{
+		"github.com":                  5000,
+		"github.com#0123456789abcdef": 5000,
+		"gitlab.example.com":          600,
+		"gitlab.example.com#01234567": 600,
+		"bitbucket.example.com":       0,
+	} {
+		if got := requestsPerHour(name); got != want {
+			t.Errorf("%s: got %d, want %d", name, got, want)
+		}
+	}
+}
+
+func TestBudgetMiddleware_proxy(t *testing.T) {
+	RegisterProxy("http://test-proxy")
+
+	var got context.Context
+	proxy := httpcli.DoerFunc(func(req *http.Request) (*http.Response, error) {
+		// Simulate the proxy receiving the request.
+		got = ProxiedRequestContext(req, "proxy")
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	req, err := http.NewRequest("GET", "http://test-proxy/repos/foo/bar", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := WithPriority(context.Background(), PriorityInteractive)
+	if _, err := BudgetMiddleware("test")(proxy).Do(req.WithContext(ctx)); err != nil {
+		t.Fatal(err)
+	}
+	if p := PriorityFromContext(got); p != PriorityInteractive {
+		t.Errorf("got priority %s, want %s", p, PriorityInteractive)
+	}
+	if c := ConsumerFromContext(got); c != "test" {
+		t.Errorf("got consumer %q, want %q", c, "test")
+	}
+
+	StripProxyHeaders(req.Header)
+	if len(req.Header) != 0 {
+		t.Errorf("got headers %v, want none", req.Header)
+	}
+}
@@ -0,0 +1,93 @@
+package rcache
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/garyburd/redigo/redis"
+)
+
+// TokenBucket is a redis backed token bucket rate limiter. It is shared by
+// every process which uses the same name, so it can be used to enforce a
+// budget across replicas and services.
+type TokenBucket struct {
+	name     string
+	capacity int
+	rate     float64 // tokens per second
+}
+
+// NewTokenBucket returns a TokenBucket identified by name, which holds at
+// most capacity tokens and is refilled at rate tokens per second.
+func NewTokenBucket(name string, capacity int, rate float64) *TokenBucket {
+	return &TokenBucket{
+		name:     name,
+		capacity: capacity,
+		rate:     rate,
+	}
+}
+
+// Capacity returns the maximum number of tokens the bucket holds.
+func (b *TokenBucket) Capacity() int { return b.capacity }
+
+// Take atomically removes n tokens from the bucket, as long as at least
+// reserve tokens are left afterwards. If that is not possible no tokens are
+// taken and the returned wait is the time until enough tokens will have been
+// refilled. remaining is the number of tokens left in the bucket.
+func (b *TokenBucket) Take(n, reserve int) (wait time.Duration, remaining int, err error) {
+	c := pool.Get()
+	defer c.Close()
+
+	vals, err := redis.Ints(takeTokensScript.Do(c, b.rkey(), n, reserve, b.capacity, b.rate))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if len(vals) != 2 {
+		return 0, 0, fmt.Errorf("rcache: unexpected token bucket reply %v", vals)
+	}
+
+	return time.Duration(vals[0]) * time.Millisecond, vals[1], nil
+}
+
+// rkey generates the actual key we use on redis.
+func (b *TokenBucket) rkey() string {
+	return fmt.Sprintf("%s:tokenbucket:%s", globalPrefix, b.name)
+}
+
+// takeTokensScript refills and takes tokens from the bucket stored in a
+// redis hash. Redis' clock is used so that all clients agree on the time.
+// It replies with the milliseconds to wait (0 if the tokens were taken) and
+// the number of tokens remaining.
+var takeTokensScript = redis.NewScript(1, `
+redis.replicate_commands()
+
+local n = tonumber(ARGV[1])
+local reserve = tonumber(ARGV[2])
+local capacity = tonumber(ARGV[3])
+local rate = tonumber(ARGV[4])
+
+local t = redis.call('TIME')
+local now = tonumber(t[1]) + tonumber(t[2]) / 1000000
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = capacity
+	ts = now
+end
+
+tokens = math.min(capacity, tokens + math.max(0, now - ts) * rate)
+
+local wait = 0
+if tokens - n >= reserve then
+	tokens = tokens - n
+else
+	wait = math.ceil((n + reserve - tokens) / rate * 1000)
+end
+
+redis.call('HMSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('EXPIRE', KEYS[1], math.ceil(capacity / rate) + 60)
+
+return {wait, math.floor(tokens)}
+`)
@@ -0,0 +1,36 @@
+package rcache
+
+import (
+	"testing"
+)
+
+func TestTokenBucket_Take(t *testing.T) {
+	SetupForTest(t)
+
+	b := NewTokenBucket("test", 10, 0.001)
+
+	wait, remaining, err := b.Take(4, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wait != 0 || remaining != 6 {
+		t.Fatalf("got wait=%s remaining=%d, want wait=0 remaining=6", wait, remaining)
+	}
+
+	// The reserve can't be dipped into.
+	wait, remaining, err = b.Take(4, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wait == 0 || remaining != 6 {
+		t.Fatalf("got wait=%s remaining=%d, want wait>0 remaining=6", wait, remaining)
+	}
+
+	wait, remaining, err = b.Take(6, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wait != 0 || remaining != 0 {
+		t.Fatalf("got wait=%s remaining=%d, want wait=0 remaining=0", wait, remaining)
+	}
+}
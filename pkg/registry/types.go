@@ -29,3 +29,20 @@ type Publisher struct {
 	Name string `json:"name"`
 	URL  string `json:"url"`
 }
+
+// Archive is the contents of an extension archive file, which contains the latest releases of
+// extensions (including their bundles). Sites that can't access a registry can mirror extensions
+// from an archive that was exported from a site that can access it.
+type Archive struct {
+	// RegistryURL is the URL of the registry that the extensions were exported from.
+	RegistryURL string               `json:"registryURL"`
+	Extensions  []*ArchivedExtension `json:"extensions"`
+}
+
+// ArchivedExtension is an extension and the artifacts of its latest release in an extension
+// archive. The extension's manifest has no "url" field if its bundle is included.
+type ArchivedExtension struct {
+	Extension
+	Bundle    *string `json:"bundle,omitempty"`
+	SourceMap *string `json:"sourceMap,omitempty"`
+}
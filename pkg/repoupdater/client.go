@@ -72,6 +72,28 @@ func (c *Client) RepoUpdateSchedulerInfo(ctx context.Context, args protocol.Repo
 	return result, err
 }
 
+// MockFailingRepos mocks (*Client).FailingRepos for tests.
+var MockFailingRepos func() (*protocol.FailingReposResult, error)
+
+// FailingRepos returns the repos whose last update failed.
+func (c *Client) FailingRepos(ctx context.Context) (result *protocol.FailingReposResult, err error) {
+	if MockFailingRepos != nil {
+		return MockFailingRepos()
+	}
+
+	resp, err := c.httpPost(ctx, "failing-repos", struct{}{})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Wrap(fmt.Errorf("http status %d", resp.StatusCode), "FailingRepos")
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	return result, err
+}
+
 // MockRepoLookup mocks (*Client).RepoLookup for tests.
 var MockRepoLookup func(protocol.RepoLookupArgs) (*protocol.RepoLookupResult, error)
 
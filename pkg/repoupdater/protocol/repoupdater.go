@@ -26,6 +26,7 @@ type RepoScheduleState struct {
 	Total           int
 	IntervalSeconds int
 	Due             time.Time
+	LastError       string `json:",omitempty"`
 }
 
 type RepoQueueState struct {
@@ -34,6 +35,22 @@ type RepoQueueState struct {
 	Updating bool
 }
 
+// FailingReposResult is the list of repos whose last update failed, ordered by their number of
+// consecutive failures.
+type FailingReposResult struct {
+	Repos []*FailingRepo
+}
+
+// FailingRepo is a repo whose last update failed.
+type FailingRepo struct {
+	ID        uint32
+	Name      api.RepoName
+	Failures  int       // the number of consecutive failed updates
+	ErrorKind string    // the kind of LastError, as classified by gitserver
+	LastError string    // the error of the last update
+	Due       time.Time // the next time the repo will be updated
+}
+
 // RepoExternalServicesRequest is a request for the external services
 // associated with a repository.
 type RepoExternalServicesRequest struct {
@@ -0,0 +1,52 @@
+// Package requestclient provides the network address of the client that made an HTTP request, so
+// that code that only has a context.Context (such as the audit log recorder) can access it.
+package requestclient
+
+import (
+	"context"
+	"net"
+	"net/http"
+)
+
+// Client describes the client that made an HTTP request.
+type Client struct {
+	// IP is the IP address of the direct peer of the request (which is a proxy if Sourcegraph is
+	// deployed behind one).
+	IP string
+
+	// ForwardedFor is the value of the request's X-Forwarded-For header, if any. It is set by
+	// proxies, but it can be forged by clients, so it must not be trusted.
+	ForwardedFor string
+}
+
+type key int
+
+const clientKey key = iota
+
+// FromContext returns the client stored in the context, or nil if there is none.
+func FromContext(ctx context.Context) *Client {
+	c, _ := ctx.Value(clientKey).(*Client)
+	return c
+}
+
+// WithClient returns a copy of the context with the given client.
+func WithClient(ctx context.Context, c *Client) context.Context {
+	return context.WithValue(ctx, clientKey, c)
+}
+
+// FromRequest returns the client that made the HTTP request.
+func FromRequest(r *http.Request) *Client {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ip = host
+	}
+	return &Client{IP: ip, ForwardedFor: r.Header.Get("X-Forwarded-For")}
+}
+
+// HTTPMiddleware is an HTTP middleware that stores the client that made the request in the
+// request context.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(WithClient(r.Context(), FromRequest(r))))
+	})
+}
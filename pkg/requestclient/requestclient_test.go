@@ -0,0 +1,28 @@
+package requestclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestHTTPMiddleware(t *testing.T) {
+	var got *Client
+	h := HTTPMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = FromContext(r.Context())
+	}))
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.7")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	if want := (&Client{IP: "10.0.0.1", ForwardedFor: "203.0.113.7"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	if c := FromContext(context.Background()); c != nil {
+		t.Errorf("got %+v, want nil", c)
+	}
+}
@@ -0,0 +1,64 @@
+package txemail
+
+import (
+	"net/mail"
+
+	gophermail "gopkg.in/jpoehls/gophermail.v0"
+)
+
+// Email is a rendered email to a single recipient, which is ready to be
+// delivered by a transport.
+type Email struct {
+	FromName    string   // "From" address proper name
+	FromAddress string   // "From" address
+	To          string   // the recipient's address
+	ReplyTo     *string  // optional "ReplyTo" address
+	MessageID   *string  // optional "Message-ID" header
+	References  []string // optional "References" header list
+	Subject     string
+	Text        string // the text body
+	HTML        string // the HTML body
+}
+
+// render renders the message into an email to each of its recipients.
+func render(message Message, fromAddress string) ([]*Email, error) {
+	m, err := Render(message)
+	if err != nil {
+		return nil, err
+	}
+	emails := make([]*Email, len(m.To))
+	for i, to := range m.To {
+		emails[i] = &Email{
+			FromName:    m.From.Name,
+			FromAddress: fromAddress,
+			To:          to.Address,
+			ReplyTo:     message.ReplyTo,
+			MessageID:   message.MessageID,
+			References:  message.References,
+			Subject:     m.Subject,
+			Text:        m.Body,
+			HTML:        m.HTMLBody,
+		}
+	}
+	return emails, nil
+}
+
+// gophermailMessage returns the email as a gophermail message (for sending
+// over SMTP).
+func (e *Email) gophermailMessage() (*gophermail.Message, error) {
+	m := &gophermail.Message{
+		From:     mail.Address{Name: e.FromName, Address: e.FromAddress},
+		To:       []mail.Address{{Address: e.To}},
+		Subject:  e.Subject,
+		Body:     e.Text,
+		HTMLBody: e.HTML,
+		Headers:  mail.Header{},
+	}
+	if e.ReplyTo != nil {
+		if err := m.SetReplyTo(*e.ReplyTo); err != nil {
+			return nil, err
+		}
+	}
+	setThreadingHeaders(m, e.MessageID, e.References)
+	return m, nil
+}
@@ -0,0 +1,116 @@
+package txemail
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// httpClient is the HTTP client used to send emails to HTTP email APIs.
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// httpTransport delivers emails to an HTTP email API that accepts requests in
+// the format of SendGrid's v3 mail send API (see
+// https://sendgrid.com/docs/API_Reference/api_v3.html).
+type httpTransport struct {
+	config *schema.EmailHTTPAPIConfig
+}
+
+type httpAPIAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type httpAPIContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type httpAPIPersonalization struct {
+	To []httpAPIAddress `json:"to"`
+}
+
+type httpAPIRequest struct {
+	Personalizations []httpAPIPersonalization `json:"personalizations"`
+	From             httpAPIAddress           `json:"from"`
+	ReplyTo          *httpAPIAddress          `json:"reply_to,omitempty"`
+	Subject          string                   `json:"subject"`
+	Content          []httpAPIContent         `json:"content"`
+	Headers          map[string]string        `json:"headers,omitempty"`
+}
+
+// httpAPIRequestBody returns the JSON body of the HTTP email API request that
+// sends the email.
+func httpAPIRequestBody(e *Email) ([]byte, error) {
+	req := httpAPIRequest{
+		Personalizations: []httpAPIPersonalization{{To: []httpAPIAddress{{Email: e.To}}}},
+		From:             httpAPIAddress{Email: e.FromAddress, Name: e.FromName},
+		Subject:          e.Subject,
+		Content: []httpAPIContent{
+			{Type: "text/plain", Value: e.Text},
+			{Type: "text/html", Value: e.HTML},
+		},
+	}
+	if e.ReplyTo != nil {
+		req.ReplyTo = &httpAPIAddress{Email: *e.ReplyTo}
+	}
+
+	// Reuse the SMTP transport's threading headers, so that emails are
+	// grouped into threads in the same way.
+	m, err := e.gophermailMessage()
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range []string{"Message-ID", "References"} {
+		// Look up the header directly, because mail.Header.Get would
+		// canonicalize "Message-ID" to "Message-Id".
+		if v := m.Headers[name]; len(v) > 0 {
+			if req.Headers == nil {
+				req.Headers = map[string]string{}
+			}
+			req.Headers[name] = v[0]
+		}
+	}
+	return json.Marshal(req)
+}
+
+func (t *httpTransport) Send(ctx context.Context, e *Email) error {
+	body, err := httpAPIRequestBody(e)
+	if err != nil {
+		return &PermanentError{Err: err}
+	}
+	req, err := http.NewRequest("POST", t.config.Url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if t.config.ApiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+t.config.ApiKey)
+	}
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return nil
+	}
+
+	respBody, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	err = fmt.Errorf("HTTP email API responded with status %d: %s", resp.StatusCode, bytes.TrimSpace(respBody))
+	switch resp.StatusCode {
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+		// The API rejected the email itself, so retrying it would fail too.
+		// Other errors (such as authentication errors and rate limiting) may
+		// succeed when retried.
+		return &PermanentError{Err: err}
+	}
+	return err
+}
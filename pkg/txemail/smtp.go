@@ -0,0 +1,131 @@
+package txemail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+	gophermail "gopkg.in/jpoehls/gophermail.v0"
+)
+
+// smtpTransport delivers emails to an SMTP server.
+type smtpTransport struct {
+	config *schema.SMTPServerConfig
+}
+
+func (t *smtpTransport) Send(ctx context.Context, e *Email) error {
+	m, err := e.gophermailMessage()
+	if err != nil {
+		return &PermanentError{Err: err}
+	}
+
+	// Disable Mandrill features, because they make the emails look sketchy.
+	if t.config.Host == "smtp.mandrillapp.com" {
+		// Disable click tracking ("noclicks" could be any string; the docs say that anything will disable click tracking except
+		// those defined at
+		// https://mandrill.zendesk.com/hc/en-us/articles/205582117-How-to-Use-SMTP-Headers-to-Customize-Your-Messages#enable-open-and-click-tracking).
+		m.Headers["X-MC-Track"] = []string{"noclicks"}
+
+		m.Headers["X-MC-AutoText"] = []string{"false"}
+		m.Headers["X-MC-AutoHTML"] = []string{"false"}
+		m.Headers["X-MC-ViewContentLink"] = []string{"false"}
+	}
+
+	var smtpAuth smtp.Auth
+	switch t.config.Authentication {
+	case "none": // nothing to do
+	case "PLAIN":
+		smtpAuth = smtp.PlainAuth("", t.config.Username, t.config.Password, t.config.Host)
+	case "CRAM-MD5":
+		smtpAuth = smtp.CRAMMD5Auth(t.config.Username, t.config.Password)
+	default:
+		return fmt.Errorf("invalid SMTP authentication type %q", t.config.Authentication)
+	}
+
+	return sendMail(t.config.Host, net.JoinHostPort(t.config.Host, strconv.Itoa(t.config.Port)), smtpAuth, m)
+}
+
+// sendMail is like gophermail.SendMail (and smtp.SendMail), except that it
+// returns a *PermanentError if the SMTP server permanently rejects a recipient
+// in response to the RCPT command (i.e., the email bounced). Other errors (such
+// as connection errors, temporary failures, authentication failures that are
+// fixed by updating the configuration, and rejections of the sender or of the
+// message) are returned as is, because retrying may succeed.
+func sendMail(host, addr string, auth smtp.Auth, m *gophermail.Message) error {
+	msg, err := m.Bytes()
+	if err != nil {
+		return &PermanentError{Err: err}
+	}
+	var to []string
+	for _, addrs := range [][]mail.Address{m.To, m.Cc, m.Bcc} {
+		for _, a := range addrs {
+			to = append(to, a.Address)
+		}
+	}
+	for _, a := range append([]string{m.From.Address}, to...) {
+		if strings.ContainsAny(a, "\r\n") {
+			return &PermanentError{Err: errors.New("smtp: email address must not contain CR or LF")}
+		}
+	}
+
+	c, err := smtp.Dial(addr)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(m.From.Address); err != nil {
+		return err
+	}
+	for _, a := range to {
+		if err := c.Rcpt(a); err != nil {
+			return rcptError(err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// rcptError returns a *PermanentError if the error is the SMTP server's
+// response to the RCPT command that permanently rejects the recipient's
+// address. Other errors are returned as is.
+func rcptError(err error) error {
+	if e, ok := err.(*textproto.Error); ok {
+		switch e.Code {
+		case 550, // mailbox unavailable (e.g., not found)
+			551, // user not local
+			553: // mailbox name not allowed
+			return &PermanentError{Err: err, Bounce: true}
+		}
+	}
+	return err
+}
@@ -0,0 +1,41 @@
+package txemail
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A transport delivers rendered emails (e.g., to an SMTP server or an HTTP
+// email API).
+type transport interface {
+	Send(ctx context.Context, e *Email) error
+}
+
+// configuredTransport returns the transport configured in the site
+// configuration. The HTTP email API (email.http) takes precedence over the
+// SMTP server (email.smtp).
+func configuredTransport(c *schema.SiteConfiguration) (transport, error) {
+	switch {
+	case c.EmailHttp != nil:
+		return &httpTransport{config: c.EmailHttp}, nil
+	case c.EmailSmtp != nil:
+		return &smtpTransport{config: c.EmailSmtp}, nil
+	default:
+		return nil, errors.New("no SMTP server or HTTP email API configured (in email.smtp or email.http)")
+	}
+}
+
+// PermanentError is returned when the delivery of an email failed and
+// retrying it would fail too (e.g., because the recipient's mail server
+// rejected the recipient's address).
+type PermanentError struct {
+	Err error
+
+	// Bounce is whether the recipient's mail server permanently rejected the
+	// email (i.e., the email bounced).
+	Bounce bool
+}
+
+func (e *PermanentError) Error() string { return e.Err.Error() }
@@ -0,0 +1,161 @@
+package txemail
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/textproto"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/schema"
+	gophermail "gopkg.in/jpoehls/gophermail.v0"
+)
+
+func TestHTTPTransport(t *testing.T) {
+	var (
+		status  = http.StatusAccepted
+		gotReq  *http.Request
+		gotBody []byte
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotReq = r
+		gotBody, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer ts.Close()
+
+	transport := &httpTransport{config: &schema.EmailHTTPAPIConfig{Url: ts.URL, ApiKey: "k"}}
+	replyTo := "reply@example.com"
+	messageID := "m@example.com"
+	e := &Email{
+		FromName:    "Sourcegraph",
+		FromAddress: "from@example.com",
+		To:          "to@example.com",
+		ReplyTo:     &replyTo,
+		MessageID:   &messageID,
+		References:  []string{"a@example.com", "b@example.com"},
+		Subject:     "s",
+		Text:        "t",
+		HTML:        "<p>h</p>",
+	}
+	if err := transport.Send(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := gotReq.Header.Get("Authorization"), "Bearer k"; got != want {
+		t.Errorf("got Authorization %q, want %q", got, want)
+	}
+	var got, want interface{}
+	if err := json.Unmarshal(gotBody, &got); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{
+  "personalizations": [{"to": [{"email": "to@example.com"}]}],
+  "from": {"email": "from@example.com", "name": "Sourcegraph"},
+  "reply_to": {"email": "reply@example.com"},
+  "subject": "s",
+  "content": [{"type": "text/plain", "value": "t"}, {"type": "text/html", "value": "<p>h</p>"}],
+  "headers": {"Message-ID": "m@example.com", "References": "<a@example.com> <b@example.com>"}
+}`), &want); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got body %s", gotBody)
+	}
+
+	// Rejected emails are not retried, but other failures are.
+	status = http.StatusBadRequest
+	if err, ok := transport.Send(context.Background(), e).(*PermanentError); !ok || err.Bounce {
+		t.Errorf("got %v, want a *PermanentError that is not a bounce", err)
+	}
+	status = http.StatusTooManyRequests
+	if err := transport.Send(context.Background(), e); err == nil {
+		t.Error("got no error")
+	} else if _, ok := err.(*PermanentError); ok {
+		t.Errorf("got %v, want a transient error", err)
+	}
+}
+
+func TestSendMail(t *testing.T) {
+	tests := []struct {
+		name                 string
+		mailReply, rcptReply string
+		wantErr, wantBounce  bool
+		wantPermanent        bool
+	}{
+		{name: "sent", mailReply: "250 ok", rcptReply: "250 ok"},
+		{name: "recipient rejected", mailReply: "250 ok", rcptReply: "550 no such user", wantErr: true, wantPermanent: true, wantBounce: true},
+		{name: "recipient temporarily rejected", mailReply: "250 ok", rcptReply: "451 try again later", wantErr: true},
+		{name: "sender rejected", mailReply: "550 sender not allowed", rcptReply: "250 ok", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			addr := fakeSMTPServer(t, test.mailReply, test.rcptReply)
+			m := &gophermail.Message{
+				From:    mail.Address{Address: "from@example.com"},
+				To:      []mail.Address{{Address: "to@example.com"}},
+				Subject: "s",
+				Body:    "b",
+			}
+			err := sendMail("127.0.0.1", addr, nil, m)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("got error %v, want error %v", err, test.wantErr)
+			}
+			perr, ok := err.(*PermanentError)
+			if ok != test.wantPermanent {
+				t.Fatalf("got %v, want permanent %v", err, test.wantPermanent)
+			}
+			if ok && perr.Bounce != test.wantBounce {
+				t.Errorf("got bounce %v, want %v", perr.Bounce, test.wantBounce)
+			}
+		})
+	}
+}
+
+// fakeSMTPServer serves a single SMTP session on a local port, replying to the
+// MAIL and RCPT commands with the given replies, and returns its address.
+func fakeSMTPServer(t *testing.T, mailReply, rcptReply string) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		defer l.Close()
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		c := textproto.NewConn(conn)
+		_ = c.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := c.ReadLine()
+			if err != nil {
+				return
+			}
+			switch strings.ToUpper(strings.SplitN(line, " ", 2)[0]) {
+			case "MAIL":
+				_ = c.PrintfLine("%s", mailReply)
+			case "RCPT":
+				_ = c.PrintfLine("%s", rcptReply)
+			case "DATA":
+				_ = c.PrintfLine("354 go ahead")
+				if _, err := c.ReadDotBytes(); err != nil {
+					return
+				}
+				_ = c.PrintfLine("250 ok")
+			case "QUIT":
+				_ = c.PrintfLine("221 bye")
+				return
+			default: // EHLO, HELO, RSET, NOOP
+				_ = c.PrintfLine("250 localhost")
+			}
+		}
+	}()
+	return l.Addr().String()
+}
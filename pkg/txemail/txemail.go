@@ -5,10 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"net"
 	"net/mail"
-	"net/smtp"
-	"strconv"
 
 	"github.com/sourcegraph/sourcegraph/pkg/conf"
 	"github.com/sourcegraph/sourcegraph/pkg/txemail/txtypes"
@@ -41,20 +38,7 @@ func Render(message Message) (*gophermail.Message, error) {
 			return nil, err
 		}
 	}
-	if message.MessageID != nil {
-		m.Headers["Message-ID"] = []string{*message.MessageID}
-	}
-	if len(message.References) > 0 {
-		// gophermail does not support lists, so we must build it ourself.
-		var refsList string
-		for _, ref := range message.References {
-			if refsList != "" {
-				refsList += " "
-			}
-			refsList += fmt.Sprintf("<%s>", ref)
-		}
-		m.Headers["References"] = []string{refsList}
-	}
+	setThreadingHeaders(&m, message.MessageID, message.References)
 
 	parsed, err := ParseTemplate(message.Template)
 	if err != nil {
@@ -82,6 +66,10 @@ func Render(message Message) (*gophermail.Message, error) {
 
 // Send sends a transactional email.
 //
+// If an outbox is set (see Outbox), the email is enqueued and delivered
+// asynchronously (with retries). Otherwise it is delivered synchronously with
+// the configured transport (see Deliver).
+//
 // Callers that do not live in the frontend should call api.InternalClient.SendEmail
 // instead. TODO(slimsag): needs cleanup as part of upcoming configuration refactor.
 func Send(ctx context.Context, message Message) error {
@@ -96,44 +84,64 @@ func Send(ctx context.Context, message Message) error {
 	if conf.EmailAddress == "" {
 		return errors.New("no \"From\" email address configured (in email.address)")
 	}
-	if conf.EmailSmtp == nil {
-		return errors.New("no SMTP server configured (in email.smtp)")
+	transport, err := configuredTransport(&conf.SiteConfiguration)
+	if err != nil {
+		return err
 	}
 
-	m, err := Render(message)
+	emails, err := render(message, conf.EmailAddress)
 	if err != nil {
 		return err
 	}
-	m.From.Address = conf.EmailAddress
-
-	// Disable Mandrill features, because they make the emails look sketchy.
-	if conf.EmailSmtp.Host == "smtp.mandrillapp.com" {
-		// Disable click tracking ("noclicks" could be any string; the docs say that anything will disable click tracking except
-		// those defined at
-		// https://mandrill.zendesk.com/hc/en-us/articles/205582117-How-to-Use-SMTP-Headers-to-Customize-Your-Messages#enable-open-and-click-tracking).
-		m.Headers["X-MC-Track"] = []string{"noclicks"}
-
-		m.Headers["X-MC-AutoText"] = []string{"false"}
-		m.Headers["X-MC-AutoHTML"] = []string{"false"}
-		m.Headers["X-MC-ViewContentLink"] = []string{"false"}
+	for _, e := range emails {
+		if Outbox != nil {
+			err = Outbox(ctx, e)
+		} else {
+			err = transport.Send(ctx, e)
+		}
+		if err != nil {
+			return err
+		}
 	}
+	return nil
+}
+
+// Outbox, if set, persists rendered emails so that they are delivered
+// asynchronously (with Deliver) and retried if delivery fails. Send calls it
+// instead of delivering emails itself. The frontend sets it to its email
+// outbox.
+var Outbox func(ctx context.Context, e *Email) error
 
-	var smtpAuth smtp.Auth
-	switch conf.EmailSmtp.Authentication {
-	case "none": // nothing to do
-	case "PLAIN":
-		smtpAuth = smtp.PlainAuth("", conf.EmailSmtp.Username, conf.EmailSmtp.Password, conf.EmailSmtp.Host)
-	case "CRAM-MD5":
-		smtpAuth = smtp.CRAMMD5Auth(conf.EmailSmtp.Username, conf.EmailSmtp.Password)
-	default:
-		return fmt.Errorf("invalid SMTP authentication type %q", conf.EmailSmtp.Authentication)
+// Deliver delivers the rendered email with the configured transport (the HTTP
+// email API in email.http if set, or else the SMTP server in email.smtp).
+//
+// If delivery failed and retrying would fail too, the error is a
+// *PermanentError.
+func Deliver(ctx context.Context, e *Email) error {
+	transport, err := configuredTransport(&conf.Get().SiteConfiguration)
+	if err != nil {
+		return err
 	}
+	return transport.Send(ctx, e)
+}
 
-	return gophermail.SendMail(
-		net.JoinHostPort(conf.EmailSmtp.Host, strconv.Itoa(conf.EmailSmtp.Port)),
-		smtpAuth,
-		m,
-	)
+// setThreadingHeaders sets the optional "Message-ID" and "References"
+// headers, which email clients use to group emails into threads.
+func setThreadingHeaders(m *gophermail.Message, messageID *string, references []string) {
+	if messageID != nil {
+		m.Headers["Message-ID"] = []string{*messageID}
+	}
+	if len(references) > 0 {
+		// gophermail does not support lists, so we must build it ourself.
+		var refsList string
+		for _, ref := range references {
+			if refsList != "" {
+				refsList += " "
+			}
+			refsList += fmt.Sprintf("<%s>", ref)
+		}
+		m.Headers["References"] = []string{refsList}
+	}
 }
 
 // MockSend is used in tests to mock the Send func.
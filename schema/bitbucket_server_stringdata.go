@@ -125,6 +125,60 @@ const BitbucketServerSchemaJSON = `{
       "description": "Defines whether repositories from this Bitbucket Server instance should be enabled and cloned when they are first seen by Sourcegraph. If false, the site admin must explicitly enable Bitbucket Server repositories (in the site admin area) to clone them and make them searchable on Sourcegraph. If true, they will be enabled and cloned immediately (subject to rate limiting by Bitbucket Server); site admins can still disable them explicitly, and they'll remain disabled.",
       "type": "boolean",
       "default": false
+    },
+    "authorization": {
+      "title": "BitbucketServerAuthorization",
+      "description": "If non-null, enforces Bitbucket Server repository permissions. This requires an Application Link in Bitbucket Server with incoming authentication (2-legged OAuth with user impersonation) for Sourcegraph.",
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["identityProvider", "oauth"],
+      "properties": {
+        "identityProvider": {
+          "title": "BitbucketServerIdentityProvider",
+          "description": "The source of identity to use when computing permissions. This defines how to compute the Bitbucket Server username of a given Sourcegraph user.\n\nIf \"username\", the Sourcegraph username is used. This is only safe if usernames can't be changed by users (see the ` + "`" + `auth.disableUsernameChanges` + "`" + ` critical configuration property).\n\nIf \"external\", the account ID of the user's external account from the authentication provider identified by \"authProviderType\" and \"authProviderID\" is used.",
+          "type": "object",
+          "additionalProperties": false,
+          "required": ["type"],
+          "properties": {
+            "type": {
+              "type": "string",
+              "enum": ["username", "external"]
+            },
+            "authProviderID": {
+              "description": "The configID of the authentication provider (in the ` + "`" + `auth.providers` + "`" + ` critical configuration property) whose external accounts' IDs are Bitbucket Server usernames. Required if \"type\" is \"external\". Only \"saml\" and \"openidconnect\" authentication providers (which have a configID) are supported.",
+              "type": "string"
+            },
+            "authProviderType": {
+              "description": "The type of the authentication provider (in the ` + "`" + `auth.providers` + "`" + ` critical configuration property) whose external accounts' IDs are Bitbucket Server usernames (\"saml\" or \"openidconnect\"). Required if \"type\" is \"external\".",
+              "type": "string"
+            }
+          }
+        },
+        "oauth": {
+          "title": "BitbucketServerOAuth",
+          "description": "The OAuth consumer of the Application Link that Sourcegraph uses to query the repositories that each user can access.",
+          "type": "object",
+          "additionalProperties": false,
+          "required": ["consumerKey", "signingKey"],
+          "properties": {
+            "consumerKey": {
+              "description": "The OAuth consumer key of the Application Link's incoming authentication.",
+              "type": "string",
+              "minLength": 1
+            },
+            "signingKey": {
+              "description": "Base64-encoded PEM RSA private key of the OAuth consumer, whose public key is configured in the Application Link's incoming authentication.",
+              "type": "string",
+              "minLength": 1
+            }
+          }
+        },
+        "ttl": {
+          "description": "The TTL of how long to cache permissions data. This is 3 hours by default.\n\nDecreasing the TTL will increase the load on the code host API. Each cache refresh lists all of a user's accessible repositories (~X/1000 API requests for X repositories).",
+          "type": "string",
+          "default": "3h"
+        }
+      }
     }
   }
 }
@@ -98,7 +98,7 @@ const CriticalSchemaJSON = `{
         "properties": {
           "type": {
             "type": "string",
-            "enum": ["builtin", "saml", "openidconnect", "http-header", "github", "gitlab"]
+            "enum": ["builtin", "saml", "openidconnect", "http-header", "github", "gitlab", "ldap"]
           }
         },
         "oneOf": [
@@ -107,7 +107,8 @@ const CriticalSchemaJSON = `{
           { "$ref": "#/definitions/OpenIDConnectAuthProvider" },
           { "$ref": "#/definitions/HTTPHeaderAuthProvider" },
           { "$ref": "#/definitions/GitHubAuthProvider" },
-          { "$ref": "#/definitions/GitLabAuthProvider" }
+          { "$ref": "#/definitions/GitLabAuthProvider" },
+          { "$ref": "#/definitions/LDAPAuthProvider" }
         ],
         "!go": {
           "taggedUnionType": true
@@ -139,6 +140,13 @@ const CriticalSchemaJSON = `{
       "type": "boolean",
       "default": false
     },
+    "auth.scimBearerToken": {
+      "description": "Enables the SCIM 2.0 user and group provisioning API at /.api/scim/v2 and sets the bearer token that identity providers must use to authenticate to it. Use a secret reference (such as \"$env:SRC_SECRET_SCIM_TOKEN\") to avoid storing the token in the configuration. See https://docs.sourcegraph.com/admin/auth/scim.",
+      "type": "string",
+      "minLength": 32,
+      "examples": ["$env:SRC_SECRET_SCIM_TOKEN"],
+      "group": "Authentication"
+    },
     "update.channel": {
       "description": "The channel on which to automatically check for Sourcegraph updates.",
       "type": ["string"],
@@ -358,6 +366,105 @@ const CriticalSchemaJSON = `{
         "displayName": { "$ref": "#/definitions/AuthProviderCommon/properties/displayName" }
       }
     },
+    "LDAPAuthProvider": {
+      "description": "Configures the LDAP authentication provider, which signs in users with their username and password in an LDAP directory (such as Active Directory or OpenLDAP).",
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["type", "url", "userBaseDN"],
+      "properties": {
+        "type": {
+          "type": "string",
+          "const": "ldap"
+        },
+        "url": {
+          "description": "The URL of the LDAP server, with the ldap:// or ldaps:// (LDAP over TLS) scheme.",
+          "type": "string",
+          "pattern": "^ldaps?://",
+          "examples": ["ldaps://ldap.example.com", "ldap://ldap.example.com:389"]
+        },
+        "startTLS": {
+          "description": "Whether to upgrade ldap:// connections to TLS with the StartTLS operation.",
+          "type": "boolean",
+          "default": false
+        },
+        "certificate": {
+          "description": "The PEM-encoded TLS certificate of the certificate authority that signed the LDAP server's certificate, if it isn't trusted by the system.",
+          "type": "string",
+          "pattern": "^-----BEGIN CERTIFICATE-----\n"
+        },
+        "insecureSkipVerify": {
+          "description": "Whether to (insecurely) skip the verification of the LDAP server's TLS certificate.",
+          "type": "boolean",
+          "default": false
+        },
+        "bindDN": {
+          "description": "The DN of the service account used to search for users and groups. If empty, searches are performed anonymously.",
+          "type": "string",
+          "examples": ["cn=sourcegraph,ou=services,dc=example,dc=com"]
+        },
+        "bindPassword": {
+          "description": "The password of the service account. Use a secret reference (such as \"$env:SRC_SECRET_LDAP_BIND_PASSWORD\") to avoid storing the password in the configuration.",
+          "type": "string"
+        },
+        "userBaseDN": {
+          "description": "The DN under which users are searched for.",
+          "type": "string",
+          "examples": ["ou=people,dc=example,dc=com"]
+        },
+        "userFilter": {
+          "description": "An LDAP filter that users must match to be able to sign in, which is combined with the username filter.",
+          "type": "string",
+          "default": "(objectClass=person)",
+          "examples": ["(&(objectClass=user)(memberOf=cn=developers,ou=groups,dc=example,dc=com))"]
+        },
+        "usernameAttribute": {
+          "description": "The attribute that users sign in with, which is also used as their Sourcegraph username (normalized). Use \"sAMAccountName\" for Active Directory.",
+          "type": "string",
+          "default": "uid"
+        },
+        "emailAttribute": {
+          "description": "The attribute that contains the user's email address, which is considered verified.",
+          "type": "string",
+          "default": "mail"
+        },
+        "displayNameAttribute": {
+          "description": "The attribute that contains the user's display name.",
+          "type": "string",
+          "default": "cn"
+        },
+        "groupBaseDN": {
+          "description": "The DN under which groups are searched for. If empty, group membership is not looked up.",
+          "type": "string",
+          "examples": ["ou=groups,dc=example,dc=com"]
+        },
+        "groupMemberAttribute": {
+          "description": "The group attribute that contains the DNs of the group's members.",
+          "type": "string",
+          "default": "member",
+          "examples": ["uniqueMember"]
+        },
+        "groupNameAttribute": {
+          "description": "The group attribute that contains the group's name.",
+          "type": "string",
+          "default": "cn"
+        },
+        "groupOrgMap": {
+          "description": "Maps LDAP group names to the names of the Sourcegraph organizations that the group's members are added to when they sign in. Users are removed from the mapped organizations when they are no longer in a group that maps to them. Requires groupBaseDN.",
+          "type": "object",
+          "additionalProperties": {
+            "type": "array",
+            "items": { "type": "string" }
+          },
+          "examples": [{ "developers": ["engineering"] }]
+        },
+        "allowSignup": {
+          "description": "Allows new visitors to sign up for accounts via LDAP authentication. If false, users signing in via LDAP must have an existing Sourcegraph account (with the same verified email address), which will be linked to their LDAP identity after sign-in.",
+          "type": "boolean",
+          "default": false
+        },
+        "displayName": { "$ref": "#/definitions/AuthProviderCommon/properties/displayName" }
+      }
+    },
     "AuthProviderCommon": {
       "$comment": "This schema is not used directly. The *AuthProvider schemas refer to its properties directly.",
       "description": "Common properties for authentication providers.",
@@ -33,6 +33,7 @@ type AuthProviders struct {
 	HttpHeader    *HTTPHeaderAuthProvider
 	Github        *GitHubAuthProvider
 	Gitlab        *GitLabAuthProvider
+	Ldap          *LDAPAuthProvider
 }
 
 func (v AuthProviders) MarshalJSON() ([]byte, error) {
@@ -54,6 +55,9 @@ func (v AuthProviders) MarshalJSON() ([]byte, error) {
 	if v.Gitlab != nil {
 		return json.Marshal(v.Gitlab)
 	}
+	if v.Ldap != nil {
+		return json.Marshal(v.Ldap)
+	}
 	return nil, errors.New("tagged union type must have exactly 1 non-nil field value")
 }
 func (v *AuthProviders) UnmarshalJSON(data []byte) error {
@@ -72,16 +76,26 @@ func (v *AuthProviders) UnmarshalJSON(data []byte) error {
 		return json.Unmarshal(data, &v.Gitlab)
 	case "http-header":
 		return json.Unmarshal(data, &v.HttpHeader)
+	case "ldap":
+		return json.Unmarshal(data, &v.Ldap)
 	case "openidconnect":
 		return json.Unmarshal(data, &v.Openidconnect)
 	case "saml":
 		return json.Unmarshal(data, &v.Saml)
 	}
-	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"builtin", "saml", "openidconnect", "http-header", "github", "gitlab"})
+	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"builtin", "saml", "openidconnect", "http-header", "github", "gitlab", "ldap"})
+}
+
+// BitbucketServerAuthorization description: If non-null, enforces Bitbucket Server repository permissions. This requires an Application Link in Bitbucket Server with incoming authentication (2-legged OAuth with user impersonation) for Sourcegraph.
+type BitbucketServerAuthorization struct {
+	IdentityProvider BitbucketServerIdentityProvider `json:"identityProvider"`
+	Oauth            BitbucketServerOAuth            `json:"oauth"`
+	Ttl              string                          `json:"ttl,omitempty"`
 }
 
 // BitbucketServerConnection description: Configuration for a connection to Bitbucket Server.
 type BitbucketServerConnection struct {
+	Authorization               *BitbucketServerAuthorization  `json:"authorization,omitempty"`
 	Certificate                 string                         `json:"certificate,omitempty"`
 	Exclude                     []*ExcludedBitbucketServerRepo `json:"exclude,omitempty"`
 	ExcludePersonalRepositories bool                           `json:"excludePersonalRepositories,omitempty"`
@@ -95,6 +109,23 @@ type BitbucketServerConnection struct {
 	Url                         string                         `json:"url"`
 	Username                    string                         `json:"username"`
 }
+
+// BitbucketServerIdentityProvider description: The source of identity to use when computing permissions. This defines how to compute the Bitbucket Server username of a given Sourcegraph user.
+//
+// If "username", the Sourcegraph username is used. This is only safe if usernames can't be changed by users (see the `auth.disableUsernameChanges` critical configuration property).
+//
+// If "external", the account ID of the user's external account from the authentication provider identified by "authProviderType" and "authProviderID" is used.
+type BitbucketServerIdentityProvider struct {
+	AuthProviderID   string `json:"authProviderID,omitempty"`
+	AuthProviderType string `json:"authProviderType,omitempty"`
+	Type             string `json:"type"`
+}
+
+// BitbucketServerOAuth description: The OAuth consumer of the Application Link that Sourcegraph uses to query the repositories that each user can access.
+type BitbucketServerOAuth struct {
+	ConsumerKey string `json:"consumerKey"`
+	SigningKey  string `json:"signingKey"`
+}
 type BrandAssets struct {
 	Logo   string `json:"logo,omitempty"`
 	Symbol string `json:"symbol,omitempty"`
@@ -125,6 +156,7 @@ type CriticalConfiguration struct {
 	AuthEnableUsernameChanges  bool                `json:"auth.enableUsernameChanges,omitempty"`
 	AuthProviders              []AuthProviders     `json:"auth.providers,omitempty"`
 	AuthPublic                 bool                `json:"auth.public,omitempty"`
+	AuthScimBearerToken        string              `json:"auth.scimBearerToken,omitempty"`
 	AuthSessionExpiry          string              `json:"auth.sessionExpiry,omitempty"`
 	AuthUserOrgMap             map[string][]string `json:"auth.userOrgMap,omitempty"`
 	ExternalURL                string              `json:"externalURL,omitempty"`
@@ -142,8 +174,20 @@ type CriticalConfiguration struct {
 
 // Discussions description: Configures Sourcegraph code discussions.
 type Discussions struct {
-	AbuseEmails     []string `json:"abuseEmails,omitempty"`
-	AbuseProtection bool     `json:"abuseProtection,omitempty"`
+	AbuseEmails          []string                         `json:"abuseEmails,omitempty"`
+	AbuseProtection      bool                             `json:"abuseProtection,omitempty"`
+	ImportReviewComments *DiscussionsImportReviewComments `json:"importReviewComments,omitempty"`
+}
+
+// DiscussionsImportReviewComments description: Import pull request review comments (from GitHub) and merge request diff comments (from GitLab) on the repositories of the GitHub and GitLab connections as code discussion threads. New comments are imported every hour, and imported threads link back to the original pull or merge request.
+type DiscussionsImportReviewComments struct {
+	AuthorUsername string `json:"authorUsername"`
+}
+
+// EmailHTTPAPIConfig description: Optional. An HTTP email API used to send transactional emails instead of the SMTP server in email.smtp. The API must accept requests in the format of SendGrid's v3 mail send API (as SendGrid does, and as relays for other providers such as Amazon SES can).
+type EmailHTTPAPIConfig struct {
+	ApiKey string `json:"apiKey,omitempty"`
+	Url    string `json:"url"`
 }
 type ExcludedBitbucketServerRepo struct {
 	Id   int    `json:"id,omitempty"`
@@ -165,9 +209,19 @@ type ExperimentalFeatures struct {
 
 // Extensions description: Configures Sourcegraph extensions.
 type Extensions struct {
-	AllowRemoteExtensions []string    `json:"allowRemoteExtensions,omitempty"`
-	Disabled              *bool       `json:"disabled,omitempty"`
-	RemoteRegistry        interface{} `json:"remoteRegistry,omitempty"`
+	AllowRemoteExtensions []string          `json:"allowRemoteExtensions,omitempty"`
+	Disabled              *bool             `json:"disabled,omitempty"`
+	Mirror                *ExtensionsMirror `json:"mirror,omitempty"`
+	RemoteRegistry        interface{}       `json:"remoteRegistry,omitempty"`
+}
+
+// ExtensionsMirror description: Mirror extensions from a remote registry (or from an extension archive file) into the private extension registry, so that they can be used on sites that can't access the remote registry. Only the extensions allowed by `allowRemoteExtensions` are mirrored. When mirroring is enabled, remote extensions are served from the mirror instead of from the remote registry.
+//
+// Only available in Sourcegraph Enterprise.
+type ExtensionsMirror struct {
+	Archive         string `json:"archive,omitempty"`
+	IntervalMinutes int    `json:"intervalMinutes,omitempty"`
+	Registry        string `json:"registry,omitempty"`
 }
 type ExternalIdentity struct {
 	AuthProviderID   string `json:"authProviderID"`
@@ -299,10 +353,37 @@ func (v *IdentityProvider) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"oauth", "username", "external"})
 }
 
+// LDAPAuthProvider description: Configures the LDAP authentication provider, which signs in users with their username and password in an LDAP directory (such as Active Directory or OpenLDAP).
+type LDAPAuthProvider struct {
+	AllowSignup          bool                `json:"allowSignup,omitempty"`
+	BindDN               string              `json:"bindDN,omitempty"`
+	BindPassword         string              `json:"bindPassword,omitempty"`
+	Certificate          string              `json:"certificate,omitempty"`
+	DisplayName          string              `json:"displayName,omitempty"`
+	DisplayNameAttribute string              `json:"displayNameAttribute,omitempty"`
+	EmailAttribute       string              `json:"emailAttribute,omitempty"`
+	GroupBaseDN          string              `json:"groupBaseDN,omitempty"`
+	GroupMemberAttribute string              `json:"groupMemberAttribute,omitempty"`
+	GroupNameAttribute   string              `json:"groupNameAttribute,omitempty"`
+	GroupOrgMap          map[string][]string `json:"groupOrgMap,omitempty"`
+	InsecureSkipVerify   bool                `json:"insecureSkipVerify,omitempty"`
+	StartTLS             bool                `json:"startTLS,omitempty"`
+	Type                 string              `json:"type"`
+	Url                  string              `json:"url"`
+	UserBaseDN           string              `json:"userBaseDN"`
+	UserFilter           string              `json:"userFilter,omitempty"`
+	UsernameAttribute    string              `json:"usernameAttribute,omitempty"`
+}
+
 // Log description: Configuration for logging and alerting, including to external services.
 type Log struct {
 	Sentry *Sentry `json:"sentry,omitempty"`
 }
+
+// MSTeamsNotificationsConfig description: Configuration for sending notifications to Microsoft Teams.
+type MSTeamsNotificationsConfig struct {
+	WebhookURL string `json:"webhookURL"`
+}
 type Notice struct {
 	Dismissible bool   `json:"dismissible,omitempty"`
 	Location    string `json:"location"`
@@ -334,6 +415,12 @@ type ParentSourcegraph struct {
 	Url string `json:"url,omitempty"`
 }
 
+// PermissionsBackgroundSync description: Syncs repository permissions from the code hosts that have authorization configured (such as GitHub and GitLab) in the background, and stores them in the database. When enabled, permissions are checked with a single database query instead of by calling the code host when repositories are accessed. Each user's permissions are synced periodically and each time they sign in.
+type PermissionsBackgroundSync struct {
+	Enabled  bool   `json:"enabled,omitempty"`
+	Interval string `json:"interval,omitempty"`
+}
+
 // Phabricator description: Phabricator instance that integrates with this Gitolite instance
 type Phabricator struct {
 	CallsignCommand string `json:"callsignCommand"`
@@ -346,6 +433,14 @@ type PhabricatorConnection struct {
 	Token string   `json:"token,omitempty"`
 	Url   string   `json:"url,omitempty"`
 }
+
+// PluginConnection description: Configuration for a connection to a repository listing plugin, which lists the repositories of a Git host for which an external service integration isn't available.
+type PluginConnection struct {
+	Command   []string `json:"command,omitempty"`
+	ServiceID string   `json:"serviceID,omitempty"`
+	Token     string   `json:"token,omitempty"`
+	Url       string   `json:"url,omitempty"`
+}
 type Repos struct {
 	Callsign string `json:"callsign"`
 	Path     string `json:"path"`
@@ -381,7 +476,9 @@ type SearchSavedQueries struct {
 	Description    string `json:"description"`
 	Key            string `json:"key"`
 	Notify         bool   `json:"notify,omitempty"`
+	NotifyMSTeams  bool   `json:"notifyMSTeams,omitempty"`
 	NotifySlack    bool   `json:"notifySlack,omitempty"`
+	NotifyWebhook  bool   `json:"notifyWebhook,omitempty"`
 	Query          string `json:"query"`
 	ShowOnHomepage bool   `json:"showOnHomepage,omitempty"`
 }
@@ -399,14 +496,17 @@ type Sentry struct {
 
 // Settings description: Configuration settings for users and organizations on Sourcegraph.
 type Settings struct {
-	Extensions             map[string]bool           `json:"extensions,omitempty"`
-	Motd                   []string                  `json:"motd,omitempty"`
-	Notices                []*Notice                 `json:"notices,omitempty"`
-	NotificationsSlack     *SlackNotificationsConfig `json:"notifications.slack,omitempty"`
-	SearchContextLines     int                       `json:"search.contextLines,omitempty"`
-	SearchRepositoryGroups map[string][]string       `json:"search.repositoryGroups,omitempty"`
-	SearchSavedQueries     []*SearchSavedQueries     `json:"search.savedQueries,omitempty"`
-	SearchScopes           []*SearchScope            `json:"search.scopes,omitempty"`
+	Extensions             map[string]bool             `json:"extensions,omitempty"`
+	ExtensionsVersions     map[string]string           `json:"extensions.versions,omitempty"`
+	Motd                   []string                    `json:"motd,omitempty"`
+	Notices                []*Notice                   `json:"notices,omitempty"`
+	NotificationsMsteams   *MSTeamsNotificationsConfig `json:"notifications.msteams,omitempty"`
+	NotificationsSlack     *SlackNotificationsConfig   `json:"notifications.slack,omitempty"`
+	NotificationsWebhook   *WebhookNotificationsConfig `json:"notifications.webhook,omitempty"`
+	SearchContextLines     int                         `json:"search.contextLines,omitempty"`
+	SearchRepositoryGroups map[string][]string         `json:"search.repositoryGroups,omitempty"`
+	SearchSavedQueries     []*SearchSavedQueries       `json:"search.savedQueries,omitempty"`
+	SearchScopes           []*SearchScope              `json:"search.scopes,omitempty"`
 }
 
 // SiteConfiguration description: Configuration for a Sourcegraph site.
@@ -420,6 +520,7 @@ type SiteConfiguration struct {
 	Discussions                       *Discussions                `json:"discussions,omitempty"`
 	DontIncludeSymbolResultsByDefault bool                        `json:"dontIncludeSymbolResultsByDefault,omitempty"`
 	EmailAddress                      string                      `json:"email.address,omitempty"`
+	EmailHttp                         *EmailHTTPAPIConfig         `json:"email.http,omitempty"`
 	EmailImap                         *IMAPServerConfig           `json:"email.imap,omitempty"`
 	EmailSmtp                         *SMTPServerConfig           `json:"email.smtp,omitempty"`
 	ExperimentalFeatures              *ExperimentalFeatures       `json:"experimentalFeatures,omitempty"`
@@ -430,6 +531,8 @@ type SiteConfiguration struct {
 	GithubClientSecret                string                      `json:"githubClientSecret,omitempty"`
 	MaxReposToSearch                  int                         `json:"maxReposToSearch,omitempty"`
 	ParentSourcegraph                 *ParentSourcegraph          `json:"parentSourcegraph,omitempty"`
+	PermissionsBackgroundSync         *PermissionsBackgroundSync  `json:"permissions.backgroundSync,omitempty"`
+	PermissionsExplicitEnabled        bool                        `json:"permissions.explicit.enabled,omitempty"`
 	RepoListUpdateInterval            int                         `json:"repoListUpdateInterval,omitempty"`
 	SearchIndexEnabled                *bool                       `json:"search.index.enabled,omitempty"`
 	SearchLargeFiles                  []string                    `json:"search.largeFiles,omitempty"`
@@ -442,3 +545,10 @@ type SlackNotificationsConfig struct {
 type UsernameIdentity struct {
 	Type string `json:"type"`
 }
+
+// WebhookNotificationsConfig description: Configuration for sending notifications to a generic webhook, which receives HTTP POST requests with a JSON body.
+type WebhookNotificationsConfig struct {
+	BodyTemplate string            `json:"bodyTemplate,omitempty"`
+	Headers      map[string]string `json:"headers,omitempty"`
+	Url          string            `json:"url"`
+}
@@ -39,6 +39,14 @@ const SettingsSchemaJSON = `{
           "notifySlack": {
             "type": "boolean",
             "description": "Notify Slack via the organization's Slack webhook URL when new results are available"
+          },
+          "notifyMSTeams": {
+            "type": "boolean",
+            "description": "Notify Microsoft Teams via the Microsoft Teams webhook URL in the ` + "`" + `notifications.msteams` + "`" + ` setting when new results are available"
+          },
+          "notifyWebhook": {
+            "type": "boolean",
+            "description": "Notify the webhook in the ` + "`" + `notifications.webhook` + "`" + ` setting when new results are available"
           }
         },
         "additionalProperties": false,
@@ -69,6 +77,12 @@ const SettingsSchemaJSON = `{
     "notifications.slack": {
       "$ref": "#/definitions/SlackNotificationsConfig"
     },
+    "notifications.msteams": {
+      "$ref": "#/definitions/MSTeamsNotificationsConfig"
+    },
+    "notifications.webhook": {
+      "$ref": "#/definitions/WebhookNotificationsConfig"
+    },
     "motd": {
       "description": "DEPRECATED: Use ` + "`" + `notices` + "`" + ` instead.\n\nAn array (often with just one element) of messages to display at the top of all pages, including for unauthenticated users. Users may dismiss a message (and any message with the same string value will remain dismissed for the user).\n\nMarkdown formatting is supported.\n\nUsually this setting is used in global and organization settings. If set in user settings, the message will only be displayed to that user. (This is useful for testing the correctness of the message's Markdown formatting.)\n\nMOTD stands for \"message of the day\" (which is the conventional Unix name for this type of message).",
       "type": "array",
@@ -111,6 +125,19 @@ const SettingsSchemaJSON = `{
         "type": "boolean",
         "description": "` + "`" + `true` + "`" + ` to enable the extension, ` + "`" + `false` + "`" + ` to disable the extension (if it was previously enabled)"
       }
+    },
+    "extensions.versions": {
+      "description": "Pins extensions published in this Sourcegraph site's private extension registry to a range of release versions. The latest release whose version is in the range (and that is not yanked) is used. Examples: ` + "`" + `\"acme/my-extension\": \"^1.2.0\"` + "`" + `, ` + "`" + `\"acme/other-extension\": \"2.0.1\"` + "`" + `.",
+      "type": "object",
+      "propertyNames": {
+        "type": "string",
+        "description": "A valid extension ID.",
+        "pattern": "^([^/]+/)?[^/]+/[^/]+$"
+      },
+      "additionalProperties": {
+        "type": "string",
+        "description": "A version range, such as ` + "`" + `1.2.3` + "`" + ` (exactly that version), ` + "`" + `~1.2.3` + "`" + ` (the latest 1.2.x release at or after 1.2.3), ` + "`" + `^1.2.3` + "`" + ` (the latest 1.x release at or after 1.2.3), or ` + "`" + `1.x` + "`" + `."
+      }
     }
   },
   "definitions": {
@@ -137,6 +164,41 @@ const SettingsSchemaJSON = `{
         }
       }
     },
+    "MSTeamsNotificationsConfig": {
+      "type": "object",
+      "description": "Configuration for sending notifications to Microsoft Teams.",
+      "additionalProperties": false,
+      "required": ["webhookURL"],
+      "properties": {
+        "webhookURL": {
+          "type": "string",
+          "description": "The incoming webhook URL of a Microsoft Teams connector, used to post notification messages to a Microsoft Teams channel. To obtain this URL, add an \"Incoming Webhook\" connector to the channel.",
+          "format": "uri"
+        }
+      }
+    },
+    "WebhookNotificationsConfig": {
+      "type": "object",
+      "description": "Configuration for sending notifications to a generic webhook, which receives HTTP POST requests with a JSON body.",
+      "additionalProperties": false,
+      "required": ["url"],
+      "properties": {
+        "url": {
+          "type": "string",
+          "description": "The URL that notifications are POSTed to.",
+          "format": "uri"
+        },
+        "bodyTemplate": {
+          "type": "string",
+          "description": "A Go template (see https://golang.org/pkg/text/template/) for the JSON request body. The template data has the fields ` + "`" + `.Event` + "`" + ` (\"results\", \"enabled\", \"disabled\" or \"test\"), ` + "`" + `.Text` + "`" + ` (a plain-text message), ` + "`" + `.Description` + "`" + `, ` + "`" + `.Query` + "`" + `, ` + "`" + `.URL` + "`" + ` and ` + "`" + `.ApproximateResultCount` + "`" + `. Use the ` + "`" + `json` + "`" + ` function to encode values as JSON strings, as in ` + "`" + `{\"text\": {{json .Text}}}` + "`" + `. If not set, the body is a JSON object with all of the template data fields."
+        },
+        "headers": {
+          "type": "object",
+          "description": "Additional HTTP headers to send with each request. The ` + "`" + `Host` + "`" + `, ` + "`" + `Authorization` + "`" + `, ` + "`" + `Proxy-Authorization` + "`" + ` and ` + "`" + `Cookie` + "`" + ` headers can't be set (use a secret token in the URL to authenticate requests instead). Requests to loopback, private and link-local addresses are refused.",
+          "additionalProperties": { "type": "string" }
+        }
+      }
+    },
     "SlackNotificationsConfig": {
       "type": "object",
       "description": "Configuration for sending notifications to Slack.",
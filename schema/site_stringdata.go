@@ -155,6 +155,31 @@ const SiteSchemaJSON = `{
       ],
       "group": "Security"
     },
+    "permissions.backgroundSync": {
+      "description": "Syncs repository permissions from the code hosts that have authorization configured (such as GitHub and GitLab) in the background, and stores them in the database. When enabled, permissions are checked with a single database query instead of by calling the code host when repositories are accessed. Each user's permissions are synced periodically and each time they sign in.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "enabled": {
+          "description": "Whether to sync repository permissions in the background.",
+          "type": "boolean",
+          "default": false
+        },
+        "interval": {
+          "description": "How often each user's repository permissions are synced (a duration such as \"30m\" or \"1h\"). Permissions that were revoked on the code host remain in effect on Sourcegraph until the next sync.",
+          "type": "string",
+          "default": "1h"
+        }
+      },
+      "examples": [{ "enabled": true, "interval": "1h" }],
+      "group": "Security"
+    },
+    "permissions.explicit.enabled": {
+      "description": "Enforces the explicit repository permissions that site admins set with the GraphQL API (the ` + "`" + `setRepositoryPermissions` + "`" + ` and ` + "`" + `setRepositoryPatternPermissions` + "`" + ` mutations). Use this to restrict access to repositories whose code host has no repository permissions on Sourcegraph (such as Gitolite and other Git hosts). Repositories with no explicit permissions remain accessible as before.",
+      "type": "boolean",
+      "default": false,
+      "group": "Security"
+    },
     "branding": {
       "description": "Customize Sourcegraph homepage logo and search icon.",
       "type": "object",
@@ -218,6 +243,33 @@ const SiteSchemaJSON = `{
       ],
       "group": "Email"
     },
+    "email.http": {
+      "title": "EmailHTTPAPIConfig",
+      "description": "Optional. An HTTP email API used to send transactional emails instead of the SMTP server in email.smtp. The API must accept requests in the format of SendGrid's v3 mail send API (as SendGrid does, and as relays for other providers such as Amazon SES can).",
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["url"],
+      "properties": {
+        "url": {
+          "description": "The URL of the API's mail send endpoint.",
+          "type": "string",
+          "format": "uri",
+          "pattern": "^https?://"
+        },
+        "apiKey": {
+          "description": "The API key, which is sent as a bearer token in the Authorization header.",
+          "type": "string"
+        }
+      },
+      "default": null,
+      "examples": [
+        {
+          "url": "https://api.sendgrid.com/v3/mail/send",
+          "apiKey": "$env:SRC_SECRET_SENDGRID_API_KEY"
+        }
+      ],
+      "group": "Email"
+    },
     "email.imap": {
       "title": "IMAPServerConfig",
       "description": "Optional. The IMAP server used to retrieve emails (such as code discussion reply emails).",
@@ -281,6 +333,9 @@ const SiteSchemaJSON = `{
           "items": {
             "type": "string"
           }
+        },
+        "mirror": {
+          "$ref": "#/definitions/ExtensionsMirror"
         }
       },
       "default": {
@@ -308,6 +363,9 @@ const SiteSchemaJSON = `{
           "type": "array",
           "items": { "type": "string" },
           "default": []
+        },
+        "importReviewComments": {
+          "$ref": "#/definitions/DiscussionsImportReviewComments"
         }
       },
       "group": "Experimental",
@@ -315,6 +373,40 @@ const SiteSchemaJSON = `{
     }
   },
   "definitions": {
+    "DiscussionsImportReviewComments": {
+      "description": "Import pull request review comments (from GitHub) and merge request diff comments (from GitLab) on the repositories of the GitHub and GitLab connections as code discussion threads. New comments are imported every hour, and imported threads link back to the original pull or merge request.",
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["authorUsername"],
+      "properties": {
+        "authorUsername": {
+          "description": "The username of the Sourcegraph user that imported comments are attributed to when their author has not signed into Sourcegraph with the code host (via a GitHub or GitLab auth provider).",
+          "type": "string"
+        }
+      }
+    },
+    "ExtensionsMirror": {
+      "description": "Mirror extensions from a remote registry (or from an extension archive file) into the private extension registry, so that they can be used on sites that can't access the remote registry. Only the extensions allowed by ` + "`" + `allowRemoteExtensions` + "`" + ` are mirrored. When mirroring is enabled, remote extensions are served from the mirror instead of from the remote registry.\n\nOnly available in Sourcegraph Enterprise.",
+      "type": "object",
+      "properties": {
+        "registry": {
+          "description": "The URL of the registry to mirror extensions from. If not set (and ` + "`" + `archive` + "`" + ` is not set), the ` + "`" + `remoteRegistry` + "`" + ` URL is used.",
+          "type": "string",
+          "format": "uri"
+        },
+        "archive": {
+          "description": "The path to an extension archive file (exported from another Sourcegraph site's /.api/registry/extensions/archive endpoint) to mirror extensions from. Use this on sites that can't access any registry.",
+          "type": "string"
+        },
+        "intervalMinutes": {
+          "description": "How often (in minutes) to update the mirrored extensions.",
+          "type": "integer",
+          "minimum": 1,
+          "default": 60
+        }
+      },
+      "examples": [{ "registry": "https://sourcegraph.com/.api/registry" }, { "archive": "/etc/sourcegraph/extensions.json" }]
+    },
     "BrandAssets": {
       "type": "object",
       "properties": {
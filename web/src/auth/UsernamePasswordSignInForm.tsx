@@ -10,6 +10,15 @@ import { getReturnTo, PasswordInput } from './SignInSignUpCommon'
 interface Props {
     location: H.Location
     history: H.History
+
+    /**
+     * The sign-in URL of an external auth provider (such as LDAP) that verifies the username and
+     * password. If not set, the credentials are verified against the builtin user accounts.
+     */
+    authenticationURL?: string
+
+    /** The display name of the external auth provider, if any. */
+    displayName?: string
 }
 
 interface State {
@@ -20,7 +29,8 @@ interface State {
 }
 
 /**
- * The form for signing in with a username and password.
+ * The form for signing in with a username and password (of a builtin user account, or of an external
+ * auth provider such as LDAP if props.authenticationURL is set).
  */
 export class UsernamePasswordSignInForm extends React.Component<Props, State> {
     constructor(props: Props) {
@@ -36,7 +46,9 @@ export class UsernamePasswordSignInForm extends React.Component<Props, State> {
     public render(): JSX.Element | null {
         return (
             <Form className="signin-signup-form signin-form" onSubmit={this.handleSubmit}>
-                {window.context.allowSignup ? (
+                {this.props.authenticationURL ? (
+                    this.props.displayName && <p className="text-muted">Sign in with {this.props.displayName}.</p>
+                ) : window.context.allowSignup ? (
                     <Link className="signin-signup-form__mode" to={`/sign-up${this.props.location.search}`}>
                         Don't have an account? Sign up.
                     </Link>
@@ -50,7 +62,7 @@ export class UsernamePasswordSignInForm extends React.Component<Props, State> {
                     <input
                         className={`form-control signin-signup-form__input`}
                         type="text"
-                        placeholder="Username or email"
+                        placeholder={this.props.authenticationURL ? 'Username' : 'Username or email'}
                         onChange={this.onEmailFieldChange}
                         required={true}
                         value={this.state.email}
@@ -74,7 +86,7 @@ export class UsernamePasswordSignInForm extends React.Component<Props, State> {
                     <button className="btn btn-primary btn-block" type="submit" disabled={this.state.loading}>
                         Sign in
                     </button>
-                    {window.context.resetPasswordEnabled && (
+                    {window.context.resetPasswordEnabled && !this.props.authenticationURL && (
                         <small className="form-text text-muted">
                             <Link to="/password-reset">Forgot password?</Link>
                         </small>
@@ -105,7 +117,7 @@ export class UsernamePasswordSignInForm extends React.Component<Props, State> {
 
         this.setState({ loading: true })
         eventLogger.log('InitiateSignIn')
-        fetch('/-/sign-in', {
+        fetch(this.props.authenticationURL || '/-/sign-in', {
             credentials: 'same-origin',
             method: 'POST',
             headers: {
@@ -113,10 +125,11 @@ export class UsernamePasswordSignInForm extends React.Component<Props, State> {
                 Accept: 'application/json',
                 'Content-Type': 'application/json',
             },
-            body: JSON.stringify({
-                email: this.state.email,
-                password: this.state.password,
-            }),
+            body: JSON.stringify(
+                this.props.authenticationURL
+                    ? { username: this.state.email, password: this.state.password }
+                    : { email: this.state.email, password: this.state.password }
+            ),
         })
             .then(resp => {
                 if (resp.status === 200) {
@@ -3,5 +3,7 @@
  */
 export enum AccessTokenScopes {
     UserAll = 'user:all',
+    UserRead = 'user:read',
+    Search = 'search',
     SiteAdminSudo = 'site-admin:sudo',
 }
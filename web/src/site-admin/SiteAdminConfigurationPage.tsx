@@ -226,6 +226,16 @@ export class SiteAdminConfigurationPage extends React.Component<Props, State> {
             )
         }
 
+        const readOnly = !!this.state.site && this.state.site.configurationReadOnly
+        if (readOnly) {
+            alerts.push(
+                <div key="read-only" className="alert alert-info site-admin-configuration-page__alert">
+                    The site configuration is loaded from configuration files and can't be edited here. See{' '}
+                    <Link to="/help/admin/config/config_files">documentation</Link> for more information.
+                </div>
+            )
+        }
+
         const isReloading = typeof this.state.reloadStartedAt === 'number'
 
         return (
@@ -254,7 +264,7 @@ export class SiteAdminConfigurationPage extends React.Component<Props, State> {
                             value={contents || ''}
                             jsonSchema={siteSchemaJSON}
                             onDirtyChange={this.onDirtyChange}
-                            canEdit={true}
+                            canEdit={!readOnly}
                             saving={this.state.saving}
                             loading={isReloading || this.state.saving}
                             height={600}
@@ -386,6 +386,7 @@ export function fetchSite(): Observable<GQL.ISite> {
         query Site {
             site {
                 id
+                configurationReadOnly
                 configuration {
                     id
                     effectiveContents
@@ -15,6 +15,7 @@ import gitlabSchemaJSON from '../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../schema/gitolite.schema.json'
 import otherExternalServiceSchemaJSON from '../../../schema/other_external_service.schema.json'
 import phabricatorSchemaJSON from '../../../schema/phabricator.schema.json'
+import pluginSchemaJSON from '../../../schema/plugin.schema.json'
 import { PhabricatorIcon } from '../../../shared/src/components/icons'
 import * as GQL from '../../../shared/src/graphql/schema'
 import { EditorAction } from './configHelpers.js'
@@ -104,6 +105,10 @@ const editorActionComments = {
     //    include the SSO auth provider for GitLab (https://docs.sourcegraph.com/admin/auth).
     // 3. Update the fields below to match the properties of this auth provider
     //    (https://docs.sourcegraph.com/admin/repo/permissions#sudo-access-token).`,
+    enforcePermissionsBitbucketServer: `// Prerequisite: Create an Application Link for Sourcegraph in Bitbucket Server
+    // with incoming authentication that allows user impersonation, and set the OAuth
+    // consumer key and base64-encoded private key below
+    // (https://docs.sourcegraph.com/admin/repo/permissions#bitbucket-server).`,
 }
 
 export const GITHUB_EXTERNAL_SERVICE: ExternalServiceKindMetadata = {
@@ -337,6 +342,25 @@ export const ALL_EXTERNAL_SERVICES: Record<GQL.ExternalServiceKind, ExternalServ
                     return { edits, selectText: '{"name": "<projectKey>/<repoSlug>"}' }
                 },
             },
+            {
+                id: 'enforcePermissions',
+                label: 'Enforce permissions',
+                run: config => {
+                    const value = {
+                        COMMENT_SENTINEL: true,
+                        identityProvider: {
+                            type: 'username',
+                        },
+                        oauth: {
+                            consumerKey: '<consumer key>',
+                            signingKey: '<base64-encoded PEM private key>',
+                        },
+                    }
+                    const comment = editorActionComments.enforcePermissionsBitbucketServer
+                    const edit = editWithComment(config, ['authorization'], value, comment)
+                    return { edits: [edit], selectText: comment }
+                },
+            },
         ],
     },
     [GQL.ExternalServiceKind.GITLAB]: {
@@ -587,6 +611,36 @@ export const ALL_EXTERNAL_SERVICES: Record<GQL.ExternalServiceKind, ExternalServ
             },
         ],
     },
+    [GQL.ExternalServiceKind.PLUGIN]: {
+        title: 'Repository listing plugin',
+        icon: <GitIcon size={ICON_SIZE} />,
+        iconBrandColor: 'git',
+        shortDescription: 'Add Git repositories listed by an HTTP endpoint or command.',
+        jsonSchema: pluginSchemaJSON,
+        defaultDisplayName: 'Repository listing plugin',
+        defaultConfig: `{
+  // Use Ctrl+Space for completion, and hover over JSON properties for documentation.
+  // Configuration options and the plugin protocol are documented here:
+  // https://docs.sourcegraph.com/admin/external_service/plugin#configuration
+
+  // The HTTP endpoint that lists the repositories (alternatively, set "command").
+  "url": "https://repos.example.com/sourcegraph/repos",
+
+  // A token sent as a bearer token in the Authorization header of requests to the url.
+  "token": ""
+}`,
+        editorActions: [
+            {
+                id: 'setURL',
+                label: 'Set plugin URL',
+                run: config => {
+                    const value = 'https://repos.example.com/sourcegraph/repos'
+                    const edits = setProperty(config, ['url'], value, defaultFormattingOptions)
+                    return { edits, selectText: value }
+                },
+            },
+        ],
+    },
     [GQL.ExternalServiceKind.OTHER]: {
         title: 'Single Git repositories',
         icon: <GitIcon size={ICON_SIZE} />,
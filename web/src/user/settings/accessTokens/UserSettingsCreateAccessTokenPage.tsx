@@ -40,6 +40,16 @@ function createAccessToken(user: GQL.ID, scopes: string[], note: string): Observ
     )
 }
 
+/** The scopes of which every access token has exactly one. */
+const USER_SCOPES: { scope: AccessTokenScopes; description: string }[] = [
+    { scope: AccessTokenScopes.UserAll, description: 'Full control of all resources accessible to the user account' },
+    {
+        scope: AccessTokenScopes.UserRead,
+        description: 'Read-only access to all resources accessible to the user account',
+    },
+    { scope: AccessTokenScopes.Search, description: 'Ability to perform searches (and nothing else)' },
+]
+
 interface Props extends UserAreaRouteContext, RouteComponentProps<{}> {
     /** Called when a new access token is created and should be temporarily displayed to the user. */
     onDidCreateAccessToken: (result: GQL.ICreateAccessTokenResult) => void
@@ -133,29 +143,25 @@ export class UserSettingsCreateAccessTokenPage extends React.PureComponent<Props
                         <label className="mb-1" htmlFor="user-settings-create-access-token-page__note">
                             Token scope
                         </label>
-                        <div>
-                            <small className="form-help text-muted">
-                                Tokens with limited user scopes are not yet supported.
-                            </small>
-                        </div>
-                        <div className="form-check">
-                            <input
-                                className="form-check-input"
-                                type="checkbox"
-                                id="user-settings-create-access-token-page__scope-user:all"
-                                checked={true}
-                                value={AccessTokenScopes.UserAll}
-                                onChange={this.onScopesChange}
-                                disabled={true}
-                            />
-                            <label
-                                className="form-check-label"
-                                htmlFor="user-settings-create-access-token-page__scope-user:all"
-                            >
-                                <strong>{AccessTokenScopes.UserAll}</strong> — Full control of all resources accessible
-                                to the user account
-                            </label>
-                        </div>
+                        {USER_SCOPES.map(({ scope, description }) => (
+                            <div className="form-check" key={scope}>
+                                <input
+                                    className="form-check-input"
+                                    type="radio"
+                                    name="user-settings-create-access-token-page__user-scope"
+                                    id={`user-settings-create-access-token-page__scope-${scope}`}
+                                    checked={this.state.scopes.includes(scope)}
+                                    value={scope}
+                                    onChange={this.onUserScopeChange}
+                                />
+                                <label
+                                    className="form-check-label"
+                                    htmlFor={`user-settings-create-access-token-page__scope-${scope}`}
+                                >
+                                    <strong>{scope}</strong> — {description}
+                                </label>
+                            </div>
+                        ))}
                         {this.props.user.siteAdmin && (
                             <div className="form-check">
                                 <input
@@ -165,6 +171,7 @@ export class UserSettingsCreateAccessTokenPage extends React.PureComponent<Props
                                     checked={this.state.scopes.includes(AccessTokenScopes.SiteAdminSudo)}
                                     value={AccessTokenScopes.SiteAdminSudo}
                                     onChange={this.onScopesChange}
+                                    disabled={!this.state.scopes.includes(AccessTokenScopes.UserAll)}
                                 />
                                 <label
                                     className="form-check-label"
@@ -212,5 +219,20 @@ export class UserSettingsCreateAccessTokenPage extends React.PureComponent<Props
         }))
     }
 
+    private onUserScopeChange: React.ChangeEventHandler<HTMLInputElement> = e => {
+        const value = e.currentTarget.value
+        this.setState(prevState => ({
+            // Only user:all tokens may have the sudo scope.
+            scopes: [
+                value,
+                ...prevState.scopes.filter(
+                    s =>
+                        !USER_SCOPES.some(({ scope }) => scope === s) &&
+                        (value === AccessTokenScopes.UserAll || s !== AccessTokenScopes.SiteAdminSudo)
+                ),
+            ],
+        }))
+    }
+
     private onSubmit: React.FormEventHandler<HTMLFormElement> = e => this.submits.next(e)
 }